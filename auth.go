@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -10,10 +12,18 @@ import (
 	"github.com/safedep/vet/internal/auth"
 	"github.com/safedep/vet/internal/command"
 	"github.com/safedep/vet/internal/ui"
+	"github.com/safedep/vet/pkg/common/encryption"
 	"github.com/safedep/vet/pkg/common/logger"
 )
 
-var authTenantDomain string
+var (
+	authTenantDomain string
+	authRegistryHost string
+	authDeviceLogin  bool
+	authClientId     string
+
+	authEncryptionKey bool
+)
 
 func newAuthCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -26,6 +36,8 @@ func newAuthCommand() *cobra.Command {
 
 	cmd.AddCommand(configureAuthCommand())
 	cmd.AddCommand(verifyAuthCommand())
+	cmd.AddCommand(loginAuthCommand())
+	cmd.AddCommand(logoutAuthCommand())
 
 	return cmd
 }
@@ -34,55 +46,195 @@ func configureAuthCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use: "configure",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var key string
-			var err error
-
-			err = survey.AskOne(&survey.Password{
-				Message: "Enter the API key",
-			}, &key)
-			if err != nil {
-				logger.Fatalf("Failed to setup auth: %v", err)
-			}
+			return configureApiKey()
+		},
+	}
+
+	cmd.Flags().StringVarP(&authTenantDomain, "tenant", "", "",
+		"Tenant domain for SafeDep Cloud")
+
+	_ = cmd.MarkFlagRequired("tenant")
 
-			if auth.TenantDomain() != "" && auth.TenantDomain() != authTenantDomain {
-				ui.PrintWarning("Tenant domain mismatch. Existing: %s, New: %s, continue? ",
-					auth.TenantDomain(), authTenantDomain)
+	return cmd
+}
 
-				var confirm bool
-				err = survey.AskOne(&survey.Confirm{
-					Message: "Do you want to continue?",
-				}, &confirm)
+func configureApiKey() error {
+	var key string
+	var err error
+
+	err = survey.AskOne(&survey.Password{
+		Message: "Enter the API key",
+	}, &key)
+	if err != nil {
+		logger.Fatalf("Failed to setup auth: %v", err)
+	}
+
+	if auth.TenantDomain() != "" && auth.TenantDomain() != authTenantDomain {
+		ui.PrintWarning("Tenant domain mismatch. Existing: %s, New: %s, continue? ",
+			auth.TenantDomain(), authTenantDomain)
+
+		var confirm bool
+		err = survey.AskOne(&survey.Confirm{
+			Message: "Do you want to continue?",
+		}, &confirm)
+		if err != nil {
+			logger.Fatalf("Failed to setup auth: %v", err)
+		}
+
+		if !confirm {
+			return nil
+		}
+	}
+
+	auth.SetRuntimeCloudTenant(authTenantDomain)
+	auth.SetRuntimeApiKey(key)
+
+	err = auth.Verify()
+	if err != nil {
+		logger.Fatalf("Failed to verify auth: %v", err)
+	}
+
+	err = auth.PersistApiKey(key, authTenantDomain)
+	if err != nil {
+		logger.Fatalf("Failed to configure auth: %v", err)
+	}
+
+	os.Exit(0)
+	return nil
+}
+
+func loginAuthCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Login using API key, device code, client credentials or store registry token",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if authRegistryHost != "" {
+				var token string
+				err := survey.AskOne(&survey.Password{
+					Message: fmt.Sprintf("Enter the token for %s", authRegistryHost),
+				}, &token)
 				if err != nil {
-					logger.Fatalf("Failed to setup auth: %v", err)
+					logger.Fatalf("Failed to login: %v", err)
 				}
 
-				if !confirm {
-					return nil
-				}
+				command.FailOnError("auth/login", auth.PersistRegistryToken(authRegistryHost, token))
+
+				ui.PrintSuccess("Registry token stored in keychain")
+				return nil
 			}
 
-			auth.SetRuntimeCloudTenant(authTenantDomain)
-			auth.SetRuntimeApiKey(key)
+			if authEncryptionKey {
+				key, err := encryption.GenerateKey()
+				command.FailOnError("auth/login", err)
+				command.FailOnError("auth/login", auth.PersistEncryptionKey(key))
 
-			err = auth.Verify()
-			if err != nil {
-				logger.Fatalf("Failed to verify auth: %v", err)
+				ui.PrintSuccess("Encryption key for cache and sync spool stored in keychain")
+				return nil
 			}
 
-			err = auth.PersistApiKey(key, authTenantDomain)
-			if err != nil {
-				logger.Fatalf("Failed to configure auth: %v", err)
+			if authClientId != "" {
+				command.FailOnError("auth/login", loginWithClientCredentials(cmd.Context()))
+
+				ui.PrintSuccess("Client credentials stored, access token is obtained automatically")
+				return nil
 			}
 
-			os.Exit(0)
-			return nil
+			if authDeviceLogin {
+				err := auth.DeviceCodeLogin(cmd.Context(), authTenantDomain, func(verificationUrl, userCode string) {
+					ui.PrintSuccess("Please visit %s and enter the code %s to authenticate",
+						verificationUrl, userCode)
+				})
+
+				command.FailOnError("auth/login", err)
+
+				ui.PrintSuccess("Logged in, access token is refreshed automatically")
+				return nil
+			}
+
+			if authTenantDomain == "" {
+				return errors.New("tenant domain is required, use --tenant")
+			}
+
+			if !auth.KeychainEnabled() {
+				ui.PrintWarning("OS keychain is not available, API key will be stored in config file")
+			}
+
+			return configureApiKey()
 		},
 	}
 
 	cmd.Flags().StringVarP(&authTenantDomain, "tenant", "", "",
 		"Tenant domain for SafeDep Cloud")
+	cmd.Flags().StringVarP(&authRegistryHost, "registry", "", "",
+		"Store token for package registry host instead of API key")
+	cmd.Flags().BoolVarP(&authDeviceLogin, "device", "", false,
+		"Login using browser with device code instead of API key")
+	cmd.Flags().StringVarP(&authClientId, "client-id", "", "",
+		"Login using OAuth2 client credentials of a machine identity instead of API key")
+	cmd.Flags().BoolVarP(&authEncryptionKey, "encryption-key", "", false,
+		"Generate and store key for encrypting cache and sync spool instead of API key")
 
-	_ = cmd.MarkFlagRequired("tenant")
+	return cmd
+}
+
+// loginWithClientCredentials verifies the client credentials by obtaining
+// a token before storing them
+func loginWithClientCredentials(ctx context.Context) error {
+	var secret string
+	err := survey.AskOne(&survey.Password{
+		Message: "Enter the client secret",
+	}, &secret)
+	if err != nil {
+		return err
+	}
+
+	provider, err := auth.NewClientCredentialsProvider(auth.ClientCredentialsConfig{
+		ClientId:     authClientId,
+		ClientSecret: secret,
+		TokenUrl:     auth.CloudClientTokenUrl(),
+		Audience:     auth.CloudIdentityServiceAudience(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := provider.Token(ctx); err != nil {
+		return err
+	}
+
+	return auth.PersistCloudClientCredentials(authClientId, secret, authTenantDomain)
+}
+
+func logoutAuthCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Remove API key and cloud tokens from OS keychain and config",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if authRegistryHost != "" {
+				command.FailOnError("auth/logout", auth.DeleteRegistryToken(authRegistryHost))
+
+				ui.PrintSuccess("Registry token removed")
+				return nil
+			}
+
+			if authEncryptionKey {
+				command.FailOnError("auth/logout", auth.DeleteEncryptionKey())
+
+				ui.PrintSuccess("Encryption key removed, data encrypted using it is not readable")
+				return nil
+			}
+
+			command.FailOnError("auth/logout", auth.DeleteCredentials())
+
+			ui.PrintSuccess("Credentials removed")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&authRegistryHost, "registry", "", "",
+		"Remove token for package registry host instead of API key")
+	cmd.Flags().BoolVarP(&authEncryptionKey, "encryption-key", "", false,
+		"Remove key for encrypting cache and sync spool instead of API key")
 
 	return cmd
 }
@@ -95,7 +247,18 @@ func verifyAuthCommand() *cobra.Command {
 				ui.PrintSuccess("Running in Community Mode")
 			}
 
-			command.FailOnError("auth/verify", auth.Verify())
+			issues, err := auth.Preflight(auth.PreflightConfig{
+				ExpiryWarning: auth.DefaultTokenExpiryWarning,
+				Online:        true,
+			})
+
+			for _, issue := range issues {
+				if !issue.Fatal {
+					ui.PrintWarning("%s", issue)
+				}
+			}
+
+			command.FailOnError("auth/verify", err)
 
 			ui.PrintSuccess("Authentication key is valid!")
 			return nil
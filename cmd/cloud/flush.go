@@ -0,0 +1,72 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/safedep/vet/internal/auth"
+	"github.com/safedep/vet/internal/ui"
+	"github.com/safedep/vet/pkg/reporter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flushSpoolDir string
+	flushTimeout  time.Duration
+)
+
+func newFlushCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "flush",
+		Short: "Publish sync sessions spooled by offline or unreachable scans",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return flushSyncSpool()
+		},
+	}
+
+	cmd.Flags().StringVarP(&flushSpoolDir, "spool-dir", "", "",
+		"Directory containing spooled sync sessions (default ~/.safedep/vet-sync-spool)")
+	cmd.Flags().DurationVar(&flushTimeout, "timeout", 10*time.Minute,
+		"Timeout for publishing spooled sync sessions")
+
+	return cmd
+}
+
+func flushSyncSpool() error {
+	dir := flushSpoolDir
+	if dir == "" {
+		var err error
+		dir, err = reporter.DefaultSyncSpoolDir()
+		if err != nil {
+			return err
+		}
+	}
+
+	cipher, err := auth.EncryptionCipher()
+	if err != nil {
+		return err
+	}
+
+	conn, err := auth.SyncClientConnection("vet-cloud-flush")
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	stats, err := reporter.ReplaySyncSpool(ctx, reporter.SyncSpoolReplayConfig{
+		Dir:              dir,
+		ClientConnection: conn,
+		Cipher:           cipher,
+	})
+
+	ui.PrintMsg("Flushed sync spool: %d published, %d failed", stats.Published, stats.Failed)
+	if err != nil {
+		return fmt.Errorf("failed to flush sync spool: %w", err)
+	}
+
+	ui.PrintSuccess("Sync spool flushed successfully")
+	return nil
+}
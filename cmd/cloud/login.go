@@ -2,11 +2,7 @@ package cloud
 
 import (
 	"context"
-	"fmt"
-	"net/http"
 
-	"github.com/cli/oauth/api"
-	"github.com/cli/oauth/device"
 	"github.com/safedep/vet/internal/auth"
 	"github.com/safedep/vet/internal/ui"
 	"github.com/safedep/vet/pkg/common/logger"
@@ -31,37 +27,10 @@ func newCloudLoginCommand() *cobra.Command {
 }
 
 func executeCloudLogin() error {
-	token, err := executeDeviceAuthFlow()
-	if err != nil {
-		return fmt.Errorf("failed to execute device auth flow: %w", err)
-	}
-
-	return auth.PersistCloudTokens(token.Token,
-		token.RefreshToken, tenantDomain)
+	return auth.DeviceCodeLogin(context.TODO(), tenantDomain, printDeviceCode)
 }
 
-func executeDeviceAuthFlow() (*api.AccessToken, error) {
-	code, err := device.RequestCode(http.DefaultClient,
-		auth.CloudIdentityServiceDeviceCodeUrl(),
-		auth.CloudIdentityServiceClientId(),
-		[]string{"offline_access", "openid", "profile", "email"},
-		device.WithAudience(auth.CloudIdentityServiceAudience()))
-	if err != nil {
-		return nil, fmt.Errorf("failed to request device code: %w", err)
-	}
-
+func printDeviceCode(verificationUrl, userCode string) {
 	ui.PrintSuccess("Please visit %s and enter the code %s to authenticate",
-		code.VerificationURIComplete, code.UserCode)
-
-	token, err := device.Wait(context.TODO(),
-		http.DefaultClient, auth.CloudIdentityServiceTokenUrl(),
-		device.WaitOptions{
-			ClientID:   auth.CloudIdentityServiceClientId(),
-			DeviceCode: code,
-		})
-	if err != nil {
-		return nil, fmt.Errorf("failed to authenticate: %w", err)
-	}
-
-	return token, nil
+		verificationUrl, userCode)
 }
@@ -36,6 +36,8 @@ func NewCloudCommand() *cobra.Command {
 	cmd.AddCommand(newWhoamiCommand())
 	cmd.AddCommand(newKeyCommand())
 	cmd.AddCommand(newCloudQuickstartCommand())
+	cmd.AddCommand(newFlushCommand())
+	cmd.AddCommand(newResumeCommand())
 
 	cmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
 		if tenantDomain != "" {
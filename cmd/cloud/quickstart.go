@@ -1,6 +1,7 @@
 package cloud
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -141,22 +142,14 @@ func quickStartSetupTenantFromAccess(userInfo *controltowerv1.GetUserInfoRespons
 func quickStartAuthentication() error {
 	ui.PrintMsg("🔑 Start by creating an account or sign-in to your existing account")
 
-	token, err := executeDeviceAuthFlow()
+	err := auth.DeviceCodeLogin(context.TODO(), "", printDeviceCode)
 	if err != nil {
 		ui.PrintError("❌ Oops! Something went wrong while authenticating you: %s", err.Error())
 		ui.PrintMsg("ℹ️  If you are using email and password, ensure your email is verified.")
 		return err
 	}
 
-	ui.PrintSuccess("✅ Successfully authenticated you!")
-
-	ui.PrintMsg("🔑 Saving your cloud credentials in your local config...")
-	if err := auth.PersistCloudTokens(token.Token, token.RefreshToken, ""); err != nil {
-		ui.PrintError("❌ Oops! Something went wrong while saving your cloud credentials: %s", err.Error())
-		return err
-	}
-
-	ui.PrintSuccess("✅ Successfully saved your cloud credentials!")
+	ui.PrintSuccess("✅ Successfully authenticated you and saved your cloud credentials!")
 
 	return nil
 }
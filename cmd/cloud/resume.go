@@ -0,0 +1,63 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/safedep/vet/internal/auth"
+	"github.com/safedep/vet/internal/ui"
+	"github.com/safedep/vet/pkg/reporter"
+	"github.com/spf13/cobra"
+)
+
+var resumeStateDir string
+
+func newResumeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume sync sessions of scans interrupted with --report-sync-resumable",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return resumeSyncSessions()
+		},
+	}
+
+	cmd.Flags().StringVarP(&resumeStateDir, "state-dir", "", "",
+		"Directory containing journals of interrupted syncs (default ~/.safedep/vet-sync-state)")
+
+	return cmd
+}
+
+func resumeSyncSessions() error {
+	dir := resumeStateDir
+	if dir == "" {
+		var err error
+		dir, err = reporter.DefaultSyncStateDir()
+		if err != nil {
+			return err
+		}
+	}
+
+	cipher, err := auth.EncryptionCipher()
+	if err != nil {
+		return err
+	}
+
+	conn, err := auth.SyncClientConnection("vet-cloud-resume")
+	if err != nil {
+		return err
+	}
+
+	stats, err := reporter.ResumeSyncSessions(context.Background(), reporter.SyncResumeConfig{
+		Dir:              dir,
+		ClientConnection: conn,
+		Cipher:           cipher,
+	})
+
+	ui.PrintMsg("Resumed sync: %d published, %d failed", stats.Published, stats.Failed)
+	if err != nil {
+		return fmt.Errorf("failed to resume sync: %w", err)
+	}
+
+	ui.PrintSuccess("Sync sessions resumed successfully")
+	return nil
+}
@@ -0,0 +1,164 @@
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/safedep/vet/internal/ui"
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/artifact"
+	"github.com/safedep/vet/pkg/purl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	artifactDiffPackageUrl  string
+	artifactDiffBaseVersion string
+	artifactDiffTimeout     time.Duration
+	artifactDiffReportJSON  string
+)
+
+func newPackageDiffInspectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Diff published artifacts of two versions of an OSS package",
+		Long: `Download and diff the published artifacts of two versions of an OSS package
+to support manual review of suspicious version bumps`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := executeArtifactDiff()
+			if err != nil {
+				ui.PrintError("Failed: %v", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&artifactDiffPackageUrl, "purl", "",
+		"Package URL of the target version to inspect")
+	cmd.Flags().StringVar(&artifactDiffBaseVersion, "base", "",
+		"Base version of the package to diff against")
+	cmd.Flags().DurationVar(&artifactDiffTimeout, "timeout", 5*time.Minute,
+		"Timeout for downloading artifacts")
+	cmd.Flags().StringVar(&artifactDiffReportJSON, "report-json", "",
+		"Path to save the diff in JSON format")
+
+	_ = cmd.MarkFlagRequired("purl")
+	_ = cmd.MarkFlagRequired("base")
+
+	return cmd
+}
+
+func executeArtifactDiff() error {
+	parsedPurl, err := purl.Parse(artifactDiffPackageUrl)
+	if err != nil {
+		return err
+	}
+
+	pd := parsedPurl.GetPackageDetails()
+	if pd.Version == "" {
+		return fmt.Errorf("package URL must include a version")
+	}
+
+	downloader, err := artifact.NewRegistryDownloader(artifact.RegistryDownloaderConfig{})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancelFun := context.WithTimeout(context.Background(), artifactDiffTimeout)
+	defer cancelFun()
+
+	ui.StartSpinner("Downloading package artifacts")
+
+	base, err := downloader.Download(ctx, string(pd.Ecosystem), pd.Name, artifactDiffBaseVersion)
+	if err != nil {
+		ui.StopSpinner()
+		return fmt.Errorf("failed to download base version %s: %w", artifactDiffBaseVersion, err)
+	}
+
+	target, err := downloader.Download(ctx, string(pd.Ecosystem), pd.Name, pd.Version)
+	if err != nil {
+		ui.StopSpinner()
+		return fmt.Errorf("failed to download target version %s: %w", pd.Version, err)
+	}
+
+	ui.StopSpinner()
+
+	diff := artifact.Compare(base, target)
+	if artifactDiffReportJSON != "" {
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize diff: %w", err)
+		}
+
+		err = os.WriteFile(artifactDiffReportJSON, data, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to write diff: %w", err)
+		}
+	}
+
+	finding, err := diff.Finding(pd.Name, artifactDiffBaseVersion, pd.Version)
+	if err != nil {
+		return fmt.Errorf("failed to build finding: %w", err)
+	}
+
+	renderArtifactDiff(pd.Name, artifactDiffBaseVersion, pd.Version, diff, finding)
+	return nil
+}
+
+func renderArtifactDiff(name, baseVersion, targetVersion string, diff *artifact.Diff,
+	finding *analyzer.Finding) {
+	ui.PrintMsg("Artifact diff for package: %s (%s -> %s)", name, baseVersion, targetVersion)
+
+	tbl := table.NewWriter()
+	tbl.SetOutputMirror(os.Stdout)
+	tbl.SetStyle(table.StyleLight)
+
+	tbl.AppendHeader(table.Row{"Change", "Path", "Size"})
+
+	for _, file := range diff.Binaries {
+		tbl.AppendRow(table.Row{text.FgHiRed.Sprint("BINARY"), file.Path, file.Size})
+	}
+
+	for _, file := range diff.InstallScriptFiles {
+		tbl.AppendRow(table.Row{text.FgHiRed.Sprint("INSTALL SCRIPT"), file.Path, file.Size})
+	}
+
+	for _, script := range diff.InstallScripts {
+		tbl.AppendRow(table.Row{text.FgHiRed.Sprint("INSTALL SCRIPT"),
+			fmt.Sprintf("scripts.%s: %q -> %q", script.Name, script.Old, script.New), ""})
+	}
+
+	for _, file := range diff.Added {
+		tbl.AppendRow(table.Row{text.FgHiGreen.Sprint("ADDED"), file.Path, file.Size})
+	}
+
+	for _, file := range diff.Removed {
+		tbl.AppendRow(table.Row{text.FgHiYellow.Sprint("REMOVED"), file.Path, file.Size})
+	}
+
+	for _, file := range diff.Modified {
+		tbl.AppendRow(table.Row{"MODIFIED", file.Path, file.Size})
+	}
+
+	tbl.Render()
+
+	fmt.Println()
+	if finding == nil {
+		fmt.Println(diff.Summary())
+		fmt.Println()
+		return
+	}
+
+	fmt.Println(text.FgHiRed.Sprintf("** %s, manual review recommended", finding.Title))
+	for _, evidence := range finding.Evidences {
+		fmt.Printf("%s: %s\n", evidence.Type, evidence.Summary)
+	}
+
+	fmt.Println()
+}
@@ -16,5 +16,8 @@ func NewPackageInspectCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(newPackageMalwareInspectCommand())
+	cmd.AddCommand(newPackageDiffInspectCommand())
+	cmd.AddCommand(newPackageProfileInspectCommand())
+	cmd.AddCommand(newInspectServerCommand())
 	return cmd
 }
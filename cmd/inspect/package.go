@@ -0,0 +1,141 @@
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/safedep/vet/gen/filtersuite"
+	"github.com/safedep/vet/internal/auth"
+	"github.com/safedep/vet/internal/ui"
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/profile"
+	"github.com/safedep/vet/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	packageProfilePackageUrl  string
+	packageProfileFilterSuite string
+	packageProfileInsightsV2  bool
+	packageProfileReportJSON  string
+)
+
+func newPackageProfileInspectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "package",
+		Short: "Inspect an OSS package and render its enriched profile as JSON",
+		Long: `Inspect an OSS package and render its enriched profile including vulnerabilities,
+licenses, scorecard, maintainers, malware analysis, health score and policy verdict as JSON`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := executePackageProfile()
+			if err != nil {
+				ui.PrintError("Failed: %v", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&packageProfilePackageUrl, "purl", "",
+		"Package URL to inspect")
+	cmd.Flags().StringVar(&packageProfileFilterSuite, "filter-suite", "",
+		"Filter suite to evaluate as policy for the verdict")
+	cmd.Flags().BoolVar(&packageProfileInsightsV2, "insights-v2", false,
+		"Use Insights v2 for package metadata enrichment")
+	cmd.Flags().StringVar(&packageProfileReportJSON, "report-json", "",
+		"Path to save the profile in JSON format, defaults to stdout")
+
+	_ = cmd.MarkFlagRequired("purl")
+
+	return cmd
+}
+
+func executePackageProfile() error {
+	profiler, err := newPackageProfiler(packageProfileFilterSuite, packageProfileInsightsV2)
+	if err != nil {
+		return err
+	}
+
+	p, err := profiler.Profile(packageProfilePackageUrl)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize profile: %w", err)
+	}
+
+	if packageProfileReportJSON == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	return os.WriteFile(packageProfileReportJSON, data, 0o644)
+}
+
+// newPackageProfiler creates a profiler using the same enrichment
+// and policy configuration as scan
+func newPackageProfiler(filterSuitePath string, insightsV2 bool) (profile.Profiler, error) {
+	var enricher scanner.PackageMetaEnricher
+	if insightsV2 {
+		if auth.CommunityMode() {
+			return nil, fmt.Errorf("access to Insights v2 requires an API key. For more details: https://docs.safedep.io/cloud/quickstart/")
+		}
+
+		client, err := auth.InsightsV2ClientConnection("vet-insights-v2")
+		if err != nil {
+			return nil, err
+		}
+
+		enricher, err = scanner.NewInsightBasedPackageEnricherV2(client)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		insightsEnricher, err := scanner.NewInsightBasedPackageEnricher(scanner.InsightsBasedPackageMetaEnricherConfig{
+			ApiUrl:     auth.ApiUrl(),
+			ApiAuthKey: auth.ApiKey(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		enricher = insightsEnricher
+	}
+
+	var fs *filtersuite.FilterSuite
+	if filterSuitePath != "" {
+		var err error
+		fs, err = analyzer.LoadFilterSuiteFromFile(filterSuitePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load filter suite: %w", err)
+		}
+	}
+
+	enrichers := []scanner.PackageMetaEnricher{enricher}
+
+	// Existing malware analysis is looked up without submitting the
+	// package for analysis so that the profile is not delayed
+	if !auth.CommunityMode() {
+		client, err := auth.MalwareAnalysisClientConnection("vet-malware-analysis")
+		if err != nil {
+			return nil, err
+		}
+
+		malwareEnricher, err := scanner.NewMalysisMalwareQueryEnricher(client,
+			scanner.MalysisMalwareQueryEnricherConfig{})
+		if err != nil {
+			return nil, err
+		}
+
+		enrichers = append(enrichers, malwareEnricher)
+	}
+
+	return profile.NewProfiler(profile.ProfilerConfig{
+		Enrichers:   enrichers,
+		FilterSuite: fs,
+		Maintainers: profile.NewRegistryMaintainerResolver(profile.RegistryMaintainerResolverConfig{}),
+	})
+}
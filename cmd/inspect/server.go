@@ -0,0 +1,76 @@
+package inspect
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/safedep/vet/internal/ui"
+	"github.com/safedep/vet/pkg/chatops"
+	"github.com/spf13/cobra"
+)
+
+const slackSigningSecretEnvKey = "VET_SLACK_SIGNING_SECRET"
+
+var (
+	inspectServerAddress     string
+	inspectServerFilterSuite string
+	inspectServerInsightsV2  bool
+)
+
+func newInspectServerCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "server",
+		Short: "Serve package inspection for chat bots",
+		Long: fmt.Sprintf(`Serve package inspection for chat bots. Slack slash commands
+e.g. "/vet check lodash@4.17.20" are served at /slack/command. The signing
+secret of the Slack app must be set in %s`, slackSigningSecretEnvKey),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := startInspectServer()
+			if err != nil {
+				ui.PrintError("Failed: %v", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&inspectServerAddress, "address", "127.0.0.1:8000",
+		"Address to listen on")
+	cmd.Flags().StringVar(&inspectServerFilterSuite, "filter-suite", "",
+		"Filter suite to evaluate as policy for the verdict")
+	cmd.Flags().BoolVar(&inspectServerInsightsV2, "insights-v2", false,
+		"Use Insights v2 for package metadata enrichment")
+
+	return cmd
+}
+
+func startInspectServer() error {
+	profiler, err := newPackageProfiler(inspectServerFilterSuite, inspectServerInsightsV2)
+	if err != nil {
+		return err
+	}
+
+	slackHandler, err := chatops.NewSlackCommandHandler(chatops.SlackCommandHandlerConfig{
+		SigningSecret: os.Getenv(slackSigningSecretEnvKey),
+		Profiler:      profiler,
+	})
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/slack/command", slackHandler)
+
+	server := &http.Server{
+		Addr:              inspectServerAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+
+	ui.PrintMsg("Listening on %s", inspectServerAddress)
+	return server.ListenAndServe()
+}
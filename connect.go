@@ -6,12 +6,12 @@ import (
 	"os"
 
 	"context"
-	"net/http"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/cli/oauth/device"
 	"github.com/safedep/vet/internal/connect"
 	"github.com/safedep/vet/internal/ui"
+	"github.com/safedep/vet/pkg/common/httpclient"
 	"github.com/safedep/vet/pkg/common/logger"
 	"github.com/spf13/cobra"
 )
@@ -122,7 +122,7 @@ func getAccessTokenViaDeviceFlow() (string, error) {
 func connectGithubWithDeviceFlow() (string, error) {
 	clientID := connect.GetGithubOAuth2ClientId()
 	scopes := []string{"repo", "read:org"}
-	httpClient := http.DefaultClient
+	httpClient := httpclient.Default()
 
 	logger.Debugf("Initiating Github device flow auth using clientId: %s", clientID)
 
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/safedep/vet/internal/command"
+	"github.com/safedep/vet/internal/ui"
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/feedback"
+)
+
+var (
+	feedbackEcosystem     string
+	feedbackPackageName   string
+	feedbackVersion       string
+	feedbackKind          string
+	feedbackFalsePositive bool
+	feedbackComment       string
+)
+
+func newFeedbackCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "feedback",
+		Short: "Record false positive or true positive feedback on findings",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			command.FailOnError("feedback", recordFeedback())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&feedbackEcosystem, "ecosystem", "", "",
+		"Ecosystem of the package e.g. npm, PyPI")
+	cmd.Flags().StringVarP(&feedbackPackageName, "package", "", "",
+		"Name of the package")
+	cmd.Flags().StringVarP(&feedbackVersion, "version", "", "",
+		"Version of the package, feedback applies to all versions when empty")
+	cmd.Flags().StringVarP(&feedbackKind, "kind", "", string(analyzer.FindingKindMalware),
+		"Kind of finding e.g. malware, threat")
+	cmd.Flags().BoolVarP(&feedbackFalsePositive, "false-positive", "", false,
+		"Mark the finding as false positive, otherwise true positive")
+	cmd.Flags().StringVarP(&feedbackComment, "comment", "", "",
+		"Optional comment for the feedback")
+
+	return cmd
+}
+
+func recordFeedback() error {
+	if feedbackEcosystem == "" || feedbackPackageName == "" {
+		return errors.New("ecosystem and package are required")
+	}
+
+	config, err := feedback.DefaultFileStoreConfig()
+	if err != nil {
+		return err
+	}
+
+	store, err := feedback.NewFileStore(config)
+	if err != nil {
+		return err
+	}
+
+	verdict := feedback.VerdictTruePositive
+	if feedbackFalsePositive {
+		verdict = feedback.VerdictFalsePositive
+	}
+
+	err = store.Add(feedback.Record{
+		Ecosystem: feedbackEcosystem,
+		Name:      feedbackPackageName,
+		Version:   feedbackVersion,
+		Kind:      analyzer.FindingKind(feedbackKind),
+		Verdict:   verdict,
+		Comment:   feedbackComment,
+	})
+	if err != nil {
+		return err
+	}
+
+	ui.PrintSuccess("Recorded %s feedback for %s/%s", verdict, feedbackEcosystem, feedbackPackageName)
+	return nil
+}
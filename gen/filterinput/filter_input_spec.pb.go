@@ -170,6 +170,7 @@ type FilterInput struct {
 	Scorecard *models.InsightScorecard     `protobuf:"bytes,3,opt,name=scorecard,proto3" json:"scorecard,omitempty"`
 	Projects  []*models.InsightProjectInfo `protobuf:"bytes,4,rep,name=projects,proto3" json:"projects,omitempty"`
 	Licenses  []string                     `protobuf:"bytes,5,rep,name=licenses,proto3" json:"licenses,omitempty"`
+	Tags      map[string]string            `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *FilterInput) Reset() {
@@ -237,6 +238,13 @@ func (x *FilterInput) GetLicenses() []string {
 	return nil
 }
 
+func (x *FilterInput) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
 var File_filter_input_spec_proto protoreflect.FileDescriptor
 
 var file_filter_input_spec_proto_rawDesc = []byte{
@@ -265,7 +273,7 @@ var file_filter_input_spec_proto_rawDesc = []byte{
 	0x65, 0x63, 0x6f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
 	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a,
 	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xec, 0x01, 0x0a, 0x0b, 0x46, 0x69, 0x6c, 0x74,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xd1, 0x02, 0x0a, 0x0b, 0x46, 0x69, 0x6c, 0x74,
 	0x65, 0x72, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x2c, 0x0a, 0x03, 0x70, 0x6b, 0x67, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x49, 0x6e, 0x70,
 	0x75, 0x74, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
@@ -280,10 +288,16 @@ var file_filter_input_spec_proto_rawDesc = []byte{
 	0x73, 0x69, 0x67, 0x68, 0x74, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x6e, 0x66, 0x6f,
 	0x52, 0x08, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x69,
 	0x63, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x69,
-	0x63, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x42, 0x28, 0x5a, 0x26, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x61, 0x66, 0x65, 0x64, 0x65, 0x70, 0x2f, 0x76, 0x65, 0x74,
-	0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x69, 0x6e, 0x70, 0x75, 0x74,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x63, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x06,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x49, 0x6e, 0x70,
+	0x75, 0x74, 0x2e, 0x54, 0x61, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x74, 0x61,
+	0x67, 0x73, 0x1a, 0x37, 0x0a, 0x09, 0x54, 0x61, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x28, 0x5a, 0x26, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x61, 0x66, 0x65, 0x64, 0x65,
+	0x70, 0x2f, 0x76, 0x65, 0x74, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x69, 0x6e, 0x70, 0x75, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -298,30 +312,32 @@ func file_filter_input_spec_proto_rawDescGZIP() []byte {
 	return file_filter_input_spec_proto_rawDescData
 }
 
-var file_filter_input_spec_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_filter_input_spec_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_filter_input_spec_proto_goTypes = []any{
 	(*FilterInputVulnerabilities)(nil),  // 0: FilterInputVulnerabilities
 	(*FilterInputPackageVersion)(nil),   // 1: FilterInputPackageVersion
 	(*FilterInput)(nil),                 // 2: FilterInput
-	(*models.InsightVulnerability)(nil), // 3: InsightVulnerability
-	(*models.InsightScorecard)(nil),     // 4: InsightScorecard
-	(*models.InsightProjectInfo)(nil),   // 5: InsightProjectInfo
+	nil,                                 // 3: FilterInput.TagsEntry
+	(*models.InsightVulnerability)(nil), // 4: InsightVulnerability
+	(*models.InsightScorecard)(nil),     // 5: InsightScorecard
+	(*models.InsightProjectInfo)(nil),   // 6: InsightProjectInfo
 }
 var file_filter_input_spec_proto_depIdxs = []int32{
-	3, // 0: FilterInputVulnerabilities.all:type_name -> InsightVulnerability
-	3, // 1: FilterInputVulnerabilities.critical:type_name -> InsightVulnerability
-	3, // 2: FilterInputVulnerabilities.high:type_name -> InsightVulnerability
-	3, // 3: FilterInputVulnerabilities.medium:type_name -> InsightVulnerability
-	3, // 4: FilterInputVulnerabilities.low:type_name -> InsightVulnerability
-	1, // 5: FilterInput.pkg:type_name -> FilterInputPackageVersion
-	0, // 6: FilterInput.vulns:type_name -> FilterInputVulnerabilities
-	4, // 7: FilterInput.scorecard:type_name -> InsightScorecard
-	5, // 8: FilterInput.projects:type_name -> InsightProjectInfo
-	9, // [9:9] is the sub-list for method output_type
-	9, // [9:9] is the sub-list for method input_type
-	9, // [9:9] is the sub-list for extension type_name
-	9, // [9:9] is the sub-list for extension extendee
-	0, // [0:9] is the sub-list for field type_name
+	4,  // 0: FilterInputVulnerabilities.all:type_name -> InsightVulnerability
+	4,  // 1: FilterInputVulnerabilities.critical:type_name -> InsightVulnerability
+	4,  // 2: FilterInputVulnerabilities.high:type_name -> InsightVulnerability
+	4,  // 3: FilterInputVulnerabilities.medium:type_name -> InsightVulnerability
+	4,  // 4: FilterInputVulnerabilities.low:type_name -> InsightVulnerability
+	1,  // 5: FilterInput.pkg:type_name -> FilterInputPackageVersion
+	0,  // 6: FilterInput.vulns:type_name -> FilterInputVulnerabilities
+	5,  // 7: FilterInput.scorecard:type_name -> InsightScorecard
+	6,  // 8: FilterInput.projects:type_name -> InsightProjectInfo
+	3,  // 9: FilterInput.tags:type_name -> FilterInput.TagsEntry
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_filter_input_spec_proto_init() }
@@ -335,7 +351,7 @@ func file_filter_input_spec_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_filter_input_spec_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -107,6 +107,7 @@ type Package struct {
 	Ecosystem Ecosystem `protobuf:"varint,1,opt,name=ecosystem,proto3,enum=Ecosystem" json:"ecosystem,omitempty"`
 	Name      string    `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Version   string    `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Purl      string    `protobuf:"bytes,4,opt,name=purl,proto3" json:"purl,omitempty"`
 }
 
 func (x *Package) Reset() {
@@ -160,6 +161,13 @@ func (x *Package) GetVersion() string {
 	return ""
 }
 
+func (x *Package) GetPurl() string {
+	if x != nil {
+		return x.Purl
+	}
+	return ""
+}
+
 type PackageManifest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -224,35 +232,36 @@ func (x *PackageManifest) GetPackages() []*Package {
 var File_models_proto protoreflect.FileDescriptor
 
 var file_models_proto_rawDesc = []byte{
-	0x0a, 0x0c, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x61,
+	0x0a, 0x0c, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x75,
 	0x0a, 0x07, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x09, 0x65, 0x63, 0x6f,
 	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x45,
 	0x63, 0x6f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x52, 0x09, 0x65, 0x63, 0x6f, 0x73, 0x79, 0x73,
 	0x74, 0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
 	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x22, 0x75, 0x0a, 0x0f, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x4d, 0x61, 0x6e, 0x69,
-	0x66, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x09, 0x65, 0x63, 0x6f, 0x73, 0x79, 0x73, 0x74, 0x65,
-	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x45, 0x63, 0x6f, 0x73, 0x79, 0x73,
-	0x74, 0x65, 0x6d, 0x52, 0x09, 0x65, 0x63, 0x6f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x12,
-	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
-	0x74, 0x68, 0x12, 0x24, 0x0a, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18, 0x03,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x08,
-	0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x2a, 0xae, 0x01, 0x0a, 0x09, 0x45, 0x63, 0x6f,
-	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x15, 0x0a, 0x11, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57,
-	0x4e, 0x5f, 0x45, 0x43, 0x4f, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x10, 0x00, 0x12, 0x09, 0x0a,
-	0x05, 0x4d, 0x61, 0x76, 0x65, 0x6e, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x75, 0x62, 0x79,
-	0x47, 0x65, 0x6d, 0x73, 0x10, 0x02, 0x12, 0x06, 0x0a, 0x02, 0x47, 0x6f, 0x10, 0x03, 0x12, 0x07,
-	0x0a, 0x03, 0x4e, 0x70, 0x6d, 0x10, 0x04, 0x12, 0x08, 0x0a, 0x04, 0x50, 0x79, 0x50, 0x49, 0x10,
-	0x05, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x61, 0x72, 0x67, 0x6f, 0x10, 0x06, 0x12, 0x09, 0x0a, 0x05,
-	0x4e, 0x75, 0x47, 0x65, 0x74, 0x10, 0x07, 0x12, 0x0d, 0x0a, 0x09, 0x50, 0x61, 0x63, 0x6b, 0x61,
-	0x67, 0x69, 0x73, 0x74, 0x10, 0x08, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x65, 0x78, 0x10, 0x09, 0x12,
-	0x07, 0x0a, 0x03, 0x50, 0x75, 0x62, 0x10, 0x0a, 0x12, 0x11, 0x0a, 0x0d, 0x43, 0x79, 0x63, 0x6c,
-	0x6f, 0x6e, 0x65, 0x44, 0x78, 0x53, 0x42, 0x4f, 0x4d, 0x10, 0x0b, 0x12, 0x0c, 0x0a, 0x08, 0x53,
-	0x70, 0x64, 0x78, 0x53, 0x42, 0x4f, 0x4d, 0x10, 0x0c, 0x42, 0x23, 0x5a, 0x21, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x61, 0x66, 0x65, 0x64, 0x65, 0x70, 0x2f,
-	0x76, 0x65, 0x74, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x75, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x70, 0x75, 0x72, 0x6c, 0x22, 0x75, 0x0a, 0x0f, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
+	0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x09, 0x65, 0x63, 0x6f, 0x73,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x45, 0x63,
+	0x6f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x52, 0x09, 0x65, 0x63, 0x6f, 0x73, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x24, 0x0a, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x52, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x2a, 0xae, 0x01, 0x0a,
+	0x09, 0x45, 0x63, 0x6f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x15, 0x0a, 0x11, 0x55, 0x4e,
+	0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x45, 0x43, 0x4f, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x10,
+	0x00, 0x12, 0x09, 0x0a, 0x05, 0x4d, 0x61, 0x76, 0x65, 0x6e, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08,
+	0x52, 0x75, 0x62, 0x79, 0x47, 0x65, 0x6d, 0x73, 0x10, 0x02, 0x12, 0x06, 0x0a, 0x02, 0x47, 0x6f,
+	0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x70, 0x6d, 0x10, 0x04, 0x12, 0x08, 0x0a, 0x04, 0x50,
+	0x79, 0x50, 0x49, 0x10, 0x05, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x61, 0x72, 0x67, 0x6f, 0x10, 0x06,
+	0x12, 0x09, 0x0a, 0x05, 0x4e, 0x75, 0x47, 0x65, 0x74, 0x10, 0x07, 0x12, 0x0d, 0x0a, 0x09, 0x50,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x69, 0x73, 0x74, 0x10, 0x08, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x65,
+	0x78, 0x10, 0x09, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x75, 0x62, 0x10, 0x0a, 0x12, 0x11, 0x0a, 0x0d,
+	0x43, 0x79, 0x63, 0x6c, 0x6f, 0x6e, 0x65, 0x44, 0x78, 0x53, 0x42, 0x4f, 0x4d, 0x10, 0x0b, 0x12,
+	0x0c, 0x0a, 0x08, 0x53, 0x70, 0x64, 0x78, 0x53, 0x42, 0x4f, 0x4d, 0x10, 0x0c, 0x42, 0x23, 0x5a,
+	0x21, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x61, 0x66, 0x65,
+	0x64, 0x65, 0x70, 0x2f, 0x76, 0x65, 0x74, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
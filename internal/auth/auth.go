@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/safedep/vet/pkg/common/logger"
 	"gopkg.in/yaml.v2"
 )
 
@@ -51,6 +52,12 @@ type Config struct {
 	CloudAccessToken          string    `yaml:"cloud_access_token"`
 	CloudRefreshToken         string    `yaml:"cloud_refresh_token"`
 	CloudAccessTokenUpdatedAt time.Time `yaml:"cloud_access_token_updated_at"`
+
+	// Secret of the client is stored only in the OS keychain
+	CloudClientId string `yaml:"cloud_client_id,omitempty"`
+
+	// Secrets are stored in the OS keychain when set to keychain
+	CredentialStore string `yaml:"credential_store,omitempty"`
 }
 
 // Global config to be used during runtime
@@ -147,7 +154,7 @@ func CloudIdentityServiceTokenUrl() string {
 
 func CloudAccessToken() string {
 	if globalConfig != nil {
-		return globalConfig.CloudAccessToken
+		return configSecret(credentialKeyCloudAccessToken, globalConfig.CloudAccessToken)
 	}
 
 	return ""
@@ -155,7 +162,7 @@ func CloudAccessToken() string {
 
 func CloudRefreshToken() string {
 	if globalConfig != nil {
-		return globalConfig.CloudRefreshToken
+		return configSecret(credentialKeyCloudRefreshToken, globalConfig.CloudRefreshToken)
 	}
 
 	return ""
@@ -252,7 +259,7 @@ func ApiKey() string {
 	}
 
 	if globalConfig != nil {
-		return globalConfig.ApiKey
+		return configSecret(credentialKeyApiKey, globalConfig.ApiKey)
 	}
 
 	return ""
@@ -309,7 +316,12 @@ func loadConfiguration() error {
 }
 
 func persistConfiguration() error {
-	data, err := yaml.Marshal(globalConfig)
+	config, err := keychainConfig(*globalConfig)
+	if err != nil {
+		logger.Warnf("Failed to use keychain, storing credentials in config file: %v", err)
+	}
+
+	data, err := yaml.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("config serialization failed: %w", err)
 	}
@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/httpclient"
+	"github.com/safedep/vet/pkg/common/logger"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	cloudClientIdEnvKey     = "VET_CLOUD_CLIENT_ID"
+	cloudClientSecretEnvKey = "VET_CLOUD_CLIENT_SECRET"
+	cloudTokenUrlEnvKey     = "VET_CLOUD_TOKEN_URL"
+
+	credentialKeyCloudClientSecret = "cloud-client-secret"
+)
+
+// CredentialProvider returns the token sent as authorization with requests
+// to SafeDep Cloud. Providers of tokens that expire refresh them so that
+// long running operations such as a scan with sync do not fail midway.
+type CredentialProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// CredentialProviderFunc adapts a function to CredentialProvider
+type CredentialProviderFunc func(ctx context.Context) (string, error)
+
+func (f CredentialProviderFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+type staticCredentialProvider string
+
+func (p staticCredentialProvider) Token(_ context.Context) (string, error) {
+	return string(p), nil
+}
+
+// staticCredentials returns nil for an empty token
+func staticCredentials(token string) CredentialProvider {
+	if token == "" {
+		return nil
+	}
+
+	return staticCredentialProvider(token)
+}
+
+// ClientCredentialsConfig of the OAuth2 client credentials flow used by
+// machine identities e.g. CI pipelines
+type ClientCredentialsConfig struct {
+	ClientId     string
+	ClientSecret string
+	TokenUrl     string
+	Audience     string
+	Scopes       []string
+
+	// Token is refreshed when it expires within this duration
+	RefreshSkew time.Duration
+}
+
+type clientCredentialsProvider struct {
+	tokenSource oauth2.TokenSource
+}
+
+// NewClientCredentialsProvider obtains a token using client credentials on
+// first use and obtains a new token when it is about to expire
+func NewClientCredentialsProvider(config ClientCredentialsConfig) (CredentialProvider, error) {
+	if config.ClientId == "" || config.ClientSecret == "" {
+		return nil, errors.New("client id and client secret are required")
+	}
+
+	if config.TokenUrl == "" {
+		return nil, errors.New("token url is required")
+	}
+
+	if config.RefreshSkew == 0 {
+		config.RefreshSkew = cloudAccessTokenRefreshSkew
+	}
+
+	cc := clientcredentials.Config{
+		ClientID:       config.ClientId,
+		ClientSecret:   config.ClientSecret,
+		TokenURL:       config.TokenUrl,
+		Scopes:         config.Scopes,
+		EndpointParams: url.Values{},
+	}
+
+	if config.Audience != "" {
+		cc.EndpointParams.Set("audience", config.Audience)
+	}
+
+	// Token requests use the shared client for proxy and DNS settings
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpclient.Default())
+
+	return &clientCredentialsProvider{
+		tokenSource: oauth2.ReuseTokenSourceWithExpiry(nil, cc.TokenSource(ctx), config.RefreshSkew),
+	}, nil
+}
+
+func (p *clientCredentialsProvider) Token(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	token, err := p.tokenSource.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to get token using client credentials: %w", err)
+	}
+
+	return token.AccessToken, nil
+}
+
+// CloudClientCredentials returns the client credentials configured in
+// environment or using `vet auth login --client-id`. The client secret
+// is read from the OS keychain when not set in environment.
+func CloudClientCredentials() (ClientCredentialsConfig, bool) {
+	clientId := os.Getenv(cloudClientIdEnvKey)
+	if clientId == "" && globalConfig != nil {
+		clientId = globalConfig.CloudClientId
+	}
+
+	if clientId == "" {
+		return ClientCredentialsConfig{}, false
+	}
+
+	clientSecret := os.Getenv(cloudClientSecretEnvKey)
+	if clientSecret == "" && keychain != nil {
+		secret, err := keychain.Get(credentialKeyCloudClientSecret)
+		if err != nil && !errors.Is(err, ErrCredentialNotFound) {
+			logger.Warnf("Failed to read client secret from keychain: %v", err)
+		}
+
+		clientSecret = secret
+	}
+
+	if clientSecret == "" {
+		logger.Warnf("Client id is configured without a client secret, ignoring client credentials")
+		return ClientCredentialsConfig{}, false
+	}
+
+	return ClientCredentialsConfig{
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		TokenUrl:     CloudClientTokenUrl(),
+		Audience:     CloudIdentityServiceAudience(),
+	}, true
+}
+
+// CloudClientTokenUrl is the token endpoint used for client credentials
+func CloudClientTokenUrl() string {
+	if tokenUrl := os.Getenv(cloudTokenUrlEnvKey); tokenUrl != "" {
+		return tokenUrl
+	}
+
+	return CloudIdentityServiceTokenUrl()
+}
+
+// PersistCloudClientCredentials stores the client secret in the OS keychain
+// and the client id in the config file. The client secret is never written
+// to the config file.
+func PersistCloudClientCredentials(clientId, clientSecret, domain string) error {
+	if !KeychainEnabled() {
+		return ErrKeychainNotAvailable
+	}
+
+	if err := keychain.Set(credentialKeyCloudClientSecret, clientSecret); err != nil {
+		return fmt.Errorf("failed to store client secret in keychain: %w", err)
+	}
+
+	if globalConfig == nil {
+		c := DefaultConfig()
+		globalConfig = &c
+	}
+
+	if domain != "" {
+		globalConfig.TenantDomain = domain
+	}
+
+	globalConfig.CloudClientId = clientId
+	return persistConfiguration()
+}
+
+// CloudCredentialProvider returns the credentials for SafeDep Cloud in order
+// of precedence: API key, client credentials and the access token of device
+// code login. Returns nil when credentials are not configured.
+func CloudCredentialProvider() (CredentialProvider, error) {
+	if key := ApiKey(); key != "" {
+		return staticCredentials(key), nil
+	}
+
+	if config, ok := CloudClientCredentials(); ok {
+		logger.Debugf("Using client credentials of %s for cloud access", config.ClientId)
+		return NewClientCredentialsProvider(config)
+	}
+
+	if CloudAccessToken() != "" {
+		logger.Debugf("Using cloud access token for cloud access")
+		return CredentialProviderFunc(ValidCloudAccessToken), nil
+	}
+
+	return nil, nil
+}
+
+// perRPCCredentials sends the token of a provider as authorization
+// metadata, obtaining the token for every request
+type perRPCCredentials struct {
+	provider                 CredentialProvider
+	requireTransportSecurity bool
+}
+
+var _ credentials.PerRPCCredentials = (*perRPCCredentials)(nil)
+
+func (c *perRPCCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	token, err := c.provider.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"authorization": token}, nil
+}
+
+func (c *perRPCCredentials) RequireTransportSecurity() bool {
+	return c.requireTransportSecurity
+}
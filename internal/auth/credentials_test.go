@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCredentialsProvider(t *testing.T) {
+	cases := []struct {
+		name      string
+		expiresIn int
+		requests  int
+	}{
+		{"token is reused till expiry", 3600, 1},
+		{"token about to expire is refreshed", 60, 2},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+				assert.Equal(t, "https://cloud.safedep.io", r.Form.Get("audience"))
+
+				clientId, clientSecret, ok := r.BasicAuth()
+				assert.True(t, ok)
+				assert.Equal(t, "client", clientId)
+				assert.Equal(t, "secret", clientSecret)
+
+				requests++
+
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`,
+					requests, test.expiresIn)
+			}))
+			defer server.Close()
+
+			provider, err := NewClientCredentialsProvider(ClientCredentialsConfig{
+				ClientId:     "client",
+				ClientSecret: "secret",
+				TokenUrl:     server.URL,
+				Audience:     "https://cloud.safedep.io",
+			})
+			assert.NoError(t, err)
+
+			token, err := provider.Token(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "token-1", token)
+
+			token, err = provider.Token(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("token-%d", test.requests), token)
+			assert.Equal(t, test.requests, requests)
+		})
+	}
+}
+
+func TestClientCredentialsProviderConfig(t *testing.T) {
+	_, err := NewClientCredentialsProvider(ClientCredentialsConfig{ClientId: "client", TokenUrl: "https://token"})
+	assert.ErrorContains(t, err, "client secret")
+
+	_, err = NewClientCredentialsProvider(ClientCredentialsConfig{ClientId: "client", ClientSecret: "secret"})
+	assert.ErrorContains(t, err, "token url")
+}
+
+func TestCloudCredentialProvider(t *testing.T) {
+	previousKeychain, previousConfig := keychain, globalConfig
+	t.Cleanup(func() {
+		keychain, globalConfig = previousKeychain, previousConfig
+	})
+
+	keychain = memoryCredentialStore{credentialKeyCloudClientSecret: "secret"}
+	globalConfig = &Config{CloudClientId: "client"}
+
+	t.Setenv(apiKeyEnvKey, "api-key")
+
+	provider, err := CloudCredentialProvider()
+	assert.NoError(t, err)
+
+	token, err := provider.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "api-key", token)
+
+	t.Setenv(apiKeyEnvKey, "")
+	t.Setenv(apiKeyAlternateEnvKey, "")
+
+	config, ok := CloudClientCredentials()
+	assert.True(t, ok)
+	assert.Equal(t, "client", config.ClientId)
+	assert.Equal(t, "secret", config.ClientSecret)
+
+	provider, err = CloudCredentialProvider()
+	assert.NoError(t, err)
+	assert.IsType(t, &clientCredentialsProvider{}, provider)
+
+	keychain = memoryCredentialStore{}
+
+	_, ok = CloudClientCredentials()
+	assert.False(t, ok)
+
+	provider, err = CloudCredentialProvider()
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+}
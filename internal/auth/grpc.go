@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -13,24 +15,42 @@ import (
 )
 
 // Create a gRPC client connection for the control plane
-// based on available configuration
+// based on available configuration. The access token is refreshed
+// before every request when about to expire.
 func ControlPlaneClientConnection(name string) (*grpc.ClientConn, error) {
-	return cloudClientConnection(name, ControlTowerUrl(), CloudAccessToken())
+	_, err := ValidCloudAccessToken(context.Background())
+	if err != nil && !errors.Is(err, ErrNotLoggedIn) {
+		return nil, err
+	}
+
+	var credentials CredentialProvider
+	if err == nil {
+		credentials = CredentialProviderFunc(ValidCloudAccessToken)
+	}
+
+	return cloudClientConnection(name, ControlTowerUrl(), credentials)
 }
 
 func SyncClientConnection(name string) (*grpc.ClientConn, error) {
-	return cloudClientConnection(name, SyncApiUrl(), ApiKey())
+	credentials, err := CloudCredentialProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return cloudClientConnection(name, SyncApiUrl(), credentials)
 }
 
 func InsightsV2ClientConnection(name string) (*grpc.ClientConn, error) {
-	return cloudClientConnection(name, InsightsApiV2Url(), ApiKey())
+	return cloudClientConnection(name, InsightsApiV2Url(), staticCredentials(ApiKey()))
 }
 
 func MalwareAnalysisClientConnection(name string) (*grpc.ClientConn, error) {
-	return cloudClientConnection(name, DataPlaneUrl(), ApiKey())
+	return cloudClientConnection(name, DataPlaneUrl(), staticCredentials(ApiKey()))
 }
 
-func cloudClientConnection(name, loc, tok string) (*grpc.ClientConn, error) {
+// cloudClientConnection sends the token of the credentials, when not nil,
+// with every request in addition to the cloud request headers
+func cloudClientConnection(name, loc string, credentials CredentialProvider) (*grpc.ClientConn, error) {
 	parsedUrl, err := url.Parse(loc)
 	if err != nil {
 		return nil, err
@@ -44,6 +64,26 @@ func cloudClientConnection(name, loc, tok string) (*grpc.ClientConn, error) {
 	logger.Debugf("Establishing grpc connection for: %s host: %s, port: %s",
 		name, host, port)
 
+	dopts := []grpc.DialOption{}
+	if credentials != nil {
+		dopts = append(dopts, grpc.WithPerRPCCredentials(&perRPCCredentials{
+			provider:                 credentials,
+			requireTransportSecurity: !InsecureTransportEnabled(),
+		}))
+	}
+
+	// Token is sent by the credentials so that it is obtained per request
+	client, err := drygrpc.GrpcClient(name, host, port,
+		"", CloudRequestHeaders(), dopts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+
+	return client, nil
+}
+
+// CloudRequestHeaders are sent with every request to SafeDep Cloud
+func CloudRequestHeaders() http.Header {
 	headers := http.Header{}
 	headers.Set("x-tenant-id", TenantDomain())
 
@@ -52,11 +92,11 @@ func cloudClientConnection(name, loc, tok string) (*grpc.ClientConn, error) {
 		headers.Set("x-mock-user", vetTenantMockUser)
 	}
 
-	client, err := drygrpc.GrpcClient(name, host, port,
-		tok, headers, []grpc.DialOption{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
-	}
+	return headers
+}
 
-	return client, nil
+// InsecureTransportEnabled is true when gRPC clients are configured to
+// connect without TLS using environment
+func InsecureTransportEnabled() bool {
+	return os.Getenv("INSECURE_GRPC_CLIENT_USE_INSECURE_TRANSPORT") == "true"
 }
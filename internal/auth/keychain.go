@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/safedep/vet/pkg/common/encryption"
+	"github.com/safedep/vet/pkg/common/logger"
+)
+
+const (
+	// Set to "file" to keep credentials in the config file
+	credentialStoreEnvKey = "VET_CREDENTIAL_STORE"
+
+	credentialStoreKeychain = "keychain"
+	credentialStoreFile     = "file"
+
+	// Service name of credentials in the OS keychain
+	keychainService = "safedep-vet"
+
+	credentialKeyApiKey            = "api-key"
+	credentialKeyCloudAccessToken  = "cloud-access-token"
+	credentialKeyCloudRefreshToken = "cloud-refresh-token"
+	credentialKeyRegistryPrefix    = "registry/"
+	credentialKeyEncryptionKey     = "encryption-key"
+)
+
+var (
+	ErrCredentialNotFound    = errors.New("credential not found")
+	ErrKeychainNotAvailable  = errors.New("keychain is not available")
+	keychainSecretCache      = map[string]string{}
+	keychainSecretCacheMutex sync.Mutex
+)
+
+// CredentialStore stores secrets in an OS keychain such as macOS Keychain,
+// Windows Credential Manager or Secret Service (libsecret) on Linux
+type CredentialStore interface {
+	Get(key string) (string, error)
+	Set(key, secret string) error
+	Delete(key string) error
+}
+
+// Platform keychain, nil when not supported
+var keychain CredentialStore = newKeychain()
+
+// KeychainEnabled is true when credentials are stored in the OS keychain
+// instead of the config file
+func KeychainEnabled() bool {
+	return keychain != nil && !strings.EqualFold(os.Getenv(credentialStoreEnvKey), credentialStoreFile)
+}
+
+// RegistryToken returns the token of a package registry stored using
+// PersistRegistryToken
+func RegistryToken(host string) (string, error) {
+	if keychain == nil {
+		return "", ErrKeychainNotAvailable
+	}
+
+	return keychain.Get(credentialKeyRegistryPrefix + host)
+}
+
+// PersistRegistryToken stores the token of a package registry in the
+// OS keychain. Registry tokens are never written to the config file.
+func PersistRegistryToken(host, token string) error {
+	if !KeychainEnabled() {
+		return ErrKeychainNotAvailable
+	}
+
+	return keychain.Set(credentialKeyRegistryPrefix+host, token)
+}
+
+// DeleteRegistryToken removes the token of a package registry
+func DeleteRegistryToken(host string) error {
+	if keychain == nil {
+		return ErrKeychainNotAvailable
+	}
+
+	return keychain.Delete(credentialKeyRegistryPrefix + host)
+}
+
+// EncryptionCipher returns the cipher for data at rest such as caches and
+// sync spool. The key in environment takes precedence over the key stored
+// in the keychain using PersistEncryptionKey. Returns nil when a key is
+// not configured.
+func EncryptionCipher() (encryption.Cipher, error) {
+	if key := os.Getenv(encryption.EncryptionKeyEnvKey); key != "" {
+		return encryption.CipherFromKey(key)
+	}
+
+	if keychain == nil || !KeychainEnabled() {
+		return nil, nil
+	}
+
+	key, err := keychain.Get(credentialKeyEncryptionKey)
+	if err != nil {
+		if errors.Is(err, ErrCredentialNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read encryption key from keychain: %w", err)
+	}
+
+	return encryption.CipherFromKey(key)
+}
+
+// PersistEncryptionKey stores the key for data at rest in the OS keychain.
+// Encryption key is never written to the config file.
+func PersistEncryptionKey(key string) error {
+	if !KeychainEnabled() {
+		return ErrKeychainNotAvailable
+	}
+
+	if _, err := encryption.CipherFromKey(key); err != nil {
+		return err
+	}
+
+	return keychain.Set(credentialKeyEncryptionKey, key)
+}
+
+// DeleteEncryptionKey removes the key for data at rest. Data encrypted
+// using the key is not readable after it is removed.
+func DeleteEncryptionKey() error {
+	if keychain == nil {
+		return ErrKeychainNotAvailable
+	}
+
+	return keychain.Delete(credentialKeyEncryptionKey)
+}
+
+// DeleteCredentials removes the API key, cloud tokens and client credentials
+// from the keychain and the config file
+func DeleteCredentials() error {
+	if keychain != nil {
+		for _, key := range []string{credentialKeyApiKey, credentialKeyCloudAccessToken,
+			credentialKeyCloudRefreshToken, credentialKeyCloudClientSecret} {
+			err := keychain.Delete(key)
+			if err != nil && !errors.Is(err, ErrCredentialNotFound) {
+				return fmt.Errorf("failed to delete %s from keychain: %w", key, err)
+			}
+		}
+	}
+
+	keychainSecretCacheMutex.Lock()
+	clear(keychainSecretCache)
+	keychainSecretCacheMutex.Unlock()
+
+	if globalConfig == nil {
+		return nil
+	}
+
+	globalConfig.ApiKey = ""
+	globalConfig.CloudAccessToken = ""
+	globalConfig.CloudRefreshToken = ""
+	globalConfig.CloudClientId = ""
+	globalConfig.CredentialStore = ""
+
+	return persistConfiguration()
+}
+
+// configSecret returns the secret from the config file or from the
+// keychain when the config file refers to it
+func configSecret(key, value string) string {
+	if value != "" || globalConfig == nil || globalConfig.CredentialStore != credentialStoreKeychain {
+		return value
+	}
+
+	if keychain == nil {
+		logger.Warnf("Credentials are in keychain but keychain is not available")
+		return ""
+	}
+
+	keychainSecretCacheMutex.Lock()
+	defer keychainSecretCacheMutex.Unlock()
+
+	if secret, ok := keychainSecretCache[key]; ok {
+		return secret
+	}
+
+	secret, err := keychain.Get(key)
+	if err != nil && !errors.Is(err, ErrCredentialNotFound) {
+		logger.Warnf("Failed to read %s from keychain: %v", key, err)
+	}
+
+	keychainSecretCache[key] = secret
+	return secret
+}
+
+// keychainConfig moves secrets in config to the keychain and returns the
+// config to be written to file
+func keychainConfig(config Config) (Config, error) {
+	if !KeychainEnabled() {
+		return config, nil
+	}
+
+	secrets := map[string]string{
+		credentialKeyApiKey:            config.ApiKey,
+		credentialKeyCloudAccessToken:  config.CloudAccessToken,
+		credentialKeyCloudRefreshToken: config.CloudRefreshToken,
+	}
+
+	keychainSecretCacheMutex.Lock()
+	defer keychainSecretCacheMutex.Unlock()
+
+	for key, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+
+		if err := keychain.Set(key, secret); err != nil {
+			return config, fmt.Errorf("failed to store %s in keychain: %w", key, err)
+		}
+
+		keychainSecretCache[key] = secret
+	}
+
+	config.ApiKey = ""
+	config.CloudAccessToken = ""
+	config.CloudRefreshToken = ""
+	config.CredentialStore = credentialStoreKeychain
+
+	return config, nil
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Exit code of security(1) when an item is not found
+const darwinKeychainItemNotFound = 44
+
+// darwinKeychain uses security(1) to access the login keychain
+type darwinKeychain struct {
+	path string
+}
+
+func newKeychain() CredentialStore {
+	path, err := exec.LookPath("security")
+	if err != nil {
+		return nil
+	}
+
+	return &darwinKeychain{path: path}
+}
+
+func (k *darwinKeychain) Get(key string) (string, error) {
+	out, err := k.run(nil, "find-generic-password", "-s", keychainService, "-a", key, "-w")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// Set passes the secret using interactive mode so that it is not
+// visible in process arguments
+func (k *darwinKeychain) Set(key, secret string) error {
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		strconv.Quote(keychainService), strconv.Quote(key), strconv.Quote(secret))
+
+	_, err := k.run(strings.NewReader(command), "-i")
+	return err
+}
+
+func (k *darwinKeychain) Delete(key string) error {
+	_, err := k.run(nil, "delete-generic-password", "-s", keychainService, "-a", key)
+	return err
+}
+
+func (k *darwinKeychain) run(stdin io.Reader, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command(k.path, args...)
+	cmd.Stderr = &stderr
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == darwinKeychainItemNotFound {
+		return nil, ErrCredentialNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("keychain command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// secretServiceKeychain uses secret-tool(1) of libsecret to access the
+// Secret Service of the desktop session e.g. GNOME Keyring or KWallet
+type secretServiceKeychain struct {
+	path string
+}
+
+func newKeychain() CredentialStore {
+	path, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return nil
+	}
+
+	return &secretServiceKeychain{path: path}
+}
+
+func (k *secretServiceKeychain) Get(key string) (string, error) {
+	out, err := k.run(nil, "lookup", "service", keychainService, "account", key)
+	if err != nil {
+		return "", err
+	}
+
+	// secret-tool exits with success and empty output for some backends
+	if len(out) == 0 {
+		return "", ErrCredentialNotFound
+	}
+
+	return string(out), nil
+}
+
+// Set passes the secret on stdin so that it is not visible in process arguments
+func (k *secretServiceKeychain) Set(key, secret string) error {
+	_, err := k.run(strings.NewReader(secret), "store",
+		"--label", fmt.Sprintf("vet (%s)", key),
+		"service", keychainService, "account", key)
+
+	return err
+}
+
+func (k *secretServiceKeychain) Delete(key string) error {
+	_, err := k.run(nil, "clear", "service", keychainService, "account", key)
+	return err
+}
+
+func (k *secretServiceKeychain) run(stdin io.Reader, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command(k.path, args...)
+	cmd.Stderr = &stderr
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		// secret-tool exits with 1 without an error message when not found
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 && stderr.Len() == 0 {
+			return nil, ErrCredentialNotFound
+		}
+
+		return nil, fmt.Errorf("keychain command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
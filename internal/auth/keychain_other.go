@@ -0,0 +1,7 @@
+//go:build !darwin && !linux && !windows
+
+package auth
+
+func newKeychain() CredentialStore {
+	return nil
+}
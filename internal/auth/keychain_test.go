@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/safedep/vet/pkg/common/encryption"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryCredentialStore map[string]string
+
+func (m memoryCredentialStore) Get(key string) (string, error) {
+	if secret, ok := m[key]; ok {
+		return secret, nil
+	}
+
+	return "", ErrCredentialNotFound
+}
+
+func (m memoryCredentialStore) Set(key, secret string) error {
+	m[key] = secret
+	return nil
+}
+
+func (m memoryCredentialStore) Delete(key string) error {
+	delete(m, key)
+	return nil
+}
+
+func TestKeychainConfig(t *testing.T) {
+	store := memoryCredentialStore{}
+
+	previousKeychain, previousConfig := keychain, globalConfig
+	t.Cleanup(func() {
+		keychain, globalConfig = previousKeychain, previousConfig
+		clear(keychainSecretCache)
+	})
+
+	keychain = store
+	clear(keychainSecretCache)
+
+	config, err := keychainConfig(Config{ApiKey: "key", CloudAccessToken: "access", TenantDomain: "acme"})
+	assert.NoError(t, err)
+
+	// Secrets are not written to file
+	assert.Equal(t, Config{TenantDomain: "acme", CredentialStore: credentialStoreKeychain}, config)
+	assert.Equal(t, memoryCredentialStore{
+		credentialKeyApiKey:           "key",
+		credentialKeyCloudAccessToken: "access",
+	}, store)
+
+	globalConfig = &config
+	clear(keychainSecretCache)
+
+	assert.Equal(t, "key", configSecret(credentialKeyApiKey, globalConfig.ApiKey))
+	assert.Equal(t, "access", configSecret(credentialKeyCloudAccessToken, globalConfig.CloudAccessToken))
+	assert.Equal(t, "", configSecret(credentialKeyCloudRefreshToken, globalConfig.CloudRefreshToken))
+	assert.Equal(t, "plain", configSecret(credentialKeyApiKey, "plain"))
+
+	t.Setenv(credentialStoreEnvKey, credentialStoreFile)
+
+	config, err = keychainConfig(Config{ApiKey: "new-key"})
+	assert.NoError(t, err)
+	assert.Equal(t, Config{ApiKey: "new-key"}, config)
+	assert.Equal(t, "key", store[credentialKeyApiKey])
+}
+
+func TestEncryptionCipher(t *testing.T) {
+	store := memoryCredentialStore{}
+
+	previousKeychain := keychain
+	t.Cleanup(func() { keychain = previousKeychain })
+
+	keychain = store
+	t.Setenv(encryption.EncryptionKeyEnvKey, "")
+	t.Setenv(credentialStoreEnvKey, "")
+
+	c, err := EncryptionCipher()
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+
+	assert.ErrorContains(t, PersistEncryptionKey("not base64!"), "must be base64 encoded")
+
+	key, err := encryption.GenerateKey()
+	assert.NoError(t, err)
+	assert.NoError(t, PersistEncryptionKey(key))
+
+	c, err = EncryptionCipher()
+	assert.NoError(t, err)
+	assert.NotNil(t, c)
+
+	sealed, err := encryption.Seal(c, []byte("data"))
+	assert.NoError(t, err)
+
+	// Key in environment takes precedence over keychain
+	otherKey, err := encryption.GenerateKey()
+	assert.NoError(t, err)
+	t.Setenv(encryption.EncryptionKeyEnvKey, otherKey)
+
+	c, err = EncryptionCipher()
+	assert.NoError(t, err)
+
+	_, err = encryption.Open(c, sealed)
+	assert.Error(t, err)
+
+	t.Setenv(encryption.EncryptionKeyEnvKey, "")
+	assert.NoError(t, DeleteEncryptionKey())
+
+	c, err = EncryptionCipher()
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+}
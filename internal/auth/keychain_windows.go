@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	windowsCredTypeGeneric          = 1
+	windowsCredPersistLocalMachine  = 2
+	windowsErrorNotFound            = syscall.Errno(1168)
+	windowsCredentialTargetTemplate = "%s:%s"
+)
+
+var (
+	windowsAdvapi32    = syscall.NewLazyDLL("advapi32.dll")
+	windowsCredReadW   = windowsAdvapi32.NewProc("CredReadW")
+	windowsCredWriteW  = windowsAdvapi32.NewProc("CredWriteW")
+	windowsCredDeleteW = windowsAdvapi32.NewProc("CredDeleteW")
+	windowsCredFree    = windowsAdvapi32.NewProc("CredFree")
+)
+
+// CREDENTIALW of wincred.h
+type windowsCredential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// windowsKeychain uses Windows Credential Manager with generic credentials
+type windowsKeychain struct{}
+
+func newKeychain() CredentialStore {
+	if err := windowsAdvapi32.Load(); err != nil {
+		return nil
+	}
+
+	return &windowsKeychain{}
+}
+
+func (k *windowsKeychain) Get(key string) (string, error) {
+	target, err := windowsCredentialTarget(key)
+	if err != nil {
+		return "", err
+	}
+
+	var cred *windowsCredential
+	r, _, err := windowsCredReadW.Call(uintptr(unsafe.Pointer(target)),
+		windowsCredTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		return "", windowsCredentialError(err)
+	}
+
+	defer windowsCredFree.Call(uintptr(unsafe.Pointer(cred)))
+
+	if cred.CredentialBlobSize == 0 {
+		return "", ErrCredentialNotFound
+	}
+
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+func (k *windowsKeychain) Set(key, secret string) error {
+	target, err := windowsCredentialTarget(key)
+	if err != nil {
+		return err
+	}
+
+	user, err := syscall.UTF16PtrFromString(key)
+	if err != nil {
+		return err
+	}
+
+	blob := []byte(secret)
+	cred := windowsCredential{
+		Type:               windowsCredTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            windowsCredPersistLocalMachine,
+		UserName:           user,
+	}
+
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+
+	r, _, err := windowsCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0)
+	if r == 0 {
+		return windowsCredentialError(err)
+	}
+
+	return nil
+}
+
+func (k *windowsKeychain) Delete(key string) error {
+	target, err := windowsCredentialTarget(key)
+	if err != nil {
+		return err
+	}
+
+	r, _, err := windowsCredDeleteW.Call(uintptr(unsafe.Pointer(target)), windowsCredTypeGeneric, 0)
+	if r == 0 {
+		return windowsCredentialError(err)
+	}
+
+	return nil
+}
+
+func windowsCredentialTarget(key string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(fmt.Sprintf(windowsCredentialTargetTemplate, keychainService, key))
+}
+
+func windowsCredentialError(err error) error {
+	if errors.Is(err, windowsErrorNotFound) {
+		return ErrCredentialNotFound
+	}
+
+	return fmt.Errorf("credential manager call failed: %w", err)
+}
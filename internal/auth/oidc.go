@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cli/oauth/api"
+	"github.com/cli/oauth/device"
+	"github.com/safedep/vet/pkg/common/httpclient"
+	"github.com/safedep/vet/pkg/common/logger"
+)
+
+// Access token is refreshed when it expires within this duration
+const cloudAccessTokenRefreshSkew = 2 * time.Minute
+
+var (
+	ErrNotLoggedIn = errors.New("not logged in, use `vet auth login --device` or `vet cloud login`")
+
+	cloudAccessTokenMutex sync.Mutex
+)
+
+// DeviceCodeLogin authenticates the user with the OAuth device code flow
+// and persists the tokens. onCode is called with the URL the user must
+// visit and the code to enter.
+func DeviceCodeLogin(ctx context.Context, domain string, onCode func(verificationUrl, userCode string)) error {
+	code, err := device.RequestCode(httpclient.Default(),
+		CloudIdentityServiceDeviceCodeUrl(),
+		CloudIdentityServiceClientId(),
+		[]string{"offline_access", "openid", "profile", "email"},
+		device.WithAudience(CloudIdentityServiceAudience()))
+	if err != nil {
+		return fmt.Errorf("failed to request device code: %w", err)
+	}
+
+	onCode(code.VerificationURIComplete, code.UserCode)
+
+	token, err := device.Wait(ctx, httpclient.Default(), CloudIdentityServiceTokenUrl(),
+		device.WaitOptions{
+			ClientID:   CloudIdentityServiceClientId(),
+			DeviceCode: code,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to authenticate: %w", err)
+	}
+
+	return PersistCloudTokens(token.Token, token.RefreshToken, domain)
+}
+
+// ValidCloudAccessToken returns the cloud access token, refreshing it
+// using the refresh token when expired or about to expire
+func ValidCloudAccessToken(ctx context.Context) (string, error) {
+	cloudAccessTokenMutex.Lock()
+	defer cloudAccessTokenMutex.Unlock()
+
+	token := CloudAccessToken()
+	if token == "" {
+		return "", ErrNotLoggedIn
+	}
+
+	expiresAt, ok := cloudAccessTokenExpiry(token)
+	if !ok || time.Until(expiresAt) > cloudAccessTokenRefreshSkew {
+		return token, nil
+	}
+
+	refreshToken := CloudRefreshToken()
+	if refreshToken == "" {
+		if time.Now().After(expiresAt) {
+			return "", fmt.Errorf("cloud access token expired at %s and refresh token is not available: %w",
+				expiresAt.Format(time.RFC3339), ErrNotLoggedIn)
+		}
+
+		return token, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	logger.Debugf("Refreshing cloud access token expiring at: %s", expiresAt.Format(time.RFC3339))
+
+	refreshed, err := requestCloudTokenRefresh(CloudIdentityServiceTokenUrl(), refreshToken)
+	if err != nil {
+		return "", err
+	}
+
+	// Refresh token is not rotated by every identity provider
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = refreshToken
+	}
+
+	if err := PersistCloudTokens(refreshed.Token, refreshed.RefreshToken, ""); err != nil {
+		logger.Warnf("Failed to persist refreshed cloud tokens: %v", err)
+	}
+
+	return refreshed.Token, nil
+}
+
+func requestCloudTokenRefresh(tokenUrl, refreshToken string) (*api.AccessToken, error) {
+	res, err := api.PostForm(httpclient.Default(), tokenUrl, url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {CloudIdentityServiceClientId()},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh cloud access token: %w", err)
+	}
+
+	token, err := res.AccessToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh cloud access token, login again: %w", err)
+	}
+
+	return token, nil
+}
+
+// cloudAccessTokenExpiry reads the expiry of a JWT access token without
+// verifying it. Returns false for opaque tokens.
+func cloudAccessTokenExpiry(token string) (time.Time, bool) {
+	claims, ok := parseTokenClaims(token)
+	if !ok || claims.ExpiresAt == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(claims.ExpiresAt, 0), true
+}
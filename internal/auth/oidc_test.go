@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testJwt(payload string) string {
+	return "eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2ln"
+}
+
+func TestCloudAccessTokenExpiry(t *testing.T) {
+	cases := []struct {
+		name     string
+		token    string
+		expected time.Time
+		ok       bool
+	}{
+		{"jwt with expiry", testJwt(`{"sub":"user","exp":1700000000}`), time.Unix(1700000000, 0), true},
+		{"jwt without expiry", testJwt(`{"sub":"user"}`), time.Time{}, false},
+		{"opaque token", "opaque-token", time.Time{}, false},
+		{"invalid payload", "a.!!!.c", time.Time{}, false},
+		{"payload is not json", "a." + base64.RawURLEncoding.EncodeToString([]byte("text")) + ".c", time.Time{}, false},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			expiresAt, ok := cloudAccessTokenExpiry(test.token)
+			assert.Equal(t, test.ok, ok)
+			assert.True(t, test.expected.Equal(expiresAt))
+		})
+	}
+}
+
+func TestRequestCloudTokenRefresh(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		assert.Equal(t, CloudIdentityServiceClientId(), r.PostForm.Get("client_id"))
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("refresh_token") != "valid" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Unknown or invalid refresh token."}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"access_token":"new-access","token_type":"Bearer","expires_in":86400}`)
+	}))
+	defer server.Close()
+
+	token, err := requestCloudTokenRefresh(server.URL, "valid")
+	assert.NoError(t, err)
+	assert.Equal(t, "new-access", token.Token)
+	assert.Equal(t, "", token.RefreshToken)
+
+	_, err = requestCloudTokenRefresh(server.URL, "revoked")
+	assert.ErrorContains(t, err, "login again")
+}
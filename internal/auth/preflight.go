@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/safedep/vet/pkg/cloud"
+	"github.com/safedep/vet/pkg/common/errcode"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Default duration before expiry of a token to start warning
+const DefaultTokenExpiryWarning = 7 * 24 * time.Hour
+
+// TokenIssue is a problem with the configured ControlTower credentials
+type TokenIssue struct {
+	// Sync can not succeed with a fatal issue
+	Fatal bool
+	Code  errcode.Code
+
+	Message     string
+	Remediation string
+}
+
+func (i TokenIssue) String() string {
+	return fmt.Sprintf("%s. %s", i.Message, i.Remediation)
+}
+
+type PreflightConfig struct {
+	// Warn when the token expires within this duration
+	ExpiryWarning time.Duration
+
+	// Optional, scopes required when the token declares its scopes
+	RequiredScopes []string
+
+	// Verify the token with ControlTower
+	Online bool
+}
+
+// tokenClaims of a JWT used for preflight checks. The token is not verified,
+// ControlTower is the authority on validity of a token.
+type tokenClaims struct {
+	ExpiresAt int64           `json:"exp"`
+	Audience  json.RawMessage `json:"aud"`
+	Scope     string          `json:"scope"`
+}
+
+func (c tokenClaims) audiences() []string {
+	var audience string
+	if err := json.Unmarshal(c.Audience, &audience); err == nil {
+		return []string{audience}
+	}
+
+	var audiences []string
+	_ = json.Unmarshal(c.Audience, &audiences)
+
+	return audiences
+}
+
+// parseTokenClaims returns false for opaque tokens
+func parseTokenClaims(token string) (tokenClaims, bool) {
+	var claims tokenClaims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return claims, false
+	}
+
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, false
+	}
+
+	return claims, true
+}
+
+// Preflight validates the ControlTower credentials used for sync so that
+// a misconfiguration is reported before a scan instead of after it.
+// Returns an error when any issue is fatal.
+func Preflight(config PreflightConfig) ([]TokenIssue, error) {
+	issues := []TokenIssue{}
+
+	token, isApiKey, renewable, issue := preflightToken()
+	if issue != nil {
+		issues = append(issues, *issue)
+		return issues, preflightError(issues)
+	}
+
+	if token == "" {
+		issues = append(issues, TokenIssue{
+			Fatal:       true,
+			Code:        errcode.AuthNotConfigured,
+			Message:     "ControlTower credentials are not configured",
+			Remediation: "Configure an API key using `vet auth configure --tenant <domain>` or login using `vet auth login --device`",
+		})
+
+		return issues, preflightError(issues)
+	}
+
+	if TenantDomain() == "" {
+		issues = append(issues, TokenIssue{
+			Fatal:       true,
+			Code:        errcode.AuthNotConfigured,
+			Message:     "ControlTower tenant is not configured",
+			Remediation: fmt.Sprintf("Set tenant using `vet auth configure --tenant <domain>` or %s", controlTowerTenantEnvKey),
+		})
+	}
+
+	issues = append(issues, tokenClaimIssues(token, isApiKey, renewable, time.Now(), config)...)
+
+	if config.Online && preflightError(issues) == nil {
+		if issue, ok := verifyTokenOnline(token); !ok {
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues, preflightError(issues)
+}
+
+// preflightToken returns the token used for sync in the order of precedence
+// of CloudCredentialProvider. A token of client credentials is obtained
+// from the identity provider so that invalid client credentials are
+// reported before a scan.
+func preflightToken() (token string, isApiKey, renewable bool, issue *TokenIssue) {
+	if key := ApiKey(); key != "" {
+		return key, true, false, nil
+	}
+
+	if config, ok := CloudClientCredentials(); ok {
+		provider, err := NewClientCredentialsProvider(config)
+		if err == nil {
+			token, err = provider.Token(context.Background())
+		}
+
+		if err != nil {
+			return "", false, false, &TokenIssue{
+				Fatal:   true,
+				Code:    errcode.AuthInvalidToken,
+				Message: fmt.Sprintf("Failed to get ControlTower token: %v", err),
+				Remediation: fmt.Sprintf("Check the client credentials configured using `vet auth login --client-id` or %s",
+					cloudClientSecretEnvKey),
+			}
+		}
+
+		// A new token is obtained on expiry, hence its expiry is not an issue
+		return token, false, true, nil
+	}
+
+	// A refresh token renews the access token, hence its expiry is not an issue
+	return CloudAccessToken(), false, CloudRefreshToken() != "", nil
+}
+
+func tokenClaimIssues(token string, isApiKey, renewable bool, now time.Time, config PreflightConfig) []TokenIssue {
+	issues := []TokenIssue{}
+
+	claims, ok := parseTokenClaims(token)
+	if !ok {
+		return issues
+	}
+
+	remediation := "Login again using `vet auth login --device`"
+	if isApiKey {
+		remediation = "Create a new key using `vet cloud key create` and configure it using `vet auth configure`"
+	}
+
+	if claims.ExpiresAt != 0 && !renewable {
+		expiresAt := time.Unix(claims.ExpiresAt, 0)
+		if !now.Before(expiresAt) {
+			issues = append(issues, TokenIssue{
+				Fatal:       true,
+				Code:        errcode.AuthInvalidToken,
+				Message:     fmt.Sprintf("ControlTower token expired at %s", expiresAt.Format(time.RFC3339)),
+				Remediation: remediation,
+			})
+		} else if config.ExpiryWarning > 0 && expiresAt.Sub(now) <= config.ExpiryWarning {
+			issues = append(issues, TokenIssue{
+				Message: fmt.Sprintf("ControlTower token expires in %d days at %s",
+					int(expiresAt.Sub(now).Hours()/24), expiresAt.Format(time.RFC3339)),
+				Remediation: remediation,
+			})
+		}
+	}
+
+	if audiences := claims.audiences(); len(audiences) > 0 && !slices.Contains(audiences, CloudIdentityServiceAudience()) {
+		issues = append(issues, TokenIssue{
+			Fatal:       true,
+			Code:        errcode.AuthInvalidToken,
+			Message:     fmt.Sprintf("ControlTower token is issued for %s", strings.Join(audiences, ", ")),
+			Remediation: remediation,
+		})
+	}
+
+	if claims.Scope != "" {
+		scopes := strings.Fields(claims.Scope)
+		for _, required := range config.RequiredScopes {
+			if !slices.Contains(scopes, required) {
+				issues = append(issues, TokenIssue{
+					Fatal:       true,
+					Code:        errcode.AuthPermissionDenied,
+					Message:     fmt.Sprintf("ControlTower token does not have scope: %s", required),
+					Remediation: remediation,
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+func verifyTokenOnline(token string) (TokenIssue, bool) {
+	conn, err := cloudClientConnection("vet-auth-preflight", SyncApiUrl(), staticCredentials(token))
+	if err != nil {
+		return TokenIssue{
+			Fatal:       true,
+			Code:        errcode.NetworkUnavailable,
+			Message:     fmt.Sprintf("Failed to connect to ControlTower: %v", err),
+			Remediation: fmt.Sprintf("Check the sync URL %s", SyncApiUrl()),
+		}, false
+	}
+
+	defer conn.Close()
+
+	pingService, err := cloud.NewPingService(conn)
+	if err == nil {
+		_, err = pingService.Ping()
+	}
+
+	switch status.Code(err) {
+	case codes.OK:
+		return TokenIssue{}, true
+	case codes.Unauthenticated:
+		return TokenIssue{
+			Fatal:       true,
+			Code:        errcode.AuthInvalidToken,
+			Message:     "ControlTower rejected the token as invalid or expired",
+			Remediation: "Configure a valid API key using `vet auth configure` or login using `vet auth login --device`",
+		}, false
+	case codes.PermissionDenied:
+		return TokenIssue{
+			Fatal:       true,
+			Code:        errcode.AuthPermissionDenied,
+			Message:     fmt.Sprintf("ControlTower token does not have access to tenant %s", TenantDomain()),
+			Remediation: "Use a token of the tenant or set the tenant of the token using --tenant",
+		}, false
+	default:
+		return TokenIssue{
+			Fatal:       true,
+			Code:        errcode.NetworkUnavailable,
+			Message:     fmt.Sprintf("Failed to verify token with ControlTower: %v", err),
+			Remediation: "Check connectivity to ControlTower and retry",
+		}, false
+	}
+}
+
+// preflightError has the code of the first fatal issue
+func preflightError(issues []TokenIssue) error {
+	var errs []error
+	code := errcode.Unknown
+
+	for _, issue := range issues {
+		if !issue.Fatal {
+			continue
+		}
+
+		if len(errs) == 0 {
+			code = issue.Code
+		}
+
+		errs = append(errs, errors.New(issue.String()))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errcode.Errorf(code, "ControlTower credentials preflight failed: %w", errors.Join(errs...))
+}
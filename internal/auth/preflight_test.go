@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenClaimIssues(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	config := PreflightConfig{
+		ExpiryWarning:  DefaultTokenExpiryWarning,
+		RequiredScopes: []string{"vet:sync"},
+	}
+
+	audience := CloudIdentityServiceAudience()
+
+	cases := []struct {
+		name      string
+		token     string
+		renewable bool
+		messages  []string
+		fatal     bool
+	}{
+		{"opaque token", "opaque-token", false, nil, false},
+		{"valid token", testJwt(fmt.Sprintf(`{"exp":%d,"aud":%q,"scope":"vet:sync"}`, now.Unix()+30*86400, audience)), false, nil, false},
+		{"expired token", testJwt(fmt.Sprintf(`{"exp":%d}`, now.Unix()-60)), false, []string{"token expired"}, true},
+		{"expired renewable token", testJwt(fmt.Sprintf(`{"exp":%d}`, now.Unix()-60)), true, nil, false},
+		{"token expiring soon", testJwt(fmt.Sprintf(`{"exp":%d}`, now.Unix()+3*86400)), false, []string{"expires in 3 days"}, false},
+		{"audience array", testJwt(fmt.Sprintf(`{"aud":["other",%q]}`, audience)), false, nil, false},
+		{"wrong audience", testJwt(`{"aud":"https://other.example.com"}`), false, []string{"issued for https://other.example.com"}, true},
+		{"missing scope", testJwt(`{"scope":"vet:read"}`), false, []string{"does not have scope: vet:sync"}, true},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			issues := tokenClaimIssues(test.token, false, test.renewable, now, config)
+			assert.Len(t, issues, len(test.messages))
+
+			for i, message := range test.messages {
+				assert.Contains(t, issues[i].Message, message)
+				assert.Equal(t, test.fatal, issues[i].Fatal)
+			}
+
+			assert.Equal(t, test.fatal, preflightError(issues) != nil)
+		})
+	}
+}
@@ -1,14 +1,72 @@
 package command
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 
 	"github.com/safedep/vet/internal/ui"
+	"github.com/safedep/vet/pkg/common/errcode"
 )
 
+const (
+	ErrorFormatText = "text"
+	ErrorFormatJson = "json"
+
+	errorFormatEnvKey = "VET_ERROR_FORMAT"
+)
+
+var errorFormat = ErrorFormatText
+
+// Failure is the JSON representation of a failure
+type Failure struct {
+	Stage       string       `json:"stage"`
+	Code        errcode.Code `json:"code"`
+	Description string       `json:"description,omitempty"`
+	Message     string       `json:"message"`
+}
+
+// SetErrorFormat sets the format of failures printed by FailOnError.
+// Environment variable takes precedence so that CI can set it globally.
+func SetErrorFormat(format string) error {
+	if env := os.Getenv(errorFormatEnvKey); env != "" {
+		format = env
+	}
+
+	switch format {
+	case "", ErrorFormatText:
+		errorFormat = ErrorFormatText
+	case ErrorFormatJson:
+		errorFormat = ErrorFormatJson
+	default:
+		return fmt.Errorf("invalid error format: %s", format)
+	}
+
+	return nil
+}
+
+// PrintFailure prints the failure with its code in the configured format
+func PrintFailure(stage string, err error) {
+	code := errcode.Of(err)
+
+	if errorFormat == ErrorFormatJson {
+		data, _ := json.Marshal(Failure{
+			Stage:       stage,
+			Code:        code,
+			Description: errcode.Describe(code),
+			Message:     err.Error(),
+		})
+
+		fmt.Fprintln(os.Stderr, string(data))
+		return
+	}
+
+	ui.PrintError("%s failed due to error: [%s] %s", stage, code, err.Error())
+}
+
 func FailOnError(stage string, err error) {
 	if err != nil {
-		ui.PrintError("%s failed due to error: %s", stage, err.Error())
+		PrintFailure(stage, err)
 		os.Exit(-1)
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/gofri/go-github-ratelimit/github_ratelimit"
 	"github.com/google/go-github/v54/github"
 	"github.com/safedep/dry/utils"
+	"github.com/safedep/vet/pkg/common/httpclient"
 	"github.com/safedep/vet/pkg/common/logger"
 	"golang.org/x/oauth2"
 )
@@ -46,7 +47,7 @@ func GetGithubClient() (*github.Client, error) {
 	}
 
 	if utils.IsEmptyString(githubToken) {
-		rateLimitedClient, err := githubRateLimitedClient(http.DefaultTransport)
+		rateLimitedClient, err := githubRateLimitedClient(httpclient.Transport())
 		if err != nil {
 			return nil, err
 		}
@@ -59,7 +60,8 @@ func GetGithubClient() (*github.Client, error) {
 		AccessToken: githubToken,
 	})
 
-	baseClient := oauth2.NewClient(context.Background(), tokenSource)
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpclient.Default())
+	baseClient := oauth2.NewClient(ctx, tokenSource)
 	rateLimitedClient, err := githubRateLimitedClient(baseClient.Transport)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,305 @@
+// Package telemetry reports anonymous usage of vet when enabled by the
+// user. Usage is limited to the command, its duration and outcome and the
+// number of manifests and packages per ecosystem. Package names, paths and
+// any other data of the scanned project are never collected.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/httpclient"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/common/paths"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	homeRelativeConfigPath = ".safedep/vet-telemetry.yml"
+	previewFileName        = "vet-telemetry-preview.json"
+
+	defaultTelemetryUrl = "https://telemetry.safedep.io/v1/events"
+
+	// Disables telemetry irrespective of the configuration
+	telemetryDisabledEnvKey = "VET_TELEMETRY_DISABLED"
+	doNotTrackEnvKey        = "DO_NOT_TRACK"
+
+	telemetryUrlEnvKey = "VET_TELEMETRY_URL"
+
+	sendTimeout = 3 * time.Second
+)
+
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Random identifier of the installation, not derived from the host
+	InstallationId string `yaml:"installation_id"`
+}
+
+type EcosystemUsage struct {
+	Manifests int `json:"manifests"`
+	Packages  int `json:"packages"`
+}
+
+// Event is the payload sent for a command
+type Event struct {
+	InstallationId string                    `json:"installation_id"`
+	Version        string                    `json:"version"`
+	Os             string                    `json:"os"`
+	Arch           string                    `json:"arch"`
+	Command        string                    `json:"command"`
+	DurationMs     int64                     `json:"duration_ms"`
+	Success        bool                      `json:"success"`
+	Ecosystems     map[string]EcosystemUsage `json:"ecosystems,omitempty"`
+}
+
+var (
+	configMutex sync.Mutex
+
+	usageMutex sync.Mutex
+	ecosystems = map[string]EcosystemUsage{}
+)
+
+// Enabled is false unless the user opted in and telemetry is not
+// disabled using environment
+func Enabled() bool {
+	if disabledByEnvironment() {
+		return false
+	}
+
+	config, err := loadConfiguration()
+	if err != nil {
+		return false
+	}
+
+	return config.Enabled
+}
+
+func disabledByEnvironment() bool {
+	for _, key := range []string{telemetryDisabledEnvKey, doNotTrackEnvKey} {
+		if disabled, err := strconv.ParseBool(os.Getenv(key)); err == nil && disabled {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Enable() error {
+	return updateConfig(func(c *Config) {
+		c.Enabled = true
+		if c.InstallationId == "" {
+			c.InstallationId = newInstallationId()
+		}
+	})
+}
+
+// Disable also forgets the installation identifier
+func Disable() error {
+	return updateConfig(func(c *Config) {
+		c.Enabled = false
+		c.InstallationId = ""
+	})
+}
+
+// RecordManifest records the ecosystem of a scanned manifest
+func RecordManifest(ecosystem string, packages int) {
+	usageMutex.Lock()
+	defer usageMutex.Unlock()
+
+	usage := ecosystems[ecosystem]
+	usage.Manifests++
+	usage.Packages += packages
+
+	ecosystems[ecosystem] = usage
+}
+
+// NewEvent builds the event of a command with the usage recorded so far
+func NewEvent(version, command string, duration time.Duration, err error) Event {
+	usageMutex.Lock()
+	defer usageMutex.Unlock()
+
+	event := Event{
+		Version:    version,
+		Os:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Command:    command,
+		DurationMs: duration.Milliseconds(),
+		Success:    err == nil,
+		Ecosystems: map[string]EcosystemUsage{},
+	}
+
+	for ecosystem, usage := range ecosystems {
+		event.Ecosystems[ecosystem] = usage
+	}
+
+	return event
+}
+
+// Report saves the event for preview and sends it when telemetry is
+// enabled. Failure to send is not reported to the user.
+func Report(event Event) {
+	if disabledByEnvironment() {
+		return
+	}
+
+	// Preview is saved even when not enabled so that users can review
+	// the payload before opting in
+	config, _ := loadConfiguration()
+	event.InstallationId = config.InstallationId
+
+	if err := savePreview(event); err != nil {
+		logger.Debugf("Telemetry: Failed to save preview: %v", err)
+	}
+
+	if !config.Enabled {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	if err := send(ctx, telemetryUrl(), event); err != nil {
+		logger.Debugf("Telemetry: Failed to send event: %v", err)
+	}
+}
+
+// Preview returns the last event saved by Report, sent only when
+// telemetry is enabled
+func Preview() ([]byte, error) {
+	path, err := paths.Resolve(paths.KindState, previewFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(path)
+}
+
+func send(ctx context.Context, url string, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := httpclient.Default().Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+func telemetryUrl() string {
+	if url := os.Getenv(telemetryUrlEnvKey); url != "" {
+		return url
+	}
+
+	return defaultTelemetryUrl
+}
+
+func savePreview(event Event) error {
+	data, err := json.MarshalIndent(event, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path, err := paths.Resolve(paths.KindState, previewFileName)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+func newInstallationId() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+func updateConfig(fn func(*Config)) error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	config, err := loadConfiguration()
+	if err != nil {
+		config = Config{}
+	}
+
+	fn(&config)
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("config serialization failed: %w", err)
+	}
+
+	path, err := homeRelativePath(homeRelativeConfigPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+func loadConfiguration() (Config, error) {
+	var config Config
+
+	path, err := homeRelativePath(homeRelativeConfigPath)
+	if err != nil {
+		return config, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return config, fmt.Errorf("config deserialization failed: %w", err)
+	}
+
+	return config, nil
+}
+
+func homeRelativePath(relative string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, relative), nil
+}
@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetTestTelemetry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(telemetryDisabledEnvKey, "")
+	t.Setenv(doNotTrackEnvKey, "")
+
+	usageMutex.Lock()
+	ecosystems = map[string]EcosystemUsage{}
+	usageMutex.Unlock()
+}
+
+func TestNewEvent(t *testing.T) {
+	resetTestTelemetry(t)
+
+	RecordManifest("npm", 10)
+	RecordManifest("npm", 5)
+	RecordManifest("PyPI", 3)
+
+	event := NewEvent("1.9.0", "vet scan", 1500*time.Millisecond, errors.New("failed"))
+	assert.Equal(t, "vet scan", event.Command)
+	assert.Equal(t, int64(1500), event.DurationMs)
+	assert.False(t, event.Success)
+	assert.Equal(t, map[string]EcosystemUsage{
+		"npm":  {Manifests: 2, Packages: 15},
+		"PyPI": {Manifests: 1, Packages: 3},
+	}, event.Ecosystems)
+}
+
+func TestEnableDisable(t *testing.T) {
+	resetTestTelemetry(t)
+
+	assert.False(t, Enabled())
+
+	require.NoError(t, Enable())
+	assert.True(t, Enabled())
+
+	config, err := loadConfiguration()
+	require.NoError(t, err)
+	assert.Len(t, config.InstallationId, 32)
+
+	t.Setenv(doNotTrackEnvKey, "1")
+	assert.False(t, Enabled())
+
+	t.Setenv(doNotTrackEnvKey, "")
+	require.NoError(t, Disable())
+	assert.False(t, Enabled())
+
+	config, err = loadConfiguration()
+	require.NoError(t, err)
+	assert.Empty(t, config.InstallationId)
+}
+
+func TestReport(t *testing.T) {
+	resetTestTelemetry(t)
+
+	received := []Event{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+
+		received = append(received, event)
+	}))
+	defer server.Close()
+
+	t.Setenv(telemetryUrlEnvKey, server.URL)
+
+	// Preview is available without sending when not enabled
+	Report(NewEvent("1.9.0", "vet scan", time.Second, nil))
+	assert.Empty(t, received)
+
+	data, err := Preview()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"command": "vet scan"`)
+
+	require.NoError(t, Enable())
+	Report(NewEvent("1.9.0", "vet query", time.Second, nil))
+
+	require.Len(t, received, 1)
+	assert.Equal(t, "vet query", received[0].Command)
+	assert.NotEmpty(t, received[0].InstallationId)
+
+	t.Setenv(telemetryDisabledEnvKey, "true")
+	Report(NewEvent("1.9.0", "vet scan", time.Second, nil))
+	assert.Len(t, received, 1)
+}
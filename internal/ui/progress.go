@@ -66,6 +66,12 @@ func IncrementTrackerTotal(i any, count int64) {
 	}
 }
 
+func UpdateTrackerTotal(i any, total int64) {
+	if tracker, ok := i.(*progress.Tracker); ok {
+		tracker.UpdateTotal(total)
+	}
+}
+
 func IncrementProgress(i any, count int64) {
 	if tracker, ok := i.(*progress.Tracker); ok && (progressTrackerDelta(tracker) > count) {
 		tracker.Increment(count)
@@ -5,13 +5,18 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/safedep/dry/utils"
 	"github.com/safedep/vet/cmd/cloud"
 	"github.com/safedep/vet/cmd/code"
 	"github.com/safedep/vet/cmd/inspect"
+	"github.com/safedep/vet/internal/command"
+	"github.com/safedep/vet/internal/telemetry"
 	"github.com/safedep/vet/internal/ui"
 	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/common/paths"
+	"github.com/safedep/vet/pkg/common/resources"
 	"github.com/safedep/vet/pkg/exceptions"
 	"github.com/spf13/cobra"
 )
@@ -21,8 +26,17 @@ var (
 	debug                 bool
 	noBanner              bool
 	logFile               string
+	errorFormat           string
 	globalExceptionsFile  string
 	globalExceptionsExtra []string
+	dataDir               string
+	cacheDir              string
+	historyDir            string
+	stateless             bool
+	maxDownloadSize       string
+	maxExtractedSize      string
+	maxTempDiskSize       string
+	memoryLimit           string
 )
 
 var banner string = `
@@ -51,14 +65,36 @@ func main() {
 	cmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "Show debug logs")
 	cmd.PersistentFlags().BoolVarP(&noBanner, "no-banner", "", false, "Do not display the vet banner")
 	cmd.PersistentFlags().StringVarP(&logFile, "log", "l", "", "Write command logs to file, use - as for stdout")
+	cmd.PersistentFlags().StringVarP(&errorFormat, "error-format", "", command.ErrorFormatText,
+		"Format of failures with error code (text, json)")
 	cmd.PersistentFlags().StringVarP(&globalExceptionsFile, "exceptions", "e", "", "Load exceptions from file")
 	cmd.PersistentFlags().StringSliceVarP(&globalExceptionsExtra, "exceptions-extra", "", []string{}, "Load additional exceptions from file")
+	cmd.PersistentFlags().StringVarP(&dataDir, "data-dir", "", "",
+		"Directory of files written by vet (default ~/.safedep, env VET_DATA_DIR)")
+	cmd.PersistentFlags().StringVarP(&cacheDir, "cache-dir", "", "",
+		"Directory of analyzer and insights cache (default data directory, env VET_CACHE_DIR)")
+	cmd.PersistentFlags().StringVarP(&historyDir, "history-dir", "", "",
+		"Directory of manifest history (default data directory, env VET_HISTORY_DIR)")
+	cmd.PersistentFlags().BoolVarP(&stateless, "stateless", "", false,
+		"Do not write to disk e.g. for read-only container file systems (env VET_STATELESS)")
+	cmd.PersistentFlags().StringVarP(&maxDownloadSize, "max-download-size", "", "",
+		"Max size of a downloaded package artifact e.g. 512MiB (default 1GiB)")
+	cmd.PersistentFlags().StringVarP(&maxExtractedSize, "max-extracted-size", "", "",
+		"Max size of a package artifact after extraction (default 4GiB)")
+	cmd.PersistentFlags().StringVarP(&maxTempDiskSize, "max-temp-disk", "", "",
+		"Max size of temporary files in use at a time (default 8GiB)")
+	cmd.PersistentFlags().StringVarP(&memoryLimit, "memory-limit", "", "",
+		"Soft memory limit of the process e.g. 2GiB, GOMEMLIMIT takes precedence")
 
 	cmd.AddCommand(newAuthCommand())
 	cmd.AddCommand(newScanCommand())
 	cmd.AddCommand(newQueryCommand())
 	cmd.AddCommand(newVersionCommand())
+	cmd.AddCommand(newUpdateCommand())
 	cmd.AddCommand(newConnectCommand())
+	cmd.AddCommand(newFeedbackCommand())
+	cmd.AddCommand(newTriageCommand())
+	cmd.AddCommand(newTelemetryCommand())
 	cmd.AddCommand(cloud.NewCloudCommand())
 	cmd.AddCommand(code.NewCodeCommand())
 
@@ -70,9 +106,42 @@ func main() {
 		printBanner()
 		loadExceptions()
 		logger.SetLogLevel(verbose, debug)
+
+		if err := command.SetErrorFormat(errorFormat); err != nil {
+			ui.PrintError("%s", err)
+			os.Exit(1)
+		}
+
+		if err := configurePaths(); err != nil {
+			ui.PrintError("%s", err)
+			os.Exit(1)
+		}
+
+		if err := configureResources(); err != nil {
+			ui.PrintError("%s", err)
+			os.Exit(1)
+		}
 	})
 
-	if err := cmd.Execute(); err != nil {
+	// Failures are printed with their error code
+	cmd.SilenceErrors = true
+
+	startedAt := time.Now()
+	executed, err := cmd.ExecuteC()
+
+	// Telemetry commands are not reported so that preview shows the
+	// data of the previous command
+	if executed != nil && !isTelemetryCommand(executed) {
+		telemetry.Report(telemetry.NewEvent(version, executed.CommandPath(), time.Since(startedAt), err))
+	}
+
+	if err != nil {
+		stage := "vet"
+		if executed != nil {
+			stage = executed.Name()
+		}
+
+		command.PrintFailure(stage, err)
 		os.Exit(1)
 	}
 }
@@ -101,6 +170,63 @@ func loadExceptionsFromFile(file string) {
 	}
 }
 
+// Flags override the paths configured in environment
+func configurePaths() error {
+	config, err := paths.ConfigFromEnvironment()
+	if err != nil {
+		return err
+	}
+
+	if dataDir != "" {
+		config.DataDir = dataDir
+	}
+
+	if cacheDir != "" {
+		config.Dirs[paths.KindCache] = cacheDir
+	}
+
+	if historyDir != "" {
+		config.Dirs[paths.KindHistory] = historyDir
+	}
+
+	if stateless {
+		config.Stateless = true
+	}
+
+	paths.Configure(config)
+	return nil
+}
+
+// Resource limits guard against untrusted package contents
+// exhausting the CI runner
+func configureResources() error {
+	limits := resources.Limits{}
+
+	sizes := map[string]struct {
+		value  string
+		target *int64
+	}{
+		"max-download-size":  {maxDownloadSize, &limits.MaxDownloadSize},
+		"max-extracted-size": {maxExtractedSize, &limits.MaxExtractedSize},
+		"max-temp-disk":      {maxTempDiskSize, &limits.MaxTempDiskSize},
+		"memory-limit":       {memoryLimit, &limits.MemoryLimit},
+	}
+
+	for flag, size := range sizes {
+		value, err := resources.ParseSize(size.value)
+		if err != nil {
+			return fmt.Errorf("invalid value of --%s: %w", flag, err)
+		}
+
+		*size.target = value
+	}
+
+	resources.Configure(limits)
+	resources.ApplyMemoryLimit(limits.MemoryLimit)
+
+	return nil
+}
+
 func printBanner() {
 	if noBanner {
 		return
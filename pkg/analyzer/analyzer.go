@@ -14,6 +14,10 @@ const (
 
 	// Following event types must set the Threat field
 	ET_LockfilePoisoningSignal = AnalyzerEventType("ev_lockfile_poisoning")
+
+	// Following event types must set the Finding field
+	ET_LockfileDriftSignal      = AnalyzerEventType("ev_lockfile_drift")
+	ET_NamespaceOwnershipSignal = AnalyzerEventType("ev_namespace_ownership")
 )
 
 type AnalyzerEvent struct {
@@ -29,6 +33,9 @@ type AnalyzerEvent struct {
 	Threat  *jsonreportspec.ReportThreat
 	Err     error
 
+	// Structured finding, use GetFinding() to read
+	Finding *Finding
+
 	// Entities on which event was generated
 	Manifest *models.PackageManifest
 	Package  *models.Package
@@ -0,0 +1,269 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/safedep/vet/gen/checks"
+	"github.com/safedep/vet/gen/filtersuite"
+	"github.com/safedep/vet/pkg/analyzer/filter"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/policy"
+	"github.com/safedep/vet/pkg/readers"
+	"gopkg.in/yaml.v2"
+)
+
+const attackPatternAnalyzerName = "AttackPatternAnalyzer"
+
+// AttackPatternSignal is a single weak signal expressed as CEL over the
+// same input available to filters e.g. `health.factors.maintenance < 2.0`.
+// Signals are limited to the enriched package data, hence signals such as
+// the publish time of a version or a change of maintainers cannot be
+// expressed until they are part of the filter input.
+type AttackPatternSignal struct {
+	Name       string `yaml:"name"`
+	Expression string `yaml:"expression"`
+}
+
+// AttackPatternRule is a composite of weak signals which together
+// indicate a supply chain attack pattern with high confidence
+type AttackPatternRule struct {
+	Name     string          `yaml:"name"`
+	Summary  string          `yaml:"summary"`
+	Severity FindingSeverity `yaml:"severity"`
+
+	// Min number of signals required to match, all signals by default
+	MinSignals int `yaml:"min_signals"`
+
+	Signals []AttackPatternSignal `yaml:"signals"`
+}
+
+// AttackPatternRuleSet is the declarative definition of attack patterns
+// loaded from YAML
+type AttackPatternRuleSet struct {
+	Name  string              `yaml:"name"`
+	Rules []AttackPatternRule `yaml:"rules"`
+}
+
+// attackPatternSignalRef maps a compiled signal back to its rule
+type attackPatternSignalRef struct {
+	rule   int
+	signal AttackPatternSignal
+}
+
+type AttackPatternAnalyzerConfig struct {
+	RuleSet AttackPatternRuleSet
+
+	// Fail the scan when a rule matches
+	FailOnMatch bool
+
+	// Optional, log signal evaluations as policy decisions
+	DecisionLogger policy.DecisionLogger
+}
+
+type attackPatternAnalyzer struct {
+	config AttackPatternAnalyzerConfig
+	rules  []AttackPatternRule
+
+	// Signals of all rules are compiled into a single evaluator so that
+	// the input of a package is built once for all signals
+	evaluator filter.Evaluator
+	signals   map[*filtersuite.Filter]attackPatternSignalRef
+
+	matches int
+}
+
+// LoadAttackPatternRuleSetFromFile loads the rule set from a YAML file
+func LoadAttackPatternRuleSetFromFile(path string) (AttackPatternRuleSet, error) {
+	var ruleSet AttackPatternRuleSet
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ruleSet, fmt.Errorf("failed to read attack pattern rules: %w", err)
+	}
+
+	err = yaml.Unmarshal(data, &ruleSet)
+	if err != nil {
+		return ruleSet, fmt.Errorf("failed to parse attack pattern rules: %w", err)
+	}
+
+	return ruleSet, nil
+}
+
+// NewAttackPatternAnalyzer creates an analyzer that evaluates composite
+// attack pattern rules over the enriched package data. A rule matches when
+// at least `min_signals` of its signals match.
+func NewAttackPatternAnalyzer(config AttackPatternAnalyzerConfig) (Analyzer, error) {
+	if len(config.RuleSet.Rules) == 0 {
+		return nil, errors.New("at least one attack pattern rule is required")
+	}
+
+	evaluator, err := filter.NewEvaluatorWithDecisionLogger(attackPatternAnalyzerName,
+		true, config.DecisionLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []AttackPatternRule{}
+	signals := map[*filtersuite.Filter]attackPatternSignalRef{}
+	for _, rule := range config.RuleSet.Rules {
+		if rule.Name == "" || len(rule.Signals) == 0 {
+			return nil, fmt.Errorf("attack pattern rule must have a name and signals: %q", rule.Name)
+		}
+
+		if rule.MinSignals <= 0 || rule.MinSignals > len(rule.Signals) {
+			rule.MinSignals = len(rule.Signals)
+		}
+
+		switch rule.Severity {
+		case "":
+			rule.Severity = FindingSeverityHigh
+		case FindingSeverityCritical, FindingSeverityHigh, FindingSeverityMedium,
+			FindingSeverityLow, FindingSeverityInfo:
+		default:
+			return nil, fmt.Errorf("invalid severity %q in rule %s", rule.Severity, rule.Name)
+		}
+
+		for _, signal := range rule.Signals {
+			f := &filtersuite.Filter{
+				Name:  rule.Name + "/" + signal.Name,
+				Value: signal.Expression,
+			}
+
+			err = evaluator.AddFilter(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid signal %s in rule %s: %w", signal.Name, rule.Name, err)
+			}
+
+			signals[f] = attackPatternSignalRef{rule: len(rules), signal: signal}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return &attackPatternAnalyzer{
+		config:    config,
+		rules:     rules,
+		evaluator: evaluator,
+		signals:   signals,
+	}, nil
+}
+
+func (a *attackPatternAnalyzer) Name() string {
+	return attackPatternAnalyzerName
+}
+
+func (a *attackPatternAnalyzer) Analyze(manifest *models.PackageManifest,
+	handler AnalyzerEventHandler) error {
+	matched := false
+	err := readers.NewManifestModelReader(manifest).EnumPackages(func(pkg *models.Package) error {
+		ruleSignals, err := a.matchedSignals(pkg)
+		if err != nil {
+			logger.Debugf("AttackPatternAnalyzer: Failed to evaluate signals of %s: %v",
+				pkg.GetName(), err)
+			return nil
+		}
+
+		for i, rule := range a.rules {
+			signals := ruleSignals[i]
+			if len(signals) < rule.MinSignals {
+				continue
+			}
+
+			matched = true
+			a.matches++
+
+			a.handleMatch(manifest, pkg, rule, signals, handler)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if a.config.FailOnMatch && matched {
+		return handler(&AnalyzerEvent{
+			Source:   a.Name(),
+			Type:     ET_AnalyzerFailOnError,
+			Manifest: manifest,
+			Err:      fmt.Errorf("failed due to attack pattern match on %s", manifest.GetDisplayPath()),
+		})
+	}
+
+	return nil
+}
+
+func (a *attackPatternAnalyzer) Finish() error {
+	if a.matches > 0 {
+		logger.Infof("AttackPatternAnalyzer: Found %d attack pattern matches", a.matches)
+	}
+
+	return nil
+}
+
+func (a *attackPatternAnalyzer) handleMatch(manifest *models.PackageManifest, pkg *models.Package,
+	rule AttackPatternRule, signals []string, handler AnalyzerEventHandler) {
+	msg := fmt.Sprintf("Package %s/%s matched attack pattern %s (%s)",
+		pkg.GetName(), pkg.GetVersion(), rule.Name, strings.Join(signals, ", "))
+
+	evidences := []FindingEvidence{}
+	for _, signal := range signals {
+		evidences = append(evidences, FindingEvidence{Type: "signal", Summary: signal})
+	}
+
+	summary := rule.Summary
+	if summary == "" {
+		summary = msg
+	}
+
+	err := handler(&AnalyzerEvent{
+		Source:   a.Name(),
+		Type:     ET_FilterExpressionMatched,
+		Manifest: manifest,
+		Package:  pkg,
+		Message:  msg,
+		Filter: &filtersuite.Filter{
+			Name:      rule.Name,
+			CheckType: checks.CheckType_CheckTypeOther,
+			Summary:   summary,
+			Tags:      []string{"attack-pattern"},
+		},
+		Finding: &Finding{
+			Kind:       FindingKindThreat,
+			Severity:   rule.Severity,
+			Confidence: FindingConfidenceHigh,
+			Title:      summary,
+			Manifest:   manifest,
+			Package:    pkg,
+			Evidences:  evidences,
+		},
+	})
+	if err != nil {
+		logger.Warnf("AttackPatternAnalyzer: Failed to handle event: %v", err)
+	}
+}
+
+// matchedSignals returns the names of the matched signals indexed by rule.
+// Signals that fail to evaluate are treated as not matched.
+func (a *attackPatternAnalyzer) matchedSignals(pkg *models.Package) ([][]string, error) {
+	filters, err := a.evaluator.EvalPackageAll(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([][]string, len(a.rules))
+	for _, f := range filters {
+		ref, ok := a.signals[f]
+		if !ok {
+			continue
+		}
+
+		matched[ref.rule] = append(matched[ref.rule], ref.signal.Name)
+	}
+
+	return matched, nil
+}
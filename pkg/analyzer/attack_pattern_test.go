@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/safedep/vet/gen/insightapi"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttackPatternAnalyzer(t *testing.T) {
+	rules := `
+name: test
+rules:
+  - name: unpopular-floating-vulnerable
+    summary: Unpopular package admitting vulnerable versions
+    severity: critical
+    signals:
+      - name: unpopular
+        expression: 'size(projects) > 0 && projects.all(p, p.stars < 10)'
+      - name: admits-vulnerable
+        expression: 'version_range.admits_vulnerable'
+  - name: any-of-two
+    min_signals: 1
+    signals:
+      - name: never
+        expression: 'false'
+      - name: npm
+        expression: 'pkg.ecosystem == "npm"'
+`
+
+	path := filepath.Join(t.TempDir(), "rules.yml")
+	assert.Nil(t, os.WriteFile(path, []byte(rules), 0600))
+
+	ruleSet, err := LoadAttackPatternRuleSetFromFile(path)
+	assert.Nil(t, err)
+	assert.Len(t, ruleSet.Rules, 2)
+
+	a, err := NewAttackPatternAnalyzer(AttackPatternAnalyzerConfig{RuleSet: ruleSet, FailOnMatch: true})
+	assert.Nil(t, err)
+
+	vulnId := "GHSA-test"
+	stars := 5
+	manifest := models.NewPackageManifestFromLocal("package.json", models.EcosystemNpm)
+	manifest.AddPackage(&models.Package{
+		PackageDetails:    models.NewPackageDetail(models.EcosystemNpm, "test", "1.0.0"),
+		VersionConstraint: "^1.0.0",
+		Insights: &insightapi.PackageVersionInsight{
+			Projects:        &[]insightapi.PackageProjectInfo{{Stars: &stars}},
+			Vulnerabilities: &[]insightapi.PackageVulnerability{{Id: &vulnId}},
+		},
+	})
+
+	// Unpopular signal must not match without project data
+	manifest.AddPackage(&models.Package{
+		PackageDetails:    models.NewPackageDetail(models.EcosystemNpm, "no-projects", "1.0.0"),
+		VersionConstraint: "^1.0.0",
+		Insights: &insightapi.PackageVersionInsight{
+			Vulnerabilities: &[]insightapi.PackageVulnerability{{Id: &vulnId}},
+		},
+	})
+
+	events := []*AnalyzerEvent{}
+	err = a.Analyze(manifest, func(event *AnalyzerEvent) error {
+		events = append(events, event)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, events, 4)
+
+	matches := map[string][]string{}
+	for _, event := range events[:3] {
+		matches[event.Package.GetName()] = append(matches[event.Package.GetName()], event.Filter.GetName())
+
+		switch event.Filter.GetName() {
+		case "unpopular-floating-vulnerable":
+			assert.Equal(t, FindingSeverityCritical, event.Finding.Severity)
+			assert.Equal(t, []FindingEvidence{
+				{Type: "signal", Summary: "unpopular"},
+				{Type: "signal", Summary: "admits-vulnerable"},
+			}, event.Finding.Evidences)
+		case "any-of-two":
+			assert.Equal(t, FindingSeverityHigh, event.Finding.Severity)
+			assert.Equal(t, []FindingEvidence{{Type: "signal", Summary: "npm"}}, event.Finding.Evidences)
+		}
+	}
+
+	assert.Equal(t, map[string][]string{
+		"test":        {"unpopular-floating-vulnerable", "any-of-two"},
+		"no-projects": {"any-of-two"},
+	}, matches)
+
+	assert.True(t, events[3].IsFailOnError())
+}
+
+func TestNewAttackPatternAnalyzerInvalidRule(t *testing.T) {
+	_, err := NewAttackPatternAnalyzer(AttackPatternAnalyzerConfig{
+		RuleSet: AttackPatternRuleSet{
+			Rules: []AttackPatternRule{
+				{Name: "invalid", Signals: []AttackPatternSignal{{Name: "bad", Expression: "pkg.("}}},
+			},
+		},
+	})
+
+	assert.ErrorContains(t, err, "invalid signal bad in rule invalid")
+}
+
+func TestNewAttackPatternAnalyzerInvalidSeverity(t *testing.T) {
+	_, err := NewAttackPatternAnalyzer(AttackPatternAnalyzerConfig{
+		RuleSet: AttackPatternRuleSet{
+			Rules: []AttackPatternRule{
+				{
+					Name:     "invalid",
+					Severity: FindingSeverity("severe"),
+					Signals:  []AttackPatternSignal{{Name: "npm", Expression: `pkg.ecosystem == "npm"`}},
+				},
+			},
+		},
+	})
+
+	assert.ErrorContains(t, err, `invalid severity "severe" in rule invalid`)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/safedep/vet/pkg/analyzer/filter"
 	"github.com/safedep/vet/pkg/common/logger"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/policy"
 	"github.com/safedep/vet/pkg/readers"
 )
 
@@ -21,8 +22,11 @@ type celFilterAnalyzer struct {
 	stat     celFilterStat
 }
 
-func NewCelFilterAnalyzer(fl string, failOnMatch bool) (Analyzer, error) {
-	evaluator, err := filter.NewEvaluator("single-filter", true)
+// NewCelFilterAnalyzer creates an analyzer for a single filter. Evaluations
+// are logged as policy decisions when decisionLogger is not nil.
+func NewCelFilterAnalyzer(fl string, failOnMatch bool,
+	decisionLogger policy.DecisionLogger) (Analyzer, error) {
+	evaluator, err := filter.NewEvaluatorWithDecisionLogger("single-filter", true, decisionLogger)
 	if err != nil {
 		return nil, err
 	}
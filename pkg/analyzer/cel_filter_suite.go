@@ -10,6 +10,7 @@ import (
 	"github.com/safedep/vet/pkg/analyzer/filter"
 	"github.com/safedep/vet/pkg/common/logger"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/policy"
 	"github.com/safedep/vet/pkg/readers"
 )
 
@@ -26,13 +27,16 @@ type celFilterSuiteAnalyzer struct {
 	stat            celFilterStat
 }
 
-func NewCelFilterSuiteAnalyzer(path string, failOnMatch bool) (Analyzer, error) {
-	fs, err := loadFilterSuiteFromFile(path)
+// NewCelFilterSuiteAnalyzer creates an analyzer for a filter suite. Evaluations
+// are logged as policy decisions when decisionLogger is not nil.
+func NewCelFilterSuiteAnalyzer(path string, failOnMatch bool,
+	decisionLogger policy.DecisionLogger) (Analyzer, error) {
+	fs, err := LoadFilterSuiteFromFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	evaluator, err := filter.NewEvaluator(fs.GetName(), true)
+	evaluator, err := filter.NewEvaluatorWithDecisionLogger(fs.GetName(), true, decisionLogger)
 	if err != nil {
 		return nil, err
 	}
@@ -155,7 +159,7 @@ func (f *celFilterSuiteAnalyzer) handleMatchedPkg(pkg *models.Package,
 // protobuf SDK and not generic JSON / YAML decoder. Since there is no
 // officially supported yamlpb, equivalent to jsonpb, we convert YAML
 // to JSON before unmarshalling it into a protobuf message
-func loadFilterSuiteFromFile(path string) (*filtersuite.FilterSuite, error) {
+func LoadFilterSuiteFromFile(path string) (*filtersuite.FilterSuite, error) {
 	logger.Debugf("CEL Filter Suite: Loading suite from file: %s", path)
 
 	file, err := os.Open(path)
@@ -61,7 +61,7 @@ func TestLoadFilterSuiteFromFile(t *testing.T) {
 
 	for _, test := range cases {
 		t.Run(test.name, func(t *testing.T) {
-			fs, err := loadFilterSuiteFromFile(test.path)
+			fs, err := LoadFilterSuiteFromFile(test.path)
 			if test.errMsg != "" {
 				assert.NotNil(t, err)
 				assert.ErrorContains(t, err, test.errMsg)
@@ -112,7 +112,7 @@ func TestFilterSuiteFilterParams(t *testing.T) {
 
 	for _, test := range cases {
 		t.Run(test.name, func(t *testing.T) {
-			fs, err := loadFilterSuiteFromFile(test.file)
+			fs, err := LoadFilterSuiteFromFile(test.file)
 			assert.Nil(t, err)
 
 			filter := fs.Filters[test.filterIdx]
@@ -19,6 +19,14 @@ func (ev *AnalyzerEvent) IsLockfilePoisoningSignal() bool {
 	return ev.Type == ET_LockfilePoisoningSignal
 }
 
+func (ev *AnalyzerEvent) IsLockfileDriftSignal() bool {
+	return ev.Type == ET_LockfileDriftSignal
+}
+
+func (ev *AnalyzerEvent) IsNamespaceOwnershipSignal() bool {
+	return ev.Type == ET_NamespaceOwnershipSignal
+}
+
 func ThreatInstanceId(id jsonreportspec.ReportThreat_ReportThreatId,
 	st jsonreportspec.ReportThreat_SubjectType,
 	s string,
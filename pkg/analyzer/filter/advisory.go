@@ -0,0 +1,91 @@
+package filter
+
+import (
+	"time"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	"github.com/safedep/dry/semver"
+	"github.com/safedep/vet/pkg/models"
+)
+
+// buildAdvisoryInput exposes the age of advisories and their fixes so that
+// policies can enforce grace periods e.g. `advisory.fix_days > 30`. Dates
+// are available only with Insights v2.
+//
+// Insights does not publish fixed versions of an advisory, so the fix date
+// is approximated as the earliest version ahead of the package version
+// published on or after the advisory.
+func (f *filterEvaluator) buildAdvisoryInput(pkg *models.Package) map[string]interface{} {
+	insights := pkg.InsightsV2
+	now := f.now()
+
+	publishedDays, fixDays := int64(0), int64(0)
+	fixAvailable := false
+	vulns := []interface{}{}
+
+	for _, vuln := range insights.GetVulnerabilities() {
+		if vuln.GetPublishedAt() == nil {
+			continue
+		}
+
+		publishedAt := vuln.GetPublishedAt().AsTime()
+		fixedAt, fixed := advisoryFixedAt(pkg.GetVersion(), publishedAt,
+			insights.GetAvailableVersions())
+
+		vulnPublishedDays := advisoryDaysSince(now, publishedAt)
+		vulnFixDays := int64(0)
+		if fixed {
+			vulnFixDays = advisoryDaysSince(now, fixedAt)
+		}
+
+		publishedDays = max(publishedDays, vulnPublishedDays)
+		fixDays = max(fixDays, vulnFixDays)
+		fixAvailable = fixAvailable || fixed
+
+		vulns = append(vulns, map[string]interface{}{
+			"id":             vuln.GetId().GetValue(),
+			"published_days": vulnPublishedDays,
+			"fix_available":  fixed,
+			"fix_days":       vulnFixDays,
+		})
+	}
+
+	return map[string]interface{}{
+		"published_days": publishedDays,
+		"fix_available":  fixAvailable,
+		"fix_days":       fixDays,
+		"vulns":          vulns,
+	}
+}
+
+func advisoryFixedAt(version string, publishedAt time.Time,
+	available []*packagev1.PackageAvailableVersion) (time.Time, bool) {
+	var fixedAt time.Time
+	fixed := false
+
+	for _, v := range available {
+		if v.GetPublishedAt() == nil || !semver.IsAhead(version, v.GetVersion()) {
+			continue
+		}
+
+		at := v.GetPublishedAt().AsTime()
+		if at.Before(publishedAt) {
+			continue
+		}
+
+		if !fixed || at.Before(fixedAt) {
+			fixedAt = at
+			fixed = true
+		}
+	}
+
+	return fixedAt, fixed
+}
+
+func advisoryDaysSince(now, t time.Time) int64 {
+	if t.After(now) {
+		return 0
+	}
+
+	return int64(now.Sub(t) / (24 * time.Hour))
+}
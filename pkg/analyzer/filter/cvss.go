@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/vet/gen/insightapi"
+	"github.com/safedep/vet/pkg/cvss"
+	"github.com/safedep/vet/pkg/models"
+)
+
+// Environment of the project used to compute adjusted CVSS scores for all
+// evaluators. Must be set before evaluation starts.
+var cvssEnvironment cvss.Environment
+
+// SetCvssEnvironment sets the environment of the project used to compute
+// adjusted CVSS scores
+func SetCvssEnvironment(env cvss.Environment) {
+	cvssEnvironment = env
+}
+
+// buildCvssInput exposes the base and adjusted CVSS v3 scores so that
+// policies can gate on either e.g. `cvss.adjusted_score >= 7.0`. Scores
+// of the package are the max of its vulnerabilities.
+func (f *filterEvaluator) buildCvssInput(pkg *models.Package) map[string]interface{} {
+	insights := utils.SafelyGetValue(pkg.Insights)
+
+	baseScore, adjustedScore := 0.0, 0.0
+	vulns := []interface{}{}
+
+	for _, vuln := range utils.SafelyGetValue(insights.Vulnerabilities) {
+		vector := ""
+		for _, s := range utils.SafelyGetValue(vuln.Severities) {
+			if utils.SafelyGetValue(s.Type) == insightapi.PackageVulnerabilitySeveritiesTypeCVSSV3 {
+				vector = utils.SafelyGetValue(s.Score)
+				break
+			}
+		}
+
+		if vector == "" {
+			continue
+		}
+
+		score, err := cvss.Compute(vector, cvssEnvironment)
+		if err != nil {
+			continue
+		}
+
+		baseScore = max(baseScore, score.Base)
+		adjustedScore = max(adjustedScore, score.Adjusted)
+
+		vulns = append(vulns, map[string]interface{}{
+			"id":             utils.SafelyGetValue(vuln.Id),
+			"base_score":     score.Base,
+			"adjusted_score": score.Adjusted,
+		})
+	}
+
+	return map[string]interface{}{
+		"base_score":     baseScore,
+		"adjusted_score": adjustedScore,
+		"vulns":          vulns,
+	}
+}
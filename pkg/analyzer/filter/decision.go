@@ -0,0 +1,57 @@
+package filter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/policy"
+)
+
+// The digest is stable for the same input because JSON
+// serialization of a map is sorted by key
+func decisionInputDigest(input map[string]interface{}) string {
+	data, err := json.Marshal(input)
+	if err != nil {
+		logger.Warnf("Failed to serialize filter input for digest: %v", err)
+		return ""
+	}
+
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:])
+}
+
+func (f *filterEvaluator) logDecision(pkg *models.Package, prog *filterProgram,
+	inputDigest string, start time.Time, matched bool, err error) {
+	if f.decisionLogger == nil {
+		return
+	}
+
+	decision := &policy.Decision{
+		Time:        start,
+		Policy:      f.name,
+		Rule:        prog.Name(),
+		Expression:  prog.GetFilter().GetValue(),
+		Ecosystem:   string(pkg.Ecosystem),
+		Package:     pkg.GetName(),
+		Version:     pkg.GetVersion(),
+		InputDigest: inputDigest,
+		Outcome:     policy.DecisionOutcomeNotMatched,
+		Duration:    time.Since(start),
+	}
+
+	switch {
+	case err != nil:
+		decision.Outcome = policy.DecisionOutcomeError
+		decision.Error = err.Error()
+	case matched:
+		decision.Outcome = policy.DecisionOutcomeMatched
+	}
+
+	if err := f.decisionLogger.Log(decision); err != nil {
+		logger.Warnf("Failed to log policy decision: %v", err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/google/cel-go/cel"
@@ -15,7 +17,10 @@ import (
 	"github.com/safedep/vet/gen/insightapi"
 	specmodels "github.com/safedep/vet/gen/models"
 	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/health"
+	"github.com/safedep/vet/pkg/license"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/policy"
 
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
@@ -33,6 +38,13 @@ const (
 	filterInputVarScorecard = "scorecard"
 	filterInputVarProjects  = "projects"
 	filterInputVarLicenses  = "licenses"
+	filterInputVarTags      = "tags"
+	filterInputVarHealth    = "health"
+	filterInputVarManifest  = "manifest"
+	filterInputVarRange     = "version_range"
+	filterInputVarMalware   = "malware"
+	filterInputVarCvss      = "cvss"
+	filterInputVarAdvisory  = "advisory"
 
 	// Soft limit to start with
 	filterEvalMaxFilters = 50
@@ -43,6 +55,15 @@ var errMaxFilter = errors.New("max filter limit has reached")
 type Evaluator interface {
 	AddFilter(filter *filtersuite.Filter) error
 	EvalPackage(pkg *models.Package) (*filterEvaluationResult, error)
+
+	// EvalPackageAll evaluates every filter and returns the matched
+	// filters in the order they were added
+	EvalPackageAll(pkg *models.Package) ([]*filtersuite.Filter, error)
+}
+
+type filterEvalInput struct {
+	vars   map[string]interface{}
+	digest string
 }
 
 type filterEvaluator struct {
@@ -50,15 +71,42 @@ type filterEvaluator struct {
 	env         *cel.Env
 	programs    []*filterProgram
 	ignoreError bool
+
+	// Clock used to compute advisory ages
+	now func() time.Time
+
+	// Optional, sink for decisions of every evaluation
+	decisionLogger policy.DecisionLogger
+
+	// Manifest level input of the last evaluated manifest. Manifests are
+	// analyzed one at a time, hence a single entry is sufficient and the
+	// cache does not grow or outlive the manifest. The input is rebuilt
+	// when packages are added to the manifest.
+	manifestInput      *manifestInputCache
+	manifestInputsLock sync.Mutex
 }
 
 func NewEvaluator(name string, ignoreError bool) (Evaluator, error) {
+	return NewEvaluatorWithDecisionLogger(name, ignoreError, nil)
+}
+
+// NewEvaluatorWithDecisionLogger creates an evaluator that logs every
+// evaluation as a policy decision. Logging is disabled when nil.
+func NewEvaluatorWithDecisionLogger(name string, ignoreError bool,
+	decisionLogger policy.DecisionLogger) (Evaluator, error) {
 	env, err := cel.NewEnv(
 		cel.Variable(filterInputVarPkg, cel.DynType),
 		cel.Variable(filterInputVarVulns, cel.DynType),
 		cel.Variable(filterInputVarProjects, cel.DynType),
 		cel.Variable(filterInputVarScorecard, cel.DynType),
 		cel.Variable(filterInputVarLicenses, cel.DynType),
+		cel.Variable(filterInputVarTags, cel.DynType),
+		cel.Variable(filterInputVarHealth, cel.DynType),
+		cel.Variable(filterInputVarManifest, cel.DynType),
+		cel.Variable(filterInputVarRange, cel.DynType),
+		cel.Variable(filterInputVarMalware, cel.DynType),
+		cel.Variable(filterInputVarCvss, cel.DynType),
+		cel.Variable(filterInputVarAdvisory, cel.DynType),
 		cel.Variable(filterInputVarRoot, cel.DynType),
 		cel.Function("contains_license",
 			cel.MemberOverload("list_string_contains_license_string",
@@ -70,10 +118,12 @@ func NewEvaluator(name string, ignoreError bool) (Evaluator, error) {
 	}
 
 	return &filterEvaluator{
-		name:        name,
-		env:         env,
-		programs:    []*filterProgram{},
-		ignoreError: ignoreError,
+		name:           name,
+		env:            env,
+		programs:       []*filterProgram{},
+		ignoreError:    ignoreError,
+		now:            time.Now,
+		decisionLogger: decisionLogger,
 	}, nil
 }
 
@@ -101,6 +151,54 @@ func (f *filterEvaluator) AddFilter(filter *filtersuite.Filter) error {
 }
 
 func (f *filterEvaluator) EvalPackage(pkg *models.Package) (*filterEvaluationResult, error) {
+	input, err := f.buildEvalInput(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prog := range f.programs {
+		matched, err := f.evalProgram(pkg, prog, input)
+		if err != nil {
+			return nil, err
+		}
+
+		if matched {
+			return &filterEvaluationResult{
+				match:   true,
+				program: prog,
+			}, nil
+		}
+	}
+
+	return &filterEvaluationResult{
+		match: false,
+	}, nil
+}
+
+func (f *filterEvaluator) EvalPackageAll(pkg *models.Package) ([]*filtersuite.Filter, error) {
+	input, err := f.buildEvalInput(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []*filtersuite.Filter{}
+	for _, prog := range f.programs {
+		ok, err := f.evalProgram(pkg, prog, input)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			matched = append(matched, prog.GetFilter())
+		}
+	}
+
+	return matched, nil
+}
+
+// buildEvalInput builds the CEL variables of a package once so that
+// they are shared by all the programs evaluated for the package
+func (f *filterEvaluator) buildEvalInput(pkg *models.Package) (*filterEvalInput, error) {
 	filterInput, err := f.buildFilterInput(pkg)
 	if err != nil {
 		return nil, err
@@ -111,38 +209,62 @@ func (f *filterEvaluator) EvalPackage(pkg *models.Package) (*filterEvaluationRes
 		return nil, err
 	}
 
-	for _, prog := range f.programs {
-		out, _, err := prog.program.Eval(map[string]interface{}{
+	// Derived inputs are not part of the filter input spec
+	serializedInput[filterInputVarHealth] = f.buildHealthInput(pkg)
+	serializedInput[filterInputVarManifest] = f.buildManifestInput(pkg.Manifest)
+	serializedInput[filterInputVarRange] = f.buildVersionRangeInput(pkg)
+	serializedInput[filterInputVarMalware] = f.buildMalwareInput(pkg)
+	serializedInput[filterInputVarCvss] = f.buildCvssInput(pkg)
+	serializedInput[filterInputVarAdvisory] = f.buildAdvisoryInput(pkg)
+
+	var inputDigest string
+	if f.decisionLogger != nil {
+		inputDigest = decisionInputDigest(serializedInput)
+	}
+
+	return &filterEvalInput{
+		vars: map[string]interface{}{
 			filterInputVarRoot:      serializedInput,
 			filterInputVarPkg:       serializedInput["pkg"],
 			filterInputVarProjects:  serializedInput["projects"],
 			filterInputVarVulns:     serializedInput["vulns"],
 			filterInputVarScorecard: serializedInput["scorecard"],
 			filterInputVarLicenses:  serializedInput["licenses"],
-		})
-		if err != nil {
-			logger.Warnf("CEL evaluator error: %s", err.Error())
+			filterInputVarTags:      serializedInput["tags"],
+			filterInputVarHealth:    serializedInput[filterInputVarHealth],
+			filterInputVarManifest:  serializedInput[filterInputVarManifest],
+			filterInputVarRange:     serializedInput[filterInputVarRange],
+			filterInputVarMalware:   serializedInput[filterInputVarMalware],
+			filterInputVarCvss:      serializedInput[filterInputVarCvss],
+			filterInputVarAdvisory:  serializedInput[filterInputVarAdvisory],
+		},
+		digest: inputDigest,
+	}, nil
+}
 
-			if f.ignoreError {
-				continue
-			}
+// evalProgram returns false without error for a failed evaluation
+// when errors are ignored
+func (f *filterEvaluator) evalProgram(pkg *models.Package, prog *filterProgram,
+	input *filterEvalInput) (bool, error) {
+	start := time.Now()
+	out, _, err := prog.program.Eval(input.vars)
+	if err != nil {
+		f.logDecision(pkg, prog, input.digest, start, false, err)
+		logger.Warnf("CEL evaluator error: %s", err.Error())
 
-			return nil, err
+		if f.ignoreError {
+			return false, nil
 		}
 
-		if (reflect.TypeOf(out).Kind() == reflect.Bool) &&
-			(reflect.ValueOf(out).Bool()) {
-
-			return &filterEvaluationResult{
-				match:   true,
-				program: prog,
-			}, nil
-		}
+		return false, err
 	}
 
-	return &filterEvaluationResult{
-		match: false,
-	}, nil
+	matched := (reflect.TypeOf(out).Kind() == reflect.Bool) &&
+		(reflect.ValueOf(out).Bool())
+
+	f.logDecision(pkg, prog, input.digest, start, matched, nil)
+
+	return matched, nil
 }
 
 // TODO: Fix this JSON round-trip problem by directly configuring CEL env to
@@ -185,6 +307,13 @@ func (f *filterEvaluator) buildFilterInput(pkg *models.Package) (*filterinput.Fi
 			Scores: map[string]float32{},
 		},
 		Licenses: []string{},
+		Tags:     map[string]string{},
+	}
+
+	if pkg.Manifest != nil {
+		for key, value := range pkg.Manifest.GetTags() {
+			fi.Tags[key] = value
+		}
 	}
 
 	// Safely get insight
@@ -275,6 +404,136 @@ func (f *filterEvaluator) buildFilterInput(pkg *models.Package) (*filterinput.Fi
 	return &fi, nil
 }
 
+// buildHealthInput exposes the composite health score so that policies
+// can use `health.score < 5.0` or `health.factors.maintenance < 3.0`
+// instead of multi-condition expressions
+func (f *filterEvaluator) buildHealthInput(pkg *models.Package) map[string]interface{} {
+	score := health.Compute(pkg)
+
+	factors := map[string]interface{}{}
+	for _, factor := range score.Factors {
+		if !factor.Available {
+			continue
+		}
+
+		factors[string(factor.Factor)] = factor.Score
+	}
+
+	return map[string]interface{}{
+		"score":     score.Score,
+		"available": score.Available,
+		"factors":   factors,
+	}
+}
+
+type manifestInputCache struct {
+	manifest *models.PackageManifest
+	packages int
+	input    map[string]interface{}
+}
+
+// buildManifestInput exposes the effective license set of the manifest
+// in which the package is found so that policies can use project level
+// conditions like `manifest.licenses.contains_license("GPL-3.0")`. Packages
+// without license information are listed in `unknown_license_packages`
+// while `unknown_licenses` is their count
+func (f *filterEvaluator) buildManifestInput(manifest *models.PackageManifest) map[string]interface{} {
+	if manifest == nil {
+		return map[string]interface{}{
+			"licenses":                 []interface{}{},
+			"unknown_licenses":         0,
+			"unknown_license_packages": []interface{}{},
+			"packages":                 0,
+		}
+	}
+
+	f.manifestInputsLock.Lock()
+	defer f.manifestInputsLock.Unlock()
+
+	packages := manifest.GetPackagesCount()
+	if cached := f.manifestInput; cached != nil && cached.manifest == manifest && cached.packages == packages {
+		return cached.input
+	}
+
+	inventory := license.Build(manifest)
+
+	licenses := []interface{}{}
+	for _, id := range inventory.LicenseIds() {
+		licenses = append(licenses, id)
+	}
+
+	unknown := []interface{}{}
+	for _, pkg := range inventory.Unknown {
+		unknown = append(unknown, map[string]interface{}{
+			"ecosystem": string(pkg.Ecosystem),
+			"name":      pkg.GetName(),
+			"version":   pkg.GetVersion(),
+		})
+	}
+
+	input := map[string]interface{}{
+		"licenses":                 licenses,
+		"unknown_licenses":         len(inventory.Unknown),
+		"unknown_license_packages": unknown,
+		"packages":                 inventory.Packages,
+	}
+
+	f.manifestInput = &manifestInputCache{manifest: manifest, packages: packages, input: input}
+	return input
+}
+
+// buildVersionRangeInput exposes the version constraint declared in the
+// manifest. Floating ranges are resolved to the lowest admitted version
+// which is the worst case, so policies can use `version_range.admits_vulnerable`
+// to forbid ranges that admit vulnerable versions
+func (f *filterEvaluator) buildVersionRangeInput(pkg *models.Package) map[string]interface{} {
+	insights := utils.SafelyGetValue(pkg.Insights)
+	vulnerable := len(utils.SafelyGetValue(insights.Vulnerabilities)) > 0
+	floating := pkg.IsFloatingVersion()
+
+	return map[string]interface{}{
+		"constraint":         pkg.GetVersionConstraint(),
+		"floating":           floating,
+		"worst_case_version": pkg.GetVersion(),
+		"admits_vulnerable":  floating && vulnerable,
+	}
+}
+
+// buildMalwareInput exposes the malware analysis result so that composite
+// rules can use behaviors e.g. `malware.behaviors.exists(b, b.contains("install"))`
+func (f *filterEvaluator) buildMalwareInput(pkg *models.Package) map[string]interface{} {
+	ma := pkg.GetMalwareAnalysisResult()
+	if ma == nil || ma.Report == nil {
+		return map[string]interface{}{
+			"analyzed":      false,
+			"is_malware":    false,
+			"is_suspicious": false,
+			"behaviors":     []interface{}{},
+			"files":         []interface{}{},
+		}
+	}
+
+	paths := map[string]string{}
+	for _, file := range ma.Report.GetFileSystem().GetFiles() {
+		paths[file.GetKey()] = file.GetPath()
+	}
+
+	behaviors := []interface{}{}
+	files := []interface{}{}
+	for _, fe := range ma.Report.GetFileEvidences() {
+		behaviors = append(behaviors, fe.GetEvidence().GetBehavior())
+		files = append(files, paths[fe.GetFileKey()])
+	}
+
+	return map[string]interface{}{
+		"analyzed":      true,
+		"is_malware":    ma.IsMalware,
+		"is_suspicious": ma.IsSuspicious,
+		"behaviors":     behaviors,
+		"files":         files,
+	}
+}
+
 func celFuncLicenseExpressionMatch() func(ref.Val, ref.Val) ref.Val {
 	return func(lhs, rhs ref.Val) ref.Val {
 		l, ok := lhs.(traits.Lister)
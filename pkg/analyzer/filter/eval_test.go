@@ -2,11 +2,17 @@ package filter
 
 import (
 	"testing"
+	"time"
 
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	vulnerabilityv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/vulnerability/v1"
 	"github.com/safedep/vet/gen/filtersuite"
 	"github.com/safedep/vet/gen/insightapi"
+	"github.com/safedep/vet/pkg/cvss"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/policy"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestEvaluatorLicenseExpression(t *testing.T) {
@@ -84,3 +90,412 @@ func TestEvaluatorLicenseExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluatorVersionRange(t *testing.T) {
+	vulnId := "GHSA-test"
+
+	cases := []struct {
+		name       string
+		constraint string
+		vulnerable bool
+		expected   bool
+	}{
+		{"Caret range admits vulnerable version", "^1.0.0", true, true},
+		{"Tilde range without vulnerability", "~1.0.0", false, false},
+		{"Exact version is not floating", "1.0.0", true, false},
+		{"No constraint", "", true, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := NewEvaluator("test", false)
+			assert.NoError(t, err)
+
+			err = f.AddFilter(&filtersuite.Filter{
+				Name:  "test",
+				Value: "version_range.floating && version_range.admits_vulnerable",
+			})
+			assert.NoError(t, err)
+
+			vulns := []insightapi.PackageVulnerability{}
+			if c.vulnerable {
+				vulns = append(vulns, insightapi.PackageVulnerability{Id: &vulnId})
+			}
+
+			pkg := &models.Package{
+				PackageDetails:    models.NewPackageDetail(models.EcosystemNpm, "test", "1.0.0"),
+				VersionConstraint: c.constraint,
+				Insights: &insightapi.PackageVersionInsight{
+					Vulnerabilities: &vulns,
+				},
+			}
+
+			result, err := f.EvalPackage(pkg)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, result.Matched())
+		})
+	}
+}
+
+func TestEvaluatorTags(t *testing.T) {
+	cases := []struct {
+		name         string
+		tags         map[string]string
+		filterString string
+		expected     bool
+	}{
+		{"Tag matches", map[string]string{"business_unit": "payments"}, "tags.business_unit == 'payments'", true},
+		{"Tag does not match", map[string]string{"business_unit": "search"}, "tags.business_unit == 'payments'", false},
+		{"Tag exists", map[string]string{"pci": ""}, "'pci' in tags", true},
+		{"Manifest without tags", nil, "'pci' in _.tags", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := NewEvaluator("test", false)
+			assert.NoError(t, err)
+
+			err = f.AddFilter(&filtersuite.Filter{
+				Name:  "test",
+				Value: c.filterString,
+			})
+			assert.NoError(t, err)
+
+			manifest := models.NewPackageManifestFromLocal("/app/package-lock.json", models.EcosystemNpm)
+			manifest.Tags = c.tags
+
+			pkg := &models.Package{
+				PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "test", "1.0.0"),
+				Manifest:       manifest,
+			}
+
+			result, err := f.EvalPackage(pkg)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, result.Matched())
+		})
+	}
+}
+
+func TestEvaluatorCvss(t *testing.T) {
+	vulnId := "GHSA-test"
+	vector := "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N"
+	cvss3 := insightapi.PackageVulnerabilitySeveritiesTypeCVSSV3
+
+	cases := []struct {
+		name         string
+		env          cvss.Environment
+		filterString string
+		expected     bool
+	}{
+		{"Base score below threshold", cvss.Environment{}, "cvss.base_score >= 7.0", false},
+		{"Adjusted score equals base score by default", cvss.Environment{}, "cvss.adjusted_score == 6.5", true},
+		{
+			"Adjusted score of sensitive data above threshold",
+			cvss.Environment{Exposure: cvss.ExposureInternet, DataSensitivity: cvss.SensitivityHigh},
+			"cvss.adjusted_score >= 7.0 && cvss.base_score < 7.0",
+			true,
+		},
+		{
+			"Adjusted score of internal tool below threshold",
+			cvss.Environment{Exposure: cvss.ExposureInternal},
+			"cvss.vulns.exists(v, v.id == 'GHSA-test' && v.adjusted_score >= 6.5)",
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			SetCvssEnvironment(c.env)
+			defer SetCvssEnvironment(cvss.Environment{})
+
+			f, err := NewEvaluator("test", false)
+			assert.NoError(t, err)
+
+			err = f.AddFilter(&filtersuite.Filter{
+				Name:  "test",
+				Value: c.filterString,
+			})
+			assert.NoError(t, err)
+
+			vulns := []insightapi.PackageVulnerability{
+				{
+					Id: &vulnId,
+					Severities: &[]struct {
+						Risk  *insightapi.PackageVulnerabilitySeveritiesRisk `json:"risk,omitempty"`
+						Score *string                                        `json:"score,omitempty"`
+						Type  *insightapi.PackageVulnerabilitySeveritiesType `json:"type,omitempty"`
+					}{
+						{Score: &vector, Type: &cvss3},
+					},
+				},
+			}
+
+			pkg := &models.Package{
+				PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "test", "1.0.0"),
+				Insights: &insightapi.PackageVersionInsight{
+					Vulnerabilities: &vulns,
+				},
+			}
+
+			result, err := f.EvalPackage(pkg)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, result.Matched())
+		})
+	}
+}
+
+func TestEvaluatorAdvisory(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) *timestamppb.Timestamp {
+		return timestamppb.New(now.Add(-time.Duration(days) * 24 * time.Hour))
+	}
+
+	cases := []struct {
+		name         string
+		versions     []*packagev1.PackageAvailableVersion
+		filterString string
+		expected     bool
+	}{
+		{
+			"Advisory older than grace period",
+			nil,
+			"advisory.published_days > 30",
+			true,
+		},
+		{
+			"No fix available",
+			[]*packagev1.PackageAvailableVersion{
+				{Version: "0.9.0", PublishedAt: daysAgo(10)},
+			},
+			"advisory.fix_available",
+			false,
+		},
+		{
+			"Fix available within grace period",
+			[]*packagev1.PackageAvailableVersion{
+				{Version: "1.0.1", PublishedAt: daysAgo(10)},
+			},
+			"advisory.fix_available && advisory.fix_days > 30",
+			false,
+		},
+		{
+			"Fix available beyond grace period",
+			[]*packagev1.PackageAvailableVersion{
+				{Version: "1.0.1", PublishedAt: daysAgo(40)},
+				{Version: "1.0.2", PublishedAt: daysAgo(5)},
+			},
+			"advisory.vulns.exists(v, v.id == 'GHSA-test' && v.fix_days > 30)",
+			true,
+		},
+		{
+			"Versions published before the advisory are not fixes",
+			[]*packagev1.PackageAvailableVersion{
+				{Version: "1.1.0", PublishedAt: daysAgo(90)},
+			},
+			"advisory.fix_available",
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := NewEvaluator("test", false)
+			assert.NoError(t, err)
+
+			f.(*filterEvaluator).now = func() time.Time { return now }
+
+			err = f.AddFilter(&filtersuite.Filter{
+				Name:  "test",
+				Value: c.filterString,
+			})
+			assert.NoError(t, err)
+
+			pkg := &models.Package{
+				PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "test", "1.0.0"),
+				InsightsV2: &packagev1.PackageVersionInsight{
+					Vulnerabilities: []*vulnerabilityv1.Vulnerability{
+						{
+							Id:          &vulnerabilityv1.VulnerabilityIdentifier{Value: "GHSA-test"},
+							PublishedAt: daysAgo(60),
+						},
+					},
+					AvailableVersions: c.versions,
+				},
+			}
+
+			result, err := f.EvalPackage(pkg)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, result.Matched())
+		})
+	}
+}
+
+func TestEvaluatorManifest(t *testing.T) {
+	newPackage := func(name string, licenses ...insightapi.License) *models.Package {
+		pkg := &models.Package{
+			PackageDetails: models.NewPackageDetail(models.EcosystemNpm, name, "1.0.0"),
+		}
+
+		if len(licenses) > 0 {
+			pkg.Insights = &insightapi.PackageVersionInsight{Licenses: &licenses}
+		}
+
+		return pkg
+	}
+
+	cases := []struct {
+		name         string
+		filterString string
+		expected     bool
+	}{
+		{
+			"Manifest contains license",
+			"manifest.licenses.contains_license('GPL-3.0')",
+			true,
+		},
+		{
+			"Manifest does not contain license",
+			"manifest.licenses.contains_license('AGPL-3.0')",
+			false,
+		},
+		{
+			"Unknown license count",
+			"manifest.unknown_licenses == 2 && manifest.packages == 3",
+			true,
+		},
+		{
+			"Unknown license package by name",
+			"manifest.unknown_license_packages.exists(p, p.name == 'c' && p.ecosystem == 'npm')",
+			true,
+		},
+		{
+			"Package with known license is not listed as unknown",
+			"manifest.unknown_license_packages.exists(p, p.name == 'a')",
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := NewEvaluator("test", false)
+			assert.NoError(t, err)
+
+			err = f.AddFilter(&filtersuite.Filter{
+				Name:  "test",
+				Value: c.filterString,
+			})
+			assert.NoError(t, err)
+
+			manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+			manifest.AddPackage(newPackage("a", "GPL-3.0"))
+			manifest.AddPackage(newPackage("b"))
+			manifest.AddPackage(newPackage("c"))
+
+			result, err := f.EvalPackage(manifest.GetPackages()[0])
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, result.Matched())
+		})
+	}
+}
+
+type recordingDecisionLogger struct {
+	decisions []*policy.Decision
+}
+
+func (l *recordingDecisionLogger) Log(decision *policy.Decision) error {
+	l.decisions = append(l.decisions, decision)
+	return nil
+}
+
+func (l *recordingDecisionLogger) Close() error {
+	return nil
+}
+
+func TestEvaluatorDecisionLogger(t *testing.T) {
+	decisionLogger := &recordingDecisionLogger{}
+
+	f, err := NewEvaluatorWithDecisionLogger("suite", true, decisionLogger)
+	assert.NoError(t, err)
+
+	// Evaluation stops at the first matching filter
+	assert.NoError(t, f.AddFilter(&filtersuite.Filter{Name: "pypi", Value: "pkg.ecosystem == 'pypi'"}))
+	assert.NoError(t, f.AddFilter(&filtersuite.Filter{Name: "npm", Value: "pkg.ecosystem == 'npm'"}))
+
+	manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+	manifest.AddPackage(&models.Package{
+		PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "a", "1.0.0"),
+	})
+
+	_, err = f.EvalPackage(manifest.GetPackages()[0])
+	assert.NoError(t, err)
+
+	if assert.Len(t, decisionLogger.decisions, 2) {
+		assert.Equal(t, "suite", decisionLogger.decisions[0].Policy)
+		assert.Equal(t, "pypi", decisionLogger.decisions[0].Rule)
+		assert.Equal(t, policy.DecisionOutcomeNotMatched, decisionLogger.decisions[0].Outcome)
+		assert.Equal(t, policy.DecisionOutcomeMatched, decisionLogger.decisions[1].Outcome)
+		assert.NotEmpty(t, decisionLogger.decisions[0].InputDigest)
+	}
+
+	// Evaluators without a logger are not affected by others
+	other, err := NewEvaluator("other", true)
+	assert.NoError(t, err)
+	assert.NoError(t, other.AddFilter(&filtersuite.Filter{Name: "npm", Value: "pkg.ecosystem == 'npm'"}))
+
+	_, err = other.EvalPackage(manifest.GetPackages()[0])
+	assert.NoError(t, err)
+	assert.Len(t, decisionLogger.decisions, 2)
+}
+
+func TestEvaluatorEvalPackageAll(t *testing.T) {
+	f, err := NewEvaluator("test", true)
+	assert.NoError(t, err)
+
+	assert.NoError(t, f.AddFilter(&filtersuite.Filter{Name: "npm", Value: "pkg.ecosystem == 'npm'"}))
+	assert.NoError(t, f.AddFilter(&filtersuite.Filter{Name: "pypi", Value: "pkg.ecosystem == 'pypi'"}))
+	assert.NoError(t, f.AddFilter(&filtersuite.Filter{Name: "name", Value: "pkg.name == 'a'"}))
+
+	manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+	manifest.AddPackage(&models.Package{
+		PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "a", "1.0.0"),
+	})
+
+	filters, err := f.EvalPackageAll(manifest.GetPackages()[0])
+	assert.NoError(t, err)
+
+	names := []string{}
+	for _, filter := range filters {
+		names = append(names, filter.GetName())
+	}
+
+	assert.Equal(t, []string{"npm", "name"}, names)
+}
+
+func TestEvaluatorManifestInputNotStale(t *testing.T) {
+	f, err := NewEvaluator("test", false)
+	assert.NoError(t, err)
+
+	err = f.AddFilter(&filtersuite.Filter{Name: "test", Value: "manifest.packages == 1"})
+	assert.NoError(t, err)
+
+	// Manifests with the same identity e.g. profiles of the same purl
+	// must not share the input
+	for i := 0; i < 2; i++ {
+		manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+		manifest.AddPackage(&models.Package{
+			PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "a", "1.0.0"),
+		})
+
+		result, err := f.EvalPackage(manifest.GetPackages()[0])
+		assert.NoError(t, err)
+		assert.True(t, result.Matched())
+
+		manifest.AddPackage(&models.Package{
+			PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "b", "1.0.0"),
+		})
+
+		result, err = f.EvalPackage(manifest.GetPackages()[0])
+		assert.NoError(t, err)
+		assert.False(t, result.Matched())
+	}
+}
@@ -0,0 +1,308 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/vet/gen/checks"
+	"github.com/safedep/vet/gen/insightapi"
+	jsonreportspec "github.com/safedep/vet/gen/jsonreport"
+	"github.com/safedep/vet/pkg/models"
+)
+
+// FindingKind is the category of risk identified by an analyzer
+type FindingKind string
+
+const (
+	FindingKindVulnerability = FindingKind("vulnerability")
+	FindingKindMalware       = FindingKind("malware")
+	FindingKindLicense       = FindingKind("license")
+	FindingKindPopularity    = FindingKind("popularity")
+	FindingKindMaintenance   = FindingKind("maintenance")
+	FindingKindScorecard     = FindingKind("scorecard")
+	FindingKindThreat        = FindingKind("threat")
+	FindingKindPolicy        = FindingKind("policy")
+)
+
+type FindingSeverity string
+
+const (
+	FindingSeverityCritical = FindingSeverity("critical")
+	FindingSeverityHigh     = FindingSeverity("high")
+	FindingSeverityMedium   = FindingSeverity("medium")
+	FindingSeverityLow      = FindingSeverity("low")
+	FindingSeverityInfo     = FindingSeverity("info")
+)
+
+// FindingConfidence is the confidence of an analyzer on a finding. Findings
+// based on heuristics e.g. automated malware analysis, lockfile poisoning
+// may have lower confidence than findings based on known data.
+type FindingConfidence string
+
+const (
+	FindingConfidenceHigh   = FindingConfidence("high")
+	FindingConfidenceMedium = FindingConfidence("medium")
+	FindingConfidenceLow    = FindingConfidence("low")
+)
+
+var findingConfidenceRank = map[FindingConfidence]int{
+	FindingConfidenceLow:    1,
+	FindingConfidenceMedium: 2,
+	FindingConfidenceHigh:   3,
+}
+
+// AtLeast returns true if the confidence is equal to or higher than min.
+// Unknown confidence is treated as high since it is not a heuristic
+func (c FindingConfidence) AtLeast(min FindingConfidence) bool {
+	rank, ok := findingConfidenceRank[c]
+	if !ok {
+		rank = findingConfidenceRank[FindingConfidenceHigh]
+	}
+
+	return rank >= findingConfidenceRank[min]
+}
+
+// FindingFilter is used by analyzers to drop findings before they
+// are published e.g. based on false positive feedback
+type FindingFilter interface {
+	Allow(finding *Finding) bool
+}
+
+const (
+	FindingEvidenceTypeCodeSnippet          = "code-snippet"
+	FindingEvidenceTypeInstallScript        = "install-script"
+	FindingEvidenceTypeYaraMatch            = "yara-match"
+	FindingEvidenceTypeRegistryMetadataDiff = "registry-metadata-diff"
+	FindingEvidenceTypeArtifactDiff         = "artifact-diff"
+)
+
+// Max size of the content of an evidence attachment. Larger content
+// is truncated to keep reports within a reasonable size.
+const FindingEvidenceAttachmentMaxSize = 16 * 1024
+
+// FindingEvidence is a piece of information that supports a finding
+type FindingEvidence struct {
+	// Type of evidence e.g. filter, url, analysis-report, code-snippet
+	Type string
+
+	// Human readable summary of the evidence
+	Summary string
+
+	// Optional, raw content backing the evidence
+	Attachment *FindingEvidenceAttachment
+}
+
+// FindingEvidenceAttachment is a blob of content attached to an evidence
+// e.g. matched code snippet, install script or registry metadata diff
+type FindingEvidenceAttachment struct {
+	// Name of the attachment e.g. file path within the package
+	Name string
+
+	// Media type of the content e.g. text/plain
+	MediaType string
+
+	// Optional, line number within the file when applicable
+	Line int
+
+	Content string
+
+	// Content was truncated to FindingEvidenceAttachmentMaxSize
+	Truncated bool
+}
+
+// NewFindingEvidenceAttachment creates an attachment with content
+// truncated to FindingEvidenceAttachmentMaxSize
+func NewFindingEvidenceAttachment(name, mediaType, content string) *FindingEvidenceAttachment {
+	attachment := &FindingEvidenceAttachment{
+		Name:      name,
+		MediaType: mediaType,
+		Content:   content,
+	}
+
+	if len(content) > FindingEvidenceAttachmentMaxSize {
+		attachment.Content = strings.ToValidUTF8(content[:FindingEvidenceAttachmentMaxSize], "")
+		attachment.Truncated = true
+	}
+
+	return attachment
+}
+
+// FindingRemediation is an actionable suggestion to resolve a finding
+type FindingRemediation struct {
+	Summary string
+
+	// Optional, when upgrading or replacing a package resolves the finding
+	TargetPackageName    string
+	TargetPackageVersion string
+}
+
+// Finding is the structured description of a risk identified by an analyzer.
+// It is shared across analyzers and reporters so that reporters can handle
+// events generically without knowing the analyzer producing the event.
+type Finding struct {
+	Kind       FindingKind
+	Severity   FindingSeverity
+	Confidence FindingConfidence
+
+	// Short human readable title of the finding
+	Title string
+
+	// Subject of the finding. Package may be nil for manifest level findings
+	Manifest *models.PackageManifest
+	Package  *models.Package
+
+	Evidences   []FindingEvidence
+	Remediation *FindingRemediation
+}
+
+// FindingKindFromCheckType maps the check type of a filter to finding kind
+func FindingKindFromCheckType(ct checks.CheckType) FindingKind {
+	switch ct {
+	case checks.CheckType_CheckTypeVulnerability:
+		return FindingKindVulnerability
+	case checks.CheckType_CheckTypeMalware:
+		return FindingKindMalware
+	case checks.CheckType_CheckTypeLicense:
+		return FindingKindLicense
+	case checks.CheckType_CheckTypePopularity:
+		return FindingKindPopularity
+	case checks.CheckType_CheckTypeMaintenance:
+		return FindingKindMaintenance
+	case checks.CheckType_CheckTypeSecurityScorecard:
+		return FindingKindScorecard
+	default:
+		return FindingKindPolicy
+	}
+}
+
+// CheckType maps the finding kind back to check type used in reports
+func (f *Finding) CheckType() checks.CheckType {
+	switch f.Kind {
+	case FindingKindVulnerability:
+		return checks.CheckType_CheckTypeVulnerability
+	case FindingKindMalware:
+		return checks.CheckType_CheckTypeMalware
+	case FindingKindLicense:
+		return checks.CheckType_CheckTypeLicense
+	case FindingKindPopularity:
+		return checks.CheckType_CheckTypePopularity
+	case FindingKindMaintenance:
+		return checks.CheckType_CheckTypeMaintenance
+	case FindingKindScorecard:
+		return checks.CheckType_CheckTypeSecurityScorecard
+	default:
+		return checks.CheckType_CheckTypeOther
+	}
+}
+
+// GetFinding returns the structured finding of the event. For events
+// that are not explicitly annotated with a finding, a finding is derived
+// from the filter or threat of the event. Returns nil for events that
+// are not findings e.g. fail on error
+func (ev *AnalyzerEvent) GetFinding() *Finding {
+	if ev.Finding != nil {
+		return ev.Finding
+	}
+
+	switch ev.Type {
+	case ET_FilterExpressionMatched:
+		if ev.Filter == nil {
+			return nil
+		}
+
+		return newFilterFinding(ev)
+	case ET_LockfilePoisoningSignal:
+		if ev.Threat == nil {
+			return nil
+		}
+
+		return newThreatFinding(ev)
+	}
+
+	return nil
+}
+
+func newFilterFinding(ev *AnalyzerEvent) *Finding {
+	finding := &Finding{
+		Kind:       FindingKindFromCheckType(ev.Filter.GetCheckType()),
+		Severity:   FindingSeverityMedium,
+		Confidence: FindingConfidenceHigh,
+		Title:      ev.Filter.GetSummary(),
+		Manifest:   ev.Manifest,
+		Package:    ev.Package,
+		Evidences: []FindingEvidence{
+			{Type: "filter", Summary: ev.Filter.GetName()},
+		},
+	}
+
+	if finding.Title == "" {
+		finding.Title = ev.Filter.GetName()
+	}
+
+	switch finding.Kind {
+	case FindingKindMalware:
+		finding.Severity = FindingSeverityCritical
+	case FindingKindVulnerability:
+		if ev.Package != nil {
+			finding.Severity = vulnerabilitySeverity(ev.Package)
+		}
+	}
+
+	return finding
+}
+
+func newThreatFinding(ev *AnalyzerEvent) *Finding {
+	severity := FindingSeverityMedium
+	confidence := FindingConfidenceMedium
+	switch ev.Threat.GetConfidence() {
+	case jsonreportspec.ReportThreat_High:
+		severity = FindingSeverityHigh
+		confidence = FindingConfidenceHigh
+	case jsonreportspec.ReportThreat_Low:
+		severity = FindingSeverityLow
+		confidence = FindingConfidenceLow
+	}
+
+	return &Finding{
+		Kind:       FindingKindThreat,
+		Severity:   severity,
+		Confidence: confidence,
+		Title:      ev.Threat.GetMessage(),
+		Manifest:   ev.Manifest,
+		Package:    ev.Package,
+		Evidences: []FindingEvidence{
+			{Type: ev.Threat.GetId().String(), Summary: ev.Threat.GetMessage()},
+		},
+	}
+}
+
+// Highest risk among the known vulnerabilities of the package
+func vulnerabilitySeverity(pkg *models.Package) FindingSeverity {
+	ranks := map[insightapi.PackageVulnerabilitySeveritiesRisk]FindingSeverity{
+		insightapi.PackageVulnerabilitySeveritiesRiskCRITICAL: FindingSeverityCritical,
+		insightapi.PackageVulnerabilitySeveritiesRiskHIGH:     FindingSeverityHigh,
+		insightapi.PackageVulnerabilitySeveritiesRiskMEDIUM:   FindingSeverityMedium,
+		insightapi.PackageVulnerabilitySeveritiesRiskLOW:      FindingSeverityLow,
+	}
+
+	order := []FindingSeverity{FindingSeverityCritical, FindingSeverityHigh,
+		FindingSeverityMedium, FindingSeverityLow}
+
+	found := map[FindingSeverity]bool{}
+	insights := utils.SafelyGetValue(pkg.Insights)
+	for _, vuln := range utils.SafelyGetValue(insights.Vulnerabilities) {
+		for _, s := range utils.SafelyGetValue(vuln.Severities) {
+			if severity, ok := ranks[utils.SafelyGetValue(s.Risk)]; ok {
+				found[severity] = true
+			}
+		}
+	}
+
+	for _, severity := range order {
+		if found[severity] {
+			return severity
+		}
+	}
+
+	return FindingSeverityMedium
+}
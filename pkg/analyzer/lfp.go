@@ -19,6 +19,9 @@ const lfpThreatSourceId = "CWE-349"
 type LockfilePoisoningAnalyzerConfig struct {
 	FailFast            bool
 	TrustedRegistryUrls []string
+
+	// Optional, drop findings e.g. based on false positive feedback
+	FindingFilter FindingFilter
 }
 
 type lockfilePoisoningAnalyzer struct {
@@ -64,6 +67,12 @@ func (lfp *lockfilePoisoningAnalyzer) Analyze(manifest *models.PackageManifest,
 
 	plugin := pluginBuilder(&lfp.config)
 	return plugin.Analyze(manifest, func(event *AnalyzerEvent) error {
+		finding := event.GetFinding()
+		if finding != nil && lfp.config.FindingFilter != nil && !lfp.config.FindingFilter.Allow(finding) {
+			logger.Debugf("LockfilePoisoningAnalyzer: Finding dropped by filter: %s", finding.Title)
+			return nil
+		}
+
 		lfp.detections = append(lfp.detections, event)
 		if lfp.config.FailFast {
 			err := handler(&AnalyzerEvent{
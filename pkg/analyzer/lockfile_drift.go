@@ -0,0 +1,195 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/models"
+)
+
+const lockfileDriftAnalyzerName = "LockfileDriftAnalyzer"
+
+type LockfileDriftAnalyzerConfig struct {
+	// Fail the scan when lockfile is not in sync with the manifest
+	FailOnDrift bool
+}
+
+type lockfileDriftAnalyzer struct {
+	config LockfileDriftAnalyzerConfig
+	drifts int
+}
+
+type npmDeclaredDependencies struct {
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+func (d *npmDeclaredDependencies) all() map[string]string {
+	deps := map[string]string{}
+	for _, m := range []map[string]string{d.Dependencies, d.DevDependencies, d.OptionalDependencies} {
+		for name, constraint := range m {
+			deps[name] = constraint
+		}
+	}
+
+	return deps
+}
+
+// NewLockfileDriftAnalyzer creates an analyzer that verifies the lockfile is
+// in sync with the manifest from which it is generated. Drift happens when
+// the manifest is updated without regenerating the lockfile, or the lockfile
+// is modified manually. Currently supports only npm package-lock.json
+func NewLockfileDriftAnalyzer(config LockfileDriftAnalyzerConfig) (Analyzer, error) {
+	return &lockfileDriftAnalyzer{config: config}, nil
+}
+
+func (a *lockfileDriftAnalyzer) Name() string {
+	return lockfileDriftAnalyzerName
+}
+
+func (a *lockfileDriftAnalyzer) Analyze(manifest *models.PackageManifest,
+	handler AnalyzerEventHandler) error {
+	if manifest.Ecosystem != models.EcosystemNpm ||
+		filepath.Base(manifest.GetPath()) != "package-lock.json" {
+		return nil
+	}
+
+	drifts, err := npmLockfileDrifts(manifest.GetPath())
+	if err != nil {
+		// A gate must fail closed on a lockfile that cannot be verified
+		if a.config.FailOnDrift {
+			return handler(&AnalyzerEvent{
+				Source:   a.Name(),
+				Type:     ET_AnalyzerFailOnError,
+				Manifest: manifest,
+				Err: fmt.Errorf("lockfile %s cannot be verified: %w",
+					manifest.GetDisplayPath(), err),
+			})
+		}
+
+		return err
+	}
+
+	for _, drift := range drifts {
+		err := handler(&AnalyzerEvent{
+			Source:   a.Name(),
+			Type:     ET_LockfileDriftSignal,
+			Message:  drift,
+			Manifest: manifest,
+			Finding: &Finding{
+				Kind:       FindingKindPolicy,
+				Severity:   FindingSeverityMedium,
+				Confidence: FindingConfidenceHigh,
+				Title:      drift,
+				Manifest:   manifest,
+				Evidences: []FindingEvidence{
+					{Type: "lockfile", Summary: manifest.GetDisplayPath()},
+				},
+				Remediation: &FindingRemediation{
+					Summary: "Regenerate the lockfile using `npm install` and commit it",
+				},
+			},
+		})
+		if err != nil {
+			logger.Errorf("LockfileDriftAnalyzer: Failed to handle drift event: %v", err)
+		}
+	}
+
+	a.drifts += len(drifts)
+	if a.config.FailOnDrift && len(drifts) > 0 {
+		return handler(&AnalyzerEvent{
+			Source:   a.Name(),
+			Type:     ET_AnalyzerFailOnError,
+			Manifest: manifest,
+			Err: fmt.Errorf("lockfile %s is not in sync with package.json (%d drifts)",
+				manifest.GetDisplayPath(), len(drifts)),
+		})
+	}
+
+	return nil
+}
+
+func (a *lockfileDriftAnalyzer) Finish() error {
+	if a.drifts > 0 {
+		logger.Infof("LockfileDriftAnalyzer: Found %d drifts", a.drifts)
+	}
+
+	return nil
+}
+
+// npmLockfileDrifts compares the dependencies declared in package.json
+// with the root package and resolved packages in package-lock.json
+func npmLockfileDrifts(lockfilePath string) ([]string, error) {
+	packageJsonPath := filepath.Join(filepath.Dir(lockfilePath), "package.json")
+
+	var packageJson npmDeclaredDependencies
+	err := npmReadJson(packageJsonPath, &packageJson)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package.json: %w", err)
+	}
+
+	var lockfile struct {
+		LockfileVersion int `json:"lockfileVersion"`
+		Packages        map[string]struct {
+			npmDeclaredDependencies
+			Version string `json:"version"`
+		} `json:"packages"`
+	}
+
+	err = npmReadJson(lockfilePath, &lockfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	if lockfile.LockfileVersion < 2 {
+		return nil, fmt.Errorf("unsupported lockfile version %d", lockfile.LockfileVersion)
+	}
+
+	declared := packageJson.all()
+	root := lockfile.Packages[""]
+	locked := root.all()
+
+	drifts := []string{}
+	for name, constraint := range declared {
+		lockedConstraint, ok := locked[name]
+		if !ok {
+			drifts = append(drifts, fmt.Sprintf("Dependency `%s` is declared in package.json but missing in lockfile", name))
+			continue
+		}
+
+		if lockedConstraint != constraint {
+			drifts = append(drifts, fmt.Sprintf("Dependency `%s` is `%s` in package.json but `%s` in lockfile",
+				name, constraint, lockedConstraint))
+			continue
+		}
+
+		if _, ok := lockfile.Packages["node_modules/"+name]; !ok {
+			drifts = append(drifts, fmt.Sprintf("Dependency `%s` is not resolved in lockfile", name))
+		}
+	}
+
+	for name := range locked {
+		if _, ok := declared[name]; !ok {
+			drifts = append(drifts, fmt.Sprintf("Dependency `%s` is in lockfile but not declared in package.json", name))
+		}
+	}
+
+	// Map iteration order is random, keep the output stable
+	sort.Strings(drifts)
+	return drifts, nil
+}
+
+func npmReadJson(path string, out any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return json.NewDecoder(bytes.NewReader(data)).Decode(out)
+}
@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNpmLockfileDrifts(t *testing.T) {
+	cases := []struct {
+		name        string
+		packageJson string
+		lockfile    string
+		drifts      []string
+		err         string
+	}{
+		{
+			"Lockfile in sync",
+			`{"dependencies": {"a": "^1.0.0"}, "devDependencies": {"b": "~2.0.0"}}`,
+			`{"lockfileVersion": 3, "packages": {
+				"": {"dependencies": {"a": "^1.0.0"}, "devDependencies": {"b": "~2.0.0"}},
+				"node_modules/a": {"version": "1.0.1"},
+				"node_modules/b": {"version": "2.0.3"}
+			}}`,
+			[]string{},
+			"",
+		},
+		{
+			"Lockfile drifted",
+			`{"dependencies": {"a": "^1.1.0", "c": "1.0.0"}}`,
+			`{"lockfileVersion": 3, "packages": {
+				"": {"dependencies": {"a": "^1.0.0", "b": "1.0.0"}},
+				"node_modules/a": {"version": "1.0.1"},
+				"node_modules/b": {"version": "1.0.0"}
+			}}`,
+			[]string{
+				"Dependency `a` is `^1.1.0` in package.json but `^1.0.0` in lockfile",
+				"Dependency `b` is in lockfile but not declared in package.json",
+				"Dependency `c` is declared in package.json but missing in lockfile",
+			},
+			"",
+		},
+		{
+			"Unsupported lockfile version",
+			`{}`,
+			`{"lockfileVersion": 1}`,
+			nil,
+			"unsupported lockfile version",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			assert.Nil(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(test.packageJson), 0600))
+			assert.Nil(t, os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte(test.lockfile), 0600))
+
+			drifts, err := npmLockfileDrifts(filepath.Join(dir, "package-lock.json"))
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.drifts, drifts)
+		})
+	}
+}
+
+func TestLockfileDriftAnalyzerUnverifiedLockfile(t *testing.T) {
+	cases := []struct {
+		name        string
+		failOnDrift bool
+		packageJson string
+		lockfile    string
+		failEvent   bool
+		err         string
+	}{
+		{
+			"Missing package.json fails the gate",
+			true,
+			"",
+			`{"lockfileVersion": 3, "packages": {}}`,
+			true,
+			"",
+		},
+		{
+			"Unsupported lockfile version fails the gate",
+			true,
+			`{}`,
+			`{"lockfileVersion": 1}`,
+			true,
+			"",
+		},
+		{
+			"Unsupported lockfile version without gate",
+			false,
+			`{}`,
+			`{"lockfileVersion": 1}`,
+			false,
+			"unsupported lockfile version",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if test.packageJson != "" {
+				assert.Nil(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(test.packageJson), 0600))
+			}
+
+			lockfilePath := filepath.Join(dir, "package-lock.json")
+			assert.Nil(t, os.WriteFile(lockfilePath, []byte(test.lockfile), 0600))
+
+			a, err := NewLockfileDriftAnalyzer(LockfileDriftAnalyzerConfig{FailOnDrift: test.failOnDrift})
+			assert.Nil(t, err)
+
+			events := []*AnalyzerEvent{}
+			err = a.Analyze(models.NewPackageManifestFromLocal(lockfilePath, models.EcosystemNpm),
+				func(event *AnalyzerEvent) error {
+					events = append(events, event)
+					return nil
+				})
+
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+			} else {
+				assert.Nil(t, err)
+			}
+
+			if !test.failEvent {
+				assert.Empty(t, events)
+				return
+			}
+
+			assert.Len(t, events, 1)
+			assert.True(t, events[0].IsFailOnError())
+			assert.ErrorContains(t, events[0].Err, "cannot be verified")
+		})
+	}
+}
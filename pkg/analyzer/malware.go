@@ -2,9 +2,12 @@ package analyzer
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 	"sync"
 
 	malysisv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/malysis/v1"
+	"github.com/safedep/dry/utils"
 	"github.com/safedep/vet/gen/checks"
 	"github.com/safedep/vet/gen/filtersuite"
 	"github.com/safedep/vet/pkg/common/logger"
@@ -19,6 +22,9 @@ type MalwareAnalyzerConfig struct {
 
 	// Fail fast on malware detection
 	FailFast bool
+
+	// Optional, drop findings e.g. based on false positive feedback
+	FindingFilter FindingFilter
 }
 
 func DefaultMalwareAnalyzerConfig() MalwareAnalyzerConfig {
@@ -59,12 +65,35 @@ func (a *malwareAnalyzer) Analyze(manifest *models.PackageManifest,
 		}
 
 		var filterMsg string
+		var severity FindingSeverity
 		if pkg.IsMalware() {
 			filterMsg = fmt.Sprintf("MalwareAnalyzer: Package %s/%s/%s is classified as malicious",
 				pkg.GetControlTowerSpecEcosystem(), pkg.GetName(), pkg.GetVersion())
+			severity = FindingSeverityCritical
 		} else {
 			filterMsg = fmt.Sprintf("MalwareAnalyzer: Package %s/%s/%s is classified as suspicious",
 				pkg.GetControlTowerSpecEcosystem(), pkg.GetName(), pkg.GetVersion())
+			severity = FindingSeverityHigh
+		}
+
+		finding := &Finding{
+			Kind:       FindingKindMalware,
+			Severity:   severity,
+			Confidence: malwareFindingConfidence(pkg),
+			Title:      filterMsg,
+			Manifest:   manifest,
+			Package:    pkg,
+			Evidences:  a.findingEvidences(pkg),
+			Remediation: &FindingRemediation{
+				Summary: "Remove the package or replace it with a trusted alternative",
+			},
+		}
+
+		// Only suspicious packages are filtered, malware is never suppressed
+		if !pkg.IsMalware() && a.config.FindingFilter != nil && !a.config.FindingFilter.Allow(finding) {
+			logger.Infof("MalwareAnalyzer: Finding for suspicious package %s/%s/%s dropped by filter",
+				pkg.GetControlTowerSpecEcosystem(), pkg.GetName(), pkg.GetVersion())
+			return nil
 		}
 
 		// Trigger a policy violation event so that it gets recorded
@@ -83,6 +112,7 @@ func (a *malwareAnalyzer) Analyze(manifest *models.PackageManifest,
 				References:  []string{"https://docs.safedep.io/cloud/malware-analysis"},
 				Tags:        []string{"malware-analysis"},
 			},
+			Finding: finding,
 		})
 
 		if err != nil {
@@ -103,6 +133,76 @@ func (a *malwareAnalyzer) Analyze(manifest *models.PackageManifest,
 	})
 }
 
+// findingEvidences builds the evidences of a malware finding from the
+// analysis report including the content matched in the package files
+func (a *malwareAnalyzer) findingEvidences(pkg *models.Package) []FindingEvidence {
+	ma := utils.SafelyGetValue(pkg.GetMalwareAnalysisResult())
+	evidences := []FindingEvidence{
+		{
+			Type:    "analysis-report",
+			Summary: fmt.Sprintf("Malware analysis id: %s", ma.AnalysisId),
+		},
+	}
+
+	if ma.Report == nil {
+		return evidences
+	}
+
+	paths := map[string]string{}
+	for _, file := range ma.Report.GetFileSystem().GetFiles() {
+		paths[file.GetKey()] = file.GetPath()
+	}
+
+	for _, fe := range ma.Report.GetFileEvidences() {
+		path := paths[fe.GetFileKey()]
+		if path == "" {
+			path = fe.GetFileKey()
+		}
+
+		attachment := NewFindingEvidenceAttachment(path, "text/plain", fe.GetEvidence().GetDetails())
+		attachment.Line = int(fe.GetLine())
+
+		evidences = append(evidences, FindingEvidence{
+			Type:       malwareEvidenceType(fe.GetEvidence().GetSource(), path),
+			Summary:    fe.GetEvidence().GetTitle(),
+			Attachment: attachment,
+		})
+	}
+
+	return evidences
+}
+
+// Verified malware is high confidence, otherwise the confidence
+// of the automated analysis is used
+func malwareFindingConfidence(pkg *models.Package) FindingConfidence {
+	ma := utils.SafelyGetValue(pkg.GetMalwareAnalysisResult())
+	if ma.VerificationRecord != nil && ma.VerificationRecord.GetIsMalware() {
+		return FindingConfidenceHigh
+	}
+
+	switch ma.Report.GetInference().GetConfidence() {
+	case malysisv1.Report_Evidence_CONFIDENCE_HIGH:
+		return FindingConfidenceHigh
+	case malysisv1.Report_Evidence_CONFIDENCE_MEDIUM:
+		return FindingConfidenceMedium
+	default:
+		return FindingConfidenceLow
+	}
+}
+
+func malwareEvidenceType(source, path string) string {
+	if strings.Contains(strings.ToLower(source), "yara") {
+		return FindingEvidenceTypeYaraMatch
+	}
+
+	switch strings.ToLower(filepath.Base(path)) {
+	case "setup.py", "install.js", "preinstall.js", "postinstall.js", "install.sh":
+		return FindingEvidenceTypeInstallScript
+	}
+
+	return FindingEvidenceTypeCodeSnippet
+}
+
 func (a *malwareAnalyzer) Finish() error {
 	return nil
 }
@@ -0,0 +1,341 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/vet/gen/checks"
+	"github.com/safedep/vet/gen/filtersuite"
+	"github.com/safedep/vet/pkg/common/httpclient"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/readers"
+)
+
+const (
+	mirrorFreshnessAnalyzerName   = "MirrorFreshnessAnalyzer"
+	mirrorFreshnessDefaultTimeout = 10 * time.Second
+
+	mirrorFreshnessDefaultConcurrency = 10
+)
+
+var mirrorFreshnessDefaultUpstreams = map[string]string{
+	models.EcosystemNpm:  "https://registry.npmjs.org",
+	models.EcosystemPyPI: "https://pypi.org",
+}
+
+type MirrorFreshnessAnalyzerConfig struct {
+	// Base URL of private mirror by ecosystem e.g. npm => https://npm.example.com
+	Mirrors map[string]string
+
+	// Optional, base URL of upstream registry by ecosystem. Public
+	// registries are used by default
+	Upstreams map[string]string
+
+	// Optional HTTP client, defaults to shared client
+	HttpClient *http.Client
+
+	// Optional timeout of a registry lookup, defaults to 10s so that a
+	// stalled registry does not hang the scan
+	Timeout time.Duration
+
+	// Optional, max number of packages looked up concurrently
+	Concurrency int
+}
+
+// Versions published in a registry for a package
+type registryPackageVersions struct {
+	latest   string
+	versions map[string]bool
+}
+
+// Freshness of a package in a mirror
+type mirrorFreshnessResult struct {
+	pkg       *models.Package
+	mirrorUrl string
+	upstream  *registryPackageVersions
+	mirror    *registryPackageVersions
+}
+
+// Lookup of a package in a registry, performed once per scan. Failures are
+// cached as well so that an unavailable registry is not retried per package.
+type registryLookup struct {
+	once     sync.Once
+	versions *registryPackageVersions
+	err      error
+}
+
+type mirrorFreshnessAnalyzer struct {
+	config MirrorFreshnessAnalyzerConfig
+
+	// Cache of registry lookups by registry URL and package name
+	m     sync.Mutex
+	cache map[string]*registryLookup
+}
+
+// NewMirrorFreshnessAnalyzer creates an analyzer that flags packages for
+// which the latest upstream version is not available in the configured
+// mirror. A stale mirror forces installation of older, possibly vulnerable,
+// versions of a package.
+func NewMirrorFreshnessAnalyzer(config MirrorFreshnessAnalyzerConfig) (Analyzer, error) {
+	if len(config.Mirrors) == 0 {
+		return nil, errors.New("at least one mirror is required")
+	}
+
+	for ecosystem := range config.Mirrors {
+		if _, ok := mirrorFreshnessDefaultUpstreams[ecosystem]; !ok {
+			return nil, fmt.Errorf("mirror freshness check is not supported for ecosystem: %s", ecosystem)
+		}
+	}
+
+	if config.Upstreams == nil {
+		config.Upstreams = map[string]string{}
+	}
+
+	for ecosystem, upstream := range mirrorFreshnessDefaultUpstreams {
+		if _, ok := config.Upstreams[ecosystem]; !ok {
+			config.Upstreams[ecosystem] = upstream
+		}
+	}
+
+	if config.HttpClient == nil {
+		config.HttpClient = httpclient.Default()
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = mirrorFreshnessDefaultTimeout
+	}
+
+	if config.Concurrency <= 0 {
+		config.Concurrency = mirrorFreshnessDefaultConcurrency
+	}
+
+	return &mirrorFreshnessAnalyzer{
+		config: config,
+		cache:  make(map[string]*registryLookup),
+	}, nil
+}
+
+func (a *mirrorFreshnessAnalyzer) Name() string {
+	return mirrorFreshnessAnalyzerName
+}
+
+// Analyze looks up the packages concurrently and reports stale packages
+// in the order of the manifest
+func (a *mirrorFreshnessAnalyzer) Analyze(manifest *models.PackageManifest,
+	handler AnalyzerEventHandler) error {
+	results := []*mirrorFreshnessResult{}
+	err := readers.NewManifestModelReader(manifest).EnumPackages(func(pkg *models.Package) error {
+		if mirrorUrl, ok := a.config.Mirrors[string(pkg.Ecosystem)]; ok {
+			results = append(results, &mirrorFreshnessResult{pkg: pkg, mirrorUrl: mirrorUrl})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, a.config.Concurrency)
+	for _, result := range results {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(result *mirrorFreshnessResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			a.lookupFreshness(result)
+		}(result)
+	}
+
+	wg.Wait()
+
+	for _, result := range results {
+		if result.upstream == nil || result.mirror == nil {
+			continue
+		}
+
+		err := a.handleResult(manifest, result, handler)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lookupFreshness leaves the result incomplete when a lookup fails
+func (a *mirrorFreshnessAnalyzer) lookupFreshness(result *mirrorFreshnessResult) {
+	pkg := result.pkg
+	ecosystem := string(pkg.Ecosystem)
+
+	upstream, err := a.lookup(ecosystem, a.config.Upstreams[ecosystem], pkg.GetName())
+	if err != nil {
+		logger.Warnf("MirrorFreshnessAnalyzer: Failed to lookup %s in upstream: %v", pkg.GetName(), err)
+		return
+	}
+
+	mirror, err := a.lookup(ecosystem, result.mirrorUrl, pkg.GetName())
+	if err != nil {
+		logger.Warnf("MirrorFreshnessAnalyzer: Failed to lookup %s in mirror: %v", pkg.GetName(), err)
+		return
+	}
+
+	result.upstream = upstream
+	result.mirror = mirror
+}
+
+func (a *mirrorFreshnessAnalyzer) handleResult(manifest *models.PackageManifest,
+	result *mirrorFreshnessResult, handler AnalyzerEventHandler) error {
+	pkg, mirrorUrl := result.pkg, result.mirrorUrl
+	ecosystem := string(pkg.Ecosystem)
+	upstream, mirror := result.upstream, result.mirror
+
+	if upstream.latest == "" || mirror.versions[upstream.latest] {
+		return nil
+	}
+
+	vulnerable := len(utils.SafelyGetValue(utils.SafelyGetValue(pkg.Insights).Vulnerabilities)) > 0
+
+	severity := FindingSeverityLow
+	if vulnerable {
+		severity = FindingSeverityHigh
+	}
+
+	msg := fmt.Sprintf("Mirror %s is stale for %s/%s, latest upstream version %s is not available (mirror latest: %s)",
+		mirrorUrl, ecosystem, pkg.GetName(), upstream.latest, mirror.latest)
+
+	return handler(&AnalyzerEvent{
+		Type:     ET_FilterExpressionMatched,
+		Source:   a.Name(),
+		Manifest: manifest,
+		Package:  pkg,
+		Message:  msg,
+		Filter: &filtersuite.Filter{
+			Name:        "stale-registry-mirror",
+			CheckType:   checks.CheckType_CheckTypeOther,
+			Summary:     msg,
+			Description: "Latest upstream version of the package is not available in the registry mirror",
+		},
+		Finding: &Finding{
+			Kind:       FindingKindPolicy,
+			Severity:   severity,
+			Confidence: FindingConfidenceHigh,
+			Title:      msg,
+			Manifest:   manifest,
+			Package:    pkg,
+			Evidences: []FindingEvidence{
+				{Type: "url", Summary: mirrorUrl},
+			},
+			Remediation: &FindingRemediation{
+				Summary:              "Sync the registry mirror with upstream",
+				TargetPackageName:    pkg.GetName(),
+				TargetPackageVersion: upstream.latest,
+			},
+		},
+	})
+}
+
+func (a *mirrorFreshnessAnalyzer) Finish() error {
+	return nil
+}
+
+func (a *mirrorFreshnessAnalyzer) lookup(ecosystem, registryUrl, name string) (*registryPackageVersions, error) {
+	key := fmt.Sprintf("%s/%s", registryUrl, name)
+
+	a.m.Lock()
+	entry, ok := a.cache[key]
+	if !ok {
+		entry = &registryLookup{}
+		a.cache[key] = entry
+	}
+	a.m.Unlock()
+
+	entry.once.Do(func() {
+		switch ecosystem {
+		case models.EcosystemNpm:
+			entry.versions, entry.err = a.lookupNpm(registryUrl, name)
+		case models.EcosystemPyPI:
+			entry.versions, entry.err = a.lookupPyPI(registryUrl, name)
+		default:
+			entry.err = fmt.Errorf("unsupported ecosystem: %s", ecosystem)
+		}
+	})
+
+	return entry.versions, entry.err
+}
+
+func (a *mirrorFreshnessAnalyzer) lookupNpm(registryUrl, name string) (*registryPackageVersions, error) {
+	var doc struct {
+		DistTags map[string]string          `json:"dist-tags"`
+		Versions map[string]json.RawMessage `json:"versions"`
+	}
+
+	err := a.get(fmt.Sprintf("%s/%s", strings.TrimSuffix(registryUrl, "/"), url.PathEscape(name)), &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := &registryPackageVersions{latest: doc.DistTags["latest"], versions: map[string]bool{}}
+	for v := range doc.Versions {
+		versions.versions[v] = true
+	}
+
+	return versions, nil
+}
+
+func (a *mirrorFreshnessAnalyzer) lookupPyPI(registryUrl, name string) (*registryPackageVersions, error) {
+	var doc struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Releases map[string]json.RawMessage `json:"releases"`
+	}
+
+	err := a.get(fmt.Sprintf("%s/pypi/%s/json", strings.TrimSuffix(registryUrl, "/"), url.PathEscape(name)), &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := &registryPackageVersions{latest: doc.Info.Version, versions: map[string]bool{}}
+	for v := range doc.Releases {
+		versions.versions[v] = true
+	}
+
+	return versions, nil
+}
+
+func (a *mirrorFreshnessAnalyzer) get(u string, out any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := a.config.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
@@ -0,0 +1,170 @@
+package analyzer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirrorFreshnessAnalyzer(t *testing.T) {
+	registry := func(latest string, versions ...string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			doc := `{"dist-tags": {"latest": "` + latest + `"}, "versions": {`
+			for idx, v := range versions {
+				if idx > 0 {
+					doc += ","
+				}
+
+				doc += `"` + v + `": {}`
+			}
+
+			_, _ = w.Write([]byte(doc + "}}"))
+		}))
+	}
+
+	upstream := registry("1.1.0", "1.0.0", "1.1.0")
+	defer upstream.Close()
+
+	cases := []struct {
+		name   string
+		mirror *httptest.Server
+		stale  bool
+	}{
+		{"Mirror in sync with upstream", registry("1.1.0", "1.0.0", "1.1.0"), false},
+		{"Mirror missing latest version", registry("1.0.0", "1.0.0"), true},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			defer test.mirror.Close()
+
+			a, err := NewMirrorFreshnessAnalyzer(MirrorFreshnessAnalyzerConfig{
+				Mirrors:   map[string]string{models.EcosystemNpm: test.mirror.URL},
+				Upstreams: map[string]string{models.EcosystemNpm: upstream.URL},
+			})
+			assert.Nil(t, err)
+
+			manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+			manifest.AddPackage(&models.Package{
+				PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "test", "1.0.0"),
+			})
+
+			events := []*AnalyzerEvent{}
+			err = a.Analyze(manifest, func(event *AnalyzerEvent) error {
+				events = append(events, event)
+				return nil
+			})
+
+			assert.Nil(t, err)
+			if !test.stale {
+				assert.Empty(t, events)
+				return
+			}
+
+			assert.Len(t, events, 1)
+			assert.Equal(t, "stale-registry-mirror", events[0].Filter.GetName())
+			assert.Equal(t, "1.1.0", events[0].Finding.Remediation.TargetPackageVersion)
+		})
+	}
+}
+
+func TestMirrorFreshnessAnalyzerUnsupportedEcosystem(t *testing.T) {
+	_, err := NewMirrorFreshnessAnalyzer(MirrorFreshnessAnalyzerConfig{
+		Mirrors: map[string]string{models.EcosystemMaven: "https://maven.example.com"},
+	})
+
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestMirrorFreshnessAnalyzerStalledRegistry(t *testing.T) {
+	var requests atomic.Int32
+	stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-r.Context().Done()
+	}))
+	defer stalled.Close()
+
+	a, err := NewMirrorFreshnessAnalyzer(MirrorFreshnessAnalyzerConfig{
+		Mirrors:   map[string]string{models.EcosystemNpm: stalled.URL},
+		Upstreams: map[string]string{models.EcosystemNpm: stalled.URL},
+		Timeout:   100 * time.Millisecond,
+	})
+	assert.Nil(t, err)
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+			manifest.AddPackage(&models.Package{
+				PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "test", "1.0.0"),
+			})
+
+			err := a.Analyze(manifest, func(event *AnalyzerEvent) error {
+				t.Errorf("unexpected event: %s", event.Message)
+				return nil
+			})
+			assert.Nil(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestMirrorFreshnessAnalyzerConcurrentLookups(t *testing.T) {
+	// Each request is held until 4 requests are in flight, hence serial
+	// lookups would time out
+	var inflight atomic.Int32
+	ready := make(chan struct{})
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if inflight.Add(1) == 4 {
+			close(ready)
+		}
+
+		select {
+		case <-ready:
+		case <-r.Context().Done():
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"dist-tags": {"latest": "1.1.0"}, "versions": {"1.0.0": {}}}`))
+	}))
+	defer registry.Close()
+
+	a, err := NewMirrorFreshnessAnalyzer(MirrorFreshnessAnalyzerConfig{
+		Mirrors:     map[string]string{models.EcosystemNpm: registry.URL + "/mirror"},
+		Upstreams:   map[string]string{models.EcosystemNpm: registry.URL + "/upstream"},
+		Timeout:     2 * time.Second,
+		Concurrency: 4,
+	})
+	assert.Nil(t, err)
+
+	manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		manifest.AddPackage(&models.Package{
+			PackageDetails: models.NewPackageDetail(models.EcosystemNpm, name, "1.0.0"),
+		})
+	}
+
+	names := []string{}
+	err = a.Analyze(manifest, func(event *AnalyzerEvent) error {
+		names = append(names, event.Package.GetName())
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, names)
+}
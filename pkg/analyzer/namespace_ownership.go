@@ -0,0 +1,474 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/httpclient"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/readers"
+)
+
+const (
+	namespaceOwnershipAnalyzerName = "NamespaceOwnershipAnalyzer"
+
+	// Change when registry lookup logic changes to invalidate cached results
+	namespaceOwnershipAnalyzerVersion = "2"
+
+	namespaceOwnershipDefaultTimeout = 10 * time.Second
+
+	// Packages of an npm scope considered for its accounts
+	namespaceOwnershipNpmSearchSize = 20
+)
+
+var namespaceOwnershipDefaultRegistries = map[string]string{
+	models.EcosystemNpm:   "https://registry.npmjs.org",
+	models.EcosystemMaven: "https://search.maven.org",
+	models.EcosystemPyPI:  "https://pypi.org",
+}
+
+// InternalNamespace is a namespace claimed as internal by an organization
+// e.g. npm scope `@acme`, Maven groupId `com.acme` or PyPI name prefix `acme-`
+type InternalNamespace struct {
+	Ecosystem string
+	Namespace string
+
+	// Optional, registry accounts (users or organizations) of the
+	// organization. When set, a namespace claimed only by other accounts
+	// is reported. Not supported for Maven since Maven Central does not
+	// expose the account owning a groupId.
+	Owners []string
+}
+
+type NamespaceOwnershipAnalyzerConfig struct {
+	Namespaces []InternalNamespace
+
+	// Optional, base URL of public registry by ecosystem
+	Registries map[string]string
+
+	// Optional HTTP client, defaults to shared client
+	HttpClient *http.Client
+
+	// Optional cache for registry lookup results across runs
+	Cache ResultCache
+
+	// Optional timeout of a registry lookup, defaults to 10s so that a
+	// stalled registry does not hang the scan
+	Timeout time.Duration
+}
+
+// namespaceClaim is a namespace as found in the public registry
+type namespaceClaim struct {
+	Exists bool `json:"exists"`
+
+	// Registry accounts owning or publishing in the namespace
+	Accounts []string `json:"accounts,omitempty"`
+}
+
+func (c *namespaceClaim) addAccount(account string) {
+	if account != "" && !slices.Contains(c.Accounts, account) {
+		c.Accounts = append(c.Accounts, account)
+	}
+}
+
+// ownedBy is true when any account of the claim is one of owners
+func (c *namespaceClaim) ownedBy(owners []string) bool {
+	for _, account := range c.Accounts {
+		for _, owner := range owners {
+			if strings.EqualFold(account, owner) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Lookup of a namespace in the public registry, performed once per scan
+type namespaceLookup struct {
+	once  sync.Once
+	claim *namespaceClaim
+	err   error
+}
+
+type namespaceOwnershipAnalyzer struct {
+	config NamespaceOwnershipAnalyzerConfig
+
+	// Public registry lookups by ecosystem and name
+	m      sync.Mutex
+	claims map[string]*namespaceLookup
+}
+
+// NewNamespaceOwnershipAnalyzer creates an analyzer that verifies internal
+// namespaces are claimed in the public registry. An unclaimed namespace or
+// internal package name can be registered by an attacker for dependency
+// confusion attacks.
+//
+// npm scopes and Maven groupIds are verified at namespace level. PyPI does
+// not have namespaces, hence each package matching the internal prefix is
+// verified. When owners of a namespace are configured, a namespace claimed
+// only by other accounts is reported as well.
+func NewNamespaceOwnershipAnalyzer(config NamespaceOwnershipAnalyzerConfig) (Analyzer, error) {
+	if len(config.Namespaces) == 0 {
+		return nil, errors.New("at least one internal namespace is required")
+	}
+
+	for _, ns := range config.Namespaces {
+		if _, ok := namespaceOwnershipDefaultRegistries[ns.Ecosystem]; !ok {
+			return nil, fmt.Errorf("namespace ownership check is not supported for ecosystem: %s", ns.Ecosystem)
+		}
+
+		if ns.Ecosystem == models.EcosystemMaven && len(ns.Owners) > 0 {
+			return nil, fmt.Errorf("owners of maven namespace %s can not be verified", ns.Namespace)
+		}
+	}
+
+	if config.Registries == nil {
+		config.Registries = map[string]string{}
+	}
+
+	for ecosystem, registry := range namespaceOwnershipDefaultRegistries {
+		if _, ok := config.Registries[ecosystem]; !ok {
+			config.Registries[ecosystem] = registry
+		}
+	}
+
+	if config.HttpClient == nil {
+		config.HttpClient = httpclient.Default()
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = namespaceOwnershipDefaultTimeout
+	}
+
+	return &namespaceOwnershipAnalyzer{
+		config: config,
+		claims: make(map[string]*namespaceLookup),
+	}, nil
+}
+
+// ParseInternalNamespace parses namespace in the form of
+// ecosystem=namespace[:owner,...] e.g. npm=@acme:acme-bot,alice
+func ParseInternalNamespace(s string) (InternalNamespace, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return InternalNamespace{}, fmt.Errorf("invalid internal namespace: %s, expected ecosystem=namespace", s)
+	}
+
+	namespace, owners, _ := strings.Cut(parts[1], ":")
+	if namespace == "" {
+		return InternalNamespace{}, fmt.Errorf("invalid internal namespace: %s, expected ecosystem=namespace", s)
+	}
+
+	ns := InternalNamespace{Namespace: namespace}
+	for _, owner := range strings.Split(owners, ",") {
+		if owner = strings.TrimSpace(owner); owner != "" {
+			ns.Owners = append(ns.Owners, owner)
+		}
+	}
+
+	for ecosystem := range namespaceOwnershipDefaultRegistries {
+		if strings.EqualFold(ecosystem, parts[0]) {
+			ns.Ecosystem = ecosystem
+			return ns, nil
+		}
+	}
+
+	return InternalNamespace{}, fmt.Errorf("namespace ownership check is not supported for ecosystem: %s", parts[0])
+}
+
+func (a *namespaceOwnershipAnalyzer) Name() string {
+	return namespaceOwnershipAnalyzerName
+}
+
+func (a *namespaceOwnershipAnalyzer) Analyze(manifest *models.PackageManifest,
+	handler AnalyzerEventHandler) error {
+	return readers.NewManifestModelReader(manifest).EnumPackages(func(pkg *models.Package) error {
+		ns := a.internalNamespaceOf(pkg)
+		if ns == nil {
+			return nil
+		}
+
+		// Namespace is verified for npm and Maven, package name for PyPI
+		subject := ns.Namespace
+		if ns.Ecosystem == models.EcosystemPyPI {
+			subject = pkg.GetName()
+		}
+
+		claim, err := a.lookup(ns.Ecosystem, subject)
+		if err != nil {
+			logger.Warnf("NamespaceOwnershipAnalyzer: Failed to verify %s/%s: %v", ns.Ecosystem, subject, err)
+			return nil
+		}
+
+		var msg, remediation string
+		switch {
+		case !claim.Exists:
+			msg = fmt.Sprintf("Internal %s namespace `%s` is not claimed in public registry and can be registered by an attacker",
+				ns.Ecosystem, subject)
+			remediation = "Register or reserve the namespace in the public registry"
+		case len(ns.Owners) > 0 && !claim.ownedBy(ns.Owners):
+			msg = fmt.Sprintf("Internal %s namespace `%s` is claimed in public registry by accounts not owned by the organization: %s",
+				ns.Ecosystem, subject, strings.Join(claim.Accounts, ", "))
+			remediation = "Verify the accounts publishing in the namespace or pin the internal registry for the namespace"
+		default:
+			return nil
+		}
+
+		return handler(&AnalyzerEvent{
+			Source:   a.Name(),
+			Type:     ET_NamespaceOwnershipSignal,
+			Message:  msg,
+			Manifest: manifest,
+			Package:  pkg,
+			Finding: &Finding{
+				Kind:       FindingKindThreat,
+				Severity:   FindingSeverityHigh,
+				Confidence: FindingConfidenceMedium,
+				Title:      msg,
+				Manifest:   manifest,
+				Package:    pkg,
+				Evidences: []FindingEvidence{
+					{Type: "url", Summary: a.config.Registries[ns.Ecosystem]},
+				},
+				Remediation: &FindingRemediation{
+					Summary: remediation,
+				},
+			},
+		})
+	})
+}
+
+func (a *namespaceOwnershipAnalyzer) Finish() error {
+	return nil
+}
+
+func (a *namespaceOwnershipAnalyzer) internalNamespaceOf(pkg *models.Package) *InternalNamespace {
+	for idx := range a.config.Namespaces {
+		ns := &a.config.Namespaces[idx]
+		if !strings.EqualFold(ns.Ecosystem, string(pkg.Ecosystem)) {
+			continue
+		}
+
+		name := strings.ToLower(pkg.GetName())
+		prefix := strings.ToLower(ns.Namespace)
+
+		switch ns.Ecosystem {
+		case models.EcosystemNpm:
+			if strings.HasPrefix(name, strings.TrimSuffix(prefix, "/")+"/") {
+				return ns
+			}
+		case models.EcosystemMaven:
+			if strings.HasPrefix(name, prefix+":") {
+				return ns
+			}
+		default:
+			if strings.HasPrefix(name, prefix) {
+				return ns
+			}
+		}
+	}
+
+	return nil
+}
+
+// lookup returns the claim of subject in the public registry. Lookups are
+// performed once per scan, concurrent lookups of the same subject wait for
+// the first one without blocking lookups of other subjects.
+func (a *namespaceOwnershipAnalyzer) lookup(ecosystem, subject string) (*namespaceClaim, error) {
+	key := fmt.Sprintf("%s/%s", ecosystem, strings.ToLower(subject))
+
+	a.m.Lock()
+	entry, ok := a.claims[key]
+	if !ok {
+		entry = &namespaceLookup{}
+		a.claims[key] = entry
+	}
+	a.m.Unlock()
+
+	entry.once.Do(func() {
+		entry.claim, entry.err = a.cachedLookup(ecosystem, subject)
+	})
+
+	return entry.claim, entry.err
+}
+
+func (a *namespaceOwnershipAnalyzer) cachedLookup(ecosystem, subject string) (*namespaceClaim, error) {
+	cacheKey := ResultCacheKey{
+		Analyzer:        namespaceOwnershipAnalyzerName,
+		AnalyzerVersion: namespaceOwnershipAnalyzerVersion,
+		Ecosystem:       ecosystem,
+		Name:            strings.ToLower(subject),
+	}
+
+	if a.config.Cache != nil {
+		var cached namespaceClaim
+		found, err := a.config.Cache.Get(cacheKey, &cached)
+		if err != nil {
+			logger.Debugf("NamespaceOwnershipAnalyzer: Failed to read cache for %s/%s: %v", ecosystem, subject, err)
+		} else if found {
+			return &cached, nil
+		}
+	}
+
+	registry := strings.TrimSuffix(a.config.Registries[ecosystem], "/")
+
+	var claim *namespaceClaim
+	var err error
+
+	switch ecosystem {
+	case models.EcosystemNpm:
+		claim, err = a.lookupNpm(registry, subject)
+	case models.EcosystemMaven:
+		claim, err = a.lookupMaven(registry, subject)
+	case models.EcosystemPyPI:
+		claim, err = a.lookupPyPI(registry, subject)
+	default:
+		err = fmt.Errorf("unsupported ecosystem: %s", ecosystem)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Only a claimed namespace is cached. An unclaimed namespace is a finding
+	// and must be verified again since it can be registered anytime.
+	if claim.Exists && a.config.Cache != nil {
+		if err := a.config.Cache.Put(cacheKey, claim); err != nil {
+			logger.Debugf("NamespaceOwnershipAnalyzer: Failed to cache result for %s/%s: %v", ecosystem, subject, err)
+		}
+	}
+
+	return claim, nil
+}
+
+// lookupNpm considers the maintainers and publishers of packages in the
+// scope as its accounts. A scope is claimed when at least one package is
+// published in it.
+func (a *namespaceOwnershipAnalyzer) lookupNpm(registry, scope string) (*namespaceClaim, error) {
+	type npmUser struct {
+		Username string `json:"username"`
+	}
+
+	var res struct {
+		Total   int `json:"total"`
+		Objects []struct {
+			Package struct {
+				Publisher   npmUser   `json:"publisher"`
+				Maintainers []npmUser `json:"maintainers"`
+			} `json:"package"`
+		} `json:"objects"`
+	}
+
+	query := url.Values{}
+	query.Set("text", "scope:"+strings.TrimPrefix(scope, "@"))
+	query.Set("size", strconv.Itoa(namespaceOwnershipNpmSearchSize))
+
+	_, err := a.get(registry+"/-/v1/search?"+query.Encode(), &res)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := &namespaceClaim{Exists: res.Total > 0}
+	for _, obj := range res.Objects {
+		claim.addAccount(obj.Package.Publisher.Username)
+		for _, m := range obj.Package.Maintainers {
+			claim.addAccount(m.Username)
+		}
+	}
+
+	return claim, nil
+}
+
+// lookupMaven verifies that the groupId is published. Maven Central
+// verifies ownership of a groupId on registration but does not expose
+// the owning account.
+func (a *namespaceOwnershipAnalyzer) lookupMaven(registry, groupId string) (*namespaceClaim, error) {
+	var res struct {
+		Response struct {
+			NumFound int `json:"numFound"`
+		} `json:"response"`
+	}
+
+	query := url.Values{}
+	query.Set("q", "g:"+groupId)
+	query.Set("rows", "1")
+	query.Set("wt", "json")
+
+	_, err := a.get(registry+"/solrsearch/select?"+query.Encode(), &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &namespaceClaim{Exists: res.Response.NumFound > 0}, nil
+}
+
+// lookupPyPI uses the ownership of the project i.e. its organization and
+// users with a role as its accounts
+func (a *namespaceOwnershipAnalyzer) lookupPyPI(registry, name string) (*namespaceClaim, error) {
+	var res struct {
+		Ownership struct {
+			Organization string `json:"organization"`
+			Roles        []struct {
+				User string `json:"user"`
+			} `json:"roles"`
+		} `json:"ownership"`
+	}
+
+	found, err := a.get(fmt.Sprintf("%s/pypi/%s/json", registry, url.PathEscape(name)), &res)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := &namespaceClaim{Exists: found}
+	claim.addAccount(res.Ownership.Organization)
+	for _, role := range res.Ownership.Roles {
+		claim.addAccount(role.User)
+	}
+
+	return claim, nil
+}
+
+// get returns false without error when the resource is not found
+func (a *namespaceOwnershipAnalyzer) get(u string, out any) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := a.config.HttpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	if out == nil {
+		return true, nil
+	}
+
+	return true, json.NewDecoder(res.Body).Decode(out)
+}
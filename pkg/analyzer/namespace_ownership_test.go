@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInternalNamespace(t *testing.T) {
+	ns, err := ParseInternalNamespace("pypi=acme-")
+	assert.Nil(t, err)
+	assert.Equal(t, InternalNamespace{Ecosystem: models.EcosystemPyPI, Namespace: "acme-"}, ns)
+
+	ns, err = ParseInternalNamespace("npm=@acme:acme-bot, alice")
+	assert.Nil(t, err)
+	assert.Equal(t, InternalNamespace{Ecosystem: models.EcosystemNpm, Namespace: "@acme",
+		Owners: []string{"acme-bot", "alice"}}, ns)
+
+	_, err = ParseInternalNamespace("acme")
+	assert.ErrorContains(t, err, "expected ecosystem=namespace")
+
+	_, err = ParseInternalNamespace("npm=:alice")
+	assert.ErrorContains(t, err, "expected ecosystem=namespace")
+
+	_, err = ParseInternalNamespace("cargo=acme")
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestNewNamespaceOwnershipAnalyzerMavenOwners(t *testing.T) {
+	_, err := NewNamespaceOwnershipAnalyzer(NamespaceOwnershipAnalyzerConfig{
+		Namespaces: []InternalNamespace{{Ecosystem: models.EcosystemMaven, Namespace: "com.acme",
+			Owners: []string{"acme"}}},
+	})
+	assert.ErrorContains(t, err, "can not be verified")
+}
+
+func TestNamespaceOwnershipAnalyzer(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.RawQuery, "scope%3Aclaimed"):
+			_, _ = w.Write([]byte(`{"total": 2, "objects": [
+				{"package": {"publisher": {"username": "acme-bot"}, "maintainers": [{"username": "alice"}]}},
+				{"package": {"publisher": {"username": "acme-bot"}, "maintainers": [{"username": "bob"}]}}
+			]}`))
+		case r.URL.Path == "/-/v1/search":
+			_, _ = w.Write([]byte(`{"total": 0, "objects": []}`))
+		case r.URL.Path == "/pypi/acme-claimed/json":
+			_, _ = w.Write([]byte(`{"ownership": {"organization": "acme", "roles": [{"role": "Owner", "user": "alice"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer registry.Close()
+
+	cases := []struct {
+		name      string
+		ecosystem string
+		namespace string
+		owners    []string
+		pkgName   string
+		alert     string
+	}{
+		{"npm scope claimed", models.EcosystemNpm, "@claimed", nil, "@claimed/utils", ""},
+		{"npm scope claimed by owner", models.EcosystemNpm, "@claimed", []string{"ACME-BOT"}, "@claimed/utils", ""},
+		{"npm scope claimed by others", models.EcosystemNpm, "@claimed", []string{"acme"}, "@claimed/utils",
+			"not owned by the organization: acme-bot, alice, bob"},
+		{"npm scope not claimed", models.EcosystemNpm, "@acme", nil, "@acme/utils", "is not claimed"},
+		{"npm package outside scope", models.EcosystemNpm, "@acme", nil, "lodash", ""},
+		{"PyPI name claimed", models.EcosystemPyPI, "acme-", nil, "acme-claimed", ""},
+		{"PyPI name claimed by owner organization", models.EcosystemPyPI, "acme-", []string{"acme"}, "acme-claimed", ""},
+		{"PyPI name claimed by others", models.EcosystemPyPI, "acme-", []string{"acme-corp"}, "acme-claimed",
+			"not owned by the organization: acme, alice"},
+		{"PyPI name not claimed", models.EcosystemPyPI, "acme-", nil, "acme-internal", "is not claimed"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			a, err := NewNamespaceOwnershipAnalyzer(NamespaceOwnershipAnalyzerConfig{
+				Namespaces: []InternalNamespace{{Ecosystem: test.ecosystem, Namespace: test.namespace,
+					Owners: test.owners}},
+				Registries: map[string]string{test.ecosystem: registry.URL},
+			})
+			assert.Nil(t, err)
+
+			manifest := models.NewPackageManifestFromLocal("manifest", test.ecosystem)
+			manifest.AddPackage(&models.Package{
+				PackageDetails: models.NewPackageDetail(test.ecosystem, test.pkgName, "1.0.0"),
+			})
+
+			events := []*AnalyzerEvent{}
+			err = a.Analyze(manifest, func(event *AnalyzerEvent) error {
+				events = append(events, event)
+				return nil
+			})
+
+			assert.Nil(t, err)
+			if test.alert != "" {
+				assert.Len(t, events, 1)
+				assert.True(t, events[0].IsNamespaceOwnershipSignal())
+				assert.Contains(t, events[0].Message, test.alert)
+			} else {
+				assert.Empty(t, events)
+			}
+		})
+	}
+}
+
+func TestNamespaceOwnershipAnalyzerStalledRegistry(t *testing.T) {
+	var requests atomic.Int32
+	stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-r.Context().Done()
+	}))
+	defer stalled.Close()
+
+	a, err := NewNamespaceOwnershipAnalyzer(NamespaceOwnershipAnalyzerConfig{
+		Namespaces: []InternalNamespace{{Ecosystem: models.EcosystemNpm, Namespace: "@acme"}},
+		Registries: map[string]string{models.EcosystemNpm: stalled.URL},
+		Timeout:    100 * time.Millisecond,
+	})
+	assert.Nil(t, err)
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+			manifest.AddPackage(&models.Package{
+				PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "@acme/utils", "1.0.0"),
+			})
+
+			err := a.Analyze(manifest, func(event *AnalyzerEvent) error {
+				t.Errorf("unexpected event: %s", event.Message)
+				return nil
+			})
+			assert.Nil(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(1), requests.Load())
+}
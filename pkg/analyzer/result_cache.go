@@ -0,0 +1,196 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/encryption"
+	"github.com/safedep/vet/pkg/common/paths"
+)
+
+const (
+	resultCacheDirName    = "vet-cache/analyzers"
+	resultCacheDefaultTTL = 24 * time.Hour
+)
+
+// ResultCacheKey identifies the result of an analyzer for a subject. The
+// analyzer version must be changed whenever the logic producing the result
+// changes so that stale results are not used.
+type ResultCacheKey struct {
+	Analyzer        string
+	AnalyzerVersion string
+	Ecosystem       string
+	Name            string
+	Version         string
+}
+
+func (k ResultCacheKey) String() string {
+	return fmt.Sprintf("%s@%s/%s/%s@%s", k.Analyzer, k.AnalyzerVersion,
+		strings.ToLower(k.Ecosystem), k.Name, k.Version)
+}
+
+// ResultCache persists deterministic analyzer results across runs. Only
+// results which are costly to compute e.g. registry lookups of the
+// namespace ownership analyzer are worth caching. Cheap results such as
+// parsed license expressions are faster to compute than to read from disk.
+type ResultCache interface {
+	// Get decodes the cached result into out and returns false on miss
+	Get(key ResultCacheKey, out any) (bool, error)
+	Put(key ResultCacheKey, value any) error
+}
+
+type FileResultCacheConfig struct {
+	Dir string
+
+	// Optional, results older than TTL are ignored
+	TTL time.Duration
+
+	// Optional, entries are encrypted when available
+	Cipher encryption.Cipher
+
+	// Optional, used for testing
+	Now func() time.Time
+}
+
+type fileResultCache struct {
+	config FileResultCacheConfig
+}
+
+type resultCacheEntry struct {
+	Key       string          `json:"key"`
+	CreatedAt time.Time       `json:"created_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// DefaultFileResultCacheConfig stores results in the cache directory
+func DefaultFileResultCacheConfig() (FileResultCacheConfig, error) {
+	dir, err := paths.Resolve(paths.KindCache, resultCacheDirName)
+	if err != nil {
+		return FileResultCacheConfig{}, err
+	}
+
+	return FileResultCacheConfig{
+		Dir: dir,
+		TTL: resultCacheDefaultTTL,
+	}, nil
+}
+
+// NewFileResultCache creates a cache storing each result as a JSON file
+func NewFileResultCache(config FileResultCacheConfig) (ResultCache, error) {
+	if config.Dir == "" {
+		return nil, errors.New("result cache directory is required")
+	}
+
+	if config.TTL == 0 {
+		config.TTL = resultCacheDefaultTTL
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &fileResultCache{config: config}, nil
+}
+
+func (c *fileResultCache) Get(key ResultCacheKey, out any) (bool, error) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to read result cache: %w", err)
+	}
+
+	data, err = encryption.Open(c.config.Cipher, data)
+	if err != nil {
+		// Encrypted entry is of no use without the key and a plaintext entry
+		// is not trusted when a key is configured, compute again
+		if errors.Is(err, encryption.ErrNoKey) || errors.Is(err, encryption.ErrNotEncrypted) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to decrypt result cache: %w", err)
+	}
+
+	var entry resultCacheEntry
+	err = json.Unmarshal(data, &entry)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse result cache: %w", err)
+	}
+
+	// Guard against hash collision and entries from an older format
+	if entry.Key != key.String() {
+		return false, nil
+	}
+
+	if c.config.Now().Sub(entry.CreatedAt) > c.config.TTL {
+		return false, nil
+	}
+
+	err = json.Unmarshal(entry.Value, out)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode cached result: %w", err)
+	}
+
+	return true, nil
+}
+
+func (c *fileResultCache) Put(key ResultCacheKey, value any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	data, err := json.Marshal(resultCacheEntry{
+		Key:       key.String(),
+		CreatedAt: c.config.Now(),
+		Value:     encoded,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err = encryption.Seal(c.config.Cipher, data)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt result cache entry: %w", err)
+	}
+
+	path := c.path(key)
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create result cache directory: %w", err)
+	}
+
+	// Write and rename so that concurrent readers never see partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return fmt.Errorf("failed to create result cache entry: %w", err)
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write result cache entry: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Entries are grouped by analyzer so that a cache can be cleared
+// selectively by removing the directory
+func (c *fileResultCache) path(key ResultCacheKey) string {
+	digest := sha256.Sum256([]byte(key.String()))
+	return filepath.Join(c.config.Dir, key.Analyzer, hex.EncodeToString(digest[:])+".json")
+}
@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/encryption"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileResultCache(t *testing.T) {
+	now := time.Now()
+	cache, err := NewFileResultCache(FileResultCacheConfig{
+		Dir: t.TempDir(),
+		TTL: time.Hour,
+		Now: func() time.Time { return now },
+	})
+	assert.Nil(t, err)
+
+	key := ResultCacheKey{
+		Analyzer:        "TestAnalyzer",
+		AnalyzerVersion: "1",
+		Ecosystem:       "npm",
+		Name:            "lodash",
+		Version:         "4.17.21",
+	}
+
+	var value []string
+	found, err := cache.Get(key, &value)
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	assert.Nil(t, cache.Put(key, []string{"MIT"}))
+
+	found, err = cache.Get(key, &value)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []string{"MIT"}, value)
+
+	cases := []struct {
+		name  string
+		key   ResultCacheKey
+		after time.Duration
+		found bool
+	}{
+		{"same key", key, 0, true},
+		{"analyzer version changed", ResultCacheKey{
+			Analyzer: key.Analyzer, AnalyzerVersion: "2",
+			Ecosystem: key.Ecosystem, Name: key.Name, Version: key.Version,
+		}, 0, false},
+		{"package version changed", ResultCacheKey{
+			Analyzer: key.Analyzer, AnalyzerVersion: key.AnalyzerVersion,
+			Ecosystem: key.Ecosystem, Name: key.Name, Version: "4.17.20",
+		}, 0, false},
+		{"expired", key, 2 * time.Hour, false},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			now = time.Now().Add(test.after)
+
+			var v []string
+			found, err := cache.Get(test.key, &v)
+			assert.Nil(t, err)
+			assert.Equal(t, test.found, found)
+		})
+	}
+}
+
+func TestNewFileResultCacheRequiresDir(t *testing.T) {
+	_, err := NewFileResultCache(FileResultCacheConfig{})
+	assert.ErrorContains(t, err, "directory is required")
+}
+
+func TestFileResultCacheEncryption(t *testing.T) {
+	cipher, err := encryption.NewAesGcmCipher(make([]byte, encryption.KeySize))
+	assert.Nil(t, err)
+
+	dir := t.TempDir()
+	encrypted, err := NewFileResultCache(FileResultCacheConfig{Dir: dir, Cipher: cipher})
+	assert.Nil(t, err)
+
+	key := ResultCacheKey{Analyzer: "TestAnalyzer", AnalyzerVersion: "1",
+		Ecosystem: "npm", Name: "@acme/internal", Version: "1.0.0"}
+	assert.Nil(t, encrypted.Put(key, []string{"MIT"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, key.Analyzer, "*.json"))
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	assert.Nil(t, err)
+	assert.True(t, encryption.IsEncrypted(data))
+	assert.NotContains(t, string(data), "@acme/internal")
+
+	var value []string
+	found, err := encrypted.Get(key, &value)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []string{"MIT"}, value)
+
+	// Encrypted entry is a miss without the key
+	plain, err := NewFileResultCache(FileResultCacheConfig{Dir: dir})
+	assert.Nil(t, err)
+
+	found, err = plain.Get(key, &value)
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	// Plaintext entry is a miss when a key is configured
+	assert.Nil(t, plain.Put(key, []string{"GPL-3.0"}))
+
+	found, err = encrypted.Get(key, &value)
+	assert.Nil(t, err)
+	assert.False(t, found)
+}
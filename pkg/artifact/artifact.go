@@ -0,0 +1,396 @@
+// Package artifact implements reading and comparing the published
+// artifacts (e.g. npm tarball) of package versions to support review
+// of suspicious version bumps.
+package artifact
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/flate"
+	"github.com/klauspost/compress/gzip"
+	"github.com/safedep/vet/pkg/common/concurrency"
+	"github.com/safedep/vet/pkg/common/resources"
+	"golang.org/x/exp/mmap"
+)
+
+const (
+	// Number of bytes inspected to classify a file as binary
+	binaryDetectionSize = 8000
+
+	// Max size of package.json read for install scripts
+	npmPackageJsonMaxSize = 1024 * 1024
+)
+
+// Well known files executed during package installation
+var installScriptFiles = map[string]bool{
+	"setup.py":       true,
+	"install.js":     true,
+	"preinstall.js":  true,
+	"postinstall.js": true,
+	"install.sh":     true,
+	"binding.gyp":    true,
+}
+
+// Top level directory of files in npm tarballs
+const NpmArchiveRoot = "package"
+
+// Lifecycle scripts of npm that are executed during installation
+var npmInstallScripts = []string{"preinstall", "install", "postinstall", "prepare"}
+
+// File is an entry in the file tree of an artifact
+type File struct {
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+	Sha256     string `json:"sha256"`
+	Executable bool   `json:"executable"`
+	Binary     bool   `json:"binary"`
+}
+
+// Artifact is the file tree of a published package version along with
+// the install scripts declared in package metadata
+type Artifact struct {
+	Files map[string]File
+
+	// Install script name => content e.g. postinstall => node setup.js
+	InstallScripts map[string]string
+}
+
+func newArtifact() *Artifact {
+	return &Artifact{
+		Files:          map[string]File{},
+		InstallScripts: map[string]string{},
+	}
+}
+
+// ReadTarGz reads an artifact from an npm tarball
+func ReadTarGz(reader io.Reader) (*Artifact, error) {
+	return ReadTarGzWithLimits(reader, NpmArchiveRoot, DefaultLimits())
+}
+
+// ReadTarGzWithLimits reads an artifact from a gzip compressed tarball.
+// The root directory (e.g. package/ of npm) is removed from file paths.
+// The tarball is streamed and only the file metadata is kept in memory.
+// Decompression uses an optimized implementation of gzip, which is several
+// times faster than the standard library on arm64.
+func ReadTarGzWithLimits(reader io.Reader, root string, limits Limits) (*Artifact, error) {
+	limits = limits.withDefaults()
+
+	gz, err := gzip.NewReader(newCappedReader(reader, limits.MaxArchiveSize, "archive size"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+
+	defer gz.Close()
+
+	artifact := newArtifact()
+	tr := tar.NewReader(newCappedReader(gz, limits.MaxUncompressedSize, "uncompressed size"))
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, fmt.Errorf("failed to read tar entry: %w", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if len(artifact.Files) >= limits.MaxFiles {
+			return nil, fmt.Errorf("%w: more than %d files", ErrLimitExceeded, limits.MaxFiles)
+		}
+
+		file, head, err := readFile(normalizePath(hdr.Name, root), hdr.FileInfo().Mode().Perm()&0111 != 0, tr, limits.MaxFileSize)
+		if err != nil {
+			return nil, err
+		}
+
+		artifact.add(file, head)
+	}
+
+	return artifact, nil
+}
+
+// ReadZip reads an artifact from a zip archive without a root directory
+// e.g. Python wheel
+func ReadZip(reader io.Reader) (*Artifact, error) {
+	return ReadZipWithLimits(reader, "", DefaultLimits())
+}
+
+// ReadZipWithLimits reads an artifact from a zip archive. Zip requires
+// random access, hence the archive is spooled to a temporary file instead
+// of memory and read using ReadZipFile.
+func ReadZipWithLimits(reader io.Reader, root string, limits Limits) (*Artifact, error) {
+	limits = limits.withDefaults()
+
+	// Spooled archives are charged to the temporary disk limit
+	file, err := resources.CreateTemp("vet-artifact-*.zip")
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Remove()
+
+	_, err = io.Copy(file, newCappedReader(reader, limits.MaxArchiveSize, "archive size"))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zip archive: %w", err)
+	}
+
+	return ReadZipFile(file.Name(), root, limits)
+}
+
+// ReadZipFile reads an artifact from a zip archive on disk. The archive
+// is memory mapped so that it is paged in by the OS on demand. Entries are
+// decompressed and hashed in parallel since zip allows random access.
+// The root directory, when not empty, is removed from file paths.
+func ReadZipFile(path, root string, limits Limits) (*Artifact, error) {
+	limits = limits.withDefaults()
+
+	mapped, err := mmap.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip archive: %w", err)
+	}
+
+	defer mapped.Close()
+
+	if int64(mapped.Len()) > limits.MaxArchiveSize {
+		return nil, fmt.Errorf("%w: archive size", ErrLimitExceeded)
+	}
+
+	zr, err := zip.NewReader(mapped, int64(mapped.Len()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip archive: %w", err)
+	}
+
+	zr.RegisterDecompressor(zip.Deflate, flate.NewReader)
+
+	if len(zr.File) > limits.MaxFiles {
+		return nil, fmt.Errorf("%w: more than %d files", ErrLimitExceeded, limits.MaxFiles)
+	}
+
+	entries := []*zip.File{}
+	for _, zf := range zr.File {
+		if !zf.FileInfo().IsDir() {
+			entries = append(entries, zf)
+		}
+	}
+
+	files, heads, err := readZipEntries(entries, root, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	// Files are added in archive order so that the last of duplicate
+	// entries wins irrespective of the order of reading
+	artifact := newArtifact()
+	for i := range files {
+		artifact.add(files[i], heads[i])
+	}
+
+	return artifact, nil
+}
+
+// readZipEntries reads the entries using a pool of workers. Reading stops
+// on the first failure.
+func readZipEntries(entries []*zip.File, root string, limits Limits) ([]File, [][]byte, error) {
+	files := make([]File, len(entries))
+	heads := make([][]byte, len(entries))
+
+	// Declared sizes can not be trusted, actual sizes are capped while reading
+	var uncompressed atomic.Int64
+	uncompressed.Store(limits.MaxUncompressedSize)
+
+	var (
+		failed   atomic.Bool
+		errOnce  sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			failed.Store(true)
+		})
+	}
+
+	indices := make(chan int)
+	workers := min(concurrency.For(concurrency.SubsystemArtifact).Workers, max(len(entries), 1))
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				if failed.Load() {
+					continue
+				}
+
+				zf := entries[i]
+				rc, err := zf.Open()
+				if err != nil {
+					fail(fmt.Errorf("failed to open zip entry: %w", err))
+					continue
+				}
+
+				reader := &budgetReader{reader: rc, remaining: &uncompressed, what: "uncompressed size"}
+				files[i], heads[i], err = readFile(normalizePath(zf.Name, root), zf.Mode().Perm()&0111 != 0, reader, limits.MaxFileSize)
+				rc.Close()
+
+				if err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	for i := range entries {
+		indices <- i
+	}
+
+	close(indices)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+
+	return files, heads, nil
+}
+
+// readFile hashes the file and returns the content of package.json used
+// for reading install scripts
+func readFile(filePath string, executable bool, reader io.Reader, maxSize int64) (File, []byte, error) {
+	limit := binaryDetectionSize
+	if filePath == "package.json" {
+		limit = npmPackageJsonMaxSize
+	}
+
+	var head bytes.Buffer
+	hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(hash, &limitedBuffer{buf: &head, limit: limit}),
+		newCappedReader(reader, maxSize, "file size of "+filePath))
+	if err != nil {
+		return File{}, nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	file := File{
+		Path:       filePath,
+		Size:       size,
+		Sha256:     hex.EncodeToString(hash.Sum(nil)),
+		Executable: executable,
+		Binary:     isBinary(head.Bytes()[:min(head.Len(), binaryDetectionSize)]),
+	}
+
+	// Head of other files is not kept since entries of a zip archive are
+	// held in memory till all are read
+	if filePath != "package.json" {
+		return file, nil, nil
+	}
+
+	return file, head.Bytes(), nil
+}
+
+func (a *Artifact) add(file File, head []byte) {
+	a.Files[file.Path] = file
+
+	if file.Path == "package.json" {
+		a.readNpmInstallScripts(head, file.Size)
+	}
+}
+
+// Install scripts are not read from unusually large package.json
+func (a *Artifact) readNpmInstallScripts(data []byte, size int64) {
+	if int64(len(data)) != size {
+		return
+	}
+
+	var pkgJson struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+
+	if json.Unmarshal(data, &pkgJson) != nil {
+		return
+	}
+
+	for _, name := range npmInstallScripts {
+		if script, ok := pkgJson.Scripts[name]; ok {
+			a.InstallScripts[name] = script
+		}
+	}
+}
+
+// Archives of npm and PyPI sdist have a top level directory e.g. package/
+// which is removed so that paths are comparable across versions. Only the
+// known root is removed since other archives e.g. wheels have top level
+// directories like name-1.0.dist-info/ which are part of the file tree.
+func normalizePath(name, root string) string {
+	name = path.Clean(strings.TrimPrefix(name, "./"))
+	if root == "" {
+		return name
+	}
+
+	if rest, ok := strings.CutPrefix(name, root+"/"); ok {
+		return rest
+	}
+
+	return name
+}
+
+func isBinary(head []byte) bool {
+	for _, magic := range [][]byte{
+		[]byte("\x7fELF"),
+		[]byte("MZ"),
+		{0xcf, 0xfa, 0xed, 0xfe},
+		{0xca, 0xfe, 0xba, 0xbe},
+		[]byte("\x00asm"),
+	} {
+		if bytes.HasPrefix(head, magic) {
+			return true
+		}
+	}
+
+	return bytes.IndexByte(head, 0) >= 0
+}
+
+func isInstallScriptFile(filePath string) bool {
+	return installScriptFiles[path.Base(filePath)]
+}
+
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - b.buf.Len()
+	if remaining > 0 {
+		if len(p) < remaining {
+			remaining = len(p)
+		}
+
+		b.buf.Write(p[:remaining])
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,218 @@
+package artifact
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/safedep/vet/pkg/common/resources"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tarGzArchive(t testing.TB, files map[string]string) []byte {
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     "package/" + name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+func zipArchive(t testing.TB, files map[string]string) []byte {
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestReadWithLimits(t *testing.T) {
+	files := map[string]string{
+		"index.js":  "module.exports = 1",
+		"large.bin": strings.Repeat("A", 4096),
+	}
+
+	cases := []struct {
+		name   string
+		limits Limits
+		err    bool
+	}{
+		{"default limits", Limits{}, false},
+		{"file size exceeded", Limits{MaxFileSize: 1024}, true},
+		{"file size at limit", Limits{MaxFileSize: 4096}, false},
+		{"uncompressed size exceeded", Limits{MaxUncompressedSize: 2048}, true},
+		{"file count exceeded", Limits{MaxFiles: 1}, true},
+		{"archive size exceeded", Limits{MaxArchiveSize: 16}, true},
+	}
+
+	readers := map[string]func(io.Reader, Limits) (*Artifact, error){
+		"tar.gz": func(r io.Reader, limits Limits) (*Artifact, error) {
+			return ReadTarGzWithLimits(r, NpmArchiveRoot, limits)
+		},
+		"zip": func(r io.Reader, limits Limits) (*Artifact, error) {
+			return ReadZipWithLimits(r, "", limits)
+		},
+	}
+
+	archives := map[string][]byte{
+		"tar.gz": tarGzArchive(t, files),
+		"zip":    zipArchive(t, files),
+	}
+
+	for format, read := range readers {
+		for _, test := range cases {
+			t.Run(format+"/"+test.name, func(t *testing.T) {
+				artifact, err := read(bytes.NewReader(archives[format]), test.limits)
+				if test.err {
+					assert.ErrorIs(t, err, ErrLimitExceeded)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.Len(t, artifact.Files, 2)
+				assert.Equal(t, int64(4096), artifact.Files["large.bin"].Size)
+			})
+		}
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	cases := []struct {
+		name, path, root, expected string
+	}{
+		{"npm root", "package/lib/index.js", NpmArchiveRoot, "lib/index.js"},
+		{"npm root with dot", "./package/index.js", NpmArchiveRoot, "index.js"},
+		{"sdist root", "requests-2.31.0/setup.py", "requests-2.31.0", "setup.py"},
+		{"wheel dist-info is kept", "requests-2.31.0.dist-info/RECORD", "", "requests-2.31.0.dist-info/RECORD"},
+		{"wheel package is kept", "my-pkg/__init__.py", "", "my-pkg/__init__.py"},
+		{"other directory is kept", "node-gyp/build.js", NpmArchiveRoot, "node-gyp/build.js"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, normalizePath(test.path, test.root))
+		})
+	}
+}
+
+func TestSdistArchiveRoot(t *testing.T) {
+	assert.Equal(t, "requests-2.31.0",
+		sdistArchiveRoot("https://files.pythonhosted.org/packages/9d/be/requests-2.31.0.tar.gz?x=1"))
+	assert.Equal(t, "my_pkg-1.0", sdistArchiveRoot("my_pkg-1.0.tar.gz"))
+}
+
+func TestCappedReader(t *testing.T) {
+	data, err := io.ReadAll(newCappedReader(strings.NewReader("abcd"), 4, "test"))
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", string(data))
+
+	_, err = io.ReadAll(newCappedReader(strings.NewReader("abcde"), 4, "test"))
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+}
+
+func TestReadWithResourceLimits(t *testing.T) {
+	t.Cleanup(func() { resources.Configure(resources.DefaultLimits()) })
+
+	archive := zipArchive(t, map[string]string{"large.bin": strings.Repeat("x", 4096)})
+
+	resources.Configure(resources.Limits{MaxExtractedSize: 1024})
+
+	_, err := ReadZip(bytes.NewReader(archive))
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	resources.Configure(resources.Limits{MaxTempDiskSize: 16})
+
+	_, err = ReadZip(bytes.NewReader(archive))
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	assert.Equal(t, int64(0), resources.TempDiskUsage())
+
+	resources.Configure(resources.DefaultLimits())
+
+	artifact, err := ReadZip(bytes.NewReader(archive))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4096), artifact.Files["large.bin"].Size)
+}
+
+func TestReadZipParallel(t *testing.T) {
+	files := map[string]string{
+		"package.json": `{"scripts":{"postinstall":"node setup.js"}}`,
+		"bin/tool":     "\x7fELF",
+	}
+
+	for i := range 200 {
+		files[fmt.Sprintf("lib/file-%d.js", i)] = strings.Repeat("x", i)
+	}
+
+	fromZip, err := ReadZip(bytes.NewReader(zipArchive(t, files)))
+	require.NoError(t, err)
+
+	fromTarGz, err := ReadTarGz(bytes.NewReader(tarGzArchive(t, files)))
+	require.NoError(t, err)
+
+	assert.Len(t, fromZip.Files, len(files))
+	assert.Equal(t, map[string]string{"postinstall": "node setup.js"}, fromZip.InstallScripts)
+	assert.True(t, fromZip.Files["bin/tool"].Binary)
+
+	for name, file := range fromTarGz.Files {
+		assert.Equal(t, file.Sha256, fromZip.Files[name].Sha256, name)
+	}
+}
+
+// Compare results across architectures using
+// go test -bench ReadArchive ./pkg/artifact/
+func BenchmarkReadArchive(b *testing.B) {
+	files := map[string]string{}
+	for i := range 500 {
+		files[fmt.Sprintf("lib/file-%d.js", i)] = strings.Repeat(fmt.Sprintf("line %d\n", i), 2000)
+	}
+
+	archives := map[string][]byte{
+		"tar.gz": tarGzArchive(b, files),
+		"zip":    zipArchive(b, files),
+	}
+
+	readers := map[string]func(io.Reader) (*Artifact, error){
+		"tar.gz": ReadTarGz,
+		"zip":    ReadZip,
+	}
+
+	for format, read := range readers {
+		b.Run(format, func(b *testing.B) {
+			b.SetBytes(int64(len(archives[format])))
+
+			for range b.N {
+				if _, err := read(bytes.NewReader(archives[format])); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
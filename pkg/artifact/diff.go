@@ -0,0 +1,145 @@
+package artifact
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/safedep/vet/pkg/analyzer"
+)
+
+// InstallScriptChange is an install script added, removed or modified
+// between two versions. Empty content means the script is absent.
+type InstallScriptChange struct {
+	Name string `json:"name"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// Diff is the structured difference between artifacts of two versions
+type Diff struct {
+	Added    []File `json:"added"`
+	Removed  []File `json:"removed"`
+	Modified []File `json:"modified"`
+
+	// Binary files that are added or modified
+	Binaries []File `json:"binaries"`
+
+	// Install script files (e.g. setup.py) added or modified
+	InstallScriptFiles []File `json:"install_script_files"`
+
+	// Install scripts declared in package metadata
+	InstallScripts []InstallScriptChange `json:"install_scripts"`
+}
+
+// Compare returns the difference from base to target artifact
+func Compare(base, target *Artifact) *Diff {
+	diff := &Diff{
+		Added:              []File{},
+		Removed:            []File{},
+		Modified:           []File{},
+		Binaries:           []File{},
+		InstallScriptFiles: []File{},
+		InstallScripts:     []InstallScriptChange{},
+	}
+
+	for filePath, file := range target.Files {
+		baseFile, ok := base.Files[filePath]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, file)
+		case baseFile.Sha256 != file.Sha256:
+			diff.Modified = append(diff.Modified, file)
+		default:
+			continue
+		}
+
+		if file.Binary {
+			diff.Binaries = append(diff.Binaries, file)
+		}
+
+		if isInstallScriptFile(filePath) {
+			diff.InstallScriptFiles = append(diff.InstallScriptFiles, file)
+		}
+	}
+
+	for filePath, file := range base.Files {
+		if _, ok := target.Files[filePath]; !ok {
+			diff.Removed = append(diff.Removed, file)
+		}
+	}
+
+	for _, name := range npmInstallScripts {
+		if base.InstallScripts[name] != target.InstallScripts[name] {
+			diff.InstallScripts = append(diff.InstallScripts, InstallScriptChange{
+				Name: name,
+				Old:  base.InstallScripts[name],
+				New:  target.InstallScripts[name],
+			})
+		}
+	}
+
+	for _, files := range [][]File{diff.Added, diff.Removed, diff.Modified,
+		diff.Binaries, diff.InstallScriptFiles} {
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].Path < files[j].Path
+		})
+	}
+
+	return diff
+}
+
+// Suspicious returns true if the diff has changes commonly seen in
+// compromised releases i.e. new binaries or changed install scripts
+func (d *Diff) Suspicious() bool {
+	return len(d.Binaries) > 0 || len(d.InstallScriptFiles) > 0 || len(d.InstallScripts) > 0
+}
+
+func (d *Diff) Summary() string {
+	return fmt.Sprintf("%d added, %d removed, %d modified files, %d binaries, %d install script changes",
+		len(d.Added), len(d.Removed), len(d.Modified), len(d.Binaries),
+		len(d.InstallScriptFiles)+len(d.InstallScripts))
+}
+
+// FindingEvidence returns the diff as evidence with the JSON
+// representation of the diff as attachment
+func (d *Diff) FindingEvidence(baseVersion, targetVersion string) (analyzer.FindingEvidence, error) {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return analyzer.FindingEvidence{}, fmt.Errorf("failed to serialize diff: %w", err)
+	}
+
+	return analyzer.FindingEvidence{
+		Type:    analyzer.FindingEvidenceTypeArtifactDiff,
+		Summary: fmt.Sprintf("Changes from %s to %s: %s", baseVersion, targetVersion, d.Summary()),
+		Attachment: analyzer.NewFindingEvidenceAttachment(
+			fmt.Sprintf("%s..%s.json", baseVersion, targetVersion),
+			"application/json", string(data)),
+	}, nil
+}
+
+// Finding returns a finding for review of a suspicious version bump of
+// the package with the diff as evidence. Nil is returned when the diff
+// is not suspicious.
+func (d *Diff) Finding(name, baseVersion, targetVersion string) (*analyzer.Finding, error) {
+	if !d.Suspicious() {
+		return nil, nil
+	}
+
+	evidence, err := d.FindingEvidence(baseVersion, targetVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &analyzer.Finding{
+		Kind:       analyzer.FindingKindThreat,
+		Severity:   analyzer.FindingSeverityMedium,
+		Confidence: analyzer.FindingConfidenceLow,
+		Title: fmt.Sprintf("Binaries or install scripts changed in %s from %s to %s",
+			name, baseVersion, targetVersion),
+		Evidences: []analyzer.FindingEvidence{evidence},
+		Remediation: &analyzer.FindingRemediation{
+			Summary: "Review the changed files before upgrading to " + targetVersion,
+		},
+	}, nil
+}
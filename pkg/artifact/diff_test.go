@@ -0,0 +1,133 @@
+package artifact
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildTarGz(t *testing.T, files map[string]string) *Artifact {
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     "package/" + name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		require.NoError(t, err)
+
+		_, err = tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	artifact, err := ReadTarGz(&buf)
+	require.NoError(t, err)
+
+	return artifact
+}
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		name                     string
+		base                     map[string]string
+		target                   map[string]string
+		added, removed, modified []string
+		binaries, scriptFiles    []string
+		installScripts           []InstallScriptChange
+		suspicious               bool
+	}{
+		{
+			"No changes",
+			map[string]string{"index.js": "module.exports = 1"},
+			map[string]string{"index.js": "module.exports = 1"},
+			nil, nil, nil, nil, nil, nil,
+			false,
+		},
+		{
+			"Modified and removed files",
+			map[string]string{"index.js": "module.exports = 1", "README.md": "docs"},
+			map[string]string{"index.js": "module.exports = 2"},
+			nil, []string{"README.md"}, []string{"index.js"}, nil, nil, nil,
+			false,
+		},
+		{
+			"Added binary",
+			map[string]string{"index.js": "module.exports = 1"},
+			map[string]string{"index.js": "module.exports = 1", "lib/native.node": "\x7fELF\x02\x01"},
+			[]string{"lib/native.node"}, nil, nil, []string{"lib/native.node"}, nil, nil,
+			true,
+		},
+		{
+			"Changed postinstall script",
+			map[string]string{"package.json": `{"scripts": {"postinstall": "node build.js"}}`},
+			map[string]string{
+				"package.json": `{"scripts": {"postinstall": "node setup.js"}}`,
+				"install.js":   "require('child_process')",
+			},
+			[]string{"install.js"}, nil, []string{"package.json"}, nil, []string{"install.js"},
+			[]InstallScriptChange{{Name: "postinstall", Old: "node build.js", New: "node setup.js"}},
+			true,
+		},
+	}
+
+	paths := func(files []File) []string {
+		var res []string
+		for _, f := range files {
+			res = append(res, f.Path)
+		}
+
+		return res
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			diff := Compare(buildTarGz(t, test.base), buildTarGz(t, test.target))
+
+			assert.Equal(t, test.added, paths(diff.Added))
+			assert.Equal(t, test.removed, paths(diff.Removed))
+			assert.Equal(t, test.modified, paths(diff.Modified))
+			assert.Equal(t, test.binaries, paths(diff.Binaries))
+			assert.Equal(t, test.scriptFiles, paths(diff.InstallScriptFiles))
+			if test.installScripts == nil {
+				assert.Empty(t, diff.InstallScripts)
+			} else {
+				assert.Equal(t, test.installScripts, diff.InstallScripts)
+			}
+
+			assert.Equal(t, test.suspicious, diff.Suspicious())
+		})
+	}
+}
+
+func TestDiffFinding(t *testing.T) {
+	base := buildTarGz(t, map[string]string{"index.js": "module.exports = 1"})
+
+	finding, err := Compare(base, base).Finding("demo", "1.0.0", "1.0.1")
+	require.NoError(t, err)
+	assert.Nil(t, finding)
+
+	target := buildTarGz(t, map[string]string{"index.js": "module.exports = 1", "install.js": "run()"})
+
+	finding, err = Compare(base, target).Finding("demo", "1.0.0", "1.0.1")
+	require.NoError(t, err)
+	require.NotNil(t, finding)
+	require.Len(t, finding.Evidences, 1)
+
+	evidence := finding.Evidences[0]
+	assert.Equal(t, analyzer.FindingEvidenceTypeArtifactDiff, evidence.Type)
+	require.NotNil(t, evidence.Attachment)
+	assert.Equal(t, "1.0.0..1.0.1.json", evidence.Attachment.Name)
+	assert.Contains(t, evidence.Attachment.Content, `"path": "install.js"`)
+}
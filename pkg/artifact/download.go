@@ -0,0 +1,222 @@
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/safedep/vet/pkg/common/httpclient"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/models"
+)
+
+const (
+	defaultNpmRegistryUrl = "https://registry.npmjs.org"
+	defaultPyPIUrl        = "https://pypi.org"
+
+	// Max size of registry metadata response
+	metadataMaxSize = 32 * 1024 * 1024
+)
+
+// Downloader fetches the published artifact of a package version
+type Downloader interface {
+	Download(ctx context.Context, ecosystem, name, version string) (*Artifact, error)
+}
+
+type RegistryDownloaderConfig struct {
+	// Optional, defaults to public registries
+	NpmRegistryUrl string
+	PyPIUrl        string
+
+	// Optional HTTP client, defaults to shared client
+	HttpClient *http.Client
+
+	// Optional, defaults to DefaultLimits
+	Limits Limits
+}
+
+type registryDownloader struct {
+	config RegistryDownloaderConfig
+}
+
+// NewRegistryDownloader creates a downloader for artifacts published
+// in npm and PyPI registries
+func NewRegistryDownloader(config RegistryDownloaderConfig) (Downloader, error) {
+	if config.NpmRegistryUrl == "" {
+		config.NpmRegistryUrl = defaultNpmRegistryUrl
+	}
+
+	if config.PyPIUrl == "" {
+		config.PyPIUrl = defaultPyPIUrl
+	}
+
+	if config.HttpClient == nil {
+		config.HttpClient = httpclient.Default()
+	}
+
+	config.Limits = config.Limits.withDefaults()
+
+	return &registryDownloader{config: config}, nil
+}
+
+func (d *registryDownloader) Download(ctx context.Context, ecosystem, name, version string) (*Artifact, error) {
+	switch ecosystem {
+	case models.EcosystemNpm:
+		return d.downloadNpm(ctx, name, version)
+	case models.EcosystemPyPI:
+		return d.downloadPyPI(ctx, name, version)
+	default:
+		return nil, fmt.Errorf("artifact download is not supported for ecosystem: %s", ecosystem)
+	}
+}
+
+func (d *registryDownloader) downloadNpm(ctx context.Context, name, version string) (*Artifact, error) {
+	var manifest struct {
+		Dist struct {
+			Tarball string `json:"tarball"`
+		} `json:"dist"`
+	}
+
+	// Scoped package names must retain `@` but escape `/`
+	manifestUrl := fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(d.config.NpmRegistryUrl, "/"),
+		strings.Replace(url.PathEscape(name), "%40", "@", 1), url.PathEscape(version))
+
+	if err := d.getJson(ctx, manifestUrl, &manifest); err != nil {
+		return nil, err
+	}
+
+	if manifest.Dist.Tarball == "" {
+		return nil, fmt.Errorf("no tarball found for npm package %s@%s", name, version)
+	}
+
+	return d.readTarGz(ctx, manifest.Dist.Tarball, NpmArchiveRoot)
+}
+
+func (d *registryDownloader) downloadPyPI(ctx context.Context, name, version string) (*Artifact, error) {
+	var release struct {
+		Urls []struct {
+			PackageType string `json:"packagetype"`
+			Url         string `json:"url"`
+		} `json:"urls"`
+	}
+
+	releaseUrl := fmt.Sprintf("%s/pypi/%s/%s/json", strings.TrimSuffix(d.config.PyPIUrl, "/"),
+		url.PathEscape(name), url.PathEscape(version))
+
+	if err := d.getJson(ctx, releaseUrl, &release); err != nil {
+		return nil, err
+	}
+
+	// Source distribution is preferred because it includes setup.py
+	// which is executed on install
+	var wheelUrl string
+	for _, u := range release.Urls {
+		switch {
+		case u.PackageType == "sdist" && strings.HasSuffix(u.Url, ".tar.gz"):
+			return d.readTarGz(ctx, u.Url, sdistArchiveRoot(u.Url))
+		case u.PackageType == "bdist_wheel" && wheelUrl == "":
+			wheelUrl = u.Url
+		}
+	}
+
+	if wheelUrl == "" {
+		return nil, fmt.Errorf("no supported distribution found for PyPI package %s==%s", name, version)
+	}
+
+	logger.Debugf("Source distribution not found for PyPI package %s==%s, using wheel", name, version)
+
+	body, err := d.open(ctx, wheelUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	defer body.Close()
+
+	return ReadZipWithLimits(body, "", d.config.Limits)
+}
+
+// Source distributions have a top level directory named after the
+// archive e.g. requests-2.31.0/ in requests-2.31.0.tar.gz
+func sdistArchiveRoot(archiveUrl string) string {
+	if parsed, err := url.Parse(archiveUrl); err == nil {
+		archiveUrl = parsed.Path
+	}
+
+	return strings.TrimSuffix(path.Base(archiveUrl), ".tar.gz")
+}
+
+// Tarballs are streamed from the response without buffering
+func (d *registryDownloader) readTarGz(ctx context.Context, url, root string) (*Artifact, error) {
+	body, err := d.open(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer body.Close()
+
+	return ReadTarGzWithLimits(body, root, d.config.Limits)
+}
+
+func (d *registryDownloader) getJson(ctx context.Context, url string, v interface{}) error {
+	data, err := d.get(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse response from %s: %w", url, err)
+	}
+
+	return nil
+}
+
+func (d *registryDownloader) get(ctx context.Context, url string) ([]byte, error) {
+	body, err := d.open(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, metadataMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", url, err)
+	}
+
+	if len(data) > metadataMaxSize {
+		return nil, fmt.Errorf("response from %s exceeds max size of %d bytes", url, metadataMaxSize)
+	}
+
+	return data, nil
+}
+
+// open returns the response body which must be closed by the caller
+func (d *registryDownloader) open(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := d.config.HttpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("unexpected status code %d from %s", res.StatusCode, url)
+	}
+
+	// Fail early instead of streaming an artifact known to exceed the cap
+	if res.ContentLength > d.config.Limits.MaxArchiveSize {
+		res.Body.Close()
+		return nil, fmt.Errorf("%w: %s has size %d bytes", ErrLimitExceeded, url, res.ContentLength)
+	}
+
+	return res.Body, nil
+}
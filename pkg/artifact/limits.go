@@ -0,0 +1,109 @@
+package artifact
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/safedep/vet/pkg/common/resources"
+)
+
+// ErrLimitExceeded is returned when an artifact exceeds a size cap
+var ErrLimitExceeded = resources.ErrLimitExceeded
+
+// Limits caps the resources used for reading an artifact. Artifacts are
+// streamed, so memory usage does not grow with the size of the artifact
+// but large artifacts still cost time and disk space for spooling.
+type Limits struct {
+	// Max size of the compressed archive
+	MaxArchiveSize int64
+
+	// Max size of all files after decompression, guards against
+	// decompression bombs
+	MaxUncompressedSize int64
+
+	// Max size of a single file after decompression
+	MaxFileSize int64
+
+	// Max number of files in the archive
+	MaxFiles int
+}
+
+// DefaultLimits derives the size caps from the configured resource limits
+func DefaultLimits() Limits {
+	configured := resources.Current()
+
+	return Limits{
+		MaxArchiveSize:      configured.MaxDownloadSize,
+		MaxUncompressedSize: configured.MaxExtractedSize,
+		MaxFileSize:         min(configured.MaxExtractedSize, 1<<30),
+		MaxFiles:            200000,
+	}
+}
+
+// withDefaults fills unset limits from default limits
+func (l Limits) withDefaults() Limits {
+	defaults := DefaultLimits()
+
+	if l.MaxArchiveSize <= 0 {
+		l.MaxArchiveSize = defaults.MaxArchiveSize
+	}
+
+	if l.MaxUncompressedSize <= 0 {
+		l.MaxUncompressedSize = defaults.MaxUncompressedSize
+	}
+
+	if l.MaxFileSize <= 0 {
+		l.MaxFileSize = defaults.MaxFileSize
+	}
+
+	if l.MaxFiles <= 0 {
+		l.MaxFiles = defaults.MaxFiles
+	}
+
+	return l
+}
+
+// cappedReader fails with ErrLimitExceeded when more than limit bytes
+// are read instead of silently truncating the stream
+type cappedReader struct {
+	reader    io.Reader
+	remaining int64
+	what      string
+}
+
+func newCappedReader(reader io.Reader, limit int64, what string) *cappedReader {
+	return &cappedReader{reader: reader, remaining: limit, what: what}
+}
+
+func (r *cappedReader) Read(p []byte) (int, error) {
+	// Read one byte beyond the limit to distinguish EOF at the limit
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, fmt.Errorf("%w: %s", ErrLimitExceeded, r.what)
+	}
+
+	return n, err
+}
+
+// budgetReader charges the bytes read to a limit shared by readers used
+// concurrently e.g. for entries of a zip archive read in parallel
+type budgetReader struct {
+	reader    io.Reader
+	remaining *atomic.Int64
+	what      string
+}
+
+func (r *budgetReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if r.remaining.Add(-int64(n)) < 0 {
+		return n, fmt.Errorf("%w: %s", ErrLimitExceeded, r.what)
+	}
+
+	return n, err
+}
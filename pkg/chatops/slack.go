@@ -0,0 +1,395 @@
+// Package chatops implements bot integrations that answer package
+// queries in chat platforms using the package profile.
+package chatops
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/httpclient"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/profile"
+	"github.com/safedep/vet/pkg/purl"
+)
+
+const (
+	slackSignatureVersion = "v0"
+
+	// Requests older than this are rejected to prevent replay
+	slackMaxRequestAge = 5 * time.Minute
+
+	// Max size of slash command request body
+	slackMaxRequestSize = 64 * 1024
+
+	slackCommandCheck = "check"
+
+	// Host of response URLs issued by Slack
+	slackResponseUrlHost = "hooks.slack.com"
+
+	defaultPurlType = "npm"
+
+	defaultSlackWorkers     = 4
+	defaultSlackQueueSize   = 100
+	defaultSlackPostTimeout = 30 * time.Second
+)
+
+type SlackCommandHandlerConfig struct {
+	// Signing secret of the Slack app used to verify requests
+	SigningSecret string
+
+	Profiler profile.Profiler
+
+	// Optional HTTP client used to post verdicts, defaults to a client
+	// with PostTimeout
+	HttpClient *http.Client
+
+	// Number of commands processed concurrently and max number of
+	// commands waiting to be processed. Commands beyond the queue
+	// are rejected.
+	Workers   int
+	QueueSize int
+
+	// Timeout of posting a verdict to the response URL
+	PostTimeout time.Duration
+
+	// Hosts allowed in response URLs, defaults to hooks.slack.com
+	ResponseUrlHosts []string
+
+	// Optional, used for request timestamp verification
+	Now func() time.Time
+}
+
+type slackCommand struct {
+	responseUrl string
+	packageUrl  string
+}
+
+type slackCommandHandler struct {
+	config SlackCommandHandlerConfig
+	queue  chan slackCommand
+}
+
+type slackMessage struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// NewSlackCommandHandler creates an HTTP handler for Slack slash commands
+// e.g. `/vet check lodash@4.17.20`. The request is acknowledged immediately
+// and the verdict is posted to the channel using the response URL because
+// Slack expects a response within 3 seconds. Commands are processed by a
+// fixed pool of workers.
+func NewSlackCommandHandler(config SlackCommandHandlerConfig) (http.Handler, error) {
+	if config.SigningSecret == "" {
+		return nil, errors.New("slack signing secret is required")
+	}
+
+	if config.Profiler == nil {
+		return nil, errors.New("profiler is required")
+	}
+
+	if config.Workers <= 0 {
+		config.Workers = defaultSlackWorkers
+	}
+
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultSlackQueueSize
+	}
+
+	if config.PostTimeout <= 0 {
+		config.PostTimeout = defaultSlackPostTimeout
+	}
+
+	if len(config.ResponseUrlHosts) == 0 {
+		config.ResponseUrlHosts = []string{slackResponseUrlHost}
+	}
+
+	if config.HttpClient == nil {
+		config.HttpClient = httpclient.NewClient(config.PostTimeout)
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	h := &slackCommandHandler{
+		config: config,
+		queue:  make(chan slackCommand, config.QueueSize),
+	}
+
+	for i := 0; i < config.Workers; i++ {
+		go h.worker()
+	}
+
+	return h, nil
+}
+
+func (h *slackCommandHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, slackMaxRequestSize))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	err = h.verify(r.Header, body)
+	if err != nil {
+		logger.Warnf("Slack: Rejected request: %v", err)
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	packageUrl, err := parseCheckCommand(form.Get("text"))
+	if err != nil {
+		command := form.Get("command")
+		h.reply(w, slackMessage{
+			ResponseType: "ephemeral",
+			Text: fmt.Sprintf("%v\nUsage: `%s check [ecosystem/]name@version` e.g. `%s check lodash@4.17.20`",
+				err, command, command),
+		})
+
+		return
+	}
+
+	responseUrl := form.Get("response_url")
+	if err := h.validateResponseUrl(responseUrl); err != nil {
+		logger.Warnf("Slack: Rejected response url: %v", err)
+		http.Error(w, "invalid response url", http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case h.queue <- slackCommand{responseUrl: responseUrl, packageUrl: packageUrl}:
+	default:
+		logger.Warnf("Slack: Command queue is full, rejected check of %s", packageUrl)
+		h.reply(w, slackMessage{
+			ResponseType: "ephemeral",
+			Text:         "Too many checks in progress, please try again later",
+		})
+
+		return
+	}
+
+	h.reply(w, slackMessage{
+		ResponseType: "ephemeral",
+		Text:         fmt.Sprintf("Checking `%s`", packageUrl),
+	})
+}
+
+// verify implements Slack request signature verification
+// https://api.slack.com/authentication/verifying-requests-from-slack
+func (h *slackCommandHandler) verify(header http.Header, body []byte) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	signature := header.Get("X-Slack-Signature")
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid request timestamp: %w", err)
+	}
+
+	age := h.config.Now().Sub(time.Unix(ts, 0))
+	if age > slackMaxRequestAge || age < -slackMaxRequestAge {
+		return fmt.Errorf("request timestamp is outside the allowed window")
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.config.SigningSecret))
+	mac.Write([]byte(fmt.Sprintf("%s:%s:", slackSignatureVersion, timestamp)))
+	mac.Write(body)
+
+	expected := slackSignatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("signature mismatch")
+	}
+
+	return nil
+}
+
+// Verdicts are posted only to Slack so that a signed request can not
+// be used to make the server send requests to arbitrary hosts
+func (h *slackCommandHandler) validateResponseUrl(responseUrl string) error {
+	u, err := url.Parse(responseUrl)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "https" {
+		return fmt.Errorf("response url must use https: %s", u.Redacted())
+	}
+
+	for _, host := range h.config.ResponseUrlHosts {
+		if strings.EqualFold(u.Host, host) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("response url host is not allowed: %s", u.Host)
+}
+
+func (h *slackCommandHandler) worker() {
+	for cmd := range h.queue {
+		h.postVerdict(cmd.responseUrl, cmd.packageUrl)
+	}
+}
+
+func (h *slackCommandHandler) postVerdict(responseUrl, packageUrl string) {
+	msg := slackMessage{ResponseType: "in_channel"}
+
+	p, err := h.config.Profiler.Profile(packageUrl)
+	if err != nil {
+		logger.Errorf("Slack: Failed to profile package %s: %v", packageUrl, err)
+
+		msg.ResponseType = "ephemeral"
+		msg.Text = fmt.Sprintf("Failed to check `%s`: %v", packageUrl, err)
+	} else {
+		msg.Text = FormatVerdict(p)
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		logger.Errorf("Slack: Failed to serialize message: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.PostTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseUrl, bytes.NewReader(data))
+	if err != nil {
+		logger.Errorf("Slack: Failed to create request: %v", err)
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := h.config.HttpClient.Do(req)
+	if err != nil {
+		logger.Errorf("Slack: Failed to post verdict for %s: %v", packageUrl, err)
+		return
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		logger.Errorf("Slack: Unexpected status code %d while posting verdict for %s",
+			res.StatusCode, packageUrl)
+	}
+}
+
+func (h *slackCommandHandler) reply(w http.ResponseWriter, msg slackMessage) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(msg)
+}
+
+// parseCheckCommand parses `check <query>` into a package URL. The query
+// is either a package URL or `[type/]name@version` where type is a purl
+// type defaulting to npm
+func parseCheckCommand(text string) (string, error) {
+	fields := strings.Fields(text)
+	if len(fields) != 2 || !strings.EqualFold(fields[0], slackCommandCheck) {
+		return "", errors.New("unsupported command")
+	}
+
+	query := fields[1]
+	if !strings.HasPrefix(query, "pkg:") {
+		purlType := defaultPurlType
+		if parts := strings.SplitN(query, "/", 2); len(parts) == 2 {
+			if _, err := purl.TypeToEcosystem(strings.ToLower(parts[0])); err == nil {
+				purlType = strings.ToLower(parts[0])
+				query = parts[1]
+			}
+		}
+
+		// Scoped npm packages start with @ which must be escaped in purl
+		if strings.HasPrefix(query, "@") {
+			query = "%40" + query[1:]
+		}
+
+		query = fmt.Sprintf("pkg:%s/%s", purlType, query)
+	}
+
+	parsedPurl, err := purl.Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("invalid package: %w", err)
+	}
+
+	if parsedPurl.GetPackageDetails().Version == "" {
+		return "", errors.New("package version is required")
+	}
+
+	return query, nil
+}
+
+// FormatVerdict renders the profile of a package as Slack message text
+func FormatVerdict(p *profile.PackageProfile) string {
+	var sb strings.Builder
+
+	status := ":white_check_mark: Allowed"
+	switch {
+	case p.Malware != nil && p.Malware.IsMalware:
+		status = ":rotating_light: Malicious"
+	case !p.Verdict.Allowed:
+		status = ":x: Blocked"
+	case !p.Verdict.Evaluated:
+		status = ":grey_question: No policy configured"
+	}
+
+	sb.WriteString(fmt.Sprintf("*%s@%s* (%s): %s\n", p.Name, p.Version, p.Ecosystem, status))
+
+	for _, v := range p.Verdict.Violations {
+		sb.WriteString(fmt.Sprintf("• Policy `%s`: %s\n", v.Name, v.Summary))
+	}
+
+	if !p.Enriched {
+		sb.WriteString("• Package metadata not available\n")
+		return sb.String()
+	}
+
+	severities := map[string]int{}
+	for _, v := range p.Vulnerabilities {
+		severities[v.Severity] += 1
+	}
+
+	sb.WriteString(fmt.Sprintf("• Vulnerabilities: %d (Critical:%d High:%d Medium:%d Low:%d)\n",
+		len(p.Vulnerabilities), severities["CRITICAL"], severities["HIGH"],
+		severities["MEDIUM"], severities["LOW"]))
+
+	if len(p.Licenses) > 0 {
+		sb.WriteString(fmt.Sprintf("• Licenses: %s\n", strings.Join(p.Licenses, ", ")))
+	}
+
+	if p.Health != nil && p.Health.Available {
+		sb.WriteString(fmt.Sprintf("• Health score: %.1f/10\n", p.Health.Score))
+	}
+
+	if p.Scorecard != nil {
+		sb.WriteString(fmt.Sprintf("• OpenSSF Scorecard: %.1f/10\n", p.Scorecard.Score))
+	}
+
+	if p.LatestVersion != "" && p.LatestVersion != p.Version {
+		sb.WriteString(fmt.Sprintf("• Latest version: %s\n", p.LatestVersion))
+	}
+
+	return sb.String()
+}
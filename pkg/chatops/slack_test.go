@@ -0,0 +1,228 @@
+package chatops
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/safedep/vet/pkg/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticProfiler struct {
+	purls chan string
+}
+
+func (p *staticProfiler) Profile(purl string) (*profile.PackageProfile, error) {
+	p.purls <- purl
+	return &profile.PackageProfile{
+		Purl:      purl,
+		Ecosystem: "npm",
+		Name:      "lodash",
+		Version:   "4.17.20",
+		Enriched:  true,
+		Vulnerabilities: []profile.Vulnerability{
+			{Id: "GHSA-35jh-r3h4-6jhm", Severity: "HIGH"},
+		},
+		Verdict: profile.Verdict{
+			Evaluated: true,
+			Violations: []profile.PolicyViolation{
+				{Name: "critical-or-high-vulns", Summary: "Critical or high risk vulnerabilities"},
+			},
+		},
+	}, nil
+}
+
+// blockingProfiler blocks till released so that workers stay busy
+type blockingProfiler struct {
+	started chan string
+	release chan struct{}
+}
+
+func (p *blockingProfiler) Profile(purl string) (*profile.PackageProfile, error) {
+	p.started <- purl
+	<-p.release
+
+	return nil, errors.New("released")
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("network is disabled in test")
+}
+
+func TestParseCheckCommand(t *testing.T) {
+	cases := []struct {
+		text string
+		purl string
+		err  string
+	}{
+		{"check lodash@4.17.20", "pkg:npm/lodash@4.17.20", ""},
+		{"check @angular/core@16.0.0", "pkg:npm/%40angular/core@16.0.0", ""},
+		{"check pypi/requests@2.31.0", "pkg:pypi/requests@2.31.0", ""},
+		{"check pkg:maven/org.yaml/snakeyaml@1.33", "pkg:maven/org.yaml/snakeyaml@1.33", ""},
+		{"check lodash", "", "package version is required"},
+		{"scan lodash@4.17.20", "", "unsupported command"},
+		{"", "", "unsupported command"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.text, func(t *testing.T) {
+			purl, err := parseCheckCommand(test.text)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.purl, purl)
+		})
+	}
+}
+
+func TestSlackCommandHandler(t *testing.T) {
+	secret := "8f742231b10e8888abcd99yyyzzz85a5"
+	now := time.Unix(1700000000, 0)
+
+	sign := func(timestamp int64, body string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(fmt.Sprintf("v0:%d:%s", timestamp, body)))
+		return "v0=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	posted := make(chan slackMessage, 1)
+	responseServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slackMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		posted <- msg
+	}))
+
+	defer responseServer.Close()
+
+	profiler := &staticProfiler{purls: make(chan string, 1)}
+	responseUrl, err := url.Parse(responseServer.URL)
+	require.NoError(t, err)
+
+	handler, err := NewSlackCommandHandler(SlackCommandHandlerConfig{
+		SigningSecret:    secret,
+		Profiler:         profiler,
+		HttpClient:       responseServer.Client(),
+		ResponseUrlHosts: []string{responseUrl.Host},
+		Now:              func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	commandBody := func(responseUrl string) string {
+		return url.Values{
+			"command":      {"/vet"},
+			"text":         {"check lodash@4.17.20"},
+			"response_url": {responseUrl},
+		}.Encode()
+	}
+
+	body := commandBody(responseServer.URL)
+	foreignBody := commandBody("https://attacker.example.com/hook")
+	insecureBody := commandBody("http://" + responseUrl.Host + "/hook")
+
+	cases := []struct {
+		name      string
+		body      string
+		timestamp int64
+		signature string
+		status    int
+		posted    bool
+	}{
+		{"Valid request", body, now.Unix(), sign(now.Unix(), body), http.StatusOK, true},
+		{"Invalid signature", body, now.Unix(), sign(now.Unix(), "text=other"), http.StatusUnauthorized, false},
+		{"Replayed request", body, now.Unix() - 3600, sign(now.Unix()-3600, body), http.StatusUnauthorized, false},
+		{"Response url of other host", foreignBody, now.Unix(), sign(now.Unix(), foreignBody), http.StatusBadRequest, false},
+		{"Response url without https", insecureBody, now.Unix(), sign(now.Unix(), insecureBody), http.StatusBadRequest, false},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/slack/command", strings.NewReader(test.body))
+			req.Header.Set("X-Slack-Request-Timestamp", fmt.Sprintf("%d", test.timestamp))
+			req.Header.Set("X-Slack-Signature", test.signature)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			data, _ := io.ReadAll(res.Body)
+
+			assert.Equal(t, test.status, res.StatusCode, string(data))
+			if !test.posted {
+				return
+			}
+
+			assert.Equal(t, "pkg:npm/lodash@4.17.20", <-profiler.purls)
+
+			msg := <-posted
+			assert.Equal(t, "in_channel", msg.ResponseType)
+			assert.Contains(t, msg.Text, "*lodash@4.17.20* (npm): :x: Blocked")
+			assert.Contains(t, msg.Text, "Policy `critical-or-high-vulns`")
+			assert.Contains(t, msg.Text, "Vulnerabilities: 1 (Critical:0 High:1 Medium:0 Low:0)")
+		})
+	}
+}
+
+func TestSlackCommandHandlerQueueFull(t *testing.T) {
+	secret := "8f742231b10e8888abcd99yyyzzz85a5"
+	now := time.Unix(1700000000, 0)
+
+	profiler := &blockingProfiler{started: make(chan string, 2), release: make(chan struct{})}
+	defer close(profiler.release)
+
+	handler, err := NewSlackCommandHandler(SlackCommandHandlerConfig{
+		SigningSecret: secret,
+		Profiler:      profiler,
+		HttpClient:    &http.Client{Transport: failingTransport{}},
+		Workers:       1,
+		QueueSize:     1,
+		Now:           func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	body := url.Values{
+		"command":      {"/vet"},
+		"text":         {"check lodash@4.17.20"},
+		"response_url": {"https://hooks.slack.com/commands/1/2/3"},
+	}.Encode()
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("v0:%d:%s", now.Unix(), body)))
+	signature := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	send := func() string {
+		req := httptest.NewRequest(http.MethodPost, "/slack/command", strings.NewReader(body))
+		req.Header.Set("X-Slack-Request-Timestamp", fmt.Sprintf("%d", now.Unix()))
+		req.Header.Set("X-Slack-Signature", signature)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		var msg slackMessage
+		require.NoError(t, json.NewDecoder(rec.Result().Body).Decode(&msg))
+
+		return msg.Text
+	}
+
+	// First command keeps the worker busy, second is queued
+	assert.Contains(t, send(), "Checking")
+	<-profiler.started
+	assert.Contains(t, send(), "Checking")
+	assert.Contains(t, send(), "Too many checks in progress")
+}
@@ -0,0 +1,134 @@
+// Package concurrency is the central configuration of worker pool and
+// queue sizes. Limits are auto-scaled from CPU count, capped by the max
+// concurrent requests of target APIs and can be overridden per subsystem.
+// Request rate is not limited here, see the rate limit of the sync reporter.
+package concurrency
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+type Subsystem string
+
+const (
+	// Package enrichment and analysis workers of the scanner
+	SubsystemScanner = Subsystem("scanner")
+
+	// Concurrent requests to Insights API
+	SubsystemInsights = Subsystem("insights")
+
+	// Cloud sync reporter workers
+	SubsystemSync = Subsystem("sync")
+
+	// Queue of each asynchronous reporter
+	SubsystemReporter = Subsystem("reporter")
+
+	// Decompression and hashing of entries of an artifact
+	SubsystemArtifact = Subsystem("artifact")
+)
+
+type Limits struct {
+	Workers   int
+	QueueSize int
+}
+
+// Workers are scaled as CPU count * factor and clamped to [min, max].
+// Sync workers are not scaled since ControlTower quotas are per tenant
+// and do not grow with the CPU count of the client.
+type profile struct {
+	factor     int
+	minWorkers int
+	maxWorkers int
+	queueSize  int
+}
+
+var profiles = map[Subsystem]profile{
+	SubsystemScanner:  {factor: 2, minWorkers: 5, maxWorkers: 32, queueSize: 100000},
+	SubsystemInsights: {factor: 4, minWorkers: 10, maxWorkers: 64},
+	SubsystemSync:     {factor: 0, minWorkers: 10, maxWorkers: 10, queueSize: 1000},
+	SubsystemReporter: {factor: 0, minWorkers: 1, maxWorkers: 1, queueSize: 1000},
+	SubsystemArtifact: {factor: 1, minWorkers: 1, maxWorkers: 16},
+}
+
+type Config struct {
+	// Number of CPUs, auto-detected when zero
+	CPUs int
+
+	// Max concurrent requests to the target API by subsystem. Workers
+	// are capped to it since a worker has one request in flight.
+	MaxConcurrency map[Subsystem]int
+
+	// Non-zero fields override the computed limits
+	Overrides map[Subsystem]Limits
+}
+
+var (
+	m       sync.RWMutex
+	current = Config{}
+)
+
+// Configure sets the configuration used by all subsystems. Must be
+// called before the subsystems are created.
+func Configure(config Config) {
+	m.Lock()
+	defer m.Unlock()
+
+	current = config
+}
+
+// For returns the limits of a subsystem under current configuration
+func For(subsystem Subsystem) Limits {
+	m.RLock()
+	defer m.RUnlock()
+
+	return current.Limits(subsystem)
+}
+
+// Limits computes the limits of a subsystem
+func (c Config) Limits(subsystem Subsystem) Limits {
+	p := profiles[subsystem]
+
+	cpus := c.CPUs
+	if cpus <= 0 {
+		cpus = runtime.NumCPU()
+	}
+
+	workers := int(math.Max(float64(p.minWorkers),
+		math.Min(float64(cpus*p.factor), float64(p.maxWorkers))))
+
+	if maxConcurrency, ok := c.MaxConcurrency[subsystem]; ok && maxConcurrency > 0 && maxConcurrency < workers {
+		workers = maxConcurrency
+	}
+
+	limits := Limits{Workers: workers, QueueSize: p.queueSize}
+	if override, ok := c.Overrides[subsystem]; ok {
+		if override.Workers > 0 {
+			limits.Workers = override.Workers
+		}
+
+		if override.QueueSize > 0 {
+			limits.QueueSize = override.QueueSize
+		}
+	}
+
+	return limits
+}
+
+// ParseSubsystem validates the name of a subsystem
+func ParseSubsystem(name string) (Subsystem, error) {
+	if _, ok := profiles[Subsystem(name)]; !ok {
+		names := []string{}
+		for s := range profiles {
+			names = append(names, string(s))
+		}
+
+		sort.Strings(names)
+		return "", fmt.Errorf("unknown subsystem: %s (supported: %v)", name, names)
+	}
+
+	return Subsystem(name), nil
+}
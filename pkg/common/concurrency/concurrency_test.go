@@ -0,0 +1,84 @@
+package concurrency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigLimits(t *testing.T) {
+	cases := []struct {
+		name      string
+		config    Config
+		subsystem Subsystem
+		limits    Limits
+	}{
+		{
+			"Minimum workers on small machines",
+			Config{CPUs: 1},
+			SubsystemScanner,
+			Limits{Workers: 5, QueueSize: 100000},
+		},
+		{
+			"Scaled from CPU count",
+			Config{CPUs: 4},
+			SubsystemInsights,
+			Limits{Workers: 16},
+		},
+		{
+			"Sync workers are not scaled from CPU count",
+			Config{CPUs: 64},
+			SubsystemSync,
+			Limits{Workers: 10, QueueSize: 1000},
+		},
+		{
+			"Maximum workers on large machines",
+			Config{CPUs: 128},
+			SubsystemInsights,
+			Limits{Workers: 64},
+		},
+		{
+			"Capped by max concurrency",
+			Config{CPUs: 8, MaxConcurrency: map[Subsystem]int{SubsystemInsights: 20}},
+			SubsystemInsights,
+			Limits{Workers: 20},
+		},
+		{
+			"Max concurrency above computed workers",
+			Config{CPUs: 2, MaxConcurrency: map[Subsystem]int{SubsystemSync: 100}},
+			SubsystemSync,
+			Limits{Workers: 10, QueueSize: 1000},
+		},
+		{
+			"Override takes precedence",
+			Config{
+				CPUs:           8,
+				MaxConcurrency: map[Subsystem]int{SubsystemSync: 5},
+				Overrides:      map[Subsystem]Limits{SubsystemSync: {Workers: 50}},
+			},
+			SubsystemSync,
+			Limits{Workers: 50, QueueSize: 1000},
+		},
+		{
+			"Override of queue size",
+			Config{CPUs: 8, Overrides: map[Subsystem]Limits{SubsystemReporter: {QueueSize: 5000}}},
+			SubsystemReporter,
+			Limits{Workers: 1, QueueSize: 5000},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.limits, test.config.Limits(test.subsystem))
+		})
+	}
+}
+
+func TestParseSubsystem(t *testing.T) {
+	s, err := ParseSubsystem("sync")
+	assert.NoError(t, err)
+	assert.Equal(t, SubsystemSync, s)
+
+	_, err = ParseSubsystem("unknown")
+	assert.ErrorContains(t, err, "unknown subsystem: unknown")
+}
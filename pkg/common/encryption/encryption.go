@@ -0,0 +1,150 @@
+// Package encryption provides encryption at rest for data written to disk
+// by vet such as caches and sync spool, which may contain internal package
+// names and paths.
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// Base64 encoded 256 bit key
+	EncryptionKeyEnvKey = "VET_ENCRYPTION_KEY"
+
+	KeySize = 32
+)
+
+// Identifies data encrypted by a Cipher and the format version
+var sealedMagic = []byte("vetenc1:")
+
+var (
+	ErrNotEncrypted = errors.New("data is not encrypted")
+	ErrNoKey        = errors.New("data is encrypted but encryption key is not configured")
+)
+
+// Cipher encrypts and authenticates data at rest
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+
+	// Decrypt returns ErrNotEncrypted when data was not produced by Encrypt
+	Decrypt(data []byte) ([]byte, error)
+}
+
+type aesGcmCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAesGcmCipher creates a cipher using AES-256-GCM with a random nonce
+// for every encryption
+func NewAesGcmCipher(key []byte) (Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM cipher: %w", err)
+	}
+
+	return &aesGcmCipher{aead: aead}, nil
+}
+
+// CipherFromKey creates a cipher using a key encoded by GenerateKey.
+// Returns nil when the key is empty.
+func CipherFromKey(encodedKey string) (Cipher, error) {
+	encodedKey = strings.TrimSpace(encodedKey)
+	if encodedKey == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key, must be base64 encoded: %w", err)
+	}
+
+	return NewAesGcmCipher(key)
+}
+
+// GenerateKey returns a random key encoded for use in environment or keychain
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// IsEncrypted is true when data was produced by a Cipher
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, sealedMagic)
+}
+
+func (c *aesGcmCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := make([]byte, 0, len(sealedMagic)+len(nonce)+len(plaintext)+c.aead.Overhead())
+	sealed = append(sealed, sealedMagic...)
+	sealed = append(sealed, nonce...)
+
+	// Magic is authenticated so that the format version can not be altered
+	return c.aead.Seal(sealed, nonce, plaintext, sealedMagic), nil
+}
+
+func (c *aesGcmCipher) Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, ErrNotEncrypted
+	}
+
+	data = data[len(sealedMagic):]
+	if len(data) < c.aead.NonceSize() {
+		return nil, errors.New("encrypted data is truncated")
+	}
+
+	nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, sealedMagic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt, data is corrupt or key is wrong: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// Open decrypts data when a cipher is available. Without a cipher,
+// plaintext data is returned as is. With a cipher, plaintext data is
+// rejected with ErrNotEncrypted since it is not authenticated.
+func Open(c Cipher, data []byte) ([]byte, error) {
+	if c == nil {
+		if IsEncrypted(data) {
+			return nil, ErrNoKey
+		}
+
+		return data, nil
+	}
+
+	return c.Decrypt(data)
+}
+
+// Seal encrypts data when a cipher is available
+func Seal(c Cipher, data []byte) ([]byte, error) {
+	if c == nil {
+		return data, nil
+	}
+
+	return c.Encrypt(data)
+}
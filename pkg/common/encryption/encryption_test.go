@@ -0,0 +1,102 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAesGcmCipher(t *testing.T) {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+
+	c, err := NewAesGcmCipher(key)
+	assert.NoError(t, err)
+
+	plaintext := []byte(`{"name":"@acme/internal"}`)
+	sealed, err := c.Encrypt(plaintext)
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(sealed))
+	assert.NotContains(t, string(sealed), "@acme/internal")
+
+	// Nonce is random for every encryption
+	again, err := c.Encrypt(plaintext)
+	assert.NoError(t, err)
+	assert.NotEqual(t, sealed, again)
+
+	opened, err := c.Decrypt(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+
+	_, err = c.Decrypt(plaintext)
+	assert.ErrorIs(t, err, ErrNotEncrypted)
+
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = c.Decrypt(tampered)
+	assert.ErrorContains(t, err, "failed to decrypt")
+
+	_, err = c.Decrypt(sealedMagic)
+	assert.ErrorContains(t, err, "truncated")
+
+	other, err := NewAesGcmCipher(make([]byte, KeySize))
+	assert.NoError(t, err)
+
+	_, err = other.Decrypt(sealed)
+	assert.ErrorContains(t, err, "key is wrong")
+
+	_, err = NewAesGcmCipher([]byte("short"))
+	assert.ErrorContains(t, err, "must be 32 bytes")
+}
+
+func TestSealAndOpen(t *testing.T) {
+	c, err := NewAesGcmCipher(make([]byte, KeySize))
+	assert.NoError(t, err)
+
+	data := []byte("data")
+
+	sealed, err := Seal(nil, data)
+	assert.NoError(t, err)
+	assert.Equal(t, data, sealed)
+
+	opened, err := Open(nil, data)
+	assert.NoError(t, err)
+	assert.Equal(t, data, opened)
+
+	// Plaintext is not authenticated, hence rejected when a key is configured
+	_, err = Open(c, data)
+	assert.ErrorIs(t, err, ErrNotEncrypted)
+
+	sealed, err = Seal(c, data)
+	assert.NoError(t, err)
+
+	_, err = Open(nil, sealed)
+	assert.ErrorIs(t, err, ErrNoKey)
+
+	opened, err = Open(c, sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, data, opened)
+}
+
+func TestCipherFromKey(t *testing.T) {
+	c, err := CipherFromKey(" ")
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	assert.NoError(t, err)
+	assert.Len(t, decoded, KeySize)
+
+	c, err = CipherFromKey(key)
+	assert.NoError(t, err)
+	assert.NotNil(t, c)
+
+	_, err = CipherFromKey("not base64!")
+	assert.ErrorContains(t, err, "must be base64 encoded")
+}
@@ -0,0 +1,134 @@
+// Package errcode attaches stable codes to user facing failures so that
+// CI scripts and support can identify a class of failure without matching
+// error messages. Codes are never reused for a different failure class.
+package errcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Code string
+
+const (
+	Unknown = Code("VET-UNKNOWN-000")
+
+	ConfigInvalid = Code("VET-CONFIG-001")
+
+	ReaderNotFound          = Code("VET-READER-001")
+	ReaderUnsupportedFormat = Code("VET-READER-002")
+	ReaderParseFailed       = Code("VET-READER-003")
+
+	AuthNotConfigured    = Code("VET-AUTH-001")
+	AuthInvalidToken     = Code("VET-AUTH-002")
+	AuthPermissionDenied = Code("VET-AUTH-003")
+
+	NetworkUnavailable = Code("VET-NETWORK-001")
+	NetworkTimeout     = Code("VET-NETWORK-002")
+
+	PolicyFailed = Code("VET-POLICY-001")
+
+	SyncIncomplete = Code("VET-SYNC-001")
+)
+
+var descriptions = map[Code]string{
+	Unknown:                 "Failure without a specific code",
+	ConfigInvalid:           "Invalid command line options or configuration",
+	ReaderNotFound:          "Manifest or path to scan does not exist",
+	ReaderUnsupportedFormat: "Manifest format is not supported",
+	ReaderParseFailed:       "Manifest could not be parsed",
+	AuthNotConfigured:       "Cloud credentials are not configured",
+	AuthInvalidToken:        "Cloud credentials are invalid or expired",
+	AuthPermissionDenied:    "Cloud credentials do not have access to the resource",
+	NetworkUnavailable:      "Remote service is unreachable",
+	NetworkTimeout:          "Remote service did not respond in time",
+	PolicyFailed:            "Policy or filter requested to fail the scan",
+	SyncIncomplete:          "Report data could not be synced to cloud",
+}
+
+// Error is a failure with a code
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap attaches the code to err. The code already attached to err is
+// retained because it is more specific. Returns nil when err is nil.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var coded *Error
+	if errors.As(err, &coded) {
+		return err
+	}
+
+	return &Error{Code: code, Err: err}
+}
+
+// Errorf formats an error with the code
+func Errorf(code Code, format string, args ...any) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// Of returns the code of err. Errors without a code are classified by
+// their well known causes, otherwise Unknown is returned.
+func Of(err error) Code {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return ReaderNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return NetworkTimeout
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unauthenticated:
+			return AuthInvalidToken
+		case codes.PermissionDenied:
+			return AuthPermissionDenied
+		case codes.Unavailable:
+			return NetworkUnavailable
+		case codes.DeadlineExceeded:
+			return NetworkTimeout
+		}
+	}
+
+	return Unknown
+}
+
+// Describe returns the description of the code
+func Describe(code Code) string {
+	return descriptions[code]
+}
+
+// All returns the known codes in order
+func All() []Code {
+	all := make([]Code, 0, len(descriptions))
+	for code := range descriptions {
+		all = append(all, code)
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	return all
+}
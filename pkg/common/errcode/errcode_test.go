@@ -0,0 +1,61 @@
+package errcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOf(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		code Code
+	}{
+		{"coded", Errorf(PolicyFailed, "filter matched"), PolicyFailed},
+		{"wrapped coded", fmt.Errorf("scan failed: %w", Errorf(ReaderParseFailed, "bad json")), ReaderParseFailed},
+		{"file not found", fmt.Errorf("open: %w", os.ErrNotExist), ReaderNotFound},
+		{"deadline", context.DeadlineExceeded, NetworkTimeout},
+		{"grpc unauthenticated", status.Error(codes.Unauthenticated, "invalid token"), AuthInvalidToken},
+		{"grpc unavailable", status.Error(codes.Unavailable, "connection refused"), NetworkUnavailable},
+		{"unknown", errors.New("failed"), Unknown},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.code, Of(test.err))
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	assert.Nil(t, Wrap(ConfigInvalid, nil))
+
+	err := Wrap(ConfigInvalid, errors.New("invalid flag"))
+	assert.Equal(t, ConfigInvalid, Of(err))
+	assert.Equal(t, "invalid flag", err.Error())
+
+	// Specific code is retained
+	err = Wrap(ConfigInvalid, fmt.Errorf("read: %w", Errorf(ReaderNotFound, "missing")))
+	assert.Equal(t, ReaderNotFound, Of(err))
+}
+
+func TestAllCodesAreDescribed(t *testing.T) {
+	all := All()
+	assert.NotEmpty(t, all)
+
+	for i, code := range all {
+		assert.NotEmpty(t, Describe(code), code)
+		assert.Regexp(t, `^VET-[A-Z]+-\d{3}$`, string(code))
+
+		if i > 0 {
+			assert.Less(t, string(all[i-1]), string(code))
+		}
+	}
+}
@@ -0,0 +1,336 @@
+// Package fixtures records HTTP interactions with registries and APIs into
+// a fixture file and replays them in tests. Recorded fixtures are sanitized
+// so that credentials are never committed.
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/safedep/vet/pkg/common/httpclient"
+)
+
+// Tests record fixtures against real services when this
+// environment variable is set to true
+const RecordEnvKey = "VET_FIXTURES_RECORD"
+
+const redactedValue = "REDACTED"
+
+type Mode int
+
+const (
+	// Serve responses from the fixture file, fail on unknown requests
+	ModeReplay Mode = iota
+
+	// Forward requests to the upstream transport and record the interactions
+	ModeRecord
+)
+
+// Headers and query parameters that are always redacted
+var (
+	sensitiveHeaders = []string{
+		"Authorization",
+		"Cookie",
+		"Set-Cookie",
+		"Proxy-Authorization",
+		"X-Api-Key",
+	}
+
+	sensitiveQueryParams = []string{
+		"api_key",
+		"apikey",
+		"access_token",
+		"token",
+		"key",
+	}
+)
+
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Sanitizer removes sensitive data from an interaction before it is saved
+type Sanitizer func(interaction *Interaction)
+
+type TransportConfig struct {
+	// Fixture file to replay from or record to
+	Path string
+	Mode Mode
+
+	// Optional, used in record mode, defaults to the shared transport
+	Upstream http.RoundTripper
+
+	// Optional, applied after the default sanitization
+	Sanitizers []Sanitizer
+}
+
+// Transport is a http.RoundTripper that records or replays interactions
+type Transport struct {
+	config TransportConfig
+
+	m            sync.Mutex
+	interactions []Interaction
+
+	// Replay position of each request key so that repeated
+	// requests are served in the order they were recorded
+	replayed map[string]int
+}
+
+// NewTransport creates a transport. Interactions are loaded from the
+// fixture file in replay mode.
+func NewTransport(config TransportConfig) (*Transport, error) {
+	if config.Path == "" {
+		return nil, errors.New("fixture path is required")
+	}
+
+	if config.Upstream == nil {
+		config.Upstream = httpclient.Transport()
+	}
+
+	t := &Transport{
+		config:       config,
+		interactions: []Interaction{},
+		replayed:     map[string]int{},
+	}
+
+	if config.Mode == ModeReplay {
+		data, err := os.ReadFile(config.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+
+		err = json.Unmarshal(data, &t.interactions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse fixture: %w", err)
+		}
+	}
+
+	return t, nil
+}
+
+// NewTestClient returns a client replaying the fixture at path. When
+// RecordEnvKey is set, the fixture is recorded instead and saved at the
+// end of the test.
+func NewTestClient(t testing.TB, path string) *http.Client {
+	t.Helper()
+
+	mode := ModeReplay
+	if record, _ := strconv.ParseBool(os.Getenv(RecordEnvKey)); record {
+		mode = ModeRecord
+	}
+
+	transport, err := NewTransport(TransportConfig{Path: path, Mode: mode})
+	if err != nil {
+		t.Fatalf("failed to create fixture transport: %v", err)
+	}
+
+	if mode == ModeRecord {
+		t.Cleanup(func() {
+			if err := transport.Save(); err != nil {
+				t.Errorf("failed to save fixture: %v", err)
+			}
+		})
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.config.Mode == ModeRecord {
+		return t.record(req, body)
+	}
+
+	return t.replay(req, body)
+}
+
+// Save writes the recorded interactions to the fixture file
+func (t *Transport) Save() error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	data, err := json.MarshalIndent(t.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize fixture: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(t.config.Path), 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+
+	return os.WriteFile(t.config.Path, data, 0644)
+}
+
+func (t *Transport) record(req *http.Request, body string) (*http.Response, error) {
+	res, err := t.config.Upstream.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: req.Header.Clone(),
+			Body:   body,
+		},
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Header:     res.Header.Clone(),
+			Body:       string(resBody),
+		},
+	}
+
+	sanitize(&interaction)
+	for _, sanitizer := range t.config.Sanitizers {
+		sanitizer(&interaction)
+	}
+
+	t.m.Lock()
+	t.interactions = append(t.interactions, interaction)
+	t.m.Unlock()
+
+	return newResponse(req, interaction.Response), nil
+}
+
+func (t *Transport) replay(req *http.Request, body string) (*http.Response, error) {
+	key := requestKey(req.Method, sanitizeURL(req.URL.String()), body)
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	skip := t.replayed[key]
+	for _, interaction := range t.interactions {
+		r := interaction.Request
+		if requestKey(r.Method, r.URL, r.Body) != key {
+			continue
+		}
+
+		if skip > 0 {
+			skip--
+			continue
+		}
+
+		t.replayed[key]++
+		return newResponse(req, interaction.Response), nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for %s %s in fixture %s",
+		req.Method, req.URL.String(), t.config.Path)
+}
+
+func requestKey(method, url, body string) string {
+	return fmt.Sprintf("%s %s\n%s", strings.ToUpper(method), url, body)
+}
+
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(data))
+
+	return string(data), nil
+}
+
+func newResponse(req *http.Request, r Response) *http.Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+func sanitize(interaction *Interaction) {
+	for _, name := range sensitiveHeaders {
+		if interaction.Request.Header.Get(name) != "" {
+			interaction.Request.Header.Set(name, redactedValue)
+		}
+
+		if interaction.Response.Header.Get(name) != "" {
+			interaction.Response.Header.Set(name, redactedValue)
+		}
+	}
+
+	interaction.Request.URL = sanitizeURL(interaction.Request.URL)
+}
+
+// Replayed requests carry real credentials, hence the URL is sanitized
+// before matching with the recorded interactions
+func sanitizeURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	if u.User != nil {
+		u.User = url.User(redactedValue)
+	}
+
+	query := u.Query()
+	for name := range query {
+		for _, sensitive := range sensitiveQueryParams {
+			if strings.EqualFold(name, sensitive) {
+				query.Set(name, redactedValue)
+			}
+		}
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,114 @@
+package fixtures
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"` + r.URL.Path + `","call":` + strconv.Itoa(calls) + `}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fixtures", "registry.json")
+
+	recorder, err := NewTransport(TransportConfig{Path: path, Mode: ModeRecord})
+	assert.NoError(t, err)
+
+	client := &http.Client{Transport: recorder}
+	for _, target := range []string{"/lodash?token=secret", "/lodash?token=secret", "/express"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+target, nil)
+		assert.NoError(t, err)
+
+		req.Header.Set("Authorization", "Bearer secret")
+
+		res, err := client.Do(req)
+		assert.NoError(t, err)
+		res.Body.Close()
+	}
+
+	assert.NoError(t, recorder.Save())
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+
+	replayer, err := NewTransport(TransportConfig{Path: path, Mode: ModeReplay})
+	assert.NoError(t, err)
+
+	client = &http.Client{Transport: replayer}
+
+	cases := []struct {
+		name   string
+		target string
+		body   string
+		err    string
+	}{
+		{"first request", "/lodash?token=other", `{"name":"/lodash","call":1}`, ""},
+		{"repeated request in recorded order", "/lodash?token=other", `{"name":"/lodash","call":2}`, ""},
+		{"another request", "/express", `{"name":"/express","call":3}`, ""},
+		{"replay exhausted", "/express", "", "no recorded interaction"},
+		{"unknown request", "/react", "", "no recorded interaction"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := client.Get(server.URL + test.target)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			defer res.Body.Close()
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+			assert.Equal(t, test.body, string(body))
+		})
+	}
+}
+
+func TestReplayMatchesRequestBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.json")
+	err := os.WriteFile(path, []byte(`[
+		{"request": {"method": "POST", "url": "https://api.example.com/query", "body": "a"},
+		 "response": {"status_code": 200, "body": "A"}},
+		{"request": {"method": "POST", "url": "https://api.example.com/query", "body": "b"},
+		 "response": {"status_code": 404, "body": "B"}}
+	]`), 0600)
+	assert.NoError(t, err)
+
+	client := NewTestClient(t, path)
+
+	res, err := client.Post("https://api.example.com/query", "text/plain", strings.NewReader("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	res.Body.Close()
+}
+
+func TestNewTransportRequiresFixture(t *testing.T) {
+	_, err := NewTransport(TransportConfig{})
+	assert.ErrorContains(t, err, "fixture path is required")
+
+	_, err = NewTransport(TransportConfig{Path: filepath.Join(t.TempDir(), "missing.json")})
+	assert.ErrorContains(t, err, "failed to read fixture")
+}
@@ -0,0 +1,267 @@
+// Package httpclient provides a shared HTTP transport for all outbound
+// HTTP requests so that connections are pooled across call sites instead
+// of each call site creating its own transport.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/logger"
+)
+
+const (
+	maxIdleConns        = 256
+	maxIdleConnsPerHost = 64
+	idleConnTimeout     = 90 * time.Second
+	dialTimeout         = 30 * time.Second
+	dialKeepAlive       = 30 * time.Second
+
+	// Upper bound of a request including reading the body. Large enough
+	// for artifact downloads while not letting a stalled server hang a
+	// request forever.
+	defaultTimeout = 5 * time.Minute
+
+	// Delay before racing the fallback address family (RFC 6555),
+	// same as the default of net.Dialer
+	fallbackDelay = 300 * time.Millisecond
+
+	// Resolved addresses are cached for this duration
+	dnsCacheTTL = 5 * time.Minute
+)
+
+var (
+	sharedTransport     *http.Transport
+	sharedTransportOnce sync.Once
+)
+
+// Transport returns the shared transport with connection pooling,
+// HTTP/2 and DNS caching. It is safe for concurrent use.
+func Transport() *http.Transport {
+	sharedTransportOnce.Do(func() {
+		dialer := &net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: dialKeepAlive,
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = newDNSCache(net.DefaultResolver, dnsCacheTTL).dialContext(dialer)
+		transport.ForceAttemptHTTP2 = true
+		transport.MaxIdleConns = maxIdleConns
+		transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+		transport.IdleConnTimeout = idleConnTimeout
+
+		sharedTransport = transport
+	})
+
+	return sharedTransport
+}
+
+// Default returns a client using the shared transport with a default
+// timeout. Callers may use request context for a shorter deadline.
+func Default() *http.Client {
+	return NewClient(defaultTimeout)
+}
+
+// NewClient returns a client using the shared transport
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: Transport(),
+		Timeout:   timeout,
+	}
+}
+
+type hostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+type dnsCache struct {
+	resolver hostResolver
+	ttl      time.Duration
+	now      func() time.Time
+
+	m       sync.Mutex
+	entries map[string]dnsCacheEntry
+}
+
+func newDNSCache(resolver hostResolver, ttl time.Duration) *dnsCache {
+	return &dnsCache{
+		resolver: resolver,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  map[string]dnsCacheEntry{},
+	}
+}
+
+func (c *dnsCache) lookup(ctx context.Context, host string) ([]string, error) {
+	c.m.Lock()
+	entry, ok := c.entries[host]
+	c.m.Unlock()
+
+	if ok && c.now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := c.resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	c.m.Lock()
+	c.entries[host] = dnsCacheEntry{addrs: addrs, expires: c.now().Add(c.ttl)}
+	c.m.Unlock()
+
+	return addrs, nil
+}
+
+// dialContext resolves the host using the cache and dials the resolved
+// addresses. IP addresses are dialed without resolution. Similar to
+// net.Dialer, addresses of the first address family are raced against
+// the other family after a short delay (Happy Eyeballs).
+func (c *dnsCache) dialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		if net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		addrs, err := c.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		primaries, fallbacks := partitionAddrs(addrs)
+		conn, err := dialParallel(ctx, dialer, network, port, primaries, fallbacks)
+		if err == nil {
+			return conn, nil
+		}
+
+		// Cached addresses may be stale, resolve again on next dial
+		c.m.Lock()
+		delete(c.entries, host)
+		c.m.Unlock()
+
+		logger.Debugf("Failed to dial any resolved address of %s: %v", host, err)
+		return nil, err
+	}
+}
+
+// partitionAddrs splits the addresses into those of the same family as
+// the first address and the rest, preserving order
+func partitionAddrs(addrs []string) (primaries, fallbacks []string) {
+	isIPv4 := func(addr string) bool {
+		ip := net.ParseIP(addr)
+		return ip != nil && ip.To4() != nil
+	}
+
+	for _, addr := range addrs {
+		if len(primaries) == 0 || isIPv4(addr) == isIPv4(primaries[0]) {
+			primaries = append(primaries, addr)
+		} else {
+			fallbacks = append(fallbacks, addr)
+		}
+	}
+
+	return primaries, fallbacks
+}
+
+type dialResult struct {
+	conn    net.Conn
+	err     error
+	primary bool
+}
+
+// dialParallel dials the primaries and, after fallbackDelay or as soon
+// as the primaries fail, the fallbacks. The first connection wins.
+func dialParallel(ctx context.Context, dialer *net.Dialer, network, port string,
+	primaries, fallbacks []string) (net.Conn, error) {
+	if len(fallbacks) == 0 {
+		return dialSerial(ctx, dialer, network, port, primaries)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan dialResult)
+	race := func(addrs []string, primary bool) {
+		conn, err := dialSerial(ctx, dialer, network, port, addrs)
+		select {
+		case results <- dialResult{conn: conn, err: err, primary: primary}:
+		case <-ctx.Done():
+			if conn != nil {
+				conn.Close()
+			}
+		}
+	}
+
+	go race(primaries, true)
+
+	fallbackTimer := time.NewTimer(fallbackDelay)
+	defer fallbackTimer.Stop()
+
+	var dialErr error
+	fallbackStarted := false
+	pending := 1
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-fallbackTimer.C:
+			if !fallbackStarted {
+				fallbackStarted = true
+				pending++
+				go race(fallbacks, false)
+			}
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				return res.conn, nil
+			}
+
+			dialErr = errors.Join(dialErr, res.err)
+			if res.primary && !fallbackStarted {
+				fallbackStarted = true
+				pending++
+				go race(fallbacks, false)
+			}
+
+			if pending == 0 {
+				return nil, dialErr
+			}
+		}
+	}
+}
+
+// dialSerial dials the addresses in order until one succeeds
+func dialSerial(ctx context.Context, dialer *net.Dialer, network, port string,
+	addrs []string) (net.Conn, error) {
+	var dialErr error
+	for _, addr := range addrs {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+		if err == nil {
+			return conn, nil
+		}
+
+		dialErr = errors.Join(dialErr, err)
+	}
+
+	if dialErr == nil {
+		dialErr = errors.New("no address to dial")
+	}
+
+	return nil, dialErr
+}
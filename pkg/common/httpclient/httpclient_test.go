@@ -0,0 +1,95 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingResolver struct {
+	calls int
+	addrs []string
+	err   error
+}
+
+func (r *countingResolver) LookupHost(_ context.Context, _ string) ([]string, error) {
+	r.calls += 1
+	return r.addrs, r.err
+}
+
+func TestDNSCacheLookup(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		name    string
+		err     error
+		elapsed time.Duration
+		calls   int
+	}{
+		{"Cached within TTL", nil, time.Minute, 1},
+		{"Resolved again after TTL", nil, 2 * time.Minute, 2},
+		{"Errors are not cached", errors.New("no such host"), 0, 2},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			resolver := &countingResolver{addrs: []string{"127.0.0.1"}, err: test.err}
+			cache := newDNSCache(resolver, 90*time.Second)
+			cache.now = func() time.Time { return now }
+
+			_, _ = cache.lookup(context.Background(), "example.com")
+
+			cache.now = func() time.Time { return now.Add(test.elapsed) }
+			addrs, err := cache.lookup(context.Background(), "example.com")
+
+			assert.Equal(t, test.calls, resolver.calls)
+			if test.err != nil {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"127.0.0.1"}, addrs)
+		})
+	}
+}
+
+func TestTransportIsShared(t *testing.T) {
+	assert.True(t, Transport() == Transport())
+	assert.True(t, Default().Transport == NewClient(time.Second).Transport)
+	assert.True(t, Transport().ForceAttemptHTTP2)
+}
+
+func TestPartitionAddrs(t *testing.T) {
+	primaries, fallbacks := partitionAddrs([]string{"::1", "127.0.0.1", "2001:db8::1", "10.0.0.1"})
+
+	assert.Equal(t, []string{"::1", "2001:db8::1"}, primaries)
+	assert.Equal(t, []string{"127.0.0.1", "10.0.0.1"}, fallbacks)
+}
+
+func TestDialParallelFallback(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	assert.NoError(t, err)
+
+	// 127.0.0.2 is not listening on the port, hence the fallback wins
+	conn, err := dialParallel(context.Background(), &net.Dialer{Timeout: time.Second},
+		"tcp", port, []string{"127.0.0.2"}, []string{"127.0.0.1"})
+	assert.NoError(t, err)
+
+	if assert.NotNil(t, conn) {
+		assert.Equal(t, listener.Addr().String(), conn.RemoteAddr().String())
+		conn.Close()
+	}
+
+	_, err = dialParallel(context.Background(), &net.Dialer{Timeout: time.Second},
+		"tcp", port, []string{"127.0.0.2"}, []string{"127.0.0.3"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+// Package paths resolves the paths written by vet. Paths are under the data
+// directory, ~/.safedep by default, unless the directory of their kind is
+// configured explicitly e.g. to a volume of a container. In stateless mode
+// only paths of explicitly configured kinds are resolved so that vet works
+// with a read-only file system.
+package paths
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+type Kind string
+
+const (
+	// Files not belonging to any other kind e.g. feedback
+	KindData = Kind("data")
+
+	// Results of analyzers and insights, safe to delete
+	KindCache = Kind("cache")
+
+	// Sync sessions spooled for publishing later
+	KindSpool = Kind("spool")
+
+	// Journals of sync sessions in progress and preview of telemetry
+	KindState = Kind("state")
+
+	// Fingerprints of scanned manifests
+	KindHistory = Kind("history")
+)
+
+const (
+	homeRelativeDataDir = ".safedep"
+
+	dataDirEnvKey   = "VET_DATA_DIR"
+	statelessEnvKey = "VET_STATELESS"
+)
+
+// Environment variables overriding the directory of a kind
+var dirEnvKeys = map[Kind]string{
+	KindCache:   "VET_CACHE_DIR",
+	KindSpool:   "VET_SPOOL_DIR",
+	KindState:   "VET_STATE_DIR",
+	KindHistory: "VET_HISTORY_DIR",
+}
+
+// ErrStateless is returned when resolving a path in stateless mode
+var ErrStateless = errors.New("no writable path in stateless mode")
+
+type Config struct {
+	// Directory of kinds not configured explicitly, ~/.safedep when empty
+	DataDir string
+
+	// Directory by kind, overrides the data directory
+	Dirs map[Kind]string
+
+	// Nothing is read from or written to the data directory
+	Stateless bool
+}
+
+var (
+	m       sync.RWMutex
+	current = Config{}
+)
+
+// ConfigFromEnvironment builds the configuration from VET_DATA_DIR,
+// VET_STATELESS and the directory of each kind e.g. VET_CACHE_DIR
+func ConfigFromEnvironment() (Config, error) {
+	config := Config{
+		DataDir: os.Getenv(dataDirEnvKey),
+		Dirs:    map[Kind]string{},
+	}
+
+	for kind, key := range dirEnvKeys {
+		if dir := os.Getenv(key); dir != "" {
+			config.Dirs[kind] = dir
+		}
+	}
+
+	if value := os.Getenv(statelessEnvKey); value != "" {
+		stateless, err := strconv.ParseBool(value)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid value of %s: %w", statelessEnvKey, err)
+		}
+
+		config.Stateless = stateless
+	}
+
+	return config, nil
+}
+
+// Configure sets the configuration used to resolve all paths. Must be
+// called before any path is resolved.
+func Configure(config Config) {
+	m.Lock()
+	defer m.Unlock()
+
+	current = config
+}
+
+// Stateless is true when nothing is to be written to disk
+func Stateless() bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	return current.Stateless
+}
+
+// Writable is true when paths of kind are resolved i.e. not in stateless
+// mode or the directory of kind is given
+func Writable(kind Kind) bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	return !current.Stateless || current.Dirs[kind] != ""
+}
+
+// Resolve returns the path of name in the directory of kind. Returns
+// ErrStateless in stateless mode unless a directory of kind is given.
+func Resolve(kind Kind, name string) (string, error) {
+	m.RLock()
+	config := current
+	m.RUnlock()
+
+	if dir := config.Dirs[kind]; dir != "" {
+		return filepath.Join(dir, name), nil
+	}
+
+	if config.Stateless {
+		return "", fmt.Errorf("%s path %s: %w", kind, name, ErrStateless)
+	}
+
+	dir := config.DataDir
+	if dir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+
+		dir = filepath.Join(homeDir, homeRelativeDataDir)
+	}
+
+	return filepath.Join(dir, name), nil
+}
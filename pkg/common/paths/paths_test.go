@@ -0,0 +1,108 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		config Config
+		kind   Kind
+		path   string
+		err    error
+	}{
+		{
+			"default data directory",
+			Config{},
+			KindCache,
+			filepath.Join(homeDir, ".safedep", "vet-cache"),
+			nil,
+		},
+		{
+			"configured data directory",
+			Config{DataDir: "/data"},
+			KindCache,
+			"/data/vet-cache",
+			nil,
+		},
+		{
+			"directory of kind overrides data directory",
+			Config{DataDir: "/data", Dirs: map[Kind]string{KindCache: "/cache"}},
+			KindCache,
+			"/cache/vet-cache",
+			nil,
+		},
+		{
+			"directory of other kind is not used",
+			Config{DataDir: "/data", Dirs: map[Kind]string{KindSpool: "/spool"}},
+			KindCache,
+			"/data/vet-cache",
+			nil,
+		},
+		{
+			"stateless",
+			Config{DataDir: "/data", Stateless: true},
+			KindCache,
+			"",
+			ErrStateless,
+		},
+		{
+			"stateless with directory of kind",
+			Config{DataDir: "/data", Dirs: map[Kind]string{KindCache: "/cache"}, Stateless: true},
+			KindCache,
+			"/cache/vet-cache",
+			nil,
+		},
+		{
+			"stateless with directory of other kind",
+			Config{DataDir: "/data", Dirs: map[Kind]string{KindSpool: "/spool"}, Stateless: true},
+			KindCache,
+			"",
+			ErrStateless,
+		},
+	}
+
+	defer Configure(Config{})
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			Configure(test.config)
+
+			path, err := Resolve(test.kind, "vet-cache")
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.path, path)
+			assert.Equal(t, test.config.Stateless, Stateless())
+			assert.Equal(t, test.err == nil, Writable(test.kind))
+		})
+	}
+}
+
+func TestConfigFromEnvironment(t *testing.T) {
+	t.Setenv("VET_DATA_DIR", "/data")
+	t.Setenv("VET_SPOOL_DIR", "/spool")
+	t.Setenv("VET_STATELESS", "true")
+
+	config, err := ConfigFromEnvironment()
+	assert.NoError(t, err)
+	assert.Equal(t, "/data", config.DataDir)
+	assert.Equal(t, map[Kind]string{KindSpool: "/spool"}, config.Dirs)
+	assert.True(t, config.Stateless)
+
+	t.Setenv("VET_STATELESS", "maybe")
+
+	_, err = ConfigFromEnvironment()
+	assert.Error(t, err)
+}
@@ -0,0 +1,216 @@
+// Package resources is the central configuration of resource caps for
+// subsystems handling untrusted content e.g. published artifacts of
+// packages, so that a malicious package can not exhaust the disk or memory
+// of a CI runner. CPU is capped by the worker pools of package concurrency.
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/safedep/vet/pkg/common/logger"
+)
+
+const memoryLimitEnvKey = "GOMEMLIMIT"
+
+// ErrLimitExceeded is returned when a resource cap is exceeded
+var ErrLimitExceeded = errors.New("resource limit exceeded")
+
+type Limits struct {
+	// Max size of a downloaded artifact
+	MaxDownloadSize int64
+
+	// Max size of the files of an artifact after extraction
+	MaxExtractedSize int64
+
+	// Max size of all temporary files in use at a time
+	MaxTempDiskSize int64
+
+	// Soft memory limit of the Go runtime, not set when zero
+	MemoryLimit int64
+}
+
+// DefaultLimits are large enough for ML wheels and electron apps
+func DefaultLimits() Limits {
+	return Limits{
+		MaxDownloadSize:  1 << 30,
+		MaxExtractedSize: 4 << 30,
+		MaxTempDiskSize:  8 << 30,
+	}
+}
+
+// withDefaults fills unset limits from default limits
+func (l Limits) withDefaults() Limits {
+	defaults := DefaultLimits()
+
+	if l.MaxDownloadSize <= 0 {
+		l.MaxDownloadSize = defaults.MaxDownloadSize
+	}
+
+	if l.MaxExtractedSize <= 0 {
+		l.MaxExtractedSize = defaults.MaxExtractedSize
+	}
+
+	if l.MaxTempDiskSize <= 0 {
+		l.MaxTempDiskSize = defaults.MaxTempDiskSize
+	}
+
+	return l
+}
+
+var (
+	m       sync.RWMutex
+	current = DefaultLimits()
+
+	// Bytes written to temporary files not yet removed
+	tempDiskUsage atomic.Int64
+)
+
+// Configure sets the limits used by all subsystems. Must be called
+// before the subsystems are created.
+func Configure(limits Limits) {
+	m.Lock()
+	defer m.Unlock()
+
+	current = limits.withDefaults()
+}
+
+// Current returns the configured limits
+func Current() Limits {
+	m.RLock()
+	defer m.RUnlock()
+
+	return current
+}
+
+// ApplyMemoryLimit sets the soft memory limit of the Go runtime so that
+// garbage is collected more aggressively near the limit instead of the
+// process being killed by the OOM killer. GOMEMLIMIT in environment takes
+// precedence. Returns true when the limit is set.
+func ApplyMemoryLimit(limit int64) bool {
+	if limit <= 0 {
+		return false
+	}
+
+	if value := os.Getenv(memoryLimitEnvKey); value != "" {
+		logger.Debugf("Memory limit is set by %s=%s, ignoring limit of %d bytes",
+			memoryLimitEnvKey, value, limit)
+		return false
+	}
+
+	debug.SetMemoryLimit(limit)
+	logger.Debugf("Memory limit set to %d bytes", limit)
+
+	return true
+}
+
+// TempDiskUsage returns the bytes written to temporary files in use
+func TempDiskUsage() int64 {
+	return tempDiskUsage.Load()
+}
+
+// TempFile is a temporary file with writes charged to the temp disk limit
+// shared by all temporary files. Remove releases the charged bytes.
+type TempFile struct {
+	file    *os.File
+	charged int64
+}
+
+// CreateTemp creates a temporary file in the default directory for
+// temporary files. The pattern is as in os.CreateTemp.
+func CreateTemp(pattern string) (*TempFile, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	return &TempFile{file: file}, nil
+}
+
+func (f *TempFile) Name() string {
+	return f.file.Name()
+}
+
+func (f *TempFile) Write(p []byte) (int, error) {
+	limit := Current().MaxTempDiskSize
+
+	size := int64(len(p))
+	if tempDiskUsage.Add(size) > limit {
+		tempDiskUsage.Add(-size)
+		return 0, fmt.Errorf("%w: temporary disk usage over %d bytes", ErrLimitExceeded, limit)
+	}
+
+	n, err := f.file.Write(p)
+
+	// Only the bytes actually written are charged
+	tempDiskUsage.Add(int64(n) - size)
+	f.charged += int64(n)
+
+	return n, err
+}
+
+func (f *TempFile) Close() error {
+	return f.file.Close()
+}
+
+// Remove closes and deletes the file
+func (f *TempFile) Remove() error {
+	_ = f.file.Close()
+
+	err := os.Remove(f.file.Name())
+
+	tempDiskUsage.Add(-f.charged)
+	f.charged = 0
+
+	return err
+}
+
+var sizeUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"KiB", 1 << 10},
+	{"MiB", 1 << 20},
+	{"GiB", 1 << 30},
+	{"TiB", 1 << 40},
+	{"KB", 1000},
+	{"MB", 1000 * 1000},
+	{"GB", 1000 * 1000 * 1000},
+	{"TB", 1000 * 1000 * 1000 * 1000},
+	{"B", 1},
+}
+
+// ParseSize parses a size in bytes with an optional unit suffix in the
+// format of GOMEMLIMIT e.g. 512MiB. Zero is returned for an empty size.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	value, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size: %s", s)
+	}
+
+	if value > (1<<63-1)/multiplier {
+		return 0, fmt.Errorf("size is too large: %s", s)
+	}
+
+	return value * multiplier, nil
+}
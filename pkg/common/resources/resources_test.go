@@ -0,0 +1,101 @@
+package resources
+
+import (
+	"io"
+	"os"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSize(t *testing.T) {
+	cases := []struct {
+		name string
+		size string
+		want int64
+		err  bool
+	}{
+		{"empty", "", 0, false},
+		{"bytes", "1024", 1024, false},
+		{"bytes with unit", "1024B", 1024, false},
+		{"binary unit", "512MiB", 512 << 20, false},
+		{"decimal unit", "2GB", 2000 * 1000 * 1000, false},
+		{"space before unit", "1 GiB", 1 << 30, false},
+		{"negative", "-1", 0, true},
+		{"unknown unit", "1PiB", 0, true},
+		{"overflow", "9000000000TiB", 0, true},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			size, err := ParseSize(test.size)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, size)
+		})
+	}
+}
+
+func TestConfigureDefaults(t *testing.T) {
+	t.Cleanup(func() { Configure(DefaultLimits()) })
+
+	Configure(Limits{MaxDownloadSize: 1024})
+
+	limits := Current()
+	assert.Equal(t, int64(1024), limits.MaxDownloadSize)
+	assert.Equal(t, DefaultLimits().MaxExtractedSize, limits.MaxExtractedSize)
+	assert.Equal(t, DefaultLimits().MaxTempDiskSize, limits.MaxTempDiskSize)
+}
+
+func TestTempFileDiskLimit(t *testing.T) {
+	t.Cleanup(func() { Configure(DefaultLimits()) })
+
+	Configure(Limits{MaxTempDiskSize: 1024})
+
+	first, err := CreateTemp("vet-test-*")
+	require.NoError(t, err)
+
+	_, err = io.Copy(first, strings.NewReader(strings.Repeat("x", 768)))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(768), TempDiskUsage())
+
+	// Limit is shared by all temporary files
+	second, err := CreateTemp("vet-test-*")
+	require.NoError(t, err)
+
+	_, err = second.Write([]byte(strings.Repeat("x", 512)))
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	assert.NoError(t, first.Remove())
+	assert.NoFileExists(t, first.Name())
+
+	_, err = second.Write([]byte(strings.Repeat("x", 512)))
+	assert.NoError(t, err)
+
+	assert.NoError(t, second.Remove())
+	assert.Equal(t, int64(0), TempDiskUsage())
+}
+
+func TestApplyMemoryLimit(t *testing.T) {
+	previous := debug.SetMemoryLimit(-1)
+	t.Cleanup(func() { debug.SetMemoryLimit(previous) })
+
+	t.Setenv(memoryLimitEnvKey, "")
+	os.Unsetenv(memoryLimitEnvKey)
+
+	assert.False(t, ApplyMemoryLimit(0))
+
+	assert.True(t, ApplyMemoryLimit(1<<30))
+	assert.Equal(t, int64(1<<30), debug.SetMemoryLimit(-1))
+
+	t.Setenv(memoryLimitEnvKey, "512MiB")
+	assert.False(t, ApplyMemoryLimit(2<<30))
+	assert.Equal(t, int64(1<<30), debug.SetMemoryLimit(-1))
+}
@@ -2,10 +2,10 @@ package sbom
 
 import (
 	"github.com/google/osv-scanner/pkg/lockfile"
-	"github.com/safedep/vet/pkg/common/purl"
+	"github.com/safedep/vet/pkg/purl"
 )
 
-// DEPRECATED: Use purl.PurlTypeToEcosystem directly
+// DEPRECATED: Use purl.TypeToEcosystem directly
 func PurlTypeToLockfileEcosystem(purl_type string) (lockfile.Ecosystem, error) {
-	return purl.PurlTypeToEcosystem(purl_type)
+	return purl.TypeToEcosystem(purl_type)
 }
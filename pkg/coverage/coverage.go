@@ -0,0 +1,148 @@
+// Package coverage records manifests that were detected but not scanned so
+// that reports show the blind spots of a scan instead of implying that every
+// dependency was covered.
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Reason string
+
+const (
+	// Manifest of an ecosystem or format that vet does not support
+	ReasonUnsupported = Reason("unsupported")
+
+	// Manifest is supported but failed to parse
+	ReasonParseFailed = Reason("parse_failed")
+
+	// Manifest or directory matched an exclusion of the user
+	ReasonExcluded = Reason("excluded")
+)
+
+type knownManifest struct {
+	ecosystem string
+
+	// Lockfiles next to the manifest that cover its dependencies
+	lockfiles []string
+}
+
+// Well known manifests of package managers, used to detect manifests that
+// vet does not have a parser for
+var knownManifests = map[string]knownManifest{
+	"Cargo.lock":           {ecosystem: "Cargo"},
+	"Cargo.toml":           {ecosystem: "Cargo", lockfiles: []string{"Cargo.lock"}},
+	"mix.lock":             {ecosystem: "Hex"},
+	"pubspec.lock":         {ecosystem: "Pub"},
+	"pubspec.yaml":         {ecosystem: "Pub", lockfiles: []string{"pubspec.lock"}},
+	"Package.resolved":     {ecosystem: "SwiftURL"},
+	"Podfile.lock":         {ecosystem: "CocoaPods"},
+	"packages.lock.json":   {ecosystem: "NuGet"},
+	"packages.config":      {ecosystem: "NuGet"},
+	"paket.lock":           {ecosystem: "NuGet"},
+	"uv.lock":              {ecosystem: "PyPI"},
+	"pdm.lock":             {ecosystem: "PyPI"},
+	"Pipfile":              {ecosystem: "PyPI", lockfiles: []string{"Pipfile.lock"}},
+	"pyproject.toml":       {ecosystem: "PyPI", lockfiles: []string{"poetry.lock", "uv.lock", "pdm.lock"}},
+	"bun.lockb":            {ecosystem: "npm"},
+	"bun.lock":             {ecosystem: "npm"},
+	"build.gradle":         {ecosystem: "Maven", lockfiles: []string{"gradle.lockfile"}},
+	"build.gradle.kts":     {ecosystem: "Maven", lockfiles: []string{"gradle.lockfile"}},
+	"build.sbt":            {ecosystem: "Maven"},
+	"Gemfile":              {ecosystem: "RubyGems", lockfiles: []string{"Gemfile.lock"}},
+	"composer.json":        {ecosystem: "Packagist", lockfiles: []string{"composer.lock"}},
+	"conan.lock":           {ecosystem: "ConanCenter"},
+	"vcpkg.json":           {ecosystem: "vcpkg"},
+	"cabal.project.freeze": {ecosystem: "Hackage"},
+	"stack.yaml.lock":      {ecosystem: "Hackage"},
+	"renv.lock":            {ecosystem: "CRAN"},
+}
+
+var knownManifestExtensions = map[string]string{
+	".csproj": "NuGet",
+	".fsproj": "NuGet",
+	".vbproj": "NuGet",
+}
+
+// KnownManifest returns the ecosystem of a well known manifest of a package
+// manager unless a lockfile next to it covers its dependencies. It does not
+// mean that vet supports the manifest.
+func KnownManifest(path string) (string, bool) {
+	base := filepath.Base(path)
+	if manifest, ok := knownManifests[base]; ok {
+		for _, lockfile := range manifest.lockfiles {
+			if _, err := os.Stat(filepath.Join(filepath.Dir(path), lockfile)); err == nil {
+				return "", false
+			}
+		}
+
+		return manifest.ecosystem, true
+	}
+
+	ecosystem, ok := knownManifestExtensions[strings.ToLower(filepath.Ext(base))]
+	return ecosystem, ok
+}
+
+// Record of a manifest or directory that was not scanned
+type Record struct {
+	Path string
+
+	// Empty when not known e.g. an excluded directory
+	Ecosystem string
+
+	Reason Reason
+	Detail string
+}
+
+// Recorder collects what was not scanned during a scan. A nil Recorder
+// discards the records.
+type Recorder struct {
+	m       sync.Mutex
+	records map[string]Record
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{records: make(map[string]Record)}
+}
+
+// Record a path as not scanned, the last record of a path wins
+func (r *Recorder) Record(path, ecosystem string, reason Reason, detail string) {
+	if r == nil {
+		return
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.records[path] = Record{
+		Path:      path,
+		Ecosystem: ecosystem,
+		Reason:    reason,
+		Detail:    detail,
+	}
+}
+
+// Records returns what was not scanned ordered by path
+func (r *Recorder) Records() []Record {
+	if r == nil {
+		return nil
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	records := make([]Record, 0, len(r.records))
+	for _, record := range r.records {
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Path < records[j].Path
+	})
+
+	return records
+}
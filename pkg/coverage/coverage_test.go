@@ -0,0 +1,54 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKnownManifest(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Gemfile.lock"), []byte{}, 0600))
+
+	cases := []struct {
+		name      string
+		path      string
+		ecosystem string
+		known     bool
+	}{
+		{"unsupported lockfile", filepath.Join(dir, "Cargo.lock"), "Cargo", true},
+		{"manifest without lockfile", filepath.Join(dir, "pyproject.toml"), "PyPI", true},
+		{"manifest covered by lockfile", filepath.Join(dir, "Gemfile"), "", false},
+		{"project file by extension", filepath.Join(dir, "App.CSPROJ"), "NuGet", true},
+		{"not a manifest", filepath.Join(dir, "README.md"), "", false},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			ecosystem, known := KnownManifest(test.path)
+			assert.Equal(t, test.known, known)
+			assert.Equal(t, test.ecosystem, ecosystem)
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	r.Record("/src/b/Cargo.lock", "Cargo", ReasonUnsupported, "no supported parser")
+	r.Record("/src/a/package-lock.json", "npm", ReasonParseFailed, "unexpected EOF")
+	r.Record("/src/b/Cargo.lock", "Cargo", ReasonExcluded, "manifest matched exclusion")
+
+	records := r.Records()
+	assert.Len(t, records, 2)
+	assert.Equal(t, "/src/a/package-lock.json", records[0].Path)
+	assert.Equal(t, ReasonParseFailed, records[0].Reason)
+	assert.Equal(t, ReasonExcluded, records[1].Reason)
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.Record("/src/Cargo.lock", "Cargo", ReasonUnsupported, "")
+	assert.Empty(t, r.Records())
+}
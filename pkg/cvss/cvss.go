@@ -0,0 +1,127 @@
+// Package cvss computes CVSS v3 scores adjusted for the environment of the
+// project so that e.g. an internet facing service handling sensitive data
+// is gated stricter than an internal tool
+package cvss
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/facebookincubator/nvdtools/cvss3"
+)
+
+// Exposure of the project to attackers
+type Exposure string
+
+const (
+	// Reachable from the internet, attack vector is not adjusted
+	ExposureInternet = Exposure("internet")
+
+	// Reachable only from the internal network, network attack vector
+	// is adjusted to adjacent
+	ExposureInternal = Exposure("internal")
+
+	// Not reachable over network, network and adjacent attack vectors
+	// are adjusted to local
+	ExposureLocal = Exposure("local")
+)
+
+// Sensitivity of the data handled by the project, used as the
+// confidentiality and integrity requirement
+type Sensitivity string
+
+const (
+	SensitivityHigh   = Sensitivity("high")
+	SensitivityMedium = Sensitivity("medium")
+	SensitivityLow    = Sensitivity("low")
+)
+
+// Environment of the project, empty values do not adjust the score
+type Environment struct {
+	Exposure        Exposure
+	DataSensitivity Sensitivity
+}
+
+// Validate returns an error for unknown exposure or sensitivity
+func (e Environment) Validate() error {
+	switch e.Exposure {
+	case "", ExposureInternet, ExposureInternal, ExposureLocal:
+	default:
+		return fmt.Errorf("invalid exposure %q, must be one of internet, internal, local", e.Exposure)
+	}
+
+	switch e.DataSensitivity {
+	case "", SensitivityHigh, SensitivityMedium, SensitivityLow:
+	default:
+		return fmt.Errorf("invalid data sensitivity %q, must be one of high, medium, low", e.DataSensitivity)
+	}
+
+	return nil
+}
+
+// Score of a vulnerability, Adjusted is the environmental score
+type Score struct {
+	Base     float64
+	Adjusted float64
+}
+
+// Compute returns the base and adjusted score of a CVSS v3 vector. A
+// numeric score is returned as is since it cannot be adjusted.
+func Compute(vector string, env Environment) (Score, error) {
+	vector = strings.TrimSpace(vector)
+	if value, err := strconv.ParseFloat(vector, 64); err == nil {
+		return Score{Base: value, Adjusted: value}, nil
+	}
+
+	v, err := cvss3.VectorFromString(vector)
+	if err != nil {
+		return Score{}, fmt.Errorf("invalid CVSS v3 vector: %w", err)
+	}
+
+	if err := v.Validate(); err != nil {
+		return Score{}, fmt.Errorf("invalid CVSS v3 vector: %w", err)
+	}
+
+	base := v.BaseScore()
+
+	metrics := environmentalMetrics(v, env)
+	if len(metrics) > 0 {
+		// Version does not matter for the metrics absorbed
+		adjustment, err := cvss3.VectorFromString("CVSS:3.1/" + strings.Join(metrics, "/"))
+		if err != nil {
+			return Score{}, fmt.Errorf("invalid environmental metrics: %w", err)
+		}
+
+		v.Absorb(adjustment)
+	}
+
+	return Score{Base: base, Adjusted: v.EnvironmentalScore()}, nil
+}
+
+func environmentalMetrics(v cvss3.Vector, env Environment) []string {
+	metrics := []string{}
+
+	attackVector := v.BaseMetrics.AttackVector.String()
+	switch env.Exposure {
+	case ExposureInternal:
+		if attackVector == "N" {
+			metrics = append(metrics, "MAV:A")
+		}
+	case ExposureLocal:
+		if attackVector == "N" || attackVector == "A" {
+			metrics = append(metrics, "MAV:L")
+		}
+	}
+
+	switch env.DataSensitivity {
+	case SensitivityHigh:
+		metrics = append(metrics, "CR:H", "IR:H")
+	case SensitivityMedium:
+		metrics = append(metrics, "CR:M", "IR:M")
+	case SensitivityLow:
+		metrics = append(metrics, "CR:L", "IR:L")
+	}
+
+	return metrics
+}
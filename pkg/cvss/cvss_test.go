@@ -0,0 +1,104 @@
+package cvss
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompute(t *testing.T) {
+	cases := []struct {
+		name     string
+		vector   string
+		env      Environment
+		base     float64
+		adjusted float64
+		err      bool
+	}{
+		{
+			"no environment",
+			"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			Environment{},
+			9.8,
+			9.8,
+			false,
+		},
+		{
+			"internet facing with sensitive data",
+			"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N",
+			Environment{Exposure: ExposureInternet, DataSensitivity: SensitivityHigh},
+			6.5,
+			8.3,
+			false,
+		},
+		{
+			"internal tool",
+			"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			Environment{Exposure: ExposureInternal},
+			9.8,
+			8.8,
+			false,
+		},
+		{
+			"local with low sensitivity",
+			"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			Environment{Exposure: ExposureLocal, DataSensitivity: SensitivityLow},
+			9.8,
+			7.5,
+			false,
+		},
+		{
+			"physical attack vector is not raised",
+			"CVSS:3.0/AV:P/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N",
+			Environment{Exposure: ExposureLocal},
+			4.6,
+			4.6,
+			false,
+		},
+		{
+			"numeric score",
+			"7.5",
+			Environment{Exposure: ExposureInternal},
+			7.5,
+			7.5,
+			false,
+		},
+		{
+			"cvss v2 vector",
+			"AV:N/AC:L/Au:N/C:P/I:P/A:P",
+			Environment{},
+			0,
+			0,
+			true,
+		},
+		{
+			"incomplete vector",
+			"CVSS:3.1/AV:N",
+			Environment{},
+			0,
+			0,
+			true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			score, err := Compute(test.vector, test.env)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.base, score.Base)
+			assert.Equal(t, test.adjusted, score.Adjusted)
+		})
+	}
+}
+
+func TestEnvironmentValidate(t *testing.T) {
+	assert.NoError(t, Environment{}.Validate())
+	assert.NoError(t, Environment{Exposure: ExposureInternal, DataSensitivity: SensitivityHigh}.Validate())
+	assert.ErrorContains(t, Environment{Exposure: "public"}.Validate(), "invalid exposure")
+	assert.ErrorContains(t, Environment{DataSensitivity: "secret"}.Validate(), "invalid data sensitivity")
+}
@@ -0,0 +1,171 @@
+// Package degradation defines the behavior of a scan when an upstream
+// service fails. The behavior is configured per data source as a matrix
+// and every degradation applied during a scan is recorded so that it can
+// be reported along with the results.
+package degradation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Source string
+
+const (
+	// Package insights used for enrichment
+	SourceInsights = Source("insights")
+
+	// Vulnerability data of packages
+	SourceOsv = Source("osv")
+
+	// ControlTower used for syncing the results
+	SourceControlTower = Source("controltower")
+)
+
+type Mode string
+
+const (
+	// Failure is not handled, same as without degradation
+	ModeFail = Mode("fail")
+
+	// Use the last known data from cache
+	ModeCache = Mode("cache")
+
+	// Mark the data as unknown instead of treating it as empty
+	ModeUnknown = Mode("unknown")
+
+	// Spool the data locally for publishing later
+	ModeSpool = Mode("spool")
+)
+
+var supportedModes = map[Source][]Mode{
+	SourceInsights:     {ModeCache, ModeFail},
+	SourceOsv:          {ModeUnknown, ModeFail},
+	SourceControlTower: {ModeSpool, ModeFail},
+}
+
+// Matrix maps a data source to the mode of degradation
+type Matrix map[Source]Mode
+
+// DefaultMatrix degrades every source instead of failing
+func DefaultMatrix() Matrix {
+	return Matrix{
+		SourceInsights:     ModeCache,
+		SourceOsv:          ModeUnknown,
+		SourceControlTower: ModeSpool,
+	}
+}
+
+// ParseMatrix overrides the default matrix with specs of the form
+// source=mode e.g. insights=fail
+func ParseMatrix(specs []string) (Matrix, error) {
+	matrix := DefaultMatrix()
+	for _, spec := range specs {
+		source, mode, found := strings.Cut(spec, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid degradation %q, expected source=mode", spec)
+		}
+
+		source = strings.ToLower(strings.TrimSpace(source))
+		mode = strings.ToLower(strings.TrimSpace(mode))
+
+		modes, ok := supportedModes[Source(source)]
+		if !ok {
+			return nil, fmt.Errorf("unknown degradation source: %s", source)
+		}
+
+		if !containsMode(modes, Mode(mode)) {
+			return nil, fmt.Errorf("unsupported degradation mode %q for %s, supported: %v",
+				mode, source, modes)
+		}
+
+		matrix[Source(source)] = Mode(mode)
+	}
+
+	return matrix, nil
+}
+
+// Mode returns the mode of the source, ModeFail when not configured
+func (m Matrix) Mode(source Source) Mode {
+	if mode, ok := m[source]; ok {
+		return mode
+	}
+
+	return ModeFail
+}
+
+func containsMode(modes []Mode, mode Mode) bool {
+	for _, m := range modes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Record is a degradation applied during a scan
+type Record struct {
+	Source Source
+	Mode   Mode
+
+	// Number of times the degradation was applied
+	Count int
+
+	// Last failure of the source
+	Reason string
+}
+
+// Recorder collects the degradations applied during a scan. A nil
+// Recorder discards the degradations.
+type Recorder struct {
+	m       sync.Mutex
+	records map[Source]*Record
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{records: make(map[Source]*Record)}
+}
+
+func (r *Recorder) Record(source Source, mode Mode, reason error) {
+	if r == nil {
+		return
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	record, ok := r.records[source]
+	if !ok || record.Mode != mode {
+		record = &Record{Source: source, Mode: mode}
+		r.records[source] = record
+	}
+
+	record.Count++
+	if reason != nil {
+		record.Reason = reason.Error()
+	}
+}
+
+// Records returns the degradations ordered by source
+func (r *Recorder) Records() []Record {
+	if r == nil {
+		return nil
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	records := make([]Record, 0, len(r.records))
+	for _, record := range r.records {
+		records = append(records, *record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Source < records[j].Source
+	})
+
+	return records
+}
@@ -0,0 +1,84 @@
+package degradation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMatrix(t *testing.T) {
+	cases := []struct {
+		name     string
+		specs    []string
+		expected Matrix
+		err      string
+	}{
+		{
+			"defaults",
+			nil,
+			DefaultMatrix(),
+			"",
+		},
+		{
+			"override",
+			[]string{"insights=fail", " ControlTower = FAIL "},
+			Matrix{
+				SourceInsights:     ModeFail,
+				SourceOsv:          ModeUnknown,
+				SourceControlTower: ModeFail,
+			},
+			"",
+		},
+		{
+			"invalid spec",
+			[]string{"insights"},
+			nil,
+			"expected source=mode",
+		},
+		{
+			"unknown source",
+			[]string{"registry=cache"},
+			nil,
+			"unknown degradation source",
+		},
+		{
+			"unsupported mode",
+			[]string{"osv=cache"},
+			nil,
+			"unsupported degradation mode",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			matrix, err := ParseMatrix(test.specs)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, matrix)
+		})
+	}
+
+	assert.Equal(t, ModeFail, Matrix{}.Mode(SourceInsights))
+}
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+
+	recorder.Record(SourceOsv, ModeUnknown, errors.New("timeout"))
+	recorder.Record(SourceInsights, ModeCache, errors.New("unavailable"))
+	recorder.Record(SourceOsv, ModeUnknown, errors.New("connection refused"))
+
+	assert.Equal(t, []Record{
+		{Source: SourceInsights, Mode: ModeCache, Count: 1, Reason: "unavailable"},
+		{Source: SourceOsv, Mode: ModeUnknown, Count: 2, Reason: "connection refused"},
+	}, recorder.Records())
+
+	var nilRecorder *Recorder
+	nilRecorder.Record(SourceOsv, ModeUnknown, nil)
+	assert.Empty(t, nilRecorder.Records())
+}
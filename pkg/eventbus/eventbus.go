@@ -0,0 +1,116 @@
+// Package eventbus is an internal publish / subscribe bus for events of a
+// scan. Subsystems such as the progress UI, telemetry and plugins subscribe
+// to events instead of being called directly by the subsystem raising them.
+package eventbus
+
+import (
+	"runtime/debug"
+	"sync"
+
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/models"
+)
+
+type Topic string
+
+const (
+	TopicScanStarted     = Topic("scan.started")
+	TopicManifestParsed  = Topic("manifest.parsed")
+	TopicPackageEnriched = Topic("package.enriched")
+	TopicFindingEmitted  = Topic("finding.emitted")
+	TopicSyncFailed      = Topic("sync.failed")
+)
+
+// Event carries the data relevant to its topic, other fields are nil
+type Event struct {
+	Topic Topic
+
+	// Available for manifest and package events
+	Manifest *models.PackageManifest
+
+	// Available for package events
+	Package *models.Package
+
+	// Available for finding events
+	Finding *analyzer.AnalyzerEvent
+
+	// Available for failure events
+	Err error
+}
+
+type Handler func(event Event)
+
+type subscription struct {
+	id      uint64
+	handler Handler
+}
+
+// Bus dispatches events to subscribers synchronously in the order of
+// subscription. Handlers may be invoked concurrently when events are
+// published from multiple goroutines. A nil Bus discards events.
+type Bus struct {
+	m      sync.RWMutex
+	nextId uint64
+	topics map[Topic][]subscription
+}
+
+func New() *Bus {
+	return &Bus{topics: make(map[Topic][]subscription)}
+}
+
+// Subscribe registers the handler for the topics and returns a function
+// to unsubscribe
+func (b *Bus) Subscribe(handler Handler, topics ...Topic) func() {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.nextId++
+	id := b.nextId
+
+	for _, topic := range topics {
+		b.topics[topic] = append(b.topics[topic], subscription{id: id, handler: handler})
+	}
+
+	return func() {
+		b.m.Lock()
+		defer b.m.Unlock()
+
+		for _, topic := range topics {
+			subscriptions := b.topics[topic]
+			for i, s := range subscriptions {
+				if s.id == id {
+					b.topics[topic] = append(subscriptions[:i:i], subscriptions[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+}
+
+// Publish invokes the subscribers of the event topic. A panic in a handler
+// is logged and does not affect other subscribers or the publisher.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.m.RLock()
+	subscriptions := b.topics[event.Topic]
+	b.m.RUnlock()
+
+	for _, s := range subscriptions {
+		dispatch(s.handler, event)
+	}
+}
+
+func dispatch(handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("Event bus: Handler for %s panicked: %v\n%s",
+				event.Topic, r, debug.Stack())
+		}
+	}()
+
+	handler(event)
+}
@@ -0,0 +1,50 @@
+package eventbus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusPublish(t *testing.T) {
+	bus := New()
+
+	received := []string{}
+	unsubscribe := bus.Subscribe(func(event Event) {
+		received = append(received, "first:"+string(event.Topic))
+	}, TopicScanStarted, TopicSyncFailed)
+
+	bus.Subscribe(func(event Event) {
+		panic("handler failed")
+	}, TopicSyncFailed)
+
+	bus.Subscribe(func(event Event) {
+		received = append(received, "last:"+event.Err.Error())
+	}, TopicSyncFailed)
+
+	bus.Publish(Event{Topic: TopicScanStarted})
+	bus.Publish(Event{Topic: TopicSyncFailed, Err: errors.New("unavailable")})
+	bus.Publish(Event{Topic: TopicManifestParsed})
+
+	assert.Equal(t, []string{
+		"first:scan.started",
+		"first:sync.failed",
+		"last:unavailable",
+	}, received)
+
+	unsubscribe()
+	received = nil
+
+	bus.Publish(Event{Topic: TopicScanStarted})
+	bus.Publish(Event{Topic: TopicSyncFailed, Err: errors.New("unavailable")})
+
+	assert.Equal(t, []string{"last:unavailable"}, received)
+}
+
+func TestNilBusPublish(t *testing.T) {
+	var bus *Bus
+	assert.NotPanics(t, func() {
+		bus.Publish(Event{Topic: TopicScanStarted})
+	})
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/safedep/vet/gen/exceptionsapi"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/naming"
 )
 
 var (
@@ -131,7 +132,8 @@ func (r *exceptionRule) matchByPattern(_ *models.Package) bool {
 
 func (r *exceptionRule) matchByVersion(pkg *models.Package) bool {
 	return strings.EqualFold(string(pkg.PackageDetails.Ecosystem), r.spec.GetEcosystem()) &&
-		strings.EqualFold(pkg.PackageDetails.Name, r.spec.GetName()) &&
+		strings.EqualFold(naming.Normalize(r.spec.GetEcosystem(), pkg.PackageDetails.Name),
+			naming.Normalize(r.spec.GetEcosystem(), r.spec.GetName())) &&
 		((r.spec.GetVersion() == "*") || (r.spec.GetVersion() == pkg.PackageDetails.Version))
 }
 
@@ -142,7 +144,7 @@ func (r *exceptionMatchResult) Matched() bool {
 func pkgHash(ecosystem, name string) string {
 	h := fnv.New64a()
 	h.Write([]byte(fmt.Sprintf("%s/%s",
-		strings.ToLower(ecosystem), strings.ToLower(name))))
+		strings.ToLower(ecosystem), strings.ToLower(naming.Normalize(ecosystem, name)))))
 
 	return strconv.FormatUint(h.Sum64(), 16)
 }
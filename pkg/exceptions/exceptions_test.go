@@ -206,6 +206,25 @@ func TestApply(t *testing.T) {
 			true,
 			"",
 		},
+		{
+			"Match with normalized name",
+			[]exceptionRule{
+				{
+					spec: &exceptionsapi.Exception{
+						Id:        "a",
+						Ecosystem: "PYPI",
+						Name:      "Django_REST",
+						Version:   "v1",
+					},
+					expiry: time.Now().Add(1 * time.Hour),
+				},
+			},
+			"PyPI",
+			"django-rest",
+			"v1",
+			true,
+			"",
+		},
 		{
 			"No match with case-insensitive version",
 			[]exceptionRule{
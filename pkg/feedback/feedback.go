@@ -0,0 +1,151 @@
+package feedback
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/common/paths"
+	"github.com/safedep/vet/pkg/naming"
+)
+
+const feedbackFileName = "vet-feedback.json"
+
+// Verdict is the user feedback on a finding
+type Verdict string
+
+const (
+	VerdictTruePositive  = Verdict("true-positive")
+	VerdictFalsePositive = Verdict("false-positive")
+)
+
+// Record is the feedback on a finding for a package version
+type Record struct {
+	Ecosystem string               `json:"ecosystem"`
+	Name      string               `json:"name"`
+	Version   string               `json:"version"`
+	Kind      analyzer.FindingKind `json:"kind"`
+	Verdict   Verdict              `json:"verdict"`
+	Comment   string               `json:"comment,omitempty"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+// Store persists feedback records
+type Store interface {
+	Add(record Record) error
+	Records() ([]Record, error)
+}
+
+type FileStoreConfig struct {
+	Path string
+}
+
+type fileStore struct {
+	m      sync.Mutex
+	config FileStoreConfig
+}
+
+// DefaultFileStoreConfig stores feedback in the data directory
+func DefaultFileStoreConfig() (FileStoreConfig, error) {
+	path, err := paths.Resolve(paths.KindData, feedbackFileName)
+	if err != nil {
+		return FileStoreConfig{}, err
+	}
+
+	return FileStoreConfig{Path: path}, nil
+}
+
+// NewFileStore creates a store backed by a JSON file
+func NewFileStore(config FileStoreConfig) (Store, error) {
+	if config.Path == "" {
+		return nil, errors.New("feedback store path is required")
+	}
+
+	return &fileStore{config: config}, nil
+}
+
+func (s *fileStore) Add(record Record) error {
+	if record.Name == "" || record.Kind == "" {
+		return errors.New("feedback record must have a package name and finding kind")
+	}
+
+	if record.Verdict != VerdictTruePositive && record.Verdict != VerdictFalsePositive {
+		return fmt.Errorf("invalid feedback verdict: %s", record.Verdict)
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	// Latest feedback on the same finding replaces the earlier one
+	updated := []Record{}
+	for _, r := range records {
+		if !r.sameFinding(&record) {
+			updated = append(updated, r)
+		}
+	}
+
+	updated = append(updated, record)
+	return s.write(updated)
+}
+
+func (s *fileStore) Records() ([]Record, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.read()
+}
+
+func (s *fileStore) read() ([]Record, error) {
+	data, err := os.ReadFile(s.config.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Record{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to read feedback file: %w", err)
+	}
+
+	records := []Record{}
+	err = json.Unmarshal(data, &records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse feedback file: %w", err)
+	}
+
+	return records, nil
+}
+
+func (s *fileStore) write(records []Record) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize feedback: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.config.Path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create feedback directory: %w", err)
+	}
+
+	return os.WriteFile(s.config.Path, data, 0600)
+}
+
+func (r *Record) sameFinding(other *Record) bool {
+	return strings.EqualFold(r.Ecosystem, other.Ecosystem) &&
+		naming.Equal(r.Ecosystem, r.Name, other.Name) &&
+		r.Version == other.Version &&
+		r.Kind == other.Kind
+}
@@ -0,0 +1,106 @@
+package feedback
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(FileStoreConfig{Path: filepath.Join(t.TempDir(), "feedback.json")})
+	assert.Nil(t, err)
+
+	records, err := store.Records()
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+
+	record := Record{Ecosystem: "npm", Name: "a", Version: "1.0.0",
+		Kind: analyzer.FindingKindMalware, Verdict: VerdictFalsePositive}
+
+	assert.Nil(t, store.Add(record))
+
+	record.Verdict = VerdictTruePositive
+	assert.Nil(t, store.Add(record))
+
+	records, err = store.Records()
+	assert.Nil(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, VerdictTruePositive, records[0].Verdict)
+	assert.False(t, records[0].CreatedAt.IsZero())
+
+	err = store.Add(Record{Name: "a", Kind: analyzer.FindingKindMalware, Verdict: "maybe"})
+	assert.ErrorContains(t, err, "invalid feedback verdict")
+}
+
+func TestFindingFilter(t *testing.T) {
+	fp := func(name string) Record {
+		return Record{Ecosystem: "npm", Name: name, Version: "1.0.0",
+			Kind: analyzer.FindingKindMalware, Verdict: VerdictFalsePositive}
+	}
+
+	finding := func(name string, confidence analyzer.FindingConfidence) *analyzer.Finding {
+		return &analyzer.Finding{
+			Kind:       analyzer.FindingKindMalware,
+			Confidence: confidence,
+			Package: &models.Package{
+				PackageDetails: models.NewPackageDetail(models.EcosystemNpm, name, "1.0.0"),
+			},
+		}
+	}
+
+	malware := finding("a", analyzer.FindingConfidenceHigh)
+	malware.Package.MalwareAnalysis = &models.MalwareAnalysisResult{IsMalware: true}
+
+	cases := []struct {
+		name    string
+		records []Record
+		finding *analyzer.Finding
+		allow   bool
+	}{
+		{
+			"No feedback",
+			[]Record{},
+			finding("a", analyzer.FindingConfidenceLow),
+			true,
+		},
+		{
+			"Package marked as false positive",
+			[]Record{fp("a")},
+			finding("a", analyzer.FindingConfidenceHigh),
+			false,
+		},
+		{
+			"Malware is not dropped when marked as false positive",
+			[]Record{fp("a")},
+			malware,
+			true,
+		},
+		{
+			"Low confidence dropped with high false positive rate",
+			[]Record{fp("a"), fp("b"), fp("c")},
+			finding("d", analyzer.FindingConfidenceMedium),
+			false,
+		},
+		{
+			"High confidence allowed with high false positive rate",
+			[]Record{fp("a"), fp("b"), fp("c")},
+			finding("d", analyzer.FindingConfidenceHigh),
+			true,
+		},
+		{
+			"Threshold not adjusted below min samples",
+			[]Record{fp("a"), fp("b")},
+			finding("d", analyzer.FindingConfidenceLow),
+			true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.allow, NewFindingFilter(test.records).Allow(test.finding))
+		})
+	}
+}
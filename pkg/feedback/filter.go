@@ -0,0 +1,111 @@
+package feedback
+
+import (
+	"strings"
+
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/naming"
+)
+
+const (
+	// Min number of feedback records of a finding kind before
+	// the confidence threshold of the kind is adjusted
+	feedbackMinSamples = 3
+
+	// False positive rate at which the confidence threshold is raised
+	feedbackFalsePositiveRateMedium = 0.25
+	feedbackFalsePositiveRateHigh   = 0.5
+)
+
+type findingFilter struct {
+	records    []Record
+	thresholds map[analyzer.FindingKind]analyzer.FindingConfidence
+}
+
+var _ analyzer.FindingFilter = (*findingFilter)(nil)
+
+// NewFindingFilter creates a filter from feedback records. Findings
+// explicitly marked as false positive are dropped. Heuristic findings
+// with confidence below the threshold of their kind are also dropped
+// where the threshold is raised with the false positive rate of the kind.
+// Packages classified as malware are never dropped.
+func NewFindingFilter(records []Record) analyzer.FindingFilter {
+	return &findingFilter{
+		records:    records,
+		thresholds: ConfidenceThresholds(records),
+	}
+}
+
+// ConfidenceThresholds computes the min confidence required for
+// findings of each kind based on the false positive rate
+func ConfidenceThresholds(records []Record) map[analyzer.FindingKind]analyzer.FindingConfidence {
+	total := map[analyzer.FindingKind]int{}
+	falsePositives := map[analyzer.FindingKind]int{}
+
+	for _, r := range records {
+		total[r.Kind]++
+		if r.Verdict == VerdictFalsePositive {
+			falsePositives[r.Kind]++
+		}
+	}
+
+	thresholds := map[analyzer.FindingKind]analyzer.FindingConfidence{}
+	for kind, count := range total {
+		if count < feedbackMinSamples {
+			continue
+		}
+
+		rate := float64(falsePositives[kind]) / float64(count)
+		switch {
+		case rate >= feedbackFalsePositiveRateHigh:
+			thresholds[kind] = analyzer.FindingConfidenceHigh
+		case rate >= feedbackFalsePositiveRateMedium:
+			thresholds[kind] = analyzer.FindingConfidenceMedium
+		}
+	}
+
+	return thresholds
+}
+
+func (f *findingFilter) Allow(finding *analyzer.Finding) bool {
+	// Malware verdict is a decision on verified or trusted analysis,
+	// not a heuristic that feedback can override
+	if finding.Package != nil && finding.Package.IsMalware() {
+		return true
+	}
+
+	if finding.Package != nil {
+		for _, r := range f.records {
+			if r.Kind != finding.Kind || !f.matchPackage(&r, finding) {
+				continue
+			}
+
+			if r.Verdict == VerdictTruePositive {
+				return true
+			}
+
+			logger.Infof("Feedback: Dropping %s finding of %s/%s/%s marked as false positive",
+				finding.Kind, finding.Package.Ecosystem, finding.Package.GetName(), finding.Package.GetVersion())
+
+			return false
+		}
+	}
+
+	threshold, ok := f.thresholds[finding.Kind]
+	if !ok || finding.Confidence.AtLeast(threshold) {
+		return true
+	}
+
+	logger.Infof("Feedback: Dropping %s finding %q with confidence %s below threshold %s",
+		finding.Kind, finding.Title, finding.Confidence, threshold)
+
+	return false
+}
+
+func (f *findingFilter) matchPackage(r *Record, finding *analyzer.Finding) bool {
+	pkg := finding.Package
+	return strings.EqualFold(r.Ecosystem, string(pkg.Ecosystem)) &&
+		naming.Equal(r.Ecosystem, r.Name, pkg.GetName()) &&
+		(r.Version == "" || r.Version == pkg.GetVersion())
+}
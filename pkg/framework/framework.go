@@ -0,0 +1,126 @@
+// Package framework detects the application framework (stack) around a
+// manifest e.g. Next.js, so that findings can be grouped by stack.
+package framework
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/safedep/vet/pkg/models"
+)
+
+// Framework is the identifier of an application framework
+type Framework string
+
+const (
+	FrameworkNextJs     = Framework("nextjs")
+	FrameworkDjango     = Framework("django")
+	FrameworkSpringBoot = Framework("spring-boot")
+)
+
+// rule detects a framework by a package of the manifest or a marker file
+// in the directory of a local manifest
+type rule struct {
+	framework Framework
+	ecosystem string
+
+	// Normalized package names, a name ending with `:` is a Maven group
+	packages []string
+
+	files []string
+}
+
+var rules = []rule{
+	{
+		framework: FrameworkNextJs,
+		ecosystem: models.EcosystemNpm,
+		packages:  []string{"next"},
+		files:     []string{"next.config.js", "next.config.mjs", "next.config.ts"},
+	},
+	{
+		framework: FrameworkDjango,
+		ecosystem: models.EcosystemPyPI,
+		packages:  []string{"django"},
+		files:     []string{"manage.py"},
+	},
+	{
+		framework: FrameworkSpringBoot,
+		ecosystem: models.EcosystemMaven,
+		packages:  []string{"org.springframework.boot:"},
+	},
+}
+
+// Detect returns the frameworks used by the manifest in sorted order
+func Detect(manifest *models.PackageManifest) []Framework {
+	detected := map[Framework]bool{}
+
+	for _, pkg := range manifest.GetPackages() {
+		ecosystem := string(pkg.Ecosystem)
+		for _, r := range rules {
+			if !strings.EqualFold(ecosystem, r.ecosystem) {
+				continue
+			}
+
+			if matchPackage(r, pkg.GetNormalizedName()) {
+				detected[r.framework] = true
+			}
+		}
+	}
+
+	// Marker files are only available for local manifests
+	if manifest.GetSource().GetType() == models.ManifestSourceLocal {
+		dir := filepath.Dir(manifest.GetPath())
+		for _, r := range rules {
+			if !detected[r.framework] && hasAnyFile(dir, r.files) {
+				detected[r.framework] = true
+			}
+		}
+	}
+
+	frameworks := make([]Framework, 0, len(detected))
+	for f := range detected {
+		frameworks = append(frameworks, f)
+	}
+
+	sort.Slice(frameworks, func(i, j int) bool {
+		return frameworks[i] < frameworks[j]
+	})
+
+	return frameworks
+}
+
+// Annotate attaches the frameworks detected around the manifest
+func Annotate(manifest *models.PackageManifest) {
+	frameworks := Detect(manifest)
+
+	manifest.Frameworks = make([]string, 0, len(frameworks))
+	for _, f := range frameworks {
+		manifest.Frameworks = append(manifest.Frameworks, string(f))
+	}
+}
+
+func matchPackage(r rule, name string) bool {
+	for _, p := range r.packages {
+		if strings.HasSuffix(p, ":") && strings.HasPrefix(name, p) {
+			return true
+		}
+
+		if name == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasAnyFile(dir string, files []string) bool {
+	for _, file := range files {
+		if info, err := os.Stat(filepath.Join(dir, file)); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,123 @@
+package framework
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		name       string
+		ecosystem  string
+		packages   []string
+		files      []string
+		frameworks []Framework
+	}{
+		{
+			"no framework",
+			models.EcosystemNpm,
+			[]string{"lodash", "express"},
+			nil,
+			[]Framework{},
+		},
+		{
+			"nextjs package",
+			models.EcosystemNpm,
+			[]string{"react", "next"},
+			nil,
+			[]Framework{FrameworkNextJs},
+		},
+		{
+			"nextjs config",
+			models.EcosystemNpm,
+			[]string{"react"},
+			[]string{"next.config.mjs"},
+			[]Framework{FrameworkNextJs},
+		},
+		{
+			"django package is normalized",
+			models.EcosystemPyPI,
+			[]string{"Django"},
+			nil,
+			[]Framework{FrameworkDjango},
+		},
+		{
+			"django manage.py",
+			models.EcosystemPyPI,
+			[]string{"requests"},
+			[]string{"manage.py"},
+			[]Framework{FrameworkDjango},
+		},
+		{
+			"spring boot group",
+			models.EcosystemMaven,
+			[]string{"org.springframework.boot:spring-boot-starter-web"},
+			nil,
+			[]Framework{FrameworkSpringBoot},
+		},
+		{
+			"spring without boot",
+			models.EcosystemMaven,
+			[]string{"org.springframework:spring-core"},
+			nil,
+			[]Framework{},
+		},
+		{
+			"package of other ecosystem",
+			models.EcosystemPyPI,
+			[]string{"next"},
+			nil,
+			[]Framework{},
+		},
+		{
+			"sorted",
+			models.EcosystemPyPI,
+			[]string{"django"},
+			[]string{"next.config.js"},
+			[]Framework{FrameworkDjango, FrameworkNextJs},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, file := range test.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte{}, 0600))
+			}
+
+			manifest := models.NewPackageManifestFromLocal(filepath.Join(dir, "lockfile"), test.ecosystem)
+			for _, name := range test.packages {
+				manifest.AddPackage(&models.Package{
+					PackageDetails: models.NewPackageDetail(test.ecosystem, name, "1.0.0"),
+				})
+			}
+
+			assert.Equal(t, test.frameworks, Detect(manifest))
+		})
+	}
+}
+
+func TestDetectMarkerFileOfRemoteManifest(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "manage.py"), []byte{}, 0600))
+
+	manifest := models.NewPackageManifestFromPurl("pkg:pypi/requests@2.0.0", models.EcosystemPyPI)
+	manifest.Path = filepath.Join(dir, "requirements.txt")
+
+	assert.Empty(t, Detect(manifest))
+}
+
+func TestAnnotate(t *testing.T) {
+	manifest := models.NewPackageManifestFromLocal("/app/package-lock.json", models.EcosystemNpm)
+	manifest.AddPackage(&models.Package{
+		PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "next", "14.0.0"),
+	})
+
+	Annotate(manifest)
+	assert.Equal(t, []string{"nextjs"}, manifest.GetFrameworks())
+}
@@ -0,0 +1,278 @@
+// Package health computes a composite health score for a package
+// using the insights collected during enrichment. The score is explainable
+// i.e. every contributing factor is available with its own score, weight
+// and a human readable reason.
+package health
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/vet/gen/insightapi"
+	"github.com/safedep/vet/pkg/models"
+)
+
+type Factor string
+
+const (
+	FactorMaintenance   = Factor("maintenance")
+	FactorPopularity    = Factor("popularity")
+	FactorScorecard     = Factor("scorecard")
+	FactorVulnerability = Factor("vulnerability")
+	FactorMaintainer    = Factor("maintainer")
+
+	// Score is always normalized to this range
+	MinScore = 0.0
+	MaxScore = 10.0
+
+	// Stars count at which popularity is considered to be max
+	popularityMaxStars = 10000
+)
+
+// Opinionated weights for composite score. Factors that are not
+// available for a package are excluded and the remaining weights
+// are re-normalized.
+var defaultWeights = map[Factor]float64{
+	FactorMaintenance:   0.25,
+	FactorPopularity:    0.15,
+	FactorScorecard:     0.20,
+	FactorVulnerability: 0.30,
+	FactorMaintainer:    0.10,
+}
+
+// Penalty for each vulnerability based on risk
+var vulnerabilityPenalty = map[insightapi.PackageVulnerabilitySeveritiesRisk]float64{
+	insightapi.PackageVulnerabilitySeveritiesRiskCRITICAL: 5,
+	insightapi.PackageVulnerabilitySeveritiesRiskHIGH:     3,
+	insightapi.PackageVulnerabilitySeveritiesRiskMEDIUM:   1,
+	insightapi.PackageVulnerabilitySeveritiesRiskLOW:      0.5,
+}
+
+type FactorScore struct {
+	Factor    Factor  `json:"factor"`
+	Score     float64 `json:"score"`
+	Weight    float64 `json:"weight"`
+	Reason    string  `json:"reason"`
+	Available bool    `json:"available"`
+}
+
+type Score struct {
+	// Composite score between [MinScore, MaxScore]
+	Score float64 `json:"score"`
+
+	// Breakdown of the score by factors
+	Factors []FactorScore `json:"factors"`
+
+	// Score is available only when at least one factor is available
+	Available bool `json:"available"`
+}
+
+// Compute builds the composite health score for a package
+func Compute(pkg *models.Package) *Score {
+	insights := utils.SafelyGetValue(pkg.Insights)
+
+	factors := []FactorScore{
+		maintenanceFactor(&insights),
+		popularityFactor(&insights),
+		scorecardFactor(&insights),
+		vulnerabilityFactor(&insights),
+		maintainerFactor(&insights),
+	}
+
+	score := Score{Factors: factors}
+
+	totalWeight := 0.0
+	weighted := 0.0
+	for i := range factors {
+		factors[i].Weight = defaultWeights[factors[i].Factor]
+		if !factors[i].Available {
+			continue
+		}
+
+		totalWeight += factors[i].Weight
+		weighted += factors[i].Weight * factors[i].Score
+	}
+
+	if totalWeight > 0 {
+		score.Available = true
+		score.Score = round(weighted / totalWeight)
+	}
+
+	return &score
+}
+
+// Factor returns the score of a factor if available
+func (s *Score) Factor(factor Factor) (FactorScore, bool) {
+	for _, f := range s.Factors {
+		if f.Factor == factor && f.Available {
+			return f, true
+		}
+	}
+
+	return FactorScore{}, false
+}
+
+// Weakest returns the available factor with the lowest score
+func (s *Score) Weakest() (FactorScore, bool) {
+	var weakest *FactorScore
+	for i := range s.Factors {
+		f := &s.Factors[i]
+		if !f.Available {
+			continue
+		}
+
+		if weakest == nil || f.Score < weakest.Score {
+			weakest = f
+		}
+	}
+
+	if weakest == nil {
+		return FactorScore{}, false
+	}
+
+	return *weakest, true
+}
+
+func maintenanceFactor(insights *insightapi.PackageVersionInsight) FactorScore {
+	fs := FactorScore{Factor: FactorMaintenance}
+
+	score, ok := scorecardCheckScore(insights, insightapi.ScorecardV2CheckNameMaintained)
+	if !ok {
+		fs.Reason = "Scorecard maintenance check not available"
+		return fs
+	}
+
+	fs.Available = true
+	fs.Score = score
+	fs.Reason = fmt.Sprintf("Scorecard maintenance check score is %.1f", score)
+
+	return fs
+}
+
+func popularityFactor(insights *insightapi.PackageVersionInsight) FactorScore {
+	fs := FactorScore{Factor: FactorPopularity}
+
+	projects := utils.SafelyGetValue(insights.Projects)
+	if len(projects) == 0 {
+		fs.Reason = "Source project information not available"
+		return fs
+	}
+
+	stars := utils.SafelyGetValue(projects[0].Stars)
+
+	fs.Available = true
+	fs.Score = normalize(MaxScore * math.Log10(float64(stars)+1) / math.Log10(popularityMaxStars))
+	fs.Reason = fmt.Sprintf("Source project has %d stars", stars)
+
+	return fs
+}
+
+func scorecardFactor(insights *insightapi.PackageVersionInsight) FactorScore {
+	fs := FactorScore{Factor: FactorScorecard}
+
+	scorecard := utils.SafelyGetValue(insights.Scorecard)
+	content := utils.SafelyGetValue(scorecard.Content)
+	if content.Score == nil {
+		fs.Reason = "OpenSSF Scorecard not available"
+		return fs
+	}
+
+	fs.Available = true
+	fs.Score = normalize(float64(utils.SafelyGetValue(content.Score)))
+	fs.Reason = fmt.Sprintf("OpenSSF Scorecard aggregate score is %.1f", fs.Score)
+
+	return fs
+}
+
+func vulnerabilityFactor(insights *insightapi.PackageVersionInsight) FactorScore {
+	fs := FactorScore{Factor: FactorVulnerability}
+
+	if insights.Vulnerabilities == nil {
+		fs.Reason = "Vulnerability information not available"
+		return fs
+	}
+
+	penalty := 0.0
+	counts := map[insightapi.PackageVulnerabilitySeveritiesRisk]int{}
+
+	for _, vuln := range utils.SafelyGetValue(insights.Vulnerabilities) {
+		risk := insightapi.PackageVulnerabilitySeveritiesRiskUNKNOWN
+		for _, s := range utils.SafelyGetValue(vuln.Severities) {
+			sType := utils.SafelyGetValue(s.Type)
+			if (sType == insightapi.PackageVulnerabilitySeveritiesTypeCVSSV3) ||
+				(sType == insightapi.PackageVulnerabilitySeveritiesTypeCVSSV2) {
+				risk = utils.SafelyGetValue(s.Risk)
+				break
+			}
+		}
+
+		counts[risk] += 1
+		penalty += vulnerabilityPenalty[risk]
+	}
+
+	fs.Available = true
+	fs.Score = normalize(MaxScore - penalty)
+	fs.Reason = fmt.Sprintf("Critical:%d High:%d Medium:%d Low:%d known vulnerabilities",
+		counts[insightapi.PackageVulnerabilitySeveritiesRiskCRITICAL],
+		counts[insightapi.PackageVulnerabilitySeveritiesRiskHIGH],
+		counts[insightapi.PackageVulnerabilitySeveritiesRiskMEDIUM],
+		counts[insightapi.PackageVulnerabilitySeveritiesRiskLOW])
+
+	return fs
+}
+
+func maintainerFactor(insights *insightapi.PackageVersionInsight) FactorScore {
+	fs := FactorScore{Factor: FactorMaintainer}
+
+	codeReview, crOk := scorecardCheckScore(insights, insightapi.ScorecardV2CheckNameCodeReview)
+	contributors, cOk := scorecardCheckScore(insights, insightapi.ScorecardV2CheckNameContributors)
+
+	switch {
+	case crOk && cOk:
+		fs.Score = round((codeReview + contributors) / 2)
+	case crOk:
+		fs.Score = codeReview
+	case cOk:
+		fs.Score = contributors
+	default:
+		fs.Reason = "Scorecard code review and contributors checks not available"
+		return fs
+	}
+
+	fs.Available = true
+	fs.Reason = fmt.Sprintf("Scorecard maintainer signals (code review, contributors) score is %.1f",
+		fs.Score)
+
+	return fs
+}
+
+func scorecardCheckScore(insights *insightapi.PackageVersionInsight,
+	name insightapi.ScorecardV2CheckName) (float64, bool) {
+	scorecard := utils.SafelyGetValue(insights.Scorecard)
+	content := utils.SafelyGetValue(scorecard.Content)
+
+	for _, check := range utils.SafelyGetValue(content.Checks) {
+		if utils.SafelyGetValue(check.Name) != name {
+			continue
+		}
+
+		// Scorecard uses -1 for inconclusive checks
+		score := float64(utils.SafelyGetValue(check.Score))
+		if score < MinScore {
+			return 0, false
+		}
+
+		return normalize(score), true
+	}
+
+	return 0, false
+}
+
+func normalize(score float64) float64 {
+	return round(math.Max(MinScore, math.Min(MaxScore, score)))
+}
+
+func round(score float64) float64 {
+	return math.Round(score*10) / 10
+}
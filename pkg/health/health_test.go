@@ -0,0 +1,172 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/safedep/vet/gen/insightapi"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompute(t *testing.T) {
+	critical := insightapi.PackageVulnerabilitySeveritiesRiskCRITICAL
+	cvss3 := insightapi.PackageVulnerabilitySeveritiesTypeCVSSV3
+
+	scorecardWithChecks := func(aggregate float32, checks map[insightapi.ScorecardV2CheckName]float32) *insightapi.Scorecard {
+		c := []insightapi.ScorecardV2Check{}
+		for name, score := range checks {
+			c = append(c, insightapi.ScorecardV2Check{Name: &name, Score: &score})
+		}
+
+		return &insightapi.Scorecard{
+			Content: &insightapi.ScorecardContentV2{
+				Score:  &aggregate,
+				Checks: &c,
+			},
+		}
+	}
+
+	stars := 10000
+
+	cases := []struct {
+		name      string
+		insights  *insightapi.PackageVersionInsight
+		available bool
+		score     float64
+		weakest   Factor
+	}{
+		{
+			"No insights",
+			nil,
+			false,
+			0,
+			"",
+		},
+		{
+			"Healthy package",
+			&insightapi.PackageVersionInsight{
+				Vulnerabilities: &[]insightapi.PackageVulnerability{},
+				Projects: &[]insightapi.PackageProjectInfo{
+					{Stars: &stars},
+				},
+				Scorecard: scorecardWithChecks(10, map[insightapi.ScorecardV2CheckName]float32{
+					insightapi.ScorecardV2CheckNameMaintained:   10,
+					insightapi.ScorecardV2CheckNameCodeReview:   10,
+					insightapi.ScorecardV2CheckNameContributors: 10,
+				}),
+			},
+			true,
+			10,
+			FactorMaintenance,
+		},
+		{
+			"Vulnerable and unmaintained package",
+			&insightapi.PackageVersionInsight{
+				Vulnerabilities: &[]insightapi.PackageVulnerability{
+					{
+						Severities: &[]struct {
+							Risk  *insightapi.PackageVulnerabilitySeveritiesRisk `json:"risk,omitempty"`
+							Score *string                                        `json:"score,omitempty"`
+							Type  *insightapi.PackageVulnerabilitySeveritiesType `json:"type,omitempty"`
+						}{
+							{Risk: &critical, Type: &cvss3},
+						},
+					},
+				},
+				Scorecard: scorecardWithChecks(4, map[insightapi.ScorecardV2CheckName]float32{
+					insightapi.ScorecardV2CheckNameMaintained: 0,
+				}),
+			},
+			true,
+			3.1,
+			FactorMaintenance,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			score := Compute(&models.Package{Insights: test.insights})
+
+			assert.Equal(t, test.available, score.Available)
+			assert.Equal(t, test.score, score.Score)
+			assert.Len(t, score.Factors, 5)
+
+			if test.available {
+				weakest, ok := score.Weakest()
+				assert.True(t, ok)
+				assert.Equal(t, test.weakest, weakest.Factor)
+			}
+		})
+	}
+}
+
+func TestComputeInherited(t *testing.T) {
+	vulnerableWithStars := func(stars, vulns int) *insightapi.PackageVersionInsight {
+		critical := insightapi.PackageVulnerabilitySeveritiesRiskCRITICAL
+		cvss3 := insightapi.PackageVulnerabilitySeveritiesTypeCVSSV3
+
+		v := []insightapi.PackageVulnerability{}
+		for i := 0; i < vulns; i++ {
+			v = append(v, insightapi.PackageVulnerability{
+				Severities: &[]struct {
+					Risk  *insightapi.PackageVulnerabilitySeveritiesRisk `json:"risk,omitempty"`
+					Score *string                                        `json:"score,omitempty"`
+					Type  *insightapi.PackageVulnerabilitySeveritiesType `json:"type,omitempty"`
+				}{
+					{Risk: &critical, Type: &cvss3},
+				},
+			})
+		}
+
+		return &insightapi.PackageVersionInsight{
+			Vulnerabilities: &v,
+			Projects:        &[]insightapi.PackageProjectInfo{{Stars: &stars}},
+		}
+	}
+
+	newPackage := func(name string, insights *insightapi.PackageVersionInsight) *models.Package {
+		return &models.Package{
+			PackageDetails: lockfile.PackageDetails{
+				Name:      name,
+				Version:   "1.0.0",
+				Ecosystem: models.EcosystemNpm,
+			},
+			Insights: insights,
+		}
+	}
+
+	manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+
+	a := newPackage("a", vulnerableWithStars(10000, 0))
+	b := newPackage("b", vulnerableWithStars(10000, 0))
+	c := newPackage("c", vulnerableWithStars(10000, 2))
+	d := newPackage("d", vulnerableWithStars(10, 1))
+	e := newPackage("e", nil)
+
+	manifest.DependencyGraph.AddRootNode(a)
+	manifest.DependencyGraph.AddRootNode(d)
+	manifest.DependencyGraph.AddDependency(a, b)
+	manifest.DependencyGraph.AddDependency(b, c)
+	manifest.DependencyGraph.AddDependency(b, e)
+	manifest.DependencyGraph.SetPresent(true)
+
+	scores := ComputeInherited(manifest)
+	assert.Len(t, scores, 2)
+
+	assert.Equal(t, "a", scores[0].Package.GetName())
+	assert.True(t, scores[0].Inherited())
+	assert.Equal(t, "c", scores[0].Contributor().GetName())
+	assert.Equal(t, []string{"a", "b", "c"}, []string{
+		scores[0].Path[0].GetName(),
+		scores[0].Path[1].GetName(),
+		scores[0].Path[2].GetName(),
+	})
+
+	assert.Equal(t, "d", scores[1].Package.GetName())
+	assert.False(t, scores[1].Inherited())
+	assert.Equal(t, scores[1].Own.Score, scores[1].Score)
+
+	assert.Empty(t, ComputeInherited(models.NewPackageManifestFromLocal("go.mod",
+		models.EcosystemGo)))
+}
@@ -0,0 +1,143 @@
+package health
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/safedep/vet/pkg/models"
+)
+
+// InheritedScore is the health of a direct dependency after taking into
+// account the worst package in its transitive dependency subtree. Replacing
+// or upgrading the direct dependency is usually the only actionable fix
+// for a risky transitive dependency.
+type InheritedScore struct {
+	// The direct dependency
+	Package *models.Package
+
+	// Own health score of the direct dependency
+	Own *Score
+
+	// Lowest health score among the direct dependency and
+	// its transitive dependencies
+	Score float64
+
+	// Path from the direct dependency to the package contributing
+	// the inherited score. Contains only the direct dependency when
+	// it is the weakest package in its subtree.
+	Path []*models.Package
+}
+
+// Inherited returns true when the score is contributed by a
+// transitive dependency instead of the direct dependency itself
+func (s *InheritedScore) Inherited() bool {
+	return len(s.Path) > 1
+}
+
+// Contributor returns the package contributing the inherited score
+func (s *InheritedScore) Contributor() *models.Package {
+	return s.Path[len(s.Path)-1]
+}
+
+// ComputeInherited propagates health scores from transitive dependencies up
+// to the direct dependencies of a manifest. Returns an empty list when the
+// dependency graph is not available for the manifest. Results are sorted by
+// ascending score i.e. riskiest direct dependency first.
+func ComputeInherited(manifest *models.PackageManifest) []*InheritedScore {
+	results := []*InheritedScore{}
+
+	dg := manifest.DependencyGraph
+	if dg == nil || !dg.Present() {
+		return results
+	}
+
+	scores := map[string]*Score{}
+	scoreOf := func(pkg *models.Package) *Score {
+		if s, ok := scores[pkg.Id()]; ok {
+			return s
+		}
+
+		s := Compute(pkg)
+		scores[pkg.Id()] = s
+
+		return s
+	}
+
+	for _, node := range dg.GetNodes() {
+		if !node.Root {
+			continue
+		}
+
+		results = append(results, inheritedScoreFor(dg, node.Data, scoreOf))
+	}
+
+	slices.SortFunc(results, func(a, b *InheritedScore) int {
+		if a.Score == b.Score {
+			return cmp.Compare(a.Package.Id(), b.Package.Id())
+		}
+
+		return cmp.Compare(a.Score, b.Score)
+	})
+
+	return results
+}
+
+// Breadth first traversal of the subtree so that the shortest path to the
+// weakest package is reported. Packages without an available score do not
+// contribute to the inherited score.
+func inheritedScoreFor(dg *models.DependencyGraph[*models.Package],
+	root *models.Package, scoreOf func(*models.Package) *Score,
+) *InheritedScore {
+	own := scoreOf(root)
+	result := &InheritedScore{
+		Package: root,
+		Own:     own,
+		Score:   MaxScore,
+		Path:    []*models.Package{root},
+	}
+
+	if own.Available {
+		result.Score = own.Score
+	}
+
+	parents := map[string]*models.Package{}
+	visited := map[string]bool{root.Id(): true}
+	queue := []*models.Package{root}
+
+	var weakest *models.Package
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+
+		for _, dep := range dg.GetDependencies(pkg) {
+			if visited[dep.Id()] {
+				continue
+			}
+
+			visited[dep.Id()] = true
+			parents[dep.Id()] = pkg
+			queue = append(queue, dep)
+
+			s := scoreOf(dep)
+			if s.Available && s.Score < result.Score {
+				result.Score = s.Score
+				weakest = dep
+			}
+		}
+	}
+
+	if weakest == nil {
+		return result
+	}
+
+	path := []*models.Package{weakest}
+	for node := weakest; node.Id() != root.Id(); {
+		node = parents[node.Id()]
+		path = append(path, node)
+	}
+
+	slices.Reverse(path)
+	result.Path = path
+
+	return result
+}
@@ -0,0 +1,107 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/safedep/vet/pkg/models"
+)
+
+// ManifestFingerprint identifies the content of a manifest and the set of
+// dependencies resolved from it. Equal fingerprints across scans mean that
+// the manifest need not be scanned again.
+type ManifestFingerprint struct {
+	Ecosystem string `json:"ecosystem"`
+	Path      string `json:"path"`
+
+	// Empty when the manifest is not a local file e.g. a purl
+	ContentHash string `json:"content_hash,omitempty"`
+
+	DependencySetHash string    `json:"dependency_set_hash"`
+	PackageCount      int       `json:"package_count"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// Key identifies the manifest across scans
+func (f ManifestFingerprint) Key() string {
+	return fmt.Sprintf("%s:%s", strings.ToLower(f.Ecosystem), f.Path)
+}
+
+// Same is true when both fingerprints are of unchanged manifest content
+// and dependency set
+func (f ManifestFingerprint) Same(other ManifestFingerprint) bool {
+	return f.Key() == other.Key() &&
+		f.ContentHash == other.ContentHash &&
+		f.DependencySetHash == other.DependencySetHash
+}
+
+// NewManifestFingerprint computes the fingerprint of a parsed manifest
+func NewManifestFingerprint(manifest *models.PackageManifest) (ManifestFingerprint, error) {
+	contentHash, err := fileContentHash(manifest.GetPath())
+	if err != nil {
+		return ManifestFingerprint{}, err
+	}
+
+	packages := manifest.GetPackages()
+
+	return ManifestFingerprint{
+		Ecosystem:         manifest.Ecosystem,
+		Path:              manifest.GetDisplayPath(),
+		ContentHash:       contentHash,
+		DependencySetHash: dependencySetHash(packages),
+		PackageCount:      len(packages),
+		CreatedAt:         time.Now(),
+	}, nil
+}
+
+func fileContentHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to open manifest: %w", err)
+	}
+
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat manifest: %w", err)
+	}
+
+	// Manifests such as a directory of jars are identified by the
+	// dependency set only
+	if !stat.Mode().IsRegular() {
+		return "", nil
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// The hash is independent of the order in which packages are resolved
+func dependencySetHash(packages []*models.Package) string {
+	entries := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		entries = append(entries, fmt.Sprintf("%s/%s@%s",
+			strings.ToLower(string(pkg.Ecosystem)), pkg.GetNormalizedName(), pkg.GetVersion()))
+	}
+
+	sort.Strings(entries)
+
+	digest := sha256.Sum256([]byte(strings.Join(entries, "\n")))
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,119 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/safedep/vet/pkg/common/paths"
+)
+
+const historyFileName = "vet-history.json"
+
+// Store persists manifest fingerprints across scans
+type Store interface {
+	// Get returns false when the manifest was not seen before
+	Get(key string) (ManifestFingerprint, bool, error)
+	Put(fingerprint ManifestFingerprint) error
+}
+
+// Unchanged is true when the fingerprint is the same as the one
+// recorded by an earlier scan
+func Unchanged(store Store, fingerprint ManifestFingerprint) (bool, error) {
+	previous, ok, err := store.Get(fingerprint.Key())
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return previous.Same(fingerprint), nil
+}
+
+type FileStoreConfig struct {
+	Path string
+}
+
+type fileStore struct {
+	m      sync.Mutex
+	config FileStoreConfig
+}
+
+// DefaultFileStoreConfig stores history in the history directory
+func DefaultFileStoreConfig() (FileStoreConfig, error) {
+	path, err := paths.Resolve(paths.KindHistory, historyFileName)
+	if err != nil {
+		return FileStoreConfig{}, err
+	}
+
+	return FileStoreConfig{Path: path}, nil
+}
+
+// NewFileStore creates a store backed by a JSON file
+func NewFileStore(config FileStoreConfig) (Store, error) {
+	if config.Path == "" {
+		return nil, errors.New("history store path is required")
+	}
+
+	return &fileStore{config: config}, nil
+}
+
+func (s *fileStore) Get(key string) (ManifestFingerprint, bool, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	fingerprints, err := s.read()
+	if err != nil {
+		return ManifestFingerprint{}, false, err
+	}
+
+	fingerprint, ok := fingerprints[key]
+	return fingerprint, ok, nil
+}
+
+func (s *fileStore) Put(fingerprint ManifestFingerprint) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	fingerprints, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	fingerprints[fingerprint.Key()] = fingerprint
+	return s.write(fingerprints)
+}
+
+func (s *fileStore) read() (map[string]ManifestFingerprint, error) {
+	data, err := os.ReadFile(s.config.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]ManifestFingerprint{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	fingerprints := map[string]ManifestFingerprint{}
+	err = json.Unmarshal(data, &fingerprints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse history file: %w", err)
+	}
+
+	return fingerprints, nil
+}
+
+func (s *fileStore) write(fingerprints map[string]ManifestFingerprint) error {
+	data, err := json.MarshalIndent(fingerprints, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize history: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.config.Path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	return os.WriteFile(s.config.Path, data, 0600)
+}
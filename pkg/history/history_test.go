@@ -0,0 +1,190 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func testManifest(t *testing.T, content string, packages ...string) *models.PackageManifest {
+	path := filepath.Join(t.TempDir(), "requirements.txt")
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0600))
+
+	manifest := models.NewPackageManifestFromLocal(path, models.EcosystemPyPI)
+	for _, name := range packages {
+		manifest.AddPackage(&models.Package{
+			PackageDetails: models.NewPackageDetail(models.EcosystemPyPI, name, "1.0.0"),
+		})
+	}
+
+	// Manifests are read from different directories but must be keyed
+	// as the same manifest of a project
+	manifest.Source.Namespace = "/app"
+	manifest.Source.Path = "requirements.txt"
+
+	return manifest
+}
+
+func TestNewManifestFingerprint(t *testing.T) {
+	base, err := NewManifestFingerprint(testManifest(t, "a\nb\n", "a", "b"))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, base.ContentHash)
+	assert.Equal(t, 2, base.PackageCount)
+
+	cases := []struct {
+		name     string
+		manifest *models.PackageManifest
+		same     bool
+	}{
+		{
+			"Package order does not matter",
+			testManifest(t, "a\nb\n", "b", "a"),
+			true,
+		},
+		{
+			"Package name is normalized",
+			testManifest(t, "a\nb\n", "A", "b"),
+			true,
+		},
+		{
+			"Content changed",
+			testManifest(t, "a\nb==1.0.0\n", "a", "b"),
+			false,
+		},
+		{
+			"Dependency set changed",
+			testManifest(t, "a\nb\n", "a", "b", "c"),
+			false,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			fingerprint, err := NewManifestFingerprint(test.manifest)
+			assert.Nil(t, err)
+			assert.Equal(t, test.same, base.Same(fingerprint))
+		})
+	}
+}
+
+func TestNewManifestFingerprintWithoutFile(t *testing.T) {
+	manifest := models.NewPackageManifestFromPurl("pkg:pypi/a@1.0.0", models.EcosystemPyPI)
+
+	fingerprint, err := NewManifestFingerprint(manifest)
+	assert.Nil(t, err)
+	assert.Empty(t, fingerprint.ContentHash)
+	assert.NotEmpty(t, fingerprint.DependencySetHash)
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(FileStoreConfig{Path: filepath.Join(t.TempDir(), "history.json")})
+	assert.Nil(t, err)
+
+	fingerprint, err := NewManifestFingerprint(testManifest(t, "a\n", "a"))
+	assert.Nil(t, err)
+
+	unchanged, err := Unchanged(store, fingerprint)
+	assert.Nil(t, err)
+	assert.False(t, unchanged)
+
+	assert.Nil(t, store.Put(fingerprint))
+
+	unchanged, err = Unchanged(store, fingerprint)
+	assert.Nil(t, err)
+	assert.True(t, unchanged)
+
+	changed, err := NewManifestFingerprint(testManifest(t, "a\nb\n", "a", "b"))
+	assert.Nil(t, err)
+
+	unchanged, err = Unchanged(store, changed)
+	assert.Nil(t, err)
+	assert.False(t, unchanged)
+
+	_, err = NewFileStore(FileStoreConfig{})
+	assert.ErrorContains(t, err, "history store path is required")
+}
+
+func TestKeyValueStore(t *testing.T) {
+	store, err := NewKeyValueStore(storage.NewMemoryKeyValueStorage())
+	assert.Nil(t, err)
+
+	fingerprint, err := NewManifestFingerprint(testManifest(t, "a\n", "a"))
+	assert.Nil(t, err)
+
+	_, ok, err := store.Get(fingerprint.Key())
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, store.Put(fingerprint))
+
+	unchanged, err := Unchanged(store, fingerprint)
+	assert.Nil(t, err)
+	assert.True(t, unchanged)
+
+	_, err = NewKeyValueStore(nil)
+	assert.ErrorContains(t, err, "key value storage is required")
+}
+
+func TestTriageStore(t *testing.T) {
+	fileStore, err := NewFileTriageStore(FileStoreConfig{Path: filepath.Join(t.TempDir(), "triage.json")})
+	assert.Nil(t, err)
+
+	kvStore, err := NewKeyValueTriageStore(storage.NewMemoryKeyValueStorage())
+	assert.Nil(t, err)
+
+	cases := []struct {
+		name  string
+		store TriageStore
+	}{
+		{"file", fileStore},
+		{"key value", kvStore},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			key := FindingKey{Ecosystem: models.EcosystemPyPI, Name: "Django_Rest", Version: "1.0.0", FindingId: "GHSA-1"}
+
+			triage, err := Triage(test.store, key)
+			assert.Nil(t, err)
+			assert.Equal(t, TriageStateNew, triage.State)
+
+			assert.Nil(t, test.store.PutTriage(FindingTriage{FindingKey: key, State: TriageStateAcknowledged}))
+			assert.Nil(t, test.store.PutTriage(FindingTriage{
+				FindingKey: FindingKey{Ecosystem: models.EcosystemPyPI, Name: "a", Version: "1.0.0", FindingId: "GHSA-2"},
+				State:      TriageStateAcceptedRisk,
+				Note:       "not reachable",
+			}))
+
+			// Name is normalized
+			triage, err = Triage(test.store, FindingKey{Ecosystem: "pypi", Name: "django-rest", Version: "1.0.0", FindingId: "GHSA-1"})
+			assert.Nil(t, err)
+			assert.Equal(t, TriageStateAcknowledged, triage.State)
+			assert.False(t, triage.UpdatedAt.IsZero())
+
+			list, err := test.store.ListTriage()
+			assert.Nil(t, err)
+			assert.Len(t, list, 2)
+			assert.Equal(t, "a", list[0].Name)
+			assert.Equal(t, "not reachable", list[0].Note)
+
+			err = test.store.PutTriage(FindingTriage{FindingKey: key, State: "done"})
+			assert.ErrorContains(t, err, "invalid triage state")
+
+			err = test.store.PutTriage(FindingTriage{State: TriageStateFixed})
+			assert.ErrorContains(t, err, "must have an ecosystem")
+		})
+	}
+}
+
+func TestParseTriageState(t *testing.T) {
+	state, err := ParseTriageState("Accepted-Risk")
+	assert.Nil(t, err)
+	assert.Equal(t, TriageStateAcceptedRisk, state)
+
+	_, err = ParseTriageState("closed")
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/safedep/vet/pkg/storage"
+)
+
+const keyValueStoreNamespace = "manifest_history"
+
+type keyValueStore struct {
+	kv storage.KeyValueStorage
+}
+
+// NewKeyValueStore creates a store backed by a key value storage. The
+// storage is owned by the caller.
+func NewKeyValueStore(kv storage.KeyValueStorage) (Store, error) {
+	if kv == nil {
+		return nil, errors.New("key value storage is required")
+	}
+
+	return &keyValueStore{kv: kv}, nil
+}
+
+func (s *keyValueStore) Get(key string) (ManifestFingerprint, bool, error) {
+	data, err := s.kv.Get(context.Background(), keyValueStoreNamespace, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return ManifestFingerprint{}, false, nil
+		}
+
+		return ManifestFingerprint{}, false, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var fingerprint ManifestFingerprint
+	if err := json.Unmarshal(data, &fingerprint); err != nil {
+		return ManifestFingerprint{}, false, fmt.Errorf("failed to parse history: %w", err)
+	}
+
+	return fingerprint, true, nil
+}
+
+func (s *keyValueStore) Put(fingerprint ManifestFingerprint) error {
+	data, err := json.Marshal(fingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to serialize history: %w", err)
+	}
+
+	return s.kv.Put(context.Background(), keyValueStoreNamespace, fingerprint.Key(), data)
+}
@@ -0,0 +1,312 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/paths"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/naming"
+	"github.com/safedep/vet/pkg/storage"
+)
+
+const (
+	triageFileName = "vet-triage.json"
+
+	keyValueTriageNamespace = "finding_triage"
+)
+
+// TriageState is the review workflow state of a finding
+type TriageState string
+
+const (
+	TriageStateNew          = TriageState("new")
+	TriageStateAcknowledged = TriageState("acknowledged")
+	TriageStateInProgress   = TriageState("in-progress")
+	TriageStateFixed        = TriageState("fixed")
+	TriageStateAcceptedRisk = TriageState("accepted-risk")
+)
+
+var triageStates = []TriageState{
+	TriageStateNew,
+	TriageStateAcknowledged,
+	TriageStateInProgress,
+	TriageStateFixed,
+	TriageStateAcceptedRisk,
+}
+
+// ParseTriageState returns an error for an unknown state
+func ParseTriageState(state string) (TriageState, error) {
+	for _, s := range triageStates {
+		if strings.EqualFold(state, string(s)) {
+			return s, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid triage state: %q", state)
+}
+
+// FindingKey identifies a finding of a package version across scans. The
+// finding is a vulnerability identified by its ID or a policy violation
+// identified by the filter name.
+type FindingKey struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	FindingId string `json:"finding_id"`
+}
+
+// NewFindingKey returns the key of a finding of the package. Ecosystem is
+// of the manifest as in the JSON report so that triage states can be
+// imported from a report.
+func NewFindingKey(pkg *models.Package, findingId string) FindingKey {
+	ecosystem := string(pkg.Ecosystem)
+	if pkg.Manifest != nil {
+		ecosystem = pkg.GetSpecEcosystem().String()
+	}
+
+	return FindingKey{
+		Ecosystem: ecosystem,
+		Name:      pkg.GetName(),
+		Version:   pkg.GetVersion(),
+		FindingId: findingId,
+	}
+}
+
+// String is the storage key, package name is normalized so that the
+// triage applies irrespective of how the name is written in a manifest
+func (k FindingKey) String() string {
+	return fmt.Sprintf("%s/%s@%s/%s", strings.ToLower(k.Ecosystem),
+		naming.Normalize(k.Ecosystem, k.Name), k.Version, k.FindingId)
+}
+
+// FindingTriage is the triage state of a finding
+type FindingTriage struct {
+	FindingKey
+
+	State     TriageState `json:"state"`
+	Note      string      `json:"note,omitempty"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// TriageStore persists triage states of findings across scans
+type TriageStore interface {
+	// GetTriage returns false when the finding was not triaged
+	GetTriage(key FindingKey) (FindingTriage, bool, error)
+	PutTriage(triage FindingTriage) error
+
+	// ListTriage returns triage of all findings ordered by key
+	ListTriage() ([]FindingTriage, error)
+}
+
+// Triage returns the triage of a finding. Findings not triaged are new.
+func Triage(store TriageStore, key FindingKey) (FindingTriage, error) {
+	triage, ok, err := store.GetTriage(key)
+	if err != nil {
+		return FindingTriage{}, err
+	}
+
+	if !ok {
+		return FindingTriage{FindingKey: key, State: TriageStateNew}, nil
+	}
+
+	return triage, nil
+}
+
+func validateTriage(triage *FindingTriage) error {
+	if triage.Ecosystem == "" || triage.Name == "" || triage.FindingId == "" {
+		return errors.New("triage must have an ecosystem, package name and finding id")
+	}
+
+	if _, err := ParseTriageState(string(triage.State)); err != nil {
+		return err
+	}
+
+	if triage.UpdatedAt.IsZero() {
+		triage.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+type fileTriageStore struct {
+	m      sync.Mutex
+	config FileStoreConfig
+}
+
+// DefaultTriageFileStoreConfig stores triage in the history directory
+func DefaultTriageFileStoreConfig() (FileStoreConfig, error) {
+	path, err := paths.Resolve(paths.KindHistory, triageFileName)
+	if err != nil {
+		return FileStoreConfig{}, err
+	}
+
+	return FileStoreConfig{Path: path}, nil
+}
+
+// NewFileTriageStore creates a triage store backed by a JSON file
+func NewFileTriageStore(config FileStoreConfig) (TriageStore, error) {
+	if config.Path == "" {
+		return nil, errors.New("triage store path is required")
+	}
+
+	return &fileTriageStore{config: config}, nil
+}
+
+func (s *fileTriageStore) GetTriage(key FindingKey) (FindingTriage, bool, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	triages, err := s.read()
+	if err != nil {
+		return FindingTriage{}, false, err
+	}
+
+	triage, ok := triages[key.String()]
+	return triage, ok, nil
+}
+
+func (s *fileTriageStore) PutTriage(triage FindingTriage) error {
+	if err := validateTriage(&triage); err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	triages, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	triages[triage.String()] = triage
+	return s.write(triages)
+}
+
+func (s *fileTriageStore) ListTriage() ([]FindingTriage, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	triages, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(triages))
+	for key := range triages {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	list := make([]FindingTriage, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, triages[key])
+	}
+
+	return list, nil
+}
+
+func (s *fileTriageStore) read() (map[string]FindingTriage, error) {
+	data, err := os.ReadFile(s.config.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]FindingTriage{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to read triage file: %w", err)
+	}
+
+	triages := map[string]FindingTriage{}
+	err = json.Unmarshal(data, &triages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse triage file: %w", err)
+	}
+
+	return triages, nil
+}
+
+func (s *fileTriageStore) write(triages map[string]FindingTriage) error {
+	data, err := json.MarshalIndent(triages, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize triage: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.config.Path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create triage directory: %w", err)
+	}
+
+	return os.WriteFile(s.config.Path, data, 0600)
+}
+
+type keyValueTriageStore struct {
+	kv storage.KeyValueStorage
+}
+
+// NewKeyValueTriageStore creates a triage store backed by a key value
+// storage. The storage is owned by the caller.
+func NewKeyValueTriageStore(kv storage.KeyValueStorage) (TriageStore, error) {
+	if kv == nil {
+		return nil, errors.New("key value storage is required")
+	}
+
+	return &keyValueTriageStore{kv: kv}, nil
+}
+
+func (s *keyValueTriageStore) GetTriage(key FindingKey) (FindingTriage, bool, error) {
+	data, err := s.kv.Get(context.Background(), keyValueTriageNamespace, key.String())
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return FindingTriage{}, false, nil
+		}
+
+		return FindingTriage{}, false, fmt.Errorf("failed to read triage: %w", err)
+	}
+
+	var triage FindingTriage
+	if err := json.Unmarshal(data, &triage); err != nil {
+		return FindingTriage{}, false, fmt.Errorf("failed to parse triage: %w", err)
+	}
+
+	return triage, true, nil
+}
+
+func (s *keyValueTriageStore) PutTriage(triage FindingTriage) error {
+	if err := validateTriage(&triage); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(triage)
+	if err != nil {
+		return fmt.Errorf("failed to serialize triage: %w", err)
+	}
+
+	return s.kv.Put(context.Background(), keyValueTriageNamespace, triage.String(), data)
+}
+
+func (s *keyValueTriageStore) ListTriage() ([]FindingTriage, error) {
+	list := []FindingTriage{}
+	err := s.kv.Scan(context.Background(), keyValueTriageNamespace, "", func(key string, value []byte) error {
+		var triage FindingTriage
+		if err := json.Unmarshal(value, &triage); err != nil {
+			return fmt.Errorf("failed to parse triage: %w", err)
+		}
+
+		list = append(list, triage)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
@@ -0,0 +1,102 @@
+package license
+
+import "strings"
+
+type Copyleft string
+
+const (
+	CopyleftNone    = Copyleft("none")
+	CopyleftWeak    = Copyleft("weak")
+	CopyleftStrong  = Copyleft("strong")
+	CopyleftNetwork = Copyleft("network")
+)
+
+// Conditions are the well known obligations and approvals of a license
+type Conditions struct {
+	Name        string   `json:"name"`
+	Copyleft    Copyleft `json:"copyleft"`
+	OsiApproved bool     `json:"osi_approved"`
+	FsfApproved bool     `json:"fsf_approved"`
+
+	// Network copyleft licenses impose obligations on SaaS usage
+	SaasCompatible bool `json:"saas_compatible"`
+
+	CommercialUseAllowed bool `json:"commercial_use_allowed"`
+}
+
+func permissive(name string, osi, fsf bool) Conditions {
+	return Conditions{Name: name, Copyleft: CopyleftNone, OsiApproved: osi, FsfApproved: fsf,
+		SaasCompatible: true, CommercialUseAllowed: true}
+}
+
+func copyleft(name string, kind Copyleft) Conditions {
+	return Conditions{Name: name, Copyleft: kind, OsiApproved: true, FsfApproved: true,
+		SaasCompatible: kind != CopyleftNetwork, CommercialUseAllowed: true}
+}
+
+// Commonly used licenses in open source packages. Keys are lower case
+// SPDX identifiers.
+var knownConditions = map[string]Conditions{
+	"0bsd":          permissive("BSD Zero Clause License", true, false),
+	"apache-1.1":    permissive("Apache License 1.1", true, true),
+	"apache-2.0":    permissive("Apache License 2.0", true, true),
+	"artistic-2.0":  permissive("Artistic License 2.0", true, true),
+	"blueoak-1.0.0": permissive("Blue Oak Model License 1.0.0", true, false),
+	"bsd-2-clause":  permissive("BSD 2-Clause \"Simplified\" License", true, true),
+	"bsd-3-clause":  permissive("BSD 3-Clause \"New\" or \"Revised\" License", true, true),
+	"bsl-1.0":       permissive("Boost Software License 1.0", true, true),
+	"cc0-1.0":       permissive("Creative Commons Zero v1.0 Universal", false, true),
+	"cc-by-3.0":     permissive("Creative Commons Attribution 3.0 Unported", false, false),
+	"cc-by-4.0":     permissive("Creative Commons Attribution 4.0 International", false, true),
+	"isc":           permissive("ISC License", true, true),
+	"mit":           permissive("MIT License", true, true),
+	"mit-0":         permissive("MIT No Attribution", true, false),
+	"postgresql":    permissive("PostgreSQL License", true, false),
+	"psf-2.0":       permissive("Python Software Foundation License 2.0", false, false),
+	"python-2.0":    permissive("Python License 2.0", true, true),
+	"unicode-3.0":   permissive("Unicode License v3", true, false),
+	"unlicense":     permissive("The Unlicense", true, true),
+	"wtfpl":         permissive("Do What The F*ck You Want To Public License", false, true),
+	"zlib":          permissive("zlib License", true, true),
+
+	"epl-1.0":           copyleft("Eclipse Public License 1.0", CopyleftWeak),
+	"epl-2.0":           copyleft("Eclipse Public License 2.0", CopyleftWeak),
+	"lgpl-2.1":          copyleft("GNU Lesser General Public License v2.1", CopyleftWeak),
+	"lgpl-2.1-only":     copyleft("GNU Lesser General Public License v2.1 only", CopyleftWeak),
+	"lgpl-2.1-or-later": copyleft("GNU Lesser General Public License v2.1 or later", CopyleftWeak),
+	"lgpl-3.0":          copyleft("GNU Lesser General Public License v3.0", CopyleftWeak),
+	"lgpl-3.0-only":     copyleft("GNU Lesser General Public License v3.0 only", CopyleftWeak),
+	"lgpl-3.0-or-later": copyleft("GNU Lesser General Public License v3.0 or later", CopyleftWeak),
+	"mpl-2.0":           copyleft("Mozilla Public License 2.0", CopyleftWeak),
+	"gpl-2.0":           copyleft("GNU General Public License v2.0", CopyleftStrong),
+	"gpl-2.0-only":      copyleft("GNU General Public License v2.0 only", CopyleftStrong),
+	"gpl-2.0-or-later":  copyleft("GNU General Public License v2.0 or later", CopyleftStrong),
+	"gpl-3.0":           copyleft("GNU General Public License v3.0", CopyleftStrong),
+	"gpl-3.0-only":      copyleft("GNU General Public License v3.0 only", CopyleftStrong),
+	"gpl-3.0-or-later":  copyleft("GNU General Public License v3.0 or later", CopyleftStrong),
+	"agpl-3.0":          copyleft("GNU Affero General Public License v3.0", CopyleftNetwork),
+	"agpl-3.0-only":     copyleft("GNU Affero General Public License v3.0 only", CopyleftNetwork),
+	"agpl-3.0-or-later": copyleft("GNU Affero General Public License v3.0 or later", CopyleftNetwork),
+
+	"sspl-1.0": {Name: "Server Side Public License, v 1", Copyleft: CopyleftNetwork,
+		SaasCompatible: false, CommercialUseAllowed: true},
+	"busl-1.1": {Name: "Business Source License 1.1", Copyleft: CopyleftNone,
+		SaasCompatible: false, CommercialUseAllowed: false},
+	"cc-by-nc-4.0": {Name: "Creative Commons Attribution Non Commercial 4.0 International",
+		Copyleft: CopyleftNone, SaasCompatible: true, CommercialUseAllowed: false},
+	"cc-by-nc-sa-4.0": {Name: "Creative Commons Attribution Non Commercial Share Alike 4.0 International",
+		Copyleft: CopyleftStrong, SaasCompatible: true, CommercialUseAllowed: false},
+}
+
+// LookupConditions returns the conditions of a license by its SPDX
+// identifier. The "+" suffix of a leaf expression is not part of the
+// identifier.
+func LookupConditions(spdxId string) (Conditions, bool) {
+	c, ok := knownConditions[strings.ToLower(strings.TrimSpace(spdxId))]
+	return c, ok
+}
+
+// ReferenceUrl is the SPDX license list page of the license
+func ReferenceUrl(spdxId string) string {
+	return "https://spdx.org/licenses/" + spdxId + ".html"
+}
@@ -0,0 +1,262 @@
+package license
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type Operator string
+
+const (
+	OperatorAnd = Operator("AND")
+	OperatorOr  = Operator("OR")
+)
+
+// Expression is a parsed SPDX license expression. A leaf expression is a
+// license with an optional exception while a compound expression combines
+// its operands with AND or OR.
+type Expression struct {
+	License   string `json:"license,omitempty"`
+	OrLater   bool   `json:"or_later,omitempty"`
+	Exception string `json:"exception,omitempty"`
+
+	Operator Operator      `json:"operator,omitempty"`
+	Operands []*Expression `json:"operands,omitempty"`
+}
+
+func (e *Expression) IsCompound() bool {
+	return e.Operator != ""
+}
+
+func (e *Expression) String() string {
+	if !e.IsCompound() {
+		s := e.License
+		if e.OrLater {
+			s += "+"
+		}
+
+		if e.Exception != "" {
+			s += " WITH " + e.Exception
+		}
+
+		return s
+	}
+
+	parts := make([]string, 0, len(e.Operands))
+	for _, operand := range e.Operands {
+		// Explicit grouping, AND binds tighter than OR
+		if operand.IsCompound() && operand.Operator != e.Operator {
+			parts = append(parts, "("+operand.String()+")")
+		} else {
+			parts = append(parts, operand.String())
+		}
+	}
+
+	return strings.Join(parts, " "+string(e.Operator)+" ")
+}
+
+// Licenses returns the leaf expressions, deduplicated, in the order
+// they appear in the expression
+func (e *Expression) Licenses() []*Expression {
+	leaves := []*Expression{}
+	seen := map[string]bool{}
+
+	var walk func(*Expression)
+	walk = func(node *Expression) {
+		if !node.IsCompound() {
+			key := strings.ToLower(node.String())
+			if !seen[key] {
+				seen[key] = true
+				leaves = append(leaves, node)
+			}
+
+			return
+		}
+
+		for _, operand := range node.Operands {
+			walk(operand)
+		}
+	}
+
+	walk(e)
+	return leaves
+}
+
+// ParseExpression parses an SPDX license expression as per SPDX spec
+// Annex D. Operators are matched without considering case since
+// registries are not strict about it.
+func ParseExpression(s string) (*Expression, error) {
+	p := &expressionParser{tokens: tokenizeExpression(s)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression %q: %w", s, err)
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid license expression %q: unexpected %q",
+			s, p.tokens[p.pos])
+	}
+
+	return e, nil
+}
+
+func tokenizeExpression(s string) []string {
+	tokens := []string{}
+	current := strings.Builder{}
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	flush()
+	return tokens
+}
+
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *expressionParser) peekOperator(op string) bool {
+	return p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], op)
+}
+
+func (p *expressionParser) parseOr() (*Expression, error) {
+	return p.parseCompound(OperatorOr, p.parseAnd)
+}
+
+func (p *expressionParser) parseAnd() (*Expression, error) {
+	return p.parseCompound(OperatorAnd, p.parseWith)
+}
+
+// parseCompound flattens a chain of the same operator into one node
+func (p *expressionParser) parseCompound(op Operator,
+	operand func() (*Expression, error),
+) (*Expression, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []*Expression{first}
+	for p.peekOperator(string(op)) {
+		p.pos++
+
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		if next.Operator == op {
+			operands = append(operands, next.Operands...)
+		} else {
+			operands = append(operands, next)
+		}
+	}
+
+	if len(operands) == 1 {
+		return first, nil
+	}
+
+	if first.Operator == op {
+		operands = append(append([]*Expression{}, first.Operands...), operands[1:]...)
+	}
+
+	return &Expression{Operator: op, Operands: operands}, nil
+}
+
+func (p *expressionParser) parseWith() (*Expression, error) {
+	e, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.peekOperator("WITH") {
+		return e, nil
+	}
+
+	if e.IsCompound() {
+		return nil, fmt.Errorf("exception must follow a license")
+	}
+
+	p.pos++
+	if p.pos >= len(p.tokens) || !isLicenseToken(p.tokens[p.pos]) {
+		return nil, fmt.Errorf("missing exception after WITH")
+	}
+
+	e.Exception = p.tokens[p.pos]
+	p.pos++
+
+	return e, nil
+}
+
+func (p *expressionParser) parsePrimary() (*Expression, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	token := p.tokens[p.pos]
+	if token == "(" {
+		p.pos++
+
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.pos >= len(p.tokens) || p.tokens[p.pos] != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+
+		p.pos++
+		return e, nil
+	}
+
+	if !isLicenseToken(token) {
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+
+	p.pos++
+
+	e := &Expression{License: token}
+	if strings.HasSuffix(token, "+") && len(token) > 1 {
+		e.License = strings.TrimSuffix(token, "+")
+		e.OrLater = true
+	}
+
+	return e, nil
+}
+
+func isLicenseToken(token string) bool {
+	if token == "(" || token == ")" {
+		return false
+	}
+
+	for _, op := range []string{"AND", "OR", "WITH"} {
+		if strings.EqualFold(token, op) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,129 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExpression(t *testing.T) {
+	cases := []struct {
+		name       string
+		expression string
+		expected   string
+		licenses   []string
+		err        string
+	}{
+		{
+			"single license",
+			"MIT",
+			"MIT",
+			[]string{"MIT"},
+			"",
+		},
+		{
+			"or later with exception",
+			"GPL-2.0+ WITH Classpath-exception-2.0",
+			"GPL-2.0+ WITH Classpath-exception-2.0",
+			[]string{"GPL-2.0+ WITH Classpath-exception-2.0"},
+			"",
+		},
+		{
+			"AND binds tighter than OR",
+			"MIT OR Apache-2.0 AND BSD-3-Clause",
+			"MIT OR (Apache-2.0 AND BSD-3-Clause)",
+			[]string{"MIT", "Apache-2.0", "BSD-3-Clause"},
+			"",
+		},
+		{
+			"chains and groups are flattened",
+			"(MIT OR ISC) OR (Apache-2.0 OR MIT)",
+			"MIT OR ISC OR Apache-2.0 OR MIT",
+			[]string{"MIT", "ISC", "Apache-2.0"},
+			"",
+		},
+		{
+			"operators are case insensitive",
+			"(mit and isc) or 0BSD",
+			"(mit AND isc) OR 0BSD",
+			[]string{"mit", "isc", "0BSD"},
+			"",
+		},
+		{
+			"empty",
+			"  ",
+			"",
+			nil,
+			"empty license expression",
+		},
+		{
+			"dangling operator",
+			"MIT OR",
+			"",
+			nil,
+			"unexpected end of expression",
+		},
+		{
+			"unbalanced parenthesis",
+			"(MIT OR ISC",
+			"",
+			nil,
+			"missing closing parenthesis",
+		},
+		{
+			"exception on compound",
+			"(MIT OR ISC) WITH LLVM-exception",
+			"",
+			nil,
+			"exception must follow a license",
+		},
+		{
+			"missing operator",
+			"MIT ISC",
+			"",
+			nil,
+			"unexpected \"ISC\"",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := ParseExpression(test.expression)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, e.String())
+
+			licenses := []string{}
+			for _, leaf := range e.Licenses() {
+				licenses = append(licenses, leaf.String())
+			}
+
+			assert.Equal(t, test.licenses, licenses)
+		})
+	}
+}
+
+func TestLookupConditions(t *testing.T) {
+	c, ok := LookupConditions(" apache-2.0 ")
+	assert.True(t, ok)
+	assert.Equal(t, "Apache License 2.0", c.Name)
+	assert.Equal(t, CopyleftNone, c.Copyleft)
+	assert.True(t, c.OsiApproved)
+	assert.True(t, c.SaasCompatible)
+
+	c, ok = LookupConditions("AGPL-3.0-only")
+	assert.True(t, ok)
+	assert.Equal(t, CopyleftNetwork, c.Copyleft)
+	assert.False(t, c.SaasCompatible)
+
+	c, ok = LookupConditions("CC-BY-NC-4.0")
+	assert.True(t, ok)
+	assert.False(t, c.CommercialUseAllowed)
+
+	_, ok = LookupConditions("LicenseRef-Proprietary")
+	assert.False(t, ok)
+}
@@ -0,0 +1,123 @@
+// Package license builds a deduplicated inventory of licenses used by
+// the packages in a manifest i.e. the effective license set of a project.
+package license
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/vet/pkg/models"
+)
+
+// LicenseUsage is a license (or SPDX license expression) along with
+// the number of unique packages using it
+type LicenseUsage struct {
+	License string `json:"license"`
+	Count   int    `json:"count"`
+}
+
+// Inventory is the effective license set of a manifest
+type Inventory struct {
+	// Deduplicated licenses sorted by usage in descending order
+	Licenses []LicenseUsage `json:"licenses"`
+
+	// Packages for which license information is not available
+	Unknown []*models.Package `json:"-"`
+
+	// Number of unique packages considered
+	Packages int `json:"packages"`
+}
+
+// Build computes the effective license set of a manifest. Packages are
+// deduplicated by their Id and licenses are deduplicated without
+// considering case and surrounding whitespace. The first seen form of
+// a license is used for display.
+func Build(manifest *models.PackageManifest) *Inventory {
+	inventory := &Inventory{
+		Licenses: []LicenseUsage{},
+		Unknown:  []*models.Package{},
+	}
+
+	seenPackages := map[string]bool{}
+	usage := map[string]*LicenseUsage{}
+
+	for _, pkg := range manifest.GetPackages() {
+		if seenPackages[pkg.Id()] {
+			continue
+		}
+
+		seenPackages[pkg.Id()] = true
+		inventory.Packages++
+
+		licenses := PackageLicenses(pkg)
+		if len(licenses) == 0 {
+			inventory.Unknown = append(inventory.Unknown, pkg)
+			continue
+		}
+
+		for _, lic := range licenses {
+			key := normalize(lic)
+			if _, ok := usage[key]; !ok {
+				usage[key] = &LicenseUsage{License: strings.TrimSpace(lic)}
+			}
+
+			usage[key].Count++
+		}
+	}
+
+	for _, u := range usage {
+		inventory.Licenses = append(inventory.Licenses, *u)
+	}
+
+	slices.SortFunc(inventory.Licenses, func(a, b LicenseUsage) int {
+		if a.Count == b.Count {
+			return cmp.Compare(a.License, b.License)
+		}
+
+		return cmp.Compare(b.Count, a.Count)
+	})
+
+	slices.SortFunc(inventory.Unknown, func(a, b *models.Package) int {
+		return cmp.Compare(a.GetName(), b.GetName())
+	})
+
+	return inventory
+}
+
+// LicenseIds returns the list of deduplicated licenses in the inventory
+func (i *Inventory) LicenseIds() []string {
+	ids := make([]string, 0, len(i.Licenses))
+	for _, l := range i.Licenses {
+		ids = append(ids, l.License)
+	}
+
+	return ids
+}
+
+// PackageLicenses returns the unique licenses of a package
+// from the enriched insights
+func PackageLicenses(pkg *models.Package) []string {
+	insights := utils.SafelyGetValue(pkg.Insights)
+
+	seen := map[string]bool{}
+	licenses := []string{}
+
+	for _, lic := range utils.SafelyGetValue(insights.Licenses) {
+		key := normalize(string(lic))
+		if key == "" || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		licenses = append(licenses, strings.TrimSpace(string(lic)))
+	}
+
+	return licenses
+}
+
+// SPDX license identifiers are case insensitive
+func normalize(license string) string {
+	return strings.ToLower(strings.TrimSpace(license))
+}
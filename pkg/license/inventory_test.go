@@ -0,0 +1,47 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/safedep/vet/gen/insightapi"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild(t *testing.T) {
+	newPackage := func(name string, licenses ...insightapi.License) *models.Package {
+		pkg := &models.Package{
+			PackageDetails: lockfile.PackageDetails{
+				Name:      name,
+				Version:   "1.0.0",
+				Ecosystem: models.EcosystemNpm,
+			},
+		}
+
+		if len(licenses) > 0 {
+			pkg.Insights = &insightapi.PackageVersionInsight{Licenses: &licenses}
+		}
+
+		return pkg
+	}
+
+	manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+	manifest.AddPackage(newPackage("a", "MIT"))
+	manifest.AddPackage(newPackage("b", "mit ", "Apache-2.0"))
+	manifest.AddPackage(newPackage("c", "Apache-2.0", "apache-2.0"))
+	manifest.AddPackage(newPackage("d"))
+	manifest.AddPackage(newPackage("a", "MIT"))
+
+	inventory := Build(manifest)
+
+	assert.Equal(t, 4, inventory.Packages)
+	assert.Equal(t, []LicenseUsage{
+		{License: "Apache-2.0", Count: 2},
+		{License: "MIT", Count: 2},
+	}, inventory.Licenses)
+	assert.Equal(t, []string{"Apache-2.0", "MIT"}, inventory.LicenseIds())
+
+	assert.Len(t, inventory.Unknown, 1)
+	assert.Equal(t, "d", inventory.Unknown[0].GetName())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,6 +16,8 @@ import (
 	"github.com/safedep/vet/gen/insightapi"
 
 	modelspec "github.com/safedep/vet/gen/models"
+	"github.com/safedep/vet/pkg/naming"
+	"github.com/safedep/vet/pkg/purl"
 )
 
 const (
@@ -45,6 +48,14 @@ const (
 	ManifestSourceGitRepository = ManifestSourceType("git_repository")
 )
 
+// ManifestKind is the kind of artifact a manifest was parsed from
+type ManifestKind string
+
+const (
+	ManifestKindLockfile = ManifestKind("lockfile")
+	ManifestKindSbom     = ManifestKind("sbom")
+)
+
 // We now have different sources from where a package
 // manifest can be identified. For example, local, github,
 // and may be in future within containers or archives like
@@ -107,6 +118,19 @@ type PackageManifest struct {
 	// The package dependency graph representation
 	DependencyGraph *DependencyGraph[*Package] `json:"dependency_graph"`
 
+	// Digest of the manifest file content as sha256:<hex>. Empty when
+	// the manifest is not a regular file e.g. a directory of jars
+	Digest string `json:"digest,omitempty"`
+
+	// The original SBOM document when the manifest is an SBOM
+	SbomDocument *SbomDocumentReference `json:"sbom_document,omitempty"`
+
+	// User defined tags e.g. business_unit=payments
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Application frameworks detected around the manifest e.g. nextjs
+	Frameworks []string `json:"frameworks,omitempty"`
+
 	// Lock to serialize updating packages
 	m sync.Mutex
 }
@@ -160,6 +184,16 @@ func (p *PackageManifest) UpdateSourceAsGitRepository(repo, repoRelativePath str
 	}
 }
 
+// SbomDocumentReference identifies the SBOM document scanned so that
+// findings are traceable to the exact document
+type SbomDocumentReference struct {
+	// CycloneDX serialNumber e.g. urn:uuid:<uuid>
+	SerialNumber string `json:"serial_number,omitempty"`
+
+	// SPDX documentNamespace
+	DocumentUri string `json:"document_uri,omitempty"`
+}
+
 func (pm *PackageManifest) AddPackage(pkg *Package) {
 	pm.m.Lock()
 	defer pm.m.Unlock()
@@ -176,6 +210,32 @@ func (pm *PackageManifest) GetSource() PackageManifestSource {
 	return pm.Source
 }
 
+// GetKind returns the kind of artifact the manifest was parsed from
+func (pm *PackageManifest) GetKind() ManifestKind {
+	switch pm.Ecosystem {
+	case EcosystemCyDxSBOM, EcosystemSpdxSBOM:
+		return ManifestKindSbom
+	default:
+		return ManifestKindLockfile
+	}
+}
+
+func (pm *PackageManifest) GetDigest() string {
+	return pm.Digest
+}
+
+func (pm *PackageManifest) GetSbomDocument() *SbomDocumentReference {
+	return pm.SbomDocument
+}
+
+func (pm *PackageManifest) GetTags() map[string]string {
+	return pm.Tags
+}
+
+func (pm *PackageManifest) GetFrameworks() []string {
+	return pm.Frameworks
+}
+
 func (pm *PackageManifest) GetPath() string {
 	return pm.Path
 }
@@ -356,6 +416,11 @@ type Package struct {
 	// Insights v2
 	InsightsV2 *packagev1.PackageVersionInsight `json:"insights_v2,omitempty"`
 
+	// Vulnerabilities are unknown when vulnerability data was unavailable
+	// e.g. the upstream service failed. Absence of vulnerabilities in
+	// insights must not be treated as the package being safe.
+	VulnerabilitiesUnknown bool `json:"vulnerabilities_unknown,omitempty"`
+
 	// This package is a transitive dependency of parent package
 	Parent *Package `json:"-"`
 
@@ -373,6 +438,10 @@ type Package struct {
 
 	// Manifest from where this package was found directly or indirectly
 	Manifest *PackageManifest `json:"-"`
+
+	// Optional version constraint declared in the manifest when the
+	// version is resolved from a range e.g. ^1.2.0 in package.json
+	VersionConstraint string `json:"version_constraint,omitempty"`
 }
 
 // Id returns a unique identifier for this package within a manifest
@@ -381,7 +450,7 @@ type Package struct {
 func (p *Package) Id() string {
 	return hashedId(fmt.Sprintf("%s/%s/%s",
 		strings.ToLower(string(p.PackageDetails.Ecosystem)),
-		strings.ToLower(p.PackageDetails.Name),
+		strings.ToLower(p.GetNormalizedName()),
 		strings.ToLower(p.PackageDetails.Version)))
 }
 
@@ -399,10 +468,39 @@ func (p *Package) GetName() string {
 	return p.Name
 }
 
+// GetNormalizedName returns the canonical name of the package as per the
+// naming rules of its ecosystem, to be used for lookups and comparison
+func (p *Package) GetNormalizedName() string {
+	return naming.Normalize(string(p.PackageDetails.Ecosystem), p.Name)
+}
+
+// PackageURL returns the purl of the package for interop with other tools
+func (p *Package) PackageURL() string {
+	return purl.New(string(p.PackageDetails.Ecosystem), p.Name, p.Version).String()
+}
+
 func (p *Package) GetVersion() string {
 	return p.Version
 }
 
+func (p *Package) GetVersionConstraint() string {
+	return p.VersionConstraint
+}
+
+var floatingVersionWildcardRegex = regexp.MustCompile(`(^|\.)[xX](\.|$)`)
+
+// IsFloatingVersion returns true if the package version is resolved
+// from a range that admits more than one version
+func (p *Package) IsFloatingVersion() bool {
+	c := strings.TrimSpace(p.VersionConstraint)
+	if c == "" || c == p.Version {
+		return false
+	}
+
+	return strings.ContainsAny(c, "^~<>*| ") ||
+		floatingVersionWildcardRegex.MatchString(c) || c == "latest"
+}
+
 func (p *Package) GetProvenances() []*Provenance {
 	return p.Provenances
 }
@@ -0,0 +1,126 @@
+// Package naming normalizes package names as per the rules of each
+// ecosystem so that the same package is identified consistently across
+// readers, enrichment and deduplication, irrespective of how the name is
+// written in a manifest.
+package naming
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+var pypiSeparatorRegex = regexp.MustCompile(`[-_.]+`)
+
+// Normalize returns the canonical name of a package in an ecosystem.
+// Names in ecosystems without normalization rules are only trimmed.
+func Normalize(ecosystem, name string) string {
+	name = strings.TrimSpace(name)
+
+	switch strings.ToLower(ecosystem) {
+	case strings.ToLower(string(lockfile.PipEcosystem)):
+		return PyPI(name)
+	case strings.ToLower(string(lockfile.NpmEcosystem)):
+		return Npm(name)
+	case strings.ToLower(string(lockfile.MavenEcosystem)):
+		return Maven(name)
+	case strings.ToLower(string(lockfile.GoEcosystem)):
+		return GoModule(name)
+	default:
+		return name
+	}
+}
+
+// Equal returns true when both names refer to the same package
+func Equal(ecosystem, a, b string) bool {
+	return Normalize(ecosystem, a) == Normalize(ecosystem, b)
+}
+
+// PyPI normalizes name as per PEP 503 i.e. case insensitive with runs
+// of `-`, `_` and `.` treated as a single `-`. Extras such as
+// `requests[security]` are removed.
+func PyPI(name string) string {
+	name, _, _ = strings.Cut(strings.TrimSpace(name), "[")
+	return strings.ToLower(pypiSeparatorRegex.ReplaceAllString(name, "-"))
+}
+
+// Npm normalizes scoped names that are URL encoded e.g. in a PURL or
+// a registry URL. npm names are case sensitive for legacy packages,
+// hence the case is retained.
+func Npm(name string) string {
+	name = strings.TrimSpace(name)
+	if strings.HasPrefix(name, "%40") {
+		name = "@" + strings.TrimPrefix(name, "%40")
+	}
+
+	if strings.HasPrefix(name, "@") {
+		name = strings.Replace(name, "%2F", "/", 1)
+		name = strings.Replace(name, "%2f", "/", 1)
+	}
+
+	return name
+}
+
+// Maven normalizes name to the `groupId:artifactId` form. The `/`
+// separator used by PURL is accepted. Maven coordinates are case
+// sensitive, hence the case is retained.
+func Maven(name string) string {
+	name = strings.TrimSpace(name)
+	if !strings.Contains(name, ":") {
+		name = strings.Replace(name, "/", ":", 1)
+	}
+
+	group, artifact, found := strings.Cut(name, ":")
+	if !found {
+		return name
+	}
+
+	return strings.TrimSpace(group) + ":" + strings.TrimSpace(artifact)
+}
+
+// GoModule decodes a case encoded module path e.g. as used by module
+// proxy and cache `github.com/!azure/azure-sdk-for-go` to its canonical
+// form `github.com/Azure/azure-sdk-for-go`. Module paths are case sensitive.
+func GoModule(path string) string {
+	path = strings.TrimSpace(path)
+	if !strings.Contains(path, "!") {
+		return path
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(path))
+
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '!' && i+1 < len(path) && path[i+1] >= 'a' && path[i+1] <= 'z' {
+			sb.WriteByte(path[i+1] - 'a' + 'A')
+			i++
+			continue
+		}
+
+		sb.WriteByte(c)
+	}
+
+	return sb.String()
+}
+
+// EscapeGoModule case encodes a module path for lookups against module
+// proxy, the inverse of GoModule
+func EscapeGoModule(path string) string {
+	var sb strings.Builder
+	sb.Grow(len(path))
+
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c >= 'A' && c <= 'Z' {
+			sb.WriteByte('!')
+			sb.WriteByte(c - 'A' + 'a')
+			continue
+		}
+
+		sb.WriteByte(c)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,48 @@
+package naming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		name      string
+		ecosystem string
+		input     string
+		expected  string
+	}{
+		{"PyPI case and separators", "PyPI", "Django_REST.framework", "django-rest-framework"},
+		{"PyPI repeated separators", "PyPI", "zope--_.interface", "zope-interface"},
+		{"PyPI extras", "PyPI", "requests[security]", "requests"},
+		{"PyPI ecosystem case insensitive", "pypi", "PyYAML", "pyyaml"},
+		{"npm unscoped", "npm", " lodash ", "lodash"},
+		{"npm scoped", "npm", "@types/node", "@types/node"},
+		{"npm encoded scope", "npm", "%40types%2Fnode", "@types/node"},
+		{"npm legacy case retained", "npm", "JSONStream", "JSONStream"},
+		{"Maven colon", "Maven", "org.apache.commons:commons-lang3", "org.apache.commons:commons-lang3"},
+		{"Maven slash", "Maven", "org.apache.commons/commons-lang3", "org.apache.commons:commons-lang3"},
+		{"Maven spaces", "Maven", "org.slf4j : slf4j-api", "org.slf4j:slf4j-api"},
+		{"Go case encoded", "Go", "github.com/!azure/azure-sdk-for-go", "github.com/Azure/azure-sdk-for-go"},
+		{"Go canonical", "Go", "github.com/Azure/go-autorest", "github.com/Azure/go-autorest"},
+		{"Unknown ecosystem", "Cargo", " serde ", "serde"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Normalize(test.ecosystem, test.input))
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	assert.True(t, Equal("PyPI", "Flask_Cors", "flask-cors"))
+	assert.False(t, Equal("npm", "JSONStream", "jsonstream"))
+}
+
+func TestEscapeGoModule(t *testing.T) {
+	path := "github.com/BurntSushi/toml"
+	assert.Equal(t, "github.com/!burnt!sushi/toml", EscapeGoModule(path))
+	assert.Equal(t, path, GoModule(EscapeGoModule(path)))
+}
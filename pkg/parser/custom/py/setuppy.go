@@ -5,7 +5,7 @@ import (
 
 	"github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/safedep/vet/pkg/common/logger"
-	regex_utils "github.com/safedep/vet/pkg/common/utils/regex"
+	"github.com/safedep/vet/pkg/naming"
 )
 
 func ParseSetuppy(pathToLockfile string) ([]lockfile.PackageDetails, error) {
@@ -90,10 +90,5 @@ func parseRequirementsFileLine(line string) lockfile.PackageDetails {
 // It's possible that this will cause some false positives, but that is better
 // than false negatives, and can be dealt with when/if it actually happens.
 func normalizedRequirementName(name string) string {
-	// per https://www.python.org/dev/peps/pep-0503/#normalized-names
-	name = regex_utils.MustCompileAndCache(`[-_.]+`).ReplaceAllString(name, "-")
-	name = strings.ToLower(name)
-	name, _, _ = strings.Cut(name, "[")
-
-	return name
+	return naming.PyPI(name)
 }
@@ -1,6 +1,7 @@
 package spdx
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
@@ -34,6 +35,27 @@ func Parse(pathToLockfile string) ([]lockfile.PackageDetails, error) {
 	return details, nil
 }
 
+// DocumentNamespace returns the documentNamespace of a JSON SPDX document
+// which uniquely identifies the document
+func DocumentNamespace(path string) (string, error) {
+	r, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer r.Close()
+
+	var doc struct {
+		DocumentNamespace string `json:"documentNamespace"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return "", fmt.Errorf("failed to decode SPDX document: %w", err)
+	}
+
+	return doc.DocumentNamespace, nil
+}
+
 /*
 Parse and create PackageDetailsDoc
 */
@@ -345,3 +345,12 @@ func TestGetDependencies(t *testing.T) {
 		})
 	}
 }
+
+func TestDocumentNamespace(t *testing.T) {
+	namespace, err := DocumentNamespace("./fixtures/janusgraph_oss_2dc3a123d9.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/oss-security-labs/janusgraph/dependency_graph/sbom-95e99f9c487ce673", namespace)
+
+	_, err = DocumentNamespace("./fixtures/does-not-exist.json")
+	assert.Error(t, err)
+}
@@ -8,8 +8,8 @@ import (
 	cdx "github.com/CycloneDX/cyclonedx-go"
 	"github.com/safedep/dry/utils"
 	"github.com/safedep/vet/pkg/common/logger"
-	"github.com/safedep/vet/pkg/common/purl"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/purl"
 )
 
 func parseSbomCycloneDxAsGraph(path string, config *ParserConfig) (*models.PackageManifest, error) {
@@ -51,6 +51,10 @@ func parseSbomCycloneDxAsGraph(path string, config *ParserConfig) (*models.Packa
 	bomRefMap[ref] = pkg
 
 	manifest := models.NewPackageManifest(path, models.EcosystemCyDxSBOM)
+	if bom.SerialNumber != "" {
+		manifest.SbomDocument = &models.SbomDocumentReference{SerialNumber: bom.SerialNumber}
+	}
+
 	components := utils.SafelyGetValue(bom.Components)
 
 	// Iterate over all components in the BOM and add the package in dependency graph
@@ -125,7 +129,7 @@ func cdxExtractPackageFromComponent(component cdx.Component) (string, *models.Pa
 		return "", nil, fmt.Errorf("Invalid CycloneDX SBOM: PackageURL or BOMRef is nil")
 	}
 
-	parsedPurl, err := purl.ParsePackageUrl(pUrl)
+	parsedPurl, err := purl.Parse(pUrl)
 	if err != nil {
 		return "", nil, err
 	}
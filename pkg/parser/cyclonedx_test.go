@@ -7,8 +7,8 @@ import (
 
 	cdx "github.com/CycloneDX/cyclonedx-go"
 	"github.com/google/osv-scanner/pkg/lockfile"
-	"github.com/safedep/vet/pkg/common/purl"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/purl"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -112,7 +112,7 @@ func TestParseCyclonedxSBOMWithGradleSBOM(t *testing.T) {
 	assert.NotEmpty(t, dg.GetNodes())
 	assert.NotEmpty(t, dg.GetPackages())
 
-	pkg, err := purl.ParsePackageUrl("pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.13.0?type=jar")
+	pkg, err := purl.Parse("pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.13.0?type=jar")
 	assert.Nil(t, err)
 
 	nodes := dg.GetDependencies(&models.Package{PackageDetails: pkg.GetPackageDetails()})
@@ -130,7 +130,7 @@ func TestParseCyclonedxSBOMWithMavenSBOM(t *testing.T) {
 	packages := manifest.GetPackages()
 	assert.Equal(t, 167, len(packages))
 
-	pkg, err := purl.ParsePackageUrl("pkg:maven/io.dropwizard/dropwizard-client@1.3.15?type=jar")
+	pkg, err := purl.Parse("pkg:maven/io.dropwizard/dropwizard-client@1.3.15?type=jar")
 	assert.Nil(t, err)
 
 	nodes := manifest.DependencyGraph.GetDependencies(&models.Package{PackageDetails: pkg.GetPackageDetails()})
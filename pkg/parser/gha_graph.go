@@ -8,8 +8,8 @@ import (
 	"github.com/anchore/syft/syft/pkg/cataloger/githubactions"
 	"github.com/anchore/syft/syft/source/filesource"
 	"github.com/safedep/vet/pkg/common/logger"
-	"github.com/safedep/vet/pkg/common/purl"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/purl"
 )
 
 func parseGithubActionWorkflowAsGraph(path string, _ *ParserConfig) (*models.PackageManifest, error) {
@@ -40,7 +40,7 @@ func parseGithubActionWorkflowAsGraph(path string, _ *ParserConfig) (*models.Pac
 
 	manifest := models.NewPackageManifestFromLocal(path, models.EcosystemGitHubActions)
 	for _, pkg := range pkgs {
-		parsedPurl, err := purl.ParsePackageUrl(pkg.PURL)
+		parsedPurl, err := purl.Parse(pkg.PURL)
 		if err != nil {
 			logger.Errorf("failed to parse package url: %s from file: %s: %v",
 				pkg.PURL, path, err)
@@ -8,8 +8,8 @@ import (
 	"github.com/anchore/syft/syft/pkg/cataloger/java"
 	"github.com/anchore/syft/syft/source/filesource"
 	"github.com/safedep/vet/pkg/common/logger"
-	"github.com/safedep/vet/pkg/common/purl"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/purl"
 )
 
 func parseJavaArchiveAsGraph(path string, config *ParserConfig) (*models.PackageManifest, error) {
@@ -40,7 +40,7 @@ func parseJavaArchiveAsGraph(path string, config *ParserConfig) (*models.Package
 
 	manifest := models.NewPackageManifestFromLocal(path, models.EcosystemMaven)
 	for _, pkg := range pkgs {
-		parsedPurl, err := purl.ParsePackageUrl(pkg.PURL)
+		parsedPurl, err := purl.Parse(pkg.PURL)
 		if err != nil {
 			logger.Errorf("failed to parse package url: %s from jar: %s", pkg.PURL, path)
 			continue
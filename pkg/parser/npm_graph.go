@@ -101,9 +101,12 @@ func parseNpmPackageJsonAsGraph(packageJsonPath string, config *ParserConfig) (*
 			continue
 		}
 
+		// The lowest version admitted by the constraint is resolved which
+		// is the worst case for ranges admitting older vulnerable versions
 		pkgDetails := models.NewPackageDetail(models.EcosystemNpm, depName, resolvedVersion)
 		manifest.AddPackage(&models.Package{
-			PackageDetails: pkgDetails,
+			PackageDetails:    pkgDetails,
+			VersionConstraint: depVersion,
 		})
 	}
 
@@ -1,10 +1,15 @@
 package parser
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"regexp"
+	"runtime/debug"
 
 	"github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/safedep/vet/pkg/common/logger"
@@ -27,6 +32,7 @@ const (
 
 var (
 	errUnsupportedFormat = errors.New("unsupported format")
+	errMalformedManifest = errors.New("malformed manifest")
 )
 
 // Exporting as constants for use outside this package to refer to specific
@@ -271,8 +277,84 @@ func (pw *parserWrapper) Parse(lockfilePath string) (*models.PackageManifest, er
 	})
 }
 
-func (pw *parserWrapper) ParseWithConfig(lockfilePath string, config *ParserConfig) (*models.PackageManifest, error) {
+// ParseWithConfig recovers from a panic in the parser so that a malformed
+// third party file fails only its own manifest instead of the scan
+func (pw *parserWrapper) ParseWithConfig(lockfilePath string,
+	config *ParserConfig,
+) (pm *models.PackageManifest, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[%s] Parser panic on %s: %v\n%s", pw.parseAs, lockfilePath, r, debug.Stack())
+
+			pm = nil
+			err = fmt.Errorf("%w: %s parser failed on %s: %v", errMalformedManifest,
+				pw.parseAs, lockfilePath, r)
+		}
+	}()
+
 	logger.Infof("[%s] Parsing %s", pw.parseAs, lockfilePath)
+
+	pm, err = pw.parse(lockfilePath, config)
+	if err != nil || pm == nil {
+		return pm, err
+	}
+
+	pw.annotate(pm, lockfilePath)
+	return pm, nil
+}
+
+// annotate sets the manifest level metadata used to trace findings to the
+// exact artifact scanned. Failure is not fatal for the scan.
+func (pw *parserWrapper) annotate(pm *models.PackageManifest, lockfilePath string) {
+	if pm.Digest == "" {
+		digest, err := manifestDigest(lockfilePath)
+		if err != nil {
+			logger.Warnf("[%s] Failed to compute digest of %s: %v", pw.parseAs, lockfilePath, err)
+		}
+
+		pm.Digest = digest
+	}
+
+	if pw.parseAs == customParserSpdxSBOM && pm.SbomDocument == nil {
+		namespace, err := spdx.DocumentNamespace(lockfilePath)
+		if err != nil {
+			logger.Warnf("[%s] Failed to read document namespace of %s: %v", pw.parseAs, lockfilePath, err)
+		}
+
+		if namespace != "" {
+			pm.SbomDocument = &models.SbomDocumentReference{DocumentUri: namespace}
+		}
+	}
+}
+
+// manifestDigest returns the sha256 digest of a regular file. Manifests
+// such as a directory of jars do not have a digest.
+func manifestDigest(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	if !stat.Mode().IsRegular() {
+		return "", nil
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+
+	return "sha256:" + hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func (pw *parserWrapper) parse(lockfilePath string, config *ParserConfig) (*models.PackageManifest, error) {
 	if pw.graphParser != nil {
 		return pw.graphParser(lockfilePath, config)
 	}
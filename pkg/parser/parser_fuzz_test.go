@@ -0,0 +1,180 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Fuzz targets run the parsers without panic recovery so that a panic on
+// malformed input is reported. Parse errors are expected. Run a target with:
+//
+//	go test ./pkg/parser -run '^$' -fuzz '^FuzzGoMod$' -fuzztime 1m
+func fuzzParser(f *testing.F, parseAs, fileName string, seeds ...[]byte) {
+	p, err := FindParser("", parseAs)
+	if err != nil {
+		f.Fatalf("no parser for %s: %v", parseAs, err)
+	}
+
+	pw := p.(*parserWrapper)
+
+	f.Add([]byte{})
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		path := filepath.Join(t.TempDir(), fileName)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, _ = pw.parse(path, &ParserConfig{IncludeDevDependencies: true})
+	})
+}
+
+func fuzzSeedFile(f *testing.F, path string) []byte {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		f.Fatalf("failed to read seed: %v", err)
+	}
+
+	return data
+}
+
+func fuzzSeedZip(f *testing.F, files map[string]string) []byte {
+	buf := bytes.Buffer{}
+	w := zip.NewWriter(&buf)
+
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			f.Fatalf("failed to create seed: %v", err)
+		}
+
+		if _, err := fw.Write([]byte(content)); err != nil {
+			f.Fatalf("failed to create seed: %v", err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		f.Fatalf("failed to create seed: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func FuzzPackageLockJson(f *testing.F) {
+	fuzzParser(f, "package-lock.json", "package-lock.json",
+		fuzzSeedFile(f, "./fixtures/package-lock-graph.json"),
+		[]byte(`{"lockfileVersion": 3, "packages": {"": {"dependencies": {"a": "^1.0.0"}},
+			"node_modules/a": {"version": "1.0.0", "dependencies": {"b": "*"}}}}`))
+}
+
+func FuzzPackageJson(f *testing.F) {
+	fuzzParser(f, "package.json", "package.json",
+		fuzzSeedFile(f, "./fixtures/package.json"),
+		fuzzSeedFile(f, "./fixtures/package-json-with-only-dev-dependencies.json"))
+}
+
+func FuzzYarnLock(f *testing.F) {
+	fuzzParser(f, "yarn.lock", "yarn.lock",
+		[]byte("# yarn lockfile v1\n\n\"lodash@^4.17.21\":\n  version \"4.17.21\"\n"+
+			"  resolved \"https://registry.yarnpkg.com/lodash/-/lodash-4.17.21.tgz\"\n"))
+}
+
+func FuzzPnpmLock(f *testing.F) {
+	fuzzParser(f, "pnpm-lock.yaml", "pnpm-lock.yaml",
+		[]byte("lockfileVersion: '6.0'\npackages:\n  /lodash@4.17.21:\n    resolution: {integrity: sha512-x}\n"))
+}
+
+func FuzzRequirementsTxt(f *testing.F) {
+	fuzzParser(f, "requirements.txt", "requirements.txt",
+		fuzzSeedFile(f, "../../test/samples/packagefiles/py/requirements_1.txt"),
+		[]byte("-r other.txt\nrequests[security]>=2.0 ; python_version > '3'\n"))
+}
+
+func FuzzPoetryLock(f *testing.F) {
+	fuzzParser(f, "poetry.lock", "poetry.lock",
+		[]byte("[[package]]\nname = \"requests\"\nversion = \"2.31.0\"\n"))
+}
+
+func FuzzPipfileLock(f *testing.F) {
+	fuzzParser(f, "Pipfile.lock", "Pipfile.lock",
+		[]byte(`{"default": {"requests": {"version": "==2.31.0"}}, "develop": {}}`))
+}
+
+func FuzzGoMod(f *testing.F) {
+	fuzzParser(f, "go.mod", "go.mod",
+		fuzzSeedFile(f, "../../go.mod"),
+		[]byte("module a\n\ngo 1.21\n\nrequire b v1.0.0\n\nreplace b => ../b\n"))
+}
+
+func FuzzPomXml(f *testing.F) {
+	fuzzParser(f, "pom.xml", "pom.xml",
+		[]byte(`<project><dependencies><dependency><groupId>a</groupId>`+
+			`<artifactId>b</artifactId><version>${v}</version></dependency></dependencies></project>`))
+}
+
+func FuzzGradleLockfile(f *testing.F) {
+	fuzzParser(f, "gradle.lockfile", "gradle.lockfile",
+		[]byte("org.slf4j:slf4j-api:2.0.9=compileClasspath\nempty=\n"))
+}
+
+func FuzzGemfileLock(f *testing.F) {
+	fuzzParser(f, "Gemfile.lock", "Gemfile.lock",
+		[]byte("GEM\n  remote: https://rubygems.org/\n  specs:\n    rails (7.1.0)\n      actionpack (= 7.1.0)\n"))
+}
+
+func FuzzComposerLock(f *testing.F) {
+	fuzzParser(f, "composer.lock", "composer.lock",
+		[]byte(`{"packages": [{"name": "a/b", "version": "1.0.0"}], "packages-dev": []}`))
+}
+
+func FuzzCycloneDx(f *testing.F) {
+	fuzzParser(f, customParserCycloneDXSBOM, "bom.json",
+		fuzzSeedFile(f, "./fixtures/bom-maven.json"))
+}
+
+func FuzzSpdx(f *testing.F) {
+	fuzzParser(f, customParserSpdxSBOM, "bom.spdx.json",
+		fuzzSeedFile(f, "./custom/sbom/spdx/fixtures/requests_psf_2ee5b0b01.json"))
+}
+
+func FuzzGitHubActions(f *testing.F) {
+	fuzzParser(f, customParserGitHubActions, ".github/workflows/ci.yml",
+		fuzzSeedFile(f, "./fixtures/gha/.github/workflows/ci.yml"))
+}
+
+func FuzzTerraformLock(f *testing.F) {
+	fuzzParser(f, customParserTerraform, ".terraform.lock.hcl",
+		fuzzSeedFile(f, "./fixtures/terraform.lock.hcl"))
+}
+
+func FuzzSetupPy(f *testing.F) {
+	fuzzParser(f, customParserTypeSetupPy, "setup.py",
+		fuzzSeedFile(f, "./custom/py/fixtures/setuppy/setup1.py"))
+}
+
+func FuzzPythonWheel(f *testing.F) {
+	fuzzParser(f, customParserTypePyWheel, "a-1.0.0-py3-none-any.whl",
+		fuzzSeedZip(f, map[string]string{
+			"a-1.0.0.dist-info/METADATA": "Metadata-Version: 2.1\nName: a\nVersion: 1.0.0\n" +
+				"Requires-Dist: requests (>=2.0)\n\n",
+		}))
+}
+
+func FuzzJavaArchive(f *testing.F) {
+	fuzzParser(f, customParserTypeJavaArchive, "a.jar",
+		fuzzSeedZip(f, map[string]string{
+			"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\nImplementation-Title: a\n" +
+				"Implementation-Version: 1.0.0\n",
+			"META-INF/maven/org.example/a/pom.properties": "groupId=org.example\nartifactId=a\nversion=1.0.0\n",
+		}))
+}
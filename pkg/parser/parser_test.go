@@ -1,11 +1,18 @@
 package parser
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/safedep/vet/pkg/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestListParser(t *testing.T) {
@@ -71,3 +78,103 @@ func TestFindParserForGitHubAction(t *testing.T) {
 		})
 	}
 }
+
+func TestParserPanicIsRecovered(t *testing.T) {
+	cases := []struct {
+		name string
+		pw   *parserWrapper
+	}{
+		{
+			"lockfile parser",
+			&parserWrapper{
+				parseAs: "requirements.txt",
+				parser: func(string) ([]lockfile.PackageDetails, error) {
+					var details []lockfile.PackageDetails
+					return []lockfile.PackageDetails{details[1]}, nil
+				},
+			},
+		},
+		{
+			"graph parser",
+			&parserWrapper{
+				parseAs: "package.json",
+				graphParser: func(string, *ParserConfig) (*models.PackageManifest, error) {
+					panic("malformed")
+				},
+			},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			pm, err := test.pw.Parse("/a/b")
+			assert.Nil(t, pm)
+			assert.ErrorIs(t, err, errMalformedManifest)
+		})
+	}
+}
+
+func TestParserManifestMetadata(t *testing.T) {
+	cases := []struct {
+		name         string
+		path         string
+		parseAs      string
+		kind         models.ManifestKind
+		sbomDocument *models.SbomDocumentReference
+	}{
+		{
+			"lockfile",
+			"./fixtures/package-lock-graph.json",
+			"package-lock.json",
+			models.ManifestKindLockfile,
+			nil,
+		},
+		{
+			"cyclonedx sbom",
+			"./fixtures/bom-maven.json",
+			LockfileAsBomCycloneDx,
+			models.ManifestKindSbom,
+			&models.SbomDocumentReference{SerialNumber: "urn:uuid:c334aaf0-543e-4252-8888-3d21ae52dd11"},
+		},
+		{
+			"spdx sbom",
+			"./custom/sbom/spdx/fixtures/requests_psf_2ee5b0b01.json",
+			LockfileAsBomSpdx,
+			models.ManifestKindSbom,
+			&models.SbomDocumentReference{DocumentUri: "https://github.com/psf/requests/dependency_graph/sbom-fd982324975211d7"},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			content, err := os.ReadFile(test.path)
+			require.NoError(t, err)
+
+			digest := sha256.Sum256(content)
+
+			pw, err := FindParser(test.path, test.parseAs)
+			require.NoError(t, err)
+
+			pm, err := pw.Parse(test.path)
+			require.NoError(t, err)
+
+			assert.Equal(t, "sha256:"+hex.EncodeToString(digest[:]), pm.GetDigest())
+			assert.Equal(t, test.kind, pm.GetKind())
+			assert.Equal(t, test.sbomDocument, pm.GetSbomDocument())
+		})
+	}
+}
+
+func TestParserMalformedPnpmLock(t *testing.T) {
+	// Found by FuzzPnpmLock, the upstream parser panics on an empty
+	// package name
+	path := filepath.Join(t.TempDir(), "pnpm-lock.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("lockfileVersion: '0'\npackages:\n /@:"), 0600))
+
+	p, err := FindParser("", "pnpm-lock.yaml")
+	assert.NoError(t, err)
+
+	pm, err := p.Parse(path)
+	assert.Nil(t, pm)
+	assert.ErrorIs(t, err, errMalformedManifest)
+}
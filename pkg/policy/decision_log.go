@@ -0,0 +1,283 @@
+package policy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/httpclient"
+	"github.com/safedep/vet/pkg/common/logger"
+)
+
+type DecisionOutcome string
+
+const (
+	DecisionOutcomeMatched    = DecisionOutcome("matched")
+	DecisionOutcomeNotMatched = DecisionOutcome("not_matched")
+	DecisionOutcomeError      = DecisionOutcome("error")
+
+	defaultHttpDecisionLoggerBatchSize = 100
+	defaultHttpDecisionLoggerQueueSize = 10
+	defaultHttpDecisionLoggerTimeout   = 10 * time.Second
+)
+
+// Decision is the outcome of evaluating a policy rule on a package. The
+// input digest identifies the exact input used for evaluation without
+// logging the input itself.
+type Decision struct {
+	Time        time.Time       `json:"time"`
+	Policy      string          `json:"policy"`
+	Rule        string          `json:"rule"`
+	Expression  string          `json:"expression"`
+	Ecosystem   string          `json:"ecosystem"`
+	Package     string          `json:"package"`
+	Version     string          `json:"version"`
+	InputDigest string          `json:"input_digest"`
+	Outcome     DecisionOutcome `json:"outcome"`
+	Duration    time.Duration   `json:"duration_ns"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// DecisionLogger is a sink for policy decisions. Implementations
+// must be safe for concurrent use.
+type DecisionLogger interface {
+	Log(decision *Decision) error
+
+	// Flush pending decisions and release resources
+	Close() error
+}
+
+// NewDecisionLogger creates an HTTP decision logger when target is an
+// HTTP(S) URL, otherwise a file decision logger
+func NewDecisionLogger(target string) (DecisionLogger, error) {
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		return NewHttpDecisionLogger(HttpDecisionLoggerConfig{Url: target})
+	}
+
+	return NewFileDecisionLogger(target)
+}
+
+type fileDecisionLogger struct {
+	m      sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// NewFileDecisionLogger appends decisions as JSON lines to a file
+func NewFileDecisionLogger(path string) (DecisionLogger, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open decision log: %w", err)
+	}
+
+	return &fileDecisionLogger{
+		file:   file,
+		writer: bufio.NewWriter(file),
+	}, nil
+}
+
+func (l *fileDecisionLogger) Log(decision *Decision) error {
+	data, err := json.Marshal(decision)
+	if err != nil {
+		return fmt.Errorf("failed to serialize decision: %w", err)
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	_, err = l.writer.Write(append(data, '\n'))
+	return err
+}
+
+func (l *fileDecisionLogger) Close() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	err := l.writer.Flush()
+	return errors.Join(err, l.file.Close())
+}
+
+type HttpDecisionLoggerConfig struct {
+	Url string
+
+	// Optional headers e.g. Authorization
+	Headers map[string]string
+
+	// Number of decisions sent in a request
+	BatchSize int
+
+	// Max number of batches waiting to be sent. Batches are dropped
+	// when the queue is full so that evaluation is never blocked.
+	QueueSize int
+
+	// Timeout of a request to the collector
+	Timeout time.Duration
+
+	// Optional HTTP client, defaults to shared client
+	HttpClient *http.Client
+}
+
+type httpDecisionLogger struct {
+	config HttpDecisionLoggerConfig
+
+	m       sync.Mutex
+	pending []*Decision
+	closed  bool
+
+	queue chan []*Decision
+	done  chan struct{}
+
+	// Accessed only by the sender after start and by Close after done
+	failed  int
+	lastErr error
+}
+
+// NewHttpDecisionLogger posts decisions in batches as newline
+// delimited JSON to a collector. Batches are sent asynchronously.
+func NewHttpDecisionLogger(config HttpDecisionLoggerConfig) (DecisionLogger, error) {
+	if config.Url == "" {
+		return nil, errors.New("decision log URL is required")
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultHttpDecisionLoggerBatchSize
+	}
+
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultHttpDecisionLoggerQueueSize
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = defaultHttpDecisionLoggerTimeout
+	}
+
+	if config.HttpClient == nil {
+		config.HttpClient = httpclient.Default()
+	}
+
+	l := &httpDecisionLogger{
+		config: config,
+		queue:  make(chan []*Decision, config.QueueSize),
+		done:   make(chan struct{}),
+	}
+
+	go l.sender()
+	return l, nil
+}
+
+func (l *httpDecisionLogger) Log(decision *Decision) error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if l.closed {
+		return errors.New("decision logger is closed")
+	}
+
+	l.pending = append(l.pending, decision)
+	if len(l.pending) < l.config.BatchSize {
+		return nil
+	}
+
+	return l.enqueue()
+}
+
+// Close sends the pending decisions and waits for the queue to drain.
+// Each request is bounded by the timeout, hence so is Close.
+func (l *httpDecisionLogger) Close() error {
+	l.m.Lock()
+	if l.closed {
+		l.m.Unlock()
+		return nil
+	}
+
+	err := l.enqueue()
+	l.closed = true
+	close(l.queue)
+	l.m.Unlock()
+
+	<-l.done
+
+	if l.failed > 0 {
+		err = errors.Join(err, fmt.Errorf("failed to send %d decisions: %w", l.failed, l.lastErr))
+	}
+
+	return err
+}
+
+// Must be called with lock held
+func (l *httpDecisionLogger) enqueue() error {
+	if len(l.pending) == 0 {
+		return nil
+	}
+
+	batch := l.pending
+	l.pending = nil
+
+	select {
+	case l.queue <- batch:
+		return nil
+	default:
+		return fmt.Errorf("decision log queue is full, dropped %d decisions", len(batch))
+	}
+}
+
+// Decisions are dropped when the collector fails to avoid
+// unbounded growth of pending decisions
+func (l *httpDecisionLogger) sender() {
+	defer close(l.done)
+
+	for batch := range l.queue {
+		if err := l.send(batch); err != nil {
+			logger.Warnf("Failed to send policy decisions: %v", err)
+
+			l.failed += len(batch)
+			l.lastErr = err
+		}
+	}
+}
+
+func (l *httpDecisionLogger) send(batch []*Decision) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, decision := range batch {
+		err := encoder.Encode(decision)
+		if err != nil {
+			return fmt.Errorf("failed to serialize decision: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.config.Url, &buf)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	for k, v := range l.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := l.config.HttpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %d decisions: %w", len(batch), err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("failed to send %d decisions: unexpected status code %d",
+			len(batch), res.StatusCode)
+	}
+
+	return nil
+}
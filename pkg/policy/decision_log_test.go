@@ -0,0 +1,140 @@
+package policy
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileDecisionLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "decisions.jsonl")
+
+	l, err := NewDecisionLogger(path)
+	require.NoError(t, err)
+
+	for _, outcome := range []DecisionOutcome{DecisionOutcomeMatched, DecisionOutcomeNotMatched} {
+		err = l.Log(&Decision{
+			Policy:   "suite",
+			Rule:     "critical-vulns",
+			Package:  "lodash",
+			Outcome:  outcome,
+			Duration: time.Millisecond,
+		})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, l.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	var outcomes []DecisionOutcome
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var d Decision
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &d))
+
+		assert.Equal(t, "critical-vulns", d.Rule)
+		assert.Equal(t, time.Millisecond, d.Duration)
+		outcomes = append(outcomes, d.Outcome)
+	}
+
+	assert.Equal(t, []DecisionOutcome{DecisionOutcomeMatched, DecisionOutcomeNotMatched}, outcomes)
+}
+
+func TestHttpDecisionLogger(t *testing.T) {
+	cases := []struct {
+		name       string
+		decisions  int
+		batchSize  int
+		status     int
+		batches    []int
+		errOnClose bool
+	}{
+		{"Flush on close", 3, 10, http.StatusOK, []int{3}, false},
+		{"Flush on full batch", 5, 2, http.StatusOK, []int{2, 2, 1}, false},
+		{"Collector failure", 2, 2, http.StatusInternalServerError, []int{2}, true},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			var batches []int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+				assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+				count := 0
+				decoder := json.NewDecoder(r.Body)
+				for decoder.More() {
+					var d Decision
+					assert.NoError(t, decoder.Decode(&d))
+					count += 1
+				}
+
+				batches = append(batches, count)
+				w.WriteHeader(test.status)
+			}))
+
+			defer server.Close()
+
+			l, err := NewHttpDecisionLogger(HttpDecisionLoggerConfig{
+				Url:       server.URL,
+				Headers:   map[string]string{"Authorization": "Bearer token"},
+				BatchSize: test.batchSize,
+			})
+			require.NoError(t, err)
+
+			for i := 0; i < test.decisions; i++ {
+				assert.NoError(t, l.Log(&Decision{Rule: "rule"}))
+			}
+
+			err = l.Close()
+			assert.Equal(t, test.errOnClose, err != nil)
+			assert.Equal(t, test.batches, batches)
+		})
+	}
+}
+
+func TestHttpDecisionLoggerStalledCollector(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+
+	defer server.Close()
+	defer close(release)
+
+	l, err := NewHttpDecisionLogger(HttpDecisionLoggerConfig{
+		Url:       server.URL,
+		BatchSize: 1,
+		QueueSize: 1,
+		Timeout:   100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	// Logging does not wait for the collector, batches beyond
+	// the queue are dropped
+	var dropped int
+	for i := 0; i < 5; i++ {
+		if err := l.Log(&Decision{Rule: "rule"}); err != nil {
+			assert.ErrorContains(t, err, "queue is full")
+			dropped += 1
+		}
+	}
+
+	assert.Greater(t, dropped, 0)
+
+	start := time.Now()
+	assert.ErrorContains(t, l.Close(), "failed to send")
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
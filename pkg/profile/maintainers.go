@@ -0,0 +1,160 @@
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/httpclient"
+	"github.com/safedep/vet/pkg/models"
+)
+
+const (
+	defaultMaintainerNpmRegistryUrl = "https://registry.npmjs.org"
+	defaultMaintainerPyPIUrl        = "https://pypi.org"
+	defaultMaintainerTimeout        = 10 * time.Second
+)
+
+// Maintainer is an account with publish rights on a package
+type Maintainer struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+
+	// Optional, role of the account e.g. Owner for PyPI
+	Role string `json:"role,omitempty"`
+}
+
+// MaintainerResolver finds the maintainers of a package version.
+// Maintainers are not available from Insights, hence are resolved
+// from the package registry.
+type MaintainerResolver interface {
+	Maintainers(ecosystem, name, version string) ([]Maintainer, error)
+}
+
+type RegistryMaintainerResolverConfig struct {
+	// Optional, defaults to public registries
+	NpmRegistryUrl string
+	PyPIUrl        string
+
+	// Timeout of a registry request
+	Timeout time.Duration
+
+	// Optional HTTP client, defaults to shared client
+	HttpClient *http.Client
+}
+
+type registryMaintainerResolver struct {
+	config RegistryMaintainerResolverConfig
+}
+
+func NewRegistryMaintainerResolver(config RegistryMaintainerResolverConfig) MaintainerResolver {
+	if config.NpmRegistryUrl == "" {
+		config.NpmRegistryUrl = defaultMaintainerNpmRegistryUrl
+	}
+
+	if config.PyPIUrl == "" {
+		config.PyPIUrl = defaultMaintainerPyPIUrl
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = defaultMaintainerTimeout
+	}
+
+	if config.HttpClient == nil {
+		config.HttpClient = httpclient.Default()
+	}
+
+	config.NpmRegistryUrl = strings.TrimSuffix(config.NpmRegistryUrl, "/")
+	config.PyPIUrl = strings.TrimSuffix(config.PyPIUrl, "/")
+
+	return &registryMaintainerResolver{config: config}
+}
+
+// Maintainers returns nil for ecosystems without maintainer
+// information in the registry
+func (r *registryMaintainerResolver) Maintainers(ecosystem, name, version string) ([]Maintainer, error) {
+	switch ecosystem {
+	case models.EcosystemNpm:
+		return r.npmMaintainers(name, version)
+	case models.EcosystemPyPI:
+		return r.pypiMaintainers(name)
+	default:
+		return nil, nil
+	}
+}
+
+func (r *registryMaintainerResolver) npmMaintainers(name, version string) ([]Maintainer, error) {
+	var res struct {
+		Maintainers []struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"maintainers"`
+	}
+
+	// Scoped package names must retain `@` but escape `/`
+	u := fmt.Sprintf("%s/%s/%s", r.config.NpmRegistryUrl,
+		strings.Replace(url.PathEscape(name), "%40", "@", 1), url.PathEscape(version))
+
+	if err := r.get(u, &res); err != nil {
+		return nil, err
+	}
+
+	maintainers := []Maintainer{}
+	for _, m := range res.Maintainers {
+		maintainers = append(maintainers, Maintainer{Name: m.Name, Email: m.Email})
+	}
+
+	return maintainers, nil
+}
+
+// PyPI ownership is of the project and not the release
+func (r *registryMaintainerResolver) pypiMaintainers(name string) ([]Maintainer, error) {
+	var res struct {
+		Ownership struct {
+			Roles []struct {
+				Role string `json:"role"`
+				User string `json:"user"`
+			} `json:"roles"`
+		} `json:"ownership"`
+	}
+
+	if err := r.get(fmt.Sprintf("%s/pypi/%s/json", r.config.PyPIUrl, url.PathEscape(name)), &res); err != nil {
+		return nil, err
+	}
+
+	maintainers := []Maintainer{}
+	for _, role := range res.Ownership.Roles {
+		maintainers = append(maintainers, Maintainer{Name: role.User, Role: role.Role})
+	}
+
+	return maintainers, nil
+}
+
+func (r *registryMaintainerResolver) get(u string, out any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := r.config.HttpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch maintainers: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch maintainers: unexpected status code %d", res.StatusCode)
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
@@ -0,0 +1,280 @@
+// Package profile builds the enriched profile of a single package version
+// for consumers such as editor plugins and chat bots that need a verdict
+// on a package without running a full scan.
+package profile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/vet/gen/filtersuite"
+	"github.com/safedep/vet/pkg/analyzer/filter"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/health"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/purl"
+	"github.com/safedep/vet/pkg/scanner"
+)
+
+type Vulnerability struct {
+	Id       string   `json:"id"`
+	Summary  string   `json:"summary"`
+	Aliases  []string `json:"aliases"`
+	Severity string   `json:"severity"`
+}
+
+type Scorecard struct {
+	Score  float64            `json:"score"`
+	Checks map[string]float64 `json:"checks"`
+}
+
+// Project is the source project of a package. Maintainer signals derived
+// from the project are available in health score factors.
+type Project struct {
+	Name  string `json:"name"`
+	Url   string `json:"url"`
+	Stars int    `json:"stars"`
+	Forks int    `json:"forks"`
+}
+
+type PolicyViolation struct {
+	Name      string `json:"name"`
+	Summary   string `json:"summary"`
+	CheckType string `json:"check_type"`
+}
+
+// Verdict of policy under current configuration. A package without
+// policy violations is allowed.
+type Verdict struct {
+	Evaluated  bool              `json:"evaluated"`
+	Allowed    bool              `json:"allowed"`
+	Violations []PolicyViolation `json:"violations"`
+}
+
+// PackageProfile is the enriched profile of a package version
+type PackageProfile struct {
+	Purl            string          `json:"purl"`
+	Ecosystem       string          `json:"ecosystem"`
+	Name            string          `json:"name"`
+	Version         string          `json:"version"`
+	LatestVersion   string          `json:"latest_version"`
+	Enriched        bool            `json:"enriched"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+	Licenses        []string        `json:"licenses"`
+	Scorecard       *Scorecard      `json:"scorecard,omitempty"`
+	Projects        []Project       `json:"projects"`
+	Maintainers     []Maintainer    `json:"maintainers"`
+	Health          *health.Score   `json:"health"`
+	Malware         *Malware        `json:"malware,omitempty"`
+	Verdict         Verdict         `json:"verdict"`
+}
+
+type Malware struct {
+	IsMalware    bool   `json:"is_malware"`
+	IsSuspicious bool   `json:"is_suspicious"`
+	AnalysisId   string `json:"analysis_id"`
+}
+
+// Profiler builds the profile of a package identified by its purl
+type Profiler interface {
+	Profile(purl string) (*PackageProfile, error)
+}
+
+type ProfilerConfig struct {
+	// Enrichers must complete enrichment within the Enrich call
+	// because Wait is not invoked for each profile
+	Enrichers []scanner.PackageMetaEnricher
+
+	// Optional, policy evaluated to build the verdict
+	FilterSuite *filtersuite.FilterSuite
+
+	// Optional, resolves maintainers of the package. Failure to resolve
+	// is logged and does not fail the profile.
+	Maintainers MaintainerResolver
+}
+
+type policyProgram struct {
+	filter    *filtersuite.Filter
+	evaluator filter.Evaluator
+}
+
+type profiler struct {
+	config   ProfilerConfig
+	policies []*policyProgram
+}
+
+func NewProfiler(config ProfilerConfig) (Profiler, error) {
+	p := &profiler{config: config}
+
+	// Each filter is evaluated independently so that all
+	// violations are reported and not just the first match
+	for _, f := range config.FilterSuite.GetFilters() {
+		evaluator, err := filter.NewEvaluator(f.GetName(), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create filter evaluator: %w", err)
+		}
+
+		err = evaluator.AddFilter(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile filter %s: %w", f.GetName(), err)
+		}
+
+		p.policies = append(p.policies, &policyProgram{filter: f, evaluator: evaluator})
+	}
+
+	return p, nil
+}
+
+func (p *profiler) Profile(packageUrl string) (*PackageProfile, error) {
+	parsedPurl, err := purl.Parse(packageUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse package url: %w", err)
+	}
+
+	pd := parsedPurl.GetPackageDetails()
+	if pd.Version == "" {
+		return nil, errors.New("package url must include a version")
+	}
+
+	pm := models.NewPackageManifestFromPurl(packageUrl, string(pd.Ecosystem))
+	pkg := &models.Package{
+		PackageDetails: pd,
+		Manifest:       pm,
+	}
+
+	pm.AddPackage(pkg)
+
+	for _, enricher := range p.config.Enrichers {
+		err := enricher.Enrich(pkg, func(_ *models.Package) error {
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s failed to enrich package: %w", enricher.Name(), err)
+		}
+	}
+
+	profile := buildProfile(packageUrl, pkg)
+	if p.config.Maintainers != nil {
+		maintainers, err := p.config.Maintainers.Maintainers(string(pkg.Ecosystem), pkg.GetName(), pkg.GetVersion())
+		if err != nil {
+			logger.Warnf("Failed to resolve maintainers of %s: %v", packageUrl, err)
+		} else if maintainers != nil {
+			profile.Maintainers = maintainers
+		}
+	}
+
+	verdict, err := p.verdict(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Verdict = verdict
+	return profile, nil
+}
+
+func (p *profiler) verdict(pkg *models.Package) (Verdict, error) {
+	verdict := Verdict{
+		Evaluated:  len(p.policies) > 0,
+		Allowed:    true,
+		Violations: []PolicyViolation{},
+	}
+
+	for _, policy := range p.policies {
+		res, err := policy.evaluator.EvalPackage(pkg)
+		if err != nil {
+			return verdict, fmt.Errorf("failed to evaluate filter %s: %w", policy.filter.GetName(), err)
+		}
+
+		if !res.Matched() {
+			continue
+		}
+
+		verdict.Allowed = false
+		verdict.Violations = append(verdict.Violations, PolicyViolation{
+			Name:      policy.filter.GetName(),
+			Summary:   policy.filter.GetSummary(),
+			CheckType: policy.filter.GetCheckType().String(),
+		})
+	}
+
+	return verdict, nil
+}
+
+func buildProfile(packageUrl string, pkg *models.Package) *PackageProfile {
+	profile := &PackageProfile{
+		Purl:            packageUrl,
+		Ecosystem:       string(pkg.Ecosystem),
+		Name:            pkg.GetName(),
+		Version:         pkg.GetVersion(),
+		Enriched:        pkg.Insights != nil,
+		Vulnerabilities: []Vulnerability{},
+		Licenses:        []string{},
+		Projects:        []Project{},
+		Maintainers:     []Maintainer{},
+		Health:          health.Compute(pkg),
+	}
+
+	insights := utils.SafelyGetValue(pkg.Insights)
+	profile.LatestVersion = utils.SafelyGetValue(insights.PackageCurrentVersion)
+
+	for _, vuln := range utils.SafelyGetValue(insights.Vulnerabilities) {
+		v := Vulnerability{
+			Id:       utils.SafelyGetValue(vuln.Id),
+			Summary:  utils.SafelyGetValue(vuln.Summary),
+			Aliases:  utils.SafelyGetValue(vuln.Aliases),
+			Severity: "UNKNOWN",
+		}
+
+		for _, s := range utils.SafelyGetValue(vuln.Severities) {
+			if s.Risk != nil {
+				v.Severity = string(*s.Risk)
+				break
+			}
+		}
+
+		profile.Vulnerabilities = append(profile.Vulnerabilities, v)
+	}
+
+	for _, license := range utils.SafelyGetValue(insights.Licenses) {
+		profile.Licenses = append(profile.Licenses, string(license))
+	}
+
+	for _, project := range utils.SafelyGetValue(insights.Projects) {
+		profile.Projects = append(profile.Projects, Project{
+			Name:  utils.SafelyGetValue(project.Name),
+			Url:   utils.SafelyGetValue(project.Link),
+			Stars: utils.SafelyGetValue(project.Stars),
+			Forks: utils.SafelyGetValue(project.Forks),
+		})
+	}
+
+	scorecard := utils.SafelyGetValue(insights.Scorecard)
+	if content := scorecard.Content; content != nil && content.Score != nil {
+		profile.Scorecard = &Scorecard{
+			Score:  float64(utils.SafelyGetValue(content.Score)),
+			Checks: map[string]float64{},
+		}
+
+		for _, check := range utils.SafelyGetValue(content.Checks) {
+			if check.Name == nil || check.Score == nil {
+				continue
+			}
+
+			profile.Scorecard.Checks[string(*check.Name)] = float64(*check.Score)
+		}
+	}
+
+	// Same decision as the malware analyzer with untrusted automated
+	// analysis i.e. malware only when verified, suspicious otherwise
+	if ma := pkg.GetMalwareAnalysisResult(); ma != nil {
+		isMalware := ma.IsMalware || ma.VerificationRecord.GetIsMalware()
+		profile.Malware = &Malware{
+			IsMalware:    isMalware,
+			IsSuspicious: !isMalware && (ma.IsSuspicious || ma.Report.GetInference().GetIsMalware()),
+			AnalysisId:   ma.AnalysisId,
+		}
+	}
+
+	return profile
+}
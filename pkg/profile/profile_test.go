@@ -0,0 +1,258 @@
+package profile
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	malysisv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/malysis/v1"
+	"github.com/safedep/vet/gen/checks"
+	"github.com/safedep/vet/gen/filtersuite"
+	"github.com/safedep/vet/gen/insightapi"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticInsightsEnricher struct {
+	insights *insightapi.PackageVersionInsight
+}
+
+func (e *staticInsightsEnricher) Name() string {
+	return "Static Insights"
+}
+
+func (e *staticInsightsEnricher) Enrich(pkg *models.Package, _ scanner.PackageDependencyCallbackFn) error {
+	pkg.Insights = e.insights
+	return nil
+}
+
+func (e *staticInsightsEnricher) Wait() error {
+	return nil
+}
+
+func TestProfile(t *testing.T) {
+	high := insightapi.PackageVulnerabilitySeveritiesRiskHIGH
+	cvss3 := insightapi.PackageVulnerabilitySeveritiesTypeCVSSV3
+	scorecardScore := float32(4.5)
+	stars := 100
+	latestVersion := "4.17.21"
+	projectName := "lodash/lodash"
+	vulnId := "GHSA-35jh-r3h4-6jhm"
+
+	insights := &insightapi.PackageVersionInsight{
+		PackageCurrentVersion: &latestVersion,
+		Licenses:              &[]insightapi.License{"MIT"},
+		Projects: &[]insightapi.PackageProjectInfo{
+			{Name: &projectName, Stars: &stars},
+		},
+		Scorecard: &insightapi.Scorecard{
+			Content: &insightapi.ScorecardContentV2{Score: &scorecardScore},
+		},
+		Vulnerabilities: &[]insightapi.PackageVulnerability{
+			{
+				Id:      &vulnId,
+				Aliases: &[]string{"CVE-2021-23337"},
+				Severities: &[]struct {
+					Risk  *insightapi.PackageVulnerabilitySeveritiesRisk `json:"risk,omitempty"`
+					Score *string                                        `json:"score,omitempty"`
+					Type  *insightapi.PackageVulnerabilitySeveritiesType `json:"type,omitempty"`
+				}{
+					{Risk: &high, Type: &cvss3},
+				},
+			},
+		},
+	}
+
+	suite := &filtersuite.FilterSuite{
+		Filters: []*filtersuite.Filter{
+			{
+				Name:      "critical-or-high-vulns",
+				Value:     "vulns.critical.exists(p, true) || vulns.high.exists(p, true)",
+				CheckType: checks.CheckType_CheckTypeVulnerability,
+			},
+			{
+				Name:      "copyleft-license",
+				Value:     "licenses.exists(p, p == \"GPL-3.0\")",
+				CheckType: checks.CheckType_CheckTypeLicense,
+			},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		purl       string
+		suite      *filtersuite.FilterSuite
+		evaluated  bool
+		allowed    bool
+		violations []string
+		err        string
+	}{
+		{
+			"Policy violation",
+			"pkg:npm/lodash@4.17.20",
+			suite,
+			true,
+			false,
+			[]string{"critical-or-high-vulns"},
+			"",
+		},
+		{
+			"No policy",
+			"pkg:npm/lodash@4.17.20",
+			nil,
+			false,
+			true,
+			nil,
+			"",
+		},
+		{
+			"Version is required",
+			"pkg:npm/lodash",
+			nil,
+			false,
+			false,
+			nil,
+			"package url must include a version",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := NewProfiler(ProfilerConfig{
+				Enrichers:   []scanner.PackageMetaEnricher{&staticInsightsEnricher{insights: insights}},
+				FilterSuite: test.suite,
+			})
+			require.NoError(t, err)
+
+			profile, err := p.Profile(test.purl)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, "lodash", profile.Name)
+			assert.Equal(t, "4.17.20", profile.Version)
+			assert.Equal(t, "4.17.21", profile.LatestVersion)
+			assert.True(t, profile.Enriched)
+			assert.Equal(t, []string{"MIT"}, profile.Licenses)
+			assert.Equal(t, 4.5, profile.Scorecard.Score)
+			assert.Equal(t, "lodash/lodash", profile.Projects[0].Name)
+			assert.True(t, profile.Health.Available)
+
+			require.Len(t, profile.Vulnerabilities, 1)
+			assert.Equal(t, "GHSA-35jh-r3h4-6jhm", profile.Vulnerabilities[0].Id)
+			assert.Equal(t, "HIGH", profile.Vulnerabilities[0].Severity)
+
+			assert.Equal(t, test.evaluated, profile.Verdict.Evaluated)
+			assert.Equal(t, test.allowed, profile.Verdict.Allowed)
+
+			var violations []string
+			for _, v := range profile.Verdict.Violations {
+				violations = append(violations, v.Name)
+			}
+
+			assert.Equal(t, test.violations, violations)
+		})
+	}
+}
+
+func TestProfileMaintainers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/@scope%2Fdemo/1.0.0", "/@scope/demo/1.0.0":
+			_, _ = w.Write([]byte(`{"maintainers":[{"name":"alice","email":"alice@example.com"}]}`))
+		case "/pypi/demo/json":
+			_, _ = w.Write([]byte(`{"ownership":{"roles":[{"role":"Owner","user":"bob"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	resolver := NewRegistryMaintainerResolver(RegistryMaintainerResolverConfig{
+		NpmRegistryUrl: server.URL,
+		PyPIUrl:        server.URL,
+	})
+
+	cases := []struct {
+		purl        string
+		maintainers []Maintainer
+	}{
+		{"pkg:npm/%40scope/demo@1.0.0", []Maintainer{{Name: "alice", Email: "alice@example.com"}}},
+		{"pkg:pypi/demo@1.0.0", []Maintainer{{Name: "bob", Role: "Owner"}}},
+		{"pkg:npm/missing@1.0.0", []Maintainer{}},
+		{"pkg:golang/github.com/safedep/vet@v1.0.0", []Maintainer{}},
+	}
+
+	for _, test := range cases {
+		t.Run(test.purl, func(t *testing.T) {
+			p, err := NewProfiler(ProfilerConfig{Maintainers: resolver})
+			require.NoError(t, err)
+
+			profile, err := p.Profile(test.purl)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.maintainers, profile.Maintainers)
+		})
+	}
+}
+
+type staticMalwareEnricher struct {
+	result *models.MalwareAnalysisResult
+}
+
+func (e *staticMalwareEnricher) Name() string {
+	return "Static Malware"
+}
+
+func (e *staticMalwareEnricher) Enrich(pkg *models.Package, _ scanner.PackageDependencyCallbackFn) error {
+	pkg.SetMalwareAnalysisResult(e.result)
+	return nil
+}
+
+func (e *staticMalwareEnricher) Wait() error {
+	return nil
+}
+
+func TestProfileMalware(t *testing.T) {
+	malicious := &malysisv1.Report{Inference: &malysisv1.Report_Inference{IsMalware: true}}
+
+	cases := []struct {
+		name       string
+		result     *models.MalwareAnalysisResult
+		malware    bool
+		suspicious bool
+	}{
+		{"Unverified is suspicious", &models.MalwareAnalysisResult{Report: malicious}, false, true},
+		{
+			"Verified is malware",
+			&models.MalwareAnalysisResult{
+				Report:             malicious,
+				VerificationRecord: &malysisv1.VerificationRecord{IsMalware: true},
+			},
+			true, false,
+		},
+		{"Benign", &models.MalwareAnalysisResult{Report: &malysisv1.Report{}}, false, false},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := NewProfiler(ProfilerConfig{
+				Enrichers: []scanner.PackageMetaEnricher{&staticMalwareEnricher{result: test.result}},
+			})
+			require.NoError(t, err)
+
+			profile, err := p.Profile("pkg:npm/demo@1.0.0")
+			require.NoError(t, err)
+
+			require.NotNil(t, profile.Malware)
+			assert.Equal(t, test.malware, profile.Malware.IsMalware)
+			assert.Equal(t, test.suspicious, profile.Malware.IsSuspicious)
+		})
+	}
+}
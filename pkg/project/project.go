@@ -0,0 +1,298 @@
+package project
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/mod/modfile"
+)
+
+// Metadata of a project inferred from the files in its repository
+type Metadata struct {
+	Name    string
+	Version string
+
+	// Remote URL of the git repository without credentials
+	SourceUrl string
+}
+
+type inferrer struct {
+	file  string
+	infer func(data []byte) (Metadata, error)
+}
+
+// Manifests are tried in order, the first manifest with a name takes
+// precedence. Version is taken from the first manifest with a version.
+var inferrers = []inferrer{
+	{"go.mod", inferGoModule},
+	{"package.json", inferPackageJson},
+	{"pyproject.toml", inferPyProject},
+}
+
+// Infer returns the metadata of the project in dir inferred from the go.mod,
+// package.json or pyproject.toml in dir and the git repository containing
+// dir. Name of the repository is used when no manifest has a name. Fields
+// that cannot be inferred are empty.
+func Infer(dir string) (Metadata, error) {
+	metadata := Metadata{}
+
+	for _, inf := range inferrers {
+		data, err := os.ReadFile(filepath.Join(dir, inf.file))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return metadata, fmt.Errorf("failed to read %s: %w", inf.file, err)
+		}
+
+		m, err := inf.infer(data)
+		if err != nil {
+			return metadata, fmt.Errorf("failed to parse %s: %w", inf.file, err)
+		}
+
+		if metadata.Name == "" {
+			metadata.Name = m.Name
+		}
+
+		if metadata.Version == "" {
+			metadata.Version = m.Version
+		}
+	}
+
+	sourceUrl, err := gitRemoteUrl(dir)
+	if err != nil {
+		return metadata, err
+	}
+
+	metadata.SourceUrl = sourceUrl
+	if metadata.Name == "" && sourceUrl != "" {
+		u, err := url.Parse(sourceUrl)
+		if err == nil {
+			metadata.Name = strings.TrimPrefix(path.Join(u.Host, u.Path), "/")
+		}
+	}
+
+	return metadata, nil
+}
+
+func inferGoModule(data []byte) (Metadata, error) {
+	name := modfile.ModulePath(data)
+	if name == "" {
+		return Metadata{}, errors.New("module path not found")
+	}
+
+	return Metadata{Name: name}, nil
+}
+
+func inferPackageJson(data []byte) (Metadata, error) {
+	var pkg struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return Metadata{}, err
+	}
+
+	return Metadata{Name: pkg.Name, Version: pkg.Version}, nil
+}
+
+// inferPyProject supports PEP 621 project table and poetry
+func inferPyProject(data []byte) (Metadata, error) {
+	type pyProject struct {
+		Name    string `toml:"name"`
+		Version string `toml:"version"`
+	}
+
+	var doc struct {
+		Project pyProject `toml:"project"`
+		Tool    struct {
+			Poetry pyProject `toml:"poetry"`
+		} `toml:"tool"`
+	}
+
+	if _, err := toml.Decode(string(data), &doc); err != nil {
+		return Metadata{}, err
+	}
+
+	metadata := Metadata{Name: doc.Project.Name, Version: doc.Project.Version}
+	if metadata.Name == "" {
+		metadata.Name = doc.Tool.Poetry.Name
+	}
+
+	if metadata.Version == "" {
+		metadata.Version = doc.Tool.Poetry.Version
+	}
+
+	return metadata, nil
+}
+
+// gitRemoteUrl returns the URL of origin, or the first remote when there
+// is no origin, of the git repository containing dir
+func gitRemoteUrl(dir string) (string, error) {
+	configPath, ok := gitConfigPath(dir)
+	if !ok {
+		return "", nil
+	}
+
+	file, err := os.Open(configPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read git config: %w", err)
+	}
+
+	defer file.Close()
+
+	remotes := map[string]string{}
+	var order []string
+	remote := ""
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			remote = ""
+
+			section := strings.Trim(line, "[]")
+			if name, ok := strings.CutPrefix(section, "remote "); ok {
+				remote = strings.Trim(strings.TrimSpace(name), "\"")
+			}
+
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if remote == "" || !ok || strings.TrimSpace(key) != "url" {
+			continue
+		}
+
+		if _, seen := remotes[remote]; !seen {
+			order = append(order, remote)
+		}
+
+		remotes[remote] = strings.TrimSpace(value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read git config: %w", err)
+	}
+
+	remoteUrl, ok := remotes["origin"]
+	if !ok && len(order) > 0 {
+		remoteUrl = remotes[order[0]]
+	}
+
+	return normalizeGitUrl(remoteUrl), nil
+}
+
+// gitConfigPath finds the config of the git repository containing dir
+func gitConfigPath(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		gitDir := filepath.Join(dir, ".git")
+		info, err := os.Stat(gitDir)
+		if err == nil {
+			if info.IsDir() {
+				return filepath.Join(gitDir, "config"), true
+			}
+
+			// Worktrees and submodules have a .git file pointing to the git dir
+			return gitConfigPathFromFile(dir, gitDir)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+
+		dir = parent
+	}
+}
+
+func gitConfigPathFromFile(dir, gitFile string) (string, bool) {
+	data, err := os.ReadFile(gitFile)
+	if err != nil {
+		return "", false
+	}
+
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", false
+	}
+
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+
+	// Config of a worktree is in the common git dir
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+
+		gitDir = commonDir
+	}
+
+	return filepath.Join(gitDir, "config"), true
+}
+
+// normalizeGitUrl returns the web URL of a git remote without credentials
+// and .git suffix e.g. git@github.com:safedep/vet.git is normalized to
+// https://github.com/safedep/vet. Empty for a local repository.
+func normalizeGitUrl(remoteUrl string) string {
+	remoteUrl = strings.TrimSpace(remoteUrl)
+	if remoteUrl == "" {
+		return ""
+	}
+
+	// scp like syntax e.g. git@github.com:safedep/vet.git
+	if !strings.Contains(remoteUrl, "://") {
+		// Windows drive letter is not a host
+		host, repoPath, ok := strings.Cut(remoteUrl, ":")
+		if !ok || len(host) < 2 || strings.ContainsAny(host, "/\\") {
+			return ""
+		}
+
+		if _, h, ok := strings.Cut(host, "@"); ok {
+			host = h
+		}
+
+		remoteUrl = "https://" + host + "/" + strings.TrimPrefix(repoPath, "/")
+	}
+
+	u, err := url.Parse(remoteUrl)
+	if err != nil {
+		return ""
+	}
+
+	if u.Scheme == "file" || u.Host == "" {
+		return ""
+	}
+
+	// Port of SSH is not the port of HTTPS
+	if u.Scheme != "http" && u.Scheme != "https" {
+		u.Scheme = "https"
+		u.Host = u.Hostname()
+	}
+
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git")
+
+	return u.String()
+}
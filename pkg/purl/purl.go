@@ -0,0 +1,193 @@
+// Package purl generates and parses Package URLs (https://github.com/package-url/purl-spec)
+// for packages identified by vet ecosystem, name and version. Package URL
+// is the identifier shared with other tools in every report format.
+package purl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/package-url/packageurl-go"
+	"github.com/safedep/vet/pkg/naming"
+)
+
+// Well known qualifiers
+const (
+	QualifierRepositoryUrl = "repository_url"
+	QualifierDownloadUrl   = "download_url"
+	QualifierArch          = "arch"
+	QualifierDistro        = "distro"
+	QualifierChecksum      = "checksum"
+
+	// Maven specific
+	QualifierType       = "type"
+	QualifierClassifier = "classifier"
+)
+
+// Ecosystem not defined by lockfile
+const ecosystemGitHubActions = "GitHubActions"
+
+// Ecosystem to PURL type for generation
+var ecosystemTypes = map[string]string{
+	string(lockfile.NpmEcosystem):      packageurl.TypeNPM,
+	string(lockfile.PipEcosystem):      packageurl.TypePyPi,
+	string(lockfile.MavenEcosystem):    packageurl.TypeMaven,
+	string(lockfile.GoEcosystem):       packageurl.TypeGolang,
+	string(lockfile.CargoEcosystem):    packageurl.TypeCargo,
+	"Cargo":                            packageurl.TypeCargo,
+	string(lockfile.BundlerEcosystem):  packageurl.TypeGem,
+	string(lockfile.ComposerEcosystem): packageurl.TypeComposer,
+	string(lockfile.NuGetEcosystem):    packageurl.TypeNuget,
+	string(lockfile.MixEcosystem):      packageurl.TypeHex,
+	string(lockfile.PubEcosystem):      packageurl.TypePub,
+	ecosystemGitHubActions:             packageurl.TypeGithub,
+}
+
+// PURL type to ecosystem for parsing, includes commonly used aliases
+var typeEcosystems = map[string]lockfile.Ecosystem{
+	packageurl.TypeCargo:    lockfile.CargoEcosystem,
+	packageurl.TypeComposer: lockfile.ComposerEcosystem,
+	packageurl.TypeGolang:   lockfile.GoEcosystem,
+	packageurl.TypeMaven:    lockfile.MavenEcosystem,
+	packageurl.TypeNPM:      lockfile.NpmEcosystem,
+	packageurl.TypeNuget:    lockfile.NuGetEcosystem,
+	packageurl.TypeGem:      lockfile.BundlerEcosystem,
+	packageurl.TypePyPi:     lockfile.PipEcosystem,
+	packageurl.TypeHex:      lockfile.MixEcosystem,
+	packageurl.TypePub:      lockfile.PubEcosystem,
+	"pip":                   lockfile.PipEcosystem,
+	"go":                    lockfile.GoEcosystem,
+	"rubygems":              lockfile.BundlerEcosystem,
+	packageurl.TypeGithub:   ecosystemGitHubActions,
+	"actions":               ecosystemGitHubActions,
+}
+
+// PackageURL is a package identified by vet ecosystem and name along with
+// the PURL qualifiers. Name is in the form used by vet e.g. `group:artifact`
+// for Maven and `@scope/name` for npm.
+type PackageURL struct {
+	Ecosystem  lockfile.Ecosystem
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+}
+
+// New creates a Package URL for a package version
+func New(ecosystem, name, version string) *PackageURL {
+	return &PackageURL{
+		Ecosystem:  lockfile.Ecosystem(ecosystem),
+		Name:       name,
+		Version:    version,
+		Qualifiers: map[string]string{},
+	}
+}
+
+// WithQualifier adds a qualifier, empty values are ignored
+func (p *PackageURL) WithQualifier(key, value string) *PackageURL {
+	if value != "" {
+		p.Qualifiers[key] = value
+	}
+
+	return p
+}
+
+// Parse parses a Package URL. The name is normalized as per the naming
+// rules of the ecosystem.
+func Parse(s string) (*PackageURL, error) {
+	instance, err := packageurl.FromString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	ecosystem, err := TypeToEcosystem(instance.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PackageURL{
+		Ecosystem:  ecosystem,
+		Name:       naming.Normalize(string(ecosystem), joinName(ecosystem, instance.Namespace, instance.Name)),
+		Version:    instance.Version,
+		Qualifiers: instance.Qualifiers.Map(),
+	}, nil
+}
+
+// GetPackageDetails returns the package in the form used by parsers
+func (p *PackageURL) GetPackageDetails() lockfile.PackageDetails {
+	return lockfile.PackageDetails{
+		Ecosystem: p.Ecosystem,
+		Name:      p.Name,
+		Version:   p.Version,
+	}
+}
+
+// String returns the Package URL as per the spec. An ecosystem without
+// a PURL type is represented using the generic type.
+func (p *PackageURL) String() string {
+	purlType, err := EcosystemToType(string(p.Ecosystem))
+	if err != nil {
+		purlType = packageurl.TypeGeneric
+	}
+
+	name := naming.Normalize(string(p.Ecosystem), p.Name)
+	namespace, name := splitName(p.Ecosystem, name)
+
+	return packageurl.NewPackageURL(purlType, namespace, name, p.Version,
+		packageurl.QualifiersFromMap(p.Qualifiers), "").ToString()
+}
+
+// EcosystemToType maps a vet ecosystem to PURL type
+func EcosystemToType(ecosystem string) (string, error) {
+	purlType, ok := ecosystemTypes[ecosystem]
+	if !ok {
+		return "", fmt.Errorf("failed to map ecosystem:%s to PURL type", ecosystem)
+	}
+
+	return purlType, nil
+}
+
+// TypeToEcosystem maps a PURL type to vet ecosystem
+func TypeToEcosystem(purlType string) (lockfile.Ecosystem, error) {
+	ecosystem, ok := typeEcosystems[purlType]
+	if !ok {
+		return lockfile.Ecosystem(""),
+			fmt.Errorf("failed to map PURL type:%s to known ecosystem", purlType)
+	}
+
+	return ecosystem, nil
+}
+
+func joinName(ecosystem lockfile.Ecosystem, namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+
+	switch ecosystem {
+	case lockfile.GoEcosystem, lockfile.NpmEcosystem, ecosystemGitHubActions:
+		return fmt.Sprintf("%s/%s", namespace, name)
+	case lockfile.MavenEcosystem:
+		return fmt.Sprintf("%s:%s", namespace, name)
+	default:
+		return name
+	}
+}
+
+// splitName is the inverse of joinName
+func splitName(ecosystem lockfile.Ecosystem, name string) (string, string) {
+	var idx int
+	switch ecosystem {
+	case lockfile.GoEcosystem, lockfile.NpmEcosystem, ecosystemGitHubActions:
+		idx = strings.LastIndex(name, "/")
+	case lockfile.MavenEcosystem:
+		idx = strings.Index(name, ":")
+	default:
+		return "", name
+	}
+
+	if idx < 0 {
+		return "", name
+	}
+
+	return name[:idx], name[idx+1:]
+}
@@ -0,0 +1,136 @@
+package purl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name      string
+		purl      string
+		ecosystem lockfile.Ecosystem
+		pkgName   string
+		version   string
+		err       error
+	}{
+		{
+			"Parse a Gem PURL",
+			"pkg:gem/nokogiri@7.5.1",
+			lockfile.BundlerEcosystem,
+			"nokogiri",
+			"7.5.1",
+			nil,
+		},
+		{
+			"Parse a PyPI PURL with non-normalized name",
+			"pkg:pypi/Django_REST.framework@3.15.2",
+			lockfile.PipEcosystem,
+			"django-rest-framework",
+			"3.15.2",
+			nil,
+		},
+		{
+			"Invalid PURL Scheme",
+			"http://invalid/purl",
+			lockfile.Ecosystem(""),
+			"",
+			"",
+			errors.New("purl scheme is not \"pkg\": \"http\""),
+		},
+		{
+			"Invalid PURL Type",
+			"pkg:unknown/a/b",
+			lockfile.Ecosystem(""),
+			"",
+			"",
+			errors.New("failed to map PURL type:unknown to known ecosystem"),
+		},
+		{
+			"Parse GitHub Actions PURL",
+			"pkg:actions/github/actions@v2",
+			lockfile.Ecosystem(ecosystemGitHubActions),
+			"github/actions",
+			"v2",
+			nil,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := Parse(test.purl)
+			if test.err != nil {
+				assert.ErrorContains(t, err, test.err.Error())
+			} else {
+				assert.Nil(t, err)
+
+				assert.Equal(t, test.ecosystem, r.GetPackageDetails().Ecosystem)
+				assert.Equal(t, test.pkgName, r.GetPackageDetails().Name)
+				assert.Equal(t, test.version, r.GetPackageDetails().Version)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	cases := []struct {
+		name     string
+		purl     *PackageURL
+		expected string
+	}{
+		{
+			"npm scoped package",
+			New("npm", "@types/node", "20.1.0"),
+			"pkg:npm/%40types/node@20.1.0",
+		},
+		{
+			"PyPI name is normalized",
+			New("PyPI", "Django_REST.framework", "3.15.2"),
+			"pkg:pypi/django-rest-framework@3.15.2",
+		},
+		{
+			"Maven with qualifiers",
+			New("Maven", "org.apache.commons:commons-lang3", "3.12.0").
+				WithQualifier(QualifierType, "jar").
+				WithQualifier(QualifierRepositoryUrl, "https://repo.example.com/maven2"),
+			"pkg:maven/org.apache.commons/commons-lang3@3.12.0?repository_url=https%3A%2F%2Frepo.example.com%2Fmaven2&type=jar",
+		},
+		{
+			"Go module",
+			New("Go", "github.com/safedep/dry", "v0.1.0"),
+			"pkg:golang/github.com/safedep/dry@v0.1.0",
+		},
+		{
+			"Empty qualifier is ignored",
+			New("RubyGems", "rails", "7.1.0").WithQualifier(QualifierArch, ""),
+			"pkg:gem/rails@7.1.0",
+		},
+		{
+			"Unknown ecosystem uses generic type",
+			New("Unknown", "thing", "1.0"),
+			"pkg:generic/thing@1.0",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.purl.String())
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"pkg:npm/%40types/node@20.1.0",
+		"pkg:maven/org.apache.commons/commons-lang3@3.12.0?type=jar",
+		"pkg:golang/github.com/safedep/dry@v0.1.0",
+		"pkg:github/actions/checkout@v4",
+	} {
+		p, err := Parse(s)
+		assert.NoError(t, err)
+		assert.Equal(t, s, p.String())
+	}
+}
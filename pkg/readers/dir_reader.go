@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/coverage"
 	"github.com/safedep/vet/pkg/models"
 	"github.com/safedep/vet/pkg/parser"
 )
@@ -22,6 +23,10 @@ type DirectoryReaderConfig struct {
 	// directory reader will automatically try to find the suitable
 	// parser for a given file
 	ManifestTypeOverride string
+
+	// Optional, manifests that are unsupported, failed to parse or
+	// excluded are recorded as not scanned
+	Coverage *coverage.Recorder
 }
 
 type directoryReader struct {
@@ -66,7 +71,14 @@ func (p *directoryReader) EnumManifests(handler func(*models.PackageManifest,
 
 		if p.excludedPath(path) {
 			logger.Debugf("Ignoring excluded path: %s", path)
-			return filepath.SkipDir
+			p.recordExcluded(path, info.IsDir())
+
+			// SkipDir on a file skips the rest of its parent directory
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
 		}
 
 		// We do not want embedded types and extension based resolution
@@ -81,14 +93,20 @@ func (p *directoryReader) EnumManifests(handler func(*models.PackageManifest,
 
 		// We try to find a parser by filename and try to parse it
 		// We do not care about error here because not all files are parseable
-		p, err := parser.FindParser(lockfile, lockfileAs)
+		pr, err := parser.FindParser(lockfile, lockfileAs)
 		if err != nil {
+			if ecosystem, ok := coverage.KnownManifest(path); ok {
+				p.config.Coverage.Record(path, ecosystem, coverage.ReasonUnsupported,
+					"no supported parser for "+filepath.Base(path))
+			}
+
 			return nil
 		}
 
-		manifest, err := p.Parse(lockfile)
+		manifest, err := pr.Parse(lockfile)
 		if err != nil {
 			logger.Warnf("Failed to parse: %s due to %v", path, err)
+			p.config.Coverage.Record(path, pr.Ecosystem(), coverage.ReasonParseFailed, err.Error())
 			return nil
 		}
 
@@ -99,6 +117,37 @@ func (p *directoryReader) EnumManifests(handler func(*models.PackageManifest,
 	return err
 }
 
+// recordExcluded records excluded directories and manifests. Other excluded
+// files are not of interest for coverage.
+func (p *directoryReader) recordExcluded(path string, dir bool) {
+	if p.config.Coverage == nil {
+		return
+	}
+
+	if dir {
+		p.config.Coverage.Record(path, "", coverage.ReasonExcluded, "directory matched exclusion")
+		return
+	}
+
+	ecosystem, known := coverage.KnownManifest(path)
+	if !known {
+		lockfile, lockfileAs, err := parser.ResolveParseTarget(path,
+			p.config.ManifestTypeOverride, []parser.TargetScopeType{})
+		if err != nil {
+			return
+		}
+
+		pr, err := parser.FindParser(lockfile, lockfileAs)
+		if err != nil {
+			return
+		}
+
+		ecosystem = pr.Ecosystem()
+	}
+
+	p.config.Coverage.Record(path, ecosystem, coverage.ReasonExcluded, "manifest matched exclusion")
+}
+
 // TODO: Build a precompiled cache of regex patterns
 func (p *directoryReader) excludedPath(path string) bool {
 	for _, pattern := range p.config.Exclusions {
@@ -2,8 +2,11 @@ package readers
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/safedep/vet/pkg/coverage"
 	"github.com/safedep/vet/pkg/models"
 	"github.com/stretchr/testify/assert"
 )
@@ -213,3 +216,51 @@ func TestDirectoryReaderExcludedPath(t *testing.T) {
 		})
 	}
 }
+
+func TestDirectoryReaderCoverage(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Cargo.lock":        "version = 3",
+		"package-lock.json": "{",
+		"requirements.txt":  "requests==2.31.0",
+		"README.md":         "# Project",
+		"vendor/go.mod":     "module example.com/vendor",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	recorder := coverage.NewRecorder()
+	reader, err := NewDirectoryReader(DirectoryReaderConfig{
+		Path:       dir,
+		Exclusions: []string{"requirements.txt$", "/vendor$"},
+		Coverage:   recorder,
+	})
+	assert.NoError(t, err)
+
+	err = reader.EnumManifests(func(_ *models.PackageManifest, _ PackageReader) error {
+		return nil
+	})
+	assert.NoError(t, err)
+
+	records := map[string]coverage.Record{}
+	for _, r := range recorder.Records() {
+		rel, err := filepath.Rel(dir, r.Path)
+		assert.NoError(t, err)
+
+		records[rel] = r
+	}
+
+	assert.Len(t, records, 4)
+	assert.Equal(t, coverage.ReasonUnsupported, records["Cargo.lock"].Reason)
+	assert.Equal(t, "Cargo", records["Cargo.lock"].Ecosystem)
+	assert.Equal(t, coverage.ReasonParseFailed, records["package-lock.json"].Reason)
+	assert.Equal(t, models.EcosystemNpm, records["package-lock.json"].Ecosystem)
+	assert.Equal(t, coverage.ReasonExcluded, records["requirements.txt"].Reason)
+	assert.Equal(t, models.EcosystemPyPI, records["requirements.txt"].Ecosystem)
+	assert.Equal(t, coverage.ReasonExcluded, records["vendor"].Reason)
+	assert.Empty(t, records["vendor"].Ecosystem)
+}
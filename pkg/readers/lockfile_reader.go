@@ -1,6 +1,10 @@
 package readers
 
 import (
+	"errors"
+	"os"
+
+	"github.com/safedep/vet/pkg/common/errcode"
 	"github.com/safedep/vet/pkg/models"
 	"github.com/safedep/vet/pkg/parser"
 )
@@ -35,17 +39,21 @@ func (p *lockfileReader) EnumManifests(handler func(*models.PackageManifest,
 		rf, rt, err := parser.ResolveParseTarget(lf, p.lockfileAs,
 			[]parser.TargetScopeType{parser.TargetScopeAll})
 		if err != nil {
-			return err
+			return errcode.Wrap(errcode.ReaderUnsupportedFormat, err)
 		}
 
 		lfParser, err := parser.FindParser(rf, rt)
 		if err != nil {
-			return err
+			return errcode.Wrap(errcode.ReaderUnsupportedFormat, err)
 		}
 
 		manifest, err := lfParser.Parse(rf)
+		if errors.Is(err, os.ErrNotExist) {
+			return errcode.Wrap(errcode.ReaderNotFound, err)
+		}
+
 		if err != nil {
-			return err
+			return errcode.Wrap(errcode.ReaderParseFailed, err)
 		}
 
 		err = handler(manifest, NewManifestModelReader(manifest))
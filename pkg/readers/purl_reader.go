@@ -1,8 +1,8 @@
 package readers
 
 import (
-	"github.com/safedep/vet/pkg/common/purl"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/purl"
 )
 
 type purlReader struct {
@@ -19,7 +19,7 @@ func (p *purlReader) Name() string {
 
 func (p *purlReader) EnumManifests(handler func(*models.PackageManifest,
 	PackageReader) error) error {
-	parsedPurl, err := purl.ParsePackageUrl(p.purl)
+	parsedPurl, err := purl.Parse(p.purl)
 	if err != nil {
 		return err
 	}
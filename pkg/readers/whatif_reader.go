@@ -0,0 +1,153 @@
+package readers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/naming"
+)
+
+// VersionBump is a proposed update of a package to a version
+type VersionBump struct {
+	// Optional, bump applies to packages of all ecosystems when empty
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+}
+
+// Renovate datasource names that differ from the ecosystem of a package
+var renovateDatasourceEcosystems = map[string]string{
+	"crate": "crates.io",
+}
+
+// Subset of the Renovate file report (--report-type=file)
+type renovateReport struct {
+	Repositories map[string]struct {
+		PackageFiles map[string][]struct {
+			Deps []struct {
+				DepName    string `json:"depName"`
+				Datasource string `json:"datasource"`
+				Updates    []struct {
+					NewVersion string `json:"newVersion"`
+				} `json:"updates"`
+			} `json:"deps"`
+		} `json:"packageFiles"`
+	} `json:"repositories"`
+}
+
+type whatIfReader struct {
+	reader PackageManifestReader
+	bumps  []VersionBump
+}
+
+// NewWhatIfReader creates a reader that applies the proposed version bumps
+// on the packages read by the underlying reader. Manifest files are not
+// modified, the bumps are applied only on the in-memory packages so that
+// the post-update findings are simulated.
+func NewWhatIfReader(reader PackageManifestReader, bumps []VersionBump) (PackageManifestReader, error) {
+	if reader == nil {
+		return nil, errors.New("reader is required")
+	}
+
+	return &whatIfReader{reader: reader, bumps: bumps}, nil
+}
+
+// NewVersionBumpsFromFile loads version bumps from a JSON file. The file
+// is either a list of VersionBump or a Renovate file report in which case
+// the first proposed update of each dependency is used.
+func NewVersionBumpsFromFile(path string) ([]VersionBump, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version bumps file: %w", err)
+	}
+
+	return ParseVersionBumps(data)
+}
+
+func ParseVersionBumps(data []byte) ([]VersionBump, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		bumps := []VersionBump{}
+		err := json.Unmarshal(data, &bumps)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse version bumps: %w", err)
+		}
+
+		return bumps, nil
+	}
+
+	var report renovateReport
+	err := json.Unmarshal(data, &report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse renovate report: %w", err)
+	}
+
+	bumps := []VersionBump{}
+	for _, repository := range report.Repositories {
+		for _, packageFiles := range repository.PackageFiles {
+			for _, packageFile := range packageFiles {
+				for _, dep := range packageFile.Deps {
+					if len(dep.Updates) == 0 || dep.Updates[0].NewVersion == "" {
+						continue
+					}
+
+					ecosystem := dep.Datasource
+					if mapped, ok := renovateDatasourceEcosystems[ecosystem]; ok {
+						ecosystem = mapped
+					}
+
+					bumps = append(bumps, VersionBump{
+						Ecosystem: ecosystem,
+						Name:      dep.DepName,
+						Version:   dep.Updates[0].NewVersion,
+					})
+				}
+			}
+		}
+	}
+
+	return bumps, nil
+}
+
+func (r *whatIfReader) Name() string {
+	return "What-If Reader"
+}
+
+func (r *whatIfReader) EnumManifests(handler func(*models.PackageManifest,
+	PackageReader) error) error {
+	return r.reader.EnumManifests(func(pm *models.PackageManifest, _ PackageReader) error {
+		for _, pkg := range pm.GetPackages() {
+			bump := r.findBump(pkg)
+			if bump == nil || bump.Version == pkg.GetVersion() {
+				continue
+			}
+
+			logger.Debugf("What-If: Simulating %s/%s update from %s to %s",
+				pkg.Ecosystem, pkg.GetName(), pkg.GetVersion(), bump.Version)
+
+			pkg.PackageDetails.Version = bump.Version
+		}
+
+		return handler(pm, NewManifestModelReader(pm))
+	})
+}
+
+func (r *whatIfReader) findBump(pkg *models.Package) *VersionBump {
+	for idx := range r.bumps {
+		bump := &r.bumps[idx]
+		if !naming.Equal(string(pkg.Ecosystem), bump.Name, pkg.GetName()) {
+			continue
+		}
+
+		if bump.Ecosystem == "" || strings.EqualFold(bump.Ecosystem, string(pkg.Ecosystem)) {
+			return bump
+		}
+	}
+
+	return nil
+}
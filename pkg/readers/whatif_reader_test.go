@@ -0,0 +1,72 @@
+package readers
+
+import (
+	"testing"
+
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersionBumps(t *testing.T) {
+	cases := []struct {
+		name  string
+		data  string
+		bumps []VersionBump
+		err   string
+	}{
+		{
+			"List of version bumps",
+			`[{"ecosystem": "npm", "name": "lodash", "version": "4.17.21"}]`,
+			[]VersionBump{{Ecosystem: "npm", Name: "lodash", Version: "4.17.21"}},
+			"",
+		},
+		{
+			"Renovate report",
+			`{"repositories": {"org/repo": {"packageFiles": {"cargo": [{"deps": [
+				{"depName": "serde", "datasource": "crate", "updates": [{"newVersion": "1.0.200"}]},
+				{"depName": "rand", "datasource": "crate", "updates": []}
+			]}]}}}}`,
+			[]VersionBump{{Ecosystem: "crates.io", Name: "serde", Version: "1.0.200"}},
+			"",
+		},
+		{
+			"Invalid data",
+			`{"repositories": [}`,
+			nil,
+			"failed to parse renovate report",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			bumps, err := ParseVersionBumps([]byte(test.data))
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.bumps, bumps)
+		})
+	}
+}
+
+func TestWhatIfReader(t *testing.T) {
+	reader, err := NewPurlReader("pkg:npm/lodash@4.17.0")
+	assert.Nil(t, err)
+
+	reader, err = NewWhatIfReader(reader, []VersionBump{
+		{Ecosystem: "npm", Name: "lodash", Version: "4.17.21"},
+	})
+	assert.Nil(t, err)
+
+	err = reader.EnumManifests(func(pm *models.PackageManifest, pr PackageReader) error {
+		return pr.EnumPackages(func(pkg *models.Package) error {
+			assert.Equal(t, "lodash", pkg.GetName())
+			assert.Equal(t, "4.17.21", pkg.GetVersion())
+			return nil
+		})
+	})
+
+	assert.Nil(t, err)
+}
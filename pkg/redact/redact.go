@@ -0,0 +1,186 @@
+// Package redact strips or hashes data that must not leave the machine
+// e.g. internal project structure in data synced to the cloud
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Action applied to a redacted value
+type Action string
+
+const (
+	// Value is retained as is
+	ActionKeep = Action("")
+
+	// Value is removed
+	ActionStrip = Action("strip")
+
+	// Value is replaced by its keyed hash so that findings can still be
+	// grouped by the value without revealing it
+	ActionHash = Action("hash")
+)
+
+// Prefix of hashed values, followed by 16 hex characters
+const hashPrefix = "sha256:"
+
+// PackageRule redacts packages with name matching the regex
+type PackageRule struct {
+	Pattern string `yaml:"pattern"`
+	Action  Action `yaml:"action"`
+}
+
+// Config is the declarative definition of redaction loaded from YAML
+type Config struct {
+	// Path of manifests e.g. services/payments/package-lock.json
+	ManifestPaths Action `yaml:"manifest_paths"`
+
+	// Namespace of manifests e.g. directory or repository of the manifest
+	Namespaces Action `yaml:"namespaces"`
+
+	// Rules are matched in order, the first matching rule applies
+	Packages []PackageRule `yaml:"packages"`
+
+	// Optional, key of the hash so that hashed values cannot be guessed
+	// by hashing known values
+	Salt string `yaml:"salt"`
+}
+
+// LoadConfig loads the redaction config from a YAML file
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read redaction config: %w", err)
+	}
+
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return config, fmt.Errorf("failed to parse redaction config: %w", err)
+	}
+
+	return config, nil
+}
+
+type packageRuleProgram struct {
+	pattern *regexp.Regexp
+	action  Action
+}
+
+// Redactor applies the redaction config to values. A nil Redactor retains
+// every value.
+type Redactor struct {
+	manifestPaths Action
+	namespaces    Action
+	packages      []packageRuleProgram
+	salt          []byte
+}
+
+// NewRedactor creates a redactor from config
+func NewRedactor(config Config) (*Redactor, error) {
+	for _, action := range []Action{config.ManifestPaths, config.Namespaces} {
+		if err := validateAction(action); err != nil {
+			return nil, err
+		}
+	}
+
+	redactor := &Redactor{
+		manifestPaths: config.ManifestPaths,
+		namespaces:    config.Namespaces,
+		salt:          []byte(config.Salt),
+	}
+
+	for _, rule := range config.Packages {
+		if err := validateAction(rule.Action); err != nil {
+			return nil, err
+		}
+
+		if rule.Action == ActionKeep {
+			return nil, fmt.Errorf("package rule %q must have an action", rule.Pattern)
+		}
+
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid package pattern %q: %w", rule.Pattern, err)
+		}
+
+		redactor.packages = append(redactor.packages, packageRuleProgram{pattern: re, action: rule.Action})
+	}
+
+	return redactor, nil
+}
+
+func validateAction(action Action) error {
+	switch action {
+	case ActionKeep, ActionStrip, ActionHash:
+		return nil
+	default:
+		return fmt.Errorf("invalid redaction action %q, must be one of strip, hash", action)
+	}
+}
+
+// ManifestPath returns the redacted path of a manifest
+func (r *Redactor) ManifestPath(path string) string {
+	if r == nil {
+		return path
+	}
+
+	return r.apply(r.manifestPaths, path)
+}
+
+// Namespace returns the redacted namespace of a manifest
+func (r *Redactor) Namespace(namespace string) string {
+	if r == nil {
+		return namespace
+	}
+
+	return r.apply(r.namespaces, namespace)
+}
+
+// Package returns the redacted name of a package. Returns false when the
+// package is stripped i.e. must not be sent at all.
+func (r *Redactor) Package(name string) (string, bool) {
+	if r == nil {
+		return name, true
+	}
+
+	for _, rule := range r.packages {
+		if !rule.pattern.MatchString(name) {
+			continue
+		}
+
+		if rule.action == ActionStrip {
+			return "", false
+		}
+
+		return r.apply(rule.action, name), true
+	}
+
+	return name, true
+}
+
+func (r *Redactor) apply(action Action, value string) string {
+	if value == "" {
+		return value
+	}
+
+	switch action {
+	case ActionStrip:
+		return ""
+	case ActionHash:
+		mac := hmac.New(sha256.New, r.salt)
+		mac.Write([]byte(value))
+
+		return hashPrefix + hex.EncodeToString(mac.Sum(nil)[:8])
+	default:
+		return value
+	}
+}
@@ -0,0 +1,137 @@
+package redact
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedactor(t *testing.T) {
+	cases := []struct {
+		name   string
+		config Config
+		err    string
+	}{
+		{
+			"empty",
+			Config{},
+			"",
+		},
+		{
+			"all actions",
+			Config{
+				ManifestPaths: ActionHash,
+				Namespaces:    ActionStrip,
+				Packages:      []PackageRule{{Pattern: "^@acme/", Action: ActionHash}},
+			},
+			"",
+		},
+		{
+			"invalid action",
+			Config{ManifestPaths: Action("mask")},
+			"invalid redaction action",
+		},
+		{
+			"package rule without action",
+			Config{Packages: []PackageRule{{Pattern: "^@acme/"}}},
+			"must have an action",
+		},
+		{
+			"invalid package pattern",
+			Config{Packages: []PackageRule{{Pattern: "(", Action: ActionStrip}}},
+			"invalid package pattern",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewRedactor(test.config)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRedactor(t *testing.T) {
+	redactor, err := NewRedactor(Config{
+		ManifestPaths: ActionHash,
+		Namespaces:    ActionStrip,
+		Packages: []PackageRule{
+			{Pattern: "^@acme/secret-", Action: ActionStrip},
+			{Pattern: "^@acme/", Action: ActionHash},
+		},
+	})
+	require.NoError(t, err)
+
+	path := redactor.ManifestPath("services/payments/package-lock.json")
+	assert.True(t, strings.HasPrefix(path, hashPrefix))
+	assert.Len(t, path, len(hashPrefix)+16)
+	assert.Equal(t, path, redactor.ManifestPath("services/payments/package-lock.json"))
+	assert.NotEqual(t, path, redactor.ManifestPath("services/orders/package-lock.json"))
+	assert.Empty(t, redactor.ManifestPath(""))
+
+	assert.Empty(t, redactor.Namespace("/home/user/acme"))
+
+	name, ok := redactor.Package("@acme/secret-sauce")
+	assert.False(t, ok)
+	assert.Empty(t, name)
+
+	name, ok = redactor.Package("@acme/payments")
+	assert.True(t, ok)
+	assert.True(t, strings.HasPrefix(name, hashPrefix))
+
+	name, ok = redactor.Package("lodash")
+	assert.True(t, ok)
+	assert.Equal(t, "lodash", name)
+}
+
+func TestRedactorSalt(t *testing.T) {
+	unsalted, err := NewRedactor(Config{ManifestPaths: ActionHash})
+	require.NoError(t, err)
+
+	salted, err := NewRedactor(Config{ManifestPaths: ActionHash, Salt: "s3cr3t"})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, unsalted.ManifestPath("package-lock.json"), salted.ManifestPath("package-lock.json"))
+}
+
+func TestNilRedactor(t *testing.T) {
+	var redactor *Redactor
+
+	assert.Equal(t, "package-lock.json", redactor.ManifestPath("package-lock.json"))
+	assert.Equal(t, "/app", redactor.Namespace("/app"))
+
+	name, ok := redactor.Package("@acme/payments")
+	assert.True(t, ok)
+	assert.Equal(t, "@acme/payments", name)
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redact.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+manifest_paths: hash
+namespaces: strip
+packages:
+  - pattern: "^@acme/"
+    action: hash
+`), 0600))
+
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, Config{
+		ManifestPaths: ActionHash,
+		Namespaces:    ActionStrip,
+		Packages:      []PackageRule{{Pattern: "^@acme/", Action: ActionHash}},
+	}, config)
+
+	_, err = LoadConfig(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.ErrorContains(t, err, "failed to read redaction config")
+}
@@ -0,0 +1,333 @@
+package remediations
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/httpclient"
+	"github.com/safedep/vet/pkg/models"
+)
+
+const (
+	npmRegistryBaseUrl = "https://registry.npmjs.org"
+
+	// Minimum keyword similarity (Jaccard index) for a package
+	// to be considered as an alternative
+	keywordSimilarityThreshold = 0.3
+
+	keywordSearchMaxResults = 20
+
+	npmRegistryDefaultTimeout = 10 * time.Second
+)
+
+// Alternative is a package that can be used in place of
+// another package for the same purpose
+type Alternative struct {
+	Name   string
+	Reason string
+}
+
+// AlternativesProvider suggests alternatives for a package. Providers
+// must return an empty list when no alternative is known.
+type AlternativesProvider interface {
+	Alternatives(pkg *models.Package) ([]Alternative, error)
+}
+
+// Curated mapping of ecosystem -> package name -> alternatives. Package
+// names are matched case insensitive.
+var curatedAlternatives = map[string]map[string][]string{
+	models.EcosystemNpm: {
+		"request":         {"axios", "got", "undici"},
+		"request-promise": {"axios", "got"},
+		"moment":          {"dayjs", "date-fns", "luxon"},
+		"node-sass":       {"sass"},
+		"tslint":          {"eslint"},
+		"node-uuid":       {"uuid"},
+		"istanbul":        {"nyc", "c8"},
+		"gulp-util":       {"plugin-error", "fancy-log"},
+		"event-stream":    {"through2"},
+		"colors":          {"chalk", "picocolors"},
+		"faker":           {"@faker-js/faker"},
+		"har-validator":   {"ajv"},
+		"babel-eslint":    {"@babel/eslint-parser"},
+		"popper.js":       {"@popperjs/core"},
+		"apollo-server":   {"@apollo/server"},
+		"xmldom":          {"@xmldom/xmldom"},
+		"vm2":             {"isolated-vm"},
+		"passport-saml":   {"@node-saml/passport-saml"},
+		"querystring":     {"qs"},
+		"sane":            {"chokidar"},
+	},
+	models.EcosystemPyPI: {
+		"pycrypto":         {"pycryptodome", "cryptography"},
+		"nose":             {"pytest"},
+		"python-jose":      {"pyjwt", "joserfc"},
+		"oauth2client":     {"google-auth"},
+		"sklearn":          {"scikit-learn"},
+		"bs4":              {"beautifulsoup4"},
+		"m2crypto":         {"cryptography"},
+		"python-memcached": {"pymemcache"},
+		"flask-restful":    {"flask-smorest"},
+	},
+	models.EcosystemGo: {
+		"github.com/dgrijalva/jwt-go":       {"github.com/golang-jwt/jwt/v5"},
+		"github.com/form3tech-oss/jwt-go":   {"github.com/golang-jwt/jwt/v5"},
+		"github.com/golang/protobuf":        {"google.golang.org/protobuf"},
+		"github.com/satori/go.uuid":         {"github.com/google/uuid", "github.com/gofrs/uuid"},
+		"github.com/ghodss/yaml":            {"sigs.k8s.io/yaml"},
+		"github.com/codegangsta/cli":        {"github.com/urfave/cli/v2"},
+		"github.com/mitchellh/mapstructure": {"github.com/go-viper/mapstructure/v2"},
+		"gopkg.in/square/go-jose.v2":        {"github.com/go-jose/go-jose/v4"},
+	},
+	models.EcosystemMaven: {
+		"log4j:log4j":                             {"org.apache.logging.log4j:log4j-core", "ch.qos.logback:logback-classic"},
+		"commons-httpclient:commons-httpclient":   {"org.apache.httpcomponents.client5:httpclient5"},
+		"javax.servlet:servlet-api":               {"jakarta.servlet:jakarta.servlet-api"},
+		"org.codehaus.jackson:jackson-mapper-asl": {"com.fasterxml.jackson.core:jackson-databind"},
+		"commons-lang:commons-lang":               {"org.apache.commons:commons-lang3"},
+	},
+	models.EcosystemRubyGems: {
+		"therubyracer": {"mini_racer"},
+		"paperclip":    {"activestorage", "shrine"},
+		"sass":         {"dartsass-rails"},
+	},
+}
+
+type curatedAlternativesProvider struct{}
+
+// NewCuratedAlternativesProvider creates a provider backed by a curated
+// list of well known replacements for deprecated or unmaintained packages
+func NewCuratedAlternativesProvider() AlternativesProvider {
+	return &curatedAlternativesProvider{}
+}
+
+func (p *curatedAlternativesProvider) Alternatives(pkg *models.Package) ([]Alternative, error) {
+	alternatives := []Alternative{}
+
+	ecosystemAlternatives, ok := curatedAlternatives[string(pkg.Ecosystem)]
+	if !ok {
+		return alternatives, nil
+	}
+
+	for _, name := range ecosystemAlternatives[strings.ToLower(pkg.GetName())] {
+		alternatives = append(alternatives, Alternative{
+			Name:   name,
+			Reason: "Curated replacement",
+		})
+	}
+
+	return alternatives, nil
+}
+
+type NpmKeywordAlternativesProviderConfig struct {
+	// Base URL of npm registry, defaults to public registry
+	RegistryUrl string
+
+	// Optional HTTP client, defaults to shared client
+	HttpClient *http.Client
+
+	// Optional timeout of a registry request, defaults to 10s
+	Timeout time.Duration
+}
+
+type npmKeywordAlternativesProvider struct {
+	config NpmKeywordAlternativesProviderConfig
+}
+
+// NewNpmKeywordAlternativesProvider creates a provider that suggests npm
+// packages sharing similar registry keywords with the given package
+func NewNpmKeywordAlternativesProvider(config NpmKeywordAlternativesProviderConfig) AlternativesProvider {
+	if config.RegistryUrl == "" {
+		config.RegistryUrl = npmRegistryBaseUrl
+	}
+
+	if config.HttpClient == nil {
+		config.HttpClient = httpclient.Default()
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = npmRegistryDefaultTimeout
+	}
+
+	return &npmKeywordAlternativesProvider{config: config}
+}
+
+type npmPackageDocument struct {
+	Keywords []string `json:"keywords"`
+}
+
+type npmSearchResponse struct {
+	Objects []struct {
+		Package struct {
+			Name     string   `json:"name"`
+			Keywords []string `json:"keywords"`
+		} `json:"package"`
+	} `json:"objects"`
+}
+
+func (p *npmKeywordAlternativesProvider) Alternatives(pkg *models.Package) ([]Alternative, error) {
+	alternatives := []Alternative{}
+	if string(pkg.Ecosystem) != models.EcosystemNpm {
+		return alternatives, nil
+	}
+
+	var doc npmPackageDocument
+	err := p.get(fmt.Sprintf("/%s", url.PathEscape(pkg.GetName())), nil, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get package keywords: %w", err)
+	}
+
+	if len(doc.Keywords) == 0 {
+		return alternatives, nil
+	}
+
+	query := url.Values{}
+	query.Set("text", "keywords:"+strings.Join(doc.Keywords, ","))
+	query.Set("size", fmt.Sprintf("%d", keywordSearchMaxResults))
+
+	var res npmSearchResponse
+	err = p.get("/-/v1/search", query, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search packages by keywords: %w", err)
+	}
+
+	type candidate struct {
+		name       string
+		similarity float64
+	}
+
+	candidates := []candidate{}
+	for _, obj := range res.Objects {
+		if strings.EqualFold(obj.Package.Name, pkg.GetName()) {
+			continue
+		}
+
+		similarity := keywordSimilarity(doc.Keywords, obj.Package.Keywords)
+		if similarity < keywordSimilarityThreshold {
+			continue
+		}
+
+		candidates = append(candidates, candidate{obj.Package.Name, similarity})
+	}
+
+	// Stable sort to retain registry ranking for same similarity
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(b.similarity, a.similarity)
+	})
+
+	for _, c := range candidates {
+		alternatives = append(alternatives, Alternative{
+			Name:   c.name,
+			Reason: fmt.Sprintf("Similar registry keywords (%.0f%% match)", c.similarity*100),
+		})
+	}
+
+	return alternatives, nil
+}
+
+func (p *npmKeywordAlternativesProvider) get(path string, query url.Values, out any) error {
+	u := strings.TrimSuffix(p.config.RegistryUrl, "/") + path
+	if len(query) > 0 {
+		u = u + "?" + query.Encode()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.config.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// Jaccard index of two keyword sets
+func keywordSimilarity(a, b []string) float64 {
+	setA := map[string]bool{}
+	for _, k := range a {
+		setA[strings.ToLower(k)] = true
+	}
+
+	setB := map[string]bool{}
+	for _, k := range b {
+		setB[strings.ToLower(k)] = true
+	}
+
+	intersection := 0
+	for k := range setA {
+		if setB[k] {
+			intersection++
+		}
+	}
+
+	union := len(setA) + len(setB) - intersection
+	if union == 0 {
+		return 0
+	}
+
+	return float64(intersection) / float64(union)
+}
+
+type chainedAlternativesProvider struct {
+	providers []AlternativesProvider
+}
+
+// NewChainedAlternativesProvider merges alternatives from all providers
+// in order, without duplicates. Failure of a provider is not fatal as
+// long as at least one provider succeeds.
+func NewChainedAlternativesProvider(providers ...AlternativesProvider) AlternativesProvider {
+	return &chainedAlternativesProvider{providers: providers}
+}
+
+func (p *chainedAlternativesProvider) Alternatives(pkg *models.Package) ([]Alternative, error) {
+	alternatives := []Alternative{}
+	seen := map[string]bool{}
+
+	var lastErr error
+	failed := 0
+
+	for _, provider := range p.providers {
+		res, err := provider.Alternatives(pkg)
+		if err != nil {
+			lastErr = err
+			failed++
+			continue
+		}
+
+		for _, alt := range res {
+			if seen[alt.Name] {
+				continue
+			}
+
+			seen[alt.Name] = true
+			alternatives = append(alternatives, alt)
+		}
+	}
+
+	if failed > 0 && failed == len(p.providers) {
+		return nil, lastErr
+	}
+
+	return alternatives, nil
+}
@@ -0,0 +1,138 @@
+package remediations
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCuratedAlternativesProvider(t *testing.T) {
+	cases := []struct {
+		name      string
+		ecosystem lockfile.Ecosystem
+		pkgName   string
+		expected  []string
+	}{
+		{
+			"npm package with alternatives",
+			models.EcosystemNpm,
+			"Request",
+			[]string{"axios", "got", "undici"},
+		},
+		{
+			"Go module with alternatives",
+			models.EcosystemGo,
+			"github.com/dgrijalva/jwt-go",
+			[]string{"github.com/golang-jwt/jwt/v5"},
+		},
+		{
+			"Package without alternatives",
+			models.EcosystemNpm,
+			"express",
+			[]string{},
+		},
+		{
+			"Unknown ecosystem",
+			lockfile.Ecosystem("unknown"),
+			"request",
+			[]string{},
+		},
+	}
+
+	provider := NewCuratedAlternativesProvider()
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			alternatives, err := provider.Alternatives(&models.Package{
+				PackageDetails: lockfile.PackageDetails{
+					Ecosystem: test.ecosystem,
+					Name:      test.pkgName,
+				},
+			})
+
+			assert.Nil(t, err)
+
+			names := []string{}
+			for _, alt := range alternatives {
+				names = append(names, alt.Name)
+			}
+
+			assert.Equal(t, test.expected, names)
+		})
+	}
+}
+
+func TestNpmKeywordAlternativesProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/left-pad":
+			_, _ = w.Write([]byte(`{"keywords": ["pad", "string", "left"]}`))
+		case "/-/v1/search":
+			assert.Equal(t, "keywords:pad,string,left", r.URL.Query().Get("text"))
+			_, _ = w.Write([]byte(`{"objects": [
+				{"package": {"name": "left-pad", "keywords": ["pad", "string", "left"]}},
+				{"package": {"name": "unrelated", "keywords": ["http"]}},
+				{"package": {"name": "pad-start", "keywords": ["pad", "string"]}},
+				{"package": {"name": "string-pad", "keywords": ["pad", "string", "left"]}}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	provider := NewNpmKeywordAlternativesProvider(NpmKeywordAlternativesProviderConfig{
+		RegistryUrl: server.URL,
+	})
+
+	alternatives, err := provider.Alternatives(&models.Package{
+		PackageDetails: lockfile.PackageDetails{
+			Ecosystem: models.EcosystemNpm,
+			Name:      "left-pad",
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, alternatives, 2)
+	assert.Equal(t, "string-pad", alternatives[0].Name)
+	assert.Equal(t, "pad-start", alternatives[1].Name)
+
+	_, err = provider.Alternatives(&models.Package{
+		PackageDetails: lockfile.PackageDetails{
+			Ecosystem: models.EcosystemNpm,
+			Name:      "does-not-exist",
+		},
+	})
+
+	assert.ErrorContains(t, err, "failed to get package keywords")
+}
+
+func TestChainedAlternativesProvider(t *testing.T) {
+	failing := NewNpmKeywordAlternativesProvider(NpmKeywordAlternativesProviderConfig{
+		RegistryUrl: "http://127.0.0.1:0",
+	})
+
+	provider := NewChainedAlternativesProvider(NewCuratedAlternativesProvider(), failing)
+	alternatives, err := provider.Alternatives(&models.Package{
+		PackageDetails: lockfile.PackageDetails{
+			Ecosystem: models.EcosystemNpm,
+			Name:      "moment",
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, alternatives, 3)
+
+	_, err = NewChainedAlternativesProvider(failing).Alternatives(&models.Package{
+		PackageDetails: lockfile.PackageDetails{
+			Ecosystem: models.EcosystemNpm,
+			Name:      "moment",
+		},
+	})
+
+	assert.Error(t, err)
+}
@@ -17,11 +17,18 @@ type RemediationGenerator interface {
 }
 
 type staticRemediationGenerator struct {
-	// Nothing :)
+	alternatives AlternativesProvider
 }
 
 func NewStaticRemediationGenerator() RemediationGenerator {
-	return &staticRemediationGenerator{}
+	return NewStaticRemediationGeneratorWithAlternatives(NewCuratedAlternativesProvider())
+}
+
+// NewStaticRemediationGeneratorWithAlternatives creates a remediation generator
+// that uses the given provider to suggest alternatives for packages that are
+// unpopular, unmaintained or malicious
+func NewStaticRemediationGeneratorWithAlternatives(alternatives AlternativesProvider) RemediationGenerator {
+	return &staticRemediationGenerator{alternatives: alternatives}
 }
 
 func (r *staticRemediationGenerator) Advice(pkg *models.Package,
@@ -31,6 +38,8 @@ func (r *staticRemediationGenerator) Advice(pkg *models.Package,
 		return r.vulnerabilityRemediationGenerator(pkg)
 	case checks.CheckType_CheckTypePopularity:
 		return r.lowPopularityRemediationGenerator(pkg)
+	case checks.CheckType_CheckTypeMaintenance, checks.CheckType_CheckTypeMalware:
+		return r.alternateSecurePackageRemediationGenerator(pkg)
 	}
 
 	return nil, errors.New("no advice available")
@@ -53,6 +62,33 @@ func (r *staticRemediationGenerator) vulnerabilityRemediationGenerator(pkg *mode
 
 func (r *staticRemediationGenerator) lowPopularityRemediationGenerator(pkg *models.Package) (*jsonreportspec.RemediationAdvice, error) {
 	return &jsonreportspec.RemediationAdvice{
-		Type: jsonreportspec.RemediationAdviceType_AlternatePopularPackage,
+		Type:                       jsonreportspec.RemediationAdviceType_AlternatePopularPackage,
+		TargetAlternatePackageName: r.alternatePackageName(pkg),
+	}, nil
+}
+
+func (r *staticRemediationGenerator) alternateSecurePackageRemediationGenerator(pkg *models.Package) (*jsonreportspec.RemediationAdvice, error) {
+	name := r.alternatePackageName(pkg)
+	if utils.IsEmptyString(name) {
+		return nil, fmt.Errorf("alternative package not available for %s", pkg.ShortName())
+	}
+
+	return &jsonreportspec.RemediationAdvice{
+		Type:                       jsonreportspec.RemediationAdviceType_AlternateSecurePackage,
+		TargetAlternatePackageName: name,
 	}, nil
 }
+
+// Best effort, returns the top alternative or empty string
+func (r *staticRemediationGenerator) alternatePackageName(pkg *models.Package) string {
+	if r.alternatives == nil {
+		return ""
+	}
+
+	alternatives, err := r.alternatives.Alternatives(pkg)
+	if err != nil || len(alternatives) == 0 {
+		return ""
+	}
+
+	return alternatives[0].Name
+}
@@ -9,11 +9,15 @@ import (
 	"github.com/safedep/dry/semver"
 	"github.com/safedep/dry/utils"
 	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/health"
 	"github.com/safedep/vet/pkg/models"
 	"github.com/safedep/vet/pkg/policy"
 	"github.com/safedep/vet/pkg/readers"
 )
 
+// Packages with composite health score below this are reported
+const consoleReportMinHealthScore = 5.0
+
 type consoleReporter struct{}
 
 func NewConsoleReporter() (Reporter, error) {
@@ -24,6 +28,10 @@ func (r *consoleReporter) Name() string {
 	return "Console Report Generator"
 }
 
+func (r *consoleReporter) Interactive() bool {
+	return true
+}
+
 func (r *consoleReporter) AddManifest(manifest *models.PackageManifest) {
 	tbl := table.NewWriter()
 	tbl.SetOutputMirror(os.Stdout)
@@ -63,7 +71,7 @@ func (r *consoleReporter) report(tbl table.Writer, pkg *models.Package) {
 		// Header for this package
 		tbl.AppendRow(table.Row{
 			fmt.Sprintf("%s/%s", pkg.PackageDetails.Name, pkg.PackageDetails.Version),
-			"", "",
+			"PURL", pkg.PackageURL(),
 		})
 
 		headerAppended = true
@@ -117,6 +125,21 @@ func (r *consoleReporter) report(tbl table.Writer, pkg *models.Package) {
 		})
 	}
 
+	// Composite health score with the weakest factor as explanation
+	healthScore := health.Compute(pkg)
+	if healthScore.Available && (healthScore.Score < consoleReportMinHealthScore) {
+		summary := fmt.Sprintf("Score:%.1f", healthScore.Score)
+		if weakest, ok := healthScore.Weakest(); ok {
+			summary = fmt.Sprintf("%s (%s: %s)", summary, weakest.Factor, weakest.Reason)
+		}
+
+		headerAppender()
+		tbl.AppendRow(table.Row{"",
+			text.Bold.Sprint("Low Health"),
+			summary,
+		})
+	}
+
 	if headerAppended {
 		tbl.AppendSeparator()
 	}
@@ -12,10 +12,15 @@ import (
 	"github.com/safedep/vet/pkg/common/logger"
 	"github.com/safedep/vet/pkg/models"
 	"github.com/safedep/vet/pkg/policy"
+	"github.com/safedep/vet/pkg/tags"
 )
 
 type CsvReportingConfig struct {
 	Path string
+
+	// Optional, tags of findings are recorded in addition to the
+	// tags of manifests
+	Tagger *tags.Tagger
 }
 
 type csvReporter struct {
@@ -38,6 +43,8 @@ type csvRecord struct {
 	vulnSummary         string
 	usageEvidenceCount  string
 	usageEvidenceSample string
+	packageUrl          string
+	tags                string
 }
 
 func NewCsvReporter(config CsvReportingConfig) (Reporter, error) {
@@ -104,6 +111,8 @@ func (r *csvReporter) Finish() error {
 			pathToRoot = strings.Join(pathPackages, " -> ")
 		}
 
+		findingTags := r.config.Tagger.Finding(v.Package.Manifest, v.Filter.GetName())
+
 		// Base record
 		record := csvRecord{
 			ecosystem:       string(v.Package.Ecosystem),
@@ -113,6 +122,8 @@ func (r *csvReporter) Finish() error {
 			violationReason: msg,
 			introducedBy:    introducedBy,
 			pathToRoot:      pathToRoot,
+			packageUrl:      v.Package.PackageURL(),
+			tags:            findingTags.String(),
 		}
 
 		// Flatten the vulnerabilities
@@ -164,6 +175,7 @@ func (r *csvReporter) Finish() error {
 			newRecord.vulnSeverity = risk
 			newRecord.usageEvidenceCount = usageEvidenceCount
 			newRecord.usageEvidenceSample = usageEvidenceSample
+			newRecord.tags = tags.Merge(findingTags, r.config.Tagger.Finding(v.Package.Manifest, vulnId)).String()
 
 			records = append(records, newRecord)
 		}
@@ -202,6 +214,8 @@ func (r *csvReporter) persistCsvRecords(records []csvRecord) error {
 		"Vulnerability Summary",
 		"Usage Evidence count",
 		"Sample Usage Evidence",
+		"PURL",
+		"Tags",
 	})
 	if err != nil {
 		return err
@@ -220,6 +234,8 @@ func (r *csvReporter) persistCsvRecords(records []csvRecord) error {
 			csvRecord.vulnSummary,
 			csvRecord.usageEvidenceCount,
 			csvRecord.usageEvidenceSample,
+			csvRecord.packageUrl,
+			csvRecord.tags,
 		}); err != nil {
 			return err
 		}
@@ -13,15 +13,39 @@ import (
 	"github.com/safedep/vet/gen/violations"
 	"github.com/safedep/vet/pkg/analyzer"
 	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/coverage"
+	"github.com/safedep/vet/pkg/degradation"
+	"github.com/safedep/vet/pkg/history"
 	"github.com/safedep/vet/pkg/models"
 	"github.com/safedep/vet/pkg/policy"
 	"github.com/safedep/vet/pkg/readers"
 	"github.com/safedep/vet/pkg/remediations"
 	"github.com/safedep/vet/pkg/schemamapper"
+	"github.com/safedep/vet/pkg/tags"
 )
 
 type JsonReportingConfig struct {
 	Path string
+
+	// Optional, degradations applied during the scan are
+	// recorded in the report metadata
+	Degradations *degradation.Recorder
+
+	// Optional, manifests detected but not scanned are recorded in the
+	// report metadata
+	Coverage *coverage.Recorder
+
+	// Optional, triage states of violations and vulnerabilities are
+	// recorded in the report when available
+	Triage history.TriageStore
+
+	// Optional, tags of the scan and findings are recorded in the report,
+	// tags of manifests are recorded irrespective of the tagger
+	Tagger *tags.Tagger
+
+	// Optional, suggests alternatives in remediation advice, defaults
+	// to curated alternatives
+	Alternatives remediations.AlternativesProvider
 }
 
 // Json reporter is built on top of summary reporter to
@@ -35,9 +59,14 @@ type jsonReportGenerator struct {
 }
 
 func NewJsonReportGenerator(config JsonReportingConfig) (Reporter, error) {
+	remediationGenerator := remediations.NewStaticRemediationGenerator()
+	if config.Alternatives != nil {
+		remediationGenerator = remediations.NewStaticRemediationGeneratorWithAlternatives(config.Alternatives)
+	}
+
 	return &jsonReportGenerator{
 		config:       config,
-		remediations: remediations.NewStaticRemediationGenerator(),
+		remediations: remediationGenerator,
 		manifests:    make(map[string]*schema.PackageManifestReport),
 		packages:     make(map[string]*schema.PackageReport),
 	}, nil
@@ -134,7 +163,14 @@ func (r *jsonReportGenerator) handleFilterEvent(event *analyzer.AnalyzerEvent) {
 		Filter:    event.Filter,
 	}
 
+	// Tags of the finding are recorded as extra so that violations
+	// can be grouped without joining the manifests
+	if findingTags := r.config.Tagger.Finding(event.Package.Manifest, event.Filter.GetName()); len(findingTags) > 0 {
+		violation.Extra = findingTags
+	}
+
 	pkg.Violations = append(pkg.Violations, violation)
+	r.addTriage(event.Package, pkg, event.Filter.GetName())
 
 	advice, err := r.remediations.Advice(event.Package, violation)
 	if err != nil {
@@ -155,8 +191,23 @@ func (r *jsonReportGenerator) findPackageManifestReport(manifest *models.Package
 			Path:        manifest.GetSource().GetPath(),
 			DisplayPath: manifest.GetDisplayPath(),
 			Ecosystem:   manifest.GetSpecEcosystem(),
+			Kind:        string(manifest.GetKind()),
+			Digest:      manifest.GetDigest(),
 			Threats:     make([]*schema.ReportThreat, 0),
 		}
+
+		if len(manifest.GetTags()) > 0 {
+			r.manifests[manifestId].Tags = tags.Merge(manifest.GetTags())
+		}
+
+		if len(manifest.GetFrameworks()) > 0 {
+			r.manifests[manifestId].Frameworks = manifest.GetFrameworks()
+		}
+
+		if sbom := manifest.GetSbomDocument(); sbom != nil {
+			r.manifests[manifestId].SbomSerialNumber = sbom.SerialNumber
+			r.manifests[manifestId].SbomDocumentUri = sbom.DocumentUri
+		}
 	}
 
 	return r.manifests[manifestId]
@@ -166,11 +217,41 @@ func (r *jsonReportGenerator) findPackageReport(pkg *models.Package) *jsonreport
 	pkgId := pkg.Id()
 	if _, ok := r.packages[pkgId]; !ok {
 		r.packages[pkgId] = r.buildJsonPackageReportFromPackage(pkg)
+
+		for _, vuln := range r.packages[pkgId].Vulnerabilities {
+			r.addTriage(pkg, r.packages[pkgId], vuln.GetId())
+		}
 	}
 
 	return r.packages[pkgId]
 }
 
+// addTriage records the triage state of a finding, findings not triaged
+// are recorded as new so that the report can be edited and imported
+func (r *jsonReportGenerator) addTriage(pkg *models.Package, report *jsonreportspec.PackageReport, findingId string) {
+	if r.config.Triage == nil || findingId == "" {
+		return
+	}
+
+	triage, err := history.Triage(r.config.Triage, history.NewFindingKey(pkg, findingId))
+	if err != nil {
+		logger.Warnf("Failed to get triage of %s for %s: %v", findingId, pkg.ShortName(), err)
+		return
+	}
+
+	entry := &jsonreportspec.FindingTriage{
+		FindingId: findingId,
+		State:     string(triage.State),
+		Note:      triage.Note,
+	}
+
+	if !triage.UpdatedAt.IsZero() {
+		entry.UpdatedAt = triage.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+
+	report.Triages = append(report.Triages, entry)
+}
+
 func (r *jsonReportGenerator) AddPolicyEvent(event *policy.PolicyEvent) {}
 
 func (r *jsonReportGenerator) Finish() error {
@@ -202,11 +283,30 @@ func (r *jsonReportGenerator) buildSpecReport() (*schema.Report, error) {
 			ToolName:    "vet",
 			ToolVersion: "latest",
 			CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+			Tags:        r.config.Tagger.Scan(),
 		},
 		Packages:  make([]*schema.PackageReport, 0),
 		Manifests: make([]*schema.PackageManifestReport, 0),
 	}
 
+	for _, d := range r.config.Degradations.Records() {
+		report.Meta.Degradations = append(report.Meta.Degradations, &schema.ReportDegradation{
+			Source: string(d.Source),
+			Mode:   string(d.Mode),
+			Count:  int32(d.Count),
+			Reason: d.Reason,
+		})
+	}
+
+	for _, c := range r.config.Coverage.Records() {
+		report.Meta.NotScanned = append(report.Meta.NotScanned, &schema.ReportNotScanned{
+			Path:      c.Path,
+			Ecosystem: c.Ecosystem,
+			Reason:    string(c.Reason),
+			Detail:    c.Detail,
+		})
+	}
+
 	for _, pm := range r.manifests {
 		report.Manifests = append(report.Manifests, pm)
 	}
@@ -224,7 +324,9 @@ func (j *jsonReportGenerator) buildJsonPackageReportFromPackage(p *models.Packag
 			Ecosystem: p.GetSpecEcosystem(),
 			Name:      p.GetName(),
 			Version:   p.GetVersion(),
+			Purl:      p.PackageURL(),
 		},
+		Purl:            p.PackageURL(),
 		Violations:      make([]*violations.Violation, 0),
 		Advices:         make([]*schema.RemediationAdvice, 0),
 		Vulnerabilities: make([]*modelspec.InsightVulnerability, 0),
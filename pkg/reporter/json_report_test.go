@@ -3,13 +3,22 @@ package reporter
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/safedep/dry/utils"
+	"github.com/safedep/vet/gen/checks"
+	"github.com/safedep/vet/gen/filtersuite"
+	"github.com/safedep/vet/gen/insightapi"
 	jsonreportspec "github.com/safedep/vet/gen/jsonreport"
 	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/coverage"
+	"github.com/safedep/vet/pkg/history"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/remediations"
+	"github.com/safedep/vet/pkg/storage"
+	"github.com/safedep/vet/pkg/tags"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -54,6 +63,7 @@ func TestJsonRepoGenerator(t *testing.T) {
 
 				assert.Equal(t, "/namespace/1/sample-path", report.Manifests[0].DisplayPath)
 				assert.Equal(t, string(models.ManifestSourceLocal), report.Manifests[0].SourceType)
+				assert.Equal(t, string(models.ManifestKindLockfile), report.Manifests[0].Kind)
 				assert.Equal(t, 1, len(report.Packages))
 				assert.Equal(t, "golib1", report.Packages[0].GetPackage().GetName())
 				assert.Equal(t, "0.1.2", report.Packages[0].GetPackage().GetVersion())
@@ -86,6 +96,31 @@ func TestJsonRepoGenerator(t *testing.T) {
 				assert.Equal(t, "/tmp/sample/display/path", report.Manifests[0].DisplayPath)
 			},
 		},
+		{
+			"Verify SBOM manifest metadata",
+			[]*models.PackageManifest{
+				&models.PackageManifest{
+					Source: models.PackageManifestSource{
+						Type:      models.ManifestSourceLocal,
+						Namespace: "/namespace/1",
+						Path:      "bom.json",
+					},
+					Path:      "/namespace/1/bom.json",
+					Ecosystem: models.EcosystemCyDxSBOM,
+					Digest:    "sha256:b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c",
+					SbomDocument: &models.SbomDocumentReference{
+						SerialNumber: "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79",
+					},
+				},
+			},
+			[]*analyzer.AnalyzerEvent{},
+			func(t *testing.T, report *jsonreportspec.Report) {
+				assert.Equal(t, string(models.ManifestKindSbom), report.Manifests[0].Kind)
+				assert.Equal(t, "sha256:b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c", report.Manifests[0].Digest)
+				assert.Equal(t, "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79", report.Manifests[0].SbomSerialNumber)
+				assert.Empty(t, report.Manifests[0].SbomDocumentUri)
+			},
+		},
 	}
 
 	tmpFile, err := os.CreateTemp("", "vet-json-report-test-*")
@@ -131,3 +166,232 @@ func TestJsonRepoGenerator(t *testing.T) {
 		})
 	}
 }
+
+func TestJsonReportTriage(t *testing.T) {
+	store, err := history.NewKeyValueTriageStore(storage.NewMemoryKeyValueStorage())
+	assert.NoError(t, err)
+
+	vulnId := "GHSA-1"
+	manifest := models.NewPackageManifestFromLocal("/app/package-lock.json", models.EcosystemNpm)
+	pkg := &models.Package{
+		PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "lodash", "4.17.20"),
+		Insights: &insightapi.PackageVersionInsight{
+			Vulnerabilities: &[]insightapi.PackageVulnerability{{Id: &vulnId}},
+		},
+	}
+
+	manifest.AddPackage(pkg)
+
+	err = store.PutTriage(history.FindingTriage{
+		FindingKey: history.NewFindingKey(pkg, vulnId),
+		State:      history.TriageStateInProgress,
+		Note:       "upgrade planned",
+	})
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	r, err := NewJsonReportGenerator(JsonReportingConfig{Path: path, Triage: store})
+	assert.NoError(t, err)
+
+	r.AddManifest(manifest)
+	r.AddAnalyzerEvent(&analyzer.AnalyzerEvent{
+		Type: analyzer.ET_FilterExpressionMatched,
+		Filter: &filtersuite.Filter{
+			Name:      "critical-vulns",
+			CheckType: checks.CheckType_CheckTypeVulnerability,
+		},
+		Package: pkg,
+	})
+
+	assert.NoError(t, r.Finish())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var report jsonreportspec.Report
+	assert.NoError(t, utils.FromPbJson(bytes.NewReader(data), &report))
+
+	assert.Len(t, report.Packages, 1)
+
+	triages := report.Packages[0].GetTriages()
+	assert.Len(t, triages, 2)
+
+	assert.Equal(t, vulnId, triages[0].GetFindingId())
+	assert.Equal(t, string(history.TriageStateInProgress), triages[0].GetState())
+	assert.Equal(t, "upgrade planned", triages[0].GetNote())
+	assert.NotEmpty(t, triages[0].GetUpdatedAt())
+
+	assert.Equal(t, "critical-vulns", triages[1].GetFindingId())
+	assert.Equal(t, string(history.TriageStateNew), triages[1].GetState())
+	assert.Empty(t, triages[1].GetUpdatedAt())
+}
+
+func TestJsonReportPurl(t *testing.T) {
+	manifest := models.NewPackageManifestFromLocal("/app/package-lock.json", models.EcosystemNpm)
+	pkg := &models.Package{
+		PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "@angular/core", "1.0.0"),
+	}
+
+	manifest.AddPackage(pkg)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	r, err := NewJsonReportGenerator(JsonReportingConfig{Path: path})
+	assert.NoError(t, err)
+
+	r.AddManifest(manifest)
+	r.AddAnalyzerEvent(&analyzer.AnalyzerEvent{
+		Type: analyzer.ET_FilterExpressionMatched,
+		Filter: &filtersuite.Filter{
+			Name:      "critical-vulns",
+			CheckType: checks.CheckType_CheckTypeVulnerability,
+		},
+		Package: pkg,
+	})
+
+	assert.NoError(t, r.Finish())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var report jsonreportspec.Report
+	assert.NoError(t, utils.FromPbJson(bytes.NewReader(data), &report))
+
+	assert.Len(t, report.Packages, 1)
+	assert.Equal(t, pkg.PackageURL(), report.Packages[0].GetPurl())
+	assert.Equal(t, pkg.PackageURL(), report.Packages[0].GetPackage().GetPurl())
+	assert.Equal(t, "pkg:npm/%40angular/core@1.0.0", report.Packages[0].GetPurl())
+}
+
+type jsonReportTestAlternatives struct{}
+
+func (jsonReportTestAlternatives) Alternatives(_ *models.Package) ([]remediations.Alternative, error) {
+	return []remediations.Alternative{{Name: "dayjs"}}, nil
+}
+
+func TestJsonReportAlternatives(t *testing.T) {
+	manifest := models.NewPackageManifestFromLocal("/app/package-lock.json", models.EcosystemNpm)
+	pkg := &models.Package{
+		PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "left-pad", "1.0.0"),
+	}
+
+	manifest.AddPackage(pkg)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	r, err := NewJsonReportGenerator(JsonReportingConfig{
+		Path:         path,
+		Alternatives: jsonReportTestAlternatives{},
+	})
+	assert.NoError(t, err)
+
+	r.AddManifest(manifest)
+	r.AddAnalyzerEvent(&analyzer.AnalyzerEvent{
+		Type: analyzer.ET_FilterExpressionMatched,
+		Filter: &filtersuite.Filter{
+			Name:      "unmaintained",
+			CheckType: checks.CheckType_CheckTypeMaintenance,
+		},
+		Package: pkg,
+	})
+
+	assert.NoError(t, r.Finish())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var report jsonreportspec.Report
+	assert.NoError(t, utils.FromPbJson(bytes.NewReader(data), &report))
+
+	assert.Len(t, report.Packages, 1)
+	assert.Len(t, report.Packages[0].GetAdvices(), 1)
+	assert.Equal(t, "dayjs", report.Packages[0].GetAdvices()[0].GetTargetAlternatePackageName())
+}
+
+func TestJsonReportTags(t *testing.T) {
+	tagger, err := tags.NewTagger(tags.Config{
+		Findings: []tags.FindingRule{
+			{Finding: "critical-vulns", Tags: tags.Tags{"priority": "p1"}},
+		},
+	}, tags.Tags{"business_unit": "payments"})
+	assert.NoError(t, err)
+
+	manifest := models.NewPackageManifestFromLocal("/app/package-lock.json", models.EcosystemNpm)
+	pkg := &models.Package{
+		PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "lodash", "4.17.20"),
+	}
+
+	manifest.AddPackage(pkg)
+	tagger.TagManifest(manifest)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	r, err := NewJsonReportGenerator(JsonReportingConfig{Path: path, Tagger: tagger})
+	assert.NoError(t, err)
+
+	r.AddManifest(manifest)
+	r.AddAnalyzerEvent(&analyzer.AnalyzerEvent{
+		Type: analyzer.ET_FilterExpressionMatched,
+		Filter: &filtersuite.Filter{
+			Name:      "critical-vulns",
+			CheckType: checks.CheckType_CheckTypeVulnerability,
+		},
+		Package: pkg,
+	})
+
+	assert.NoError(t, r.Finish())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var report jsonreportspec.Report
+	assert.NoError(t, utils.FromPbJson(bytes.NewReader(data), &report))
+
+	assert.Equal(t, map[string]string{"business_unit": "payments"}, report.GetMeta().GetTags())
+	assert.Len(t, report.Manifests, 1)
+	assert.Equal(t, map[string]string{"business_unit": "payments"}, report.Manifests[0].GetTags())
+
+	assert.Len(t, report.Packages, 1)
+	assert.Len(t, report.Packages[0].GetViolations(), 1)
+	assert.Equal(t, map[string]string{"business_unit": "payments", "priority": "p1"},
+		report.Packages[0].GetViolations()[0].GetExtra())
+}
+
+func TestJsonReportFrameworks(t *testing.T) {
+	manifest := models.NewPackageManifestFromLocal("/app/package-lock.json", models.EcosystemNpm)
+	manifest.Frameworks = []string{"nextjs"}
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	r, err := NewJsonReportGenerator(JsonReportingConfig{Path: path})
+	assert.NoError(t, err)
+
+	r.AddManifest(manifest)
+	assert.NoError(t, r.Finish())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var report jsonreportspec.Report
+	assert.NoError(t, utils.FromPbJson(bytes.NewReader(data), &report))
+
+	assert.Len(t, report.Manifests, 1)
+	assert.Equal(t, []string{"nextjs"}, report.Manifests[0].GetFrameworks())
+}
+
+func TestJsonReportNotScanned(t *testing.T) {
+	notScanned := coverage.NewRecorder()
+	notScanned.Record("/app/Cargo.lock", "Cargo", coverage.ReasonUnsupported, "no supported parser for Cargo.lock")
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	r, err := NewJsonReportGenerator(JsonReportingConfig{Path: path, Coverage: notScanned})
+	assert.NoError(t, err)
+	assert.NoError(t, r.Finish())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var report jsonreportspec.Report
+	assert.NoError(t, utils.FromPbJson(bytes.NewReader(data), &report))
+
+	assert.Len(t, report.GetMeta().GetNotScanned(), 1)
+	assert.Equal(t, "/app/Cargo.lock", report.GetMeta().GetNotScanned()[0].GetPath())
+	assert.Equal(t, "Cargo", report.GetMeta().GetNotScanned()[0].GetEcosystem())
+	assert.Equal(t, "unsupported", report.GetMeta().GetNotScanned()[0].GetReason())
+}
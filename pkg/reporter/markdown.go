@@ -11,6 +11,7 @@ import (
 	"github.com/safedep/vet/pkg/exceptions"
 	"github.com/safedep/vet/pkg/models"
 	"github.com/safedep/vet/pkg/policy"
+	"github.com/safedep/vet/pkg/tags"
 
 	_ "embed"
 )
@@ -25,6 +26,7 @@ type MarkdownReportingConfig struct {
 type markdownTemplateInputViolation struct {
 	Ecosystem string
 	PkgName   string
+	Purl      string
 	Message   string
 }
 
@@ -39,6 +41,7 @@ type markdownTemplateInputResultSummary struct {
 	Ecosystem              string
 	PackageCount           int
 	PackageWithIssuesCount int
+	ManifestTags           string
 }
 
 type markdownTemplateInput struct {
@@ -135,6 +138,7 @@ func (r *markdownReportGenerator) Finish() error {
 			summaries[mp] = markdownTemplateInputResultSummary{
 				Ecosystem:    string(s.pkg.Ecosystem),
 				PackageCount: len(s.pkg.Manifest.Packages),
+				ManifestTags: tags.Tags(s.pkg.Manifest.GetTags()).String(),
 			}
 		} else {
 			s := summaries[mp]
@@ -153,6 +157,7 @@ func (r *markdownReportGenerator) Finish() error {
 		violations = append(violations, markdownTemplateInputViolation{
 			Ecosystem: string(v.Package.Ecosystem),
 			PkgName:   fmt.Sprintf("%s@%s", v.Package.Name, v.Package.Version),
+			Purl:      v.Package.PackageURL(),
 			Message:   msg,
 		})
 	}
@@ -16,10 +16,12 @@ import (
 	"github.com/safedep/vet/gen/violations"
 	"github.com/safedep/vet/pkg/analyzer"
 	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/coverage"
 	"github.com/safedep/vet/pkg/malysis"
 	"github.com/safedep/vet/pkg/models"
 	"github.com/safedep/vet/pkg/policy"
 	"github.com/safedep/vet/pkg/readers"
+	"github.com/safedep/vet/pkg/remediations"
 	"github.com/safedep/vet/pkg/reporter/markdown"
 )
 
@@ -32,6 +34,12 @@ type MarkdownSummaryReporterConfig struct {
 	Path                   string
 	ReportTitle            string
 	IncludeMalwareAnalysis bool
+
+	// Optional, manifests detected but not scanned are listed
+	Coverage *coverage.Recorder
+
+	// Optional, suggests alternatives in remediation advice
+	Alternatives remediations.AlternativesProvider
 }
 
 type vetResultInternalModel struct {
@@ -78,7 +86,9 @@ func NewMarkdownSummaryReporter(config MarkdownSummaryReporterConfig) (Reporter,
 	tmpFile.Close()
 
 	jsonReporter, err := NewJsonReportGenerator(JsonReportingConfig{
-		Path: tmpFile.Name(),
+		Path:         tmpFile.Name(),
+		Coverage:     config.Coverage,
+		Alternatives: config.Alternatives,
 	})
 
 	if err != nil {
@@ -203,6 +213,11 @@ func (r *markdownSummaryReporter) buildMarkdownReport(builder *markdown.Markdown
 		return fmt.Errorf("failed to add violations section: %w", err)
 	}
 
+	err = r.addNotScannedSection(builder, report)
+	if err != nil {
+		return fmt.Errorf("failed to add not scanned section: %w", err)
+	}
+
 	return nil
 }
 
@@ -381,6 +396,10 @@ func (r *markdownSummaryReporter) addViolationSection(builder *markdown.Markdown
 			pkgModel.GetEcosystem(), pkgModel.GetName(), pkgModel.GetVersion(),
 			externalReferenceEmojiUrl))
 
+		if purl := pkg.GetPurl(); purl != "" {
+			section.Builder().AddBulletPoint(fmt.Sprintf(":package: Package URL `%s`", purl))
+		}
+
 		manifests := pkg.GetManifests()
 		for _, manifestId := range manifests {
 			if m, ok := internalModel.manifests[manifestId]; ok {
@@ -418,6 +437,32 @@ func (r *markdownSummaryReporter) addViolationSection(builder *markdown.Markdown
 	return nil
 }
 
+// addNotScannedSection lists the blind spots of the scan so that a clean
+// report is not mistaken for full coverage
+func (r *markdownSummaryReporter) addNotScannedSection(builder *markdown.MarkdownBuilder,
+	report *jsonreportspec.Report) error {
+	notScanned := report.GetMeta().GetNotScanned()
+	if len(notScanned) == 0 {
+		return nil
+	}
+
+	builder.AddHeader(2, "Not Scanned")
+	builder.AddParagraph(fmt.Sprintf("%s %d manifest(s) or directories were detected but not scanned",
+		markdown.EmojiWarning, len(notScanned)))
+
+	for _, ns := range notScanned {
+		ecosystem := ns.GetEcosystem()
+		if ecosystem == "" {
+			ecosystem = "unknown"
+		}
+
+		builder.AddBulletPoint(fmt.Sprintf("`%s` (%s): %s, %s",
+			ns.GetPath(), ecosystem, ns.GetReason(), ns.GetDetail()))
+	}
+
+	return nil
+}
+
 func (r *markdownSummaryReporter) addMalwareAnalysisReportSection(builder *markdown.MarkdownBuilder) error {
 	malwareInfoTable, err := r.malwareInfo.renderMalwareInfoTable()
 	if err != nil {
@@ -477,8 +522,18 @@ func (r *markdownSummaryReporter) getAdviceSummary(adv *jsonreportspec.Remediati
 		return fmt.Sprintf("Upgrade to %s@%s", adv.GetTargetPackageName(),
 			adv.GetTargetPackageVersion()), nil
 	case jsonreportspec.RemediationAdviceType_AlternatePopularPackage:
+		if adv.GetTargetAlternatePackageName() != "" {
+			return fmt.Sprintf("Use an alternative package that is popular such as %s",
+				adv.GetTargetAlternatePackageName()), nil
+		}
+
 		return "Use an alternative package that is popular", nil
 	case jsonreportspec.RemediationAdviceType_AlternateSecurePackage:
+		if adv.GetTargetAlternatePackageName() != "" {
+			return fmt.Sprintf("Use an alternative package that has better security posture such as %s",
+				adv.GetTargetAlternatePackageName()), nil
+		}
+
 		return "Use an alternative package that has better security posture", nil
 	}
 
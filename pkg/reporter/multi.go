@@ -0,0 +1,244 @@
+package reporter
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/common/concurrency"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/policy"
+)
+
+// InteractiveReporter is implemented by reporters that render on the
+// terminal. Interactive reporters are invoked sequentially, in the order
+// of registration, so that their output is not interleaved.
+type InteractiveReporter interface {
+	Interactive() bool
+}
+
+// ReporterError is the failure of a single reporter
+type ReporterError struct {
+	Reporter string
+	Err      error
+}
+
+func (e *ReporterError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Reporter, e.Err)
+}
+
+func (e *ReporterError) Unwrap() error {
+	return e.Err
+}
+
+// ReportingError collects the failures of individual reporters. Failure
+// of a reporter does not prevent other reporters from finishing.
+type ReportingError struct {
+	Errors []*ReporterError
+}
+
+func (e *ReportingError) Error() string {
+	return errors.Join(e.Unwrap()...).Error()
+}
+
+func (e *ReportingError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, re := range e.Errors {
+		errs = append(errs, re)
+	}
+
+	return errs
+}
+
+// multiReporter fans out the collected data to a set of reporters so that
+// any combination of output formats are generated from a single scan. The
+// data (manifests and events) are computed once by the scanner and shared
+// with all reporters.
+//
+// Non-interactive reporters are asynchronous. Each of them has its own
+// bounded queue (sized by concurrency config) drained by a dedicated go routine so that a slow reporter
+// (e.g. cloud sync) does not block the analysis loop till its queue is full.
+// Each reporter is invoked by at most one go routine at a time hence
+// reporters are not required to be thread safe.
+type multiReporter struct {
+	interactive []Reporter
+	async       []*asyncReporter
+
+	// First failure of interactive reporters while handling events
+	interactiveErrs []*ReporterError
+}
+
+type asyncReporter struct {
+	reporter Reporter
+	queue    chan func(Reporter) error
+	done     chan bool
+
+	// First failure while handling queued events
+	err *ReporterError
+}
+
+// NewMultiReporter creates a reporter that dispatches to all the
+// given reporters
+func NewMultiReporter(reporters ...Reporter) Reporter {
+	mr := &multiReporter{
+		interactive: []Reporter{},
+		async:       []*asyncReporter{},
+	}
+
+	// The producer blocks when the queue of a reporter is full
+	queueSize := concurrency.For(concurrency.SubsystemReporter).QueueSize
+
+	for _, r := range reporters {
+		if ir, ok := r.(InteractiveReporter); ok && ir.Interactive() {
+			mr.interactive = append(mr.interactive, r)
+			continue
+		}
+
+		ar := &asyncReporter{
+			reporter: r,
+			queue:    make(chan func(Reporter) error, queueSize),
+			done:     make(chan bool),
+		}
+
+		go ar.start()
+		mr.async = append(mr.async, ar)
+	}
+
+	mr.interactiveErrs = make([]*ReporterError, len(mr.interactive))
+	return mr
+}
+
+func (r *multiReporter) Name() string {
+	return "Multi Reporter"
+}
+
+func (r *multiReporter) AddManifest(manifest *models.PackageManifest) {
+	r.dispatch(func(rp Reporter) error {
+		rp.AddManifest(manifest)
+		return nil
+	})
+}
+
+func (r *multiReporter) AddAnalyzerEvent(event *analyzer.AnalyzerEvent) {
+	r.dispatch(func(rp Reporter) error {
+		rp.AddAnalyzerEvent(event)
+		return nil
+	})
+}
+
+func (r *multiReporter) AddPolicyEvent(event *policy.PolicyEvent) {
+	r.dispatch(func(rp Reporter) error {
+		rp.AddPolicyEvent(event)
+		return nil
+	})
+}
+
+// SetScanError forwards the outcome of the scan to the reporters
+// recording it
+func (r *multiReporter) SetScanError(err error) {
+	r.dispatch(func(rp Reporter) error {
+		if sr, ok := rp.(ScanErrorReporter); ok {
+			sr.SetScanError(err)
+		}
+
+		return nil
+	})
+}
+
+// Finish waits for the queued events to be handled and all reporters to
+// finish. Returns a *ReportingError when one or more reporters failed.
+func (r *multiReporter) Finish() error {
+	errs := make([]*ReporterError, len(r.async)+len(r.interactive))
+
+	for _, ar := range r.async {
+		close(ar.queue)
+	}
+
+	var wg sync.WaitGroup
+	for idx, ar := range r.async {
+		wg.Add(1)
+		go func(idx int, ar *asyncReporter) {
+			defer wg.Done()
+
+			<-ar.done
+			errs[idx] = invokeReporter(ar.reporter, func(rp Reporter) error {
+				return rp.Finish()
+			})
+
+			// Failure while handling events take precedence
+			if ar.err != nil {
+				errs[idx] = ar.err
+			}
+		}(idx, ar)
+	}
+
+	for idx, rp := range r.interactive {
+		errs[len(r.async)+idx] = invokeReporter(rp, func(rp Reporter) error {
+			return rp.Finish()
+		})
+
+		if r.interactiveErrs[idx] != nil {
+			errs[len(r.async)+idx] = r.interactiveErrs[idx]
+		}
+	}
+
+	wg.Wait()
+
+	reportingErr := &ReportingError{Errors: []*ReporterError{}}
+	for _, err := range errs {
+		if err != nil {
+			reportingErr.Errors = append(reportingErr.Errors, err)
+		}
+	}
+
+	if len(reportingErr.Errors) == 0 {
+		return nil
+	}
+
+	return reportingErr
+}
+
+// dispatch enqueues fn for asynchronous reporters while interactive
+// reporters are invoked sequentially by the caller
+func (r *multiReporter) dispatch(fn func(Reporter) error) {
+	for _, ar := range r.async {
+		ar.queue <- fn
+	}
+
+	for idx, rp := range r.interactive {
+		err := invokeReporter(rp, fn)
+		if err != nil && r.interactiveErrs[idx] == nil {
+			r.interactiveErrs[idx] = err
+		}
+	}
+}
+
+func (ar *asyncReporter) start() {
+	defer close(ar.done)
+
+	for fn := range ar.queue {
+		err := invokeReporter(ar.reporter, fn)
+		if err != nil && ar.err == nil {
+			ar.err = err
+		}
+	}
+}
+
+// invokeReporter isolates the caller from failure or panic of a reporter
+func invokeReporter(rp Reporter, fn func(Reporter) error) (rerr *ReporterError) {
+	defer func() {
+		if v := recover(); v != nil {
+			logger.Errorf("Reporter: %s panicked with %v", rp.Name(), v)
+			rerr = &ReporterError{Reporter: rp.Name(), Err: fmt.Errorf("panic: %v", v)}
+		}
+	}()
+
+	err := fn(rp)
+	if err != nil {
+		return &ReporterError{Reporter: rp.Name(), Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package reporter
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+type multiReporterTestReporter struct {
+	name        string
+	interactive bool
+	finishErr   error
+	panics      bool
+	block       chan bool
+
+	m         sync.Mutex
+	manifests int
+	events    int
+	log       *[]string
+}
+
+func (r *multiReporterTestReporter) Name() string {
+	return r.name
+}
+
+func (r *multiReporterTestReporter) Interactive() bool {
+	return r.interactive
+}
+
+func (r *multiReporterTestReporter) AddManifest(manifest *models.PackageManifest) {
+	if r.block != nil {
+		<-r.block
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.manifests++
+}
+
+func (r *multiReporterTestReporter) AddAnalyzerEvent(event *analyzer.AnalyzerEvent) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.events++
+}
+
+func (r *multiReporterTestReporter) AddPolicyEvent(event *policy.PolicyEvent) {}
+
+func (r *multiReporterTestReporter) Finish() error {
+	if r.panics {
+		panic("boom")
+	}
+
+	if r.interactive {
+		*r.log = append(*r.log, r.name)
+	}
+
+	return r.finishErr
+}
+
+func TestMultiReporter(t *testing.T) {
+	interactiveLog := []string{}
+
+	a := &multiReporterTestReporter{name: "a", log: &interactiveLog}
+	b := &multiReporterTestReporter{name: "b", finishErr: errors.New("network error"), log: &interactiveLog}
+	c := &multiReporterTestReporter{name: "c", interactive: true, log: &interactiveLog}
+	d := &multiReporterTestReporter{name: "d", interactive: true, log: &interactiveLog}
+
+	mr := NewMultiReporter(a, b, c, d)
+
+	manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+	mr.AddManifest(manifest)
+	mr.AddAnalyzerEvent(&analyzer.AnalyzerEvent{})
+	mr.AddAnalyzerEvent(&analyzer.AnalyzerEvent{})
+
+	err := mr.Finish()
+	assert.ErrorContains(t, err, "b: network error")
+
+	for _, r := range []*multiReporterTestReporter{a, b, c, d} {
+		assert.Equal(t, 1, r.manifests)
+		assert.Equal(t, 2, r.events)
+	}
+
+	var reportingErr *ReportingError
+	assert.ErrorAs(t, err, &reportingErr)
+	assert.Len(t, reportingErr.Errors, 1)
+	assert.Equal(t, "b", reportingErr.Errors[0].Reporter)
+
+	// Interactive reporters are finished in order of registration
+	assert.Equal(t, []string{"c", "d"}, interactiveLog)
+}
+
+func TestMultiReporterIsolatesPanic(t *testing.T) {
+	interactiveLog := []string{}
+
+	a := &multiReporterTestReporter{name: "a", panics: true, log: &interactiveLog}
+	b := &multiReporterTestReporter{name: "b", interactive: true, panics: true, log: &interactiveLog}
+	c := &multiReporterTestReporter{name: "c", interactive: true, log: &interactiveLog}
+
+	err := NewMultiReporter(a, b, c).Finish()
+
+	var reportingErr *ReportingError
+	assert.ErrorAs(t, err, &reportingErr)
+	assert.Len(t, reportingErr.Errors, 2)
+	assert.ErrorContains(t, err, "a: panic: boom")
+	assert.ErrorContains(t, err, "b: panic: boom")
+
+	// Reporters after the failed one are still finished
+	assert.Equal(t, []string{"c"}, interactiveLog)
+}
+
+func TestMultiReporterSlowReporterDoesNotBlock(t *testing.T) {
+	interactiveLog := []string{}
+
+	slow := &multiReporterTestReporter{name: "slow", block: make(chan bool), log: &interactiveLog}
+	fast := &multiReporterTestReporter{name: "fast", interactive: true, log: &interactiveLog}
+
+	mr := NewMultiReporter(slow, fast)
+
+	manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+	for i := 0; i < 10; i++ {
+		mr.AddManifest(manifest)
+	}
+
+	// Interactive reporter is invoked synchronously while the
+	// slow reporter is still blocked on its first manifest
+	assert.Equal(t, 10, fast.manifests)
+
+	close(slow.block)
+
+	assert.Nil(t, mr.Finish())
+	assert.Equal(t, 10, slow.manifests)
+}
@@ -17,3 +17,9 @@ type Reporter interface {
 	// Inform reporting module to finalise (e.g. write report to file)
 	Finish() error
 }
+
+// ScanErrorReporter is implemented by reporters that record the outcome
+// of the scan. The error, nil on success, is set before finishing.
+type ScanErrorReporter interface {
+	SetScanError(err error)
+}
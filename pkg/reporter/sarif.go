@@ -13,6 +13,7 @@ import (
 	"github.com/safedep/vet/pkg/models"
 	"github.com/safedep/vet/pkg/policy"
 	"github.com/safedep/vet/pkg/reporter/markdown"
+	"github.com/safedep/vet/pkg/tags"
 )
 
 // We will generate SARIF report for integration with
@@ -33,6 +34,10 @@ type SarifToolMetadata struct {
 type SarifReporterConfig struct {
 	Tool SarifToolMetadata
 	Path string
+
+	// Optional, tags of findings are recorded in addition to the
+	// tags of manifests
+	Tagger *tags.Tagger
 }
 
 type sarifReporter struct {
@@ -141,6 +146,14 @@ func (r *sarifReporter) recordFilterMatchEvent(event *analyzer.AnalyzerEvent) {
 
 	result.WithLevel("error")
 	result.WithMessage(r.buildFilterResultMessageMarkdown(event))
+	result.Properties = sarif.Properties{
+		"purl": event.Package.PackageURL(),
+	}
+
+	// Tags of the property bag are strings as per SARIF spec
+	if findingTags := r.config.Tagger.Finding(event.Package.Manifest, event.Filter.GetName()); len(findingTags) > 0 {
+		result.Properties["tags"] = findingTags.Labels()
+	}
 
 	pLocation := sarif.NewPhysicalLocation().
 		WithArtifactLocation(sarif.NewSimpleArtifactLocation(event.Manifest.GetDisplayPath()))
@@ -194,6 +207,8 @@ func (r *sarifReporter) buildFilterResultMessageMarkdown(event *analyzer.Analyze
 		}
 	}
 
+	r.addFindingEvidencesMarkdown(md, event.GetFinding())
+
 	// SARIF spec mandates that we provide text in addition to markdown
 	msg := sarif.NewMessage().
 		WithMarkdown(md.Build()).
@@ -201,3 +216,46 @@ func (r *sarifReporter) buildFilterResultMessageMarkdown(event *analyzer.Analyze
 
 	return msg
 }
+
+// Render evidence attachments e.g. matched code snippet so that
+// the consumer can review the finding without the package source
+func (r *sarifReporter) addFindingEvidencesMarkdown(md *markdown.MarkdownBuilder, finding *analyzer.Finding) {
+	if finding == nil {
+		return
+	}
+
+	hasAttachments := false
+	for _, evidence := range finding.Evidences {
+		if evidence.Attachment != nil {
+			hasAttachments = true
+			break
+		}
+	}
+
+	if !hasAttachments {
+		return
+	}
+
+	md.AddHeader(3, "Evidences")
+	for _, evidence := range finding.Evidences {
+		if evidence.Attachment == nil {
+			continue
+		}
+
+		attachment := evidence.Attachment
+		location := attachment.Name
+		if attachment.Line > 0 {
+			location = fmt.Sprintf("%s:%d", attachment.Name, attachment.Line)
+		}
+
+		md.AddBulletPoint(fmt.Sprintf("%s (%s): `%s`", evidence.Summary, evidence.Type, location))
+		if attachment.Content != "" {
+			md.AddCodeSnippet(attachment.Content, "")
+		}
+
+		if attachment.Truncated {
+			md.AddParagraph(fmt.Sprintf("Content truncated to %d bytes",
+				analyzer.FindingEvidenceAttachmentMaxSize))
+		}
+	}
+}
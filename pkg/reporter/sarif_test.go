@@ -9,6 +9,7 @@ import (
 	"github.com/safedep/vet/gen/insightapi"
 	"github.com/safedep/vet/pkg/analyzer"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/tags"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -178,3 +179,41 @@ func TestSarifReportMarkdown(t *testing.T) {
 	assert.Contains(t, *s.report.Runs[0].Results[2].Message.Markdown, "GitHub Project")
 	assert.Contains(t, *s.report.Runs[0].Results[2].Message.Text, sampleProjectName)
 }
+
+func TestSarifReportTags(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "sarif-reporter-test")
+	assert.Nil(t, err)
+
+	defer os.Remove(tmpFile.Name())
+
+	tagger, err := tags.NewTagger(tags.Config{
+		Findings: []tags.FindingRule{
+			{Finding: "sample-filter1", Tags: tags.Tags{"priority": "p1"}},
+		},
+	}, nil)
+	assert.Nil(t, err)
+
+	r, err := NewSarifReporter(SarifReporterConfig{
+		Tool: SarifToolMetadata{
+			Name:    "tool-name",
+			Version: "tool-version",
+		},
+		Path:   tmpFile.Name(),
+		Tagger: tagger,
+	})
+
+	assert.Nil(t, err)
+
+	for _, event := range events {
+		r.AddManifest(event.Manifest)
+		r.AddAnalyzerEvent(&event)
+	}
+
+	err = r.Finish()
+	assert.Nil(t, err)
+
+	s := r.(*sarifReporter)
+
+	assert.Equal(t, []string{"priority=p1"}, s.report.Runs[0].Results[0].Properties["tags"])
+	assert.NotContains(t, s.report.Runs[0].Results[1].Properties, "tags")
+}
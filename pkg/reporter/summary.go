@@ -14,9 +14,12 @@ import (
 	"github.com/safedep/vet/gen/insightapi"
 	"github.com/safedep/vet/pkg/analyzer"
 	"github.com/safedep/vet/pkg/exceptions"
+	"github.com/safedep/vet/pkg/health"
+	"github.com/safedep/vet/pkg/license"
 	"github.com/safedep/vet/pkg/models"
 	"github.com/safedep/vet/pkg/policy"
 	"github.com/safedep/vet/pkg/readers"
+	"github.com/safedep/vet/pkg/remediations"
 )
 
 const (
@@ -30,18 +33,33 @@ const (
 	summaryWeightUnpopular    = 1
 	summaryWeightUsedInCode   = 1
 	summaryWeightMajorDrift   = 2
+	summaryWeightUnmaintained = 1
 
 	// Opinionated thresholds for identifying repo popularity by stars
 	minStarsForPopularity = 10
 
 	tagVuln              = "vulnerability"
 	tagUnpopular         = "low popularity"
+	tagUnmaintained      = "unmaintained"
 	tagDrift             = "drift"
 	tagUsedInCode        = "used-in-code"
 	tagMalware           = "malware"
 	tagMalwareSuspicious = "suspicious"
 
 	summaryReportMaxUpgradeAdvice = 5
+
+	// Max number of alternative packages to suggest
+	summaryReportMaxAlternatives = 3
+
+	// Max number of licenses to render per manifest in license inventory
+	summaryReportMaxLicensesPerManifest = 5
+
+	// Direct dependencies with inherited health score below this
+	// threshold are reported as risky
+	summaryReportMinInheritedHealthScore = 5.0
+
+	// Group of manifests without the tag used for grouping
+	summaryReportUntaggedGroup = "(untagged)"
 )
 
 type summaryReporterInputViolationData struct {
@@ -72,6 +90,13 @@ type SummaryReporterConfig struct {
 	// This requires code analysis to be enabled with dependency
 	// usage evidences to be available
 	ShowOnlyPackagesWithEvidence bool
+
+	// Optional, findings are grouped by value of the manifest tag
+	GroupByTag string
+
+	// Optional, suggests alternatives for unmaintained or malicious
+	// packages, defaults to curated alternatives
+	Alternatives remediations.AlternativesProvider
 }
 
 type summaryReporter struct {
@@ -86,6 +111,9 @@ type summaryReporter struct {
 			high     int
 			medium   int
 			low      int
+
+			// Packages for which vulnerability data was unavailable
+			unknown int
 		}
 
 		metrics struct {
@@ -124,6 +152,33 @@ type summaryReporter struct {
 
 	// List of lockfile poisoning detection signals
 	lockfilePoisoning []string
+
+	// Findings of analyzer signals other than filter match and
+	// lockfile poisoning e.g. lockfile drift, namespace ownership
+	signals []string
+
+	// Direct dependencies that inherit risk from their transitive dependencies
+	inheritedRisks []*health.InheritedScore
+
+	// Suggest alternatives for unmaintained or malicious packages
+	alternatives remediations.AlternativesProvider
+
+	// Effective license set of each manifest
+	licenseInventories []*summaryReporterLicenseInventory
+
+	// Map of tag value and associated counts when grouped by tag
+	tagGroups map[string]*summaryReporterTagGroup
+}
+
+type summaryReporterTagGroup struct {
+	manifests  int
+	packages   int
+	violations int
+}
+
+type summaryReporterLicenseInventory struct {
+	manifest  *models.PackageManifest
+	inventory *license.Inventory
 }
 
 func NewSummaryReporter(config SummaryReporterConfig) (Reporter, error) {
@@ -131,11 +186,17 @@ func NewSummaryReporter(config SummaryReporterConfig) (Reporter, error) {
 		config.MaxAdvice = summaryReportMaxUpgradeAdvice
 	}
 
+	if config.Alternatives == nil {
+		config.Alternatives = remediations.NewCuratedAlternativesProvider()
+	}
+
 	return &summaryReporter{
 		config:            config,
 		remediationScores: make(map[string]*summaryReporterRemediationData),
 		vulnerabilityInfo: make(map[string]*summaryReporterVulnerabilityData),
 		violations:        make(map[string]*summaryReporterInputViolationData),
+		alternatives:      config.Alternatives,
+		tagGroups:         make(map[string]*summaryReporterTagGroup),
 	}, nil
 }
 
@@ -143,7 +204,16 @@ func (r *summaryReporter) Name() string {
 	return "Summary Report Generator"
 }
 
+func (r *summaryReporter) Interactive() bool {
+	return true
+}
+
 func (r *summaryReporter) AddManifest(manifest *models.PackageManifest) {
+	group := r.tagGroup(manifest)
+	if group != nil {
+		group.manifests += 1
+	}
+
 	readers.NewManifestModelReader(manifest).EnumPackages(func(pkg *models.Package) error {
 		if r.config.ShowOnlyPackagesWithEvidence && !r.usedInCode(pkg) {
 			return nil
@@ -152,14 +222,21 @@ func (r *summaryReporter) AddManifest(manifest *models.PackageManifest) {
 		r.processForVulns(pkg)
 		r.processForMalware(pkg)
 		r.processForPopularity(pkg)
+		r.processForMaintenance(pkg)
 		r.processForVersionDrift(pkg)
 		r.processForProvenance(pkg)
 		r.processForDepsUsageEvidence(pkg)
 
 		r.summary.packages += 1
+		if group != nil {
+			group.packages += 1
+		}
+
 		return nil
 	})
 
+	r.processForInheritedRisk(manifest)
+	r.processForLicenseInventory(manifest)
 	r.summary.manifests += 1
 }
 
@@ -168,6 +245,12 @@ func (r *summaryReporter) AddAnalyzerEvent(event *analyzer.AnalyzerEvent) {
 		r.lockfilePoisoning = append(r.lockfilePoisoning, event.Message.(string))
 	}
 
+	if !event.IsFilterMatch() && !event.IsLockfilePoisoningSignal() && !event.IsFailOnError() {
+		if finding := event.GetFinding(); finding != nil {
+			r.signals = append(r.signals, finding.Title)
+		}
+	}
+
 	if !event.IsFilterMatch() {
 		return
 	}
@@ -194,11 +277,34 @@ func (r *summaryReporter) AddAnalyzerEvent(event *analyzer.AnalyzerEvent) {
 			Message:   msg,
 		}
 		r.violations[pkgId] = &v
+
+		if group := r.tagGroup(event.Package.Manifest); group != nil {
+			group.violations += 1
+		}
 	}
 }
 
 func (r *summaryReporter) AddPolicyEvent(event *policy.PolicyEvent) {}
 
+// tagGroup returns the group of the manifest by value of the tag, nil
+// when findings are not grouped by tag
+func (r *summaryReporter) tagGroup(manifest *models.PackageManifest) *summaryReporterTagGroup {
+	if r.config.GroupByTag == "" {
+		return nil
+	}
+
+	value, ok := manifest.GetTags()[r.config.GroupByTag]
+	if !ok || value == "" {
+		value = summaryReportUntaggedGroup
+	}
+
+	if _, ok := r.tagGroups[value]; !ok {
+		r.tagGroups[value] = &summaryReporterTagGroup{}
+	}
+
+	return r.tagGroups[value]
+}
+
 func (r *summaryReporter) usedInCode(pkg *models.Package) bool {
 	if pkg.CodeAnalysis == nil || pkg.CodeAnalysis.UsageEvidences == nil {
 		return false
@@ -207,6 +313,32 @@ func (r *summaryReporter) usedInCode(pkg *models.Package) bool {
 	return len(pkg.CodeAnalysis.UsageEvidences) > 0
 }
 
+func (r *summaryReporter) processForInheritedRisk(manifest *models.PackageManifest) {
+	for _, is := range health.ComputeInherited(manifest) {
+		if !is.Inherited() || is.Score >= summaryReportMinInheritedHealthScore {
+			continue
+		}
+
+		if r.config.ShowOnlyPackagesWithEvidence && !r.usedInCode(is.Package) {
+			continue
+		}
+
+		r.inheritedRisks = append(r.inheritedRisks, is)
+	}
+}
+
+func (r *summaryReporter) processForLicenseInventory(manifest *models.PackageManifest) {
+	inventory := license.Build(manifest)
+	if inventory.Packages == 0 {
+		return
+	}
+
+	r.licenseInventories = append(r.licenseInventories, &summaryReporterLicenseInventory{
+		manifest:  manifest,
+		inventory: inventory,
+	})
+}
+
 func (r *summaryReporter) processForVersionDrift(pkg *models.Package) {
 	insight := utils.SafelyGetValue(pkg.Insights)
 
@@ -251,8 +383,24 @@ func (r *summaryReporter) processForPopularity(pkg *models.Package) {
 	}
 }
 
+func (r *summaryReporter) processForMaintenance(pkg *models.Package) {
+	// Ignore transitive dependencies from maintenance check
+	if pkg.Depth > 0 {
+		return
+	}
+
+	fs, ok := health.Compute(pkg).Factor(health.FactorMaintenance)
+	if ok && fs.Score == health.MinScore {
+		r.addPkgForRemediationAdvice(pkg, summaryWeightUnmaintained, tagUnmaintained)
+	}
+}
+
 func (r *summaryReporter) processForMalware(pkg *models.Package) {
 	// First we check for known malware from OSV MAL database
+	if pkg.VulnerabilitiesUnknown {
+		r.summary.vulns.unknown += 1
+	}
+
 	insight := utils.SafelyGetValue(pkg.Insights)
 	vulns := utils.SafelyGetValue(insight.Vulnerabilities)
 
@@ -406,6 +554,21 @@ func (r *summaryReporter) Finish() error {
 	r.renderRemediationAdvice()
 	fmt.Println()
 
+	if len(r.inheritedRisks) > 0 {
+		r.renderInheritedRisks()
+		fmt.Println()
+	}
+
+	if len(r.licenseInventories) > 0 {
+		r.renderLicenseInventory()
+		fmt.Println()
+	}
+
+	if len(r.tagGroups) > 0 {
+		r.renderTagGroups()
+		fmt.Println()
+	}
+
 	if exceptions.ActiveCount() > 0 {
 		fmt.Println(text.Faint.Sprint(summaryListPrependText, r.exceptionsCountStatement()))
 		fmt.Println()
@@ -422,6 +585,17 @@ func (r *summaryReporter) Finish() error {
 		fmt.Println()
 	}
 
+	if len(r.signals) > 0 {
+		fmt.Println(summaryListPrependText, text.Bold.Sprint(" Supply Chain Signals "))
+		fmt.Println()
+
+		for _, msg := range r.signals {
+			fmt.Println(text.WrapHard(text.FgYellow.Sprint(summaryListPrependText, msg), 120))
+		}
+
+		fmt.Println()
+	}
+
 	fmt.Println("Run with `vet --filter=\"...\"` for custom filters to identify risky libraries")
 	fmt.Println("For more details", text.Bold.Sprint("https://github.com/safedep/vet"))
 	fmt.Println()
@@ -543,6 +717,117 @@ func (r *summaryReporter) renderRemediationAdvice() {
 	}
 }
 
+// Highlight direct dependencies that should be replaced or upgraded
+// because of risky transitive dependencies which are not directly fixable
+func (r *summaryReporter) renderInheritedRisks() {
+	slices.SortFunc(r.inheritedRisks, func(a, b *health.InheritedScore) int {
+		return cmp.Compare(a.Score, b.Score)
+	})
+
+	fmt.Println(text.Bold.Sprint("Direct dependencies with risky transitive dependencies ..."))
+	fmt.Println()
+
+	tbl := table.NewWriter()
+	tbl.SetOutputMirror(os.Stdout)
+	tbl.SetStyle(table.StyleLight)
+
+	tbl.AppendHeader(table.Row{"Ecosystem", "Package", "Health", "Inherited Health", "Contributing Path"})
+	for idx, is := range r.inheritedRisks {
+		if idx >= r.config.MaxAdvice {
+			break
+		}
+
+		ownScore := "-"
+		if is.Own.Available {
+			ownScore = fmt.Sprintf("%.1f", is.Own.Score)
+		}
+
+		path := []string{}
+		for _, pkg := range is.Path {
+			path = append(path, r.packageNameForRemediationAdvice(pkg))
+		}
+
+		tbl.AppendRow(table.Row{
+			string(is.Package.Ecosystem),
+			r.packageNameForRemediationAdvice(is.Package),
+			ownScore,
+			fmt.Sprintf("%.1f", is.Score),
+			text.Faint.Sprint(strings.Join(path, " > ")),
+		})
+	}
+
+	tbl.Render()
+}
+
+func (r *summaryReporter) renderLicenseInventory() {
+	fmt.Println(text.Bold.Sprint("License inventory ..."))
+	fmt.Println()
+
+	tbl := table.NewWriter()
+	tbl.SetOutputMirror(os.Stdout)
+	tbl.SetStyle(table.StyleLight)
+
+	tbl.AppendHeader(table.Row{"Manifest", "Licenses", "Unique", "Unknown"})
+	for _, li := range r.licenseInventories {
+		licenses := []string{}
+		for idx, lu := range li.inventory.Licenses {
+			if idx >= summaryReportMaxLicensesPerManifest {
+				licenses = append(licenses, fmt.Sprintf("... and %d more",
+					len(li.inventory.Licenses)-summaryReportMaxLicensesPerManifest))
+				break
+			}
+
+			licenses = append(licenses, fmt.Sprintf("%s (%d)", lu.License, lu.Count))
+		}
+
+		unknown := fmt.Sprintf("%d", len(li.inventory.Unknown))
+		if len(li.inventory.Unknown) > 0 {
+			unknown = text.FgHiYellow.Sprint(unknown)
+		}
+
+		tbl.AppendRow(table.Row{
+			li.manifest.GetDisplayPath(),
+			strings.Join(licenses, "\n"),
+			len(li.inventory.Licenses),
+			unknown,
+		})
+
+		tbl.AppendSeparator()
+	}
+
+	tbl.Render()
+}
+
+func (r *summaryReporter) renderTagGroups() {
+	fmt.Println(text.Bold.Sprintf("Findings by %s ...", r.config.GroupByTag))
+	fmt.Println()
+
+	values := []string{}
+	for value := range r.tagGroups {
+		values = append(values, value)
+	}
+
+	slices.Sort(values)
+
+	tbl := table.NewWriter()
+	tbl.SetOutputMirror(os.Stdout)
+	tbl.SetStyle(table.StyleLight)
+
+	tbl.AppendHeader(table.Row{r.config.GroupByTag, "Manifests", "Packages", "Packages with Violations"})
+	for _, value := range values {
+		group := r.tagGroups[value]
+
+		violations := fmt.Sprintf("%d", group.violations)
+		if group.violations > 0 {
+			violations = text.FgHiRed.Sprint(violations)
+		}
+
+		tbl.AppendRow(table.Row{value, group.manifests, group.packages, violations})
+	}
+
+	tbl.Render()
+}
+
 func (r *summaryReporter) addRemediationAdviceTableRows(tbl table.Writer,
 	sortedPackages []*summaryReporterRemediationData, maxAdvice int,
 ) {
@@ -635,6 +920,13 @@ func (r *summaryReporter) addRemediationAdviceTableRows(tbl table.Writer,
 					"", pathToRoot, "", "", "",
 				})
 			}
+
+			alternatives := r.alternativesTextFor(sp)
+			if alternatives != "" {
+				tbl.AppendRow(table.Row{
+					"", text.FgHiGreen.Sprint(alternatives), "", "", "",
+				})
+			}
 		}
 
 		tbl.AppendSeparator()
@@ -748,9 +1040,16 @@ func (r *summaryReporter) packageUpdateVersionForRemediationAdvice(pkg *models.P
 }
 
 func (r *summaryReporter) vulnSummaryStatement() string {
-	return fmt.Sprintf("%d critical, %d high and %d other vulnerabilities were identified",
+	statement := fmt.Sprintf("%d critical, %d high and %d other vulnerabilities were identified",
 		r.summary.vulns.critical, r.summary.vulns.high,
 		r.summary.vulns.medium+r.summary.vulns.low)
+
+	if r.summary.vulns.unknown > 0 {
+		statement += fmt.Sprintf(", vulnerabilities of %d libraries are unknown",
+			r.summary.vulns.unknown)
+	}
+
+	return statement
 }
 
 func (r *summaryReporter) manifestCountStatement() string {
@@ -790,6 +1089,34 @@ func (r *summaryReporter) exceptionsCountStatement() string {
 		exceptions.ActiveCount())
 }
 
+// Alternatives are suggested only for packages that cannot
+// be remediated by upgrading to a newer version
+func (r *summaryReporter) alternativesTextFor(rd *summaryReporterRemediationData) string {
+	suggest := false
+	for _, t := range rd.tags {
+		if t == tagMalware || t == tagMalwareSuspicious || t == tagUnmaintained {
+			suggest = true
+			break
+		}
+	}
+
+	if !suggest {
+		return ""
+	}
+
+	alternatives, err := r.alternatives.Alternatives(rd.pkg)
+	if err != nil || len(alternatives) == 0 {
+		return ""
+	}
+
+	names := []string{}
+	for _, alt := range alternatives[0:min(len(alternatives), summaryReportMaxAlternatives)] {
+		names = append(names, alt.Name)
+	}
+
+	return "Alternatives: " + strings.Join(names, ", ")
+}
+
 func (r *summaryReporter) pathToPackageRoot(pkg *models.Package) string {
 	path := strings.Builder{}
 
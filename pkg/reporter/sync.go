@@ -2,35 +2,104 @@ package reporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"maps"
 	"sync"
+	"time"
 
 	"buf.build/gen/go/safedep/api/grpc/go/safedep/services/controltower/v1/controltowerv1grpc"
 	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
 	policyv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/policy/v1"
 	vulnerabilityv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/vulnerability/v1"
 	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/safedep/dry/utils"
-	"github.com/safedep/vet/gen/checks"
+	"github.com/safedep/vet/gen/insightapi"
 	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/common/concurrency"
+	"github.com/safedep/vet/pkg/common/encryption"
+	"github.com/safedep/vet/pkg/common/errcode"
 	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/degradation"
+	"github.com/safedep/vet/pkg/eventbus"
+	"github.com/safedep/vet/pkg/history"
 	"github.com/safedep/vet/pkg/models"
 	"github.com/safedep/vet/pkg/policy"
 	"github.com/safedep/vet/pkg/readers"
+	"github.com/safedep/vet/pkg/redact"
+	"github.com/safedep/vet/pkg/tags"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
-	syncReporterDefaultWorkerCount = 10
-	syncReporterMaxRetries         = 3
-	syncReporterToolName           = "vet"
+	syncReporterMaxRetries = 3
+	syncReporterToolName   = "vet"
+
+	// Sessions are completed even when the scan is cancelled
+	syncReporterCompleteSessionTimeout = 10 * time.Second
+
+	// Max number of publish errors included in the error returned by Finish
+	syncReporterMaxReportedFailures = 10
 )
 
+// Work that is not published e.g. event without a policy violation
+var errSyncSkipped = errors.New("skipped sync")
+
+// SyncStats is the outcome of publishing packages and policy violations
+type SyncStats struct {
+	Published int
+	Failed    int
+
+	// Not published because the event is not supported, is a duplicate or
+	// the sync was cancelled
+	Skipped int
+
+	// Not published because the work queue was full
+	Dropped int
+
+	// Failed items by class of the failure, available only for the
+	// items published by the reporter
+	FailedByClass map[SyncErrorClass]int
+}
+
+func (s SyncStats) Total() int {
+	return s.Published + s.Failed + s.Skipped + s.Dropped
+}
+
+func (s *SyncStats) record(err error) {
+	switch {
+	case err == nil:
+		s.Published++
+	case errors.Is(err, errSyncSkipped):
+		s.Skipped++
+	case errors.Is(err, errSyncDropped):
+		s.Dropped++
+	default:
+		s.Failed++
+	}
+}
+
+// SyncStatsProvider is implemented by reporters syncing to the cloud.
+// Stats are final only after the reporter is finished.
+type SyncStatsProvider interface {
+	SyncStats() SyncStats
+}
+
 type SyncReporterConfig struct {
 	// gRPC connection for ControlTower
 	ClientConnection *grpc.ClientConn
 
+	// Used to create a connection when ClientConnection is not provided.
+	// The connection is closed when the reporter finishes.
+	Connection SyncConnectionConfig
+
+	// Tenant and workspace selected for the connection, validated when
+	// the connection is created
+	Tenant SyncTenantConfig
+
 	// Enable multi-project syncing
 	// In this case, a new project is created per package manifest
 	EnableMultiProjectSync bool
@@ -40,14 +109,84 @@ type SyncReporterConfig struct {
 	ProjectVersion string
 	TriggerEvent   string
 
+	// Optional, source URL of the project e.g. git remote, used to identify
+	// the source of the project. Not used in multi-project sync.
+	ProjectUrl string
+
 	// Optional or auto-discovered from environment
 	GitRef     string
 	GitRefName string
 	GitRefType string
 	GitSha     string
 
-	// Performance
+	// Performance, defaults to concurrency config
 	WorkerCount int
+	QueueSize   int
+
+	// Optional, defaults to SyncQueueOverflowBlock
+	QueueOverflow SyncQueueOverflow
+
+	// Optional, directory for SyncQueueOverflowSpill, defaults to the
+	// temporary directory
+	SpillDir string
+
+	// Optional, defaults to DefaultSyncRetryPolicy
+	RetryPolicy SyncRetryPolicy
+
+	// Optional, requests are not rate limited by default
+	RateLimit SyncRateLimit
+
+	// Optional, timeout of each call to ControlTower, defaults to 30s.
+	// Calls are also cancelled when the context of the reporter is done.
+	// Negative to disable.
+	PublishTimeout time.Duration
+
+	// Optional, sessions are spooled to this directory when ControlTower
+	// is unreachable. Use `vet cloud flush` to publish spooled sessions.
+	SpoolDir string
+
+	// Optional, spooled records are encrypted when available
+	SpoolCipher encryption.Cipher
+
+	// Spool all sessions without connecting to ControlTower
+	Offline bool
+
+	// Write the requests to DryRunFile as JSON lines without connecting
+	// to ControlTower, to audit the data that is synced
+	DryRun     bool
+	DryRunFile string
+
+	// Optional, sessions in progress are journaled to this directory so
+	// that an interrupted sync can be resumed using `vet cloud resume`.
+	// Sessions are left open for resume when the sync is cancelled.
+	StateDir string
+
+	// Optional, failures to publish are raised as events
+	EventBus *eventbus.Bus
+
+	// Optional, records spooling of sessions when ControlTower is unreachable
+	Degradations *degradation.Recorder
+
+	// Optional, metrics of items queued, published and failed along with
+	// retries and publish latency are registered when available
+	MetricsRegistry prometheus.Registerer
+
+	// Optional, invoked with the number of items completed, successfully or
+	// not, and the number of items queued so far, as items are queued and
+	// completed. Calls are serialized.
+	OnProgress func(completed, total int)
+
+	// Optional, triage states of findings are published as labels of
+	// policy violations
+	Triage history.TriageStore
+
+	// Optional, tags of findings are published as labels of policy
+	// violations in addition to the tags of manifests
+	Tagger *tags.Tagger
+
+	// Optional, manifest paths, namespaces and names of packages are
+	// redacted before they are synced
+	Redactor *redact.Redactor
 
 	// Tool details
 	ToolName    string
@@ -57,40 +196,40 @@ type SyncReporterConfig struct {
 type syncSession struct {
 	sessionId         string
 	toolServiceClient controltowerv1grpc.ToolServiceClient
+
+	// Available when the session is spooled for publishing later
+	spool *syncSpool
+
+	// Available when the session is a dry run
+	dryRun *syncDryRun
 }
 
 type syncSessionPool struct {
 	mu           sync.RWMutex
 	syncSessions map[string]syncSession
+
+	// Keyed sessions being created or failed to create
+	creations map[string]*syncSessionCreation
+}
+
+type syncSessionCreation struct {
+	done chan struct{}
+	err  error
 }
 
 // Only use this session
-func (s *syncSessionPool) addPrimarySession(sessionId string, client controltowerv1grpc.ToolServiceClient) {
+func (s *syncSessionPool) addPrimarySession(session syncSession) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.syncSessions["*"] = syncSession{
-		sessionId:         sessionId,
-		toolServiceClient: client,
-	}
-}
-
-func (s *syncSessionPool) hasKeyedSession(key string) bool {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	_, ok := s.syncSessions[key]
-	return ok
+	s.syncSessions["*"] = session
 }
 
-func (s *syncSessionPool) addKeyedSession(key, sessionId string, client controltowerv1grpc.ToolServiceClient) {
+func (s *syncSessionPool) addKeyedSession(key string, session syncSession) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.syncSessions[key] = syncSession{
-		sessionId:         sessionId,
-		toolServiceClient: client,
-	}
+	s.syncSessions[key] = session
 }
 
 func (s *syncSessionPool) getSession(key string) (*syncSession, error) {
@@ -108,6 +247,48 @@ func (s *syncSessionPool) getSession(key string) (*syncSession, error) {
 	return nil, fmt.Errorf("session not found for key: %s", key)
 }
 
+// getOrCreateKeyedSession creates the session of key on first use.
+// Concurrent callers wait for the session being created. Creation is not
+// retried on failure so that the remaining work of the key fails fast.
+func (s *syncSessionPool) getOrCreateKeyedSession(key string,
+	create func() (syncSession, error),
+) (*syncSession, error) {
+	s.mu.Lock()
+	if session, ok := s.syncSessions[key]; ok {
+		s.mu.Unlock()
+		return &session, nil
+	}
+
+	if s.creations == nil {
+		s.creations = make(map[string]*syncSessionCreation)
+	}
+
+	creation, inProgress := s.creations[key]
+	if !inProgress {
+		creation = &syncSessionCreation{done: make(chan struct{})}
+		s.creations[key] = creation
+	}
+	s.mu.Unlock()
+
+	if inProgress {
+		<-creation.done
+	} else {
+		session, err := create()
+		if err == nil {
+			s.addKeyedSession(key, session)
+		}
+
+		creation.err = err
+		close(creation.done)
+	}
+
+	if creation.err != nil {
+		return nil, creation.err
+	}
+
+	return s.getSession(key)
+}
+
 func (s *syncSessionPool) forEach(f func(key string, session *syncSession) error) error {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -125,116 +306,312 @@ func (s *syncSessionPool) forEach(f func(key string, session *syncSession) error
 type workItem struct {
 	pkg   *models.Package
 	event *analyzer.AnalyzerEvent
+
+	// Package with a verdict from malware analysis
+	malware *models.Package
+
+	// Request built before spilling to disk on queue overflow
+	spilled *syncSpoolRecord
 }
 
 type syncReporter struct {
+	ctx       context.Context
 	config    *SyncReporterConfig
 	workQueue chan *workItem
 	done      chan bool
 	wg        sync.WaitGroup
-	client    *grpc.ClientConn
+	client    syncTransportConnection
 	sessions  *syncSessionPool
+	limiter   *syncRateLimiter
+	journal   *syncJournal
+	dryRun    *syncDryRun
+
+	// Connection created by the reporter is closed on finish
+	ownsClient bool
+
+	// Projects with a published scorecard
+	scorecards *syncScorecardRegistry
+
+	// Package insights published in a session
+	packageInsights *syncPublishRegistry
+
+	// Manifests with a published dependency graph
+	graphs sync.Map
+
+	statsMu  sync.Mutex
+	stats    SyncStats
+	queued   int
+	failures []error
+	metrics  *syncMetrics
+
+	// Error of the scan, set before finishing
+	scanErr error
+
+	progressMu sync.Mutex
+
+	// Work spilled to disk on queue overflow
+	spillMu sync.Mutex
+	spill   *syncSpool
+	spilled int
 }
 
 func NewSyncReporter(config SyncReporterConfig) (Reporter, error) {
-	if config.ClientConnection == nil {
+	return NewSyncReporterWithContext(context.Background(), config)
+}
+
+// NewSyncReporterWithContext creates a sync reporter bound to ctx. On
+// cancellation of the context, in-flight publishes are aborted, pending
+// work is dropped and sessions are completed with error status.
+func NewSyncReporterWithContext(ctx context.Context, config SyncReporterConfig) (Reporter, error) {
+	if config.DryRun && config.DryRunFile == "" {
+		return nil, fmt.Errorf("dry run file is required for dry run sync")
+	}
+
+	// Dry run does not connect to ControlTower
+	if config.DryRun {
+		config.ClientConnection = nil
+		config.Offline = false
+		config.StateDir = ""
+	}
+
+	metrics, err := newSyncMetrics(config.MetricsRegistry)
+	if err != nil {
+		return nil, err
+	}
+
+	var client syncTransportConnection
+	if config.ClientConnection != nil {
+		client = config.ClientConnection
+	}
+
+	ownsClient := false
+	if client == nil && !config.Offline && !config.DryRun && config.Connection.Url != "" {
+		config.Tenant = config.Tenant.withEnvironment()
+		if err := config.Tenant.Validate(); err != nil {
+			return nil, err
+		}
+
+		config.Connection.Headers = config.Tenant.headers(config.Connection.Headers)
+
+		conn, err := newSyncTransportConnection(config.Connection)
+		if err != nil {
+			return nil, err
+		}
+
+		client = conn
+		ownsClient = true
+	}
+
+	if client == nil && !config.Offline && !config.DryRun {
 		return nil, fmt.Errorf("missing gRPC client connection")
 	}
 
+	if config.Offline && config.SpoolDir == "" {
+		return nil, fmt.Errorf("spool directory is required for offline sync")
+	}
+
+	config.RetryPolicy = config.RetryPolicy.withDefaults()
+	config.RetryPolicy.metrics = metrics
+
+	if config.PublishTimeout == 0 {
+		config.PublishTimeout = syncReporterDefaultPublishTimeout
+	}
+
 	// TODO: Auto-discover config using CI environment variables
 	// if enabled by the user
 
-	syncSessionPool := syncSessionPool{
-		syncSessions: make(map[string]syncSession),
+	queueSize := config.QueueSize
+	if queueSize == 0 {
+		queueSize = concurrency.For(concurrency.SubsystemSync).QueueSize
 	}
 
-	trigger := controltowerv1.ToolTrigger_TOOL_TRIGGER_MANUAL
-	source := packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_UNSPECIFIED
+	done := make(chan bool)
+	self := &syncReporter{
+		ctx:        ctx,
+		config:     &config,
+		done:       done,
+		workQueue:  make(chan *workItem, queueSize),
+		client:     client,
+		ownsClient: ownsClient,
+		sessions: &syncSessionPool{
+			syncSessions: make(map[string]syncSession),
+		},
+		scorecards:      newSyncScorecardRegistry(),
+		packageInsights: newSyncPublishRegistry(),
+		limiter:         newSyncRateLimiter(config.RateLimit),
+		metrics:         metrics,
+	}
+
+	if config.DryRun {
+		dryRun, err := newSyncDryRun(config.DryRunFile)
+		if err != nil {
+			return nil, err
+		}
+
+		self.dryRun = dryRun
+	}
+
+	if config.StateDir != "" && !config.Offline {
+		journal, err := newSyncJournal(config.StateDir, config.SpoolCipher)
+		if err != nil {
+			self.closeClient()
+			return nil, err
+		}
+
+		self.journal = journal
+	}
 
 	// A multi-project sync is required for cases like GitHub org where
 	// we are scanning multiple repositories
 	if !config.EnableMultiProjectSync {
-		logger.Debugf("Report Sync: Creating tool session for project: %s, version: %s",
-			config.ProjectName, config.ProjectVersion)
-
-		// Refactor this into a common session creator function
-		toolServiceClient := controltowerv1grpc.NewToolServiceClient(config.ClientConnection)
-		toolSessionRes, err := toolServiceClient.CreateToolSession(context.Background(),
-			&controltowerv1.CreateToolSessionRequest{
-				ToolName:       config.ToolName,
-				ToolVersion:    config.ToolVersion,
-				ProjectName:    config.ProjectName,
-				ProjectVersion: &config.ProjectVersion,
-				ProjectSource:  &source,
-				Trigger:        &trigger,
-			})
+		session, err := self.createSession(config.ProjectName, config.ProjectVersion)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create tool session: %w", err)
-		}
+			if self.journal != nil {
+				self.journal.close(true)
+			}
 
-		logger.Debugf("Report Sync: Tool data upload session ID: %s",
-			toolSessionRes.GetToolSession().GetToolSessionId())
+			if self.dryRun != nil {
+				_ = self.dryRun.close()
+			}
 
-		syncSessionPool.addPrimarySession(toolSessionRes.GetToolSession().GetToolSessionId(),
-			toolServiceClient)
-	}
+			self.closeClient()
+			return nil, err
+		}
 
-	done := make(chan bool)
-	self := &syncReporter{
-		config:    &config,
-		done:      done,
-		workQueue: make(chan *workItem, 1000),
-		client:    config.ClientConnection,
-		sessions:  &syncSessionPool,
+		self.sessions.addPrimarySession(session)
 	}
 
 	self.startWorkers()
 	return self, nil
 }
 
-func (s *syncReporter) Name() string {
-	return "Cloud Sync Reporter"
+func (s *syncReporter) closeClient() {
+	if !s.ownsClient || s.client == nil {
+		return
+	}
+
+	if err := s.client.Close(); err != nil {
+		logger.Warnf("Report Sync: Failed to close connection: %v", err)
+	}
 }
 
-func (s *syncReporter) AddManifest(manifest *models.PackageManifest) {
+// createSession creates a tool session in ControlTower. The session is
+// spooled instead when offline or ControlTower is unreachable and
+// spooling is enabled.
+func (s *syncReporter) createSession(projectName, projectVersion string) (syncSession, error) {
+	trigger := controltowerv1.ToolTrigger_TOOL_TRIGGER_MANUAL
+	source := packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_UNSPECIFIED
+	if !s.config.EnableMultiProjectSync {
+		source = syncProjectSource(s.config.ProjectUrl)
+	}
+
+	req := &controltowerv1.CreateToolSessionRequest{
+		ToolName:       s.config.ToolName,
+		ToolVersion:    s.config.ToolVersion,
+		ProjectName:    projectName,
+		ProjectVersion: &projectVersion,
+		ProjectSource:  &source,
+		Trigger:        &trigger,
+	}
+
+	if s.dryRun != nil {
+		return s.dryRun.session(req)
+	}
+
+	if s.config.Offline {
+		return s.createSpooledSession(req)
+	}
+
+	logger.Debugf("Report Sync: Creating tool session for project: %s, version: %s",
+		projectName, projectVersion)
+
+	toolServiceClient := controltowerv1grpc.NewToolServiceClient(s.client)
+	var toolSessionRes *controltowerv1.CreateToolSessionResponse
+	err := withPublishTimeout(s.config.PublishTimeout, func(ctx context.Context) error {
+		var err error
+		toolSessionRes, err = toolServiceClient.CreateToolSession(ctx, req)
+		return err
+	})(s.ctx)
+	if err != nil {
+		code := status.Code(err)
+		if s.config.SpoolDir != "" && (code == codes.Unavailable || code == codes.DeadlineExceeded) {
+			logger.Warnf("Report Sync: ControlTower is unreachable, spooling session for project: %s/%s: %v",
+				projectName, projectVersion, err)
+
+			s.config.Degradations.Record(degradation.SourceControlTower, degradation.ModeSpool, err)
+
+			return s.createSpooledSession(req)
+		}
+
+		return syncSession{}, fmt.Errorf("failed to create tool session: %w", err)
+	}
+
+	logger.Debugf("Report Sync: Tool data upload session ID: %s",
+		toolSessionRes.GetToolSession().GetToolSessionId())
+
+	if s.journal != nil {
+		if err := s.journal.session(toolSessionRes.GetToolSession().GetToolSessionId()); err != nil {
+			logger.Warnf("Report Sync: Failed to journal session: %v", err)
+		}
+	}
+
+	return syncSession{
+		sessionId:         toolSessionRes.GetToolSession().GetToolSessionId(),
+		toolServiceClient: toolServiceClient,
+	}, nil
+}
+
+func (s *syncReporter) createSpooledSession(req *controltowerv1.CreateToolSessionRequest) (syncSession, error) {
+	spool, err := newSyncSpool(s.config.SpoolDir, s.config.SpoolCipher, req)
+	if err != nil {
+		return syncSession{}, err
+	}
+
+	return syncSession{spool: spool}, nil
+}
+
+// manifestSession returns the session for packages of the manifest. In
+// multi-project sync, the session is created on first use so that a
+// manifest without packages to sync does not create a session.
+func (s *syncReporter) manifestSession(manifest *models.PackageManifest) (*syncSession, error) {
 	manifestSessionKey := manifest.Path
-	if s.config.EnableMultiProjectSync && !s.sessions.hasKeyedSession(manifestSessionKey) {
-		projectName := manifest.GetSource().GetNamespace()
+	if !s.config.EnableMultiProjectSync {
+		return s.sessions.getSession(manifestSessionKey)
+	}
+
+	return s.sessions.getOrCreateKeyedSession(manifestSessionKey, func() (syncSession, error) {
+		// Project is named after the namespace, hence it is redacted the same
+		// way. A stripped namespace falls back to the project of the scan.
+		projectName := s.config.Redactor.Namespace(manifest.GetSource().GetNamespace())
+		if projectName == "" {
+			projectName = s.config.ProjectName
+		}
+
 		projectVersion := "main"
 
-		source := packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_UNSPECIFIED
-		trigger := controltowerv1.ToolTrigger_TOOL_TRIGGER_MANUAL
-
-		logger.Debugf("Report Sync: Creating tool session for project: %s, version: %s",
-			projectName, projectVersion)
-
-		// Refactor this into a common session creator function
-		toolServiceClient := controltowerv1grpc.NewToolServiceClient(s.client)
-		toolSessionRes, err := toolServiceClient.CreateToolSession(context.Background(),
-			&controltowerv1.CreateToolSessionRequest{
-				ToolName:       s.config.ToolName,
-				ToolVersion:    s.config.ToolVersion,
-				ProjectName:    projectName,
-				ProjectVersion: &projectVersion,
-				ProjectSource:  &source,
-				Trigger:        &trigger,
-			})
+		session, err := s.createSession(projectName, projectVersion)
 		if err != nil {
-			logger.Errorf("failed to create tool session for project: %s/%s: %v",
+			return session, fmt.Errorf("failed to create tool session for project: %s/%s: %w",
 				projectName, projectVersion, err)
 		}
 
-		logger.Debugf("Report Sync: Tool data upload session ID: %s",
-			toolSessionRes.GetToolSession().GetToolSessionId())
-
-		s.sessions.addKeyedSession(manifestSessionKey,
-			toolSessionRes.GetToolSession().GetToolSessionId(), toolServiceClient)
+		return session, nil
+	})
+}
 
-	}
+func (s *syncReporter) Name() string {
+	return "Cloud Sync Reporter"
+}
 
+func (s *syncReporter) AddManifest(manifest *models.PackageManifest) {
 	// We are ignoring the error here because we are asynchronously handling the sync of Manifest
 	_ = readers.NewManifestModelReader(manifest).EnumPackages(func(pkg *models.Package) error {
 		s.queuePackage(pkg)
+
+		if syncMalwareVerdict(pkg) {
+			s.queueMalware(pkg)
+		}
+
 		return nil
 	})
 }
@@ -246,40 +623,201 @@ func (s *syncReporter) AddAnalyzerEvent(event *analyzer.AnalyzerEvent) {
 func (s *syncReporter) AddPolicyEvent(event *policy.PolicyEvent) {
 }
 
+func (s *syncReporter) SyncStats() SyncStats {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+
+	stats := s.stats
+	if s.stats.FailedByClass != nil {
+		stats.FailedByClass = maps.Clone(s.stats.FailedByClass)
+	}
+
+	return stats
+}
+
+// SetScanError records the outcome of the scan so that sessions of a
+// failed scan are not completed as success
+func (s *syncReporter) SetScanError(err error) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+
+	s.scanErr = err
+}
+
+// scanError returns nil when the scan failed only due to a policy
+// requesting to fail since the scan itself was successful
+func (s *syncReporter) scanError() error {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+
+	if s.scanErr == nil || errcode.Of(s.scanErr) == errcode.PolicyFailed {
+		return nil
+	}
+
+	return s.scanErr
+}
+
 func (s *syncReporter) Finish() error {
 	s.wg.Wait()
+	s.drainSpill()
 	close(s.done)
 
-	return s.sessions.forEach(func(_ string, session *syncSession) error {
-		logger.Debugf("Report Sync: Completing tool session: %s", session.sessionId)
+	defer s.closeClient()
 
-		_, err := session.toolServiceClient.CompleteToolSession(context.Background(),
-			&controltowerv1.CompleteToolSessionRequest{
-				ToolSession: &controltowerv1.ToolSession{
-					ToolSessionId: session.sessionId,
-				},
+	stats := s.SyncStats()
+	logger.Debugf("Report Sync: Published: %d, Failed: %d, Skipped: %d, Dropped: %d",
+		stats.Published, stats.Failed, stats.Skipped, stats.Dropped)
 
-				Status: controltowerv1.CompleteToolSessionRequest_STATUS_SUCCESS,
-			})
+	if len(stats.FailedByClass) > 0 {
+		logger.Warnf("Report Sync: Failed to publish %d items by class: %s",
+			stats.Failed, stats.FailureSummary())
+	}
 
-		return err
+	// A partial sync or a failed scan must not be reported as success.
+	// ControlTower does not distinguish between the two.
+	sessionStatus := controltowerv1.CompleteToolSessionRequest_STATUS_SUCCESS
+	if s.ctx.Err() != nil || stats.Failed > 0 || stats.Dropped > 0 || s.scanError() != nil {
+		sessionStatus = controltowerv1.CompleteToolSessionRequest_STATUS_ERROR
+	}
+
+	// Sessions of a journaled sync are left open on cancellation to resume later
+	resumable := s.journal != nil && s.ctx.Err() != nil
+	if s.journal != nil && !resumable {
+		if err := s.journal.complete(sessionStatus); err != nil {
+			logger.Warnf("Report Sync: Failed to journal session status: %v", err)
+		}
+	}
+
+	// Sessions must not be left open when the scan is cancelled unless
+	// the sync can be resumed
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(s.ctx), syncReporterCompleteSessionTimeout)
+	defer cancel()
+
+	// Every session is completed even when completing another fails
+	var sessionErrs []error
+	sessionCount := 0
+
+	_ = s.sessions.forEach(func(key string, session *syncSession) error {
+		sessionCount++
+
+		var err error
+		if session.spool != nil {
+			err = session.spool.complete(sessionStatus)
+		} else if session.dryRun != nil {
+			err = session.dryRun.complete(session.sessionId, sessionStatus)
+		} else if !resumable {
+			logger.Debugf("Report Sync: Completing tool session: %s with status: %s",
+				session.sessionId, sessionStatus)
+
+			_, err = session.toolServiceClient.CompleteToolSession(ctx,
+				&controltowerv1.CompleteToolSessionRequest{
+					ToolSession: &controltowerv1.ToolSession{
+						ToolSessionId: session.sessionId,
+					},
+
+					Status: sessionStatus,
+				})
+		}
+
+		if err != nil {
+			logger.Errorf("failed to complete tool session: %s for: %s: %v", session.sessionId, key, err)
+			sessionErrs = append(sessionErrs, fmt.Errorf("session %s for %s: %w", session.sessionId, key, err))
+		}
+
+		return nil
 	})
+
+	if s.journal != nil {
+		s.journal.close(len(sessionErrs) == 0 && !resumable)
+	}
+
+	if s.dryRun != nil {
+		if err := s.dryRun.close(); err != nil {
+			return err
+		}
+	}
+
+	if len(sessionErrs) > 0 {
+		return fmt.Errorf("failed to complete %d of %d tool sessions: %w",
+			len(sessionErrs), sessionCount, errors.Join(sessionErrs...))
+	}
+
+	if s.ctx.Err() != nil {
+		return fmt.Errorf("sync cancelled: %w", s.ctx.Err())
+	}
+
+	if stats.Failed > 0 {
+		s.statsMu.Lock()
+		defer s.statsMu.Unlock()
+
+		return fmt.Errorf("sync incomplete, %d of %d items failed to publish (%s): %w",
+			stats.Failed, stats.Total(), stats.FailureSummary(),
+			errors.Join(s.failures...))
+	}
+
+	if stats.Dropped > 0 {
+		return fmt.Errorf("sync incomplete, %d of %d items dropped due to full queue",
+			stats.Dropped, stats.Total())
+	}
+
+	return nil
+}
+
+func (s *syncReporter) recordOutcome(err error) {
+	s.metrics.outcome(err)
+
+	s.statsMu.Lock()
+	s.stats.record(err)
+	failed := err != nil && !errors.Is(err, errSyncSkipped) && !errors.Is(err, errSyncDropped)
+	if failed {
+		if s.stats.FailedByClass == nil {
+			s.stats.FailedByClass = make(map[SyncErrorClass]int)
+		}
+
+		s.stats.FailedByClass[syncErrorClassOf(err)]++
+	}
+
+	if failed && len(s.failures) < syncReporterMaxReportedFailures {
+		s.failures = append(s.failures, err)
+	}
+	s.statsMu.Unlock()
+
+	// Subscribers are invoked without the lock so that they can query stats
+	if failed && s.config != nil {
+		s.config.EventBus.Publish(eventbus.Event{Topic: eventbus.TopicSyncFailed, Err: err})
+	}
+
+	s.progress()
+}
+
+// progress notifies the progress callback with the current stats
+func (s *syncReporter) progress() {
+	if s.config == nil || s.config.OnProgress == nil {
+		return
+	}
+
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+
+	s.statsMu.Lock()
+	completed, total := s.stats.Total(), s.queued
+	s.statsMu.Unlock()
+
+	s.config.OnProgress(completed, max(completed, total))
 }
 
 func (s *syncReporter) queueEvent(event *analyzer.AnalyzerEvent) {
-	s.wg.Add(1)
-	s.workQueue <- &workItem{event: event}
+	s.queue(&workItem{event: event})
 }
 
 func (s *syncReporter) queuePackage(pkg *models.Package) {
-	s.wg.Add(1)
-	s.workQueue <- &workItem{pkg: pkg}
+	s.queue(&workItem{pkg: pkg})
 }
 
 func (s *syncReporter) startWorkers() {
 	count := s.config.WorkerCount
 	if count == 0 {
-		count = syncReporterDefaultWorkerCount
+		count = concurrency.For(concurrency.SubsystemSync).Workers
 	}
 
 	for i := 0; i < count; i++ {
@@ -291,73 +829,130 @@ func (s *syncReporter) syncReportWorker() {
 	for {
 		select {
 		case item := <-s.workQueue:
-			if item.event != nil {
-				err := s.syncEvent(item.event)
-				if err != nil {
-					logger.Errorf("failed to sync event: %v", err)
-				}
-			} else if item.pkg != nil {
-				err := s.syncPackage(item.pkg)
-				if err != nil {
-					logger.Errorf("failed to sync package: %v", err)
-				}
-			}
+			s.recordOutcome(s.syncItem(item))
+			s.wg.Done()
 		case <-s.done:
 			return
 		}
 	}
 }
 
+// Pending work is dropped on cancellation but the queue is still
+// drained so that Finish does not block
+func (s *syncReporter) syncItem(item *workItem) error {
+	if s.ctx.Err() != nil {
+		return errSyncSkipped
+	}
+
+	var err error
+	var kind string
+	var pkg *models.Package
+	if item.event != nil {
+		kind, pkg = "policy violation", item.event.Package
+		err = s.syncEvent(item.event)
+	} else if item.pkg != nil {
+		kind, pkg = "package", item.pkg
+		err = s.syncPackage(item.pkg)
+	} else if item.malware != nil {
+		kind, pkg = "malware verdict", item.malware
+		err = s.syncMalware(item.malware)
+	} else if item.spilled != nil {
+		kind = "spilled " + string(item.spilled.Kind)
+		err = s.syncSpilled(item.spilled)
+	}
+
+	if err == nil || errors.Is(err, errSyncSkipped) {
+		return err
+	}
+
+	manifest, sessionKey := "", ""
+	if pkg != nil && pkg.Manifest != nil {
+		manifest, sessionKey = pkg.Manifest.GetDisplayPath(), pkg.Manifest.Path
+	} else if item.spilled != nil {
+		manifest, sessionKey = item.spilled.SessionKey, item.spilled.SessionKey
+	}
+
+	syncErr := newSyncError(kind, syncPackageCoordinate(pkg), manifest, s.sessionIdOf(sessionKey), err)
+	logger.Errorf("Report Sync: %v", syncErr)
+
+	return syncErr
+}
+
+// sessionIdOf returns the session of the key without creating one, empty
+// when the session is not available
+func (s *syncReporter) sessionIdOf(sessionKey string) string {
+	if s.sessions == nil {
+		return ""
+	}
+
+	session, err := s.sessions.getSession(sessionKey)
+	if err != nil {
+		return ""
+	}
+
+	return session.sessionId
+}
+
 func (s *syncReporter) syncEvent(event *analyzer.AnalyzerEvent) error {
-	defer s.wg.Done()
+	session, req, err := s.policyViolationRequest(event)
+	if err != nil {
+		return err
+	}
+
+	return s.publishPolicyViolation(session, req)
+}
 
+// policyViolationRequest returns errSyncSkipped for events without a finding
+// and for packages stripped by redaction
+func (s *syncReporter) policyViolationRequest(event *analyzer.AnalyzerEvent) (*syncSession,
+	*controltowerv1.PublishPolicyViolationRequest, error,
+) {
 	pkg := event.Package
 	filter := event.Filter
+	finding := event.GetFinding()
 
-	if pkg == nil || filter == nil || pkg.Manifest == nil {
-		return fmt.Errorf("failed to sync event: invalid event data")
+	if pkg == nil || filter == nil || finding == nil || pkg.Manifest == nil {
+		return nil, nil, errSyncSkipped
 	}
 
-	manifestSessionKey := pkg.Manifest.Path
-	session, err := s.sessions.getSession(manifestSessionKey)
+	if !s.syncRedactedPackage(pkg.GetName()) {
+		return nil, nil, errSyncSkipped
+	}
+
+	session, err := s.manifestSession(pkg.Manifest)
 	if err != nil {
-		return fmt.Errorf("failed to get session for package: %s/%s/%s: %w",
+		return nil, nil, fmt.Errorf("failed to get session for package: %s/%s/%s: %w",
 			pkg.Manifest.Ecosystem, pkg.GetName(), pkg.GetVersion(), err)
 	}
 
 	checkType := policyv1.RuleCheck_RULE_CHECK_UNSPECIFIED
-	switch filter.GetCheckType() {
-	case checks.CheckType_CheckTypeVulnerability:
+	switch finding.Kind {
+	case analyzer.FindingKindVulnerability:
 		checkType = policyv1.RuleCheck_RULE_CHECK_VULNERABILITY
-	case checks.CheckType_CheckTypeLicense:
+	case analyzer.FindingKindLicense:
 		checkType = policyv1.RuleCheck_RULE_CHECK_LICENSE
-	case checks.CheckType_CheckTypeMalware:
+	case analyzer.FindingKindMalware:
 		checkType = policyv1.RuleCheck_RULE_CHECK_MALWARE
-	case checks.CheckType_CheckTypeMaintenance:
+	case analyzer.FindingKindMaintenance:
 		checkType = policyv1.RuleCheck_RULE_CHECK_MAINTENANCE
-	case checks.CheckType_CheckTypePopularity:
+	case analyzer.FindingKindPopularity:
 		checkType = policyv1.RuleCheck_RULE_CHECK_POPULARITY
-	case checks.CheckType_CheckTypeSecurityScorecard:
+	case analyzer.FindingKindScorecard:
 		checkType = policyv1.RuleCheck_RULE_CHECK_PROJECT_SCORECARD
 	default:
-		logger.Warnf("unsupported check type: %s", filter.GetCheckType())
+		logger.Warnf("unsupported finding kind: %s", finding.Kind)
 	}
 
 	logger.Debugf("Report Sync: Publishing policy violation for package: %s/%s/%s/%s with violation %s/%s/%s",
 		pkg.Manifest.GetControlTowerSpecEcosystem(), pkg.Manifest.GetDisplayPath(), pkg.GetName(), pkg.GetVersion(),
 		checkType, filter.GetName(), filter.GetValue())
 
-	namespace := pkg.Manifest.GetSource().GetNamespace()
 	req := controltowerv1.PublishPolicyViolationRequest{
 		ToolSession: &controltowerv1.ToolSession{
 			ToolSessionId: session.sessionId,
 		},
 
-		Manifest: &packagev1.PackageManifest{
-			Ecosystem: pkg.Manifest.GetControlTowerSpecEcosystem(),
-			Namespace: &namespace,
-			Name:      pkg.Manifest.GetDisplayPath(),
-		},
+		Manifest: syncPackageManifest(pkg.Manifest),
 
 		PackageVersion: &packagev1.PackageVersion{
 			Package: &packagev1.Package{
@@ -380,38 +975,92 @@ func (s *syncReporter) syncEvent(event *analyzer.AnalyzerEvent) error {
 		},
 	}
 
-	_, err = session.toolServiceClient.PublishPolicyViolation(context.Background(), &req)
+	if label := s.triageLabel(pkg, filter.GetName()); label != "" {
+		req.Violation.Rule.Labels = append(req.Violation.Rule.Labels, label)
+	}
+
+	req.Violation.Rule.Labels = append(req.Violation.Rule.Labels,
+		s.tagLabels(pkg.Manifest, filter.GetName())...)
+	req.Violation.Rule.Labels = append(req.Violation.Rule.Labels,
+		syncFrameworkLabels(pkg.Manifest)...)
+
+	s.redactPolicyViolation(&req)
+
+	return session, &req, nil
+}
+
+func (s *syncReporter) publishPolicyViolation(session *syncSession,
+	req *controltowerv1.PublishPolicyViolationRequest,
+) error {
+	if session.spool != nil {
+		return session.spool.write(syncSpoolRecordPolicyViolation, req)
+	}
+
+	if session.dryRun != nil {
+		return session.dryRun.spool.write(syncSpoolRecordPolicyViolation, req)
+	}
+
+	seq := s.journalRequest(syncSpoolRecordPolicyViolation, req)
+	err := s.config.RetryPolicy.run(s.ctx, "policy violation publish", s.limiter.wrap(
+		withPublishTimeout(s.config.PublishTimeout, func(ctx context.Context) error {
+			_, err := session.toolServiceClient.PublishPolicyViolation(ctx, req)
+			return err
+		})))
 	if err != nil {
 		return fmt.Errorf("failed to publish policy violation: %w", err)
 	}
 
+	s.journal.published(seq)
 	return nil
 }
 
+// attachScorecard adds the scorecard to the project insight if the project
+// is not yet published in the session of the package
+func (s *syncReporter) attachScorecard(manifestSessionKey, projectKey string,
+	projectInsight *packagev1.ProjectInsight, scorecard *insightapi.Scorecard,
+) {
+	sessionKey := manifestSessionKey
+	if !s.config.EnableMultiProjectSync {
+		sessionKey = "*"
+	}
+
+	if s.scorecards.claim(sessionKey, projectKey) {
+		projectInsight.Scorecard = syncScorecard(scorecard)
+	}
+}
+
 func (s *syncReporter) syncPackage(pkg *models.Package) error {
-	defer s.wg.Done()
+	session, req, err := s.packageInsightRequest(pkg)
+	if err != nil {
+		return err
+	}
+
+	return s.publishPackageInsight(session, req)
+}
+
+func (s *syncReporter) packageInsightRequest(pkg *models.Package) (*syncSession,
+	*controltowerv1.PublishPackageInsightRequest, error,
+) {
+	if !s.syncRedactedPackage(pkg.GetName()) {
+		return nil, nil, errSyncSkipped
+	}
 
 	manifestSessionKey := pkg.Manifest.Path
-	session, err := s.sessions.getSession(manifestSessionKey)
+	session, err := s.manifestSession(pkg.Manifest)
 	if err != nil {
-		return fmt.Errorf("failed to get session for package: %s/%s/%s: %w",
+		return nil, nil, fmt.Errorf("failed to get session for package: %s/%s/%s: %w",
 			pkg.Manifest.Ecosystem, pkg.GetName(), pkg.GetVersion(), err)
 	}
 
 	logger.Debugf("Report Sync: Publishing package insight for package: %s/%s/%s/%s",
 		pkg.Manifest.GetControlTowerSpecEcosystem(), pkg.Manifest.GetDisplayPath(), pkg.GetName(), pkg.GetVersion())
 
-	namespace := pkg.Manifest.GetSource().GetNamespace()
 	req := controltowerv1.PublishPackageInsightRequest{
 		ToolSession: &controltowerv1.ToolSession{
 			ToolSessionId: session.sessionId,
 		},
 
-		Manifest: &packagev1.PackageManifest{
-			Ecosystem: pkg.Manifest.GetControlTowerSpecEcosystem(),
-			Namespace: &namespace,
-			Name:      pkg.Manifest.GetDisplayPath(),
-		},
+		Manifest: syncPackageManifest(pkg.Manifest),
 
 		PackageVersion: &packagev1.PackageVersion{
 			Package: &packagev1.Package{
@@ -448,33 +1097,24 @@ func (s *syncReporter) syncPackage(pkg *models.Package) error {
 				Version: child.GetVersion(),
 			})
 		}
+
+		s.attachDependencyGraph(pkg.Manifest, req.PackageVersionInsight)
 	}
 
 	// Get the insights
 	insights := utils.SafelyGetValue(pkg.Insights)
 
-	// Add vulnerabilities. We will publish only the minimum required information.
-	// The backend should have its own VDB to enrich the data.
+	// Add vulnerabilities along with severities and aliases
 	vulnerabilities := utils.SafelyGetValue(insights.Vulnerabilities)
 	for _, v := range vulnerabilities {
-		vId := utils.SafelyGetValue(v.Id)
-		vulnerability := vulnerabilityv1.Vulnerability{
-			Id: &vulnerabilityv1.VulnerabilityIdentifier{
-				Value: vId,
-			},
-			Summary: utils.SafelyGetValue(v.Summary),
-		}
-
-		if strings.HasPrefix(vId, "CVE-") {
-			vulnerability.Id.Type = vulnerabilityv1.VulnerabilityIdentifierType_VULNERABILITY_IDENTIFIER_TYPE_CVE
-		} else if strings.HasPrefix(vId, "OSV-") {
-			vulnerability.Id.Type = vulnerabilityv1.VulnerabilityIdentifierType_VULNERABILITY_IDENTIFIER_TYPE_OSV
-		}
-
-		req.PackageVersionInsight.Vulnerabilities = append(req.PackageVersionInsight.Vulnerabilities, &vulnerability)
+		req.PackageVersionInsight.Vulnerabilities = append(req.PackageVersionInsight.Vulnerabilities,
+			syncVulnerability(v))
 	}
 
-	// Add project information
+	// Add project information along with the scorecard of the project
+	scorecardKey := scorecardRepositoryKey(insights.Scorecard)
+	scorecardMatched := false
+
 	project := utils.SafelyGetValue(insights.Projects)
 	for _, p := range project {
 		stars := int64(utils.SafelyGetValue(p.Stars))
@@ -489,7 +1129,7 @@ func (s *syncReporter) syncPackage(pkg *models.Package) error {
 			vt = packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_GITLAB
 		}
 
-		req.PackageVersionInsight.ProjectInsights = append(req.PackageVersionInsight.ProjectInsights, &packagev1.ProjectInsight{
+		projectInsight := &packagev1.ProjectInsight{
 			Project: &packagev1.Project{
 				Type: vt,
 				Name: utils.SafelyGetValue(p.Name),
@@ -501,26 +1141,60 @@ func (s *syncReporter) syncPackage(pkg *models.Package) error {
 			Issues: &packagev1.ProjectInsight_IssueStat{
 				Total: issues,
 			},
-		})
+		}
+
+		if !scorecardMatched && scorecardMatchesProject(scorecardKey, &p) {
+			scorecardMatched = true
+			s.attachScorecard(manifestSessionKey, scorecardKey, projectInsight, insights.Scorecard)
+		}
+
+		req.PackageVersionInsight.ProjectInsights = append(req.PackageVersionInsight.ProjectInsights, projectInsight)
 	}
 
-	licenses := utils.SafelyGetValue(insights.Licenses)
-	for _, license := range licenses {
-		req.PackageVersionInsight.Licenses.Licenses = append(req.PackageVersionInsight.Licenses.Licenses, &packagev1.LicenseMeta{
-			LicenseId: string(license),
-			Name:      string(license),
-		})
+	// Project is not available in insights for some ecosystems e.g. RubyGems
+	if len(project) == 0 && scorecardKey != "" {
+		projectInsight := &packagev1.ProjectInsight{Project: scorecardProject(insights.Scorecard)}
+		s.attachScorecard(manifestSessionKey, scorecardKey, projectInsight, insights.Scorecard)
+
+		req.PackageVersionInsight.ProjectInsights = append(req.PackageVersionInsight.ProjectInsights, projectInsight)
 	}
 
-	// OpenSSF
-	// We can't use vet's collected scorecard because its data model is wrong. There is
-	// not a single scorecard per package. Rather there is a scorecard per project. Since
-	// a package may be related to multiple projects, we will have multiple related scorecards.
+	req.PackageVersionInsight.Licenses.Licenses = syncLicenses(utils.SafelyGetValue(insights.Licenses))
+
+	s.redactPackageInsight(&req)
 
-	_, err = session.toolServiceClient.PublishPackageInsight(context.Background(), &req)
+	return session, &req, nil
+}
+
+func (s *syncReporter) publishPackageInsight(session *syncSession,
+	req *controltowerv1.PublishPackageInsightRequest,
+) error {
+	key := packageInsightPublishKey(req)
+	if !s.packageInsights.claim(key) {
+		logger.Debugf("Report Sync: Skipping duplicate package insight for package: %s/%s/%s",
+			key.manifest, key.name, key.version)
+		return errSyncSkipped
+	}
+
+	if session.spool != nil {
+		return session.spool.write(syncSpoolRecordPackageInsight, req)
+	}
+
+	if session.dryRun != nil {
+		return session.dryRun.spool.write(syncSpoolRecordPackageInsight, req)
+	}
+
+	seq := s.journalRequest(syncSpoolRecordPackageInsight, req)
+	err := s.config.RetryPolicy.run(s.ctx, "package insight publish", s.limiter.wrap(
+		withPublishTimeout(s.config.PublishTimeout, func(ctx context.Context) error {
+			_, err := session.toolServiceClient.PublishPackageInsight(ctx, req)
+			return err
+		})))
 	if err != nil {
+		s.packageInsights.release(key)
 		return fmt.Errorf("failed to publish package insight: %w", err)
 	}
 
+	s.journal.published(seq)
 	return nil
 }
@@ -0,0 +1,317 @@
+package reporter
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// gRPC clients are not allowed to ping more frequently than this
+const syncKeepaliveMinTime = 10 * time.Second
+
+// SyncCredentialProvider returns the token sent as authorization with every
+// request. Providers refresh tokens that expire during a scan e.g. OAuth
+// access token or token obtained using client credentials.
+type SyncCredentialProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// SyncConnectionConfig is used by the sync reporter to connect to
+// ControlTower when a client connection is not provided
+type SyncConnectionConfig struct {
+	// ControlTower URL, port defaults to 443
+	Url string
+
+	// Optional, defaults to SyncTransportGrpc
+	Transport SyncTransport
+
+	// Sent as authorization metadata with every request
+	ApiKey  string
+	Headers http.Header
+
+	// Optional, used for every request when ApiKey is empty
+	Credentials SyncCredentialProvider
+
+	// Interval of keepalive pings, disabled when zero. Middleboxes that
+	// drop idle connections need this. Timeout defaults to 20s. Used
+	// only by the gRPC transport.
+	KeepaliveTime    time.Duration
+	KeepaliveTimeout time.Duration
+
+	// PEM encoded CA certificates trusted in addition to system roots
+	CACertFile string
+
+	// Skip verification of the server certificate
+	InsecureSkipVerify bool
+
+	// Connect without TLS, for self-hosted ControlTower behind
+	// a TLS terminating proxy in a trusted network
+	Plaintext bool
+
+	// HTTP proxy to connect through using CONNECT. When empty, proxy
+	// is discovered from HTTPS_PROXY and NO_PROXY environment variables.
+	ProxyUrl string
+}
+
+func (c SyncConnectionConfig) address() (string, error) {
+	parsedUrl, err := url.Parse(c.Url)
+	if err != nil {
+		return "", fmt.Errorf("invalid sync url: %w", err)
+	}
+
+	host, port := parsedUrl.Hostname(), parsedUrl.Port()
+	if host == "" {
+		return "", fmt.Errorf("invalid sync url: %q has no host", c.Url)
+	}
+
+	if port == "" {
+		port = "443"
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// DialOptions for the transport, keepalive and proxy settings
+func (c SyncConnectionConfig) DialOptions() ([]grpc.DialOption, error) {
+	dopts := []grpc.DialOption{}
+
+	if c.Plaintext {
+		if c.CACertFile != "" || c.InsecureSkipVerify {
+			return nil, errors.New("TLS options are not allowed with plaintext connection")
+		}
+
+		dopts = append(dopts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		tlsConfig, err := c.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		dopts = append(dopts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	dopts = append(dopts, grpc.WithPerRPCCredentials(&syncTokenCredential{
+		apiKey:                   c.ApiKey,
+		credentials:              c.Credentials,
+		headers:                  c.Headers,
+		requireTransportSecurity: !c.Plaintext,
+	}))
+
+	if c.KeepaliveTime > 0 {
+		if c.KeepaliveTime < syncKeepaliveMinTime {
+			return nil, fmt.Errorf("keepalive time must be at least %s", syncKeepaliveMinTime)
+		}
+
+		dopts = append(dopts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                c.KeepaliveTime,
+			Timeout:             c.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	proxyUrl, err := c.proxyUrl()
+	if err != nil {
+		return nil, err
+	}
+
+	if proxyUrl != nil {
+		dopts = append(dopts, grpc.WithContextDialer(syncProxyDialer(proxyUrl)))
+	}
+
+	return dopts, nil
+}
+
+// proxyUrl returns nil when proxy is not configured
+func (c SyncConnectionConfig) proxyUrl() (*url.URL, error) {
+	if c.ProxyUrl == "" {
+		return nil, nil
+	}
+
+	proxyUrl, err := url.Parse(c.ProxyUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+
+	if proxyUrl.Scheme != "http" || proxyUrl.Host == "" {
+		return nil, fmt.Errorf("proxy url must be http://host:port, got: %q", c.ProxyUrl)
+	}
+
+	return proxyUrl, nil
+}
+
+func (c SyncConnectionConfig) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+
+		// #nosec G402 -- explicitly requested for self-hosted ControlTower
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CACertFile == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(c.CACertFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		logger.Warnf("Report Sync: Failed to load system cert pool: %v", err)
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in %s", c.CACertFile)
+	}
+
+	tlsConfig.RootCAs = pool
+	return tlsConfig, nil
+}
+
+// newSyncClientConnection creates a client connection. The connection is
+// established lazily on the first RPC.
+func newSyncClientConnection(config SyncConnectionConfig) (*grpc.ClientConn, error) {
+	address, err := config.address()
+	if err != nil {
+		return nil, err
+	}
+
+	dopts, err := config.DialOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	// Proxy must receive the host name instead of a resolved address
+	target := "dns:///" + address
+	if config.ProxyUrl != "" {
+		target = "passthrough:///" + address
+	}
+
+	logger.Debugf("Report Sync: Connecting to %s (plaintext: %t, proxy: %t)",
+		address, config.Plaintext, config.ProxyUrl != "")
+
+	conn, err := grpc.NewClient(target, dopts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+
+	return conn, nil
+}
+
+type syncTokenCredential struct {
+	apiKey                   string
+	credentials              SyncCredentialProvider
+	headers                  http.Header
+	requireTransportSecurity bool
+}
+
+func (t *syncTokenCredential) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	md := map[string]string{}
+	for k, v := range t.headers {
+		if len(v) > 0 && v[0] != "" {
+			md[k] = v[0]
+		}
+	}
+
+	if t.apiKey != "" {
+		md["authorization"] = t.apiKey
+	} else if t.credentials != nil {
+		token, err := t.credentials.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sync token: %w", err)
+		}
+
+		md["authorization"] = token
+	}
+
+	return md, nil
+}
+
+func (t *syncTokenCredential) RequireTransportSecurity() bool {
+	return t.requireTransportSecurity
+}
+
+// syncProxyDialer tunnels connections through an HTTP proxy using CONNECT
+func syncProxyDialer(proxyUrl *url.URL) func(context.Context, string) (net.Conn, error) {
+	proxyAddress := proxyUrl.Host
+	if proxyUrl.Port() == "" {
+		proxyAddress = net.JoinHostPort(proxyUrl.Hostname(), "80")
+	}
+
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		dialer := net.Dialer{}
+		conn, err := dialer.DialContext(ctx, "tcp", proxyAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to proxy: %w", err)
+		}
+
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(deadline)
+			defer conn.SetDeadline(time.Time{})
+		}
+
+		req := &http.Request{
+			Method: http.MethodConnect,
+			URL:    &url.URL{Host: address},
+			Host:   address,
+			Header: http.Header{},
+		}
+
+		if proxyUrl.User != nil {
+			password, _ := proxyUrl.User.Password()
+			auth := base64.StdEncoding.EncodeToString([]byte(proxyUrl.User.Username() + ":" + password))
+			req.Header.Set("Proxy-Authorization", "Basic "+auth)
+		}
+
+		if err := req.Write(conn); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to write proxy CONNECT request: %w", err)
+		}
+
+		reader := bufio.NewReader(conn)
+		res, err := http.ReadResponse(reader, req)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to read proxy CONNECT response: %w", err)
+		}
+
+		res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			conn.Close()
+			return nil, fmt.Errorf("proxy CONNECT to %s failed: %s", address, res.Status)
+		}
+
+		// Bytes sent by the server right after the tunnel is established
+		if reader.Buffered() > 0 {
+			return &syncBufferedConn{Conn: conn, reader: reader}, nil
+		}
+
+		return conn, nil
+	}
+}
+
+type syncBufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *syncBufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
@@ -0,0 +1,176 @@
+package reporter
+
+import (
+	"bufio"
+	"context"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncConnectionConfigDialOptions(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caCertFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}), 0600))
+
+	invalidCertFile := filepath.Join(t.TempDir(), "invalid.pem")
+	assert.NoError(t, os.WriteFile(invalidCertFile, []byte("invalid"), 0600))
+
+	cases := []struct {
+		name   string
+		config SyncConnectionConfig
+		err    string
+	}{
+		{
+			"default TLS",
+			SyncConnectionConfig{},
+			"",
+		},
+		{
+			"custom CA with keepalive and proxy",
+			SyncConnectionConfig{
+				CACertFile:    caCertFile,
+				KeepaliveTime: 30 * time.Second,
+				ProxyUrl:      "http://proxy.example.com:3128",
+			},
+			"",
+		},
+		{
+			"plaintext",
+			SyncConnectionConfig{Plaintext: true},
+			"",
+		},
+		{
+			"plaintext with TLS options",
+			SyncConnectionConfig{Plaintext: true, InsecureSkipVerify: true},
+			"TLS options are not allowed",
+		},
+		{
+			"missing CA file",
+			SyncConnectionConfig{CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+			"failed to read CA certificate",
+		},
+		{
+			"CA file without certificate",
+			SyncConnectionConfig{CACertFile: invalidCertFile},
+			"no certificate found",
+		},
+		{
+			"keepalive too frequent",
+			SyncConnectionConfig{KeepaliveTime: time.Second},
+			"keepalive time must be at least",
+		},
+		{
+			"unsupported proxy scheme",
+			SyncConnectionConfig{ProxyUrl: "socks5://proxy.example.com:1080"},
+			"proxy url must be http://host:port",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			dopts, err := test.config.DialOptions()
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, dopts)
+		})
+	}
+
+	tlsConfig, err := SyncConnectionConfig{CACertFile: caCertFile}.tlsConfig()
+	assert.NoError(t, err)
+	assert.NotNil(t, tlsConfig.RootCAs)
+}
+
+func TestSyncConnectionConfigAddress(t *testing.T) {
+	address, err := SyncConnectionConfig{Url: "https://api.safedep.io"}.address()
+	assert.NoError(t, err)
+	assert.Equal(t, "api.safedep.io:443", address)
+
+	address, err = SyncConnectionConfig{Url: "http://localhost:9000"}.address()
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:9000", address)
+
+	_, err = SyncConnectionConfig{Url: "api.safedep.io"}.address()
+	assert.ErrorContains(t, err, "has no host")
+}
+
+func TestSyncProxyDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	requests := make(chan *http.Request, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+
+				reader := bufio.NewReader(conn)
+				req, err := http.ReadRequest(reader)
+				if err != nil {
+					return
+				}
+
+				requests <- req
+				if req.Host != "controltower.example.com:443" {
+					_, _ = io.WriteString(conn, "HTTP/1.1 403 Forbidden\r\n\r\n")
+					return
+				}
+
+				// Greeting is sent along with the response to test buffering
+				_, _ = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\nhello")
+				_, _ = io.Copy(conn, reader)
+			}(conn)
+		}
+	}()
+
+	proxyUrl, err := url.Parse("http://user:secret@" + listener.Addr().String())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dial := syncProxyDialer(proxyUrl)
+	conn, err := dial(ctx, "controltower.example.com:443")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	req := <-requests
+	assert.Equal(t, http.MethodConnect, req.Method)
+	assert.Equal(t, "Basic dXNlcjpzZWNyZXQ=", req.Header.Get("Proxy-Authorization"))
+
+	_, err = io.WriteString(conn, "ping")
+	assert.NoError(t, err)
+
+	data := make([]byte, 9)
+	_, err = io.ReadFull(conn, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "helloping", string(data))
+
+	_, err = dial(ctx, "blocked.example.com:443")
+	assert.ErrorContains(t, err, "403 Forbidden")
+	<-requests
+}
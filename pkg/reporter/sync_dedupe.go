@@ -0,0 +1,68 @@
+package reporter
+
+import (
+	"sync"
+
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+)
+
+// A package version is often found multiple times in a session e.g. in
+// nested node_modules of an npm lockfile or in manifests of a monorepo
+// synced as a single manifest. Identical package insights are published
+// only once per session.
+type syncPublishRegistry struct {
+	mu        sync.Mutex
+	published map[syncPublishKey]bool
+}
+
+type syncPublishKey struct {
+	sessionId string
+	ecosystem string
+	name      string
+	version   string
+	manifest  string
+}
+
+func newSyncPublishRegistry() *syncPublishRegistry {
+	return &syncPublishRegistry{published: map[syncPublishKey]bool{}}
+}
+
+func packageInsightPublishKey(req *controltowerv1.PublishPackageInsightRequest) syncPublishKey {
+	return syncPublishKey{
+		sessionId: req.GetToolSession().GetToolSessionId(),
+		ecosystem: req.GetPackageVersion().GetPackage().GetEcosystem().String(),
+		name:      req.GetPackageVersion().GetPackage().GetName(),
+		version:   req.GetPackageVersion().GetVersion(),
+		manifest:  req.GetManifest().GetName(),
+	}
+}
+
+// claim returns true only for the first claim of a key. A nil registry
+// does not deduplicate.
+func (r *syncPublishRegistry) claim(key syncPublishKey) bool {
+	if r == nil {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.published[key] {
+		return false
+	}
+
+	r.published[key] = true
+	return true
+}
+
+// release allows the key to be claimed again e.g. when publishing failed
+func (r *syncPublishRegistry) release(key syncPublishKey) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.published, key)
+}
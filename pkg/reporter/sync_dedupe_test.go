@@ -0,0 +1,48 @@
+package reporter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncPublishRegistry(t *testing.T) {
+	registry := newSyncPublishRegistry()
+
+	key := syncPublishKey{sessionId: "session-1", name: "lodash", version: "4.17.21", manifest: "package-lock.json"}
+	otherManifest := key
+	otherManifest.manifest = "apps/web/package-lock.json"
+
+	assert.True(t, registry.claim(key))
+	assert.False(t, registry.claim(key))
+	assert.True(t, registry.claim(otherManifest))
+
+	registry.release(key)
+	assert.True(t, registry.claim(key))
+
+	var disabled *syncPublishRegistry
+	assert.True(t, disabled.claim(key))
+	assert.True(t, disabled.claim(key))
+}
+
+func TestSyncPackageDeduplicated(t *testing.T) {
+	client := &spoolTestToolServiceClient{}
+	s := newSyncQueueTestReporter(t, SyncQueueOverflowBlock, client)
+	s.packageInsights = newSyncPublishRegistry()
+
+	pkgs := newSyncQueueTestPackages("lodash", "lodash", "express", "react")
+
+	assert.NoError(t, s.syncPackage(pkgs[0]))
+	assert.ErrorIs(t, s.syncPackage(pkgs[1]), errSyncSkipped)
+	assert.NoError(t, s.syncPackage(pkgs[2]))
+
+	// Failed publish is attempted again for a duplicate
+	client.publishErr = errors.New("unavailable")
+	assert.Error(t, s.syncPackage(pkgs[3]))
+
+	client.publishErr = nil
+	assert.NoError(t, s.syncPackage(pkgs[3]))
+
+	assert.Equal(t, []string{"session-1/lodash", "session-1/express", "session-1/react"}, client.packages)
+}
@@ -0,0 +1,83 @@
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/safedep/vet/pkg/common/logger"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// syncDryRun writes the requests that would be sent to ControlTower to a
+// file so that users can audit the data before enabling sync. Requests of
+// all sessions are written to the same file in the spool record format
+// without encryption. Records of a session carry its dry run session ID.
+type syncDryRun struct {
+	spool    *syncSpool
+	sessions atomic.Int64
+}
+
+func newSyncDryRun(path string) (*syncDryRun, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dry run directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dry run file: %w", err)
+	}
+
+	logger.Debugf("Report Sync: Dry run, writing sync requests to: %s", path)
+	return &syncDryRun{
+		spool: &syncSpool{file: file, writer: bufio.NewWriter(file)},
+	}, nil
+}
+
+func (d *syncDryRun) session(req *controltowerv1.CreateToolSessionRequest) (syncSession, error) {
+	sessionId := fmt.Sprintf("dry-run-%d", d.sessions.Add(1))
+
+	payload, err := protojson.Marshal(req)
+	if err != nil {
+		return syncSession{}, fmt.Errorf("failed to serialize %s: %w", syncSpoolRecordSession, err)
+	}
+
+	err = d.spool.writeRecord(syncSpoolRecord{
+		Kind:       syncSpoolRecordSession,
+		Payload:    payload,
+		SessionKey: sessionId,
+	})
+	if err != nil {
+		return syncSession{}, err
+	}
+
+	return syncSession{sessionId: sessionId, dryRun: d}, nil
+}
+
+func (d *syncDryRun) complete(sessionId string, status controltowerv1.CompleteToolSessionRequest_Status) error {
+	return d.spool.writeRecord(syncSpoolRecord{
+		Kind:       syncSpoolRecordComplete,
+		Status:     status.String(),
+		SessionKey: sessionId,
+	})
+}
+
+func (d *syncDryRun) close() error {
+	d.spool.m.Lock()
+	defer d.spool.m.Unlock()
+
+	err := d.spool.writer.Flush()
+	if closeErr := d.spool.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write dry run file: %w", err)
+	}
+
+	return nil
+}
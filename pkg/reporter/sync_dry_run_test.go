@@ -0,0 +1,81 @@
+package reporter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestSyncRecords(t *testing.T, path string) []syncSpoolRecord {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	records := []syncSpoolRecord{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record syncSpoolRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+
+		records = append(records, record)
+	}
+
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func TestSyncDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "sync.jsonl")
+
+	dryRun, err := newSyncDryRun(path)
+	require.NoError(t, err)
+
+	s := &syncReporter{
+		ctx:    context.Background(),
+		config: &SyncReporterConfig{ToolName: "vet", RetryPolicy: DefaultSyncRetryPolicy()},
+		dryRun: dryRun,
+	}
+
+	session, err := s.createSession("test-project", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "dry-run-1", session.sessionId)
+
+	err = s.publishPackageInsight(&session, &controltowerv1.PublishPackageInsightRequest{
+		ToolSession: &controltowerv1.ToolSession{ToolSessionId: session.sessionId},
+		PackageVersion: &packagev1.PackageVersion{
+			Package: &packagev1.Package{Name: "lodash"},
+			Version: "4.17.21",
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, dryRun.complete(session.sessionId, controltowerv1.CompleteToolSessionRequest_STATUS_SUCCESS))
+	require.NoError(t, dryRun.close())
+
+	records := readTestSyncRecords(t, path)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, syncSpoolRecordSession, records[0].Kind)
+	assert.Equal(t, "dry-run-1", records[0].SessionKey)
+	assert.Contains(t, string(records[0].Payload), "test-project")
+
+	assert.Equal(t, syncSpoolRecordPackageInsight, records[1].Kind)
+	assert.Contains(t, string(records[1].Payload), "lodash")
+
+	assert.Equal(t, syncSpoolRecordComplete, records[2].Kind)
+	assert.Equal(t, "STATUS_SUCCESS", records[2].Status)
+}
+
+func TestSyncDryRunRequiresFile(t *testing.T) {
+	_, err := NewSyncReporter(SyncReporterConfig{DryRun: true})
+	assert.ErrorContains(t, err, "dry run file is required")
+}
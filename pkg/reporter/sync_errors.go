@@ -0,0 +1,149 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	"github.com/safedep/vet/pkg/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SyncErrorClass groups failures to sync by the action required to fix them
+type SyncErrorClass string
+
+const (
+	// Credentials are missing, expired or not allowed for the tenant
+	SyncErrorAuth = SyncErrorClass("auth")
+
+	// Rate limit or quota of the tenant is exhausted
+	SyncErrorQuota = SyncErrorClass("quota")
+
+	// Request is rejected by ControlTower, retrying will not help
+	SyncErrorValidation = SyncErrorClass("validation")
+
+	// ControlTower is unreachable or timed out, retrying may help
+	SyncErrorTransient = SyncErrorClass("transient")
+
+	// Failures that are not classified e.g. internal errors
+	SyncErrorOther = SyncErrorClass("other")
+)
+
+// classifySyncError classifies err using its gRPC status code
+func classifySyncError(err error) SyncErrorClass {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return SyncErrorTransient
+	}
+
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return SyncErrorAuth
+	case codes.ResourceExhausted:
+		return SyncErrorQuota
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange,
+		codes.AlreadyExists, codes.NotFound:
+		return SyncErrorValidation
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted, codes.Canceled:
+		return SyncErrorTransient
+	default:
+		return SyncErrorOther
+	}
+}
+
+// syncError is a failure to sync an item along with the context required
+// to troubleshoot it
+type syncError struct {
+	class SyncErrorClass
+
+	// Kind of the item e.g. package, policy violation
+	item string
+
+	// Optional, ecosystem/name@version of the package
+	coordinate string
+	manifest   string
+	sessionId  string
+
+	err error
+}
+
+func newSyncError(item, coordinate, manifest, sessionId string, err error) *syncError {
+	return &syncError{
+		class:      classifySyncError(err),
+		item:       item,
+		coordinate: coordinate,
+		manifest:   manifest,
+		sessionId:  sessionId,
+		err:        err,
+	}
+}
+
+func (e *syncError) Error() string {
+	details := []string{"class: " + string(e.class)}
+	if e.coordinate != "" {
+		details = append(details, "package: "+e.coordinate)
+	}
+
+	if e.manifest != "" {
+		details = append(details, "manifest: "+e.manifest)
+	}
+
+	if e.sessionId != "" {
+		details = append(details, "session: "+e.sessionId)
+	}
+
+	return fmt.Sprintf("failed to sync %s [%s]: %v", e.item, strings.Join(details, ", "), e.err)
+}
+
+func (e *syncError) Unwrap() error {
+	return e.err
+}
+
+// syncErrorClassOf returns the class of a failure, errors not created by
+// newSyncError are classified on demand
+func syncErrorClassOf(err error) SyncErrorClass {
+	var se *syncError
+	if errors.As(err, &se) {
+		return se.class
+	}
+
+	return classifySyncError(err)
+}
+
+func syncPackageCoordinate(pkg *models.Package) string {
+	if pkg == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/%s@%s", pkg.Ecosystem, pkg.GetName(), pkg.GetVersion())
+}
+
+func syncPackageVersionCoordinate(pv *packagev1.PackageVersion) string {
+	if pv == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/%s@%s", models.GetModelEcosystem(pv.GetPackage().GetEcosystem()),
+		pv.GetPackage().GetName(), pv.GetVersion())
+}
+
+// FailureSummary formats the failed items ordered by class
+// e.g. auth: 2, transient: 1
+func (s SyncStats) FailureSummary() string {
+	classes := make([]string, 0, len(s.FailedByClass))
+	for class := range s.FailedByClass {
+		classes = append(classes, string(class))
+	}
+
+	sort.Strings(classes)
+
+	parts := make([]string, 0, len(classes))
+	for _, class := range classes {
+		parts = append(parts, fmt.Sprintf("%s: %d", class, s.FailedByClass[SyncErrorClass(class)]))
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,85 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifySyncError(t *testing.T) {
+	cases := []struct {
+		name  string
+		err   error
+		class SyncErrorClass
+	}{
+		{"unauthenticated", status.Error(codes.Unauthenticated, "token expired"), SyncErrorAuth},
+		{"permission denied", status.Error(codes.PermissionDenied, "tenant mismatch"), SyncErrorAuth},
+		{"quota", status.Error(codes.ResourceExhausted, "rate limited"), SyncErrorQuota},
+		{"invalid argument", status.Error(codes.InvalidArgument, "bad request"), SyncErrorValidation},
+		{"unavailable", status.Error(codes.Unavailable, "connection refused"), SyncErrorTransient},
+		{"wrapped status", fmt.Errorf("failed to publish: %w", status.Error(codes.Unavailable, "")), SyncErrorTransient},
+		{"deadline", fmt.Errorf("publish: %w", context.DeadlineExceeded), SyncErrorTransient},
+		{"internal", status.Error(codes.Internal, "server error"), SyncErrorOther},
+		{"local", errors.New("failed to marshal"), SyncErrorOther},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.class, classifySyncError(test.err))
+		})
+	}
+}
+
+func TestSyncErrorContext(t *testing.T) {
+	cause := status.Error(codes.PermissionDenied, "denied")
+	err := newSyncError("package", "npm/lodash@4.17.21", "package-lock.json", "session-1", cause)
+
+	assert.Equal(t, "failed to sync package [class: auth, package: npm/lodash@4.17.21, "+
+		"manifest: package-lock.json, session: session-1]: "+cause.Error(), err.Error())
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	assert.Equal(t, SyncErrorAuth, syncErrorClassOf(wrapped))
+
+	partial := newSyncError("spilled package_insight", "", "", "", errors.New("bad spill"))
+	assert.Equal(t, "failed to sync spilled package_insight [class: other]: bad spill", partial.Error())
+}
+
+func TestSyncItemClassifiedError(t *testing.T) {
+	client := &spoolTestToolServiceClient{publishErr: status.Error(codes.Unauthenticated, "token expired")}
+	s := newSyncQueueTestReporter(t, SyncQueueOverflowBlock, client)
+
+	pkg := newSyncQueueTestPackages("lodash")[0]
+	err := s.syncItem(&workItem{pkg: pkg})
+
+	assert.ErrorContains(t, err, "class: auth")
+	assert.ErrorContains(t, err, "package: npm/lodash@1.0.0")
+	assert.ErrorContains(t, err, "manifest: package-lock.json")
+	assert.ErrorContains(t, err, "session: session-1")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestSyncStatsFailedByClass(t *testing.T) {
+	s := &syncReporter{}
+
+	s.recordOutcome(nil)
+	s.recordOutcome(errSyncSkipped)
+	s.recordOutcome(newSyncError("package", "", "", "", status.Error(codes.Unavailable, "")))
+	s.recordOutcome(newSyncError("event", "", "", "", status.Error(codes.Unauthenticated, "")))
+	s.recordOutcome(status.Error(codes.Unavailable, ""))
+
+	stats := s.SyncStats()
+	assert.Equal(t, 3, stats.Failed)
+	assert.Equal(t, map[SyncErrorClass]int{SyncErrorAuth: 1, SyncErrorTransient: 2}, stats.FailedByClass)
+	assert.Equal(t, "auth: 1, transient: 2", stats.FailureSummary())
+
+	// Stats are a snapshot
+	stats.FailedByClass[SyncErrorQuota] = 1
+	assert.NotContains(t, s.SyncStats().FailedByClass, SyncErrorQuota)
+}
@@ -0,0 +1,23 @@
+package reporter
+
+import (
+	"github.com/safedep/vet/pkg/models"
+)
+
+// Frameworks are labels since the rule has no attribute for them
+const syncFrameworkLabelPrefix = "framework:"
+
+// syncFrameworkLabels returns the labels of the frameworks detected around
+// the manifest so that findings can be grouped by stack
+func syncFrameworkLabels(manifest *models.PackageManifest) []string {
+	labels := []string{}
+	if manifest == nil {
+		return labels
+	}
+
+	for _, framework := range manifest.GetFrameworks() {
+		labels = append(labels, syncFrameworkLabelPrefix+framework)
+	}
+
+	return labels
+}
@@ -0,0 +1,37 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/safedep/vet/gen/checks"
+	"github.com/safedep/vet/gen/filtersuite"
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncPolicyViolationFrameworkLabels(t *testing.T) {
+	s := newSyncQueueTestReporter(t, SyncQueueOverflowBlock, &spoolTestToolServiceClient{})
+	pkgs := newSyncQueueTestPackages("next")
+
+	event := &analyzer.AnalyzerEvent{
+		Type: analyzer.ET_FilterExpressionMatched,
+		Filter: &filtersuite.Filter{
+			Name:      "critical-vulns",
+			CheckType: checks.CheckType_CheckTypeVulnerability,
+		},
+		Package: pkgs[0],
+	}
+
+	_, req, err := s.policyViolationRequest(event)
+	assert.NoError(t, err)
+	assert.Empty(t, req.GetViolation().GetRule().GetLabels())
+
+	pkgs[0].Manifest.Frameworks = []string{"nextjs"}
+	pkgs[0].Manifest.Tags = map[string]string{"team": "web"}
+
+	_, req, err = s.policyViolationRequest(event)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tag:team=web", "framework:nextjs"}, req.GetViolation().GetRule().GetLabels())
+
+	assert.Empty(t, syncFrameworkLabels(nil))
+}
@@ -0,0 +1,102 @@
+package reporter
+
+import (
+	"sort"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	"github.com/safedep/vet/pkg/models"
+)
+
+// syncDependencyGraph converts the resolved dependency graph of a manifest
+// for publishing to ControlTower. Root packages of the manifest are direct
+// dependencies and other packages are transitive. Edges refer to packages
+// by index in the dependencies list and carry the version constraint of
+// the dependency when available. ControlTower does not have a dependency
+// scope attribute, hence scope e.g. dev of a dependency is not published.
+// Returns nil when the manifest does not have a dependency graph.
+func syncDependencyGraph(manifest *models.PackageManifest) *packagev1.PackageVersionDependencyGraph {
+	if manifest == nil || manifest.DependencyGraph == nil || !manifest.DependencyGraph.Present() {
+		return nil
+	}
+
+	nodes := manifest.DependencyGraph.GetNodes()
+
+	// Same graph is published in the same order across runs
+	sort.Slice(nodes, func(i, j int) bool {
+		return syncGraphNodeId(nodes[i]) < syncGraphNodeId(nodes[j])
+	})
+
+	graph := &packagev1.PackageVersionDependencyGraph{}
+	index := map[string]uint32{}
+
+	for _, node := range nodes {
+		if node.Data == nil {
+			continue
+		}
+
+		relation := packagev1.PackageVersionDependencyGraph_RELATION_INDIRECT
+		if node.Root {
+			relation = packagev1.PackageVersionDependencyGraph_RELATION_DIRECT
+		}
+
+		index[node.Data.Id()] = uint32(len(graph.Dependencies))
+		graph.Dependencies = append(graph.Dependencies, &packagev1.PackageVersionDependencyGraph_PackageVersionDependency{
+			PackageVersion: &packagev1.PackageVersion{
+				Package: &packagev1.Package{
+					Ecosystem: manifest.GetControlTowerSpecEcosystem(),
+					Name:      node.Data.GetName(),
+				},
+				Version: node.Data.GetVersion(),
+			},
+			Relation: relation,
+		})
+	}
+
+	for _, node := range nodes {
+		if node.Data == nil {
+			continue
+		}
+
+		from := index[node.Data.Id()]
+		for _, child := range node.Children {
+			if child == nil {
+				continue
+			}
+
+			to, ok := index[child.Id()]
+			if !ok {
+				continue
+			}
+
+			graph.DependencyRelations = append(graph.DependencyRelations,
+				&packagev1.PackageVersionDependencyGraph_PackageVersionDependencyRelation{
+					From:        from,
+					To:          to,
+					Requirement: child.GetVersionConstraint(),
+				})
+		}
+	}
+
+	return graph
+}
+
+func syncGraphNodeId(node *models.DependencyGraphNode[*models.Package]) string {
+	if node.Data == nil {
+		return ""
+	}
+
+	return node.Data.Id()
+}
+
+// attachDependencyGraph adds the dependency graph of the manifest to the
+// first package insight published for the manifest so that the graph is
+// published once per manifest
+func (s *syncReporter) attachDependencyGraph(manifest *models.PackageManifest,
+	insight *packagev1.PackageVersionInsight,
+) {
+	if _, published := s.graphs.LoadOrStore(manifest.Path, true); published {
+		return
+	}
+
+	insight.DependencyGraph = syncDependencyGraph(manifest)
+}
@@ -0,0 +1,81 @@
+package reporter
+
+import (
+	"testing"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncDependencyGraph(t *testing.T) {
+	manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+	assert.Nil(t, syncDependencyGraph(manifest))
+
+	newPackage := func(name, constraint string) *models.Package {
+		return &models.Package{
+			PackageDetails:    models.NewPackageDetail(models.EcosystemNpm, name, "1.0.0"),
+			Manifest:          manifest,
+			VersionConstraint: constraint,
+		}
+	}
+
+	express := newPackage("express", "^1.0.0")
+	body := newPackage("body-parser", "~1.0.0")
+	bytes := newPackage("bytes", "")
+	lodash := newPackage("lodash", "")
+
+	manifest.DependencyGraph.AddRootNode(express)
+	manifest.DependencyGraph.AddRootNode(lodash)
+	manifest.DependencyGraph.AddDependency(express, body)
+	manifest.DependencyGraph.AddDependency(body, bytes)
+	manifest.DependencyGraph.SetPresent(true)
+
+	graph := syncDependencyGraph(manifest)
+	require.NotNil(t, graph)
+	require.Len(t, graph.GetDependencies(), 4)
+
+	relations := map[string]packagev1.PackageVersionDependencyGraph_Relation{}
+	for _, dependency := range graph.GetDependencies() {
+		relations[dependency.GetPackageVersion().GetPackage().GetName()] = dependency.GetRelation()
+	}
+
+	assert.Equal(t, map[string]packagev1.PackageVersionDependencyGraph_Relation{
+		"express":     packagev1.PackageVersionDependencyGraph_RELATION_DIRECT,
+		"lodash":      packagev1.PackageVersionDependencyGraph_RELATION_DIRECT,
+		"body-parser": packagev1.PackageVersionDependencyGraph_RELATION_INDIRECT,
+		"bytes":       packagev1.PackageVersionDependencyGraph_RELATION_INDIRECT,
+	}, relations)
+
+	edges := []string{}
+	for _, relation := range graph.GetDependencyRelations() {
+		from := graph.GetDependencies()[relation.GetFrom()].GetPackageVersion().GetPackage().GetName()
+		to := graph.GetDependencies()[relation.GetTo()].GetPackageVersion().GetPackage().GetName()
+
+		edges = append(edges, from+" -> "+to+" "+relation.GetRequirement())
+	}
+
+	assert.ElementsMatch(t, []string{"express -> body-parser ~1.0.0", "body-parser -> bytes "}, edges)
+}
+
+func TestSyncReporterAttachDependencyGraphOnce(t *testing.T) {
+	manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+	pkg := &models.Package{
+		PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "lodash", "1.0.0"),
+		Manifest:       manifest,
+	}
+
+	manifest.DependencyGraph.AddRootNode(pkg)
+	manifest.DependencyGraph.SetPresent(true)
+
+	s := &syncReporter{}
+
+	first := &packagev1.PackageVersionInsight{}
+	s.attachDependencyGraph(manifest, first)
+	assert.Len(t, first.GetDependencyGraph().GetDependencies(), 1)
+
+	second := &packagev1.PackageVersionInsight{}
+	s.attachDependencyGraph(manifest, second)
+	assert.Nil(t, second.GetDependencyGraph())
+}
@@ -0,0 +1,363 @@
+package reporter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"buf.build/gen/go/safedep/api/grpc/go/safedep/services/controltower/v1/controltowerv1grpc"
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/safedep/vet/pkg/common/encryption"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/common/paths"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	syncStateDirName         = "vet-sync-state"
+	syncJournalFileExtension = ".journal"
+
+	// Acknowledges a publish request with the same sequence number
+	syncSpoolRecordPublished = syncSpoolRecordKind("published")
+)
+
+// DefaultSyncStateDir is the directory used for journals of in-progress
+// sync sessions when not configured by the user
+func DefaultSyncStateDir() (string, error) {
+	return paths.Resolve(paths.KindState, syncStateDirName)
+}
+
+// syncJournal records the sessions and publish requests of a sync in
+// progress along with acknowledgement of published requests. The journal
+// of a sync that is interrupted e.g. the process is killed, is used to
+// resume the sync in the same sessions. Work still queued in memory is
+// not journaled and is lost on interruption.
+type syncJournal struct {
+	m     sync.Mutex
+	spool *syncSpool
+	seq   uint64
+}
+
+func newSyncJournal(dir string, cipher encryption.Cipher) (*syncJournal, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sync state directory: %w", err)
+	}
+
+	// Owner process is in the name so that a journal of a sync that is
+	// still running is not resumed
+	pattern := fmt.Sprintf("%s-%d-*%s", time.Now().UTC().Format("20060102T150405"),
+		os.Getpid(), syncJournalFileExtension)
+
+	file, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sync journal: %w", err)
+	}
+
+	logger.Debugf("Report Sync: Journaling sync to: %s", file.Name())
+	return &syncJournal{
+		spool: &syncSpool{file: file, writer: bufio.NewWriter(file), cipher: cipher},
+	}, nil
+}
+
+func (j *syncJournal) session(sessionId string) error {
+	payload, err := protojson.Marshal(&controltowerv1.ToolSession{ToolSessionId: sessionId})
+	if err != nil {
+		return fmt.Errorf("failed to serialize session: %w", err)
+	}
+
+	return j.write(syncSpoolRecord{Kind: syncSpoolRecordSession, Payload: payload})
+}
+
+// request returns the sequence number to acknowledge the request with
+func (j *syncJournal) request(kind syncSpoolRecordKind, req proto.Message) (uint64, error) {
+	payload, err := protojson.Marshal(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize %s: %w", kind, err)
+	}
+
+	j.m.Lock()
+	j.seq++
+	seq := j.seq
+	j.m.Unlock()
+
+	return seq, j.write(syncSpoolRecord{Kind: kind, Payload: payload, Seq: seq})
+}
+
+func (j *syncJournal) published(seq uint64) {
+	if j == nil || seq == 0 {
+		return
+	}
+
+	err := j.write(syncSpoolRecord{Kind: syncSpoolRecordPublished, Seq: seq})
+	if err != nil {
+		logger.Warnf("Report Sync: Failed to journal published request: %v", err)
+	}
+}
+
+func (j *syncJournal) complete(status controltowerv1.CompleteToolSessionRequest_Status) error {
+	return j.write(syncSpoolRecord{Kind: syncSpoolRecordComplete, Status: status.String()})
+}
+
+// Records are flushed immediately so that they survive the process
+func (j *syncJournal) write(record syncSpoolRecord) error {
+	j.m.Lock()
+	defer j.m.Unlock()
+
+	err := j.spool.writeRecord(record)
+	if err != nil {
+		return err
+	}
+
+	return j.spool.writer.Flush()
+}
+
+// close retains the journal for resume unless discarded
+func (j *syncJournal) close(discard bool) {
+	j.m.Lock()
+	defer j.m.Unlock()
+
+	_ = j.spool.writer.Flush()
+	_ = j.spool.file.Close()
+
+	if discard {
+		os.Remove(j.spool.file.Name())
+		return
+	}
+
+	logger.Warnf("Report Sync: Sync is incomplete, resume using `vet cloud resume`")
+}
+
+// journalRequest returns the sequence number of the journaled request,
+// zero when journaling is disabled or failed
+func (s *syncReporter) journalRequest(kind syncSpoolRecordKind, req proto.Message) uint64 {
+	if s.journal == nil {
+		return 0
+	}
+
+	seq, err := s.journal.request(kind, req)
+	if err != nil {
+		logger.Warnf("Report Sync: Failed to journal %s: %v", kind, err)
+		return 0
+	}
+
+	return seq
+}
+
+type SyncResumeConfig struct {
+	// Directory containing journals of interrupted syncs
+	Dir string
+
+	// gRPC connection for ControlTower
+	ClientConnection *grpc.ClientConn
+
+	// Optional, defaults to DefaultSyncRetryPolicy
+	RetryPolicy SyncRetryPolicy
+
+	// Required to resume encrypted journals
+	Cipher encryption.Cipher
+}
+
+// ResumeSyncSessions publishes the requests of interrupted syncs that
+// were not acknowledged and completes their sessions. A journal is removed
+// after its sync is resumed successfully. Journals of running syncs are
+// skipped.
+func ResumeSyncSessions(ctx context.Context, config SyncResumeConfig) (SyncStats, error) {
+	stats := SyncStats{}
+	if config.ClientConnection == nil {
+		return stats, fmt.Errorf("missing gRPC client connection")
+	}
+
+	config.RetryPolicy = config.RetryPolicy.withDefaults()
+
+	files, err := filepath.Glob(filepath.Join(config.Dir, "*"+syncJournalFileExtension))
+	if err != nil {
+		return stats, fmt.Errorf("failed to list sync journals: %w", err)
+	}
+
+	sort.Strings(files)
+
+	client := controltowerv1grpc.NewToolServiceClient(config.ClientConnection)
+
+	var errs []error
+	for _, file := range files {
+		if pid, ok := syncJournalOwner(file); ok && syncJournalOwnerRunning(pid) {
+			logger.Debugf("Report Sync: Skipping journal of running sync: %s", file)
+			continue
+		}
+
+		err := resumeSyncJournal(ctx, client, config.RetryPolicy, config.Cipher, file, &stats)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to resume %s: %w", file, err))
+			continue
+		}
+
+		if err := os.Remove(file); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove resumed sync journal: %w", err))
+		}
+	}
+
+	return stats, errors.Join(errs...)
+}
+
+func resumeSyncJournal(ctx context.Context, client controltowerv1grpc.ToolServiceClient,
+	retryPolicy SyncRetryPolicy, cipher encryption.Cipher, path string, stats *SyncStats,
+) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), syncSpoolMaxRecordSize)
+
+	sessions := []*controltowerv1.ToolSession{}
+	requests := []syncSpoolRecord{}
+	published := map[uint64]bool{}
+
+	// Journal without a complete record is from a sync that was killed
+	// before all work was published
+	status := controltowerv1.CompleteToolSessionRequest_STATUS_ERROR
+
+	// Last record is truncated when the process is killed while writing
+	var truncated error
+
+	for scanner.Scan() {
+		if truncated != nil {
+			return truncated
+		}
+
+		record, err := readSyncSpoolRecord(cipher, scanner.Bytes())
+		if err != nil {
+			truncated = err
+			continue
+		}
+
+		switch record.Kind {
+		case syncSpoolRecordSession:
+			var session controltowerv1.ToolSession
+			if err := protojson.Unmarshal(record.Payload, &session); err != nil {
+				return fmt.Errorf("failed to parse session record: %w", err)
+			}
+
+			sessions = append(sessions, &session)
+		case syncSpoolRecordPackageInsight, syncSpoolRecordPolicyViolation:
+			requests = append(requests, record)
+		case syncSpoolRecordPublished:
+			published[record.Seq] = true
+		case syncSpoolRecordComplete:
+			if v, ok := controltowerv1.CompleteToolSessionRequest_Status_value[record.Status]; ok {
+				status = controltowerv1.CompleteToolSessionRequest_Status(v)
+			}
+		default:
+			logger.Warnf("Report Sync: Ignoring unknown journal record: %s", record.Kind)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read sync journal: %w", err)
+	}
+
+	failed := 0
+	for _, record := range requests {
+		if published[record.Seq] {
+			continue
+		}
+
+		// Requests carry the session they belong to
+		err := retryPolicy.run(ctx, string(record.Kind)+" publish", func(ctx context.Context) error {
+			return publishSyncJournalRecord(ctx, client, record)
+		})
+
+		stats.record(err)
+		if err != nil {
+			logger.Errorf("failed to resume %s: %v", record.Kind, err)
+			failed++
+		}
+	}
+
+	// Sessions are left open so that a later attempt publishes in them
+	if failed > 0 {
+		return fmt.Errorf("%d records failed to publish", failed)
+	}
+
+	for _, session := range sessions {
+		logger.Debugf("Report Sync: Completing resumed tool session: %s with status: %s",
+			session.GetToolSessionId(), status)
+
+		_, err := client.CompleteToolSession(ctx, &controltowerv1.CompleteToolSessionRequest{
+			ToolSession: session,
+			Status:      status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to complete tool session: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func publishSyncJournalRecord(ctx context.Context, client controltowerv1grpc.ToolServiceClient,
+	record syncSpoolRecord,
+) error {
+	switch record.Kind {
+	case syncSpoolRecordPackageInsight:
+		var req controltowerv1.PublishPackageInsightRequest
+		if err := protojson.Unmarshal(record.Payload, &req); err != nil {
+			return fmt.Errorf("failed to parse package insight record: %w", err)
+		}
+
+		_, err := client.PublishPackageInsight(ctx, &req)
+		return err
+	case syncSpoolRecordPolicyViolation:
+		var req controltowerv1.PublishPolicyViolationRequest
+		if err := protojson.Unmarshal(record.Payload, &req); err != nil {
+			return fmt.Errorf("failed to parse policy violation record: %w", err)
+		}
+
+		_, err := client.PublishPolicyViolation(ctx, &req)
+		return err
+	default:
+		return fmt.Errorf("unknown journal record: %s", record.Kind)
+	}
+}
+
+// syncJournalOwner returns the process ID in the journal file name
+func syncJournalOwner(path string) (int, bool) {
+	parts := strings.SplitN(filepath.Base(path), "-", 3)
+	if len(parts) != 3 {
+		return 0, false
+	}
+
+	pid, err := strconv.Atoi(parts[1])
+	return pid, err == nil
+}
+
+// syncJournalOwnerRunning is false on platforms where liveness of
+// a process can not be checked
+func syncJournalOwnerRunning(pid int) bool {
+	if pid == os.Getpid() {
+		return true
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, os.ErrPermission)
+}
@@ -0,0 +1,91 @@
+package reporter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestJournal(t *testing.T, dir string) string {
+	journal, err := newSyncJournal(dir, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, journal.session("session-1"))
+
+	for _, name := range []string{"lodash", "express", "react"} {
+		seq, err := journal.request(syncSpoolRecordPackageInsight, &controltowerv1.PublishPackageInsightRequest{
+			ToolSession: &controltowerv1.ToolSession{ToolSessionId: "session-1"},
+			PackageVersion: &packagev1.PackageVersion{
+				Package: &packagev1.Package{Name: name},
+				Version: "1.0.0",
+			},
+		})
+		require.NoError(t, err)
+
+		if name == "lodash" {
+			journal.published(seq)
+		}
+	}
+
+	// Process killed while writing a record
+	_, err = journal.spool.writer.WriteString(`{"kind":"package_ins`)
+	require.NoError(t, err)
+
+	journal.close(false)
+	return journal.spool.file.Name()
+}
+
+func TestSyncJournalResume(t *testing.T) {
+	path := writeTestJournal(t, t.TempDir())
+
+	client := &spoolTestToolServiceClient{}
+	stats := SyncStats{}
+
+	err := resumeSyncJournal(context.Background(), client, DefaultSyncRetryPolicy(), nil, path, &stats)
+	assert.NoError(t, err)
+
+	// Only requests that were not acknowledged are published
+	assert.Equal(t, []string{"session-1/express", "session-1/react"}, client.packages)
+	assert.Equal(t, 2, stats.Published)
+	assert.Empty(t, client.projects)
+	assert.Equal(t, []controltowerv1.CompleteToolSessionRequest_Status{
+		controltowerv1.CompleteToolSessionRequest_STATUS_ERROR,
+	}, client.statuses)
+}
+
+func TestSyncJournalResumeFailureKeepsSessionOpen(t *testing.T) {
+	path := writeTestJournal(t, t.TempDir())
+
+	client := &spoolTestToolServiceClient{publishErr: assert.AnError}
+	stats := SyncStats{}
+
+	err := resumeSyncJournal(context.Background(), client, DefaultSyncRetryPolicy(), nil, path, &stats)
+	assert.ErrorContains(t, err, "2 records failed")
+	assert.Equal(t, 2, stats.Failed)
+	assert.Empty(t, client.statuses)
+}
+
+func TestSyncJournalOwner(t *testing.T) {
+	dir := t.TempDir()
+
+	journal, err := newSyncJournal(dir, nil)
+	require.NoError(t, err)
+	journal.close(true)
+
+	pid, ok := syncJournalOwner(journal.spool.file.Name())
+	assert.True(t, ok)
+	assert.Equal(t, os.Getpid(), pid)
+	assert.True(t, syncJournalOwnerRunning(pid))
+
+	_, err = os.Stat(journal.spool.file.Name())
+	assert.True(t, os.IsNotExist(err))
+
+	_, ok = syncJournalOwner(filepath.Join(dir, "invalid.journal"))
+	assert.False(t, ok)
+}
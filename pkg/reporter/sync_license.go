@@ -0,0 +1,122 @@
+package reporter
+
+import (
+	"strings"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	"github.com/safedep/vet/gen/insightapi"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/license"
+)
+
+// syncLicenses converts the licenses in insights, which may be SPDX
+// expressions, into license metadata. An expression is published as a
+// whole so that a choice of licenses (OR) is not mistaken for a package
+// licensed under all of them. A license that is not a valid expression
+// is published as is.
+func syncLicenses(licenses []insightapi.License) []*packagev1.LicenseMeta {
+	metas := []*packagev1.LicenseMeta{}
+	seen := map[string]bool{}
+
+	add := func(meta *packagev1.LicenseMeta) {
+		key := strings.ToLower(meta.GetLicenseId())
+		if !seen[key] {
+			seen[key] = true
+			metas = append(metas, meta)
+		}
+	}
+
+	for _, l := range licenses {
+		expression, err := license.ParseExpression(string(l))
+		if err != nil {
+			logger.Debugf("Report Sync: Publishing license as is: %v", err)
+
+			add(&packagev1.LicenseMeta{LicenseId: string(l), Name: string(l)})
+			continue
+		}
+
+		add(syncExpressionMeta(expression))
+	}
+
+	return metas
+}
+
+// syncExpressionMeta returns the metadata of an expression. A condition of
+// an OR expression holds when it holds for any of the alternatives since the
+// licensee may elect one. A condition of an AND expression holds only when
+// it holds for all the licenses.
+func syncExpressionMeta(expression *license.Expression) *packagev1.LicenseMeta {
+	if !expression.IsCompound() {
+		return syncLicenseMeta(expression)
+	}
+
+	meta := &packagev1.LicenseMeta{
+		LicenseId:            expression.String(),
+		OsiApproved:          expression.Operator == license.OperatorAnd,
+		FsfApproved:          expression.Operator == license.OperatorAnd,
+		SaasCompatible:       expression.Operator == license.OperatorAnd,
+		CommercialUseAllowed: expression.Operator == license.OperatorAnd,
+	}
+
+	combine := func(a, b bool) bool {
+		if expression.Operator == license.OperatorOr {
+			return a || b
+		}
+
+		return a && b
+	}
+
+	names := make([]string, 0, len(expression.Operands))
+	for _, operand := range expression.Operands {
+		operandMeta := syncExpressionMeta(operand)
+
+		name := operandMeta.GetName()
+		if operand.IsCompound() && operand.Operator != expression.Operator {
+			name = "(" + name + ")"
+		}
+
+		names = append(names, name)
+
+		meta.OsiApproved = combine(meta.OsiApproved, operandMeta.GetOsiApproved())
+		meta.FsfApproved = combine(meta.FsfApproved, operandMeta.GetFsfApproved())
+		meta.SaasCompatible = combine(meta.SaasCompatible, operandMeta.GetSaasCompatible())
+		meta.CommercialUseAllowed = combine(meta.CommercialUseAllowed, operandMeta.GetCommercialUseAllowed())
+	}
+
+	meta.Name = strings.Join(names, " "+string(expression.Operator)+" ")
+	return meta
+}
+
+func syncLicenseMeta(leaf *license.Expression) *packagev1.LicenseMeta {
+	meta := &packagev1.LicenseMeta{
+		LicenseId: leaf.License,
+		Name:      leaf.String(),
+	}
+
+	// GPL-2.0+ is the deprecated form of GPL-2.0-or-later
+	conditions, ok := license.LookupConditions(leaf.License + "-or-later")
+	if !leaf.OrLater || !ok {
+		conditions, ok = license.LookupConditions(leaf.License)
+	}
+
+	if !ok {
+		return meta
+	}
+
+	meta.Name = conditions.Name
+	if leaf.OrLater && !strings.HasSuffix(conditions.Name, "or later") {
+		meta.Name += " or later"
+	}
+
+	if leaf.Exception != "" {
+		meta.Name += " with " + leaf.Exception
+	}
+
+	meta.OsiApproved = conditions.OsiApproved
+	meta.FsfApproved = conditions.FsfApproved
+	meta.SaasCompatible = conditions.SaasCompatible
+	meta.CommercialUseAllowed = conditions.CommercialUseAllowed
+	meta.ReferenceUrl = license.ReferenceUrl(leaf.License)
+
+	return meta
+}
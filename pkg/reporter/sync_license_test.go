@@ -0,0 +1,98 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/safedep/vet/gen/insightapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncLicenses(t *testing.T) {
+	metas := syncLicenses([]insightapi.License{
+		"MIT",
+		"mit",
+		"GPL-2.0+ WITH Classpath-exception-2.0",
+		"LicenseRef-Custom",
+		"MIT AND",
+	})
+
+	assert.Len(t, metas, 4)
+
+	assert.Equal(t, "MIT", metas[0].GetLicenseId())
+	assert.Equal(t, "MIT License", metas[0].GetName())
+	assert.True(t, metas[0].GetOsiApproved())
+	assert.True(t, metas[0].GetCommercialUseAllowed())
+	assert.Equal(t, "https://spdx.org/licenses/MIT.html", metas[0].GetReferenceUrl())
+
+	assert.Equal(t, "GPL-2.0", metas[1].GetLicenseId())
+	assert.Equal(t, "GNU General Public License v2.0 or later with Classpath-exception-2.0",
+		metas[1].GetName())
+	assert.True(t, metas[1].GetFsfApproved())
+
+	assert.Equal(t, "LicenseRef-Custom", metas[2].GetLicenseId())
+	assert.Equal(t, "LicenseRef-Custom", metas[2].GetName())
+	assert.False(t, metas[2].GetOsiApproved())
+	assert.Empty(t, metas[2].GetReferenceUrl())
+
+	assert.Equal(t, "MIT AND", metas[3].GetLicenseId())
+	assert.Equal(t, "MIT AND", metas[3].GetName())
+
+	assert.Empty(t, syncLicenses(nil))
+}
+
+func TestSyncLicensesExpression(t *testing.T) {
+	cases := []struct {
+		name           string
+		license        insightapi.License
+		licenseId      string
+		licenseName    string
+		saasCompatible bool
+		osiApproved    bool
+	}{
+		{
+			"OR is a choice of licenses",
+			"MIT OR AGPL-3.0-only",
+			"MIT OR AGPL-3.0-only",
+			"MIT License OR GNU Affero General Public License v3.0 only",
+			true,
+			true,
+		},
+		{
+			"AND requires all licenses",
+			"MIT AND AGPL-3.0-only",
+			"MIT AND AGPL-3.0-only",
+			"MIT License AND GNU Affero General Public License v3.0 only",
+			false,
+			true,
+		},
+		{
+			"OR with an unknown license",
+			"LicenseRef-Custom OR MIT",
+			"LicenseRef-Custom OR MIT",
+			"LicenseRef-Custom OR MIT License",
+			true,
+			true,
+		},
+		{
+			"Nested expression",
+			"(MIT AND AGPL-3.0-only) OR LicenseRef-Custom",
+			"(MIT AND AGPL-3.0-only) OR LicenseRef-Custom",
+			"(MIT License AND GNU Affero General Public License v3.0 only) OR LicenseRef-Custom",
+			false,
+			true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			metas := syncLicenses([]insightapi.License{test.license})
+
+			assert.Len(t, metas, 1)
+			assert.Equal(t, test.licenseId, metas[0].GetLicenseId())
+			assert.Equal(t, test.licenseName, metas[0].GetName())
+			assert.Equal(t, test.saasCompatible, metas[0].GetSaasCompatible())
+			assert.Equal(t, test.osiApproved, metas[0].GetOsiApproved())
+			assert.Empty(t, metas[0].GetReferenceUrl())
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	policyv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/policy/v1"
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/malysis"
+	"github.com/safedep/vet/pkg/models"
+)
+
+const (
+	syncMalwareRuleMalicious  = "malicious-package"
+	syncMalwareRuleSuspicious = "suspicious-package"
+
+	syncMalwareLabelVerified = "verified"
+)
+
+func (s *syncReporter) queueMalware(pkg *models.Package) {
+	s.queue(&workItem{malware: pkg})
+}
+
+func (s *syncReporter) syncMalware(pkg *models.Package) error {
+	session, req, err := s.malwareVerdictRequest(pkg)
+	if err != nil {
+		return err
+	}
+
+	return s.publishPolicyViolation(session, req)
+}
+
+// syncMalwareVerdict is true for packages with a malicious or suspicious
+// verdict from malware analysis
+func syncMalwareVerdict(pkg *models.Package) bool {
+	ma := pkg.MalwareAnalysis
+	return ma != nil && (ma.IsMalware || ma.IsSuspicious)
+}
+
+// malwareVerdictRequest publishes the verdict of malware analysis as a
+// violation of a malware rule with the behaviors found during analysis
+// as evidences. Returns errSyncSkipped for packages without a verdict and
+// for packages stripped by redaction.
+func (s *syncReporter) malwareVerdictRequest(pkg *models.Package) (*syncSession,
+	*controltowerv1.PublishPolicyViolationRequest, error,
+) {
+	if pkg.Manifest == nil || !syncMalwareVerdict(pkg) || !s.syncRedactedPackage(pkg.GetName()) {
+		return nil, nil, errSyncSkipped
+	}
+
+	session, err := s.manifestSession(pkg.Manifest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get session for package: %s/%s/%s: %w",
+			pkg.Manifest.Ecosystem, pkg.GetName(), pkg.GetVersion(), err)
+	}
+
+	logger.Debugf("Report Sync: Publishing malware verdict for package: %s/%s/%s/%s",
+		pkg.Manifest.GetControlTowerSpecEcosystem(), pkg.Manifest.GetDisplayPath(), pkg.GetName(), pkg.GetVersion())
+
+	req := controltowerv1.PublishPolicyViolationRequest{
+		ToolSession: &controltowerv1.ToolSession{
+			ToolSessionId: session.sessionId,
+		},
+
+		Manifest: syncPackageManifest(pkg.Manifest),
+
+		PackageVersion: &packagev1.PackageVersion{
+			Package: &packagev1.Package{
+				Ecosystem: pkg.Manifest.GetControlTowerSpecEcosystem(),
+				Name:      pkg.Name,
+			},
+
+			Version: pkg.Version,
+		},
+
+		Violation: syncMalwareViolation(pkg.MalwareAnalysis),
+	}
+
+	req.Violation.Rule.Labels = append(req.Violation.Rule.Labels,
+		s.tagLabels(pkg.Manifest, req.Violation.Rule.GetName())...)
+	req.Violation.Rule.Labels = append(req.Violation.Rule.Labels,
+		syncFrameworkLabels(pkg.Manifest)...)
+
+	s.redactPolicyViolation(&req)
+
+	return session, &req, nil
+}
+
+func syncMalwareViolation(ma *models.MalwareAnalysisResult) *policyv1.Violation {
+	report := ma.Report
+
+	reportId := report.GetReportId()
+	if reportId == "" {
+		reportId = ma.AnalysisId
+	}
+
+	rule := &policyv1.Rule{
+		Name:        syncMalwareRuleSuspicious,
+		Check:       policyv1.RuleCheck_RULE_CHECK_MALWARE,
+		Description: report.GetInference().GetSummary(),
+		Value:       reportId,
+		References:  []*policyv1.Rule_Reference{},
+		Labels:      []string{},
+	}
+
+	if ma.IsMalware {
+		rule.Name = syncMalwareRuleMalicious
+	}
+
+	if reportId != "" {
+		rule.References = append(rule.References, &policyv1.Rule_Reference{
+			Url: malysis.ReportURL(reportId),
+		})
+	}
+
+	// Confidence is a label since the rule has no attribute for it
+	confidence := strings.TrimPrefix(report.GetInference().GetConfidence().String(), "CONFIDENCE_")
+	rule.Labels = append(rule.Labels, "confidence:"+strings.ToLower(confidence))
+
+	if ma.VerificationRecord != nil {
+		rule.Labels = append(rule.Labels, syncMalwareLabelVerified)
+	}
+
+	violation := &policyv1.Violation{
+		Rule:       rule,
+		Evidences:  []*policyv1.ViolationEvidence{},
+		DetectedAt: report.GetAnalyzedAt(),
+	}
+
+	for _, fe := range report.GetFileEvidences() {
+		location := fe.GetFileKey()
+		if location != "" && fe.GetLine() > 0 {
+			location = fmt.Sprintf("%s:%d", location, fe.GetLine())
+		}
+
+		violation.Evidences = append(violation.Evidences, &policyv1.ViolationEvidence{
+			Evidence: syncMalwareEvidence(fe.GetEvidence().GetBehavior(), fe.GetEvidence().GetTitle(), location),
+		})
+	}
+
+	for _, pe := range report.GetProjectEvidences() {
+		violation.Evidences = append(violation.Evidences, &policyv1.ViolationEvidence{
+			Evidence: syncMalwareEvidence(pe.GetEvidence().GetBehavior(), pe.GetEvidence().GetTitle(),
+				pe.GetProject().GetUrl()),
+		})
+	}
+
+	return violation
+}
+
+func syncMalwareEvidence(behavior, title, location string) string {
+	parts := []string{}
+	for _, part := range []string{behavior, title, location} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, " | ")
+}
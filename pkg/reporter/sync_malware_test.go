@@ -0,0 +1,84 @@
+package reporter
+
+import (
+	"testing"
+
+	malysisv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/malysis/v1"
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	policyv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/policy/v1"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncMalwareViolation(t *testing.T) {
+	report := &malysisv1.Report{
+		ReportId: "01JREPORT",
+		Inference: &malysisv1.Report_Inference{
+			IsMalware:  true,
+			Confidence: malysisv1.Report_Evidence_CONFIDENCE_HIGH,
+			Summary:    "Exfiltrates environment variables",
+		},
+		FileEvidences: []*malysisv1.Report_FileEvidence{
+			{
+				FileKey: "package/index.js",
+				Line:    12,
+				Evidence: &malysisv1.Report_Evidence{
+					Behavior: "network",
+					Title:    "Sends environment to remote host",
+				},
+			},
+		},
+		ProjectEvidences: []*malysisv1.Report_ProjectEvidence{
+			{
+				Project:  &packagev1.Project{Url: "https://github.com/example/example"},
+				Evidence: &malysisv1.Report_Evidence{Title: "Repository does not exist"},
+			},
+		},
+	}
+
+	violation := syncMalwareViolation(&models.MalwareAnalysisResult{
+		AnalysisId:         "01JANALYSIS",
+		IsMalware:          true,
+		Report:             report,
+		VerificationRecord: &malysisv1.VerificationRecord{},
+	})
+
+	rule := violation.GetRule()
+	assert.Equal(t, syncMalwareRuleMalicious, rule.GetName())
+	assert.Equal(t, policyv1.RuleCheck_RULE_CHECK_MALWARE, rule.GetCheck())
+	assert.Equal(t, "Exfiltrates environment variables", rule.GetDescription())
+	assert.Equal(t, "01JREPORT", rule.GetValue())
+	assert.Equal(t, []string{"confidence:high", syncMalwareLabelVerified}, rule.GetLabels())
+	assert.Len(t, rule.GetReferences(), 1)
+	assert.Contains(t, rule.GetReferences()[0].GetUrl(), "01JREPORT")
+
+	evidences := []string{}
+	for _, e := range violation.GetEvidences() {
+		evidences = append(evidences, e.GetEvidence())
+	}
+
+	assert.Equal(t, []string{
+		"network | Sends environment to remote host | package/index.js:12",
+		"Repository does not exist | https://github.com/example/example",
+	}, evidences)
+}
+
+func TestSyncMalwareViolationSuspicious(t *testing.T) {
+	violation := syncMalwareViolation(&models.MalwareAnalysisResult{
+		AnalysisId:   "01JANALYSIS",
+		IsSuspicious: true,
+	})
+
+	assert.Equal(t, syncMalwareRuleSuspicious, violation.GetRule().GetName())
+	assert.Equal(t, "01JANALYSIS", violation.GetRule().GetValue())
+	assert.Equal(t, []string{"confidence:unspecified"}, violation.GetRule().GetLabels())
+	assert.Empty(t, violation.GetEvidences())
+}
+
+func TestSyncMalwareVerdict(t *testing.T) {
+	assert.False(t, syncMalwareVerdict(&models.Package{}))
+	assert.False(t, syncMalwareVerdict(&models.Package{MalwareAnalysis: &models.MalwareAnalysisResult{}}))
+	assert.True(t, syncMalwareVerdict(&models.Package{
+		MalwareAnalysis: &models.MalwareAnalysisResult{IsSuspicious: true},
+	}))
+}
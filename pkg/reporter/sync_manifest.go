@@ -0,0 +1,60 @@
+package reporter
+
+import (
+	"net/url"
+	"strings"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	"github.com/safedep/vet/pkg/models"
+)
+
+// syncPackageManifest builds the manifest published along with package
+// insights and violations. The manifest digest and SBOM document reference
+// do not have a field in the Control Tower API yet and are available only
+// in the JSON report.
+func syncPackageManifest(manifest *models.PackageManifest) *packagev1.PackageManifest {
+	namespace := manifest.GetSource().GetNamespace()
+
+	return &packagev1.PackageManifest{
+		Ecosystem: manifest.GetControlTowerSpecEcosystem(),
+		Type:      syncManifestType(manifest),
+		Source:    syncManifestSource(manifest),
+		Namespace: &namespace,
+		Name:      manifest.GetDisplayPath(),
+	}
+}
+
+// An SBOM is not specific to a package manager
+func syncManifestType(manifest *models.PackageManifest) packagev1.PackageManifestType {
+	switch manifest.GetKind() {
+	case models.ManifestKindLockfile:
+		return packagev1.PackageManifestType_PACKAGE_MANIFEST_TYPE_PACKAGE_MANAGER_SPECIFIC
+	default:
+		return packagev1.PackageManifestType_PACKAGE_MANIFEST_TYPE_UNSPECIFIED
+	}
+}
+
+func syncManifestSource(manifest *models.PackageManifest) packagev1.PackageManifestSource {
+	source := manifest.GetSource()
+
+	switch source.GetType() {
+	case models.ManifestSourceLocal:
+		return packagev1.PackageManifestSource_PACKAGE_MANIFEST_SOURCE_FILE
+	case models.ManifestSourceGitRepository:
+		u, err := url.Parse(source.GetNamespace())
+		if err != nil {
+			return packagev1.PackageManifestSource_PACKAGE_MANIFEST_SOURCE_UNSPECIFIED
+		}
+
+		switch strings.ToLower(u.Hostname()) {
+		case "github.com":
+			return packagev1.PackageManifestSource_PACKAGE_MANIFEST_SOURCE_GITHUB
+		case "gitlab.com":
+			return packagev1.PackageManifestSource_PACKAGE_MANIFEST_SOURCE_GITLAB
+		default:
+			return packagev1.PackageManifestSource_PACKAGE_MANIFEST_SOURCE_URL
+		}
+	default:
+		return packagev1.PackageManifestSource_PACKAGE_MANIFEST_SOURCE_UNSPECIFIED
+	}
+}
@@ -0,0 +1,61 @@
+package reporter
+
+import (
+	"testing"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncPackageManifest(t *testing.T) {
+	cases := []struct {
+		name         string
+		manifest     *models.PackageManifest
+		manifestType packagev1.PackageManifestType
+		source       packagev1.PackageManifestSource
+	}{
+		{
+			"local lockfile",
+			models.NewPackageManifestFromLocal("/app/package-lock.json", models.EcosystemNpm),
+			packagev1.PackageManifestType_PACKAGE_MANIFEST_TYPE_PACKAGE_MANAGER_SPECIFIC,
+			packagev1.PackageManifestSource_PACKAGE_MANIFEST_SOURCE_FILE,
+		},
+		{
+			"local sbom",
+			models.NewPackageManifestFromLocal("/app/bom.json", models.EcosystemCyDxSBOM),
+			packagev1.PackageManifestType_PACKAGE_MANIFEST_TYPE_UNSPECIFIED,
+			packagev1.PackageManifestSource_PACKAGE_MANIFEST_SOURCE_FILE,
+		},
+		{
+			"github repository",
+			models.NewPackageManifestFromGitHub("https://github.com/safedep/vet", "go.mod", "/tmp/vet/go.mod", models.EcosystemGo),
+			packagev1.PackageManifestType_PACKAGE_MANIFEST_TYPE_PACKAGE_MANAGER_SPECIFIC,
+			packagev1.PackageManifestSource_PACKAGE_MANIFEST_SOURCE_GITHUB,
+		},
+		{
+			"gitlab repository",
+			models.NewPackageManifestFromGitHub("https://gitlab.com/safedep/vet", "go.mod", "/tmp/vet/go.mod", models.EcosystemGo),
+			packagev1.PackageManifestType_PACKAGE_MANIFEST_TYPE_PACKAGE_MANAGER_SPECIFIC,
+			packagev1.PackageManifestSource_PACKAGE_MANIFEST_SOURCE_GITLAB,
+		},
+		{
+			"purl",
+			models.NewPackageManifestFromPurl("pkg:npm/lodash@4.17.21", models.EcosystemNpm),
+			packagev1.PackageManifestType_PACKAGE_MANIFEST_TYPE_PACKAGE_MANAGER_SPECIFIC,
+			packagev1.PackageManifestSource_PACKAGE_MANIFEST_SOURCE_UNSPECIFIED,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			manifest := syncPackageManifest(test.manifest)
+
+			assert.Equal(t, test.manifest.GetControlTowerSpecEcosystem(), manifest.GetEcosystem())
+			assert.Equal(t, test.manifestType, manifest.GetType())
+			assert.Equal(t, test.source, manifest.GetSource())
+			assert.Equal(t, test.manifest.GetSource().GetNamespace(), manifest.GetNamespace())
+			assert.Equal(t, test.manifest.GetDisplayPath(), manifest.GetName())
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package reporter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const syncMetricsNamespace = "vet_sync"
+
+// syncMetrics are the Prometheus metrics of a sync reporter. A nil
+// syncMetrics discards observations.
+type syncMetrics struct {
+	queued    prometheus.Counter
+	published prometheus.Counter
+	failed    prometheus.Counter
+	skipped   prometheus.Counter
+	dropped   prometheus.Counter
+	retries   *prometheus.CounterVec
+	latency   *prometheus.HistogramVec
+}
+
+// newSyncMetrics registers the metrics with the registry. Metrics registered
+// by a previous reporter e.g. of an earlier scan in a long running process
+// are reused so that counters are cumulative.
+func newSyncMetrics(registry prometheus.Registerer) (*syncMetrics, error) {
+	if registry == nil {
+		return nil, nil
+	}
+
+	counter := func(name, help string) prometheus.Counter {
+		return prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: syncMetricsNamespace,
+			Name:      name,
+			Help:      help,
+		})
+	}
+
+	m := &syncMetrics{
+		queued:    counter("queued_total", "Items queued for publishing to ControlTower"),
+		published: counter("published_total", "Items published to ControlTower"),
+		failed:    counter("failed_total", "Items failed to publish to ControlTower"),
+		skipped:   counter("skipped_total", "Items not published as not supported or cancelled"),
+		dropped:   counter("dropped_total", "Items not published as the queue was full"),
+
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: syncMetricsNamespace,
+			Name:      "retries_total",
+			Help:      "Requests to ControlTower retried on transient failure",
+		}, []string{"operation"}),
+
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: syncMetricsNamespace,
+			Name:      "publish_duration_seconds",
+			Help:      "Latency of publishing to ControlTower including retries",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "status"}),
+	}
+
+	var errs []error
+	register := func(c prometheus.Collector) prometheus.Collector {
+		existing, err := registerSyncCollector(registry, c)
+		errs = append(errs, err)
+		return existing
+	}
+
+	m.queued = register(m.queued).(prometheus.Counter)
+	m.published = register(m.published).(prometheus.Counter)
+	m.failed = register(m.failed).(prometheus.Counter)
+	m.skipped = register(m.skipped).(prometheus.Counter)
+	m.dropped = register(m.dropped).(prometheus.Counter)
+	m.retries = register(m.retries).(*prometheus.CounterVec)
+	m.latency = register(m.latency).(*prometheus.HistogramVec)
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// registerSyncCollector returns the collector already registered in place
+// of an equivalent collector
+func registerSyncCollector(registry prometheus.Registerer, c prometheus.Collector) (prometheus.Collector, error) {
+	err := registry.Register(c)
+	if err == nil {
+		return c, nil
+	}
+
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		return registered.ExistingCollector, nil
+	}
+
+	return c, fmt.Errorf("failed to register sync metric: %w", err)
+}
+
+func (m *syncMetrics) queue() {
+	if m == nil {
+		return
+	}
+
+	m.queued.Inc()
+}
+
+// outcome is counted in the same way as SyncStats
+func (m *syncMetrics) outcome(err error) {
+	if m == nil {
+		return
+	}
+
+	switch {
+	case err == nil:
+		m.published.Inc()
+	case errors.Is(err, errSyncSkipped):
+		m.skipped.Inc()
+	case errors.Is(err, errSyncDropped):
+		m.dropped.Inc()
+	default:
+		m.failed.Inc()
+	}
+}
+
+func (m *syncMetrics) retry(op string) {
+	if m == nil {
+		return
+	}
+
+	m.retries.WithLabelValues(syncMetricsOperation(op)).Inc()
+}
+
+func (m *syncMetrics) publish(op string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+
+	m.latency.WithLabelValues(syncMetricsOperation(op), status).Observe(duration.Seconds())
+}
+
+// syncMetricsOperation converts an operation e.g. package insight publish
+// to a label value e.g. package_insight
+func syncMetricsOperation(op string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(op, " publish"), " ", "_")
+}
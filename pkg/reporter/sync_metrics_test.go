@@ -0,0 +1,76 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSyncMetricsOutcome(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	metrics, err := newSyncMetrics(registry)
+	require.NoError(t, err)
+
+	metrics.queue()
+	metrics.queue()
+	metrics.outcome(nil)
+	metrics.outcome(errors.New("failed"))
+	metrics.outcome(errSyncSkipped)
+	metrics.outcome(errSyncDropped)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.queued))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.published))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.failed))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.skipped))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.dropped))
+
+	// Metrics of a reporter created later with the same registry are cumulative
+	next, err := newSyncMetrics(registry)
+	require.NoError(t, err)
+
+	next.queue()
+	assert.Equal(t, float64(3), testutil.ToFloat64(metrics.queued))
+}
+
+func TestSyncMetricsRetryPolicy(t *testing.T) {
+	metrics, err := newSyncMetrics(prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	policy := SyncRetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}.withDefaults()
+	policy.metrics = metrics
+
+	attempts := 0
+	err = policy.run(context.Background(), "package insight publish", func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return status.Error(codes.Unavailable, "unavailable")
+		}
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.retries.WithLabelValues("package_insight")))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.latency))
+}
+
+func TestNilSyncMetrics(t *testing.T) {
+	metrics, err := newSyncMetrics(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, metrics)
+
+	assert.NotPanics(t, func() {
+		metrics.queue()
+		metrics.outcome(nil)
+		metrics.retry("package insight publish")
+	})
+}
@@ -0,0 +1,32 @@
+package reporter
+
+import (
+	"net/url"
+	"strings"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+)
+
+// syncProjectSource returns the source type of a project by the host of
+// its source URL. Self hosted repositories are private.
+func syncProjectSource(projectUrl string) packagev1.ProjectSourceType {
+	if projectUrl == "" {
+		return packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_UNSPECIFIED
+	}
+
+	u, err := url.Parse(projectUrl)
+	if err != nil || u.Hostname() == "" {
+		return packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_UNSPECIFIED
+	}
+
+	switch strings.ToLower(u.Hostname()) {
+	case "github.com":
+		return packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_GITHUB
+	case "gitlab.com":
+		return packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_GITLAB
+	case "bitbucket.org":
+		return packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_BITBUCKET
+	default:
+		return packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_PRIVATE
+	}
+}
@@ -0,0 +1,29 @@
+package reporter
+
+import (
+	"testing"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncProjectSource(t *testing.T) {
+	cases := []struct {
+		name   string
+		url    string
+		source packagev1.ProjectSourceType
+	}{
+		{"empty", "", packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_UNSPECIFIED},
+		{"github", "https://github.com/safedep/vet", packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_GITHUB},
+		{"gitlab", "https://GitLab.com/safedep/vet", packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_GITLAB},
+		{"bitbucket", "https://bitbucket.org/safedep/vet", packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_BITBUCKET},
+		{"self hosted", "https://git.example.com/team/app", packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_PRIVATE},
+		{"not a url", "vet", packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_UNSPECIFIED},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.source, syncProjectSource(test.url))
+		})
+	}
+}
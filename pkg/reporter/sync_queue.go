@@ -0,0 +1,240 @@
+package reporter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/safedep/vet/pkg/common/logger"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// SyncQueueOverflow is the strategy used when the work queue of the sync
+// reporter is full
+type SyncQueueOverflow string
+
+const (
+	// Wait for a worker to pick up queued work. This stalls the scan
+	// when ControlTower is slower than the scan.
+	SyncQueueOverflowBlock = SyncQueueOverflow("block")
+
+	// Drop the work and count it in sync stats
+	SyncQueueOverflowDrop = SyncQueueOverflow("drop")
+
+	// Write the request to a file and publish after the queue is drained
+	SyncQueueOverflowSpill = SyncQueueOverflow("spill")
+)
+
+// Work not published because the queue was full
+var errSyncDropped = errors.New("dropped sync, queue is full")
+
+// queue adds work to the queue as per the overflow strategy
+func (s *syncReporter) queue(item *workItem) {
+	s.wg.Add(1)
+	s.metrics.queue()
+
+	s.statsMu.Lock()
+	s.queued++
+	s.statsMu.Unlock()
+
+	s.progress()
+
+	if s.config.QueueOverflow == "" || s.config.QueueOverflow == SyncQueueOverflowBlock {
+		s.workQueue <- item
+		return
+	}
+
+	select {
+	case s.workQueue <- item:
+		return
+	default:
+	}
+
+	switch s.config.QueueOverflow {
+	case SyncQueueOverflowDrop:
+		logger.Debugf("Report Sync: Dropping work, queue is full")
+		s.recordOutcome(errSyncDropped)
+	case SyncQueueOverflowSpill:
+		err := s.spillItem(item)
+		if err != nil {
+			logger.Warnf("Report Sync: Failed to spill work, waiting for queue: %v", err)
+
+			s.workQueue <- item
+			return
+		}
+	default:
+		logger.Warnf("Report Sync: Unknown queue overflow strategy: %s", s.config.QueueOverflow)
+
+		s.workQueue <- item
+		return
+	}
+
+	s.wg.Done()
+}
+
+// spillItem builds the request in the caller and writes it to the spill
+// file. Outcome of work that fails to build is recorded here.
+func (s *syncReporter) spillItem(item *workItem) error {
+	var record syncSpoolRecord
+	var err error
+
+	switch {
+	case item.event != nil:
+		var req *controltowerv1.PublishPolicyViolationRequest
+		_, req, err = s.policyViolationRequest(item.event)
+		if err == nil {
+			record, err = newSyncSpillRecord(syncSpoolRecordPolicyViolation,
+				item.event.Package.Manifest.Path, req)
+		}
+	case item.pkg != nil:
+		var req *controltowerv1.PublishPackageInsightRequest
+		_, req, err = s.packageInsightRequest(item.pkg)
+		if err == nil {
+			record, err = newSyncSpillRecord(syncSpoolRecordPackageInsight,
+				item.pkg.Manifest.Path, req)
+		}
+	case item.malware != nil:
+		var req *controltowerv1.PublishPolicyViolationRequest
+		_, req, err = s.malwareVerdictRequest(item.malware)
+		if err == nil {
+			record, err = newSyncSpillRecord(syncSpoolRecordPolicyViolation,
+				item.malware.Manifest.Path, req)
+		}
+	default:
+		return nil
+	}
+
+	if err != nil {
+		s.recordOutcome(err)
+		return nil
+	}
+
+	s.spillMu.Lock()
+	defer s.spillMu.Unlock()
+
+	if s.spill == nil {
+		file, err := os.CreateTemp(s.config.SpillDir, "vet-sync-spill-*"+syncSpoolFileExtension)
+		if err != nil {
+			return fmt.Errorf("failed to create spill file: %w", err)
+		}
+
+		logger.Debugf("Report Sync: Queue is full, spilling work to: %s", file.Name())
+		s.spill = &syncSpool{file: file, writer: bufio.NewWriter(file), cipher: s.config.SpoolCipher}
+	}
+
+	err = s.spill.writeRecord(record)
+	if err != nil {
+		return err
+	}
+
+	s.spilled++
+	return nil
+}
+
+func newSyncSpillRecord(kind syncSpoolRecordKind, sessionKey string,
+	req proto.Message,
+) (syncSpoolRecord, error) {
+	payload, err := protojson.Marshal(req)
+	if err != nil {
+		return syncSpoolRecord{}, fmt.Errorf("failed to serialize %s: %w", kind, err)
+	}
+
+	return syncSpoolRecord{Kind: kind, SessionKey: sessionKey, Payload: payload}, nil
+}
+
+// drainSpill queues the spilled work once the queue is drained and waits
+// for it to be published. Must be called after queued work is done.
+func (s *syncReporter) drainSpill() {
+	s.spillMu.Lock()
+	spill, count := s.spill, s.spilled
+	s.spill, s.spilled = nil, 0
+	s.spillMu.Unlock()
+
+	if spill == nil {
+		return
+	}
+
+	defer func() {
+		spill.file.Close()
+		os.Remove(spill.file.Name())
+	}()
+
+	logger.Debugf("Report Sync: Publishing %d spilled items", count)
+
+	err := spill.writer.Flush()
+	if err == nil {
+		_, err = spill.file.Seek(0, 0)
+	}
+
+	if err != nil {
+		logger.Errorf("failed to read spilled work: %v", err)
+		for i := 0; i < count; i++ {
+			s.recordOutcome(fmt.Errorf("failed to read spilled work: %w", err))
+		}
+
+		return
+	}
+
+	scanner := bufio.NewScanner(spill.file)
+	scanner.Buffer(make([]byte, 0, 64*1024), syncSpoolMaxRecordSize)
+
+	read := 0
+	for scanner.Scan() {
+		record, err := readSyncSpoolRecord(spill.cipher, scanner.Bytes())
+		read++
+
+		s.wg.Add(1)
+		if err != nil {
+			s.recordOutcome(err)
+			s.wg.Done()
+
+			continue
+		}
+
+		s.workQueue <- &workItem{spilled: &record}
+	}
+
+	if read < count {
+		err := scanner.Err()
+		if err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+
+		logger.Errorf("failed to read spilled work: %v", err)
+		for ; read < count; read++ {
+			s.recordOutcome(fmt.Errorf("failed to read spilled work: %w", err))
+		}
+	}
+
+	s.wg.Wait()
+}
+
+func (s *syncReporter) syncSpilled(record *syncSpoolRecord) error {
+	session, err := s.sessions.getSession(record.SessionKey)
+	if err != nil {
+		return fmt.Errorf("failed to get session for spilled work: %w", err)
+	}
+
+	switch record.Kind {
+	case syncSpoolRecordPackageInsight:
+		var req controltowerv1.PublishPackageInsightRequest
+		if err := protojson.Unmarshal(record.Payload, &req); err != nil {
+			return fmt.Errorf("failed to parse spilled package insight: %w", err)
+		}
+
+		return s.publishPackageInsight(session, &req)
+	case syncSpoolRecordPolicyViolation:
+		var req controltowerv1.PublishPolicyViolationRequest
+		if err := protojson.Unmarshal(record.Payload, &req); err != nil {
+			return fmt.Errorf("failed to parse spilled policy violation: %w", err)
+		}
+
+		return s.publishPolicyViolation(session, &req)
+	default:
+		return fmt.Errorf("unknown spilled work: %s", record.Kind)
+	}
+}
@@ -0,0 +1,118 @@
+package reporter
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSyncQueueTestReporter(t *testing.T, overflow SyncQueueOverflow,
+	client *spoolTestToolServiceClient,
+) *syncReporter {
+	s := &syncReporter{
+		ctx: context.Background(),
+		config: &SyncReporterConfig{
+			WorkerCount:   1,
+			QueueOverflow: overflow,
+			SpillDir:      t.TempDir(),
+			RetryPolicy:   DefaultSyncRetryPolicy(),
+		},
+		done:       make(chan bool),
+		workQueue:  make(chan *workItem, 1),
+		sessions:   &syncSessionPool{syncSessions: make(map[string]syncSession)},
+		scorecards: newSyncScorecardRegistry(),
+	}
+
+	s.sessions.addPrimarySession(syncSession{sessionId: "session-1", toolServiceClient: client})
+	return s
+}
+
+func newSyncQueueTestPackages(names ...string) []*models.Package {
+	manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+
+	pkgs := []*models.Package{}
+	for _, name := range names {
+		pkgs = append(pkgs, &models.Package{
+			PackageDetails: models.NewPackageDetail(models.EcosystemNpm, name, "1.0.0"),
+			Manifest:       manifest,
+		})
+	}
+
+	return pkgs
+}
+
+func TestSyncQueueOverflowDrop(t *testing.T) {
+	client := &spoolTestToolServiceClient{}
+	s := newSyncQueueTestReporter(t, SyncQueueOverflowDrop, client)
+
+	// Workers are not started so that the queue stays full
+	for _, pkg := range newSyncQueueTestPackages("lodash", "express", "react") {
+		s.queuePackage(pkg)
+	}
+
+	assert.Equal(t, 2, s.SyncStats().Dropped)
+	assert.Len(t, s.workQueue, 1)
+
+	s.startWorkers()
+
+	err := s.Finish()
+	assert.ErrorContains(t, err, "2 of 3 items dropped")
+
+	assert.Equal(t, []string{"session-1/lodash"}, client.packages)
+	assert.Empty(t, s.failures)
+	assert.Equal(t, 1, s.SyncStats().Published)
+}
+
+func TestSyncQueueOverflowSpill(t *testing.T) {
+	client := &spoolTestToolServiceClient{}
+	s := newSyncQueueTestReporter(t, SyncQueueOverflowSpill, client)
+
+	for _, pkg := range newSyncQueueTestPackages("lodash", "express", "react") {
+		s.queuePackage(pkg)
+	}
+
+	assert.Equal(t, 2, s.spilled)
+	assert.Len(t, s.workQueue, 1)
+
+	entries, err := os.ReadDir(s.config.SpillDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	s.startWorkers()
+	assert.NoError(t, s.Finish())
+
+	assert.ElementsMatch(t, []string{"session-1/lodash", "session-1/express", "session-1/react"},
+		client.packages)
+	assert.Equal(t, SyncStats{Published: 3}, s.SyncStats())
+
+	// Spill file is removed once published
+	entries, err = os.ReadDir(s.config.SpillDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestSyncProgress(t *testing.T) {
+	client := &spoolTestToolServiceClient{}
+	s := newSyncQueueTestReporter(t, SyncQueueOverflowDrop, client)
+
+	type progress struct{ completed, total int }
+
+	updates := []progress{}
+	s.config.OnProgress = func(completed, total int) {
+		updates = append(updates, progress{completed, total})
+	}
+
+	for _, pkg := range newSyncQueueTestPackages("lodash", "express") {
+		s.queuePackage(pkg)
+	}
+
+	s.startWorkers()
+	assert.ErrorContains(t, s.Finish(), "1 of 2 items dropped")
+
+	// Dropped items are completed as soon as they are queued
+	assert.Equal(t, []progress{{0, 1}, {0, 2}, {1, 2}, {2, 2}}, updates)
+}
@@ -0,0 +1,121 @@
+package reporter
+
+import (
+	"context"
+	"sync"
+
+	"github.com/safedep/vet/pkg/common/logger"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// Adaptive rate is lowered to this fraction of the configured rate
+	syncRateLimitDefaultMinFraction = 0.1
+
+	// Multiplicative decrease on RESOURCE_EXHAUSTED
+	syncRateLimitDecreaseFactor = 0.5
+
+	// Additive increase on success, as a fraction of the configured rate
+	syncRateLimitIncreaseFraction = 0.05
+)
+
+// SyncRateLimit limits the rate of requests to ControlTower across
+// all workers. Worker count alone does not bound the request rate
+// since requests finish at different speeds.
+type SyncRateLimit struct {
+	// Requests per second, unlimited when zero
+	RequestsPerSecond float64
+
+	// Requests allowed to exceed the rate momentarily, defaults to 1
+	Burst int
+
+	// Lower the rate when ControlTower responds with RESOURCE_EXHAUSTED
+	// and gradually recover to the configured rate on success
+	Adaptive bool
+
+	// Optional, lowest rate in adaptive mode, defaults to 10% of the rate
+	MinRequestsPerSecond float64
+}
+
+type syncRateLimiter struct {
+	config  SyncRateLimit
+	limiter *rate.Limiter
+
+	m       sync.Mutex
+	current float64
+}
+
+// newSyncRateLimiter returns nil when the rate is not limited
+func newSyncRateLimiter(config SyncRateLimit) *syncRateLimiter {
+	if config.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	if config.Burst <= 0 {
+		config.Burst = 1
+	}
+
+	if config.MinRequestsPerSecond <= 0 || config.MinRequestsPerSecond > config.RequestsPerSecond {
+		config.MinRequestsPerSecond = config.RequestsPerSecond * syncRateLimitDefaultMinFraction
+	}
+
+	return &syncRateLimiter{
+		config:  config,
+		limiter: rate.NewLimiter(rate.Limit(config.RequestsPerSecond), config.Burst),
+		current: config.RequestsPerSecond,
+	}
+}
+
+// wrap returns fn that waits for its turn before calling fn. Every
+// attempt of a retry is limited.
+func (l *syncRateLimiter) wrap(fn func(ctx context.Context) error) func(ctx context.Context) error {
+	if l == nil {
+		return fn
+	}
+
+	return func(ctx context.Context) error {
+		if err := l.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		err := fn(ctx)
+		l.observe(err)
+
+		return err
+	}
+}
+
+// observe adjusts the rate using AIMD in adaptive mode
+func (l *syncRateLimiter) observe(err error) {
+	if !l.config.Adaptive {
+		return
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	previous := l.current
+	if status.Code(err) == codes.ResourceExhausted {
+		l.current = max(l.current*syncRateLimitDecreaseFactor, l.config.MinRequestsPerSecond)
+	} else if err == nil {
+		l.current = min(l.current+l.config.RequestsPerSecond*syncRateLimitIncreaseFraction,
+			l.config.RequestsPerSecond)
+	}
+
+	if l.current != previous {
+		if l.current < previous {
+			logger.Debugf("Report Sync: Lowering request rate to %.2f/s", l.current)
+		}
+
+		l.limiter.SetLimit(rate.Limit(l.current))
+	}
+}
+
+func (l *syncRateLimiter) currentRate() float64 {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	return l.current
+}
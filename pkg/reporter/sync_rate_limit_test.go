@@ -0,0 +1,113 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSyncRateLimiterUnlimited(t *testing.T) {
+	l := newSyncRateLimiter(SyncRateLimit{})
+	assert.Nil(t, l)
+
+	calls := 0
+	err := l.wrap(func(ctx context.Context) error {
+		calls++
+		return nil
+	})(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestSyncRateLimiterWait(t *testing.T) {
+	l := newSyncRateLimiter(SyncRateLimit{RequestsPerSecond: 50, Burst: 1})
+	fn := l.wrap(func(ctx context.Context) error { return nil })
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		assert.NoError(t, fn(context.Background()))
+	}
+
+	// First request uses the burst, rest wait 20ms each
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	slow := newSyncRateLimiter(SyncRateLimit{RequestsPerSecond: 0.001})
+	slowFn := slow.wrap(func(ctx context.Context) error { return nil })
+	assert.NoError(t, slowFn(context.Background()))
+	assert.Error(t, slowFn(ctx))
+}
+
+func TestSyncRateLimiterAdaptive(t *testing.T) {
+	exhausted := status.Error(codes.ResourceExhausted, "slow down")
+
+	cases := []struct {
+		name     string
+		config   SyncRateLimit
+		outcomes []error
+		expected float64
+	}{
+		{
+			"not adaptive",
+			SyncRateLimit{RequestsPerSecond: 100},
+			[]error{exhausted, exhausted},
+			100,
+		},
+		{
+			"halved on resource exhausted",
+			SyncRateLimit{RequestsPerSecond: 100, Adaptive: true},
+			[]error{exhausted, exhausted},
+			25,
+		},
+		{
+			"bounded by min rate",
+			SyncRateLimit{RequestsPerSecond: 100, Adaptive: true, MinRequestsPerSecond: 40},
+			[]error{exhausted, exhausted, exhausted},
+			40,
+		},
+		{
+			"default min rate",
+			SyncRateLimit{RequestsPerSecond: 100, Adaptive: true},
+			[]error{exhausted, exhausted, exhausted, exhausted, exhausted},
+			10,
+		},
+		{
+			"recovers on success",
+			SyncRateLimit{RequestsPerSecond: 100, Adaptive: true},
+			[]error{exhausted, nil, nil},
+			60,
+		},
+		{
+			"other errors do not change rate",
+			SyncRateLimit{RequestsPerSecond: 100, Adaptive: true},
+			[]error{exhausted, errors.New("failed"), status.Error(codes.Unavailable, "down")},
+			50,
+		},
+		{
+			"does not exceed configured rate",
+			SyncRateLimit{RequestsPerSecond: 100, Adaptive: true},
+			[]error{nil, nil},
+			100,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			l := newSyncRateLimiter(test.config)
+			for _, err := range test.outcomes {
+				l.observe(err)
+			}
+
+			assert.InDelta(t, test.expected, l.currentRate(), 0.001)
+			assert.InDelta(t, test.expected, float64(l.limiter.Limit()), 0.001)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package reporter
+
+import (
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+)
+
+// syncRedactedPackage returns false when the package is stripped by the
+// redaction config and must not be synced
+func (s *syncReporter) syncRedactedPackage(name string) bool {
+	_, ok := s.config.Redactor.Package(name)
+	return ok
+}
+
+// redactManifest redacts the path and namespace of the manifest
+func (s *syncReporter) redactManifest(manifest *packagev1.PackageManifest) {
+	if s.config.Redactor == nil || manifest == nil {
+		return
+	}
+
+	manifest.Name = s.config.Redactor.ManifestPath(manifest.GetName())
+	if manifest.Namespace != nil {
+		namespace := s.config.Redactor.Namespace(manifest.GetNamespace())
+		manifest.Namespace = &namespace
+	}
+}
+
+// redactPackageVersion returns false when the package is stripped
+func (s *syncReporter) redactPackageVersion(pv *packagev1.PackageVersion) bool {
+	if s.config.Redactor == nil || pv.GetPackage() == nil {
+		return true
+	}
+
+	name, ok := s.config.Redactor.Package(pv.GetPackage().GetName())
+	pv.Package.Name = name
+
+	return ok
+}
+
+// redactPolicyViolation redacts the request before it is spooled, journaled
+// or published
+func (s *syncReporter) redactPolicyViolation(req *controltowerv1.PublishPolicyViolationRequest) {
+	s.redactManifest(req.GetManifest())
+	s.redactPackageVersion(req.GetPackageVersion())
+}
+
+// redactPackageInsight redacts the request before it is spooled, journaled
+// or published. Stripped dependencies are removed from the insight.
+func (s *syncReporter) redactPackageInsight(req *controltowerv1.PublishPackageInsightRequest) {
+	if s.config.Redactor == nil {
+		return
+	}
+
+	s.redactManifest(req.GetManifest())
+	s.redactPackageVersion(req.GetPackageVersion())
+
+	insight := req.GetPackageVersionInsight()
+	if insight == nil {
+		return
+	}
+
+	dependencies := make([]*packagev1.PackageVersion, 0, len(insight.GetDependencies()))
+	for _, dependency := range insight.GetDependencies() {
+		if s.redactPackageVersion(dependency) {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	insight.Dependencies = dependencies
+	insight.DependencyGraph = s.redactDependencyGraph(insight.GetDependencyGraph())
+}
+
+// redactDependencyGraph removes stripped packages along with their
+// relations from the graph
+func (s *syncReporter) redactDependencyGraph(graph *packagev1.PackageVersionDependencyGraph,
+) *packagev1.PackageVersionDependencyGraph {
+	if graph == nil {
+		return nil
+	}
+
+	index := map[uint32]uint32{}
+	dependencies := make([]*packagev1.PackageVersionDependencyGraph_PackageVersionDependency, 0,
+		len(graph.GetDependencies()))
+
+	for i, dependency := range graph.GetDependencies() {
+		if !s.redactPackageVersion(dependency.GetPackageVersion()) {
+			continue
+		}
+
+		index[uint32(i)] = uint32(len(dependencies))
+		dependencies = append(dependencies, dependency)
+	}
+
+	relations := make([]*packagev1.PackageVersionDependencyGraph_PackageVersionDependencyRelation, 0,
+		len(graph.GetDependencyRelations()))
+
+	for _, relation := range graph.GetDependencyRelations() {
+		from, fromOk := index[relation.GetFrom()]
+		to, toOk := index[relation.GetTo()]
+		if !fromOk || !toOk {
+			continue
+		}
+
+		relation.From, relation.To = from, to
+		relations = append(relations, relation)
+	}
+
+	graph.Dependencies = dependencies
+	graph.DependencyRelations = relations
+
+	return graph
+}
@@ -0,0 +1,205 @@
+package reporter
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/safedep/vet/gen/checks"
+	"github.com/safedep/vet/gen/filtersuite"
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func newRedactTestReporter(t *testing.T) *syncReporter {
+	redactor, err := redact.NewRedactor(redact.Config{
+		ManifestPaths: redact.ActionHash,
+		Namespaces:    redact.ActionStrip,
+		Packages: []redact.PackageRule{
+			{Pattern: "^@acme/secret-", Action: redact.ActionStrip},
+			{Pattern: "^@acme/", Action: redact.ActionHash},
+		},
+	})
+	require.NoError(t, err)
+
+	return &syncReporter{config: &SyncReporterConfig{Redactor: redactor}}
+}
+
+func newRedactTestPackageVersion(name string) *packagev1.PackageVersion {
+	return &packagev1.PackageVersion{
+		Package: &packagev1.Package{Name: name},
+		Version: "1.0.0",
+	}
+}
+
+func TestSyncRedactPackageInsight(t *testing.T) {
+	s := newRedactTestReporter(t)
+	namespace := "/home/user/acme"
+
+	req := &controltowerv1.PublishPackageInsightRequest{
+		Manifest: &packagev1.PackageManifest{
+			Name:      "services/payments/package-lock.json",
+			Namespace: &namespace,
+		},
+		PackageVersion: newRedactTestPackageVersion("@acme/payments"),
+		PackageVersionInsight: &packagev1.PackageVersionInsight{
+			Dependencies: []*packagev1.PackageVersion{
+				newRedactTestPackageVersion("lodash"),
+				newRedactTestPackageVersion("@acme/secret-sauce"),
+			},
+			DependencyGraph: &packagev1.PackageVersionDependencyGraph{
+				Dependencies: []*packagev1.PackageVersionDependencyGraph_PackageVersionDependency{
+					{PackageVersion: newRedactTestPackageVersion("@acme/payments")},
+					{PackageVersion: newRedactTestPackageVersion("@acme/secret-sauce")},
+					{PackageVersion: newRedactTestPackageVersion("lodash")},
+				},
+				DependencyRelations: []*packagev1.PackageVersionDependencyGraph_PackageVersionDependencyRelation{
+					{From: 0, To: 1},
+					{From: 1, To: 2},
+					{From: 0, To: 2},
+				},
+			},
+		},
+	}
+
+	s.redactPackageInsight(req)
+
+	assert.True(t, strings.HasPrefix(req.GetManifest().GetName(), "sha256:"))
+	assert.Empty(t, req.GetManifest().GetNamespace())
+	assert.True(t, strings.HasPrefix(req.GetPackageVersion().GetPackage().GetName(), "sha256:"))
+
+	insight := req.GetPackageVersionInsight()
+	require.Len(t, insight.GetDependencies(), 1)
+	assert.Equal(t, "lodash", insight.GetDependencies()[0].GetPackage().GetName())
+
+	graph := insight.GetDependencyGraph()
+	require.Len(t, graph.GetDependencies(), 2)
+	assert.Equal(t, req.GetPackageVersion().GetPackage().GetName(),
+		graph.GetDependencies()[0].GetPackageVersion().GetPackage().GetName())
+	assert.Equal(t, "lodash", graph.GetDependencies()[1].GetPackageVersion().GetPackage().GetName())
+
+	require.Len(t, graph.GetDependencyRelations(), 1)
+	assert.Equal(t, uint32(0), graph.GetDependencyRelations()[0].GetFrom())
+	assert.Equal(t, uint32(1), graph.GetDependencyRelations()[0].GetTo())
+}
+
+func TestSyncRedactWithoutRedactor(t *testing.T) {
+	s := &syncReporter{config: &SyncReporterConfig{}}
+
+	req := &controltowerv1.PublishPackageInsightRequest{
+		Manifest:       &packagev1.PackageManifest{Name: "package-lock.json"},
+		PackageVersion: newRedactTestPackageVersion("@acme/secret-sauce"),
+	}
+
+	s.redactPackageInsight(req)
+
+	assert.True(t, s.syncRedactedPackage("@acme/secret-sauce"))
+	assert.Equal(t, "package-lock.json", req.GetManifest().GetName())
+	assert.Equal(t, "@acme/secret-sauce", req.GetPackageVersion().GetPackage().GetName())
+}
+
+func TestSyncPolicyViolationRedacted(t *testing.T) {
+	s := newSyncQueueTestReporter(t, SyncQueueOverflowBlock, &spoolTestToolServiceClient{})
+	s.config.Redactor = newRedactTestReporter(t).config.Redactor
+
+	pkgs := newSyncQueueTestPackages("@acme/payments", "@acme/secret-sauce")
+	event := func(i int) *analyzer.AnalyzerEvent {
+		return &analyzer.AnalyzerEvent{
+			Type: analyzer.ET_FilterExpressionMatched,
+			Filter: &filtersuite.Filter{
+				Name:      "critical-vulns",
+				CheckType: checks.CheckType_CheckTypeVulnerability,
+			},
+			Package: pkgs[i],
+		}
+	}
+
+	_, req, err := s.policyViolationRequest(event(0))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(req.GetPackageVersion().GetPackage().GetName(), "sha256:"))
+	assert.True(t, strings.HasPrefix(req.GetManifest().GetName(), "sha256:"))
+
+	_, _, err = s.policyViolationRequest(event(1))
+	assert.ErrorIs(t, err, errSyncSkipped)
+}
+
+func TestSyncMultiProjectSessionRedacted(t *testing.T) {
+	cases := []struct {
+		name     string
+		action   redact.Action
+		projects func(t *testing.T, projects []string)
+	}{
+		{
+			"hashed namespace",
+			redact.ActionHash,
+			func(t *testing.T, projects []string) {
+				for _, project := range projects {
+					assert.True(t, strings.HasPrefix(project, "sha256:"))
+				}
+
+				assert.NotEqual(t, projects[0], projects[1])
+			},
+		},
+		{
+			"stripped namespace",
+			redact.ActionStrip,
+			func(t *testing.T, projects []string) {
+				assert.Equal(t, []string{"scan-project", "scan-project"}, projects)
+			},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			redactor, err := redact.NewRedactor(redact.Config{Namespaces: test.action})
+			require.NoError(t, err)
+
+			path := filepath.Join(t.TempDir(), "sync.jsonl")
+			dryRun, err := newSyncDryRun(path)
+			require.NoError(t, err)
+
+			s := &syncReporter{
+				ctx: context.Background(),
+				config: &SyncReporterConfig{
+					ToolName:               "vet",
+					ProjectName:            "scan-project",
+					EnableMultiProjectSync: true,
+					Redactor:               redactor,
+				},
+				dryRun:   dryRun,
+				sessions: &syncSessionPool{syncSessions: make(map[string]syncSession)},
+			}
+
+			for _, namespace := range []string{"/home/acme/payments", "/home/acme/billing"} {
+				manifest := models.NewPackageManifestFromLocal(namespace+"/package-lock.json", models.EcosystemNpm)
+				manifest.Source.Namespace = namespace
+
+				_, err := s.manifestSession(manifest)
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, dryRun.close())
+
+			projects := []string{}
+			for _, record := range readTestSyncRecords(t, path) {
+				require.Equal(t, syncSpoolRecordSession, record.Kind)
+				assert.NotContains(t, string(record.Payload), "acme")
+
+				var req controltowerv1.CreateToolSessionRequest
+				require.NoError(t, protojson.Unmarshal(record.Payload, &req))
+
+				projects = append(projects, req.GetProjectName())
+			}
+
+			require.Len(t, projects, 2)
+			test.projects(t, projects)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package reporter
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	syncReporterDefaultInitialBackoff = 500 * time.Millisecond
+	syncReporterDefaultMaxBackoff     = 10 * time.Second
+	syncReporterDefaultBackoffFactor  = 2
+)
+
+// syncRetryJitter returns a random duration in [0, backoff] so that
+// clients failing together do not retry together (full jitter)
+var syncRetryJitter = func(backoff time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// SyncRetryPolicy defines how publishing to ControlTower is retried
+// on transient failures
+type SyncRetryPolicy struct {
+	// Maximum number of attempts including the first one
+	MaxAttempts int
+
+	// Backoff before the first retry, multiplied by Multiplier
+	// for every subsequent retry upto MaxBackoff. The actual delay
+	// is a random duration upto the backoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+
+	// Optional, status codes to retry, defaults to UNAVAILABLE
+	// and RESOURCE_EXHAUSTED
+	RetryableCodes []codes.Code
+
+	// Set by the reporter to observe retries and latency
+	metrics *syncMetrics
+}
+
+// DefaultSyncRetryPolicy returns the retry policy used when
+// not configured by the caller
+func DefaultSyncRetryPolicy() SyncRetryPolicy {
+	return SyncRetryPolicy{
+		MaxAttempts:    syncReporterMaxRetries,
+		InitialBackoff: syncReporterDefaultInitialBackoff,
+		MaxBackoff:     syncReporterDefaultMaxBackoff,
+		Multiplier:     syncReporterDefaultBackoffFactor,
+		RetryableCodes: []codes.Code{codes.Unavailable, codes.ResourceExhausted},
+	}
+}
+
+// withDefaults fills unset fields from the default policy
+func (p SyncRetryPolicy) withDefaults() SyncRetryPolicy {
+	defaults := DefaultSyncRetryPolicy()
+
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaults.InitialBackoff
+	}
+
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaults.MaxBackoff
+	}
+
+	if p.Multiplier < 1 {
+		p.Multiplier = defaults.Multiplier
+	}
+
+	if len(p.RetryableCodes) == 0 {
+		p.RetryableCodes = defaults.RetryableCodes
+	}
+
+	return p
+}
+
+func (p SyncRetryPolicy) isRetryable(err error) bool {
+	code := status.Code(err)
+	for _, c := range p.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// run executes fn until it succeeds, fails with a non-retryable error,
+// attempts are exhausted or the context is cancelled
+func (p SyncRetryPolicy) run(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	backoff := p.InitialBackoff
+	startedAt := time.Now()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn(ctx)
+		if err == nil || !p.isRetryable(err) || attempt >= p.MaxAttempts {
+			p.metrics.publish(op, time.Since(startedAt), err)
+			return err
+		}
+
+		delay := syncRetryJitter(backoff)
+		logger.Debugf("Report Sync: Retrying %s after %s (attempt %d/%d): %v",
+			op, delay, attempt, p.MaxAttempts, err)
+
+		p.metrics.retry(op)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			p.metrics.publish(op, time.Since(startedAt), err)
+			return err
+		case <-timer.C:
+		}
+
+		backoff = time.Duration(float64(backoff) * p.Multiplier)
+		if backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSyncRetryPolicyRun(t *testing.T) {
+	cases := []struct {
+		name     string
+		errs     []error
+		attempts int
+		err      bool
+	}{
+		{
+			"success on first attempt",
+			[]error{nil},
+			1, false,
+		},
+		{
+			"retry on unavailable",
+			[]error{status.Error(codes.Unavailable, "unavailable"), nil},
+			2, false,
+		},
+		{
+			"retry on resource exhausted",
+			[]error{status.Error(codes.ResourceExhausted, "slow down"), nil},
+			2, false,
+		},
+		{
+			"no retry on invalid argument",
+			[]error{status.Error(codes.InvalidArgument, "bad request"), nil},
+			1, true,
+		},
+		{
+			"no retry on non gRPC error",
+			[]error{errors.New("failed"), nil},
+			1, true,
+		},
+		{
+			"attempts exhausted",
+			[]error{
+				status.Error(codes.Unavailable, "unavailable"),
+				status.Error(codes.Unavailable, "unavailable"),
+				status.Error(codes.Unavailable, "unavailable"),
+				nil,
+			},
+			3, true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			policy := SyncRetryPolicy{
+				MaxAttempts:    3,
+				InitialBackoff: time.Millisecond,
+			}.withDefaults()
+
+			attempts := 0
+			err := policy.run(context.Background(), "test", func(_ context.Context) error {
+				err := test.errs[attempts]
+				attempts++
+				return err
+			})
+
+			assert.Equal(t, test.attempts, attempts)
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSyncRetryPolicyRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	policy := SyncRetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}.withDefaults()
+
+	attempts := 0
+	err := policy.run(ctx, "test", func(_ context.Context) error {
+		attempts++
+		return status.Error(codes.Unavailable, "unavailable")
+	})
+
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestSyncRetryPolicyDefaults(t *testing.T) {
+	policy := SyncRetryPolicy{}.withDefaults()
+	assert.Equal(t, DefaultSyncRetryPolicy(), policy)
+}
+
+func TestSyncRetryPolicyJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		delay := syncRetryJitter(10 * time.Millisecond)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, 10*time.Millisecond)
+	}
+
+	jitter := syncRetryJitter
+	defer func() { syncRetryJitter = jitter }()
+
+	backoffs := []time.Duration{}
+	syncRetryJitter = func(backoff time.Duration) time.Duration {
+		backoffs = append(backoffs, backoff)
+		return 0
+	}
+
+	policy := SyncRetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+	}.withDefaults()
+
+	err := policy.run(context.Background(), "test", func(_ context.Context) error {
+		return status.Error(codes.Unavailable, "unavailable")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}, backoffs)
+}
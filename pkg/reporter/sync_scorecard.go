@@ -0,0 +1,144 @@
+package reporter
+
+import (
+	"strings"
+	"sync"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	scorecardv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/scorecard/v1"
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/vet/gen/insightapi"
+)
+
+// OpenSSF scorecard is computed per project and not per package. A project
+// is usually related to multiple packages e.g. a monorepo publishing many
+// packages. Hence the scorecard of a project is published only with the
+// first package of the project in a session.
+type syncScorecardRegistry struct {
+	mu        sync.Mutex
+	published map[string]bool
+}
+
+func newSyncScorecardRegistry() *syncScorecardRegistry {
+	return &syncScorecardRegistry{published: map[string]bool{}}
+}
+
+// claim returns true only for the first claim of a project in a session
+func (r *syncScorecardRegistry) claim(sessionKey, projectKey string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := sessionKey + "/" + projectKey
+	if r.published[key] {
+		return false
+	}
+
+	r.published[key] = true
+	return true
+}
+
+// syncProjectKey normalizes a project URL or name such that
+// https://github.com/safedep/vet.git and github.com/safedep/vet
+// are the same project
+func syncProjectKey(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, prefix := range []string{"https://", "http://", "git+", "www."} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+
+	s = strings.TrimSuffix(s, "/")
+	s = strings.TrimSuffix(s, ".git")
+
+	return s
+}
+
+// scorecardRepositoryKey returns the project key of the repository
+// for which the scorecard was computed
+func scorecardRepositoryKey(scorecard *insightapi.Scorecard) string {
+	if scorecard == nil {
+		return ""
+	}
+
+	content := utils.SafelyGetValue(scorecard.Content)
+	repository := utils.SafelyGetValue(content.Repository)
+
+	return syncProjectKey(utils.SafelyGetValue(repository.Name))
+}
+
+// scorecardMatchesProject checks if the scorecard is of the project. Project
+// link is preferred while the name is used for sources without a link.
+func scorecardMatchesProject(scorecardKey string, project *insightapi.PackageProjectInfo) bool {
+	if scorecardKey == "" {
+		return false
+	}
+
+	if link := syncProjectKey(utils.SafelyGetValue(project.Link)); link != "" {
+		return link == scorecardKey
+	}
+
+	name := syncProjectKey(utils.SafelyGetValue(project.Name))
+	return name != "" && (name == scorecardKey || strings.HasSuffix(scorecardKey, "/"+name))
+}
+
+// scorecardProject is used when insights have a scorecard but not the
+// projects, e.g. packages from RubyGems
+func scorecardProject(scorecard *insightapi.Scorecard) *packagev1.Project {
+	key := scorecardRepositoryKey(scorecard)
+	if key == "" {
+		return nil
+	}
+
+	project := &packagev1.Project{
+		Type: packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_UNSPECIFIED,
+		Name: key,
+		Url:  "https://" + key,
+	}
+
+	if parts := strings.SplitN(key, "/", 2); len(parts) == 2 {
+		project.Name = parts[1]
+		if parts[0] == "github.com" {
+			project.Type = packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_GITHUB
+		} else if parts[0] == "gitlab.com" {
+			project.Type = packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_GITLAB
+		}
+	}
+
+	return project
+}
+
+func syncScorecard(scorecard *insightapi.Scorecard) *scorecardv1.Scorecard {
+	content := utils.SafelyGetValue(scorecard.Content)
+	repository := utils.SafelyGetValue(content.Repository)
+	version := utils.SafelyGetValue(content.Scorecard)
+
+	sc := &scorecardv1.Scorecard{
+		Score: utils.SafelyGetValue(content.Score),
+		Repo: &scorecardv1.Scorecard_Repo{
+			Name:   utils.SafelyGetValue(repository.Name),
+			Commit: utils.SafelyGetValue(repository.Commit),
+		},
+		ScorecardVersion: &scorecardv1.Scorecard_ScorecardVersion{
+			Version: utils.SafelyGetValue(version.Version),
+			Commit:  utils.SafelyGetValue(version.Commit),
+		},
+		Checks: []*scorecardv1.ScorecardCheck{},
+	}
+
+	if content.Date != nil {
+		sc.Date = content.Date.Format("2006-01-02")
+	}
+
+	for _, check := range utils.SafelyGetValue(content.Checks) {
+		if check.Name == nil {
+			continue
+		}
+
+		sc.Checks = append(sc.Checks, &scorecardv1.ScorecardCheck{
+			Name:   string(*check.Name),
+			Score:  utils.SafelyGetValue(check.Score),
+			Reason: check.Reason,
+		})
+	}
+
+	return sc
+}
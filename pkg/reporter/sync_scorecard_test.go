@@ -0,0 +1,104 @@
+package reporter
+
+import (
+	"testing"
+	"time"
+
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
+	"github.com/safedep/vet/gen/insightapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
+
+func testScorecard(repository string) *insightapi.Scorecard {
+	checkName := insightapi.ScorecardV2CheckNameMaintained
+	return &insightapi.Scorecard{
+		Content: &insightapi.ScorecardContentV2{
+			Date:  &openapi_types.Date{Time: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+			Score: ptrTo(float32(7.5)),
+			Repository: &insightapi.ScorecardContentV2Repository{
+				Name: ptrTo(repository),
+			},
+			Checks: &[]insightapi.ScorecardV2Check{
+				{Name: &checkName, Score: ptrTo(float32(10)), Reason: ptrTo("active")},
+				{Score: ptrTo(float32(0))},
+			},
+		},
+	}
+}
+
+func TestScorecardMatchesProject(t *testing.T) {
+	key := scorecardRepositoryKey(testScorecard("github.com/safedep/vet"))
+	assert.Equal(t, "github.com/safedep/vet", key)
+
+	cases := []struct {
+		name    string
+		project insightapi.PackageProjectInfo
+		match   bool
+	}{
+		{
+			"link with scheme and git suffix",
+			insightapi.PackageProjectInfo{Link: ptrTo("https://github.com/SafeDep/vet.git")},
+			true,
+		},
+		{
+			"link of another project",
+			insightapi.PackageProjectInfo{Link: ptrTo("https://github.com/safedep/dry")},
+			false,
+		},
+		{
+			"name when link is not available",
+			insightapi.PackageProjectInfo{Name: ptrTo("safedep/vet")},
+			true,
+		},
+		{
+			"link takes precedence over name",
+			insightapi.PackageProjectInfo{Name: ptrTo("safedep/vet"), Link: ptrTo("https://gitlab.com/a/b")},
+			false,
+		},
+		{
+			"project without name and link",
+			insightapi.PackageProjectInfo{},
+			false,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.match, scorecardMatchesProject(key, &test.project))
+		})
+	}
+
+	assert.False(t, scorecardMatchesProject("", &insightapi.PackageProjectInfo{Name: ptrTo("a")}))
+}
+
+func TestSyncScorecard(t *testing.T) {
+	sc := syncScorecard(testScorecard("github.com/safedep/vet"))
+
+	assert.Equal(t, "2025-01-02", sc.GetDate())
+	assert.Equal(t, float32(7.5), sc.GetScore())
+	assert.Equal(t, "github.com/safedep/vet", sc.GetRepo().GetName())
+	assert.Len(t, sc.GetChecks(), 1)
+	assert.Equal(t, "Maintained", sc.GetChecks()[0].GetName())
+	assert.Equal(t, "active", sc.GetChecks()[0].GetReason())
+
+	project := scorecardProject(testScorecard("github.com/safedep/vet"))
+	assert.Equal(t, "safedep/vet", project.GetName())
+	assert.Equal(t, "https://github.com/safedep/vet", project.GetUrl())
+	assert.Equal(t, packagev1.ProjectSourceType_PROJECT_SOURCE_TYPE_GITHUB, project.GetType())
+
+	assert.Nil(t, scorecardProject(&insightapi.Scorecard{}))
+}
+
+func TestSyncScorecardRegistry(t *testing.T) {
+	r := newSyncScorecardRegistry()
+
+	assert.True(t, r.claim("*", "github.com/safedep/vet"))
+	assert.False(t, r.claim("*", "github.com/safedep/vet"))
+	assert.True(t, r.claim("*", "github.com/safedep/dry"))
+	assert.True(t, r.claim("manifest-2", "github.com/safedep/vet"))
+}
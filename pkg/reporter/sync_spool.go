@@ -0,0 +1,387 @@
+package reporter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"buf.build/gen/go/safedep/api/grpc/go/safedep/services/controltower/v1/controltowerv1grpc"
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/safedep/vet/pkg/common/encryption"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/common/paths"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	syncSpoolDirName = "vet-sync-spool"
+
+	syncSpoolFileExtension    = ".ndjson"
+	syncSpoolPartialExtension = ".partial"
+
+	// Max size of a spooled record, package insight can be large
+	syncSpoolMaxRecordSize = 64 * 1024 * 1024
+)
+
+type syncSpoolRecordKind string
+
+const (
+	syncSpoolRecordSession         = syncSpoolRecordKind("session")
+	syncSpoolRecordPackageInsight  = syncSpoolRecordKind("package_insight")
+	syncSpoolRecordPolicyViolation = syncSpoolRecordKind("policy_violation")
+	syncSpoolRecordComplete        = syncSpoolRecordKind("complete")
+)
+
+// A spool file is a session record followed by publish requests of the
+// session and a complete record, one JSON record per line. Requests are
+// serialized using protojson so that the spool is readable for audit.
+// When encryption is enabled, each record is sealed as a whole.
+type syncSpoolRecord struct {
+	Kind    syncSpoolRecordKind `json:"kind,omitempty"`
+	Payload json.RawMessage     `json:"payload,omitempty"`
+	Status  string              `json:"status,omitempty"`
+	Sealed  []byte              `json:"sealed,omitempty"`
+
+	// Used only when work queue overflows to disk and in dry run
+	SessionKey string `json:"session_key,omitempty"`
+
+	// Used only in journal of a sync in progress
+	Seq uint64 `json:"seq,omitempty"`
+}
+
+// DefaultSyncSpoolDir is the directory used for spooling sync data
+// when not configured by the user
+func DefaultSyncSpoolDir() (string, error) {
+	return paths.Resolve(paths.KindSpool, syncSpoolDirName)
+}
+
+// syncSpool writes the requests of a tool session to a file. The file
+// has a partial extension till the session is completed so that a
+// concurrent flush does not replay an incomplete session.
+type syncSpool struct {
+	m      sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	cipher encryption.Cipher
+}
+
+func newSyncSpool(dir string, cipher encryption.Cipher,
+	session *controltowerv1.CreateToolSessionRequest,
+) (*syncSpool, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	pattern := fmt.Sprintf("%s-*%s%s", time.Now().UTC().Format("20060102T150405"),
+		syncSpoolFileExtension, syncSpoolPartialExtension)
+
+	file, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spool file: %w", err)
+	}
+
+	spool := &syncSpool{file: file, writer: bufio.NewWriter(file), cipher: cipher}
+	if err := spool.write(syncSpoolRecordSession, session); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+
+		return nil, err
+	}
+
+	logger.Debugf("Report Sync: Spooling tool session to: %s", file.Name())
+	return spool, nil
+}
+
+func (s *syncSpool) write(kind syncSpoolRecordKind, req proto.Message) error {
+	payload, err := protojson.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to serialize %s: %w", kind, err)
+	}
+
+	return s.writeRecord(syncSpoolRecord{Kind: kind, Payload: payload})
+}
+
+func (s *syncSpool) writeRecord(record syncSpoolRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if s.cipher != nil {
+		sealed, err := s.cipher.Encrypt(data)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt spool record: %w", err)
+		}
+
+		data, err = json.Marshal(syncSpoolRecord{Sealed: sealed})
+		if err != nil {
+			return err
+		}
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	_, err = s.writer.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write spool: %w", err)
+	}
+
+	return nil
+}
+
+// complete records the session status and makes the spool available for replay
+func (s *syncSpool) complete(status controltowerv1.CompleteToolSessionRequest_Status) error {
+	err := s.writeRecord(syncSpoolRecord{Kind: syncSpoolRecordComplete, Status: status.String()})
+	if err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	err = s.writer.Flush()
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write spool: %w", err)
+	}
+
+	return os.Rename(s.file.Name(), strings.TrimSuffix(s.file.Name(), syncSpoolPartialExtension))
+}
+
+type SyncSpoolReplayConfig struct {
+	// Directory containing spooled sessions
+	Dir string
+
+	// gRPC connection for ControlTower
+	ClientConnection *grpc.ClientConn
+
+	// Optional, defaults to DefaultSyncRetryPolicy
+	RetryPolicy SyncRetryPolicy
+
+	// Required to replay encrypted spool
+	Cipher encryption.Cipher
+}
+
+// ReplaySyncSpool publishes the sessions spooled in a directory to
+// ControlTower. A spool file is removed after its session is replayed
+// successfully. Failed sessions are retained for a later attempt.
+func ReplaySyncSpool(ctx context.Context, config SyncSpoolReplayConfig) (SyncStats, error) {
+	stats := SyncStats{}
+	if config.ClientConnection == nil {
+		return stats, fmt.Errorf("missing gRPC client connection")
+	}
+
+	config.RetryPolicy = config.RetryPolicy.withDefaults()
+
+	files, err := filepath.Glob(filepath.Join(config.Dir, "*"+syncSpoolFileExtension))
+	if err != nil {
+		return stats, fmt.Errorf("failed to list spool files: %w", err)
+	}
+
+	// File names are prefixed with time of creation
+	sort.Strings(files)
+
+	client := controltowerv1grpc.NewToolServiceClient(config.ClientConnection)
+
+	var errs []error
+	for _, file := range files {
+		err := replaySyncSpoolFile(ctx, client, config.RetryPolicy, config.Cipher, file, &stats)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to replay %s: %w", file, err))
+			continue
+		}
+
+		if err := os.Remove(file); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove replayed spool file: %w", err))
+		}
+	}
+
+	return stats, errors.Join(errs...)
+}
+
+func replaySyncSpoolFile(ctx context.Context, client controltowerv1grpc.ToolServiceClient,
+	retryPolicy SyncRetryPolicy, cipher encryption.Cipher, path string, stats *SyncStats,
+) (err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), syncSpoolMaxRecordSize)
+
+	var session *controltowerv1.ToolSession
+	var failed int
+
+	// Spool without a complete record is from an interrupted scan
+	status := controltowerv1.CompleteToolSessionRequest_STATUS_ERROR
+
+	// Session created for replay is completed on every exit so that it is
+	// not left open. Spool is retained on partial replay, hence a later
+	// attempt creates a new session with all the records.
+	defer func() {
+		if session == nil {
+			return
+		}
+
+		if err != nil {
+			status = controltowerv1.CompleteToolSessionRequest_STATUS_ERROR
+		}
+
+		completeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), syncReporterCompleteSessionTimeout)
+		defer cancel()
+
+		_, completeErr := client.CompleteToolSession(completeCtx, &controltowerv1.CompleteToolSessionRequest{
+			ToolSession: session,
+			Status:      status,
+		})
+		if completeErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to complete tool session: %w", completeErr))
+		}
+	}()
+
+	// Last record is truncated when the process is killed while writing
+	var truncated error
+
+	for scanner.Scan() {
+		if truncated != nil {
+			return truncated
+		}
+
+		record, err := readSyncSpoolRecord(cipher, scanner.Bytes())
+		if err != nil {
+			truncated = err
+			continue
+		}
+
+		if session == nil && record.Kind != syncSpoolRecordSession {
+			return fmt.Errorf("spool does not start with a session record")
+		}
+
+		switch record.Kind {
+		case syncSpoolRecordSession:
+			var req controltowerv1.CreateToolSessionRequest
+			if err := protojson.Unmarshal(record.Payload, &req); err != nil {
+				return fmt.Errorf("failed to parse session record: %w", err)
+			}
+
+			res, err := client.CreateToolSession(ctx, &req)
+			if err != nil {
+				return fmt.Errorf("failed to create tool session: %w", err)
+			}
+
+			session = res.GetToolSession()
+			logger.Debugf("Report Sync: Replaying spool %s with session ID: %s",
+				path, session.GetToolSessionId())
+		case syncSpoolRecordPackageInsight:
+			var req controltowerv1.PublishPackageInsightRequest
+			if err := protojson.Unmarshal(record.Payload, &req); err != nil {
+				return fmt.Errorf("failed to parse package insight record: %w", err)
+			}
+
+			req.ToolSession = session
+			err = retryPolicy.run(ctx, "package insight publish", func(ctx context.Context) error {
+				_, err := client.PublishPackageInsight(ctx, &req)
+				return err
+			})
+
+			stats.record(err)
+			if err != nil {
+				failed++
+			}
+		case syncSpoolRecordPolicyViolation:
+			var req controltowerv1.PublishPolicyViolationRequest
+			if err := protojson.Unmarshal(record.Payload, &req); err != nil {
+				return fmt.Errorf("failed to parse policy violation record: %w", err)
+			}
+
+			req.ToolSession = session
+			err = retryPolicy.run(ctx, "policy violation publish", func(ctx context.Context) error {
+				_, err := client.PublishPolicyViolation(ctx, &req)
+				return err
+			})
+
+			stats.record(err)
+			if err != nil {
+				failed++
+			}
+		case syncSpoolRecordComplete:
+			if v, ok := controltowerv1.CompleteToolSessionRequest_Status_value[record.Status]; ok {
+				status = controltowerv1.CompleteToolSessionRequest_Status(v)
+			}
+		default:
+			logger.Warnf("Report Sync: Ignoring unknown spool record: %s", record.Kind)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read spool: %w", err)
+	}
+
+	if session == nil {
+		if truncated != nil {
+			return truncated
+		}
+
+		return fmt.Errorf("spool does not have a session record")
+	}
+
+	if truncated != nil {
+		logger.Warnf("Report Sync: Skipping truncated last record of spool %s: %v", path, truncated)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d records failed to publish", failed)
+	}
+
+	return nil
+}
+
+func readSyncSpoolRecord(cipher encryption.Cipher, line []byte) (syncSpoolRecord, error) {
+	var record syncSpoolRecord
+	if err := json.Unmarshal(line, &record); err != nil {
+		return record, fmt.Errorf("failed to parse spool record: %w", err)
+	}
+
+	if len(record.Sealed) == 0 {
+		// Plaintext records are not authenticated
+		if cipher != nil {
+			return record, encryption.ErrNotEncrypted
+		}
+
+		return record, nil
+	}
+
+	if cipher == nil {
+		return record, encryption.ErrNoKey
+	}
+
+	data, err := cipher.Decrypt(record.Sealed)
+	if err != nil {
+		return record, fmt.Errorf("failed to decrypt spool record: %w", err)
+	}
+
+	record = syncSpoolRecord{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return record, fmt.Errorf("failed to parse spool record: %w", err)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,239 @@
+package reporter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"buf.build/gen/go/safedep/api/grpc/go/safedep/services/controltower/v1/controltowerv1grpc"
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/safedep/vet/pkg/common/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type spoolTestToolServiceClient struct {
+	controltowerv1grpc.ToolServiceClient
+
+	publishErr error
+	projects   []string
+	packages   []string
+	statuses   []controltowerv1.CompleteToolSessionRequest_Status
+}
+
+func (c *spoolTestToolServiceClient) CreateToolSession(_ context.Context,
+	req *controltowerv1.CreateToolSessionRequest, _ ...grpc.CallOption,
+) (*controltowerv1.CreateToolSessionResponse, error) {
+	c.projects = append(c.projects, req.GetProjectName())
+	return &controltowerv1.CreateToolSessionResponse{
+		ToolSession: &controltowerv1.ToolSession{ToolSessionId: "session-1"},
+	}, nil
+}
+
+func (c *spoolTestToolServiceClient) PublishPackageInsight(_ context.Context,
+	req *controltowerv1.PublishPackageInsightRequest, _ ...grpc.CallOption,
+) (*controltowerv1.PublishPackageInsightResponse, error) {
+	if c.publishErr != nil {
+		return nil, c.publishErr
+	}
+
+	c.packages = append(c.packages, req.GetToolSession().GetToolSessionId()+"/"+
+		req.GetPackageVersion().GetPackage().GetName())
+
+	return &controltowerv1.PublishPackageInsightResponse{}, nil
+}
+
+func (c *spoolTestToolServiceClient) CompleteToolSession(_ context.Context,
+	req *controltowerv1.CompleteToolSessionRequest, _ ...grpc.CallOption,
+) (*controltowerv1.CompleteToolSessionResponse, error) {
+	c.statuses = append(c.statuses, req.GetStatus())
+	return &controltowerv1.CompleteToolSessionResponse{}, nil
+}
+
+func writeTestSpool(t *testing.T, dir string, cipher encryption.Cipher, complete bool) string {
+	spool, err := newSyncSpool(dir, cipher, &controltowerv1.CreateToolSessionRequest{
+		ToolName:    "vet",
+		ProjectName: "test-project",
+	})
+	require.NoError(t, err)
+
+	for _, name := range []string{"lodash", "express"} {
+		err = spool.write(syncSpoolRecordPackageInsight, &controltowerv1.PublishPackageInsightRequest{
+			PackageVersion: &packagev1.PackageVersion{
+				Package: &packagev1.Package{Name: name},
+				Version: "1.0.0",
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	if !complete {
+		require.NoError(t, spool.writer.Flush())
+		require.NoError(t, spool.file.Close())
+		return spool.file.Name()
+	}
+
+	require.NoError(t, spool.complete(controltowerv1.CompleteToolSessionRequest_STATUS_SUCCESS))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+syncSpoolFileExtension))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	return files[0]
+}
+
+func TestSyncSpoolReplay(t *testing.T) {
+	cases := []struct {
+		name       string
+		publishErr error
+		err        bool
+		packages   []string
+		status     controltowerv1.CompleteToolSessionRequest_Status
+		stats      SyncStats
+	}{
+		{
+			"replay completed session",
+			nil, false,
+			[]string{"session-1/lodash", "session-1/express"},
+			controltowerv1.CompleteToolSessionRequest_STATUS_SUCCESS,
+			SyncStats{Published: 2},
+		},
+		{
+			"publish failure retains spool",
+			status.Error(codes.InvalidArgument, "bad request"), true,
+			nil,
+			controltowerv1.CompleteToolSessionRequest_STATUS_ERROR,
+			SyncStats{Failed: 2},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			file := writeTestSpool(t, t.TempDir(), nil, true)
+			client := &spoolTestToolServiceClient{publishErr: test.publishErr}
+
+			stats := SyncStats{}
+			err := replaySyncSpoolFile(context.Background(), client,
+				DefaultSyncRetryPolicy(), nil, file, &stats)
+
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, []string{"test-project"}, client.projects)
+			assert.Equal(t, test.packages, client.packages)
+			assert.Equal(t, []controltowerv1.CompleteToolSessionRequest_Status{test.status}, client.statuses)
+			assert.Equal(t, test.stats, stats)
+		})
+	}
+}
+
+func TestSyncSpoolPartialIsNotReplayed(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTestSpool(t, dir, nil, false)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+syncSpoolFileExtension))
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	// Interrupted session is replayed with error status once renamed
+	client := &spoolTestToolServiceClient{}
+	err = replaySyncSpoolFile(context.Background(), client, DefaultSyncRetryPolicy(), nil, file, &SyncStats{})
+	assert.NoError(t, err)
+	assert.Equal(t, []controltowerv1.CompleteToolSessionRequest_Status{
+		controltowerv1.CompleteToolSessionRequest_STATUS_ERROR,
+	}, client.statuses)
+
+	_, err = os.Stat(file)
+	assert.NoError(t, err)
+}
+
+func TestSyncSpoolReplayCorruptRecord(t *testing.T) {
+	cases := []struct {
+		name     string
+		trailer  string
+		err      bool
+		packages []string
+	}{
+		{
+			"truncated last record is skipped",
+			`{"kind":"package_ins`,
+			false,
+			[]string{"session-1/lodash", "session-1/express"},
+		},
+		{
+			"corrupt record is an error",
+			"{bad\n" + `{"kind":"complete","status":"STATUS_SUCCESS"}` + "\n",
+			true,
+			[]string{"session-1/lodash", "session-1/express"},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			file := writeTestSpool(t, t.TempDir(), nil, false)
+
+			f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o600)
+			require.NoError(t, err)
+
+			_, err = f.WriteString(test.trailer)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+
+			client := &spoolTestToolServiceClient{}
+			err = replaySyncSpoolFile(context.Background(), client, DefaultSyncRetryPolicy(), nil, file, &SyncStats{})
+
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Session is completed with error status on every exit
+			assert.Equal(t, test.packages, client.packages)
+			assert.Equal(t, []controltowerv1.CompleteToolSessionRequest_Status{
+				controltowerv1.CompleteToolSessionRequest_STATUS_ERROR,
+			}, client.statuses)
+		})
+	}
+}
+
+func TestSyncSpoolEncryption(t *testing.T) {
+	cipher, err := encryption.NewAesGcmCipher(make([]byte, encryption.KeySize))
+	require.NoError(t, err)
+
+	file := writeTestSpool(t, t.TempDir(), cipher, true)
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "test-project")
+	assert.NotContains(t, string(data), "lodash")
+
+	// Spool is not replayed without the key
+	client := &spoolTestToolServiceClient{}
+	err = replaySyncSpoolFile(context.Background(), client, DefaultSyncRetryPolicy(), nil, file, &SyncStats{})
+	assert.ErrorIs(t, err, encryption.ErrNoKey)
+	assert.Empty(t, client.projects)
+
+	stats := SyncStats{}
+	err = replaySyncSpoolFile(context.Background(), client, DefaultSyncRetryPolicy(), cipher, file, &stats)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test-project"}, client.projects)
+	assert.Equal(t, []string{"session-1/lodash", "session-1/express"}, client.packages)
+	assert.Equal(t, SyncStats{Published: 2}, stats)
+
+	// Plaintext spool is not replayed when a key is configured
+	plain := writeTestSpool(t, t.TempDir(), nil, true)
+
+	client = &spoolTestToolServiceClient{}
+	err = replaySyncSpoolFile(context.Background(), client, DefaultSyncRetryPolicy(), cipher, plain, &SyncStats{})
+	assert.ErrorIs(t, err, encryption.ErrNotEncrypted)
+	assert.Empty(t, client.projects)
+}
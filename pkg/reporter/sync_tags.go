@@ -0,0 +1,18 @@
+package reporter
+
+import (
+	"github.com/safedep/vet/pkg/models"
+)
+
+// Tags are labels since the rule has no attribute for them
+const syncTagLabelPrefix = "tag:"
+
+// tagLabels returns the labels of the tags of a finding sorted by key
+func (s *syncReporter) tagLabels(manifest *models.PackageManifest, findingId string) []string {
+	labels := []string{}
+	for _, label := range s.config.Tagger.Finding(manifest, findingId).Labels() {
+		labels = append(labels, syncTagLabelPrefix+label)
+	}
+
+	return labels
+}
@@ -0,0 +1,51 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/safedep/vet/gen/checks"
+	"github.com/safedep/vet/gen/filtersuite"
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncPolicyViolationTagLabels(t *testing.T) {
+	s := newSyncQueueTestReporter(t, SyncQueueOverflowBlock, &spoolTestToolServiceClient{})
+	pkgs := newSyncQueueTestPackages("lodash")
+
+	event := &analyzer.AnalyzerEvent{
+		Type: analyzer.ET_FilterExpressionMatched,
+		Filter: &filtersuite.Filter{
+			Name:      "critical-vulns",
+			CheckType: checks.CheckType_CheckTypeVulnerability,
+		},
+		Package: pkgs[0],
+	}
+
+	// No label for untagged manifest
+	_, req, err := s.policyViolationRequest(event)
+	assert.NoError(t, err)
+	assert.Empty(t, req.GetViolation().GetRule().GetLabels())
+
+	// Tags of the manifest are labels irrespective of the tagger
+	pkgs[0].Manifest.Tags = map[string]string{"business_unit": "payments"}
+
+	_, req, err = s.policyViolationRequest(event)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tag:business_unit=payments"}, req.GetViolation().GetRule().GetLabels())
+
+	tagger, err := tags.NewTagger(tags.Config{
+		Findings: []tags.FindingRule{
+			{Finding: "critical-vulns", Tags: tags.Tags{"priority": "p1"}},
+		},
+	}, nil)
+	assert.NoError(t, err)
+
+	s.config.Tagger = tagger
+
+	_, req, err = s.policyViolationRequest(event)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tag:business_unit=payments", "tag:priority=p1"},
+		req.GetViolation().GetRule().GetLabels())
+}
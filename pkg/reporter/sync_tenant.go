@@ -0,0 +1,107 @@
+package reporter
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	syncTenantIdEnvKey  = "VET_CONTROL_TOWER_TENANT_ID"
+	syncWorkspaceEnvKey = "VET_CONTROL_TOWER_WORKSPACE"
+	syncMockUserEnvKey  = "VET_CONTROL_TOWER_MOCK_USER"
+
+	syncTenantIdHeader  = "x-tenant-id"
+	syncWorkspaceHeader = "x-workspace-id"
+	syncMockUserHeader  = "x-mock-user"
+)
+
+// Tenant IDs are domains e.g. default-team.example.safedep.io
+var syncTenantIdPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?)*$`)
+
+var syncWorkspacePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// SyncTenantConfig selects the tenant and workspace of ControlTower the
+// sync reporter publishes to. Empty values fall back to the environment.
+type SyncTenantConfig struct {
+	// Required when connecting to ControlTower, falls back to
+	// VET_CONTROL_TOWER_TENANT_ID
+	TenantId string
+
+	// Optional workspace (team) within the tenant, falls back to
+	// VET_CONTROL_TOWER_WORKSPACE
+	Workspace string
+
+	// Optional user to impersonate in development setups of ControlTower,
+	// falls back to VET_CONTROL_TOWER_MOCK_USER
+	MockUser string
+}
+
+// withEnvironment returns the config with empty values read from environment
+func (c SyncTenantConfig) withEnvironment() SyncTenantConfig {
+	if c.TenantId == "" {
+		c.TenantId = os.Getenv(syncTenantIdEnvKey)
+	}
+
+	if c.Workspace == "" {
+		c.Workspace = os.Getenv(syncWorkspaceEnvKey)
+	}
+
+	if c.MockUser == "" {
+		c.MockUser = os.Getenv(syncMockUserEnvKey)
+	}
+
+	c.TenantId = strings.TrimSpace(c.TenantId)
+	c.Workspace = strings.TrimSpace(c.Workspace)
+	c.MockUser = strings.TrimSpace(c.MockUser)
+
+	return c
+}
+
+// Validate returns an error when the tenant ID is missing or any of the
+// values is malformed
+func (c SyncTenantConfig) Validate() error {
+	if c.TenantId == "" {
+		return fmt.Errorf("missing ControlTower tenant ID, set it with --report-sync-tenant or %s, "+
+			"or run `vet cloud quickstart` to configure a tenant", syncTenantIdEnvKey)
+	}
+
+	if !syncTenantIdPattern.MatchString(c.TenantId) {
+		return fmt.Errorf("invalid ControlTower tenant ID %q, must be a domain e.g. default-team.example.safedep.io",
+			c.TenantId)
+	}
+
+	if c.Workspace != "" && !syncWorkspacePattern.MatchString(c.Workspace) {
+		return fmt.Errorf("invalid ControlTower workspace %q, must contain only letters, digits, '.', '_' and '-'",
+			c.Workspace)
+	}
+
+	if strings.ContainsAny(c.MockUser, " \t\r\n") {
+		return fmt.Errorf("invalid ControlTower mock user %q, must not contain whitespace", c.MockUser)
+	}
+
+	return nil
+}
+
+// headers returns a copy of headers with the tenant selection applied,
+// overriding values already present
+func (c SyncTenantConfig) headers(headers http.Header) http.Header {
+	ret := headers.Clone()
+	if ret == nil {
+		ret = http.Header{}
+	}
+
+	ret.Set(syncTenantIdHeader, c.TenantId)
+
+	if c.Workspace != "" {
+		ret.Set(syncWorkspaceHeader, c.Workspace)
+	}
+
+	if c.MockUser != "" {
+		ret.Set(syncMockUserHeader, c.MockUser)
+	}
+
+	return ret
+}
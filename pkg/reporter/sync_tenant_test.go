@@ -0,0 +1,95 @@
+package reporter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncTenantConfigWithEnvironment(t *testing.T) {
+	t.Setenv(syncTenantIdEnvKey, "env-team.example.safedep.io")
+	t.Setenv(syncWorkspaceEnvKey, "env-workspace")
+	t.Setenv(syncMockUserEnvKey, "")
+
+	config := SyncTenantConfig{TenantId: " team.example.safedep.io "}.withEnvironment()
+
+	assert.Equal(t, "team.example.safedep.io", config.TenantId)
+	assert.Equal(t, "env-workspace", config.Workspace)
+	assert.Empty(t, config.MockUser)
+}
+
+func TestSyncTenantConfigValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config SyncTenantConfig
+		err    string
+	}{
+		{
+			"valid",
+			SyncTenantConfig{TenantId: "default-team.example.safedep.io", Workspace: "platform_team-1", MockUser: "user@example.com"},
+			"",
+		},
+		{
+			"missing tenant",
+			SyncTenantConfig{Workspace: "platform"},
+			"missing ControlTower tenant ID",
+		},
+		{
+			"tenant is not a domain",
+			SyncTenantConfig{TenantId: "https://example.safedep.io"},
+			"invalid ControlTower tenant ID",
+		},
+		{
+			"invalid workspace",
+			SyncTenantConfig{TenantId: "example.safedep.io", Workspace: "platform team"},
+			"invalid ControlTower workspace",
+		},
+		{
+			"invalid mock user",
+			SyncTenantConfig{TenantId: "example.safedep.io", MockUser: "user\nx-tenant-id: other"},
+			"invalid ControlTower mock user",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestSyncTenantConfigHeaders(t *testing.T) {
+	base := http.Header{}
+	base.Set(syncTenantIdHeader, "stale.example.safedep.io")
+	base.Set("x-custom", "value")
+
+	headers := SyncTenantConfig{
+		TenantId:  "team.example.safedep.io",
+		Workspace: "platform",
+	}.headers(base)
+
+	assert.Equal(t, "team.example.safedep.io", headers.Get(syncTenantIdHeader))
+	assert.Equal(t, "platform", headers.Get(syncWorkspaceHeader))
+	assert.Empty(t, headers.Get(syncMockUserHeader))
+	assert.Equal(t, "value", headers.Get("x-custom"))
+
+	// Headers given by the caller are not modified
+	assert.Equal(t, "stale.example.safedep.io", base.Get(syncTenantIdHeader))
+}
+
+func TestNewSyncReporterRequiresTenant(t *testing.T) {
+	t.Setenv(syncTenantIdEnvKey, "")
+
+	_, err := NewSyncReporter(SyncReporterConfig{
+		ProjectName: "test",
+		Connection:  SyncConnectionConfig{Url: "https://api.safedep.io"},
+	})
+
+	assert.ErrorContains(t, err, "missing ControlTower tenant ID")
+}
@@ -0,0 +1,183 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"buf.build/gen/go/safedep/api/grpc/go/safedep/services/controltower/v1/controltowerv1grpc"
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/safedep/vet/pkg/common/errcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestSyncReporterRecordOutcome(t *testing.T) {
+	s := &syncReporter{}
+
+	s.recordOutcome(nil)
+	s.recordOutcome(nil)
+	s.recordOutcome(errSyncSkipped)
+	s.recordOutcome(fmt.Errorf("wrapped: %w", errSyncSkipped))
+
+	for i := 0; i < syncReporterMaxReportedFailures+5; i++ {
+		s.recordOutcome(errors.New("failed to publish"))
+	}
+
+	stats := s.SyncStats()
+	assert.Equal(t, 2, stats.Published)
+	assert.Equal(t, 2, stats.Skipped)
+	assert.Equal(t, syncReporterMaxReportedFailures+5, stats.Failed)
+	assert.Equal(t, syncReporterMaxReportedFailures+9, stats.Total())
+	assert.Len(t, s.failures, syncReporterMaxReportedFailures)
+}
+
+func TestSyncSessionPoolGetOrCreateKeyedSession(t *testing.T) {
+	pool := &syncSessionPool{syncSessions: make(map[string]syncSession)}
+
+	var created atomic.Int32
+	create := func(id string, err error) func() (syncSession, error) {
+		return func() (syncSession, error) {
+			created.Add(1)
+			return syncSession{sessionId: id}, err
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			session, err := pool.getOrCreateKeyedSession("a/package-lock.json", create("session-a", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, "session-a", session.sessionId)
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, int32(1), created.Load())
+
+	// Failure is not retried
+	for i := 0; i < 2; i++ {
+		_, err := pool.getOrCreateKeyedSession("b/go.mod", create("session-b", errors.New("quota exceeded")))
+		assert.ErrorContains(t, err, "quota exceeded")
+	}
+
+	assert.Equal(t, int32(2), created.Load())
+
+	_, err := pool.getSession("b/go.mod")
+	assert.Error(t, err)
+}
+
+type completeTestToolServiceClient struct {
+	controltowerv1grpc.ToolServiceClient
+
+	m         sync.Mutex
+	completed []string
+}
+
+func (c *completeTestToolServiceClient) CompleteToolSession(_ context.Context,
+	req *controltowerv1.CompleteToolSessionRequest, _ ...grpc.CallOption,
+) (*controltowerv1.CompleteToolSessionResponse, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	id := req.GetToolSession().GetToolSessionId()
+	c.completed = append(c.completed, id)
+	if id == "session-bad" {
+		return nil, errors.New("unavailable")
+	}
+
+	return &controltowerv1.CompleteToolSessionResponse{}, nil
+}
+
+func TestSyncReporterFinishCompletesEverySession(t *testing.T) {
+	client := &completeTestToolServiceClient{}
+
+	s := &syncReporter{
+		ctx:        context.Background(),
+		config:     &SyncReporterConfig{EnableMultiProjectSync: true, WorkerCount: 1},
+		done:       make(chan bool),
+		workQueue:  make(chan *workItem, 1),
+		sessions:   &syncSessionPool{syncSessions: make(map[string]syncSession)},
+		scorecards: newSyncScorecardRegistry(),
+	}
+
+	for _, id := range []string{"session-a", "session-bad", "session-c"} {
+		s.sessions.addKeyedSession(id+"/package-lock.json",
+			syncSession{sessionId: id, toolServiceClient: client})
+	}
+
+	s.startWorkers()
+
+	err := s.Finish()
+	assert.ErrorContains(t, err, "failed to complete 1 of 3 tool sessions")
+	assert.ErrorContains(t, err, "session-bad")
+	assert.ElementsMatch(t, []string{"session-a", "session-bad", "session-c"}, client.completed)
+}
+
+type scanErrorTestToolServiceClient struct {
+	controltowerv1grpc.ToolServiceClient
+
+	statuses []controltowerv1.CompleteToolSessionRequest_Status
+}
+
+func (c *scanErrorTestToolServiceClient) CompleteToolSession(_ context.Context,
+	req *controltowerv1.CompleteToolSessionRequest, _ ...grpc.CallOption,
+) (*controltowerv1.CompleteToolSessionResponse, error) {
+	c.statuses = append(c.statuses, req.GetStatus())
+	return &controltowerv1.CompleteToolSessionResponse{}, nil
+}
+
+func TestSyncReporterFinishScanError(t *testing.T) {
+	cases := []struct {
+		name    string
+		scanErr error
+		status  controltowerv1.CompleteToolSessionRequest_Status
+	}{
+		{
+			"successful scan",
+			nil,
+			controltowerv1.CompleteToolSessionRequest_STATUS_SUCCESS,
+		},
+		{
+			"failed scan",
+			errors.New("failed to read manifest"),
+			controltowerv1.CompleteToolSessionRequest_STATUS_ERROR,
+		},
+		{
+			"policy requested to fail",
+			errcode.Errorf(errcode.PolicyFailed, "critical vulnerability"),
+			controltowerv1.CompleteToolSessionRequest_STATUS_SUCCESS,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			client := &scanErrorTestToolServiceClient{}
+
+			s := &syncReporter{
+				ctx:        context.Background(),
+				config:     &SyncReporterConfig{WorkerCount: 1},
+				done:       make(chan bool),
+				workQueue:  make(chan *workItem, 1),
+				sessions:   &syncSessionPool{syncSessions: make(map[string]syncSession)},
+				scorecards: newSyncScorecardRegistry(),
+			}
+
+			s.sessions.addPrimarySession(syncSession{sessionId: "session-1", toolServiceClient: client})
+			s.startWorkers()
+
+			s.SetScanError(test.scanErr)
+			assert.NoError(t, s.Finish())
+
+			require.Len(t, client.statuses, 1)
+			assert.Equal(t, test.status, client.statuses[0])
+		})
+	}
+}
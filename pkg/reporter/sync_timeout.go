@@ -0,0 +1,25 @@
+package reporter
+
+import (
+	"context"
+	"time"
+)
+
+// A slow call must not hold a sync worker indefinitely
+const syncReporterDefaultPublishTimeout = 30 * time.Second
+
+// withPublishTimeout bounds each call of fn by timeout. The call is
+// cancelled earlier when ctx is done e.g. the sync is cancelled or the
+// overall sync deadline passes. A timeout of zero or less is unbounded.
+func withPublishTimeout(timeout time.Duration, fn func(ctx context.Context) error) func(ctx context.Context) error {
+	if timeout <= 0 {
+		return fn
+	}
+
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return fn(ctx)
+	}
+}
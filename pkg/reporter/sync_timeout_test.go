@@ -0,0 +1,88 @@
+package reporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Blocks every publish until the call is cancelled
+type slowTestToolServiceClient struct {
+	spoolTestToolServiceClient
+}
+
+func (c *slowTestToolServiceClient) PublishPackageInsight(ctx context.Context,
+	_ *controltowerv1.PublishPackageInsightRequest, _ ...grpc.CallOption,
+) (*controltowerv1.PublishPackageInsightResponse, error) {
+	<-ctx.Done()
+	return nil, status.FromContextError(ctx.Err()).Err()
+}
+
+func TestWithPublishTimeout(t *testing.T) {
+	cases := []struct {
+		name           string
+		timeout        time.Duration
+		parentTimeout  time.Duration
+		expectDeadline bool
+		expectErr      error
+	}{
+		{"unbounded", 0, 0, false, nil},
+		{"negative is unbounded", -time.Second, 0, false, nil},
+		{"call timeout", 10 * time.Millisecond, 0, true, context.DeadlineExceeded},
+		{"parent deadline before call timeout", time.Hour, 10 * time.Millisecond, true, context.DeadlineExceeded},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.parentTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, test.parentTimeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+			err := withPublishTimeout(test.timeout, func(ctx context.Context) error {
+				_, ok := ctx.Deadline()
+				assert.Equal(t, test.expectDeadline, ok)
+
+				if !ok {
+					return nil
+				}
+
+				<-ctx.Done()
+				return ctx.Err()
+			})(ctx)
+
+			assert.ErrorIs(t, err, test.expectErr)
+			assert.Less(t, time.Since(start), time.Minute)
+		})
+	}
+}
+
+func TestSyncPublishPackageInsightTimeout(t *testing.T) {
+	client := &slowTestToolServiceClient{}
+
+	s := newSyncQueueTestReporter(t, SyncQueueOverflowBlock, &client.spoolTestToolServiceClient)
+	s.config.PublishTimeout = 20 * time.Millisecond
+
+	session := syncSession{sessionId: "session-1", toolServiceClient: client}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.publishPackageInsight(&session, &controltowerv1.PublishPackageInsightRequest{})
+	}()
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	case <-time.After(5 * time.Second):
+		t.Fatal("publish was not bounded by the publish timeout")
+	}
+}
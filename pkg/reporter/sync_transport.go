@@ -0,0 +1,288 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/httpclient"
+	"github.com/safedep/vet/pkg/common/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// SyncTransport is the protocol used to call ControlTower
+type SyncTransport string
+
+const (
+	// gRPC over HTTP/2, the default
+	SyncTransportGrpc = SyncTransport("grpc")
+
+	// Unary HTTP POST as per the Connect protocol, for networks that
+	// block gRPC
+	SyncTransportHttp = SyncTransport("http")
+)
+
+// Responses of ToolService are small, this guards against a misbehaving
+// proxy returning a large error page
+const syncHttpMaxResponseSize = 4 << 20
+
+// syncTransportConnection carries the calls of the ToolService client to
+// ControlTower. *grpc.ClientConn is the gRPC implementation.
+type syncTransportConnection interface {
+	grpc.ClientConnInterface
+	Close() error
+}
+
+// newSyncTransportConnection creates a connection for the transport of
+// the config
+func newSyncTransportConnection(config SyncConnectionConfig) (syncTransportConnection, error) {
+	switch config.Transport {
+	case "", SyncTransportGrpc:
+		conn, err := newSyncClientConnection(config)
+		if err != nil {
+			return nil, err
+		}
+
+		return conn, nil
+	case SyncTransportHttp:
+		conn, err := newSyncHttpConnection(config)
+		if err != nil {
+			return nil, err
+		}
+
+		return conn, nil
+	default:
+		return nil, fmt.Errorf("unsupported sync transport: %s", config.Transport)
+	}
+}
+
+// syncHttpConnection calls unary methods of ToolService as HTTP POST of
+// the binary encoded request to /<service>/<method>. Errors are mapped to
+// gRPC status so that retry and spooling work the same as with gRPC.
+type syncHttpConnection struct {
+	baseUrl    string
+	client     *http.Client
+	credential *syncTokenCredential
+}
+
+func newSyncHttpConnection(config SyncConnectionConfig) (*syncHttpConnection, error) {
+	address, err := config.address()
+	if err != nil {
+		return nil, err
+	}
+
+	// Path of the URL is retained for ControlTower behind a path based proxy
+	parsedUrl, err := url.Parse(config.Url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sync url: %w", err)
+	}
+
+	scheme := "https"
+	if config.Plaintext {
+		if config.CACertFile != "" || config.InsecureSkipVerify {
+			return nil, errors.New("TLS options are not allowed with plaintext connection")
+		}
+
+		scheme = "http"
+	}
+
+	proxyUrl, err := config.proxyUrl()
+	if err != nil {
+		return nil, err
+	}
+
+	// Connections are pooled with other outbound requests unless the
+	// connection needs its own TLS or proxy configuration
+	transport := httpclient.Transport()
+	if config.CACertFile != "" || config.InsecureSkipVerify || proxyUrl != nil {
+		transport = transport.Clone()
+
+		if !config.Plaintext {
+			tlsConfig, err := config.tlsConfig()
+			if err != nil {
+				return nil, err
+			}
+
+			transport.TLSClientConfig = tlsConfig
+		}
+
+		if proxyUrl != nil {
+			transport.Proxy = http.ProxyURL(proxyUrl)
+		}
+	}
+
+	logger.Debugf("Report Sync: Using HTTP transport for %s (plaintext: %t, proxy: %t)",
+		address, config.Plaintext, config.ProxyUrl != "")
+
+	return &syncHttpConnection{
+		baseUrl: scheme + "://" + address + strings.TrimSuffix(parsedUrl.Path, "/"),
+		client:  &http.Client{Transport: transport},
+		credential: &syncTokenCredential{
+			apiKey:      config.ApiKey,
+			credentials: config.Credentials,
+			headers:     config.Headers,
+		},
+	}, nil
+}
+
+func (c *syncHttpConnection) Invoke(ctx context.Context, method string, args any, reply any, _ ...grpc.CallOption) error {
+	req, ok := args.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unsupported request type: %T", args)
+	}
+
+	res, ok := reply.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unsupported response type: %T", reply)
+	}
+
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal request: %v", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseUrl+method, bytes.NewReader(body))
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to create request: %v", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/proto")
+	httpReq.Header.Set("Connect-Protocol-Version", "1")
+
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := max(time.Until(deadline).Milliseconds(), 1)
+		httpReq.Header.Set("Connect-Timeout-Ms", strconv.FormatInt(timeout, 10))
+	}
+
+	md, err := c.credential.GetRequestMetadata(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	for k, v := range md {
+		httpReq.Header.Set(k, v)
+	}
+
+	// Metadata of the call e.g. summary of the session
+	outgoing, _ := metadata.FromOutgoingContext(ctx)
+	for k, values := range outgoing {
+		for _, v := range values {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+
+			httpReq.Header.Add(k, v)
+		}
+	}
+
+	httpRes, err := c.client.Do(httpReq)
+	if err != nil {
+		return syncHttpTransportError(ctx, err)
+	}
+
+	defer httpRes.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(httpRes.Body, syncHttpMaxResponseSize))
+	if err != nil {
+		return syncHttpTransportError(ctx, err)
+	}
+
+	if httpRes.StatusCode != http.StatusOK {
+		return syncHttpStatusError(httpRes.StatusCode, data)
+	}
+
+	if err := proto.Unmarshal(data, res); err != nil {
+		return status.Errorf(codes.Internal, "failed to unmarshal response: %v", err)
+	}
+
+	return nil
+}
+
+// NewStream is not required since all methods of ToolService are unary
+func (c *syncHttpConnection) NewStream(_ context.Context, _ *grpc.StreamDesc, method string,
+	_ ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "streaming %s is not supported by HTTP transport", method)
+}
+
+func (c *syncHttpConnection) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+func syncHttpTransportError(ctx context.Context, err error) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	return status.Errorf(codes.Unavailable, "failed to call ControlTower: %v", err)
+}
+
+var syncHttpErrorCodes = map[string]codes.Code{
+	"canceled":            codes.Canceled,
+	"unknown":             codes.Unknown,
+	"invalid_argument":    codes.InvalidArgument,
+	"deadline_exceeded":   codes.DeadlineExceeded,
+	"not_found":           codes.NotFound,
+	"already_exists":      codes.AlreadyExists,
+	"permission_denied":   codes.PermissionDenied,
+	"resource_exhausted":  codes.ResourceExhausted,
+	"failed_precondition": codes.FailedPrecondition,
+	"aborted":             codes.Aborted,
+	"out_of_range":        codes.OutOfRange,
+	"unimplemented":       codes.Unimplemented,
+	"internal":            codes.Internal,
+	"unavailable":         codes.Unavailable,
+	"data_loss":           codes.DataLoss,
+	"unauthenticated":     codes.Unauthenticated,
+}
+
+// syncHttpStatusError maps the error of a call to gRPC status, using the
+// HTTP status when the body is not a Connect error e.g. from a proxy
+func syncHttpStatusError(statusCode int, body []byte) error {
+	var connectErr struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(body, &connectErr); err == nil {
+		if code, ok := syncHttpErrorCodes[connectErr.Code]; ok {
+			return status.Error(code, connectErr.Message)
+		}
+	}
+
+	code := codes.Unknown
+	switch statusCode {
+	case http.StatusBadRequest:
+		code = codes.Internal
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.Unimplemented
+	case http.StatusTooManyRequests:
+		// Rate limiter backs off on resource exhausted
+		code = codes.ResourceExhausted
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		code = codes.Unavailable
+	}
+
+	return status.Errorf(code, "ControlTower responded with HTTP %d", statusCode)
+}
@@ -0,0 +1,179 @@
+package reporter
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"buf.build/gen/go/safedep/api/grpc/go/safedep/services/controltower/v1/controltowerv1grpc"
+	controltowerv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/controltower/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSyncHttpConnectionInvoke(t *testing.T) {
+	var headers http.Header
+	var path string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers, path = r.Header, r.URL.Path
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var req controltowerv1.CreateToolSessionRequest
+		require.NoError(t, proto.Unmarshal(body, &req))
+
+		data, err := proto.Marshal(&controltowerv1.CreateToolSessionResponse{
+			ToolSession: &controltowerv1.ToolSession{ToolSessionId: "session-" + req.GetProjectName()},
+		})
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/proto")
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	conn, err := newSyncTransportConnection(SyncConnectionConfig{
+		Url:       server.URL + "/controltower/",
+		Transport: SyncTransportHttp,
+		ApiKey:    "test-key",
+		Headers:   http.Header{"X-Tenant-Id": []string{"tenant"}},
+		Plaintext: true,
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-test-bin", `{"packages":1}`)
+	res, err := controltowerv1grpc.NewToolServiceClient(conn).CreateToolSession(ctx,
+		&controltowerv1.CreateToolSessionRequest{ToolName: "vet", ProjectName: "app"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "session-app", res.GetToolSession().GetToolSessionId())
+	assert.Equal(t, "/controltower/safedep.services.controltower.v1.ToolService/CreateToolSession", path)
+	assert.Equal(t, "application/proto", headers.Get("Content-Type"))
+	assert.Equal(t, "test-key", headers.Get("Authorization"))
+	assert.Equal(t, "tenant", headers.Get("X-Tenant-Id"))
+
+	value, err := base64.RawStdEncoding.DecodeString(headers.Get("x-test-bin"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"packages":1}`, string(value))
+}
+
+func TestSyncHttpConnectionErrors(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		body       string
+		code       codes.Code
+	}{
+		{
+			"connect error",
+			http.StatusTooManyRequests,
+			`{"code": "resource_exhausted", "message": "slow down"}`,
+			codes.ResourceExhausted,
+		},
+		{
+			"connect error with unknown code",
+			http.StatusInternalServerError,
+			`{"code": "teapot"}`,
+			codes.Unknown,
+		},
+		{
+			"unauthorized",
+			http.StatusUnauthorized,
+			"",
+			codes.Unauthenticated,
+		},
+		{
+			"proxy error page",
+			http.StatusBadGateway,
+			"<html>Bad Gateway</html>",
+			codes.Unavailable,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(test.statusCode)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			conn, err := newSyncHttpConnection(SyncConnectionConfig{Url: server.URL, Plaintext: true})
+			require.NoError(t, err)
+
+			_, err = controltowerv1grpc.NewToolServiceClient(conn).CompleteToolSession(context.Background(),
+				&controltowerv1.CompleteToolSessionRequest{})
+			assert.Equal(t, test.code, status.Code(err))
+		})
+	}
+}
+
+func TestSyncHttpConnectionUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	conn, err := newSyncHttpConnection(SyncConnectionConfig{Url: url, Plaintext: true})
+	require.NoError(t, err)
+
+	_, err = controltowerv1grpc.NewToolServiceClient(conn).PublishPolicyViolation(context.Background(),
+		&controltowerv1.PublishPolicyViolationRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestNewSyncTransportConnection(t *testing.T) {
+	cases := []struct {
+		name   string
+		config SyncConnectionConfig
+		err    string
+	}{
+		{
+			"default is grpc",
+			SyncConnectionConfig{Url: "https://api.safedep.io"},
+			"",
+		},
+		{
+			"http",
+			SyncConnectionConfig{Url: "https://api.safedep.io", Transport: SyncTransportHttp},
+			"",
+		},
+		{
+			"http plaintext with TLS options",
+			SyncConnectionConfig{Url: "http://localhost:8080", Transport: SyncTransportHttp, Plaintext: true, InsecureSkipVerify: true},
+			"TLS options are not allowed",
+		},
+		{
+			"http with invalid proxy",
+			SyncConnectionConfig{Url: "https://api.safedep.io", Transport: SyncTransportHttp, ProxyUrl: "socks5://proxy:1080"},
+			"proxy url must be http://host:port",
+		},
+		{
+			"unsupported transport",
+			SyncConnectionConfig{Url: "https://api.safedep.io", Transport: SyncTransport("websocket")},
+			"unsupported sync transport",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			conn, err := newSyncTransportConnection(test.config)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NoError(t, conn.Close())
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package reporter
+
+import (
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/history"
+	"github.com/safedep/vet/pkg/models"
+)
+
+// Triage state is a label since the rule has no attribute for it
+const syncTriageLabelPrefix = "triage:"
+
+// triageLabel returns the label of the triage state of a finding or an
+// empty string when triage states are not available
+func (s *syncReporter) triageLabel(pkg *models.Package, findingId string) string {
+	if s.config.Triage == nil {
+		return ""
+	}
+
+	triage, err := history.Triage(s.config.Triage, history.NewFindingKey(pkg, findingId))
+	if err != nil {
+		logger.Warnf("Report Sync: Failed to get triage of %s for %s: %v", findingId, pkg.ShortName(), err)
+		return ""
+	}
+
+	return syncTriageLabelPrefix + string(triage.State)
+}
@@ -0,0 +1,52 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/safedep/vet/gen/checks"
+	"github.com/safedep/vet/gen/filtersuite"
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/history"
+	"github.com/safedep/vet/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncPolicyViolationTriageLabel(t *testing.T) {
+	s := newSyncQueueTestReporter(t, SyncQueueOverflowBlock, &spoolTestToolServiceClient{})
+	pkgs := newSyncQueueTestPackages("lodash", "express")
+
+	event := func(pkgIndex int) *analyzer.AnalyzerEvent {
+		return &analyzer.AnalyzerEvent{
+			Type: analyzer.ET_FilterExpressionMatched,
+			Filter: &filtersuite.Filter{
+				Name:      "critical-vulns",
+				CheckType: checks.CheckType_CheckTypeVulnerability,
+			},
+			Package: pkgs[pkgIndex],
+		}
+	}
+
+	// No label without triage store
+	_, req, err := s.policyViolationRequest(event(0))
+	assert.NoError(t, err)
+	assert.Empty(t, req.GetViolation().GetRule().GetLabels())
+
+	store, err := history.NewKeyValueTriageStore(storage.NewMemoryKeyValueStorage())
+	assert.NoError(t, err)
+
+	err = store.PutTriage(history.FindingTriage{
+		FindingKey: history.NewFindingKey(pkgs[0], "critical-vulns"),
+		State:      history.TriageStateAcceptedRisk,
+	})
+	assert.NoError(t, err)
+
+	s.config.Triage = store
+
+	_, req, err = s.policyViolationRequest(event(0))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"triage:accepted-risk"}, req.GetViolation().GetRule().GetLabels())
+
+	_, req, err = s.policyViolationRequest(event(1))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"triage:new"}, req.GetViolation().GetRule().GetLabels())
+}
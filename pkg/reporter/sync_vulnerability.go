@@ -0,0 +1,74 @@
+package reporter
+
+import (
+	"strings"
+
+	vulnerabilityv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/vulnerability/v1"
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/vet/gen/insightapi"
+)
+
+// syncVulnerability maps a vulnerability from insights including its
+// severities and the aliases, so that the backend does not have to
+// resolve them again. Affected version ranges are not available in
+// insights and are not published.
+func syncVulnerability(v insightapi.PackageVulnerability) *vulnerabilityv1.Vulnerability {
+	vulnerability := &vulnerabilityv1.Vulnerability{
+		Id:         syncVulnerabilityIdentifier(utils.SafelyGetValue(v.Id)),
+		Summary:    utils.SafelyGetValue(v.Summary),
+		Aliases:    []*vulnerabilityv1.VulnerabilityIdentifier{},
+		Related:    []*vulnerabilityv1.VulnerabilityIdentifier{},
+		Severities: []*vulnerabilityv1.Severity{},
+	}
+
+	for _, alias := range utils.SafelyGetValue(v.Aliases) {
+		vulnerability.Aliases = append(vulnerability.Aliases, syncVulnerabilityIdentifier(alias))
+	}
+
+	for _, related := range utils.SafelyGetValue(v.Related) {
+		vulnerability.Related = append(vulnerability.Related, syncVulnerabilityIdentifier(related))
+	}
+
+	for _, s := range utils.SafelyGetValue(v.Severities) {
+		severity := &vulnerabilityv1.Severity{
+			Score: utils.SafelyGetValue(s.Score),
+		}
+
+		switch utils.SafelyGetValue(s.Type) {
+		case insightapi.PackageVulnerabilitySeveritiesTypeCVSSV2:
+			severity.Type = vulnerabilityv1.Severity_TYPE_CVSS_V2
+		case insightapi.PackageVulnerabilitySeveritiesTypeCVSSV3:
+			severity.Type = vulnerabilityv1.Severity_TYPE_CVSS_V3
+		}
+
+		switch utils.SafelyGetValue(s.Risk) {
+		case insightapi.PackageVulnerabilitySeveritiesRiskCRITICAL:
+			severity.Risk = vulnerabilityv1.Severity_RISK_CRITICAL
+		case insightapi.PackageVulnerabilitySeveritiesRiskHIGH:
+			severity.Risk = vulnerabilityv1.Severity_RISK_HIGH
+		case insightapi.PackageVulnerabilitySeveritiesRiskMEDIUM:
+			severity.Risk = vulnerabilityv1.Severity_RISK_MEDIUM
+		case insightapi.PackageVulnerabilitySeveritiesRiskLOW:
+			severity.Risk = vulnerabilityv1.Severity_RISK_LOW
+		}
+
+		vulnerability.Severities = append(vulnerability.Severities, severity)
+	}
+
+	return vulnerability
+}
+
+func syncVulnerabilityIdentifier(id string) *vulnerabilityv1.VulnerabilityIdentifier {
+	identifier := &vulnerabilityv1.VulnerabilityIdentifier{Value: id}
+
+	switch {
+	case strings.HasPrefix(id, "CVE-"):
+		identifier.Type = vulnerabilityv1.VulnerabilityIdentifierType_VULNERABILITY_IDENTIFIER_TYPE_CVE
+	case strings.HasPrefix(id, "GHSA-"):
+		identifier.Type = vulnerabilityv1.VulnerabilityIdentifierType_VULNERABILITY_IDENTIFIER_TYPE_GHSA
+	case strings.HasPrefix(id, "OSV-"):
+		identifier.Type = vulnerabilityv1.VulnerabilityIdentifierType_VULNERABILITY_IDENTIFIER_TYPE_OSV
+	}
+
+	return identifier
+}
@@ -0,0 +1,73 @@
+package reporter
+
+import (
+	"testing"
+
+	vulnerabilityv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/vulnerability/v1"
+	"github.com/safedep/vet/gen/insightapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncVulnerability(t *testing.T) {
+	id := "GHSA-35jh-r3h4-6jhm"
+	summary := "Command Injection in lodash"
+	aliases := []string{"CVE-2021-23337"}
+	score := "CVSS:3.1/AV:N/AC:L/PR:H/UI:N/S:U/C:H/I:H/A:H"
+	sevType := insightapi.PackageVulnerabilitySeveritiesTypeCVSSV3
+	risk := insightapi.PackageVulnerabilitySeveritiesRiskHIGH
+
+	v := insightapi.PackageVulnerability{
+		Id:      &id,
+		Summary: &summary,
+		Aliases: &aliases,
+	}
+
+	v.Severities = &[]struct {
+		Risk  *insightapi.PackageVulnerabilitySeveritiesRisk `json:"risk,omitempty"`
+		Score *string                                        `json:"score,omitempty"`
+		Type  *insightapi.PackageVulnerabilitySeveritiesType `json:"type,omitempty"`
+	}{
+		{Risk: &risk, Score: &score, Type: &sevType},
+		{},
+	}
+
+	vulnerability := syncVulnerability(v)
+
+	assert.Equal(t, id, vulnerability.GetId().GetValue())
+	assert.Equal(t, vulnerabilityv1.VulnerabilityIdentifierType_VULNERABILITY_IDENTIFIER_TYPE_GHSA,
+		vulnerability.GetId().GetType())
+	assert.Equal(t, summary, vulnerability.GetSummary())
+
+	assert.Len(t, vulnerability.GetAliases(), 1)
+	assert.Equal(t, "CVE-2021-23337", vulnerability.GetAliases()[0].GetValue())
+	assert.Equal(t, vulnerabilityv1.VulnerabilityIdentifierType_VULNERABILITY_IDENTIFIER_TYPE_CVE,
+		vulnerability.GetAliases()[0].GetType())
+	assert.Empty(t, vulnerability.GetRelated())
+
+	assert.Len(t, vulnerability.GetSeverities(), 2)
+	assert.Equal(t, vulnerabilityv1.Severity_TYPE_CVSS_V3, vulnerability.GetSeverities()[0].GetType())
+	assert.Equal(t, vulnerabilityv1.Severity_RISK_HIGH, vulnerability.GetSeverities()[0].GetRisk())
+	assert.Equal(t, score, vulnerability.GetSeverities()[0].GetScore())
+	assert.Equal(t, vulnerabilityv1.Severity_TYPE_UNSPECIFIED, vulnerability.GetSeverities()[1].GetType())
+	assert.Equal(t, vulnerabilityv1.Severity_RISK_UNSPECIFIED, vulnerability.GetSeverities()[1].GetRisk())
+}
+
+func TestSyncVulnerabilityIdentifier(t *testing.T) {
+	cases := []struct {
+		id       string
+		expected vulnerabilityv1.VulnerabilityIdentifierType
+	}{
+		{"CVE-2021-23337", vulnerabilityv1.VulnerabilityIdentifierType_VULNERABILITY_IDENTIFIER_TYPE_CVE},
+		{"GHSA-35jh-r3h4-6jhm", vulnerabilityv1.VulnerabilityIdentifierType_VULNERABILITY_IDENTIFIER_TYPE_GHSA},
+		{"OSV-2020-111", vulnerabilityv1.VulnerabilityIdentifierType_VULNERABILITY_IDENTIFIER_TYPE_OSV},
+		{"PYSEC-2021-19", vulnerabilityv1.VulnerabilityIdentifierType_VULNERABILITY_IDENTIFIER_TYPE_UNSPECIFIED},
+	}
+
+	for _, test := range cases {
+		t.Run(test.id, func(t *testing.T) {
+			identifier := syncVulnerabilityIdentifier(test.id)
+			assert.Equal(t, test.id, identifier.GetValue())
+			assert.Equal(t, test.expected, identifier.GetType())
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package scanner
 
-import "github.com/safedep/vet/pkg/models"
+import (
+	"github.com/safedep/vet/pkg/eventbus"
+	"github.com/safedep/vet/pkg/models"
+)
 
 type ScannerCallbackOnManifestFn func(manifest *models.PackageManifest)
 
@@ -27,6 +30,11 @@ func (s *packageManifestScanner) WithCallbacks(callbacks ScannerCallbacks) {
 	s.callbacks = callbacks
 }
 
+// WithEventBus publishes scan events to the bus in addition to callbacks
+func (s *packageManifestScanner) WithEventBus(bus *eventbus.Bus) {
+	s.events = bus
+}
+
 func (s *packageManifestScanner) dispatchStartManifestEnumeration() {
 	if s.callbacks.OnStartEnumerateManifest != nil {
 		s.callbacks.OnStartEnumerateManifest()
@@ -37,12 +45,16 @@ func (s *packageManifestScanner) dispatchOnManifestEnumeration(manifest *models.
 	if s.callbacks.OnEnumerateManifest != nil {
 		s.callbacks.OnEnumerateManifest(manifest)
 	}
+
+	s.events.Publish(eventbus.Event{Topic: eventbus.TopicManifestParsed, Manifest: manifest})
 }
 
 func (s *packageManifestScanner) dispatchOnStart() {
 	if s.callbacks.OnStart != nil {
 		s.callbacks.OnStart()
 	}
+
+	s.events.Publish(eventbus.Event{Topic: eventbus.TopicScanStarted})
 }
 
 func (s *packageManifestScanner) dispatchOnStartManifest(manifest *models.PackageManifest) {
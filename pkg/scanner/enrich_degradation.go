@@ -0,0 +1,127 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/safedep/vet/gen/insightapi"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/common/paths"
+	"github.com/safedep/vet/pkg/degradation"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/storage"
+)
+
+const (
+	insightsCacheNamespace = "insights_cache"
+	insightsCacheFileName  = "vet-insights-cache.db"
+)
+
+// DefaultInsightsCachePath is the path of the cache of insights used
+// when insights are unavailable
+func DefaultInsightsCachePath() (string, error) {
+	return paths.Resolve(paths.KindCache, insightsCacheFileName)
+}
+
+type DegradingEnricherConfig struct {
+	Matrix   degradation.Matrix
+	Recorder *degradation.Recorder
+
+	// Required for cache mode of insights, insights are not cached
+	// when not available
+	Cache storage.KeyValueStorage
+}
+
+// degradingEnricher applies the degradation matrix on failure of an
+// insights enricher. Insights obtained from the cache do not expand
+// transitive dependencies.
+type degradingEnricher struct {
+	enricher PackageMetaEnricher
+	config   DegradingEnricherConfig
+}
+
+var _ PackageMetaEnricher = (*degradingEnricher)(nil)
+
+func NewDegradingEnricher(enricher PackageMetaEnricher, config DegradingEnricherConfig) PackageMetaEnricher {
+	return &degradingEnricher{enricher: enricher, config: config}
+}
+
+func (e *degradingEnricher) Name() string {
+	return e.enricher.Name()
+}
+
+func (e *degradingEnricher) Enrich(pkg *models.Package, cb PackageDependencyCallbackFn) error {
+	err := e.enricher.Enrich(pkg, cb)
+	if err == nil {
+		e.cacheInsights(pkg)
+		return nil
+	}
+
+	if e.config.Matrix.Mode(degradation.SourceInsights) == degradation.ModeCache {
+		insights, cacheErr := e.cachedInsights(pkg)
+		if cacheErr == nil {
+			logger.Warnf("Using cached insights for %s/%s: %v", pkg.GetName(), pkg.GetVersion(), err)
+
+			pkg.Insights = insights
+			e.config.Recorder.Record(degradation.SourceInsights, degradation.ModeCache, err)
+			return nil
+		}
+
+		if !errors.Is(cacheErr, storage.ErrKeyNotFound) {
+			logger.Warnf("Failed to get cached insights for %s/%s: %v", pkg.GetName(), pkg.GetVersion(), cacheErr)
+		}
+	}
+
+	if e.config.Matrix.Mode(degradation.SourceOsv) == degradation.ModeUnknown {
+		pkg.VulnerabilitiesUnknown = true
+		e.config.Recorder.Record(degradation.SourceOsv, degradation.ModeUnknown, err)
+	}
+
+	return err
+}
+
+func (e *degradingEnricher) Wait() error {
+	return e.enricher.Wait()
+}
+
+func (e *degradingEnricher) cacheInsights(pkg *models.Package) {
+	if e.config.Cache == nil || pkg.Insights == nil ||
+		e.config.Matrix.Mode(degradation.SourceInsights) != degradation.ModeCache {
+		return
+	}
+
+	data, err := json.Marshal(pkg.Insights)
+	if err != nil {
+		logger.Warnf("Failed to serialize insights for cache: %v", err)
+		return
+	}
+
+	err = e.config.Cache.Put(context.Background(), insightsCacheNamespace, insightsCacheKey(pkg), data)
+	if err != nil {
+		logger.Warnf("Failed to cache insights: %v", err)
+	}
+}
+
+func (e *degradingEnricher) cachedInsights(pkg *models.Package) (*insightapi.PackageVersionInsight, error) {
+	if e.config.Cache == nil {
+		return nil, storage.ErrKeyNotFound
+	}
+
+	data, err := e.config.Cache.Get(context.Background(), insightsCacheNamespace, insightsCacheKey(pkg))
+	if err != nil {
+		return nil, err
+	}
+
+	var insights insightapi.PackageVersionInsight
+	if err := json.Unmarshal(data, &insights); err != nil {
+		return nil, fmt.Errorf("failed to parse cached insights: %w", err)
+	}
+
+	return &insights, nil
+}
+
+func insightsCacheKey(pkg *models.Package) string {
+	return fmt.Sprintf("%s/%s/%s", pkg.Ecosystem, pkg.GetNormalizedName(), pkg.GetVersion())
+}
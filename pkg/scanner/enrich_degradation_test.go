@@ -0,0 +1,101 @@
+package scanner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/safedep/vet/gen/insightapi"
+	"github.com/safedep/vet/pkg/degradation"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+type degradationTestEnricher struct {
+	err error
+}
+
+func (e *degradationTestEnricher) Name() string {
+	return "test"
+}
+
+func (e *degradationTestEnricher) Enrich(pkg *models.Package, _ PackageDependencyCallbackFn) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	summary := pkg.GetName()
+	pkg.Insights = &insightapi.PackageVersionInsight{
+		Vulnerabilities: &[]insightapi.PackageVulnerability{{Summary: &summary}},
+	}
+
+	return nil
+}
+
+func (e *degradationTestEnricher) Wait() error {
+	return nil
+}
+
+func degradationTestPackage(name string) *models.Package {
+	return &models.Package{
+		PackageDetails: models.NewPackageDetail(models.EcosystemNpm, name, "1.0.0"),
+	}
+}
+
+func TestDegradingEnricher(t *testing.T) {
+	upstream := &degradationTestEnricher{}
+	recorder := degradation.NewRecorder()
+
+	enricher := NewDegradingEnricher(upstream, DegradingEnricherConfig{
+		Matrix:   degradation.DefaultMatrix(),
+		Recorder: recorder,
+		Cache:    storage.NewMemoryKeyValueStorage(),
+	})
+
+	assert.NoError(t, enricher.Enrich(degradationTestPackage("lodash"), nil))
+	assert.Empty(t, recorder.Records())
+
+	upstream.err = errors.New("insights unavailable")
+
+	// Insights are served from cache
+	pkg := degradationTestPackage("lodash")
+	assert.NoError(t, enricher.Enrich(pkg, nil))
+	assert.NotNil(t, pkg.Insights)
+	assert.Len(t, *pkg.Insights.Vulnerabilities, 1)
+	assert.False(t, pkg.VulnerabilitiesUnknown)
+
+	// Vulnerabilities are unknown without cached insights
+	pkg = degradationTestPackage("express")
+	assert.ErrorContains(t, enricher.Enrich(pkg, nil), "insights unavailable")
+	assert.Nil(t, pkg.Insights)
+	assert.True(t, pkg.VulnerabilitiesUnknown)
+
+	assert.Equal(t, []degradation.Record{
+		{Source: degradation.SourceInsights, Mode: degradation.ModeCache, Count: 1, Reason: "insights unavailable"},
+		{Source: degradation.SourceOsv, Mode: degradation.ModeUnknown, Count: 1, Reason: "insights unavailable"},
+	}, recorder.Records())
+}
+
+func TestDegradingEnricherFailMode(t *testing.T) {
+	upstream := &degradationTestEnricher{}
+	recorder := degradation.NewRecorder()
+
+	matrix, err := degradation.ParseMatrix([]string{"insights=fail", "osv=fail"})
+	assert.NoError(t, err)
+
+	enricher := NewDegradingEnricher(upstream, DegradingEnricherConfig{
+		Matrix:   matrix,
+		Recorder: recorder,
+		Cache:    storage.NewMemoryKeyValueStorage(),
+	})
+
+	assert.NoError(t, enricher.Enrich(degradationTestPackage("lodash"), nil))
+
+	upstream.err = errors.New("insights unavailable")
+
+	pkg := degradationTestPackage("lodash")
+	assert.Error(t, enricher.Enrich(pkg, nil))
+	assert.Nil(t, pkg.Insights)
+	assert.False(t, pkg.VulnerabilitiesUnknown)
+	assert.Empty(t, recorder.Records())
+}
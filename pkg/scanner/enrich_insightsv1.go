@@ -12,13 +12,19 @@ import (
 	"github.com/safedep/dry/errors"
 	"github.com/safedep/dry/utils"
 	"github.com/safedep/vet/gen/insightapi"
+	"github.com/safedep/vet/pkg/common/concurrency"
+	"github.com/safedep/vet/pkg/common/httpclient"
 	"github.com/safedep/vet/pkg/common/logger"
 	"github.com/safedep/vet/pkg/models"
+	"github.com/safedep/vet/pkg/naming"
 )
 
 type InsightsBasedPackageMetaEnricherConfig struct {
 	ApiUrl     string
 	ApiAuthKey string
+
+	// Optional HTTP client, defaults to shared client
+	HttpClient *http.Client
 }
 
 type insightsBasedPackageEnricher struct {
@@ -34,12 +40,17 @@ func NewInsightBasedPackageEnricher(config InsightsBasedPackageMetaEnricherConfi
 	}
 
 	timeout := 5 * time.Second
+	if config.HttpClient == nil {
+		config.HttpClient = httpclient.NewClient(timeout)
+	}
+
 	backoff := heimdall.NewConstantBackoff(1*time.Second,
 		3*time.Second)
 
-	retriableClient := hystrix.NewClient(hystrix.WithHTTPTimeout(timeout),
+	retriableClient := hystrix.NewClient(hystrix.WithHTTPClient(config.HttpClient),
+		hystrix.WithHTTPTimeout(timeout),
 		hystrix.WithCommandName("insights-api-client"),
-		hystrix.WithMaxConcurrentRequests(10),
+		hystrix.WithMaxConcurrentRequests(concurrency.For(concurrency.SubsystemInsights).Workers),
 		hystrix.WithRetryCount(3),
 		hystrix.WithRetrier(heimdall.NewRetrier(backoff)))
 
@@ -67,7 +78,7 @@ func (e *insightsBasedPackageEnricher) Enrich(pkg *models.Package,
 
 	res, err := e.client.GetPackageVersionInsightWithResponse(context.Background(),
 		string(pkg.PackageDetails.Ecosystem),
-		pkg.Name, pkg.Version)
+		pkg.GetNormalizedName(), pkg.Version)
 	if err != nil {
 		logger.Errorf("Failed to enrich package: %v", err)
 		return err
@@ -84,7 +95,9 @@ func (e *insightsBasedPackageEnricher) Enrich(pkg *models.Package,
 	}
 
 	for _, dep := range utils.SafelyGetValue(res.JSON200.Dependencies) {
-		if strings.EqualFold(dep.PackageVersion.Name, pkg.PackageDetails.Name) {
+		ecosystem := string(pkg.PackageDetails.Ecosystem)
+		if strings.EqualFold(naming.Normalize(ecosystem, dep.PackageVersion.Name),
+			naming.Normalize(ecosystem, pkg.PackageDetails.Name)) {
 			// Skip self references in dependency
 			continue
 		}
@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"os"
+	"testing"
+
+	"github.com/safedep/dry/utils"
+	"github.com/safedep/vet/pkg/common/fixtures"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// Fixtures are recorded against the Insights API with:
+//
+//	VET_FIXTURES_RECORD=true VET_API_KEY=... go test ./pkg/scanner -run TestInsightsBasedPackageEnricher
+func TestInsightsBasedPackageEnricher(t *testing.T) {
+	enricher, err := NewInsightBasedPackageEnricher(InsightsBasedPackageMetaEnricherConfig{
+		ApiUrl:     "https://api.safedep.io/insights/v1",
+		ApiAuthKey: os.Getenv("VET_API_KEY"),
+		HttpClient: fixtures.NewTestClient(t, "./fixtures/insights_v1_npm.json"),
+	})
+	assert.NoError(t, err)
+
+	manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+
+	t.Run("package is enriched with insights and dependencies", func(t *testing.T) {
+		pkg := &models.Package{
+			Manifest:       manifest,
+			PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "debug", "4.3.4"),
+		}
+
+		dependencies := []*models.Package{}
+		err := enricher.Enrich(pkg, func(dep *models.Package) error {
+			dependencies = append(dependencies, dep)
+			return nil
+		})
+		assert.NoError(t, err)
+
+		if assert.NotNil(t, pkg.Insights) {
+			assert.Equal(t, "4.3.7", utils.SafelyGetValue(pkg.Insights.PackageCurrentVersion))
+			assert.Len(t, utils.SafelyGetValue(pkg.Insights.Projects), 1)
+		}
+
+		// Self reference is skipped
+		if assert.Len(t, dependencies, 1) {
+			assert.Equal(t, "ms", dependencies[0].GetName())
+			assert.Equal(t, "2.1.2", dependencies[0].GetVersion())
+			assert.Equal(t, pkg, dependencies[0].Parent)
+			assert.Equal(t, 1, dependencies[0].Depth)
+		}
+	})
+
+	t.Run("package not found", func(t *testing.T) {
+		pkg := &models.Package{
+			Manifest:       manifest,
+			PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "vet-fixture-missing", "1.0.0"),
+		}
+
+		err := enricher.Enrich(pkg, func(*models.Package) error { return nil })
+		assert.Error(t, err)
+		assert.Nil(t, pkg.Insights)
+	})
+}
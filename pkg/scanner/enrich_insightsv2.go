@@ -50,7 +50,7 @@ func (e *insightsBasedPackageEnricherV2) Enrich(pkg *models.Package,
 			PackageVersion: &packagev1.PackageVersion{
 				Package: &packagev1.Package{
 					Ecosystem: pkg.GetControlTowerSpecEcosystem(),
-					Name:      pkg.GetName(),
+					Name:      pkg.GetNormalizedName(),
 				},
 				Version: pkg.GetVersion(),
 			},
@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"buf.build/gen/go/safedep/api/grpc/go/safedep/services/malysis/v1/malysisv1grpc"
+	malysisv1pb "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/malysis/v1"
+	packagev1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/package/v1"
+	malysisv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/malysis/v1"
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MalysisMalwareQueryEnricherConfig struct {
+	// Timeout of the query for a package
+	Timeout time.Duration
+}
+
+// malysisMalwareQueryEnricher looks up the existing analysis of a package
+// without submitting it for analysis. Enrichment completes within the
+// Enrich call, hence it is suitable for single package lookups.
+type malysisMalwareQueryEnricher struct {
+	client malysisv1grpc.MalwareAnalysisServiceClient
+	config MalysisMalwareQueryEnricherConfig
+}
+
+var _ PackageMetaEnricher = (*malysisMalwareQueryEnricher)(nil)
+
+func NewMalysisMalwareQueryEnricher(cc *grpc.ClientConn,
+	config MalysisMalwareQueryEnricherConfig) (*malysisMalwareQueryEnricher, error) {
+	if cc == nil {
+		return nil, errors.New("grpc client connection is required")
+	}
+
+	return newMalysisMalwareQueryEnricher(malysisv1grpc.NewMalwareAnalysisServiceClient(cc), config), nil
+}
+
+func newMalysisMalwareQueryEnricher(client malysisv1grpc.MalwareAnalysisServiceClient,
+	config MalysisMalwareQueryEnricherConfig) *malysisMalwareQueryEnricher {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultMalysisMalwareEnricherConfig().GrpcOperationTimeout
+	}
+
+	return &malysisMalwareQueryEnricher{client: client, config: config}
+}
+
+func (e *malysisMalwareQueryEnricher) Name() string {
+	return "Malysis Malware Query Enricher"
+}
+
+// Enrich leaves the package without a malware analysis result when
+// the package is not analyzed yet
+func (e *malysisMalwareQueryEnricher) Enrich(pkg *models.Package,
+	_ PackageDependencyCallbackFn) error {
+	ctx, cancel := context.WithTimeout(context.Background(), e.config.Timeout)
+	defer cancel()
+
+	res, err := e.client.QueryPackageAnalysis(ctx, &malysisv1.QueryPackageAnalysisRequest{
+		Target: &malysisv1pb.PackageAnalysisTarget{
+			PackageVersion: &packagev1.PackageVersion{
+				Package: &packagev1.Package{
+					Ecosystem: pkg.GetControlTowerSpecEcosystem(),
+					Name:      pkg.GetName(),
+				},
+				Version: pkg.GetVersion(),
+			},
+		},
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			logger.Debugf("[Malware Analysis] No analysis found for package: %s/%s/%s",
+				pkg.GetControlTowerSpecEcosystem(), pkg.GetName(), pkg.GetVersion())
+			return nil
+		}
+
+		return fmt.Errorf("failed to query malware analysis: %w", err)
+	}
+
+	if res.GetStatus() != malysisv1.AnalysisStatus_ANALYSIS_STATUS_COMPLETED || res.GetReport() == nil {
+		logger.Debugf("[Malware Analysis] Analysis of package %s/%s/%s is not completed: %s",
+			pkg.GetControlTowerSpecEcosystem(), pkg.GetName(), pkg.GetVersion(), res.GetStatus())
+		return nil
+	}
+
+	pkg.SetMalwareAnalysisResult(&models.MalwareAnalysisResult{
+		AnalysisId:         res.GetAnalysisId(),
+		Report:             res.GetReport(),
+		VerificationRecord: res.GetVerificationRecord(),
+	})
+
+	return nil
+}
+
+func (e *malysisMalwareQueryEnricher) Wait() error {
+	return nil
+}
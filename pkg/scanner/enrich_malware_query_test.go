@@ -0,0 +1,86 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"buf.build/gen/go/safedep/api/grpc/go/safedep/services/malysis/v1/malysisv1grpc"
+	malysisv1pb "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/messages/malysis/v1"
+	malysisv1 "buf.build/gen/go/safedep/api/protocolbuffers/go/safedep/services/malysis/v1"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type queryMalwareAnalysisClient struct {
+	malysisv1grpc.MalwareAnalysisServiceClient
+
+	res *malysisv1.QueryPackageAnalysisResponse
+	err error
+}
+
+func (c *queryMalwareAnalysisClient) QueryPackageAnalysis(_ context.Context,
+	_ *malysisv1.QueryPackageAnalysisRequest, _ ...grpc.CallOption) (*malysisv1.QueryPackageAnalysisResponse, error) {
+	return c.res, c.err
+}
+
+func TestMalysisMalwareQueryEnricher(t *testing.T) {
+	report := &malysisv1pb.Report{Inference: &malysisv1pb.Report_Inference{IsMalware: true}}
+
+	cases := []struct {
+		name   string
+		client *queryMalwareAnalysisClient
+		result bool
+		err    bool
+	}{
+		{
+			"Completed analysis",
+			&queryMalwareAnalysisClient{res: &malysisv1.QueryPackageAnalysisResponse{
+				AnalysisId: "a1",
+				Status:     malysisv1.AnalysisStatus_ANALYSIS_STATUS_COMPLETED,
+				Report:     report,
+			}},
+			true, false,
+		},
+		{
+			"Pending analysis",
+			&queryMalwareAnalysisClient{res: &malysisv1.QueryPackageAnalysisResponse{
+				Status: malysisv1.AnalysisStatus_ANALYSIS_STATUS_IN_PROGRESS,
+			}},
+			false, false,
+		},
+		{
+			"Not analyzed",
+			&queryMalwareAnalysisClient{err: status.Error(codes.NotFound, "not found")},
+			false, false,
+		},
+		{
+			"Service failure",
+			&queryMalwareAnalysisClient{err: status.Error(codes.Unavailable, "unavailable")},
+			false, true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			enricher := newMalysisMalwareQueryEnricher(test.client, MalysisMalwareQueryEnricherConfig{})
+			pkg := &models.Package{
+				PackageDetails: models.NewPackageDetail(models.EcosystemNpm, "demo", "1.0.0"),
+				Manifest:       models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm),
+			}
+
+			err := enricher.Enrich(pkg, nil)
+			assert.Equal(t, test.err, err != nil)
+
+			if test.result {
+				if assert.NotNil(t, pkg.GetMalwareAnalysisResult()) {
+					assert.Equal(t, "a1", pkg.GetMalwareAnalysisResult().AnalysisId)
+				}
+			} else {
+				assert.Nil(t, pkg.GetMalwareAnalysisResult())
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/models"
+)
+
+// Built-in stages of the manifest pipeline. Filters are analyzers hence
+// evaluated in the analyze stage.
+const (
+	StageEnrich  = "enrich"
+	StageAnalyze = "analyze"
+	StageReport  = "report"
+)
+
+type PipelineStageFn func(ctx context.Context, manifest *models.PackageManifest) error
+
+// PipelineStage is a node in the DAG of stages executed for each manifest.
+// A stage runs after all stages in DependsOn and before all stages in
+// RequiredBy. Failure of a stage is logged and does not stop the pipeline.
+type PipelineStage struct {
+	Name       string
+	DependsOn  []string
+	RequiredBy []string
+
+	// Optional, the stage context is cancelled after timeout
+	Timeout time.Duration
+
+	Run PipelineStageFn
+}
+
+type StageMetrics struct {
+	Name     string
+	Runs     int
+	Failures int
+	Duration time.Duration
+}
+
+type pipeline struct {
+	// Stages in deterministic execution order
+	stages []*PipelineStage
+
+	m       sync.Mutex
+	metrics map[string]*StageMetrics
+}
+
+// newPipeline orders the stages topologically. Stages without ordering
+// constraints between them execute in the order they are given.
+func newPipeline(stages []PipelineStage) (*pipeline, error) {
+	index := map[string]int{}
+	for i, stage := range stages {
+		if stage.Name == "" || stage.Run == nil {
+			return nil, fmt.Errorf("pipeline stage at %d must have name and run function", i)
+		}
+
+		if _, ok := index[stage.Name]; ok {
+			return nil, fmt.Errorf("duplicate pipeline stage: %s", stage.Name)
+		}
+
+		index[stage.Name] = i
+	}
+
+	edges := make([][]int, len(stages))
+	inDegree := make([]int, len(stages))
+	addEdge := func(from, to string) error {
+		f, ok := index[from]
+		if !ok {
+			return fmt.Errorf("unknown pipeline stage: %s", from)
+		}
+
+		t, ok := index[to]
+		if !ok {
+			return fmt.Errorf("unknown pipeline stage: %s", to)
+		}
+
+		edges[f] = append(edges[f], t)
+		inDegree[t] += 1
+
+		return nil
+	}
+
+	for _, stage := range stages {
+		for _, dep := range stage.DependsOn {
+			if err := addEdge(dep, stage.Name); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, dep := range stage.RequiredBy {
+			if err := addEdge(stage.Name, dep); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	p := &pipeline{metrics: map[string]*StageMetrics{}}
+	done := make([]bool, len(stages))
+	for len(p.stages) < len(stages) {
+		next := -1
+		for i := range stages {
+			if !done[i] && inDegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+
+		if next < 0 {
+			return nil, fmt.Errorf("pipeline stages have a cycle")
+		}
+
+		done[next] = true
+		for _, t := range edges[next] {
+			inDegree[t] -= 1
+		}
+
+		stage := stages[next]
+		p.stages = append(p.stages, &stage)
+		p.metrics[stage.Name] = &StageMetrics{Name: stage.Name}
+	}
+
+	return p, nil
+}
+
+// run executes the stages for a manifest till completion or
+// cancellation of the context
+func (p *pipeline) run(ctx context.Context, manifest *models.PackageManifest) error {
+	for _, stage := range p.stages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var stageCtx context.Context
+		var cancel context.CancelFunc
+		if stage.Timeout > 0 {
+			stageCtx, cancel = context.WithTimeout(ctx, stage.Timeout)
+		} else {
+			stageCtx, cancel = context.WithCancel(ctx)
+		}
+
+		start := time.Now()
+		err := stage.Run(stageCtx, manifest)
+		cancel()
+
+		p.record(stage.Name, time.Since(start), err)
+		if err != nil {
+			logger.Errorf("Pipeline stage %s failed for %s manifest %s : %v",
+				stage.Name, manifest.Ecosystem, manifest.GetPath(), err)
+		}
+	}
+
+	return nil
+}
+
+func (p *pipeline) record(name string, duration time.Duration, err error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	metrics := p.metrics[name]
+	metrics.Runs += 1
+	metrics.Duration += duration
+
+	if err != nil {
+		metrics.Failures += 1
+	}
+}
+
+// stageMetrics returns a snapshot of metrics in execution order
+func (p *pipeline) stageMetrics() []StageMetrics {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	metrics := make([]StageMetrics, 0, len(p.stages))
+	for _, stage := range p.stages {
+		metrics = append(metrics, *p.metrics[stage.Name])
+	}
+
+	return metrics
+}
@@ -0,0 +1,167 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineOrder(t *testing.T) {
+	stage := func(name string, dependsOn, requiredBy []string) PipelineStage {
+		return PipelineStage{
+			Name:       name,
+			DependsOn:  dependsOn,
+			RequiredBy: requiredBy,
+			Run: func(_ context.Context, _ *models.PackageManifest) error {
+				return nil
+			},
+		}
+	}
+
+	builtin := []PipelineStage{
+		stage(StageEnrich, nil, nil),
+		stage(StageAnalyze, []string{StageEnrich}, nil),
+		stage(StageReport, []string{StageAnalyze}, nil),
+	}
+
+	cases := []struct {
+		name   string
+		extra  []PipelineStage
+		order  []string
+		errMsg string
+	}{
+		{
+			"Built-in stages",
+			nil,
+			[]string{StageEnrich, StageAnalyze, StageReport},
+			"",
+		},
+		{
+			"Stage inserted between enrich and analyze",
+			[]PipelineStage{stage("artifact-scan", []string{StageEnrich}, []string{StageAnalyze})},
+			[]string{StageEnrich, "artifact-scan", StageAnalyze, StageReport},
+			"",
+		},
+		{
+			"Stage without constraints retains insertion order",
+			[]PipelineStage{stage("reachability", nil, nil)},
+			[]string{StageEnrich, StageAnalyze, StageReport, "reachability"},
+			"",
+		},
+		{
+			"Unknown dependency",
+			[]PipelineStage{stage("artifact-scan", []string{"download"}, nil)},
+			nil,
+			"unknown pipeline stage: download",
+		},
+		{
+			"Cycle",
+			[]PipelineStage{stage("artifact-scan", []string{StageReport}, []string{StageEnrich})},
+			nil,
+			"pipeline stages have a cycle",
+		},
+		{
+			"Duplicate stage",
+			[]PipelineStage{stage(StageEnrich, nil, nil)},
+			nil,
+			"duplicate pipeline stage: enrich",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := newPipeline(append(append([]PipelineStage{}, builtin...), test.extra...))
+			if test.errMsg != "" {
+				assert.ErrorContains(t, err, test.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var order []string
+			for _, m := range p.stageMetrics() {
+				order = append(order, m.Name)
+			}
+
+			assert.Equal(t, test.order, order)
+		})
+	}
+}
+
+func TestPipelineRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var executed []string
+	p, err := newPipeline([]PipelineStage{
+		{
+			Name: "failing",
+			Run: func(_ context.Context, _ *models.PackageManifest) error {
+				executed = append(executed, "failing")
+				return errors.New("failed")
+			},
+		},
+		{
+			Name:      "cancelling",
+			DependsOn: []string{"failing"},
+			Run: func(_ context.Context, _ *models.PackageManifest) error {
+				executed = append(executed, "cancelling")
+				cancel()
+				return nil
+			},
+		},
+		{
+			Name:      "skipped",
+			DependsOn: []string{"cancelling"},
+			Run: func(_ context.Context, _ *models.PackageManifest) error {
+				executed = append(executed, "skipped")
+				return nil
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	err = p.run(ctx, models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Failure of a stage does not stop the pipeline but cancellation does
+	assert.Equal(t, []string{"failing", "cancelling"}, executed)
+
+	metrics := p.stageMetrics()
+	assert.Equal(t, StageMetrics{Name: "failing", Runs: 1, Failures: 1, Duration: metrics[0].Duration}, metrics[0])
+	assert.Equal(t, 1, metrics[1].Runs)
+	assert.Equal(t, 0, metrics[2].Runs)
+}
+
+type countingAnalyzer struct {
+	runs int
+}
+
+func (a *countingAnalyzer) Name() string  { return "counting" }
+func (a *countingAnalyzer) Finish() error { return nil }
+
+func (a *countingAnalyzer) Analyze(_ *models.PackageManifest, _ analyzer.AnalyzerEventHandler) error {
+	a.runs += 1
+	return nil
+}
+
+func TestScannerStagesCancelled(t *testing.T) {
+	counter := &countingAnalyzer{}
+	s := NewPackageManifestScanner(Config{}, nil, nil, []analyzer.Analyzer{counter}, nil)
+	manifest := models.NewPackageManifestFromLocal("package-lock.json", models.EcosystemNpm)
+
+	require.NoError(t, s.analyzeManifest(context.Background(), manifest))
+	assert.Equal(t, 1, counter.runs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, s.analyzeManifest(ctx, manifest), context.Canceled)
+	assert.ErrorIs(t, s.reportManifest(ctx, manifest), context.Canceled)
+	assert.Equal(t, 1, counter.runs)
+}
@@ -2,19 +2,25 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	dryutils "github.com/safedep/dry/utils"
 	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/common/concurrency"
+	"github.com/safedep/vet/pkg/common/errcode"
 	"github.com/safedep/vet/pkg/common/logger"
 	"github.com/safedep/vet/pkg/common/utils"
+	"github.com/safedep/vet/pkg/eventbus"
 	"github.com/safedep/vet/pkg/models"
 	"github.com/safedep/vet/pkg/readers"
 	"github.com/safedep/vet/pkg/reporter"
 )
 
 type Config struct {
-	ExcludePatterns    []string
+	ExcludePatterns []string
+
+	// Defaults to concurrency config when zero
 	ConcurrentAnalyzer int
 	TransitiveAnalysis bool
 	TransitiveDepth    int
@@ -26,10 +32,21 @@ type packageManifestScanner struct {
 	readers   []readers.PackageManifestReader
 	enrichers []PackageMetaEnricher
 	analyzers []analyzer.Analyzer
-	reporters []reporter.Reporter
+
+	// All reporters are driven through a single multi reporter
+	// so that they run concurrently over the same scan data
+	reporter reporter.Reporter
+
+	// Additional stages inserted in the manifest pipeline
+	stages   []PipelineStage
+	pipeline *pipeline
 
 	callbacks   ScannerCallbacks
+	events      *eventbus.Bus
 	failOnError error
+
+	// Failures of individual reporters, these do not fail the scan
+	reporterErrors []*reporter.ReporterError
 }
 
 func NewPackageManifestScanner(config Config,
@@ -43,11 +60,61 @@ func NewPackageManifestScanner(config Config,
 		readers:   readers,
 		enrichers: enrichers,
 		analyzers: analyzers,
-		reporters: reporters,
+		reporter:  reporter.NewMultiReporter(reporters...),
+	}
+}
+
+// AddStage inserts a stage in the manifest pipeline e.g. a stage that
+// depends on StageEnrich and is required by StageAnalyze
+func (s *packageManifestScanner) AddStage(stage PipelineStage) {
+	s.stages = append(s.stages, stage)
+}
+
+// StageMetrics returns the metrics of pipeline stages. Available
+// only after the scan is started.
+func (s *packageManifestScanner) StageMetrics() []StageMetrics {
+	if s.pipeline == nil {
+		return nil
 	}
+
+	return s.pipeline.stageMetrics()
 }
 
 func (s *packageManifestScanner) Start() error {
+	return s.StartWithContext(context.Background())
+}
+
+// StartWithContext starts the scan. On cancellation of the context or
+// failure of a reader, no new manifest is scanned, analyzers and reporters
+// are finished with the data collected so far and the error is returned.
+func (s *packageManifestScanner) StartWithContext(ctx context.Context) error {
+	p, err := newPipeline(append([]PipelineStage{
+		{
+			Name: StageEnrich,
+			Run: func(ctx context.Context, manifest *models.PackageManifest) error {
+				return s.enrichManifest(ctx, manifest)
+			},
+		},
+		{
+			Name:      StageAnalyze,
+			DependsOn: []string{StageEnrich},
+			Run: func(ctx context.Context, manifest *models.PackageManifest) error {
+				return s.analyzeManifest(ctx, manifest)
+			},
+		},
+		{
+			Name:      StageReport,
+			DependsOn: []string{StageAnalyze},
+			Run: func(ctx context.Context, manifest *models.PackageManifest) error {
+				return s.reportManifest(ctx, manifest)
+			},
+		},
+	}, s.stages...))
+	if err != nil {
+		return fmt.Errorf("failed to build scan pipeline: %w", err)
+	}
+
+	s.pipeline = p
 	s.dispatchOnStart()
 
 	// The manifest processing go routine will close the doneChannel
@@ -56,24 +123,31 @@ func (s *packageManifestScanner) Start() error {
 	// We will close the scanner channel
 	scannerChannel := make(chan *models.PackageManifest, 100)
 
-	ctx := context.Background()
-	defer ctx.Done()
-
 	go s.startManifestScanner(ctx, scannerChannel, doneChannel)
 
 	s.dispatchStartManifestEnumeration()
 
+	// Manifests enumerated before failure of a reader are still reported
+	var readerErr error
 	for _, reader := range s.readers {
 		err := reader.EnumManifests(func(manifest *models.PackageManifest,
 			_ readers.PackageReader,
 		) error {
 			s.dispatchOnManifestEnumeration(manifest)
-			scannerChannel <- manifest
 
-			return nil
+			select {
+			case scannerChannel <- manifest:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
 		})
 		if err != nil {
-			return err
+			if ctx.Err() == nil {
+				readerErr = err
+			}
+
+			break
 		}
 	}
 
@@ -86,6 +160,14 @@ func (s *packageManifestScanner) Start() error {
 
 	s.dispatchBeforeFinish()
 
+	if readerErr != nil {
+		s.failWith(readerErr)
+	}
+
+	if s.error() == nil && ctx.Err() != nil {
+		s.failWith(fmt.Errorf("scan cancelled: %w", ctx.Err()))
+	}
+
 	// Signal analyzers and reporters to finish anything pending
 	s.finishAnalyzers()
 	s.finishReporting()
@@ -114,31 +196,23 @@ func (s *packageManifestScanner) startManifestScanner(ctx context.Context,
 			break
 		}
 
-		manifest, ok := <-incoming
+		var manifest *models.PackageManifest
+		var ok bool
+		select {
+		case manifest, ok = <-incoming:
+		case <-ctx.Done():
+		}
+
 		if !ok {
 			break
 		}
 
 		s.dispatchOnStartManifest(manifest)
 
-		// Enrich each package in a manifest with metadata
-		err := s.enrichManifest(manifest)
-		if err != nil {
-			logger.Errorf("Failed to enrich %s manifest %s : %v",
-				manifest.Ecosystem, manifest.GetPath(), err)
-		}
-
-		// Invoke analyzers to analyse the manifest
-		err = s.analyzeManifest(manifest)
-		if err != nil {
-			logger.Errorf("Failed to analyze %s manifest %s : %v",
-				manifest.Ecosystem, manifest.GetPath(), err)
-		}
-
-		// Invoke activated reporting modules to report on the manifest
-		err = s.reportManifest(manifest)
+		// Enrich, analyze and report the manifest
+		err := s.pipeline.run(ctx, manifest)
 		if err != nil {
-			logger.Errorf("Failed to report %s manifest %s : %v",
+			logger.Warnf("Pipeline cancelled for %s manifest %s : %v",
 				manifest.Ecosystem, manifest.GetPath(), err)
 		}
 
@@ -146,12 +220,22 @@ func (s *packageManifestScanner) startManifestScanner(ctx context.Context,
 	}
 }
 
-func (s *packageManifestScanner) analyzeManifest(manifest *models.PackageManifest) error {
+// Analyzers do not accept a context, hence cancellation is checked
+// before running each analyzer
+func (s *packageManifestScanner) analyzeManifest(ctx context.Context, manifest *models.PackageManifest) error {
 	for _, task := range s.analyzers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		err := task.Analyze(manifest, func(event *analyzer.AnalyzerEvent) error {
-			for _, r := range s.reporters {
-				r.AddAnalyzerEvent(event)
-			}
+			s.reporter.AddAnalyzerEvent(event)
+			s.events.Publish(eventbus.Event{
+				Topic:    eventbus.TopicFindingEmitted,
+				Manifest: manifest,
+				Package:  event.Package,
+				Finding:  event,
+			})
 
 			return s.internalHandleAnalyzerEvent(event)
 		})
@@ -165,7 +249,7 @@ func (s *packageManifestScanner) analyzeManifest(manifest *models.PackageManifes
 
 func (s *packageManifestScanner) internalHandleAnalyzerEvent(event *analyzer.AnalyzerEvent) error {
 	if event.IsFailOnError() {
-		s.failWith(fmt.Errorf("%s analyzer raised an event to fail with: %w",
+		s.failWith(errcode.Errorf(errcode.PolicyFailed, "%s analyzer raised an event to fail with: %w",
 			event.Source, event.Err))
 	}
 
@@ -184,20 +268,42 @@ func (s *packageManifestScanner) error() error {
 	return s.failOnError
 }
 
-func (s *packageManifestScanner) reportManifest(manifest *models.PackageManifest) error {
-	for _, r := range s.reporters {
-		r.AddManifest(manifest)
+func (s *packageManifestScanner) reportManifest(ctx context.Context, manifest *models.PackageManifest) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
+	s.reporter.AddManifest(manifest)
+
 	return nil
 }
 
+// ReporterErrors returns the reporters that failed to finish. Available
+// only after the scan is finished.
+func (s *packageManifestScanner) ReporterErrors() []*reporter.ReporterError {
+	return s.reporterErrors
+}
+
 func (s *packageManifestScanner) finishReporting() {
-	for _, r := range s.reporters {
-		err := r.Finish()
-		if err != nil {
-			logger.Errorf("Reporter: %s failed with %v", r.Name(), err)
-		}
+	// Reporters e.g. cloud sync must not report a failed scan as success
+	if sr, ok := s.reporter.(reporter.ScanErrorReporter); ok {
+		sr.SetScanError(s.error())
+	}
+
+	err := s.reporter.Finish()
+	if err == nil {
+		return
+	}
+
+	var reportingErr *reporter.ReportingError
+	if errors.As(err, &reportingErr) {
+		s.reporterErrors = reportingErr.Errors
+	} else {
+		s.reporterErrors = []*reporter.ReporterError{{Reporter: s.reporter.Name(), Err: err}}
+	}
+
+	for _, re := range s.reporterErrors {
+		logger.Errorf("Reporter: %s failed with %v", re.Reporter, re.Err)
 	}
 }
 
@@ -210,7 +316,9 @@ func (s *packageManifestScanner) finishAnalyzers() {
 	}
 }
 
-func (s *packageManifestScanner) enrichManifest(manifest *models.PackageManifest) error {
+// enrichManifest stops queueing packages on cancellation of the context.
+// Packages already queued are drained without enrichment.
+func (s *packageManifestScanner) enrichManifest(ctx context.Context, manifest *models.PackageManifest) error {
 	if len(s.enrichers) == 0 {
 		return nil
 	}
@@ -221,9 +329,14 @@ func (s *packageManifestScanner) enrichManifest(manifest *models.PackageManifest
 	// because the goroutines perform both read and write to channel. Write occurs
 	// when goroutine invokes the work queue handler and the handler pushes back
 	// the dependencies
-	q := utils.NewWorkQueue(100000,
-		s.config.ConcurrentAnalyzer,
-		s.packageEnrichWorkQueueHandler(manifest))
+	limits := concurrency.For(concurrency.SubsystemScanner)
+	if s.config.ConcurrentAnalyzer > 0 {
+		limits.Workers = s.config.ConcurrentAnalyzer
+	}
+
+	q := utils.NewWorkQueue(limits.QueueSize,
+		limits.Workers,
+		s.packageEnrichWorkQueueHandler(ctx, manifest))
 
 	q.WithCallbacks(utils.WorkQueueCallbacks[*models.Package]{
 		OnAdd: func(q *utils.WorkQueue[*models.Package], item *models.Package) {
@@ -237,6 +350,10 @@ func (s *packageManifestScanner) enrichManifest(manifest *models.PackageManifest
 	q.Start()
 
 	readers.NewManifestModelReader(manifest).EnumPackages(func(pkg *models.Package) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		q.Add(pkg)
 		return nil
 	})
@@ -250,7 +367,7 @@ func (s *packageManifestScanner) enrichManifest(manifest *models.PackageManifest
 		return fmt.Errorf("package enricher wait failed: %w", err)
 	}
 
-	return nil
+	return ctx.Err()
 }
 
 func (s *packageManifestScanner) packageEnricherWait() error {
@@ -266,8 +383,13 @@ func (s *packageManifestScanner) packageEnricherWait() error {
 	return nil
 }
 
-func (s *packageManifestScanner) packageEnrichWorkQueueHandler(pm *models.PackageManifest) utils.WorkQueueFn[*models.Package] {
+func (s *packageManifestScanner) packageEnrichWorkQueueHandler(ctx context.Context,
+	pm *models.PackageManifest) utils.WorkQueueFn[*models.Package] {
 	return func(q *utils.WorkQueue[*models.Package], item *models.Package) error {
+		if ctx.Err() != nil {
+			return nil
+		}
+
 		for _, enricher := range s.enrichers {
 			err := enricher.Enrich(item, s.packageDependencyHandler(pm, item, q))
 			if err != nil {
@@ -275,6 +397,12 @@ func (s *packageManifestScanner) packageEnrichWorkQueueHandler(pm *models.Packag
 			}
 		}
 
+		s.events.Publish(eventbus.Event{
+			Topic:    eventbus.TopicPackageEnriched,
+			Manifest: pm,
+			Package:  item,
+		})
+
 		return nil
 	}
 }
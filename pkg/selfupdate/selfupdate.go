@@ -0,0 +1,440 @@
+// Package selfupdate updates the running vet binary from release
+// artifacts published on GitHub. Release checksums are signed and the
+// signature is verified before an artifact is trusted.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/safedep/dry/semver"
+	"github.com/safedep/vet/pkg/common/httpclient"
+	"github.com/safedep/vet/pkg/common/logger"
+)
+
+type Channel string
+
+const (
+	// Releases that are not marked as pre-release
+	ChannelStable = Channel("stable")
+
+	// Includes pre-releases
+	ChannelBeta = Channel("beta")
+)
+
+const (
+	defaultReleasesUrl = "https://api.github.com/repos/safedep/vet/releases"
+
+	checksumsAssetName          = "checksums.txt"
+	checksumsSignatureAssetName = "checksums.txt.sig"
+
+	// Limits the size of downloaded release artifacts
+	maxArtifactSize = 256 << 20
+)
+
+var (
+	ErrInvalidChannel    = errors.New("invalid release channel")
+	ErrNoRelease         = errors.New("no release available in channel")
+	ErrSignatureMismatch = errors.New("release signature verification failed")
+	ErrChecksumMismatch  = errors.New("release artifact checksum mismatch")
+)
+
+type Asset struct {
+	Name        string `json:"name"`
+	DownloadUrl string `json:"browser_download_url"`
+}
+
+type Release struct {
+	Version    string  `json:"tag_name"`
+	Prerelease bool    `json:"prerelease"`
+	Draft      bool    `json:"draft"`
+	Assets     []Asset `json:"assets"`
+}
+
+func (r *Release) asset(name string) (Asset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+
+	return Asset{}, false
+}
+
+type Config struct {
+	Channel Channel
+
+	// Version of the running binary
+	CurrentVersion string
+
+	// Verifies the signature of release checksums
+	PublicKey ed25519.PublicKey
+
+	// Optional, defaults to vet releases on GitHub
+	ReleasesUrl string
+
+	// Optional, defaults to the shared HTTP client
+	HttpClient *http.Client
+}
+
+type Updater struct {
+	config Config
+}
+
+func NewUpdater(config Config) (*Updater, error) {
+	if config.Channel == "" {
+		config.Channel = ChannelStable
+	}
+
+	if config.Channel != ChannelStable && config.Channel != ChannelBeta {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidChannel, config.Channel)
+	}
+
+	if len(config.PublicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("release signing key is not available in this build")
+	}
+
+	if config.ReleasesUrl == "" {
+		config.ReleasesUrl = defaultReleasesUrl
+	}
+
+	if config.HttpClient == nil {
+		config.HttpClient = httpclient.Default()
+	}
+
+	return &Updater{config: config}, nil
+}
+
+// ParsePublicKey parses a base64 encoded ed25519 public key, either raw
+// or PKIX (DER) encoded
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key: %w", err)
+	}
+
+	if len(der) == ed25519.PublicKeySize {
+		return ed25519.PublicKey(der), nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not ed25519")
+	}
+
+	return publicKey, nil
+}
+
+// Latest returns the highest version released in the channel
+func (u *Updater) Latest(ctx context.Context) (*Release, error) {
+	body, err := u.get(ctx, u.config.ReleasesUrl+"?per_page=50")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	var releases []Release
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+
+	var latest *Release
+	for i := range releases {
+		release := &releases[i]
+		if release.Draft || !semver.IsSemver(release.Version) {
+			continue
+		}
+
+		if release.Prerelease && u.config.Channel != ChannelBeta {
+			continue
+		}
+
+		if latest == nil || semver.IsAhead(latest.Version, release.Version) {
+			latest = release
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoRelease, u.config.Channel)
+	}
+
+	return latest, nil
+}
+
+// UpdateAvailable is false for builds without a version e.g. development
+// builds, which are never replaced
+func (u *Updater) UpdateAvailable(release *Release) bool {
+	return semver.IsAhead(u.config.CurrentVersion, release.Version)
+}
+
+// Download returns the verified vet binary of the release for the
+// running platform
+func (u *Updater) Download(ctx context.Context, release *Release) ([]byte, error) {
+	checksums, err := u.verifiedChecksums(ctx, release)
+	if err != nil {
+		return nil, err
+	}
+
+	archiveName := ArchiveName(runtime.GOOS, runtime.GOARCH)
+	asset, ok := release.asset(archiveName)
+	if !ok {
+		return nil, fmt.Errorf("release %s does not have an artifact for %s/%s",
+			release.Version, runtime.GOOS, runtime.GOARCH)
+	}
+
+	expected, ok := checksums[archiveName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not in %s", ErrChecksumMismatch, archiveName, checksumsAssetName)
+	}
+
+	logger.Debugf("Downloading release artifact: %s", asset.DownloadUrl)
+
+	archive, err := u.get(ctx, asset.DownloadUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", archiveName, err)
+	}
+
+	digest := sha256.Sum256(archive)
+	if hex.EncodeToString(digest[:]) != expected {
+		return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, archiveName)
+	}
+
+	return extractBinary(archiveName, archive)
+}
+
+// Apply replaces the executable with the binary of the release
+func (u *Updater) Apply(ctx context.Context, release *Release, executable string) error {
+	binary, err := u.Download(ctx, release)
+	if err != nil {
+		return err
+	}
+
+	return replaceExecutable(executable, binary)
+}
+
+func (u *Updater) verifiedChecksums(ctx context.Context, release *Release) (map[string]string, error) {
+	checksumsAsset, ok := release.asset(checksumsAssetName)
+	if !ok {
+		return nil, fmt.Errorf("release %s does not have %s", release.Version, checksumsAssetName)
+	}
+
+	signatureAsset, ok := release.asset(checksumsSignatureAssetName)
+	if !ok {
+		return nil, fmt.Errorf("%w: release %s is not signed", ErrSignatureMismatch, release.Version)
+	}
+
+	checksums, err := u.get(ctx, checksumsAsset.DownloadUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", checksumsAssetName, err)
+	}
+
+	signature, err := u.get(ctx, signatureAsset.DownloadUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", checksumsSignatureAssetName, err)
+	}
+
+	// Signature may be published raw or base64 encoded
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature))); err == nil {
+		signature = decoded
+	}
+
+	if !ed25519.Verify(u.config.PublicKey, checksums, signature) {
+		return nil, fmt.Errorf("%w: %s", ErrSignatureMismatch, release.Version)
+	}
+
+	return parseChecksums(checksums), nil
+}
+
+func (u *Updater) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := u.config.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxArtifactSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) > maxArtifactSize {
+		return nil, fmt.Errorf("artifact is larger than %d bytes", maxArtifactSize)
+	}
+
+	return body, nil
+}
+
+// ArchiveName is the name of the release archive for a platform as
+// published by the release pipeline
+func ArchiveName(goos, goarch string) string {
+	arch := goarch
+	switch {
+	case goos == "darwin":
+		// macOS releases are universal binaries
+		arch = "all"
+	case goarch == "amd64":
+		arch = "x86_64"
+	case goarch == "386":
+		arch = "i386"
+	}
+
+	extension := "tar.gz"
+	if goos == "windows" {
+		extension = "zip"
+	}
+
+	return fmt.Sprintf("vet_%s_%s.%s", strings.ToUpper(goos[:1])+goos[1:], arch, extension)
+}
+
+// parseChecksums parses sha256sum formatted lines into a map of name to
+// hex encoded digest
+func parseChecksums(data []byte) map[string]string {
+	checksums := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+
+	return checksums
+}
+
+func extractBinary(archiveName string, archive []byte) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		return extractZipBinary(archive, "vet.exe")
+	}
+
+	return extractTarGzBinary(archive, "vet")
+}
+
+func extractTarGzBinary(archive []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == name {
+			return io.ReadAll(io.LimitReader(reader, maxArtifactSize))
+		}
+	}
+
+	return nil, fmt.Errorf("archive does not contain %s", name)
+}
+
+func extractZipBinary(archive []byte, name string) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	for _, file := range reader.File {
+		if file.Mode().IsRegular() && path.Base(file.Name) == name {
+			rc, err := file.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read archive: %w", err)
+			}
+
+			defer rc.Close()
+			return io.ReadAll(io.LimitReader(rc, maxArtifactSize))
+		}
+	}
+
+	return nil, fmt.Errorf("archive does not contain %s", name)
+}
+
+// replaceExecutable writes the binary next to the executable and renames
+// it over the executable so that a failed update does not leave a partial
+// binary behind
+func replaceExecutable(executable string, binary []byte) error {
+	executable, err := filepath.EvalSymlinks(executable)
+	if err != nil {
+		return fmt.Errorf("failed to resolve executable: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(executable), ".vet-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to create update file: %w", err)
+	}
+
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(binary)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write update file: %w", err)
+	}
+
+	if err := os.Chmod(file.Name(), 0755); err != nil {
+		return fmt.Errorf("failed to make update executable: %w", err)
+	}
+
+	// Running executable can not be replaced on Windows but can be renamed
+	if runtime.GOOS == "windows" {
+		old := executable + ".old"
+		_ = os.Remove(old)
+
+		if err := os.Rename(executable, old); err != nil {
+			return fmt.Errorf("failed to move executable: %w", err)
+		}
+	}
+
+	if err := os.Rename(file.Name(), executable); err != nil {
+		return fmt.Errorf("failed to replace executable: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testReleaseServer struct {
+	server    *httptest.Server
+	files     map[string][]byte
+	releases  []Release
+	publicKey ed25519.PublicKey
+}
+
+func newTestReleaseServer(t *testing.T, binary []byte) *testReleaseServer {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "vet", Mode: 0755, Size: int64(len(binary)), Typeflag: tar.TypeReg}))
+	_, err = tw.Write(binary)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	archiveName := ArchiveName(runtime.GOOS, "amd64")
+	digest := sha256.Sum256(archive.Bytes())
+	checksums := []byte(fmt.Sprintf("%s  %s\n", hex.EncodeToString(digest[:]), archiveName))
+
+	s := &testReleaseServer{
+		publicKey: publicKey,
+		files: map[string][]byte{
+			archiveName:                 archive.Bytes(),
+			checksumsAssetName:          checksums,
+			checksumsSignatureAssetName: ed25519.Sign(privateKey, checksums),
+		},
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/releases" {
+			_ = json.NewEncoder(w).Encode(s.releases)
+			return
+		}
+
+		data, ok := s.files[filepath.Base(r.URL.Path)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write(data)
+	}))
+
+	t.Cleanup(s.server.Close)
+
+	assets := []Asset{}
+	for name := range s.files {
+		assets = append(assets, Asset{Name: name, DownloadUrl: s.server.URL + "/download/" + name})
+	}
+
+	s.releases = []Release{
+		{Version: "v1.9.0", Assets: assets},
+		{Version: "v1.10.0-rc.1", Prerelease: true, Assets: assets},
+		{Version: "v2.0.0", Draft: true},
+		{Version: "v1.8.2", Assets: assets},
+	}
+
+	return s
+}
+
+func (s *testReleaseServer) updater(t *testing.T, channel Channel) *Updater {
+	updater, err := NewUpdater(Config{
+		Channel:        channel,
+		CurrentVersion: "1.8.2",
+		PublicKey:      s.publicKey,
+		ReleasesUrl:    s.server.URL + "/releases",
+		HttpClient:     s.server.Client(),
+	})
+
+	require.NoError(t, err)
+	return updater
+}
+
+func TestUpdaterLatest(t *testing.T) {
+	server := newTestReleaseServer(t, []byte("vet"))
+
+	cases := []struct {
+		channel  Channel
+		expected string
+	}{
+		{ChannelStable, "v1.9.0"},
+		{ChannelBeta, "v1.10.0-rc.1"},
+	}
+
+	for _, test := range cases {
+		t.Run(string(test.channel), func(t *testing.T) {
+			updater := server.updater(t, test.channel)
+
+			release, err := updater.Latest(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, release.Version)
+			assert.True(t, updater.UpdateAvailable(release))
+		})
+	}
+
+	_, err := NewUpdater(Config{Channel: "nightly", PublicKey: server.publicKey})
+	assert.ErrorIs(t, err, ErrInvalidChannel)
+
+	_, err = NewUpdater(Config{Channel: ChannelStable})
+	assert.Error(t, err)
+}
+
+func TestUpdaterDownload(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("test release is published for linux")
+	}
+
+	t.Run("verified artifact", func(t *testing.T) {
+		server := newTestReleaseServer(t, []byte("new vet binary"))
+		updater := server.updater(t, ChannelStable)
+
+		release, err := updater.Latest(context.Background())
+		require.NoError(t, err)
+
+		if runtime.GOARCH != "amd64" {
+			t.Skip("test release is published for amd64")
+		}
+
+		executable := filepath.Join(t.TempDir(), "vet")
+		require.NoError(t, os.WriteFile(executable, []byte("old vet binary"), 0755))
+
+		err = updater.Apply(context.Background(), release, executable)
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(executable)
+		assert.NoError(t, err)
+		assert.Equal(t, "new vet binary", string(data))
+	})
+
+	t.Run("tampered checksums", func(t *testing.T) {
+		server := newTestReleaseServer(t, []byte("new vet binary"))
+		server.files[checksumsAssetName] = append(server.files[checksumsAssetName], []byte("00  other\n")...)
+
+		_, err := server.updater(t, ChannelStable).Download(context.Background(), &server.releases[0])
+		assert.ErrorIs(t, err, ErrSignatureMismatch)
+	})
+
+	t.Run("tampered artifact", func(t *testing.T) {
+		if runtime.GOARCH != "amd64" {
+			t.Skip("test release is published for amd64")
+		}
+
+		server := newTestReleaseServer(t, []byte("new vet binary"))
+		server.files[ArchiveName(runtime.GOOS, "amd64")] = []byte("malicious")
+
+		_, err := server.updater(t, ChannelStable).Download(context.Background(), &server.releases[0])
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+	})
+}
+
+func TestArchiveName(t *testing.T) {
+	assert.Equal(t, "vet_Linux_x86_64.tar.gz", ArchiveName("linux", "amd64"))
+	assert.Equal(t, "vet_Linux_arm64.tar.gz", ArchiveName("linux", "arm64"))
+	assert.Equal(t, "vet_Darwin_all.tar.gz", ArchiveName("darwin", "arm64"))
+	assert.Equal(t, "vet_Windows_x86_64.zip", ArchiveName("windows", "amd64"))
+}
+
+func TestParsePublicKey(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	parsed, err := ParsePublicKey(base64.StdEncoding.EncodeToString(publicKey))
+	assert.NoError(t, err)
+	assert.Equal(t, publicKey, parsed)
+
+	_, err = ParsePublicKey("not base64!")
+	assert.Error(t, err)
+}
+
+func TestCheckDataStaleness(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	staleness, err := CheckDataStaleness("2025-05-01T00:00:00Z", DefaultDataMaxAge, now)
+	assert.NoError(t, err)
+	assert.False(t, staleness.Stale)
+	assert.Equal(t, 31*24*time.Hour, staleness.Age)
+
+	staleness, err = CheckDataStaleness("2024-12-01T00:00:00Z", DefaultDataMaxAge, now)
+	assert.NoError(t, err)
+	assert.True(t, staleness.Stale)
+
+	_, err = CheckDataStaleness("", DefaultDataMaxAge, now)
+	assert.Error(t, err)
+}
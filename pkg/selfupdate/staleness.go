@@ -0,0 +1,40 @@
+package selfupdate
+
+import (
+	"fmt"
+	"time"
+)
+
+// Advisory and license data embedded in vet is as of its build, hence
+// data of an old build misses recent advisories and licenses
+const DefaultDataMaxAge = 90 * 24 * time.Hour
+
+type DataStaleness struct {
+	BuildDate time.Time
+	Age       time.Duration
+	Stale     bool
+}
+
+// CheckDataStaleness reports whether the embedded data of a build is older
+// than the max age. Build date is RFC3339 as set by the release pipeline.
+func CheckDataStaleness(buildDate string, maxAge time.Duration, now time.Time) (DataStaleness, error) {
+	if buildDate == "" {
+		return DataStaleness{}, fmt.Errorf("build date is not available in this build")
+	}
+
+	date, err := time.Parse(time.RFC3339, buildDate)
+	if err != nil {
+		return DataStaleness{}, fmt.Errorf("failed to parse build date: %w", err)
+	}
+
+	if maxAge <= 0 {
+		maxAge = DefaultDataMaxAge
+	}
+
+	age := now.Sub(date)
+	return DataStaleness{
+		BuildDate: date,
+		Age:       age,
+		Stale:     age > maxAge,
+	}, nil
+}
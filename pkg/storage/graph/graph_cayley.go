@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
-	_ "github.com/cayleygraph/cayley/graph/kv/bolt"
+	// Same indexes.bolt file as the archived bolt backend, the file
+	// format of bbolt is compatible with bolt
+	"github.com/cayleygraph/cayley/graph/kv/bbolt"
 
 	"github.com/cayleygraph/cayley"
 	"github.com/cayleygraph/cayley/graph"
@@ -38,7 +40,7 @@ func NewPropertyGraph(config *LocalPropertyGraphConfig) (Graph, error) {
 	var err error
 
 	if !config.OpenExisting {
-		err = graph.InitQuadStore("bolt", config.DatabasePath, nil)
+		err = graph.InitQuadStore(bbolt.Type, config.DatabasePath, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to initialize quad store: %v", err)
 		}
@@ -47,7 +49,7 @@ func NewPropertyGraph(config *LocalPropertyGraphConfig) (Graph, error) {
 	// Placeholder for future options
 	options := graph.Options{}
 
-	store, err := cayley.NewGraph("bolt", config.DatabasePath, options)
+	store, err := cayley.NewGraph(bbolt.Type, config.DatabasePath, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create graph: %v", err)
 	}
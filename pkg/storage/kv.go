@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	KeyValueBackendMemory = "memory"
+	KeyValueBackendSqlite = "sqlite"
+	KeyValueBackendBolt   = "bolt"
+
+	// Shared by multiple instances in server deployments
+	KeyValueBackendPostgres = "postgres"
+)
+
+var ErrKeyNotFound = errors.New("key not found")
+
+// ScanFunc is called for every key visited by Scan. Returning an error
+// stops the scan and the error is returned by Scan.
+type ScanFunc func(key string, value []byte) error
+
+// KeyValueStorage is a namespaced key value store used by caches and
+// history. Namespaces isolate the keys of each consumer so that a single
+// backend can be shared.
+type KeyValueStorage interface {
+	// Get returns ErrKeyNotFound when the key does not exist
+	Get(ctx context.Context, namespace, key string) ([]byte, error)
+
+	// Put creates or replaces the value of a key
+	Put(ctx context.Context, namespace, key string, value []byte) error
+
+	// Scan visits the keys of a namespace matching the prefix in
+	// lexical order. An empty prefix visits all keys.
+	Scan(ctx context.Context, namespace, prefix string, fn ScanFunc) error
+
+	Close() error
+}
+
+type KeyValueStorageConfig struct {
+	// One of memory, sqlite, bolt or postgres
+	Backend string
+
+	// Path to the database file for sqlite and bolt backends
+	Path string
+
+	// Connection URL for postgres backend
+	Url string
+}
+
+// NewKeyValueStorage creates the storage for the configured backend
+func NewKeyValueStorage(config KeyValueStorageConfig) (KeyValueStorage, error) {
+	switch config.Backend {
+	case KeyValueBackendMemory:
+		return NewMemoryKeyValueStorage(), nil
+	case KeyValueBackendSqlite:
+		return NewSqliteKeyValueStorage(SqliteKeyValueStorageConfig{Path: config.Path})
+	case KeyValueBackendBolt:
+		return NewBoltKeyValueStorage(BoltKeyValueStorageConfig{Path: config.Path})
+	case KeyValueBackendPostgres:
+		return NewPostgresKeyValueStorage(PostgresKeyValueStorageConfig{Url: config.Url})
+	default:
+		return nil, fmt.Errorf("unknown key value storage backend: %q", config.Backend)
+	}
+}
+
+func validateNamespace(namespace string) error {
+	if namespace == "" {
+		return errors.New("namespace is required")
+	}
+
+	return nil
+}
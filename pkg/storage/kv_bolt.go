@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+type BoltKeyValueStorageConfig struct {
+	// Path to the bolt database file
+	Path string
+
+	// Time to wait for the file lock held by another process
+	LockTimeout time.Duration
+}
+
+// Each namespace is a bucket
+type boltKeyValueStorage struct {
+	db *bolt.DB
+}
+
+func NewBoltKeyValueStorage(config BoltKeyValueStorageConfig) (KeyValueStorage, error) {
+	if config.Path == "" {
+		return nil, errors.New("bolt storage path is required")
+	}
+
+	if config.LockTimeout == 0 {
+		config.LockTimeout = 5 * time.Second
+	}
+
+	dir := filepath.Dir(config.Path)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create DB path %s: %w", dir, err)
+	}
+
+	db, err := bolt.Open(config.Path, 0600, &bolt.Options{Timeout: config.LockTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt db: %w", err)
+	}
+
+	return &boltKeyValueStorage{db: db}, nil
+}
+
+func (s *boltKeyValueStorage) Get(_ context.Context, namespace, key string) ([]byte, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return ErrKeyNotFound
+		}
+
+		v := bucket.Get([]byte(key))
+		if v == nil {
+			return ErrKeyNotFound
+		}
+
+		// Values are only valid during the transaction
+		value = append([]byte{}, v...)
+		return nil
+	})
+
+	return value, err
+}
+
+func (s *boltKeyValueStorage) Put(_ context.Context, namespace, key string, value []byte) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+
+	if value == nil {
+		value = []byte{}
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(namespace))
+		if err != nil {
+			return fmt.Errorf("failed to create bucket: %w", err)
+		}
+
+		return bucket.Put([]byte(key), value)
+	})
+}
+
+func (s *boltKeyValueStorage) Scan(ctx context.Context, namespace, prefix string, fn ScanFunc) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+
+	type entry struct {
+		key   string
+		value []byte
+	}
+
+	// Collect entries first since fn may write to the storage which
+	// is not allowed within a read transaction
+	entries := []entry{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return nil
+		}
+
+		p := []byte(prefix)
+		cursor := bucket.Cursor()
+		for k, v := cursor.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = cursor.Next() {
+			entries = append(entries, entry{key: string(k), value: append([]byte{}, v...)})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan keys: %w", err)
+	}
+
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(e.key, e.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *boltKeyValueStorage) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type memoryKeyValueStorage struct {
+	m          sync.RWMutex
+	namespaces map[string]map[string][]byte
+}
+
+// NewMemoryKeyValueStorage creates a storage that lives only as long as
+// the process. Useful for tests and one shot scans.
+func NewMemoryKeyValueStorage() KeyValueStorage {
+	return &memoryKeyValueStorage{
+		namespaces: map[string]map[string][]byte{},
+	}
+}
+
+func (s *memoryKeyValueStorage) Get(_ context.Context, namespace, key string) ([]byte, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	value, ok := s.namespaces[namespace][key]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	return append([]byte{}, value...), nil
+}
+
+func (s *memoryKeyValueStorage) Put(_ context.Context, namespace, key string, value []byte) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if _, ok := s.namespaces[namespace]; !ok {
+		s.namespaces[namespace] = map[string][]byte{}
+	}
+
+	s.namespaces[namespace][key] = append([]byte{}, value...)
+	return nil
+}
+
+func (s *memoryKeyValueStorage) Scan(ctx context.Context, namespace, prefix string, fn ScanFunc) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+
+	// Copy matching entries so that fn can call back into the storage
+	s.m.RLock()
+	keys := []string{}
+	values := map[string][]byte{}
+	for key, value := range s.namespaces[namespace] {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+			values[key] = append([]byte{}, value...)
+		}
+	}
+	s.m.RUnlock()
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(key, values[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *memoryKeyValueStorage) Close() error {
+	return nil
+}
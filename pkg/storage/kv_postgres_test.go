@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresKeyValueStorage(t *testing.T) {
+	url := os.Getenv("VET_TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("VET_TEST_POSTGRES_URL is not set")
+	}
+
+	table := fmt.Sprintf("vet_kv_test_%d", time.Now().UnixNano())
+	kv, err := NewPostgresKeyValueStorage(PostgresKeyValueStorageConfig{
+		Url:   url,
+		Table: table,
+	})
+
+	assert.NoError(t, err)
+	defer func() {
+		_, err := kv.(*postgresKeyValueStorage).db.Exec("DROP TABLE " + table)
+		assert.NoError(t, err)
+		assert.NoError(t, kv.Close())
+	}()
+
+	testKeyValueStorage(t, kv)
+}
+
+func TestNewPostgresKeyValueStorage(t *testing.T) {
+	_, err := NewPostgresKeyValueStorage(PostgresKeyValueStorageConfig{})
+	assert.ErrorContains(t, err, "postgres storage url is required")
+
+	_, err = NewPostgresKeyValueStorage(PostgresKeyValueStorageConfig{
+		Url:   "postgres://localhost/vet",
+		Table: "kv; DROP TABLE users",
+	})
+
+	assert.ErrorContains(t, err, "invalid postgres table name")
+}
+
+func TestPostgresLikePrefix(t *testing.T) {
+	assert.Equal(t, "%", postgresLikePrefix(""))
+	assert.Equal(t, "pkg/%", postgresLikePrefix("pkg/"))
+	assert.Equal(t, `a\_b\%c\\d%`, postgresLikePrefix(`a_b%c\d`))
+}
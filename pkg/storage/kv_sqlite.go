@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const sqliteKeyValueSchema = `CREATE TABLE IF NOT EXISTS kv (
+	namespace TEXT NOT NULL,
+	key TEXT NOT NULL,
+	value BLOB NOT NULL,
+	PRIMARY KEY (namespace, key)
+)`
+
+type SqliteKeyValueStorageConfig struct {
+	// Path to the sqlite database file
+	Path string
+}
+
+type sqliteKeyValueStorage struct {
+	db *sql.DB
+}
+
+func NewSqliteKeyValueStorage(config SqliteKeyValueStorageConfig) (KeyValueStorage, error) {
+	if config.Path == "" {
+		return nil, errors.New("sqlite storage path is required")
+	}
+
+	dir := filepath.Dir(config.Path)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create DB path %s: %w", dir, err)
+	}
+
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=rwc&_busy_timeout=5000", config.Path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite3 connection: %w", err)
+	}
+
+	if _, err := db.Exec(sqliteKeyValueSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schema resources: %w", err)
+	}
+
+	return &sqliteKeyValueStorage{db: db}, nil
+}
+
+func (s *sqliteKeyValueStorage) Get(ctx context.Context, namespace, key string) ([]byte, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	var value []byte
+	err := s.db.QueryRowContext(ctx, "SELECT value FROM kv WHERE namespace = ? AND key = ?",
+		namespace, key).Scan(&value)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrKeyNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get key: %w", err)
+	}
+
+	return value, nil
+}
+
+func (s *sqliteKeyValueStorage) Put(ctx context.Context, namespace, key string, value []byte) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+
+	if value == nil {
+		value = []byte{}
+	}
+
+	_, err := s.db.ExecContext(ctx, "INSERT INTO kv (namespace, key, value) VALUES (?, ?, ?) "+
+		"ON CONFLICT (namespace, key) DO UPDATE SET value = excluded.value",
+		namespace, key, value)
+	if err != nil {
+		return fmt.Errorf("failed to put key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqliteKeyValueStorage) Scan(ctx context.Context, namespace, prefix string, fn ScanFunc) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+
+	// Results are fully read before calling fn to release the connection
+	// in case fn calls back into the storage
+	rows, err := s.db.QueryContext(ctx, "SELECT key, value FROM kv WHERE namespace = ? "+
+		"AND instr(key, ?) = 1 ORDER BY key", namespace, prefix)
+	if err != nil {
+		return fmt.Errorf("failed to scan keys: %w", err)
+	}
+
+	keys := []string{}
+	values := [][]byte{}
+	for rows.Next() {
+		var key string
+		var value []byte
+		if err := rows.Scan(&key, &value); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read scanned key: %w", err)
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	err = errors.Join(rows.Err(), rows.Close())
+	if err != nil {
+		return fmt.Errorf("failed to scan keys: %w", err)
+	}
+
+	for i, key := range keys {
+		if err := fn(key, values[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *sqliteKeyValueStorage) Close() error {
+	return s.db.Close()
+}
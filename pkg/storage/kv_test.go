@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyValueStorage(t *testing.T) {
+	backends := []string{KeyValueBackendMemory, KeyValueBackendSqlite, KeyValueBackendBolt}
+
+	for _, backend := range backends {
+		t.Run(backend, func(t *testing.T) {
+			kv, err := NewKeyValueStorage(KeyValueStorageConfig{
+				Backend: backend,
+				Path:    filepath.Join(t.TempDir(), "kv.db"),
+			})
+
+			assert.NoError(t, err)
+			defer kv.Close()
+
+			testKeyValueStorage(t, kv)
+		})
+	}
+
+	_, err := NewKeyValueStorage(KeyValueStorageConfig{Backend: "redis"})
+	assert.ErrorContains(t, err, "unknown key value storage backend")
+}
+
+func TestKeyValueStoragePersistence(t *testing.T) {
+	for _, backend := range []string{KeyValueBackendSqlite, KeyValueBackendBolt} {
+		t.Run(backend, func(t *testing.T) {
+			config := KeyValueStorageConfig{
+				Backend: backend,
+				Path:    filepath.Join(t.TempDir(), "kv.db"),
+			}
+
+			kv, err := NewKeyValueStorage(config)
+			assert.NoError(t, err)
+			assert.NoError(t, kv.Put(context.Background(), "history", "a", []byte("1")))
+			assert.NoError(t, kv.Close())
+
+			kv, err = NewKeyValueStorage(config)
+			assert.NoError(t, err)
+			defer kv.Close()
+
+			value, err := kv.Get(context.Background(), "history", "a")
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("1"), value)
+		})
+	}
+}
+
+// testKeyValueStorage verifies the contract of a storage. The storage
+// must not have keys in cache and history namespaces.
+func testKeyValueStorage(t *testing.T, kv KeyValueStorage) {
+	ctx := context.Background()
+
+	_, err := kv.Get(ctx, "cache", "a")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	assert.NoError(t, kv.Put(ctx, "cache", "pkg/b", []byte("2")))
+	assert.NoError(t, kv.Put(ctx, "cache", "pkg/a", []byte("1")))
+	assert.NoError(t, kv.Put(ctx, "cache", "other", []byte("3")))
+	assert.NoError(t, kv.Put(ctx, "history", "pkg/a", []byte("4")))
+	assert.NoError(t, kv.Put(ctx, "cache", "pkg/a", []byte("5")))
+
+	value, err := kv.Get(ctx, "cache", "pkg/a")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("5"), value)
+
+	value, err = kv.Get(ctx, "history", "pkg/a")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("4"), value)
+
+	keys := []string{}
+	err = kv.Scan(ctx, "cache", "pkg/", func(key string, _ []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pkg/a", "pkg/b"}, keys)
+
+	count := 0
+	err = kv.Scan(ctx, "cache", "", func(key string, value []byte) error {
+		count++
+		return kv.Put(ctx, "cache", key, append(value, '!'))
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	value, err = kv.Get(ctx, "cache", "other")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("3!"), value)
+
+	stop := errors.New("stop")
+	err = kv.Scan(ctx, "cache", "", func(string, []byte) error {
+		return stop
+	})
+
+	assert.ErrorIs(t, err, stop)
+	assert.NoError(t, kv.Scan(ctx, "unknown", "", func(string, []byte) error {
+		return stop
+	}))
+
+	assert.Error(t, kv.Put(ctx, "", "a", nil))
+}
@@ -0,0 +1,192 @@
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/safedep/vet/pkg/models"
+	"gopkg.in/yaml.v2"
+)
+
+// Tags are arbitrary key value metadata attached to a scan, manifest or
+// finding e.g. business_unit=payments
+type Tags map[string]string
+
+// Parse parses tags given as key=value
+func Parse(specs []string) (Tags, error) {
+	tags := Tags{}
+	for _, spec := range specs {
+		key, value, ok := strings.Cut(spec, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag %q, expected key=value", spec)
+		}
+
+		tags[key] = strings.TrimSpace(value)
+	}
+
+	return tags, nil
+}
+
+// Merge returns a new set of tags, tags of later sets take precedence
+func Merge(sets ...map[string]string) Tags {
+	merged := Tags{}
+	for _, set := range sets {
+		for key, value := range set {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
+
+// Keys returns the tag keys in sorted order
+func (t Tags) Keys() []string {
+	keys := make([]string, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// Labels returns the tags as key=value sorted by key
+func (t Tags) Labels() []string {
+	labels := make([]string, 0, len(t))
+	for _, key := range t.Keys() {
+		labels = append(labels, key+"="+t[key])
+	}
+
+	return labels
+}
+
+// String returns the tags as comma separated key=value
+func (t Tags) String() string {
+	return strings.Join(t.Labels(), ",")
+}
+
+// ManifestRule tags manifests with path matching the regex
+type ManifestRule struct {
+	Path string `yaml:"path"`
+	Tags Tags   `yaml:"tags"`
+}
+
+// FindingRule tags a finding identified by the name of the policy filter
+// or vulnerability ID
+type FindingRule struct {
+	Finding string `yaml:"finding"`
+	Tags    Tags   `yaml:"tags"`
+}
+
+// Config is the declarative definition of tags loaded from YAML
+type Config struct {
+	// Tags of the scan, applied to every manifest and finding
+	Tags Tags `yaml:"tags"`
+
+	Manifests []ManifestRule `yaml:"manifests"`
+	Findings  []FindingRule  `yaml:"findings"`
+}
+
+// LoadConfig loads the tags config from a YAML file
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read tags config: %w", err)
+	}
+
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return config, fmt.Errorf("failed to parse tags config: %w", err)
+	}
+
+	return config, nil
+}
+
+type manifestRuleProgram struct {
+	path *regexp.Regexp
+	tags Tags
+}
+
+// Tagger resolves the effective tags of manifests and findings. Tags of a
+// more specific subject take precedence i.e. scan < manifest < finding.
+// A nil Tagger resolves only the tags already attached to the manifest.
+type Tagger struct {
+	scan      Tags
+	manifests []manifestRuleProgram
+	findings  map[string]Tags
+}
+
+// NewTagger creates a tagger from config and additional scan tags which
+// take precedence over the scan tags of config e.g. from command line
+func NewTagger(config Config, scan Tags) (*Tagger, error) {
+	tagger := &Tagger{
+		scan:     Merge(config.Tags, scan),
+		findings: map[string]Tags{},
+	}
+
+	for _, rule := range config.Manifests {
+		re, err := regexp.Compile(rule.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest path pattern %q: %w", rule.Path, err)
+		}
+
+		tagger.manifests = append(tagger.manifests, manifestRuleProgram{path: re, tags: rule.Tags})
+	}
+
+	for _, rule := range config.Findings {
+		if rule.Finding == "" {
+			return nil, errors.New("finding rule must have a finding")
+		}
+
+		tagger.findings[rule.Finding] = Merge(tagger.findings[rule.Finding], rule.Tags)
+	}
+
+	return tagger, nil
+}
+
+// Scan returns the tags of the scan
+func (t *Tagger) Scan() Tags {
+	if t == nil {
+		return Tags{}
+	}
+
+	return Merge(t.scan)
+}
+
+// TagManifest attaches the scan tags and tags of matching manifest rules
+// to the manifest. Tags already attached to the manifest are retained.
+func (t *Tagger) TagManifest(manifest *models.PackageManifest) {
+	if t == nil || manifest == nil {
+		return
+	}
+
+	tags := Merge(t.scan)
+	for _, rule := range t.manifests {
+		if rule.path.MatchString(manifest.GetDisplayPath()) || rule.path.MatchString(manifest.GetPath()) {
+			tags = Merge(tags, rule.tags)
+		}
+	}
+
+	manifest.Tags = Merge(tags, manifest.Tags)
+}
+
+// Finding returns the tags of a finding of a package in the manifest
+func (t *Tagger) Finding(manifest *models.PackageManifest, findingId string) Tags {
+	tags := Tags{}
+	if manifest != nil {
+		tags = Merge(manifest.GetTags())
+	}
+
+	if t == nil {
+		return tags
+	}
+
+	return Merge(tags, t.findings[findingId])
+}
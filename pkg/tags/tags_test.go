@@ -0,0 +1,172 @@
+package tags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/safedep/vet/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name  string
+		specs []string
+		tags  Tags
+		err   bool
+	}{
+		{
+			"empty",
+			[]string{},
+			Tags{},
+			false,
+		},
+		{
+			"key value",
+			[]string{"business_unit=payments", " team = billing "},
+			Tags{"business_unit": "payments", "team": "billing"},
+			false,
+		},
+		{
+			"empty value",
+			[]string{"pci="},
+			Tags{"pci": ""},
+			false,
+		},
+		{
+			"value with separator",
+			[]string{"query=a=b"},
+			Tags{"query": "a=b"},
+			false,
+		},
+		{
+			"later tag wins",
+			[]string{"team=a", "team=b"},
+			Tags{"team": "b"},
+			false,
+		},
+		{
+			"missing separator",
+			[]string{"payments"},
+			nil,
+			true,
+		},
+		{
+			"missing key",
+			[]string{"=payments"},
+			nil,
+			true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			tags, err := Parse(test.specs)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.tags, tags)
+		})
+	}
+}
+
+func TestTagsLabels(t *testing.T) {
+	tags := Tags{"team": "billing", "business_unit": "payments"}
+
+	assert.Equal(t, []string{"business_unit", "team"}, tags.Keys())
+	assert.Equal(t, []string{"business_unit=payments", "team=billing"}, tags.Labels())
+	assert.Equal(t, "business_unit=payments,team=billing", tags.String())
+	assert.Equal(t, "", Tags{}.String())
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tags.yml")
+	err := os.WriteFile(path, []byte(`
+tags:
+  business_unit: payments
+manifests:
+  - path: "services/billing/.*"
+    tags:
+      team: billing
+findings:
+  - finding: critical-vulns
+    tags:
+      priority: p1
+`), 0600)
+	assert.NoError(t, err)
+
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+
+	assert.Equal(t, Tags{"business_unit": "payments"}, config.Tags)
+	assert.Len(t, config.Manifests, 1)
+	assert.Equal(t, "services/billing/.*", config.Manifests[0].Path)
+	assert.Equal(t, Tags{"team": "billing"}, config.Manifests[0].Tags)
+	assert.Len(t, config.Findings, 1)
+	assert.Equal(t, "critical-vulns", config.Findings[0].Finding)
+
+	_, err = LoadConfig(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.Error(t, err)
+}
+
+func TestTagger(t *testing.T) {
+	tagger, err := NewTagger(Config{
+		Tags: Tags{"business_unit": "payments", "env": "dev"},
+		Manifests: []ManifestRule{
+			{Path: "services/billing/", Tags: Tags{"team": "billing"}},
+			{Path: "package-lock\\.json$", Tags: Tags{"team": "frontend", "lang": "js"}},
+		},
+		Findings: []FindingRule{
+			{Finding: "critical-vulns", Tags: Tags{"priority": "p1", "env": "prod"}},
+		},
+	}, Tags{"env": "prod"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, Tags{"business_unit": "payments", "env": "prod"}, tagger.Scan())
+
+	billing := models.NewPackageManifestFromLocal("/src/services/billing/go.mod", models.EcosystemGo)
+	tagger.TagManifest(billing)
+	assert.Equal(t, map[string]string{"business_unit": "payments", "env": "prod", "team": "billing"}, billing.GetTags())
+
+	// Later rules take precedence
+	web := models.NewPackageManifestFromLocal("/src/services/billing/web/package-lock.json", models.EcosystemNpm)
+	tagger.TagManifest(web)
+	assert.Equal(t, "frontend", web.GetTags()["team"])
+	assert.Equal(t, "js", web.GetTags()["lang"])
+
+	// Tags already attached to the manifest are retained
+	other := models.NewPackageManifestFromLocal("/src/tools/go.mod", models.EcosystemGo)
+	other.Tags = map[string]string{"team": "platform", "env": "staging"}
+	tagger.TagManifest(other)
+	assert.Equal(t, map[string]string{"business_unit": "payments", "env": "staging", "team": "platform"}, other.GetTags())
+
+	assert.Equal(t, Tags{"business_unit": "payments", "env": "prod", "team": "billing", "priority": "p1"},
+		tagger.Finding(billing, "critical-vulns"))
+	assert.Equal(t, Tags{"business_unit": "payments", "env": "prod", "team": "billing"},
+		tagger.Finding(billing, "GHSA-1"))
+}
+
+func TestTaggerNil(t *testing.T) {
+	var tagger *Tagger
+
+	manifest := models.NewPackageManifestFromLocal("/src/go.mod", models.EcosystemGo)
+	manifest.Tags = map[string]string{"team": "platform"}
+
+	tagger.TagManifest(manifest)
+	assert.Equal(t, map[string]string{"team": "platform"}, manifest.GetTags())
+	assert.Equal(t, Tags{}, tagger.Scan())
+	assert.Equal(t, Tags{"team": "platform"}, tagger.Finding(manifest, "critical-vulns"))
+	assert.Equal(t, Tags{}, tagger.Finding(nil, "critical-vulns"))
+}
+
+func TestNewTaggerInvalidConfig(t *testing.T) {
+	_, err := NewTagger(Config{Manifests: []ManifestRule{{Path: "("}}}, nil)
+	assert.Error(t, err)
+
+	_, err = NewTagger(Config{Findings: []FindingRule{{Tags: Tags{"a": "b"}}}}, nil)
+	assert.Error(t, err)
+}
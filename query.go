@@ -104,7 +104,7 @@ func internalStartQuery() error {
 
 	if !utils.IsEmptyString(queryFilterExpression) {
 		task, err := analyzer.NewCelFilterAnalyzer(queryFilterExpression,
-			queryFilterFailOnMatch)
+			queryFilterFailOnMatch, nil)
 		if err != nil {
 			return err
 		}
@@ -114,7 +114,7 @@ func internalStartQuery() error {
 
 	if !utils.IsEmptyString(queryFilterSuiteFile) {
 		task, err := analyzer.NewCelFilterSuiteAnalyzer(queryFilterSuiteFile,
-			queryFilterFailOnMatch)
+			queryFilterFailOnMatch, nil)
 		if err != nil {
 			return err
 		}
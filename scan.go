@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/google/go-github/v54/github"
@@ -10,16 +17,33 @@ import (
 	"github.com/safedep/vet/internal/auth"
 	"github.com/safedep/vet/internal/command"
 	"github.com/safedep/vet/internal/connect"
+	"github.com/safedep/vet/internal/telemetry"
 	"github.com/safedep/vet/internal/ui"
 	"github.com/safedep/vet/pkg/analyzer"
+	"github.com/safedep/vet/pkg/analyzer/filter"
 	"github.com/safedep/vet/pkg/code"
+	"github.com/safedep/vet/pkg/common/concurrency"
+	"github.com/safedep/vet/pkg/common/errcode"
 	"github.com/safedep/vet/pkg/common/logger"
+	"github.com/safedep/vet/pkg/common/paths"
+	"github.com/safedep/vet/pkg/coverage"
+	"github.com/safedep/vet/pkg/cvss"
+	"github.com/safedep/vet/pkg/degradation"
+	"github.com/safedep/vet/pkg/eventbus"
+	"github.com/safedep/vet/pkg/feedback"
+	"github.com/safedep/vet/pkg/framework"
+	"github.com/safedep/vet/pkg/history"
 	"github.com/safedep/vet/pkg/models"
 	"github.com/safedep/vet/pkg/parser"
+	"github.com/safedep/vet/pkg/policy"
+	"github.com/safedep/vet/pkg/project"
 	"github.com/safedep/vet/pkg/readers"
+	"github.com/safedep/vet/pkg/redact"
+	"github.com/safedep/vet/pkg/remediations"
 	"github.com/safedep/vet/pkg/reporter"
 	"github.com/safedep/vet/pkg/scanner"
 	"github.com/safedep/vet/pkg/storage"
+	"github.com/safedep/vet/pkg/tags"
 	"github.com/spf13/cobra"
 )
 
@@ -44,7 +68,10 @@ var (
 	transitiveDepth                int
 	dependencyUsageEvidence        bool
 	codeAnalysisDBPath             string
-	concurrency                    int
+	concurrentAnalyzers            int
+	subsystemWorkers               map[string]int
+	subsystemQueueSizes            map[string]int
+	subsystemMaxConcurrency        map[string]int
 	dumpJsonManifestDir            string
 	celFilterExpression            string
 	celFilterSuiteFile             string
@@ -64,6 +91,25 @@ var (
 	syncReport                     bool
 	syncReportProject              string
 	syncEnableMultiProject         bool
+	syncFailOnError                bool
+	syncSpoolDir                   string
+	syncOffline                    bool
+	syncKeepalive                  time.Duration
+	syncTokenExpiryWarning         time.Duration
+	syncCACertFile                 string
+	syncInsecureSkipVerify         bool
+	syncPlaintext                  bool
+	syncProxyUrl                   string
+	syncTenantId                   string
+	syncWorkspace                  string
+	syncTransport                  string
+	syncRedactConfigFile           string
+	syncRateLimit                  float64
+	syncAdaptiveRateLimit          bool
+	syncPublishTimeout             time.Duration
+	syncQueueOverflow              string
+	syncDryRunFile                 string
+	syncResumable                  bool
 	graphReportDirectory           string
 	syncReportStream               string
 	listExperimentalParsers        bool
@@ -72,6 +118,24 @@ var (
 	scannerExperimental            bool
 	malwareAnalyzerTrustToolResult bool
 	malwareAnalysisTimeout         time.Duration
+	disableFeedback                bool
+	disableAnalyzerCache           bool
+	recordHistory                  bool
+	historyStorageBackend          string
+	historyStorageUrl              string
+	whatIfVersionBumpsFile         string
+	registryMirrors                map[string]string
+	lockfileCheck                  bool
+	internalNamespaces             []string
+	attackPatternRulesFile         string
+	policyDecisionLog              string
+	cvssExposure                   string
+	cvssDataSensitivity            string
+	degradeSpecs                   []string
+	scanTags                       []string
+	scanTagsConfigFile             string
+	summaryReportGroupByTag        string
+	alternativesFromRegistry       bool
 )
 
 func newScanCommand() *cobra.Command {
@@ -131,8 +195,14 @@ func newScanCommand() *cobra.Command {
 		"Analyze transitive dependencies till depth")
 	cmd.Flags().StringVarP(&codeAnalysisDBPath, "code", "", "", "Path to code analysis database generated by 'vet code scan'")
 	cmd.Flags().BoolVarP(&dependencyUsageEvidence, "code-dependency-usage-evidence", "", true, "Enable dependency usage evidence during scan")
-	cmd.Flags().IntVarP(&concurrency, "concurrency", "C", 5,
-		"Number of concurrent analysis to run")
+	cmd.Flags().IntVarP(&concurrentAnalyzers, "concurrency", "C", 0,
+		"Number of concurrent analysis to run, auto-detected from CPU count when zero")
+	cmd.Flags().StringToIntVarP(&subsystemWorkers, "workers", "", map[string]int{},
+		"Override number of workers by subsystem (e.g. sync=20, insights=32)")
+	cmd.Flags().StringToIntVarP(&subsystemQueueSizes, "queue-size", "", map[string]int{},
+		"Override queue size by subsystem (e.g. reporter=5000, sync=2000)")
+	cmd.Flags().StringToIntVarP(&subsystemMaxConcurrency, "max-concurrency", "", map[string]int{},
+		"Max concurrent API requests by subsystem to cap workers (e.g. insights=50)")
 	cmd.Flags().StringVarP(&dumpJsonManifestDir, "json-dump-dir", "", "",
 		"Dump enriched package manifests as JSON files to dir")
 	cmd.Flags().StringVarP(&celFilterExpression, "filter", "", "",
@@ -157,6 +227,10 @@ func newScanCommand() *cobra.Command {
 		"Group summary report by direct dependencies")
 	cmd.Flags().BoolVarP(&summaryReportUsedOnly, "report-summary-used-only", "", false,
 		"Show only packages that are used in code (requires code analysis)")
+	cmd.Flags().StringVarP(&summaryReportGroupByTag, "report-summary-group-by-tag", "", "",
+		"Group findings in summary report by value of the tag (e.g. business_unit)")
+	cmd.Flags().BoolVarP(&alternativesFromRegistry, "alternatives-from-registry", "", false,
+		"Suggest npm packages with similar registry keywords as alternatives in addition to curated alternatives")
 	cmd.Flags().StringVarP(&csvReportPath, "report-csv", "", "",
 		"Generate CSV report of filtered packages")
 	cmd.Flags().StringVarP(&jsonReportPath, "report-json", "", "",
@@ -168,19 +242,90 @@ func newScanCommand() *cobra.Command {
 	cmd.Flags().BoolVarP(&syncReport, "report-sync", "", false,
 		"Enable syncing report data to cloud")
 	cmd.Flags().StringVarP(&syncReportProject, "report-sync-project", "", "",
-		"Project name to use in cloud")
+		"Project name to use in cloud (default inferred from go.mod, package.json, pyproject.toml or git remote)")
 	cmd.Flags().BoolVarP(&syncEnableMultiProject, "report-sync-multi-project", "", false,
 		"Lazily create cloud sessions for multiple projects (per manifest)")
 	cmd.Flags().StringVarP(&syncReportStream, "report-sync-project-version", "", "",
-		"Project stream name (e.g. branch) to use in cloud")
+		"Project stream name (e.g. branch) to use in cloud (default inferred from package.json or pyproject.toml)")
+	cmd.Flags().BoolVarP(&syncFailOnError, "report-sync-fail-on-error", "", false,
+		"Fail the scan if any package or policy violation failed to sync to cloud")
+	cmd.Flags().StringVarP(&syncSpoolDir, "report-sync-spool-dir", "", "",
+		"Spool sync data to directory when cloud is unreachable, publish later using vet cloud flush")
+	cmd.Flags().BoolVarP(&syncOffline, "report-sync-offline", "", false,
+		"Spool sync data without connecting to cloud (default spool directory ~/.safedep/vet-sync-spool)")
+	cmd.Flags().DurationVarP(&syncKeepalive, "report-sync-keepalive", "", 0,
+		"Interval of keepalive pings to ControlTower, minimum 10s (default disabled)")
+	cmd.Flags().StringVarP(&syncCACertFile, "report-sync-ca-cert", "", "",
+		"PEM encoded CA certificate to trust for self-hosted ControlTower")
+	cmd.Flags().BoolVarP(&syncInsecureSkipVerify, "report-sync-insecure-skip-verify", "", false,
+		"Skip TLS certificate verification of ControlTower (insecure)")
+	cmd.Flags().BoolVarP(&syncPlaintext, "report-sync-plaintext", "", false,
+		"Connect to ControlTower without TLS (insecure)")
+	cmd.Flags().StringVarP(&syncProxyUrl, "report-sync-proxy", "", "",
+		"HTTP proxy for ControlTower (default from HTTPS_PROXY)")
+	cmd.Flags().StringVarP(&syncTenantId, "report-sync-tenant", "", "",
+		"ControlTower tenant ID to sync to (default from VET_CONTROL_TOWER_TENANT_ID or cloud config)")
+	cmd.Flags().StringVarP(&syncWorkspace, "report-sync-workspace", "", "",
+		"ControlTower workspace (team) within the tenant (default from VET_CONTROL_TOWER_WORKSPACE)")
+	cmd.Flags().StringVarP(&syncTransport, "report-sync-transport", "", "grpc",
+		"Transport to ControlTower (grpc, http), use http when gRPC is blocked")
+	cmd.Flags().StringVarP(&syncRedactConfigFile, "report-sync-redact-config", "", "",
+		"Strip or hash manifest paths, namespaces and internal package names before sync (YAML)")
+	cmd.Flags().Float64VarP(&syncRateLimit, "report-sync-rate-limit", "", 0,
+		"Max requests per second to cloud across all sync workers (0 for unlimited)")
+	cmd.Flags().BoolVarP(&syncAdaptiveRateLimit, "report-sync-adaptive-rate-limit", "", false,
+		"Lower sync rate limit when cloud is overloaded and recover gradually")
+	cmd.Flags().DurationVarP(&syncPublishTimeout, "report-sync-publish-timeout", "", 30*time.Second,
+		"Timeout of each call to publish data to cloud (negative to disable)")
+	cmd.Flags().StringVarP(&syncQueueOverflow, "report-sync-queue-overflow", "", "block",
+		"Action when sync queue (--queue-size sync=N) is full (block, drop, spill)")
+	cmd.Flags().StringVarP(&syncDryRunFile, "report-sync-dry-run", "", "",
+		"Write sync data to file as JSON lines instead of syncing to cloud, to audit what is synced")
+	cmd.Flags().BoolVarP(&syncResumable, "report-sync-resumable", "", false,
+		"Journal sync progress so that an interrupted sync can be resumed using 'vet cloud resume'")
+	cmd.Flags().DurationVarP(&syncTokenExpiryWarning, "report-sync-token-expiry-warning", "", auth.DefaultTokenExpiryWarning,
+		"Warn when the cloud token expires within this duration (0 to disable)")
 	cmd.Flags().StringArrayVarP(&trustedRegistryUrls, "trusted-registry", "", []string{},
 		"Trusted registry URLs to use for package manifest verification")
+	cmd.Flags().BoolVarP(&lockfileCheck, "lockfile-check", "", false,
+		"Fail if lockfile is not in sync with manifest or resolves packages from untrusted registries")
+	cmd.Flags().StringVarP(&attackPatternRulesFile, "attack-patterns", "", "",
+		"Evaluate composite supply chain attack pattern rules from file (YAML)")
+	cmd.Flags().StringVarP(&policyDecisionLog, "policy-decision-log", "", "",
+		"Log every policy decision to file (JSON lines) or HTTP(S) URL (NDJSON)")
+	cmd.Flags().StringVarP(&cvssExposure, "cvss-exposure", "", "",
+		"Exposure of the project (internet, internal, local) to compute cvss.adjusted_score for policies")
+	cmd.Flags().StringVarP(&cvssDataSensitivity, "cvss-data-sensitivity", "", "",
+		"Sensitivity of data handled by the project (high, medium, low) to compute cvss.adjusted_score for policies")
+	cmd.Flags().StringArrayVarP(&internalNamespaces, "internal-namespace", "", []string{},
+		"Verify internal namespace is claimed in public registry, optionally by owner accounts (e.g. npm=@acme:acme-bot, maven=com.acme, pypi=acme-:acme)")
+	cmd.Flags().StringToStringVarP(&registryMirrors, "registry-mirror", "", map[string]string{},
+		"Check freshness of private registry mirror against upstream (e.g. npm=https://npm.example.com)")
 	cmd.Flags().BoolVarP(&scannerExperimental, "experimental", "", false,
 		"Enable experimental features in scanner")
 	cmd.Flags().BoolVarP(&malwareAnalyzerTrustToolResult, "malware-trust-tool-result", "", false,
 		"Trust malicious package analysis tool result without verification record")
+	cmd.Flags().StringVarP(&whatIfVersionBumpsFile, "what-if", "", "",
+		"Simulate findings after applying version bumps from file (JSON list or Renovate report)")
+	cmd.Flags().BoolVarP(&disableFeedback, "no-feedback", "", false,
+		"Do not use recorded false positive feedback to filter findings")
+	cmd.Flags().BoolVarP(&disableAnalyzerCache, "no-analyzer-cache", "", false,
+		"Do not use cached analyzer results from previous runs")
+	cmd.Flags().BoolVarP(&recordHistory, "history", "", false,
+		"Record manifest fingerprints to detect manifests unchanged since the last scan and report triage states of findings")
+	cmd.Flags().StringVarP(&historyStorageBackend, "history-storage", "", "file",
+		"Storage backend for manifest history and triage states (file, sqlite, bolt, postgres)")
+	cmd.Flags().StringVarP(&historyStorageUrl, "history-storage-url", "", "",
+		"Connection URL for postgres history storage shared by multiple instances")
 	cmd.Flags().DurationVarP(&malwareAnalysisTimeout, "malware-analysis-timeout", "", 5*time.Minute,
 		"Timeout for malicious package analysis")
+	cmd.Flags().StringSliceVarP(&degradeSpecs, "degrade", "", []string{},
+		"Behavior on failure of an upstream service as source=mode e.g. insights=fail "+
+			"(insights: cache, fail; osv: unknown, fail; controltower: spool, fail)")
+	cmd.Flags().StringArrayVarP(&scanTags, "tag", "", []string{},
+		"Tag the scan, its manifests and findings as key=value (e.g. business_unit=payments)")
+	cmd.Flags().StringVarP(&scanTagsConfigFile, "tags-config", "", "",
+		"Tag the scan, manifests by path and findings by ID from file (YAML)")
 
 	// Add validations that should trigger a fail fast condition
 	cmd.PreRun = func(cmd *cobra.Command, args []string) {
@@ -195,10 +340,43 @@ func newScanCommand() *cobra.Command {
 					"Enable with --code")
 			}
 
+			if syncDryRunFile != "" && !syncReport {
+				return fmt.Errorf("sync dry run requires --report-sync")
+			}
+
+			if syncAdaptiveRateLimit && syncRateLimit <= 0 {
+				return fmt.Errorf("adaptive sync rate limit requires --report-sync-rate-limit")
+			}
+
+			if _, err := degradation.ParseMatrix(degradeSpecs); err != nil {
+				return err
+			}
+
+			if _, err := tags.Parse(scanTags); err != nil {
+				return err
+			}
+
+			if err := cvssEnvironment().Validate(); err != nil {
+				return err
+			}
+
+			switch reporter.SyncTransport(syncTransport) {
+			case reporter.SyncTransportGrpc, reporter.SyncTransportHttp:
+			default:
+				return fmt.Errorf("invalid sync transport: %s, must be one of grpc, http", syncTransport)
+			}
+
+			switch reporter.SyncQueueOverflow(syncQueueOverflow) {
+			case reporter.SyncQueueOverflowBlock, reporter.SyncQueueOverflowDrop, reporter.SyncQueueOverflowSpill:
+			default:
+				return fmt.Errorf("invalid sync queue overflow: %s, must be one of block, drop, spill",
+					syncQueueOverflow)
+			}
+
 			return nil
 		}()
 
-		command.FailOnError("pre-scan", err)
+		command.FailOnError("pre-scan", errcode.Wrap(errcode.ConfigInvalid, err))
 	}
 
 	cmd.AddCommand(listParsersCommand())
@@ -246,10 +424,456 @@ func startScan() {
 	command.FailOnError("scan", internalStartScan())
 }
 
+// Results of deterministic analyzers are cached across runs. Failure to
+// setup the cache is not fatal, analyzers will compute results instead.
+func buildAnalyzerResultCache() analyzer.ResultCache {
+	if disableAnalyzerCache || !paths.Writable(paths.KindCache) {
+		return nil
+	}
+
+	config, err := analyzer.DefaultFileResultCacheConfig()
+	if err != nil {
+		logger.Warnf("Failed to setup analyzer result cache: %v", err)
+		return nil
+	}
+
+	// Results must not be written in plaintext when encryption is requested
+	config.Cipher, err = auth.EncryptionCipher()
+	if err != nil {
+		logger.Warnf("Failed to setup analyzer result cache: %v", err)
+		return nil
+	}
+
+	cache, err := analyzer.NewFileResultCache(config)
+	if err != nil {
+		logger.Warnf("Failed to setup analyzer result cache: %v", err)
+		return nil
+	}
+
+	return cache
+}
+
+// manifestHistoryRecorder fingerprints manifests as they are discovered
+// and records them in history only when the scan succeeds
+type manifestHistoryRecorder struct {
+	store  history.Store
+	triage history.TriageStore
+	closer io.Closer
+
+	m            sync.Mutex
+	fingerprints []history.ManifestFingerprint
+}
+
+// buildInsightsCache returns nil when insights are not to be cached. Failure
+// to open the cache e.g. when locked by another scan, disables the cache.
+func buildInsightsCache(matrix degradation.Matrix) (storage.KeyValueStorage, error) {
+	if matrix.Mode(degradation.SourceInsights) != degradation.ModeCache || !paths.Writable(paths.KindCache) {
+		return nil, nil
+	}
+
+	path, err := scanner.DefaultInsightsCachePath()
+	if err != nil {
+		return nil, err
+	}
+
+	kv, err := storage.NewBoltKeyValueStorage(storage.BoltKeyValueStorageConfig{Path: path})
+	if err != nil {
+		logger.Warnf("Insights cache is disabled, failed to open: %v", err)
+		return nil, nil
+	}
+
+	return kv, nil
+}
+
+func buildManifestHistoryRecorder() (*manifestHistoryRecorder, error) {
+	if !recordHistory {
+		return nil, nil
+	}
+
+	if historyStorageBackend == "file" {
+		config, err := history.DefaultFileStoreConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		store, err := history.NewFileStore(config)
+		if err != nil {
+			return nil, err
+		}
+
+		triage, err := buildTriageStore(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return &manifestHistoryRecorder{store: store, triage: triage}, nil
+	}
+
+	kv, err := openHistoryStorage(historyStorageBackend, historyStorageUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := history.NewKeyValueStore(kv)
+	if err != nil {
+		kv.Close()
+		return nil, err
+	}
+
+	triage, err := buildTriageStore(kv)
+	if err != nil {
+		kv.Close()
+		return nil, err
+	}
+
+	return &manifestHistoryRecorder{store: store, triage: triage, closer: kv}, nil
+}
+
+// openHistoryStorage opens the key value storage shared by manifest
+// history and triage states for backends other than file
+func openHistoryStorage(backend, url string) (storage.KeyValueStorage, error) {
+	// Only database file backends write locally, others work in
+	// stateless mode
+	path := ""
+	if backend == storage.KeyValueBackendSqlite || backend == storage.KeyValueBackendBolt {
+		config, err := history.DefaultFileStoreConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		path = strings.TrimSuffix(config.Path, filepath.Ext(config.Path)) + ".db"
+	}
+
+	kv, err := storage.NewKeyValueStorage(storage.KeyValueStorageConfig{
+		Backend: backend,
+		Path:    path,
+		Url:     url,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create history storage: %w", err)
+	}
+
+	return kv, nil
+}
+
+// buildTriageStore uses the key value storage when available, otherwise
+// the file backend
+func buildTriageStore(kv storage.KeyValueStorage) (history.TriageStore, error) {
+	if kv != nil {
+		return history.NewKeyValueTriageStore(kv)
+	}
+
+	config, err := history.DefaultTriageFileStoreConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return history.NewFileTriageStore(config)
+}
+
+// triageStore returns nil when history is not recorded
+func (r *manifestHistoryRecorder) triageStore() history.TriageStore {
+	if r == nil {
+		return nil
+	}
+
+	return r.triage
+}
+
+func (r *manifestHistoryRecorder) observe(manifest *models.PackageManifest) {
+	if r == nil {
+		return
+	}
+
+	fingerprint, err := history.NewManifestFingerprint(manifest)
+	if err != nil {
+		logger.Warnf("Failed to fingerprint manifest %s: %v", manifest.GetDisplayPath(), err)
+		return
+	}
+
+	unchanged, err := history.Unchanged(r.store, fingerprint)
+	if err != nil {
+		logger.Warnf("Failed to lookup manifest history: %v", err)
+	} else if unchanged {
+		logger.Infof("Manifest %s is unchanged since the last scan", manifest.GetDisplayPath())
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.fingerprints = append(r.fingerprints, fingerprint)
+}
+
+func (r *manifestHistoryRecorder) commit() {
+	if r == nil {
+		return
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for _, fingerprint := range r.fingerprints {
+		if err := r.store.Put(fingerprint); err != nil {
+			logger.Warnf("Failed to record manifest history: %v", err)
+			return
+		}
+	}
+}
+
+func (r *manifestHistoryRecorder) close() {
+	if r == nil || r.closer == nil {
+		return
+	}
+
+	if err := r.closer.Close(); err != nil {
+		logger.Warnf("Failed to close manifest history storage: %v", err)
+	}
+}
+
+// Interval of progress messages of sync after the progress writer is stopped
+const syncProgressMessageInterval = 5 * time.Second
+
+// syncProgress shows progress of publishing to the cloud on a tracker while
+// scanning and as periodic messages while the sync reporter finishes, so
+// that a long sync does not appear to hang
+type syncProgress struct {
+	m           sync.Mutex
+	tracker     any
+	stopped     bool
+	lastMessage time.Time
+}
+
+func (p *syncProgress) start(tracker any) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.tracker = tracker
+}
+
+// stop is called when the progress writer is stopped
+func (p *syncProgress) stop() {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	ui.MarkTrackerAsDone(p.tracker)
+	p.stopped = true
+	p.lastMessage = time.Now()
+}
+
+func (p *syncProgress) update(completed, total int) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	// Tracker is not completed while scanning because more items are queued
+	if !p.stopped {
+		ui.UpdateTrackerTotal(p.tracker, int64(total))
+		if completed < total {
+			ui.UpdateValue(p.tracker, int64(completed))
+		}
+
+		return
+	}
+
+	if silentScan || completed == total || time.Since(p.lastMessage) < syncProgressMessageInterval {
+		return
+	}
+
+	p.lastMessage = time.Now()
+	ui.PrintMsg("Syncing to cloud: %d of %d items completed", completed, total)
+}
+
+// Feedback recorded using `vet feedback` is used to filter heuristic findings.
+// Feedback is not available in stateless mode unless a data directory is given.
+// Scan continues without filtering when feedback is not readable.
+func buildFeedbackFindingFilter() analyzer.FindingFilter {
+	if disableFeedback || !paths.Writable(paths.KindData) {
+		return nil
+	}
+
+	config, err := feedback.DefaultFileStoreConfig()
+	if err != nil {
+		logger.Warnf("Failed to setup feedback store, findings are not filtered: %v", err)
+		return nil
+	}
+
+	store, err := feedback.NewFileStore(config)
+	if err != nil {
+		logger.Warnf("Failed to setup feedback store, findings are not filtered: %v", err)
+		return nil
+	}
+
+	records, err := store.Records()
+	if err != nil {
+		logger.Warnf("Failed to read feedback, findings are not filtered: %v", err)
+		return nil
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	ui.PrintMsg("Using %d feedback records to filter findings", len(records))
+	return feedback.NewFindingFilter(records)
+}
+
+// buildTagger returns nil when the scan is not tagged
+func buildTagger() (*tags.Tagger, error) {
+	if len(scanTags) == 0 && scanTagsConfigFile == "" {
+		return nil, nil
+	}
+
+	scan, err := tags.Parse(scanTags)
+	if err != nil {
+		return nil, err
+	}
+
+	config := tags.Config{}
+	if scanTagsConfigFile != "" {
+		config, err = tags.LoadConfig(scanTagsConfigFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tags.NewTagger(config, scan)
+}
+
+func cvssEnvironment() cvss.Environment {
+	return cvss.Environment{
+		Exposure:        cvss.Exposure(cvssExposure),
+		DataSensitivity: cvss.Sensitivity(cvssDataSensitivity),
+	}
+}
+
+func buildSyncRedactor() (*redact.Redactor, error) {
+	if syncRedactConfigFile == "" {
+		return nil, nil
+	}
+
+	config, err := redact.LoadConfig(syncRedactConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return redact.NewRedactor(config)
+}
+
+// syncTenant returns the tenant given by the user or configured for cloud
+func syncTenant() string {
+	if syncTenantId != "" {
+		return syncTenantId
+	}
+
+	return auth.TenantDomain()
+}
+
+// inferSyncProject returns the project to sync inferred from the repository
+// files in dir. Project name and version given by the user take precedence.
+func inferSyncProject(dir string) (name, version, sourceUrl string) {
+	name, version = syncReportProject, syncReportStream
+	if dir == "" || syncEnableMultiProject {
+		return name, version, ""
+	}
+
+	metadata, err := project.Infer(dir)
+	if err != nil {
+		logger.Warnf("Failed to infer project metadata: %v", err)
+	}
+
+	if name == "" {
+		name = metadata.Name
+	}
+
+	if version == "" {
+		version = metadata.Version
+	}
+
+	logger.Debugf("Syncing project: %s, version: %s, source: %s", name, version, metadata.SourceUrl)
+	return name, version, metadata.SourceUrl
+}
+
+// buildAlternativesProvider suggests curated alternatives, and npm packages
+// with similar registry keywords when enabled
+func buildAlternativesProvider() remediations.AlternativesProvider {
+	curated := remediations.NewCuratedAlternativesProvider()
+	if !alternativesFromRegistry {
+		return curated
+	}
+
+	return remediations.NewChainedAlternativesProvider(curated,
+		remediations.NewNpmKeywordAlternativesProvider(remediations.NpmKeywordAlternativesProviderConfig{}))
+}
+
+// Concurrency config must be applied before any subsystem is created
+func configureConcurrency() error {
+	config := concurrency.Config{
+		MaxConcurrency: map[concurrency.Subsystem]int{},
+		Overrides:      map[concurrency.Subsystem]concurrency.Limits{},
+	}
+
+	for name, workers := range subsystemWorkers {
+		subsystem, err := concurrency.ParseSubsystem(name)
+		if err != nil {
+			return err
+		}
+
+		limits := config.Overrides[subsystem]
+		limits.Workers = workers
+		config.Overrides[subsystem] = limits
+	}
+
+	for name, queueSize := range subsystemQueueSizes {
+		subsystem, err := concurrency.ParseSubsystem(name)
+		if err != nil {
+			return err
+		}
+
+		limits := config.Overrides[subsystem]
+		limits.QueueSize = queueSize
+		config.Overrides[subsystem] = limits
+	}
+
+	for name, maxConcurrency := range subsystemMaxConcurrency {
+		subsystem, err := concurrency.ParseSubsystem(name)
+		if err != nil {
+			return err
+		}
+
+		config.MaxConcurrency[subsystem] = maxConcurrency
+	}
+
+	concurrency.Configure(config)
+	return nil
+}
+
 func internalStartScan() error {
+	if err := configureConcurrency(); err != nil {
+		return err
+	}
+
+	// Interrupt stops scanning new manifests but reports the data collected
+	// so far. Reporters publishing to remote services abort in-flight requests.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Subsystems observing the scan subscribe to its events
+	events := eventbus.New()
+
+	degradationMatrix, err := degradation.ParseMatrix(degradeSpecs)
+	if err != nil {
+		return err
+	}
+
+	degradations := degradation.NewRecorder()
+	notScanned := coverage.NewRecorder()
+
+	tagger, err := buildTagger()
+	if err != nil {
+		return err
+	}
+
 	readerList := []readers.PackageManifestReader{}
 	var reader readers.PackageManifestReader
-	var err error
 
 	githubClientBuilder := func() *github.Client {
 		githubClient, err := connect.GetGithubClient()
@@ -268,13 +892,21 @@ func internalStartScan() error {
 		manifestType = lockfileAs
 	}
 
+	// Local directory of the project from which project metadata is
+	// inferred, not available for remote sources
+	projectDir := ""
+
 	// We can easily support both directory and lockfile reader. But current UX
 	// contract is to support one of them at a time. Lets not break the contract
 	// for now and figure out UX improvement later
 	if len(lockfiles) > 0 {
+		projectDir = filepath.Dir(lockfiles[0])
+
 		// nolint:ineffassign,staticcheck
 		reader, err = readers.NewLockfileReader(lockfiles, manifestType)
 	} else if len(manifests) > 0 {
+		projectDir = filepath.Dir(manifests[0])
+
 		// We will make manifestType backward compatible with lockfileAs
 		if manifestType == "" {
 			manifestType = lockfileAs
@@ -313,11 +945,14 @@ func internalStartScan() error {
 			reader, err = readers.NewVSCodeExtReader(vsxDirectories)
 		}
 	} else {
+		projectDir = baseDirectory
+
 		// nolint:ineffassign,staticcheck
 		reader, err = readers.NewDirectoryReader(readers.DirectoryReaderConfig{
 			Path:                 baseDirectory,
 			Exclusions:           scanExclude,
 			ManifestTypeOverride: manifestType,
+			Coverage:             notScanned,
 		})
 	}
 
@@ -325,18 +960,61 @@ func internalStartScan() error {
 		return err
 	}
 
+	if !utils.IsEmptyString(whatIfVersionBumpsFile) {
+		bumps, err := readers.NewVersionBumpsFromFile(whatIfVersionBumpsFile)
+		if err != nil {
+			return err
+		}
+
+		reader, err = readers.NewWhatIfReader(reader, bumps)
+		if err != nil {
+			return err
+		}
+
+		ui.PrintMsg("Simulating %d version bumps, manifests will not be modified", len(bumps))
+	}
+
 	readerList = append(readerList, reader)
 
+	findingFilter := buildFeedbackFindingFilter()
+
+	filter.SetCvssEnvironment(cvssEnvironment())
+
+	var decisionLogger policy.DecisionLogger
+	if !utils.IsEmptyString(policyDecisionLog) {
+		decisionLogger, err = policy.NewDecisionLogger(policyDecisionLog)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if err := decisionLogger.Close(); err != nil {
+				ui.PrintWarning("Failed to close policy decision log: %v", err)
+			}
+		}()
+	}
+
 	// We will always use this analyzer
 	lfpAnalyzer, err := analyzer.NewLockfilePoisoningAnalyzer(analyzer.LockfilePoisoningAnalyzerConfig{
-		FailFast:            failFast,
+		FailFast:            failFast || lockfileCheck,
 		TrustedRegistryUrls: trustedRegistryUrls,
+		FindingFilter:       findingFilter,
 	})
 	if err != nil {
 		return err
 	}
 
 	analyzers := []analyzer.Analyzer{lfpAnalyzer}
+	if lockfileCheck {
+		task, err := analyzer.NewLockfileDriftAnalyzer(analyzer.LockfileDriftAnalyzerConfig{
+			FailOnDrift: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		analyzers = append(analyzers, task)
+	}
 	if !utils.IsEmptyString(dumpJsonManifestDir) {
 		task, err := analyzer.NewJsonDumperAnalyzer(dumpJsonManifestDir)
 		if err != nil {
@@ -348,7 +1026,7 @@ func internalStartScan() error {
 
 	if !utils.IsEmptyString(celFilterExpression) {
 		task, err := analyzer.NewCelFilterAnalyzer(celFilterExpression,
-			failFast || celFilterFailOnMatch)
+			failFast || celFilterFailOnMatch, decisionLogger)
 		if err != nil {
 			return err
 		}
@@ -358,7 +1036,58 @@ func internalStartScan() error {
 
 	if !utils.IsEmptyString(celFilterSuiteFile) {
 		task, err := analyzer.NewCelFilterSuiteAnalyzer(celFilterSuiteFile,
-			failFast || celFilterFailOnMatch)
+			failFast || celFilterFailOnMatch, decisionLogger)
+		if err != nil {
+			return err
+		}
+
+		analyzers = append(analyzers, task)
+	}
+
+	if !utils.IsEmptyString(attackPatternRulesFile) {
+		ruleSet, err := analyzer.LoadAttackPatternRuleSetFromFile(attackPatternRulesFile)
+		if err != nil {
+			return err
+		}
+
+		task, err := analyzer.NewAttackPatternAnalyzer(analyzer.AttackPatternAnalyzerConfig{
+			RuleSet:        ruleSet,
+			FailOnMatch:    failFast,
+			DecisionLogger: decisionLogger,
+		})
+		if err != nil {
+			return err
+		}
+
+		analyzers = append(analyzers, task)
+	}
+
+	if len(internalNamespaces) > 0 {
+		namespaces := []analyzer.InternalNamespace{}
+		for _, ns := range internalNamespaces {
+			namespace, err := analyzer.ParseInternalNamespace(ns)
+			if err != nil {
+				return err
+			}
+
+			namespaces = append(namespaces, namespace)
+		}
+
+		task, err := analyzer.NewNamespaceOwnershipAnalyzer(analyzer.NamespaceOwnershipAnalyzerConfig{
+			Namespaces: namespaces,
+			Cache:      buildAnalyzerResultCache(),
+		})
+		if err != nil {
+			return err
+		}
+
+		analyzers = append(analyzers, task)
+	}
+
+	if len(registryMirrors) > 0 {
+		task, err := analyzer.NewMirrorFreshnessAnalyzer(analyzer.MirrorFreshnessAnalyzerConfig{
+			Mirrors: registryMirrors,
+		})
 		if err != nil {
 			return err
 		}
@@ -370,6 +1099,7 @@ func internalStartScan() error {
 		config := analyzer.DefaultMalwareAnalyzerConfig()
 		config.TrustAutomatedAnalysis = malwareAnalyzerTrustToolResult
 		config.FailFast = failFast
+		config.FindingFilter = findingFilter
 
 		task, err := analyzer.NewMalwareAnalyzer(config)
 		if err != nil {
@@ -379,6 +1109,13 @@ func internalStartScan() error {
 		analyzers = append(analyzers, task)
 	}
 
+	historyRecorder, err := buildManifestHistoryRecorder()
+	if err != nil {
+		return err
+	}
+
+	defer historyRecorder.close()
+
 	reporters := []reporter.Reporter{}
 	if consoleReport {
 		rp, err := reporter.NewConsoleReporter()
@@ -389,11 +1126,15 @@ func internalStartScan() error {
 		reporters = append(reporters, rp)
 	}
 
+	alternatives := buildAlternativesProvider()
+
 	if summaryReport {
 		rp, err := reporter.NewSummaryReporter(reporter.SummaryReporterConfig{
 			MaxAdvice:                    summaryReportMaxAdvice,
 			GroupByDirectDependency:      summaryReportGroupByDirectDeps,
 			ShowOnlyPackagesWithEvidence: summaryReportUsedOnly,
+			GroupByTag:                   summaryReportGroupByTag,
+			Alternatives:                 alternatives,
 		})
 		if err != nil {
 			return err
@@ -417,6 +1158,8 @@ func internalStartScan() error {
 		rp, err := reporter.NewMarkdownSummaryReporter(reporter.MarkdownSummaryReporterConfig{
 			Path:                   markdownSummaryReportPath,
 			IncludeMalwareAnalysis: enrichMalware,
+			Coverage:               notScanned,
+			Alternatives:           alternatives,
 		})
 		if err != nil {
 			return err
@@ -427,7 +1170,12 @@ func internalStartScan() error {
 
 	if !utils.IsEmptyString(jsonReportPath) {
 		rp, err := reporter.NewJsonReportGenerator(reporter.JsonReportingConfig{
-			Path: jsonReportPath,
+			Path:         jsonReportPath,
+			Degradations: degradations,
+			Coverage:     notScanned,
+			Triage:       historyRecorder.triageStore(),
+			Tagger:       tagger,
+			Alternatives: alternatives,
 		})
 		if err != nil {
 			return err
@@ -442,7 +1190,8 @@ func internalStartScan() error {
 				Name:    "vet",
 				Version: version,
 			},
-			Path: sarifReportPath,
+			Path:   sarifReportPath,
+			Tagger: tagger,
 		})
 		if err != nil {
 			return err
@@ -462,7 +1211,8 @@ func internalStartScan() error {
 
 	if !utils.IsEmptyString(csvReportPath) {
 		rp, err := reporter.NewCsvReporter(reporter.CsvReportingConfig{
-			Path: csvReportPath,
+			Path:   csvReportPath,
+			Tagger: tagger,
 		})
 		if err != nil {
 			return err
@@ -471,24 +1221,113 @@ func internalStartScan() error {
 		reporters = append(reporters, rp)
 	}
 
+	var syncStats reporter.SyncStatsProvider
+	var syncProgressTracker *syncProgress
 	if syncReport {
-		clientConn, err := auth.SyncClientConnection("vet-sync")
+		// Offline sync does not use the credentials until flushed
+		if !syncOffline && syncDryRunFile == "" {
+			issues, err := auth.Preflight(auth.PreflightConfig{
+				ExpiryWarning: syncTokenExpiryWarning,
+			})
+
+			for _, issue := range issues {
+				if !issue.Fatal {
+					ui.PrintWarning("%s", issue)
+				}
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		spoolDir := syncSpoolDir
+		// Sync failures are not spooled in stateless mode unless a spool
+		// directory is given
+		spoolOnFailure := degradationMatrix.Mode(degradation.SourceControlTower) == degradation.ModeSpool &&
+			(spoolDir != "" || paths.Writable(paths.KindSpool))
+		if (syncOffline || spoolOnFailure) && spoolDir == "" {
+			spoolDir, err = reporter.DefaultSyncSpoolDir()
+			if err != nil {
+				return err
+			}
+		}
+
+		stateDir := ""
+		if syncResumable {
+			stateDir, err = reporter.DefaultSyncStateDir()
+			if err != nil {
+				return err
+			}
+		}
+
+		spoolCipher, err := auth.EncryptionCipher()
 		if err != nil {
 			return err
 		}
 
-		rp, err := reporter.NewSyncReporter(reporter.SyncReporterConfig{
+		redactor, err := buildSyncRedactor()
+		if err != nil {
+			return err
+		}
+
+		// Client credentials and cloud access token are refreshed on expiry
+		syncCredentials, err := auth.CloudCredentialProvider()
+		if err != nil {
+			return err
+		}
+
+		syncProgressTracker = &syncProgress{}
+		projectName, projectVersion, projectUrl := inferSyncProject(projectDir)
+
+		rp, err := reporter.NewSyncReporterWithContext(ctx, reporter.SyncReporterConfig{
 			ToolName:               "vet",
 			ToolVersion:            version,
-			ProjectName:            syncReportProject,
-			ProjectVersion:         syncReportStream,
+			ProjectName:            projectName,
+			ProjectVersion:         projectVersion,
+			ProjectUrl:             projectUrl,
 			EnableMultiProjectSync: syncEnableMultiProject,
-			ClientConnection:       clientConn,
+			Connection: reporter.SyncConnectionConfig{
+				Url:                auth.SyncApiUrl(),
+				Transport:          reporter.SyncTransport(syncTransport),
+				Credentials:        syncCredentials,
+				KeepaliveTime:      syncKeepalive,
+				CACertFile:         syncCACertFile,
+				InsecureSkipVerify: syncInsecureSkipVerify,
+				Plaintext:          syncPlaintext || auth.InsecureTransportEnabled(),
+				ProxyUrl:           syncProxyUrl,
+			},
+			Tenant: reporter.SyncTenantConfig{
+				TenantId:  syncTenant(),
+				Workspace: syncWorkspace,
+			},
+			SpoolDir:      spoolDir,
+			SpoolCipher:   spoolCipher,
+			Offline:       syncOffline,
+			QueueOverflow: reporter.SyncQueueOverflow(syncQueueOverflow),
+			DryRun:        syncDryRunFile != "",
+			DryRunFile:    syncDryRunFile,
+			StateDir:      stateDir,
+			EventBus:      events,
+			Degradations:  degradations,
+			RateLimit: reporter.SyncRateLimit{
+				RequestsPerSecond: syncRateLimit,
+				Adaptive:          syncAdaptiveRateLimit,
+			},
+			PublishTimeout: syncPublishTimeout,
+			OnProgress:     syncProgressTracker.update,
+			Triage:         historyRecorder.triageStore(),
+			Tagger:         tagger,
+			Redactor:       redactor,
 		})
 		if err != nil {
 			return err
 		}
 
+		if provider, ok := rp.(reporter.SyncStatsProvider); ok {
+			syncStats = provider
+		}
+
 		reporters = append(reporters, rp)
 	}
 
@@ -527,7 +1366,20 @@ func internalStartScan() error {
 			enricher = insightsEnricher
 		}
 
-		enrichers = append(enrichers, enricher)
+		insightsCache, err := buildInsightsCache(degradationMatrix)
+		if err != nil {
+			return err
+		}
+
+		if insightsCache != nil {
+			defer insightsCache.Close()
+		}
+
+		enrichers = append(enrichers, scanner.NewDegradingEnricher(enricher, scanner.DegradingEnricherConfig{
+			Matrix:   degradationMatrix,
+			Recorder: degradations,
+			Cache:    insightsCache,
+		}))
 	}
 
 	if codeAnalysisDBPath != "" {
@@ -585,11 +1437,32 @@ func internalStartScan() error {
 	pmScanner := scanner.NewPackageManifestScanner(scanner.Config{
 		TransitiveAnalysis: transitiveAnalysis,
 		TransitiveDepth:    transitiveDepth,
-		ConcurrentAnalyzer: concurrency,
+		ConcurrentAnalyzer: concurrentAnalyzers,
 		ExcludePatterns:    scanExclude,
 		Experimental:       scannerExperimental,
 	}, readerList, enrichers, analyzers, reporters)
 
+	// Manifests are tagged before enrichment so that filters can use tags
+	if tagger != nil {
+		pmScanner.AddStage(scanner.PipelineStage{
+			Name:       "tag",
+			RequiredBy: []string{scanner.StageEnrich},
+			Run: func(_ context.Context, manifest *models.PackageManifest) error {
+				tagger.TagManifest(manifest)
+				return nil
+			},
+		})
+	}
+
+	pmScanner.AddStage(scanner.PipelineStage{
+		Name:       "framework",
+		RequiredBy: []string{scanner.StageEnrich},
+		Run: func(_ context.Context, manifest *models.PackageManifest) error {
+			framework.Annotate(manifest)
+			return nil
+		},
+	})
+
 	// Redirect log to files to create space for UI rendering
 	redirectLogToFile(logFile)
 
@@ -597,6 +1470,18 @@ func internalStartScan() error {
 	var packageManifestTracker any
 	var packageTracker any
 
+	events.Subscribe(func(event eventbus.Event) {
+		switch event.Topic {
+		case eventbus.TopicManifestParsed:
+			historyRecorder.observe(event.Manifest)
+			telemetry.RecordManifest(event.Manifest.Ecosystem, event.Manifest.GetPackagesCount())
+		case eventbus.TopicPackageEnriched:
+			ui.IncrementProgress(packageTracker, 1)
+		}
+	}, eventbus.TopicManifestParsed, eventbus.TopicPackageEnriched)
+
+	pmScanner.WithEventBus(events)
+
 	manifestsCount := 0
 	pmScanner.WithCallbacks(scanner.ScannerCallbacks{
 		OnStartEnumerateManifest: func() {
@@ -620,6 +1505,10 @@ func internalStartScan() error {
 
 			packageManifestTracker = ui.TrackProgress("Scanning manifests", 0)
 			packageTracker = ui.TrackProgress("Scanning packages", 0)
+
+			if syncProgressTracker != nil {
+				syncProgressTracker.start(ui.TrackProgress("Syncing to cloud", 0))
+			}
 		},
 		OnAddTransitivePackage: func(pkg *models.Package) {
 			ui.IncrementTrackerTotal(packageTracker, 1)
@@ -627,15 +1516,63 @@ func internalStartScan() error {
 		OnDoneManifest: func(manifest *models.PackageManifest) {
 			ui.IncrementProgress(packageManifestTracker, 1)
 		},
-		OnDonePackage: func(pkg *models.Package) {
-			ui.IncrementProgress(packageTracker, 1)
-		},
 		BeforeFinish: func() {
 			ui.MarkTrackerAsDone(packageManifestTracker)
 			ui.MarkTrackerAsDone(packageTracker)
 			ui.StopProgressWriter()
+
+			if syncProgressTracker != nil {
+				syncProgressTracker.stop()
+			}
+		},
+		OnStop: func(err error) {
+			if err == nil {
+				historyRecorder.commit()
+			}
 		},
 	})
 
-	return pmScanner.Start()
+	err = pmScanner.StartWithContext(ctx)
+
+	// Failure of a reporter does not fail the scan but must not go unnoticed
+	for _, re := range pmScanner.ReporterErrors() {
+		ui.PrintWarning("Reporter %s failed: %v", re.Reporter, re.Err)
+	}
+
+	for _, stage := range pmScanner.StageMetrics() {
+		logger.Infof("Pipeline stage %s: %d runs, %d failures in %s",
+			stage.Name, stage.Runs, stage.Failures, stage.Duration)
+
+		if stage.Failures > 0 {
+			ui.PrintWarning("Pipeline stage %s failed for %d of %d manifest(s)",
+				stage.Name, stage.Failures, stage.Runs)
+		}
+	}
+
+	for _, d := range degradations.Records() {
+		ui.PrintWarning("Degraded %s: %s applied %d time(s), last failure: %s",
+			d.Source, d.Mode, d.Count, d.Reason)
+	}
+
+	for _, ns := range notScanned.Records() {
+		ui.PrintWarning("Not scanned %s (%s): %s, %s", ns.Path, ns.Ecosystem, ns.Reason, ns.Detail)
+	}
+
+	if err == nil && syncStats != nil {
+		stats := syncStats.SyncStats()
+		ui.PrintMsg("Synced to cloud: %d published, %d failed, %d skipped, %d dropped",
+			stats.Published, stats.Failed, stats.Skipped, stats.Dropped)
+
+		if stats.Failed > 0 {
+			ui.PrintWarning("Sync failures by class: %s", stats.FailureSummary())
+		}
+
+		// Dropped items are not published either, hence the sync is incomplete
+		if syncFailOnError && (stats.Failed > 0 || stats.Dropped > 0) {
+			return errcode.Errorf(errcode.SyncIncomplete, "sync incomplete: %d of %d items failed to publish, %d dropped",
+				stats.Failed, stats.Total(), stats.Dropped)
+		}
+	}
+
+	return err
 }
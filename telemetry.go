@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/safedep/vet/internal/command"
+	"github.com/safedep/vet/internal/telemetry"
+	"github.com/safedep/vet/internal/ui"
+)
+
+func newTelemetryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manage anonymous usage telemetry (opt-in)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "enable",
+		Short: "Opt-in to report anonymous usage",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			command.FailOnError("telemetry/enable", telemetry.Enable())
+
+			ui.PrintSuccess("Telemetry enabled, review the reported data using `vet telemetry preview`")
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "disable",
+		Short: "Stop reporting anonymous usage",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			command.FailOnError("telemetry/disable", telemetry.Disable())
+
+			ui.PrintSuccess("Telemetry disabled")
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show if telemetry is enabled",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if telemetry.Enabled() {
+				ui.PrintMsg("Telemetry is enabled")
+			} else {
+				ui.PrintMsg("Telemetry is disabled")
+			}
+
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "preview",
+		Short: "Show the usage data reported for the last command",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := telemetry.Preview()
+			if os.IsNotExist(err) {
+				ui.PrintMsg("No usage data recorded yet")
+				return nil
+			}
+
+			command.FailOnError("telemetry/preview", err)
+
+			fmt.Fprintln(os.Stdout, string(data))
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+func isTelemetryCommand(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd.Name() == "telemetry" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/safedep/dry/utils"
+	"github.com/spf13/cobra"
+
+	jsonreportspec "github.com/safedep/vet/gen/jsonreport"
+	"github.com/safedep/vet/internal/command"
+	"github.com/safedep/vet/internal/ui"
+	"github.com/safedep/vet/pkg/history"
+	"github.com/safedep/vet/pkg/storage"
+)
+
+var (
+	triageStorageBackend string
+	triageStorageUrl     string
+	triageEcosystem      string
+	triagePackageName    string
+	triageVersion        string
+	triageFindingId      string
+	triageState          string
+	triageNote           string
+	triageReportPath     string
+)
+
+func newTriageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "triage",
+		Short: "Track review workflow state of findings",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&triageStorageBackend, "history-storage", "", "file",
+		"Storage backend for manifest history and triage states (file, sqlite, bolt, postgres)")
+	cmd.PersistentFlags().StringVarP(&triageStorageUrl, "history-storage-url", "", "",
+		"Connection URL for postgres history storage shared by multiple instances")
+
+	cmd.AddCommand(newTriageSetCommand())
+	cmd.AddCommand(newTriageListCommand())
+	cmd.AddCommand(newTriageImportCommand())
+
+	return cmd
+}
+
+func newTriageSetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set triage state of a finding",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			command.FailOnError("triage", setTriage())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&triageEcosystem, "ecosystem", "", "",
+		"Ecosystem of the package e.g. npm, PyPI")
+	cmd.Flags().StringVarP(&triagePackageName, "package", "", "",
+		"Name of the package")
+	cmd.Flags().StringVarP(&triageVersion, "version", "", "",
+		"Version of the package")
+	cmd.Flags().StringVarP(&triageFindingId, "finding", "", "",
+		"Vulnerability ID or name of the policy filter")
+	cmd.Flags().StringVarP(&triageState, "state", "", "",
+		"Triage state (new, acknowledged, in-progress, fixed, accepted-risk)")
+	cmd.Flags().StringVarP(&triageNote, "note", "", "",
+		"Optional note e.g. reason for accepting the risk")
+
+	return cmd
+}
+
+func newTriageListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List triage states of findings",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			command.FailOnError("triage", listTriage())
+			return nil
+		},
+	}
+}
+
+func newTriageImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import triage states edited in a JSON report",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			command.FailOnError("triage", importTriage())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&triageReportPath, "report", "", "",
+		"Path to JSON report generated with --report-json and --history")
+
+	return cmd
+}
+
+// withTriageStore opens the triage store of the configured backend for
+// the duration of fn
+func withTriageStore(fn func(store history.TriageStore) error) error {
+	var kv storage.KeyValueStorage
+	if triageStorageBackend != "file" {
+		var err error
+		kv, err = openHistoryStorage(triageStorageBackend, triageStorageUrl)
+		if err != nil {
+			return err
+		}
+
+		defer kv.Close()
+	}
+
+	store, err := buildTriageStore(kv)
+	if err != nil {
+		return err
+	}
+
+	return fn(store)
+}
+
+func setTriage() error {
+	if triageEcosystem == "" || triagePackageName == "" || triageFindingId == "" {
+		return errors.New("ecosystem, package and finding are required")
+	}
+
+	state, err := history.ParseTriageState(triageState)
+	if err != nil {
+		return err
+	}
+
+	key := history.FindingKey{
+		Ecosystem: triageEcosystem,
+		Name:      triagePackageName,
+		Version:   triageVersion,
+		FindingId: triageFindingId,
+	}
+
+	err = withTriageStore(func(store history.TriageStore) error {
+		return store.PutTriage(history.FindingTriage{FindingKey: key, State: state, Note: triageNote})
+	})
+	if err != nil {
+		return err
+	}
+
+	ui.PrintSuccess("Triage state of %s for %s/%s is %s", triageFindingId, triageEcosystem, triagePackageName, state)
+	return nil
+}
+
+func listTriage() error {
+	return withTriageStore(func(store history.TriageStore) error {
+		triages, err := store.ListTriage()
+		if err != nil {
+			return err
+		}
+
+		tbl := table.NewWriter()
+		tbl.SetOutputMirror(os.Stdout)
+		tbl.SetStyle(table.StyleLight)
+
+		tbl.AppendHeader(table.Row{"Ecosystem", "Package", "Version", "Finding", "State", "Note", "Updated"})
+		for _, triage := range triages {
+			tbl.AppendRow(table.Row{triage.Ecosystem, triage.Name, triage.Version, triage.FindingId,
+				triage.State, triage.Note, triage.UpdatedAt.Format(time.RFC3339)})
+		}
+
+		tbl.Render()
+		return nil
+	})
+}
+
+// importTriage records the triage states of a JSON report that differ from
+// the stored states
+func importTriage() error {
+	if triageReportPath == "" {
+		return errors.New("report is required")
+	}
+
+	file, err := os.Open(triageReportPath)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	var report jsonreportspec.Report
+	if err := utils.FromPbJson(file, &report); err != nil {
+		return fmt.Errorf("failed to parse JSON report: %w", err)
+	}
+
+	imported := 0
+	err = withTriageStore(func(store history.TriageStore) error {
+		for _, pkg := range report.GetPackages() {
+			for _, entry := range pkg.GetTriages() {
+				key := history.FindingKey{
+					Ecosystem: pkg.GetPackage().GetEcosystem().String(),
+					Name:      pkg.GetPackage().GetName(),
+					Version:   pkg.GetPackage().GetVersion(),
+					FindingId: entry.GetFindingId(),
+				}
+
+				state, err := history.ParseTriageState(entry.GetState())
+				if err != nil {
+					return fmt.Errorf("%s of %s: %w", key.FindingId, key.Name, err)
+				}
+
+				current, err := history.Triage(store, key)
+				if err != nil {
+					return err
+				}
+
+				if current.State == state && current.Note == entry.GetNote() {
+					continue
+				}
+
+				err = store.PutTriage(history.FindingTriage{FindingKey: key, State: state, Note: entry.GetNote()})
+				if err != nil {
+					return err
+				}
+
+				imported++
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ui.PrintSuccess("Imported %d triage states from %s", imported, triageReportPath)
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/safedep/vet/internal/command"
+	"github.com/safedep/vet/internal/ui"
+	"github.com/safedep/vet/pkg/selfupdate"
+)
+
+// Base64 encoded ed25519 public key of the release signing key, set by
+// the release pipeline
+var releasePublicKey string
+
+var (
+	updateChannel   string
+	updateCheckOnly bool
+)
+
+func newUpdateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update vet to the latest release",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			command.FailOnError("update", selfUpdate(cmd.Context()))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&updateChannel, "channel", "", string(selfupdate.ChannelStable),
+		"Release channel to update from (stable, beta)")
+	cmd.Flags().BoolVarP(&updateCheckOnly, "check", "", false,
+		"Only check if an update is available")
+
+	return cmd
+}
+
+func selfUpdate(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Development builds are not signed
+	if releasePublicKey == "" {
+		return errors.New("self update is not supported by this build")
+	}
+
+	publicKey, err := selfupdate.ParsePublicKey(releasePublicKey)
+	if err != nil {
+		return err
+	}
+
+	updater, err := selfupdate.NewUpdater(selfupdate.Config{
+		Channel:        selfupdate.Channel(updateChannel),
+		CurrentVersion: version,
+		PublicKey:      publicKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	release, err := updater.Latest(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !updater.UpdateAvailable(release) {
+		ui.PrintSuccess("vet %s is the latest release in %s channel", version, updateChannel)
+		return nil
+	}
+
+	if updateCheckOnly {
+		ui.PrintMsg("Update available: %s -> %s", version, release.Version)
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	ui.PrintMsg("Updating vet %s -> %s", version, release.Version)
+
+	err = updater.Apply(ctx, release, executable)
+	if err != nil {
+		return err
+	}
+
+	ui.PrintSuccess("Updated vet to %s", release.Version)
+	return nil
+}
@@ -3,13 +3,19 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/safedep/vet/internal/ui"
+	"github.com/safedep/vet/pkg/selfupdate"
 	"github.com/spf13/cobra"
 )
 
 var version string
 var commit string
 
+// Build date in RFC3339, set by the release pipeline
+var date string
+
 func newVersionCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "version",
@@ -18,6 +24,16 @@ func newVersionCommand() *cobra.Command {
 			fmt.Fprintf(os.Stdout, "Version: %s\n", version)
 			fmt.Fprintf(os.Stdout, "CommitSHA: %s\n", commit)
 
+			if date != "" {
+				fmt.Fprintf(os.Stdout, "BuildDate: %s\n", date)
+			}
+
+			staleness, err := selfupdate.CheckDataStaleness(date, selfupdate.DefaultDataMaxAge, time.Now())
+			if err == nil && staleness.Stale {
+				ui.PrintWarning("Embedded advisory and license data is %d days old, update using `vet update`",
+					int(staleness.Age.Hours()/24))
+			}
+
 			os.Exit(1)
 			return nil
 		},